@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nyunja/fity-budget-backend/internal/config"
+	"github.com/nyunja/fity-budget-backend/internal/repository"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+)
+
+// import-rates loads dated exchange rates into the database from a CSV file
+// (date,base,quote,rate) or an ECB-style XML file, e.g.
+//
+//	go run ./cmd/import-rates -file eurofxref-hist.xml
+func main() {
+	path := flag.String("file", "", "path to the rate file")
+	format := flag.String("format", "", "file format: csv or ecb (detected from the extension when omitted)")
+	flag.Parse()
+
+	if *path == "" {
+		log.Fatal("❌ -file is required")
+	}
+	if *format == "" {
+		*format = services.RateFormatCSV
+		if strings.EqualFold(filepath.Ext(*path), ".xml") {
+			*format = services.RateFormatECB
+		}
+	}
+
+	log.Println("=== Importing Exchange Rates ===")
+
+	// Load configuration
+	cfg := config.Load()
+	log.Println("✓ Configuration loaded")
+
+	// Connect to database
+	db, err := config.ConnectDB(cfg)
+	if err != nil {
+		log.Fatalf("❌ Failed to connect to database: %v", err)
+	}
+	log.Println("✓ Database connected")
+
+	file, err := os.Open(*path)
+	if err != nil {
+		log.Fatalf("❌ Failed to open rate file: %v", err)
+	}
+	defer file.Close()
+
+	rateService := services.NewExchangeRateService(repository.NewExchangeRateRepository(db))
+	result, err := rateService.ImportRates(file, *format)
+	if err != nil {
+		log.Fatalf("❌ Import failed: %v", err)
+	}
+
+	log.Printf("\n✅ Imported %d %s rates from %s to %s",
+		result.Imported, result.Format,
+		result.FirstDate.Format("2006-01-02"), result.LastDate.Format("2006-01-02"))
+	log.Printf("Currencies: %s", strings.Join(result.Currencies, ", "))
+}
@@ -4,7 +4,7 @@ import (
 	"log"
 
 	"github.com/nyunja/fity-budget-backend/internal/config"
-	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/database"
 )
 
 func main() {
@@ -30,13 +30,7 @@ func main() {
 
 	// Run migrations
 	log.Println("\nRunning auto-migrations...")
-	err = db.AutoMigrate(
-		&models.User{},
-		&models.Wallet{},
-		&models.Transaction{},
-		&models.SavingGoal{},
-		&models.Budget{},
-	)
+	err = database.Migrate(db)
 
 	if err != nil {
 		log.Fatalf("❌ Migration failed: %v", err)
@@ -47,6 +41,24 @@ func main() {
 	log.Println("  - users")
 	log.Println("  - wallets")
 	log.Println("  - transactions")
+	log.Println("  - transaction_splits")
 	log.Println("  - saving_goals")
 	log.Println("  - budgets")
+	log.Println("  - budget_limits")
+	log.Println("  - budget_ledger_entries")
+	log.Println("  - envelope_allocations")
+	log.Println("  - budget_templates")
+	log.Println("  - budget_template_lines")
+	log.Println("  - transfers")
+	log.Println("  - exchange_rates")
+	log.Println("  - import_jobs")
+	log.Println("  - import_rows")
+	log.Println("  - recurring_transactions")
+	log.Println("  - categories")
+	log.Println("  - tags")
+	log.Println("  - transaction_tags")
+	log.Println("  - rules")
+	log.Println("  - rule_tags")
+	log.Println("  - receipts")
+	log.Println("  - schema_migrations")
 }
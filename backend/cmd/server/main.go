@@ -1,16 +1,20 @@
 package main
 
 import (
+	"context"
 	"log"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/nyunja/fity-budget-backend/internal/api/handlers"
 	"github.com/nyunja/fity-budget-backend/internal/api/routes"
 	"github.com/nyunja/fity-budget-backend/internal/config"
-	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/database"
 	"github.com/nyunja/fity-budget-backend/internal/repository"
+	"github.com/nyunja/fity-budget-backend/internal/scheduler"
 	"github.com/nyunja/fity-budget-backend/internal/services"
+	"github.com/nyunja/fity-budget-backend/internal/storage"
 	"gorm.io/gorm"
 
 	swaggerFiles "github.com/swaggo/files"
@@ -61,9 +65,29 @@ func main() {
 	transactionRepo := repository.NewTransactionRepository(db)
 	goalRepo := repository.NewGoalRepository(db)
 	budgetRepo := repository.NewBudgetRepository(db)
+	budgetLimitRepo := repository.NewBudgetLimitRepository(db)
+	budgetLedgerRepo := repository.NewBudgetLedgerRepository(db)
+	envelopeAllocationRepo := repository.NewEnvelopeAllocationRepository(db)
+	budgetTemplateRepo := repository.NewBudgetTemplateRepository(db)
 	walletRepo := repository.NewWalletRepository(db)
+	transferRepo := repository.NewTransferRepository(db)
+	exchangeRateRepo := repository.NewExchangeRateRepository(db)
+	importJobRepo := repository.NewImportJobRepository(db)
+	recurringRepo := repository.NewRecurringTransactionRepository(db)
+	categoryRepo := repository.NewCategoryRepository(db)
+	tagRepo := repository.NewTagRepository(db)
+	ruleRepo := repository.NewRuleRepository(db)
+	receiptRepo := repository.NewReceiptRepository(db)
+	txManager := repository.NewTxManager(db)
 	log.Println("Repositories initialized")
 
+	// Initialize file storage
+	receiptStorage, err := storage.NewLocalStorage(cfg.Storage.Path)
+	if err != nil {
+		log.Fatal("Failed to initialize file storage:", err)
+	}
+	log.Printf("File storage initialized at %s", cfg.Storage.Path)
+
 	// Initialize services
 	jwtExpiry, err := time.ParseDuration(cfg.JWT.Expiry)
 	if err != nil {
@@ -71,12 +95,30 @@ func main() {
 		jwtExpiry = 15 * time.Minute
 	}
 
+	trashRetentionDays, err := strconv.Atoi(cfg.Trash.RetentionDays)
+	if err != nil || trashRetentionDays < 0 {
+		log.Printf("Invalid trash retention, using default 30 days: %v", err)
+		trashRetentionDays = 30
+	}
+
 	authService := services.NewAuthService(userRepo, walletRepo, cfg.JWT.Secret, jwtExpiry)
-	transactionService := services.NewTransactionService(transactionRepo, walletRepo)
+	transactionService := services.NewTransactionService(transactionRepo, walletRepo, userRepo, exchangeRateRepo, ruleRepo, tagRepo, txManager)
 	goalService := services.NewGoalService(goalRepo)
-	budgetService := services.NewBudgetService(budgetRepo, transactionRepo)
-	walletService := services.NewWalletService(walletRepo)
-	analyticsService := services.NewAnalyticsService(transactionRepo, walletRepo, budgetRepo, goalRepo)
+	budgetService := services.NewBudgetService(budgetRepo, budgetLimitRepo, budgetLedgerRepo, transactionRepo, categoryRepo, userRepo, walletRepo, exchangeRateRepo, txManager)
+	walletService := services.NewWalletService(walletRepo, transactionRepo, transferRepo, exchangeRateRepo, txManager)
+	analyticsService := services.NewAnalyticsService(transactionRepo, walletRepo, budgetRepo, goalRepo, userRepo, exchangeRateRepo, categoryRepo, tagRepo)
+	exchangeRateService := services.NewExchangeRateService(exchangeRateRepo)
+	importService := services.NewImportService(importJobRepo, transactionRepo, walletRepo, ruleRepo, txManager)
+	recurringService := services.NewRecurringTransactionService(recurringRepo, transactionRepo, walletRepo, txManager)
+	categoryService := services.NewCategoryService(categoryRepo, budgetRepo, budgetLimitRepo, txManager)
+	ruleService := services.NewRuleService(ruleRepo, tagRepo, transactionRepo, txManager)
+	tagService := services.NewTagService(tagRepo, txManager)
+	receiptService := services.NewReceiptService(receiptRepo, transactionRepo, receiptStorage)
+	duplicateService := services.NewDuplicateService(transactionRepo, walletRepo, receiptRepo, txManager)
+	bulkTransactionService := services.NewBulkTransactionService(transactionRepo, walletRepo, tagRepo, txManager)
+	envelopeService := services.NewEnvelopeService(budgetRepo, envelopeAllocationRepo, walletRepo, transactionRepo, categoryRepo, userRepo, exchangeRateRepo, txManager)
+	budgetTemplateService := services.NewBudgetTemplateService(budgetTemplateRepo, budgetRepo, userRepo, categoryRepo, budgetService, txManager)
+	trashService := services.NewTrashService(transactionRepo, budgetRepo, goalRepo, walletRepo, importJobRepo, receiptService, txManager, time.Duration(trashRetentionDays)*24*time.Hour)
 	log.Println("Services initialized")
 
 	// Initialize handlers
@@ -86,6 +128,18 @@ func main() {
 	budgetHandler := handlers.NewBudgetHandler(budgetService)
 	walletHandler := handlers.NewWalletHandler(walletService)
 	analyticsHandler := handlers.NewAnalyticsHandler(analyticsService)
+	exchangeRateHandler := handlers.NewExchangeRateHandler(exchangeRateService)
+	importHandler := handlers.NewImportHandler(importService)
+	recurringHandler := handlers.NewRecurringHandler(recurringService)
+	categoryHandler := handlers.NewCategoryHandler(categoryService)
+	ruleHandler := handlers.NewRuleHandler(ruleService)
+	tagHandler := handlers.NewTagHandler(tagService)
+	receiptHandler := handlers.NewReceiptHandler(receiptService)
+	duplicateHandler := handlers.NewDuplicateHandler(duplicateService)
+	trashHandler := handlers.NewTrashHandler(trashService)
+	bulkTransactionHandler := handlers.NewBulkTransactionHandler(bulkTransactionService)
+	envelopeHandler := handlers.NewEnvelopeHandler(envelopeService)
+	budgetTemplateHandler := handlers.NewBudgetTemplateHandler(budgetTemplateService)
 	log.Println("Handlers initialized")
 
 	// Setup Gin engine
@@ -104,6 +158,18 @@ func main() {
 		budgetHandler,
 		walletHandler,
 		analyticsHandler,
+		exchangeRateHandler,
+		importHandler,
+		recurringHandler,
+		categoryHandler,
+		ruleHandler,
+		tagHandler,
+		receiptHandler,
+		duplicateHandler,
+		trashHandler,
+		bulkTransactionHandler,
+		envelopeHandler,
+		budgetTemplateHandler,
 	)
 	log.Println("Routes configured")
 
@@ -111,6 +177,44 @@ func main() {
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	log.Println("Swagger documentation enabled at /swagger/index.html")
 
+	// Start background jobs
+	schedulerInterval, err := time.ParseDuration(cfg.Scheduler.Interval)
+	if err != nil || schedulerInterval <= 0 {
+		log.Printf("Invalid scheduler interval, using default 1m: %v", err)
+		schedulerInterval = time.Minute
+	}
+	ctx, stopScheduler := context.WithCancel(context.Background())
+	defer stopScheduler()
+	scheduler.New(schedulerInterval, scheduler.Job{
+		Name: "post recurring transactions",
+		Run: func(now time.Time) error {
+			posted, err := recurringService.PostDueOccurrences(now)
+			if posted > 0 {
+				log.Printf("Posted %d recurring transactions", posted)
+			}
+			return err
+		},
+	}, scheduler.Job{
+		Name: "snapshot closed budget periods",
+		Run: func(now time.Time) error {
+			snapshotted, err := budgetService.SnapshotClosedPeriods(now)
+			if snapshotted > 0 {
+				log.Printf("Snapshotted %d closed budget periods", snapshotted)
+			}
+			return err
+		},
+	}, scheduler.Job{
+		Name: "purge expired trash",
+		Run: func(now time.Time) error {
+			purged, err := trashService.PurgeExpired(now)
+			if purged > 0 {
+				log.Printf("Purged %d expired trash items", purged)
+			}
+			return err
+		},
+	}).Start(ctx)
+	log.Printf("Scheduler started, running every %s", schedulerInterval)
+
 	// Start server
 	addr := ":" + cfg.Server.Port
 	log.Printf("🚀 FityBudget API server starting on port %s", cfg.Server.Port)
@@ -132,11 +236,5 @@ func runMigrations(db *gorm.DB) error {
 	}
 
 	log.Println("Running auto-migrations for all models...")
-	return db.AutoMigrate(
-		&models.User{},
-		&models.Wallet{},
-		&models.Transaction{},
-		&models.SavingGoal{},
-		&models.Budget{},
-	)
+	return database.Migrate(db)
 }
@@ -55,7 +55,7 @@ func main() {
 	}
 
 	// Verify specific tables
-	expectedTables := []string{"users", "wallets", "transactions", "saving_goals", "budgets"}
+	expectedTables := []string{"users", "wallets", "transactions", "transaction_splits", "saving_goals", "budgets", "budget_limits", "budget_ledger_entries", "envelope_allocations", "budget_templates", "budget_template_lines", "transfers", "exchange_rates", "import_jobs", "import_rows", "recurring_transactions", "categories", "tags", "transaction_tags", "rules", "rule_tags", "receipts"}
 	fmt.Println("=== Verification Results ===")
 
 	allFound := true
@@ -109,13 +109,14 @@ func (h *AnalyticsHandler) GetMoneyFlow(c *gin.Context) {
 
 // GetSpendingAnalysis godoc
 // @Summary Get spending analysis
-// @Description Get spending breakdown by category for specified period
+// @Description Get spending breakdown by category for specified period. With rollup, spending in subcategories is reported under their parent category.
 // @Tags analytics
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param period query string false "Time period" Enums(7days, 1month, 3months, 6months, 1year) default(1month)
-// @Success 200 {object} utils.Response{data=object{total_spending=number,by_category=[]object}}
+// @Param rollup query bool false "Roll subcategories up into their parents" default(false)
+// @Success 200 {object} utils.Response{data=object{total_spending=number,by_category=[]object,currency=string,exchange_rates=[]object}}
 // @Failure 401 {object} utils.Response
 // @Failure 500 {object} utils.Response
 // @Router /analytics/spending [get]
@@ -148,24 +149,75 @@ func (h *AnalyticsHandler) GetSpendingAnalysis(c *gin.Context) {
 		startDate = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
 	}
 
-	categories, err := h.analyticsService.GetSpendingByCategory(userID, startDate, now)
+	rollup := c.DefaultQuery("rollup", "false") == "true"
+
+	report, err := h.analyticsService.GetSpendingByCategory(userID, startDate, now, rollup)
 	if err != nil {
 		utils.Error(c, http.StatusInternalServerError, "SPENDING_ANALYSIS_FAILED", err.Error())
 		return
 	}
 
-	// Calculate total spending
-	totalSpending := 0.0
-	for _, cat := range categories {
-		totalSpending += cat.Amount
-	}
-
 	utils.Success(c, http.StatusOK, gin.H{
-		"total_spending": totalSpending,
-		"by_category":    categories,
+		"total_spending": report.TotalSpending,
+		"by_category":    report.Categories,
+		"currency":       report.Currency,
+		"exchange_rates": report.ExchangeRates,
 	})
 }
 
+// GetTagSpending godoc
+// @Summary Get spending by tag
+// @Description Get the spending carrying each tag over a date range, with the categories it was spent on. A transaction with several tags counts under each of them. The range defaults to the current month.
+// @Tags analytics
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param start_date query string false "First day of the range (YYYY-MM-DD)"
+// @Param end_date query string false "Last day of the range, inclusive (YYYY-MM-DD)"
+// @Success 200 {object} utils.Response{data=services.SpendingByTagReport}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /analytics/tags [get]
+func (h *AnalyticsHandler) GetTagSpending(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	now := time.Now()
+	startDate := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	endDate := startDate.AddDate(0, 1, 0)
+
+	if value := c.Query("start_date"); value != "" {
+		if startDate, err = time.Parse("2006-01-02", value); err != nil {
+			utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", "start_date must be formatted as YYYY-MM-DD")
+			return
+		}
+	}
+	if value := c.Query("end_date"); value != "" {
+		if endDate, err = time.Parse("2006-01-02", value); err != nil {
+			utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", "end_date must be formatted as YYYY-MM-DD")
+			return
+		}
+		// The end date is inclusive, so report up to the start of the next day
+		endDate = endDate.AddDate(0, 0, 1)
+	}
+	if !startDate.Before(endDate) {
+		utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", "start_date must not be after end_date")
+		return
+	}
+
+	report, err := h.analyticsService.GetSpendingByTag(userID, startDate, endDate)
+	if err != nil {
+		utils.Error(c, http.StatusInternalServerError, "SPENDING_ANALYSIS_FAILED", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusOK, report)
+}
+
 // GetInsights godoc
 // @Summary Get financial insights
 // @Description Get AI-generated financial insights and recommendations
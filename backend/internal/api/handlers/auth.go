@@ -5,6 +5,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/nyunja/fity-budget-backend/internal/api/middleware"
+	"github.com/nyunja/fity-budget-backend/internal/money"
 	"github.com/nyunja/fity-budget-backend/internal/services"
 	"github.com/nyunja/fity-budget-backend/internal/utils"
 )
@@ -35,9 +36,9 @@ type UpdateProfileRequest struct {
 }
 
 type OnboardingRequest struct {
-	MonthlyIncome  float64  `json:"monthly_income" binding:"required,min=0"`
-	Currency       string   `json:"currency" binding:"required,len=3"`
-	FinancialGoals []string `json:"financial_goals"`
+	MonthlyIncome  money.Amount `json:"monthly_income" binding:"required,min=0"`
+	Currency       string       `json:"currency" binding:"required,len=3"`
+	FinancialGoals []string     `json:"financial_goals"`
 }
 
 // Register godoc
@@ -0,0 +1,295 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/api/middleware"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+	"github.com/nyunja/fity-budget-backend/internal/utils"
+)
+
+type BudgetTemplateHandler struct {
+	templateService services.BudgetTemplateService
+}
+
+func NewBudgetTemplateHandler(templateService services.BudgetTemplateService) *BudgetTemplateHandler {
+	return &BudgetTemplateHandler{templateService: templateService}
+}
+
+// Request/Response types
+type CreateBudgetTemplateRequest struct {
+	Name  string                      `json:"name" binding:"required,max=100"`
+	Lines []BudgetTemplateLineRequest `json:"lines" binding:"required,min=1,max=50,dive"`
+}
+
+type BudgetTemplateLineRequest struct {
+	Category   string  `json:"category" binding:"required,max=100"`
+	Percentage float64 `json:"percentage" binding:"required,gt=0,lte=100"`
+}
+
+// GenerateBudgetsRequest names a built-in template with the categories for
+// each of its groups, or a saved template by ID
+type GenerateBudgetsRequest struct {
+	Template   string              `json:"template" binding:"omitempty,oneof=50-30-20 70-20-10"`
+	TemplateID *uuid.UUID          `json:"template_id"`
+	Groups     map[string][]string `json:"groups"`
+	Income     money.Amount        `json:"income" binding:"omitempty,gte=0"`
+}
+
+type RescaleBudgetsRequest struct {
+	Income money.Amount `json:"income" binding:"required,gt=0"`
+}
+
+// ListBudgetTemplates godoc
+// @Summary List budget templates
+// @Description List the built-in budget templates (50/30/20 and 70/20/10) and the templates the user saved
+// @Tags budget-templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=services.BudgetTemplateList}
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /budget-templates [get]
+func (h *BudgetTemplateHandler) ListBudgetTemplates(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	templates, err := h.templateService.ListTemplates(userID)
+	if err != nil {
+		utils.Error(c, http.StatusInternalServerError, "FETCH_FAILED", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusOK, templates)
+}
+
+// CreateBudgetTemplate godoc
+// @Summary Save budget template
+// @Description Save a template giving each category a percentage of income. The percentages may add up to at most 100.
+// @Tags budget-templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateBudgetTemplateRequest true "Template name and lines"
+// @Success 201 {object} utils.Response{data=object{template=models.BudgetTemplate}}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /budget-templates [post]
+func (h *BudgetTemplateHandler) CreateBudgetTemplate(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	var req CreateBudgetTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	serviceReq := services.CreateBudgetTemplateRequest{Name: req.Name}
+	for _, line := range req.Lines {
+		serviceReq.Lines = append(serviceReq.Lines, services.BudgetTemplateLineRequest{
+			Category:   line.Category,
+			Percentage: line.Percentage,
+		})
+	}
+
+	template, err := h.templateService.CreateTemplate(userID, serviceReq)
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "CREATE_FAILED", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusCreated, gin.H{
+		"template": template,
+	})
+}
+
+// DeleteBudgetTemplate godoc
+// @Summary Delete budget template
+// @Description Delete a saved budget template. Budgets generated from it are kept.
+// @Tags budget-templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Template ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /budget-templates/{id} [delete]
+func (h *BudgetTemplateHandler) DeleteBudgetTemplate(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "INVALID_ID", "Invalid template ID")
+		return
+	}
+
+	if err := h.templateService.DeleteTemplate(id, userID); err != nil {
+		utils.Error(c, http.StatusBadRequest, "DELETE_FAILED", err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// PreviewBudgets godoc
+// @Summary Preview budgets from a template
+// @Description Work out the budgets a template sets up from the income, or the user's monthly income, without changing anything. Each budget shows whether it would be created, updated or left unchanged.
+// @Tags budget-templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body GenerateBudgetsRequest true "Template, group categories and income"
+// @Success 200 {object} utils.Response{data=services.BudgetPlan}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /budget-templates/preview [post]
+func (h *BudgetTemplateHandler) PreviewBudgets(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	var req GenerateBudgetsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	plan, err := h.templateService.PreviewBudgets(userID, req.toServiceRequest())
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "PREVIEW_FAILED", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusOK, plan)
+}
+
+// GenerateBudgets godoc
+// @Summary Generate budgets from a template
+// @Description Create the budgets a template sets up from the income, or the user's monthly income, and update the limits of categories that already have a budget. The income becomes the user's monthly income.
+// @Tags budget-templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body GenerateBudgetsRequest true "Template, group categories and income"
+// @Success 200 {object} utils.Response{data=services.BudgetPlan}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /budget-templates/generate [post]
+func (h *BudgetTemplateHandler) GenerateBudgets(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	var req GenerateBudgetsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	plan, err := h.templateService.GenerateBudgets(userID, req.toServiceRequest())
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "GENERATE_FAILED", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusOK, plan)
+}
+
+// PreviewRescale godoc
+// @Summary Preview rescaling budgets
+// @Description Work out every budget limit scaled from the user's monthly income to a new income, without changing anything
+// @Tags budgets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body RescaleBudgetsRequest true "New monthly income"
+// @Success 200 {object} utils.Response{data=services.BudgetPlan}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /budgets/rescale/preview [post]
+func (h *BudgetTemplateHandler) PreviewRescale(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	var req RescaleBudgetsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	plan, err := h.templateService.PreviewRescale(userID, req.Income)
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "PREVIEW_FAILED", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusOK, plan)
+}
+
+// RescaleBudgets godoc
+// @Summary Rescale budgets to a new income
+// @Description Scale every budget limit by how much the user's monthly income changed, from the current period on, and record the new monthly income
+// @Tags budgets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body RescaleBudgetsRequest true "New monthly income"
+// @Success 200 {object} utils.Response{data=services.BudgetPlan}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /budgets/rescale [post]
+func (h *BudgetTemplateHandler) RescaleBudgets(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	var req RescaleBudgetsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	plan, err := h.templateService.RescaleBudgets(userID, req.Income)
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "RESCALE_FAILED", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusOK, plan)
+}
+
+// toServiceRequest converts the request into a budget generation request
+func (r *GenerateBudgetsRequest) toServiceRequest() services.GenerateBudgetsRequest {
+	return services.GenerateBudgetsRequest{
+		Template:   r.Template,
+		TemplateID: r.TemplateID,
+		Groups:     r.Groups,
+		Income:     r.Income,
+	}
+}
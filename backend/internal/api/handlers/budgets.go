@@ -1,11 +1,15 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/nyunja/fity-budget-backend/internal/api/middleware"
+	"github.com/nyunja/fity-budget-backend/internal/money"
 	"github.com/nyunja/fity-budget-backend/internal/services"
 	"github.com/nyunja/fity-budget-backend/internal/utils"
 )
@@ -20,23 +24,31 @@ func NewBudgetHandler(budgetService services.BudgetService) *BudgetHandler {
 
 // Request/Response types
 type CreateBudgetRequest struct {
-	Category       string  `json:"category" binding:"required"`
-	LimitAmount    float64 `json:"limit" binding:"required,gt=0"`
-	Color          string  `json:"color" binding:"required"`
-	Icon           string  `json:"icon"`
-	IsRollover     bool    `json:"is_rollover"`
-	Type           string  `json:"type" binding:"omitempty,oneof=Fixed Variable"`
-	AlertThreshold int     `json:"alert_threshold" binding:"omitempty,gte=0,lte=100"`
+	Category       string       `json:"category" binding:"required"`
+	LimitAmount    money.Amount `json:"limit" binding:"required,gt=0"`
+	Color          string       `json:"color" binding:"required"`
+	Icon           string       `json:"icon"`
+	IsRollover     bool         `json:"is_rollover"`
+	IsEnvelope     bool         `json:"is_envelope"`
+	Type           string       `json:"type" binding:"omitempty,oneof=Fixed Variable"`
+	AlertThreshold int          `json:"alert_threshold" binding:"omitempty,gte=0,lte=100"`
+	Period         string       `json:"period" binding:"omitempty,oneof=weekly monthly quarterly yearly custom"`
+	PeriodAnchor   *time.Time   `json:"period_anchor"`
+	PeriodDays     int          `json:"period_days" binding:"omitempty,gte=1,lte=366"`
 }
 
 type UpdateBudgetRequest struct {
-	Category       string  `json:"category"`
-	LimitAmount    float64 `json:"limit" binding:"omitempty,gt=0"`
-	Color          string  `json:"color"`
-	Icon           string  `json:"icon"`
-	IsRollover     *bool   `json:"is_rollover"`
-	Type           string  `json:"type" binding:"omitempty,oneof=Fixed Variable"`
-	AlertThreshold *int    `json:"alert_threshold" binding:"omitempty,gte=0,lte=100"`
+	Category       string       `json:"category"`
+	LimitAmount    money.Amount `json:"limit" binding:"omitempty,gt=0"`
+	Color          string       `json:"color"`
+	Icon           string       `json:"icon"`
+	IsRollover     *bool        `json:"is_rollover"`
+	IsEnvelope     *bool        `json:"is_envelope"`
+	Type           string       `json:"type" binding:"omitempty,oneof=Fixed Variable"`
+	AlertThreshold *int         `json:"alert_threshold" binding:"omitempty,gte=0,lte=100"`
+	Period         string       `json:"period" binding:"omitempty,oneof=weekly monthly quarterly yearly custom"`
+	PeriodAnchor   *time.Time   `json:"period_anchor"`
+	PeriodDays     *int         `json:"period_days" binding:"omitempty,gte=1,lte=366"`
 }
 
 // ListBudgets godoc
@@ -108,7 +120,7 @@ func (h *BudgetHandler) GetBudget(c *gin.Context) {
 
 // CreateBudget godoc
 // @Summary Create budget
-// @Description Create a new budget with spending limit
+// @Description Create a new budget with a spending limit per period. Budgets run by calendar month unless period is weekly, quarterly, yearly or custom. Periods repeat from period_anchor, so a weekly budget anchored on a Monday runs Monday to Sunday and a monthly one anchored on the 25th runs payday to payday; custom periods need an anchor and period_days.
 // @Tags budgets
 // @Accept json
 // @Produce json
@@ -138,8 +150,12 @@ func (h *BudgetHandler) CreateBudget(c *gin.Context) {
 		Color:          req.Color,
 		Icon:           req.Icon,
 		IsRollover:     req.IsRollover,
+		IsEnvelope:     req.IsEnvelope,
 		Type:           req.Type,
 		AlertThreshold: req.AlertThreshold,
+		Period:         req.Period,
+		PeriodAnchor:   req.PeriodAnchor,
+		PeriodDays:     req.PeriodDays,
 	}
 
 	budget, err := h.budgetService.CreateBudget(userID, serviceReq)
@@ -193,8 +209,12 @@ func (h *BudgetHandler) UpdateBudget(c *gin.Context) {
 		Color:          req.Color,
 		Icon:           req.Icon,
 		IsRollover:     req.IsRollover,
+		IsEnvelope:     req.IsEnvelope,
 		Type:           req.Type,
 		AlertThreshold: req.AlertThreshold,
+		Period:         req.Period,
+		PeriodAnchor:   req.PeriodAnchor,
+		PeriodDays:     req.PeriodDays,
 	}
 
 	budget, err := h.budgetService.UpdateBudget(id, userID, serviceReq)
@@ -244,11 +264,12 @@ func (h *BudgetHandler) DeleteBudget(c *gin.Context) {
 
 // GetBudgetSummary godoc
 // @Summary Get budget summary
-// @Description Get overall budget summary and spending for the current month
+// @Description Get overall budget summary and spending over each budget's current period, or the periods containing date
 // @Tags budgets
 // @Accept json
 // @Produce json
 // @Security BearerAuth
+// @Param date query string false "Day within the periods to summarize (YYYY-MM-DD), defaults to today"
 // @Success 200 {object} utils.Response{data=object{summary=object}}
 // @Failure 401 {object} utils.Response
 // @Failure 500 {object} utils.Response
@@ -260,7 +281,13 @@ func (h *BudgetHandler) GetBudgetSummary(c *gin.Context) {
 		return
 	}
 
-	summary, err := h.budgetService.GetBudgetSummary(userID)
+	at, err := parseBudgetDate(c)
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	summary, err := h.budgetService.GetBudgetSummary(userID, at)
 	if err != nil {
 		utils.Error(c, http.StatusInternalServerError, "SUMMARY_FAILED", err.Error())
 		return
@@ -270,3 +297,184 @@ func (h *BudgetHandler) GetBudgetSummary(c *gin.Context) {
 		"summary": summary,
 	})
 }
+
+// GetBudgetStatuses godoc
+// @Summary Get budget statuses
+// @Description Get the spending status of every budget over its current period, or over its period containing date to look at past periods
+// @Tags budgets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param date query string false "Day within the periods to check (YYYY-MM-DD), defaults to today"
+// @Success 200 {object} utils.Response{data=object{statuses=[]services.BudgetStatus}}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /budgets/status [get]
+func (h *BudgetHandler) GetBudgetStatuses(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	at, err := parseBudgetDate(c)
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	statuses, err := h.budgetService.CheckBudgetStatus(userID, at)
+	if err != nil {
+		utils.Error(c, http.StatusInternalServerError, "FETCH_FAILED", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusOK, gin.H{
+		"statuses": statuses,
+	})
+}
+
+// GetBudgetStatus godoc
+// @Summary Get budget status
+// @Description Get the spending status of a budget over its current period, or over its period containing date
+// @Tags budgets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Budget ID"
+// @Param date query string false "Day within the period to check (YYYY-MM-DD), defaults to today"
+// @Success 200 {object} utils.Response{data=object{status=services.BudgetStatus}}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /budgets/{id}/status [get]
+func (h *BudgetHandler) GetBudgetStatus(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "INVALID_ID", "Invalid budget ID")
+		return
+	}
+
+	at, err := parseBudgetDate(c)
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	status, err := h.budgetService.GetBudgetStatus(id, userID, at)
+	if err != nil {
+		utils.Error(c, http.StatusNotFound, "NOT_FOUND", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusOK, gin.H{
+		"status": status,
+	})
+}
+
+// GetBudgetLedger godoc
+// @Summary Get budget ledger
+// @Description Get the closed periods recorded for a budget, oldest first, with each period's base limit, the amount carried in, the effective limit, the spending and the amount carried on to the next period. Corrections follow the entry they correct with the next revision.
+// @Tags budgets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Budget ID"
+// @Success 200 {object} utils.Response{data=object{ledger=[]models.BudgetLedgerEntry}}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /budgets/{id}/ledger [get]
+func (h *BudgetHandler) GetBudgetLedger(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "INVALID_ID", "Invalid budget ID")
+		return
+	}
+
+	ledger, err := h.budgetService.GetBudgetLedger(id, userID)
+	if err != nil {
+		utils.Error(c, http.StatusNotFound, "NOT_FOUND", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusOK, gin.H{
+		"ledger": ledger,
+	})
+}
+
+// GetBudgetHistory godoc
+// @Summary Get budget history
+// @Description Compare each budget's limit with the actual spending per period, over the given number of periods up to the current one or the one containing date. Limits are those in force in each period, and closed periods report the numbers snapshotted when they closed. Variance is what was left of the limit, negative when overspent.
+// @Tags budgets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param periods query int false "Number of periods" default(6) minimum(1) maximum(24)
+// @Param date query string false "Day within the last period to report (YYYY-MM-DD), defaults to today"
+// @Success 200 {object} utils.Response{data=object{history=[]services.BudgetHistory}}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /budgets/history [get]
+func (h *BudgetHandler) GetBudgetHistory(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	// Get periods parameter (default: 6)
+	periods, err := strconv.Atoi(c.DefaultQuery("periods", "6"))
+	if err != nil || periods < 1 {
+		periods = 6
+	}
+	if periods > 24 {
+		periods = 24
+	}
+
+	at, err := parseBudgetDate(c)
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	history, err := h.budgetService.GetBudgetHistory(userID, periods, at)
+	if err != nil {
+		utils.Error(c, http.StatusInternalServerError, "FETCH_FAILED", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusOK, gin.H{
+		"history": history,
+	})
+}
+
+// parseBudgetDate reads the optional date query parameter picking the budget
+// periods to report on, defaulting to now
+func parseBudgetDate(c *gin.Context) (time.Time, error) {
+	value := c.Query("date")
+	if value == "" {
+		return time.Now(), nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, errors.New("date must be formatted as YYYY-MM-DD")
+	}
+	return date, nil
+}
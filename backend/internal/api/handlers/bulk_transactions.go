@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/api/middleware"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+	"github.com/nyunja/fity-budget-backend/internal/utils"
+)
+
+type BulkTransactionHandler struct {
+	bulkService services.BulkTransactionService
+}
+
+func NewBulkTransactionHandler(bulkService services.BulkTransactionService) *BulkTransactionHandler {
+	return &BulkTransactionHandler{bulkService: bulkService}
+}
+
+// Request/Response types
+type BulkTransactionRequest struct {
+	IDs       []uuid.UUID            `json:"ids" binding:"omitempty,max=1000"`
+	Filter    *BulkTransactionFilter `json:"filter"`
+	Operation string                 `json:"operation" binding:"required,oneof=recategorize set_status move_wallet add_tags remove_tags delete"`
+	Category  string                 `json:"category" binding:"omitempty,max=100"`
+	Status    string                 `json:"status" binding:"omitempty,oneof=Completed Pending Failed"`
+	WalletID  *uuid.UUID             `json:"wallet_id"`
+	Tags      []string               `json:"tags" binding:"omitempty,max=20,dive,max=50"`
+}
+
+// BulkTransactionFilter takes the same filters as the transaction listing.
+// Dates are formatted as YYYY-MM-DD and the end date is inclusive.
+type BulkTransactionFilter struct {
+	StartDate  string        `json:"start_date"`
+	EndDate    string        `json:"end_date"`
+	Categories []string      `json:"category"`
+	WalletIDs  []uuid.UUID   `json:"wallet_id"`
+	Statuses   []string      `json:"status"`
+	Types      []string      `json:"type"`
+	Methods    []string      `json:"method"`
+	MinAmount  *money.Amount `json:"min_amount"`
+	MaxAmount  *money.Amount `json:"max_amount"`
+	TagIDs     []uuid.UUID   `json:"tag_id"`
+	Search     string        `json:"search"`
+}
+
+// BulkUpdateTransactions godoc
+// @Summary Bulk update transactions
+// @Description Apply one operation to up to 1000 transactions chosen by ids or by filter: recategorize (category), set_status (status), move_wallet (wallet_id), add_tags or remove_tags (tags), or delete. Transactions that are missing, not owned, transfer legs or cannot take the change are reported as failed; the others are changed together in one database transaction.
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body BulkTransactionRequest true "Selection and operation"
+// @Success 200 {object} utils.Response{data=services.BulkTransactionResult}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /transactions/bulk [post]
+func (h *BulkTransactionHandler) BulkUpdateTransactions(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	var req BulkTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	serviceReq := services.BulkTransactionRequest{
+		IDs:       req.IDs,
+		Operation: req.Operation,
+		Category:  req.Category,
+		Status:    req.Status,
+		WalletID:  req.WalletID,
+		Tags:      req.Tags,
+	}
+	if req.Filter != nil {
+		filter, err := req.Filter.toListQuery()
+		if err != nil {
+			utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+			return
+		}
+		serviceReq.Filter = &filter
+	}
+
+	result, err := h.bulkService.BulkUpdate(userID, serviceReq)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidTransactionQuery) {
+			utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+			return
+		}
+		utils.Error(c, http.StatusBadRequest, "BULK_UPDATE_FAILED", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusOK, result)
+}
+
+// toListQuery converts the filter into a transaction list query
+func (f *BulkTransactionFilter) toListQuery() (services.TransactionListQuery, error) {
+	query := services.TransactionListQuery{
+		Categories: f.Categories,
+		WalletIDs:  f.WalletIDs,
+		Statuses:   f.Statuses,
+		Types:      f.Types,
+		Methods:    f.Methods,
+		MinAmount:  f.MinAmount,
+		MaxAmount:  f.MaxAmount,
+		TagIDs:     f.TagIDs,
+		Search:     f.Search,
+	}
+
+	if f.StartDate != "" {
+		startDate, err := time.Parse("2006-01-02", f.StartDate)
+		if err != nil {
+			return query, errors.New("start_date must be formatted as YYYY-MM-DD")
+		}
+		query.StartDate = startDate
+	}
+	if f.EndDate != "" {
+		endDate, err := time.Parse("2006-01-02", f.EndDate)
+		if err != nil {
+			return query, errors.New("end_date must be formatted as YYYY-MM-DD")
+		}
+		// The end date is inclusive, so cover up to the start of the next day
+		query.EndDate = endDate.AddDate(0, 0, 1)
+	}
+
+	return query, nil
+}
@@ -0,0 +1,310 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/api/middleware"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+	"github.com/nyunja/fity-budget-backend/internal/utils"
+)
+
+type CategoryHandler struct {
+	categoryService services.CategoryService
+}
+
+func NewCategoryHandler(categoryService services.CategoryService) *CategoryHandler {
+	return &CategoryHandler{categoryService: categoryService}
+}
+
+// Request/Response types
+type CreateCategoryRequest struct {
+	Name     string     `json:"name" binding:"required,max=100"`
+	ParentID *uuid.UUID `json:"parent_id"`
+	Kind     string     `json:"kind" binding:"omitempty,oneof=expense income"`
+	Icon     string     `json:"icon" binding:"omitempty,max=50"`
+	Color    string     `json:"color" binding:"omitempty,max=20"`
+}
+
+type UpdateCategoryRequest struct {
+	Name     string     `json:"name" binding:"omitempty,max=100"`
+	ParentID *uuid.UUID `json:"parent_id"`
+	TopLevel bool       `json:"top_level"`
+	Kind     string     `json:"kind" binding:"omitempty,oneof=expense income"`
+	Icon     string     `json:"icon" binding:"omitempty,max=50"`
+	Color    string     `json:"color" binding:"omitempty,max=20"`
+}
+
+type MergeCategoryRequest struct {
+	TargetID uuid.UUID `json:"target_id" binding:"required"`
+}
+
+// ListCategories godoc
+// @Summary List categories
+// @Description Get the authenticated user's top-level categories with their subcategories, ordered by name
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=object{categories=[]models.Category}}
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /categories [get]
+func (h *CategoryHandler) ListCategories(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	categories, err := h.categoryService.GetUserCategories(userID)
+	if err != nil {
+		utils.Error(c, http.StatusInternalServerError, "FETCH_FAILED", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusOK, gin.H{
+		"categories": categories,
+	})
+}
+
+// CreateCategory godoc
+// @Summary Create category
+// @Description Create a category, optionally as a subcategory of a top-level category. Subcategories share their parent's kind.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateCategoryRequest true "Category data"
+// @Success 201 {object} utils.Response{data=object{category=models.Category}}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /categories [post]
+func (h *CategoryHandler) CreateCategory(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	var req CreateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	// Convert to service request
+	serviceReq := services.CreateCategoryRequest{
+		Name:     req.Name,
+		ParentID: req.ParentID,
+		Kind:     req.Kind,
+		Icon:     req.Icon,
+		Color:    req.Color,
+	}
+
+	category, err := h.categoryService.CreateCategory(userID, serviceReq)
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "CREATE_FAILED", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusCreated, gin.H{
+		"category": category,
+	})
+}
+
+// SyncCategories godoc
+// @Summary Create categories from existing records
+// @Description Create a category for every category name used by the user's transactions and budgets that has none yet
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=object{created=[]models.Category}}
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /categories/sync [post]
+func (h *CategoryHandler) SyncCategories(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	created, err := h.categoryService.SyncCategories(userID)
+	if err != nil {
+		utils.Error(c, http.StatusInternalServerError, "SYNC_FAILED", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusOK, gin.H{
+		"created": created,
+	})
+}
+
+// GetCategory godoc
+// @Summary Get category
+// @Description Get a single category with its subcategories
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Category ID"
+// @Success 200 {object} utils.Response{data=object{category=models.Category}}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /categories/{id} [get]
+func (h *CategoryHandler) GetCategory(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "INVALID_ID", "Invalid category ID")
+		return
+	}
+
+	category, err := h.categoryService.GetCategoryByID(id, userID)
+	if err != nil {
+		utils.Error(c, http.StatusNotFound, "NOT_FOUND", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusOK, gin.H{
+		"category": category,
+	})
+}
+
+// UpdateCategory godoc
+// @Summary Update category
+// @Description Update a category or move it under another parent; top_level detaches it from its parent. Renaming a category renames it on the user's transactions, split lines, recurring transactions, budgets and goals.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Category ID"
+// @Param request body UpdateCategoryRequest true "Category update data"
+// @Success 200 {object} utils.Response{data=object{category=models.Category}}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /categories/{id} [put]
+func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "INVALID_ID", "Invalid category ID")
+		return
+	}
+
+	var req UpdateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	// Convert to service request
+	serviceReq := services.UpdateCategoryRequest{
+		Name:     req.Name,
+		ParentID: req.ParentID,
+		TopLevel: req.TopLevel,
+		Kind:     req.Kind,
+		Icon:     req.Icon,
+		Color:    req.Color,
+	}
+
+	category, err := h.categoryService.UpdateCategory(id, userID, serviceReq)
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "UPDATE_FAILED", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusOK, gin.H{
+		"category": category,
+	})
+}
+
+// DeleteCategory godoc
+// @Summary Delete category
+// @Description Delete a category without subcategories that no transaction, budget or goal uses. Merge categories that are in use instead.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Category ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /categories/{id} [delete]
+func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "INVALID_ID", "Invalid category ID")
+		return
+	}
+
+	if err := h.categoryService.DeleteCategory(id, userID); err != nil {
+		utils.Error(c, http.StatusBadRequest, "DELETE_FAILED", err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// MergeCategory godoc
+// @Summary Merge category
+// @Description Merge a category into a target category of the same kind. Its transactions, split lines, recurring transactions, goals and subcategories move to the target, its budget limit is added to the target's budget, and the category is deleted.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Category ID to merge away"
+// @Param request body MergeCategoryRequest true "Target category"
+// @Success 200 {object} utils.Response{data=object{category=models.Category}}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /categories/{id}/merge [post]
+func (h *CategoryHandler) MergeCategory(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "INVALID_ID", "Invalid category ID")
+		return
+	}
+
+	var req MergeCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	category, err := h.categoryService.MergeCategory(id, userID, req.TargetID)
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "MERGE_FAILED", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusOK, gin.H{
+		"category": category,
+	})
+}
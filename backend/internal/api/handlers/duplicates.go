@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/api/middleware"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+	"github.com/nyunja/fity-budget-backend/internal/utils"
+)
+
+type DuplicateHandler struct {
+	duplicateService services.DuplicateService
+}
+
+func NewDuplicateHandler(duplicateService services.DuplicateService) *DuplicateHandler {
+	return &DuplicateHandler{duplicateService: duplicateService}
+}
+
+// Request/Response types
+type MergeTransactionsRequest struct {
+	DuplicateIDs []uuid.UUID `json:"duplicate_ids" binding:"required,min=1,max=50"`
+}
+
+// ListDuplicates godoc
+// @Summary Find duplicate transactions
+// @Description Find groups of income and expenses that likely record the same money movement: same wallet, type and amount, dated within window_days of each other and with similar names. Transactions with different statement references, or posted by the same recurring schedule, are never grouped. The range defaults to the last 90 days.
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param start_date query string false "First day of the range (YYYY-MM-DD)"
+// @Param end_date query string false "Last day of the range, inclusive (YYYY-MM-DD)"
+// @Param window_days query int false "Maximum days between duplicates (1-14)" default(3)
+// @Success 200 {object} utils.Response{data=object{groups=[]services.DuplicateGroup}}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /transactions/duplicates [get]
+func (h *DuplicateHandler) ListDuplicates(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	var req services.FindDuplicatesRequest
+	if value := c.Query("start_date"); value != "" {
+		if req.StartDate, err = time.Parse("2006-01-02", value); err != nil {
+			utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", "start_date must be formatted as YYYY-MM-DD")
+			return
+		}
+	}
+	if value := c.Query("end_date"); value != "" {
+		if req.EndDate, err = time.Parse("2006-01-02", value); err != nil {
+			utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", "end_date must be formatted as YYYY-MM-DD")
+			return
+		}
+		// The end date is inclusive, so scan up to the start of the next day
+		req.EndDate = req.EndDate.AddDate(0, 0, 1)
+	}
+	if value := c.Query("window_days"); value != "" {
+		if req.WindowDays, err = strconv.Atoi(value); err != nil {
+			utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", "window_days must be a whole number")
+			return
+		}
+	}
+
+	groups, err := h.duplicateService.FindDuplicates(userID, req)
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "FETCH_FAILED", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusOK, gin.H{
+		"groups": groups,
+	})
+}
+
+// MergeTransactions godoc
+// @Summary Merge duplicate transactions
+// @Description Keep this transaction and delete the listed duplicates. The kept transaction gains their notes, tags and receipts, and wallet balances are corrected for the removed duplicates.
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Transaction ID to keep"
+// @Param request body MergeTransactionsRequest true "Duplicates to merge"
+// @Success 200 {object} utils.Response{data=object{transaction=models.Transaction}}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /transactions/{id}/merge [post]
+func (h *DuplicateHandler) MergeTransactions(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "INVALID_ID", "Invalid transaction ID")
+		return
+	}
+
+	var req MergeTransactionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	transaction, err := h.duplicateService.MergeDuplicates(id, userID, services.MergeDuplicatesRequest{
+		DuplicateIDs: req.DuplicateIDs,
+	})
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "MERGE_FAILED", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusOK, gin.H{
+		"transaction": transaction,
+	})
+}
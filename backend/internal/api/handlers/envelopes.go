@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/api/middleware"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+	"github.com/nyunja/fity-budget-backend/internal/utils"
+)
+
+type EnvelopeHandler struct {
+	envelopeService services.EnvelopeService
+}
+
+func NewEnvelopeHandler(envelopeService services.EnvelopeService) *EnvelopeHandler {
+	return &EnvelopeHandler{envelopeService: envelopeService}
+}
+
+// Request/Response types
+type AssignEnvelopeRequest struct {
+	BudgetID uuid.UUID    `json:"budget_id" binding:"required"`
+	Month    string       `json:"month"`
+	Amount   money.Amount `json:"amount" binding:"required"`
+	Note     string       `json:"note" binding:"omitempty,max=255"`
+}
+
+type MoveEnvelopeRequest struct {
+	FromBudgetID uuid.UUID    `json:"from_budget_id" binding:"required"`
+	ToBudgetID   uuid.UUID    `json:"to_budget_id" binding:"required"`
+	Month        string       `json:"month"`
+	Amount       money.Amount `json:"amount" binding:"required,gt=0"`
+	Note         string       `json:"note" binding:"omitempty,max=255"`
+}
+
+type CoverEnvelopeRequest struct {
+	BudgetID     uuid.UUID    `json:"budget_id" binding:"required"`
+	FromBudgetID uuid.UUID    `json:"from_budget_id" binding:"required"`
+	Month        string       `json:"month"`
+	Amount       money.Amount `json:"amount" binding:"omitempty,gt=0"`
+	Note         string       `json:"note" binding:"omitempty,max=255"`
+}
+
+// GetEnvelopes godoc
+// @Summary Get envelopes
+// @Description Get the envelope budgets of a month with what was carried in, assigned, moved, spent and is available in each, and the income still ready to assign. Ready to assign is the income received in on-budget wallets up to the end of the month less everything assigned so far.
+// @Tags envelopes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param month query string false "Month (YYYY-MM), defaults to the current month"
+// @Success 200 {object} utils.Response{data=services.EnvelopeMonth}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /envelopes [get]
+func (h *EnvelopeHandler) GetEnvelopes(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	month, err := parseEnvelopeMonth(c.Query("month"))
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	envelopes, err := h.envelopeService.GetEnvelopes(userID, month)
+	if err != nil {
+		utils.Error(c, http.StatusInternalServerError, "FETCH_FAILED", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusOK, envelopes)
+}
+
+// AssignEnvelope godoc
+// @Summary Assign money to an envelope
+// @Description Give an envelope money that is ready to assign in a month, or take unspent money back with a negative amount
+// @Tags envelopes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body AssignEnvelopeRequest true "Envelope, month (YYYY-MM) and amount"
+// @Success 200 {object} utils.Response{data=services.EnvelopeMonth}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /envelopes/assign [post]
+func (h *EnvelopeHandler) AssignEnvelope(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	var req AssignEnvelopeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+	month, err := parseEnvelopeMonth(req.Month)
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	envelopes, err := h.envelopeService.Assign(userID, services.AssignEnvelopeRequest{
+		BudgetID: req.BudgetID,
+		Month:    month,
+		Amount:   req.Amount,
+		Note:     req.Note,
+	})
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "ASSIGN_FAILED", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusOK, envelopes)
+}
+
+// MoveEnvelope godoc
+// @Summary Move money between envelopes
+// @Description Move money available in one envelope to another in a month
+// @Tags envelopes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body MoveEnvelopeRequest true "Source and target envelopes, month (YYYY-MM) and amount"
+// @Success 200 {object} utils.Response{data=services.EnvelopeMonth}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /envelopes/move [post]
+func (h *EnvelopeHandler) MoveEnvelope(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	var req MoveEnvelopeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+	month, err := parseEnvelopeMonth(req.Month)
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	envelopes, err := h.envelopeService.Move(userID, services.MoveEnvelopeRequest{
+		FromBudgetID: req.FromBudgetID,
+		ToBudgetID:   req.ToBudgetID,
+		Month:        month,
+		Amount:       req.Amount,
+		Note:         req.Note,
+	})
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "MOVE_FAILED", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusOK, envelopes)
+}
+
+// CoverEnvelope godoc
+// @Summary Cover an overspent envelope
+// @Description Move money from another envelope into an overspent one. Without an amount the whole overspending is covered.
+// @Tags envelopes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CoverEnvelopeRequest true "Overspent envelope, envelope to cover it from, month (YYYY-MM) and optional amount"
+// @Success 200 {object} utils.Response{data=services.EnvelopeMonth}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /envelopes/cover [post]
+func (h *EnvelopeHandler) CoverEnvelope(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	var req CoverEnvelopeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+	month, err := parseEnvelopeMonth(req.Month)
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	envelopes, err := h.envelopeService.Cover(userID, services.CoverEnvelopeRequest{
+		BudgetID:     req.BudgetID,
+		FromBudgetID: req.FromBudgetID,
+		Month:        month,
+		Amount:       req.Amount,
+		Note:         req.Note,
+	})
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "COVER_FAILED", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusOK, envelopes)
+}
+
+// parseEnvelopeMonth reads an optional YYYY-MM month, defaulting to the current one
+func parseEnvelopeMonth(value string) (time.Time, error) {
+	if value == "" {
+		return time.Now(), nil
+	}
+	month, err := time.Parse("2006-01", value)
+	if err != nil {
+		return time.Time{}, errors.New("month must be formatted as YYYY-MM")
+	}
+	return month, nil
+}
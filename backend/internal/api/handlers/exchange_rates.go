@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+	"github.com/nyunja/fity-budget-backend/internal/utils"
+)
+
+type ExchangeRateHandler struct {
+	exchangeRateService services.ExchangeRateService
+}
+
+func NewExchangeRateHandler(exchangeRateService services.ExchangeRateService) *ExchangeRateHandler {
+	return &ExchangeRateHandler{exchangeRateService: exchangeRateService}
+}
+
+// ListExchangeRates godoc
+// @Summary List exchange rates
+// @Description Get paginated list of stored exchange rates, newest first
+// @Tags exchange-rates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param base query string false "Base currency code"
+// @Param quote query string false "Quote currency code"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(50)
+// @Success 200 {object} utils.Response{data=object{exchange_rates=[]models.ExchangeRate,pagination=object}}
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /exchange-rates [get]
+func (h *ExchangeRateHandler) ListExchangeRates(c *gin.Context) {
+	// Get pagination parameters
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 500 {
+		limit = 50
+	}
+
+	offset := (page - 1) * limit
+
+	rates, total, err := h.exchangeRateService.ListRates(c.Query("base"), c.Query("quote"), limit, offset)
+	if err != nil {
+		utils.Error(c, http.StatusInternalServerError, "FETCH_FAILED", err.Error())
+		return
+	}
+
+	totalPages := (int(total) + limit - 1) / limit
+
+	utils.Success(c, http.StatusOK, gin.H{
+		"exchange_rates": rates,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": totalPages,
+			"has_next":    page < totalPages,
+			"has_prev":    page > 1,
+		},
+	})
+}
+
+// ConvertCurrency godoc
+// @Summary Convert an amount
+// @Description Convert an amount between currencies at the latest rate quoted on or before the given date
+// @Tags exchange-rates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param amount query number true "Amount to convert"
+// @Param from query string true "Currency to convert from"
+// @Param to query string true "Currency to convert to"
+// @Param date query string false "Date of the rate (YYYY-MM-DD), defaults to today"
+// @Success 200 {object} utils.Response{data=object{amount=number,from=string,converted_amount=number,to=string,exchange_rate=services.AppliedRate}}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /exchange-rates/convert [get]
+func (h *ExchangeRateHandler) ConvertCurrency(c *gin.Context) {
+	amount, err := money.Parse(c.Query("amount"))
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", "amount must be a number")
+		return
+	}
+
+	from := strings.ToUpper(c.Query("from"))
+	to := strings.ToUpper(c.Query("to"))
+	if len(from) != 3 || len(to) != 3 {
+		utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", "from and to must be 3-letter currency codes")
+		return
+	}
+
+	on := time.Now()
+	if dateStr := c.Query("date"); dateStr != "" {
+		on, err = time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", "date must be formatted as YYYY-MM-DD")
+			return
+		}
+	}
+
+	converted, applied, err := h.exchangeRateService.Convert(amount, from, to, on)
+	if err != nil {
+		if errors.Is(err, services.ErrNoExchangeRate) {
+			utils.Error(c, http.StatusNotFound, "RATE_NOT_FOUND", err.Error())
+			return
+		}
+		utils.Error(c, http.StatusInternalServerError, "CONVERSION_FAILED", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusOK, gin.H{
+		"amount":           amount,
+		"from":             from,
+		"converted_amount": converted,
+		"to":               to,
+		"exchange_rate":    applied,
+	})
+}
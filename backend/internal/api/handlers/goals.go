@@ -7,6 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/nyunja/fity-budget-backend/internal/api/middleware"
+	"github.com/nyunja/fity-budget-backend/internal/money"
 	"github.com/nyunja/fity-budget-backend/internal/services"
 	"github.com/nyunja/fity-budget-backend/internal/utils"
 )
@@ -21,31 +22,31 @@ func NewGoalHandler(goalService services.GoalService) *GoalHandler {
 
 // Request/Response types
 type CreateGoalRequest struct {
-	Name          string     `json:"name" binding:"required"`
-	TargetAmount  float64    `json:"target" binding:"required,gt=0"`
-	CurrentAmount float64    `json:"current" binding:"omitempty,gte=0"`
-	Color         string     `json:"color" binding:"required"`
-	Icon          string     `json:"icon"`
-	Deadline      *time.Time `json:"deadline"`
-	Priority      string     `json:"priority" binding:"omitempty,oneof=High Medium Low"`
-	Category      string     `json:"category"`
-	Status        string     `json:"status" binding:"omitempty,oneof=Active Paused Completed"`
+	Name          string       `json:"name" binding:"required"`
+	TargetAmount  money.Amount `json:"target" binding:"required,gt=0"`
+	CurrentAmount money.Amount `json:"current" binding:"omitempty,gte=0"`
+	Color         string       `json:"color" binding:"required"`
+	Icon          string       `json:"icon"`
+	Deadline      *time.Time   `json:"deadline"`
+	Priority      string       `json:"priority" binding:"omitempty,oneof=High Medium Low"`
+	Category      string       `json:"category"`
+	Status        string       `json:"status" binding:"omitempty,oneof=Active Paused Completed"`
 }
 
 type UpdateGoalRequest struct {
-	Name          string     `json:"name"`
-	TargetAmount  float64    `json:"target" binding:"omitempty,gt=0"`
-	CurrentAmount float64    `json:"current" binding:"omitempty,gte=0"`
-	Color         string     `json:"color"`
-	Icon          string     `json:"icon"`
-	Deadline      *time.Time `json:"deadline"`
-	Priority      string     `json:"priority" binding:"omitempty,oneof=High Medium Low"`
-	Category      string     `json:"category"`
-	Status        string     `json:"status" binding:"omitempty,oneof=Active Paused Completed"`
+	Name          string       `json:"name"`
+	TargetAmount  money.Amount `json:"target" binding:"omitempty,gt=0"`
+	CurrentAmount money.Amount `json:"current" binding:"omitempty,gte=0"`
+	Color         string       `json:"color"`
+	Icon          string       `json:"icon"`
+	Deadline      *time.Time   `json:"deadline"`
+	Priority      string       `json:"priority" binding:"omitempty,oneof=High Medium Low"`
+	Category      string       `json:"category"`
+	Status        string       `json:"status" binding:"omitempty,oneof=Active Paused Completed"`
 }
 
 type UpdateProgressRequest struct {
-	Amount float64 `json:"amount" binding:"required,gt=0"`
+	Amount money.Amount `json:"amount" binding:"required,gt=0"`
 }
 
 // ListGoals godoc
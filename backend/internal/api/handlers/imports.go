@@ -0,0 +1,331 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/api/middleware"
+	"github.com/nyunja/fity-budget-backend/internal/imports"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+	"github.com/nyunja/fity-budget-backend/internal/utils"
+)
+
+// maxImportFileSize bounds the size of an uploaded statement
+const maxImportFileSize = 5 << 20
+
+type ImportHandler struct {
+	importService services.ImportService
+}
+
+func NewImportHandler(importService services.ImportService) *ImportHandler {
+	return &ImportHandler{importService: importService}
+}
+
+// Request/Response types
+type CommitImportRequest struct {
+	IncludeDuplicates bool `json:"include_duplicates"`
+}
+
+type ImportMpesaMessagesRequest struct {
+	WalletID *uuid.UUID `json:"wallet_id"`
+	Text     string     `json:"text" binding:"required"`
+}
+
+// ListImports godoc
+// @Summary List imports
+// @Description Get paginated list of the user's statement imports, newest first
+// @Tags imports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} utils.Response{data=object{imports=[]models.ImportJob,pagination=object}}
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /imports [get]
+func (h *ImportHandler) ListImports(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	// Get pagination parameters
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	offset := (page - 1) * limit
+
+	jobs, total, err := h.importService.ListImports(userID, limit, offset)
+	if err != nil {
+		utils.Error(c, http.StatusInternalServerError, "FETCH_FAILED", err.Error())
+		return
+	}
+
+	totalPages := (int(total) + limit - 1) / limit
+
+	utils.Success(c, http.StatusOK, gin.H{
+		"imports": jobs,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": totalPages,
+			"has_next":    page < totalPages,
+			"has_prev":    page > 1,
+		},
+	})
+}
+
+// PreviewImport godoc
+// @Summary Upload and preview a statement
+// @Description Parse a CSV, OFX/QFX, QIF or M-PESA text statement into an import job without touching the wallet. CSV columns are detected from the header unless a mapping is given. Rows that match existing transactions, by FITID or reference first, are flagged as duplicates.
+// @Tags imports
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "Statement file"
+// @Param wallet_id formData string true "Wallet to import into"
+// @Param format formData string false "File format (csv, ofx, qfx, qif or mpesa for statement text), inferred from the file name by default"
+// @Param mapping formData string false "CSV column mapping as JSON, e.g. {\"date\":\"Completion Time\",\"description\":\"Details\",\"debit\":\"Withdrawn\",\"credit\":\"Paid In\",\"date_format\":\"YYYY-MM-DD HH:mm:ss\"}"
+// @Success 201 {object} utils.Response{data=object{import=models.ImportJob,mapping=imports.Mapping}}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /imports [post]
+func (h *ImportHandler) PreviewImport(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	walletID, err := uuid.Parse(c.PostForm("wallet_id"))
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", "wallet_id must be a valid wallet ID")
+		return
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", "a statement file is required")
+		return
+	}
+	if header.Size > maxImportFileSize {
+		utils.Error(c, http.StatusBadRequest, "FILE_TOO_LARGE", "statement files must be at most 5 MB")
+		return
+	}
+
+	var mapping *imports.Mapping
+	if raw := c.PostForm("mapping"); raw != "" {
+		mapping = &imports.Mapping{}
+		if err := json.Unmarshal([]byte(raw), mapping); err != nil {
+			utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", "mapping must be a JSON object")
+			return
+		}
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", "could not read the statement file")
+		return
+	}
+	defer file.Close()
+
+	preview, err := h.importService.PreviewImport(userID, services.PreviewImportRequest{
+		WalletID: walletID,
+		FileName: header.Filename,
+		Format:   c.PostForm("format"),
+		File:     file,
+		Mapping:  mapping,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, imports.ErrInvalidFile), errors.Is(err, imports.ErrInvalidMapping),
+			errors.Is(err, imports.ErrNoRecords), errors.Is(err, services.ErrUnsupportedImportFormat):
+			utils.Error(c, http.StatusBadRequest, "INVALID_FILE", err.Error())
+		default:
+			utils.Error(c, http.StatusBadRequest, "IMPORT_FAILED", err.Error())
+		}
+		return
+	}
+
+	utils.Success(c, http.StatusCreated, gin.H{
+		"import":  preview.Job,
+		"mapping": preview.Mapping,
+	})
+}
+
+// GetImport godoc
+// @Summary Get import
+// @Description Get an import job with its rows
+// @Tags imports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Import ID"
+// @Success 200 {object} utils.Response{data=object{import=models.ImportJob}}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /imports/{id} [get]
+func (h *ImportHandler) GetImport(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "INVALID_ID", "Invalid import ID")
+		return
+	}
+
+	job, err := h.importService.GetImport(id, userID)
+	if err != nil {
+		utils.Error(c, http.StatusNotFound, "NOT_FOUND", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusOK, gin.H{
+		"import": job,
+	})
+}
+
+// CommitImport godoc
+// @Summary Commit import
+// @Description Create transactions in the import's wallet for every readable row. Duplicates are skipped unless include_duplicates is set.
+// @Tags imports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Import ID"
+// @Param request body CommitImportRequest false "Commit options"
+// @Success 200 {object} utils.Response{data=object{import=models.ImportJob}}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /imports/{id}/commit [post]
+func (h *ImportHandler) CommitImport(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "INVALID_ID", "Invalid import ID")
+		return
+	}
+
+	var req CommitImportRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+			return
+		}
+	}
+
+	job, err := h.importService.CommitImport(id, userID, services.CommitImportRequest{
+		IncludeDuplicates: req.IncludeDuplicates,
+	})
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "COMMIT_FAILED", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusOK, gin.H{
+		"import": job,
+	})
+}
+
+// UndoImport godoc
+// @Summary Undo import
+// @Description Delete the transactions a committed import created and restore the wallet balance
+// @Tags imports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Import ID"
+// @Success 200 {object} utils.Response{data=object{import=models.ImportJob}}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /imports/{id}/undo [post]
+func (h *ImportHandler) UndoImport(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "INVALID_ID", "Invalid import ID")
+		return
+	}
+
+	job, err := h.importService.UndoImport(id, userID)
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "UNDO_FAILED", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusOK, gin.H{
+		"import": job,
+	})
+}
+
+// ImportMpesaMessages godoc
+// @Summary Import M-PESA messages
+// @Description Read pasted M-PESA confirmation SMS messages (sent, received, Pay Bill, Buy Goods, withdrawal, deposit and airtime) or statement text, and create their transactions and transaction costs in the mobile money wallet. Messages whose M-PESA code was imported before are skipped.
+// @Tags imports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body ImportMpesaMessagesRequest true "Messages and optional wallet, defaulting to the mobile money wallet"
+// @Success 201 {object} utils.Response{data=object{import=models.ImportJob}}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /imports/mpesa [post]
+func (h *ImportHandler) ImportMpesaMessages(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	var req ImportMpesaMessagesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	job, err := h.importService.ImportMpesaMessages(userID, services.ImportMpesaMessagesRequest{
+		WalletID: req.WalletID,
+		Text:     req.Text,
+	})
+	if err != nil {
+		if errors.Is(err, imports.ErrNoRecords) || errors.Is(err, imports.ErrInvalidFile) {
+			utils.Error(c, http.StatusBadRequest, "INVALID_MESSAGES", err.Error())
+			return
+		}
+		utils.Error(c, http.StatusBadRequest, "IMPORT_FAILED", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusCreated, gin.H{
+		"import": job,
+	})
+}
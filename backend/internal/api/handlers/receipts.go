@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/api/middleware"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+	"github.com/nyunja/fity-budget-backend/internal/utils"
+)
+
+type ReceiptHandler struct {
+	receiptService services.ReceiptService
+}
+
+func NewReceiptHandler(receiptService services.ReceiptService) *ReceiptHandler {
+	return &ReceiptHandler{receiptService: receiptService}
+}
+
+// ListTransactionReceipts godoc
+// @Summary List transaction receipts
+// @Description Get the receipts uploaded for a transaction
+// @Tags receipts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Transaction ID"
+// @Success 200 {object} utils.Response{data=object{receipts=[]models.Receipt}}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /transactions/{id}/receipts [get]
+func (h *ReceiptHandler) ListTransactionReceipts(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	transactionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "INVALID_ID", "Invalid transaction ID")
+		return
+	}
+
+	receipts, err := h.receiptService.GetTransactionReceipts(transactionID, userID)
+	if err != nil {
+		utils.Error(c, http.StatusNotFound, "NOT_FOUND", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusOK, gin.H{
+		"receipts": receipts,
+	})
+}
+
+// UploadReceipt godoc
+// @Summary Upload receipt
+// @Description Attach a receipt to a transaction. JPEG, PNG, GIF and WebP images and PDF files up to 10 MB are accepted; the type is detected from the file contents. JPEG, PNG and GIF images get a thumbnail.
+// @Tags receipts
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Transaction ID"
+// @Param file formData file true "Receipt image or PDF"
+// @Success 201 {object} utils.Response{data=object{receipt=models.Receipt}}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /transactions/{id}/receipts [post]
+func (h *ReceiptHandler) UploadReceipt(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	transactionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "INVALID_ID", "Invalid transaction ID")
+		return
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", "a receipt file is required")
+		return
+	}
+	if header.Size > services.MaxReceiptSize {
+		utils.Error(c, http.StatusBadRequest, "FILE_TOO_LARGE", services.ErrReceiptTooLarge.Error())
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", "could not read the receipt file")
+		return
+	}
+	defer file.Close()
+
+	receipt, err := h.receiptService.UploadReceipt(userID, transactionID, services.UploadReceiptRequest{
+		FileName: header.Filename,
+		File:     file,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrReceiptTooLarge):
+			utils.Error(c, http.StatusBadRequest, "FILE_TOO_LARGE", err.Error())
+		case errors.Is(err, services.ErrUnsupportedReceiptType):
+			utils.Error(c, http.StatusBadRequest, "INVALID_FILE", err.Error())
+		default:
+			utils.Error(c, http.StatusBadRequest, "UPLOAD_FAILED", err.Error())
+		}
+		return
+	}
+
+	utils.Success(c, http.StatusCreated, gin.H{
+		"receipt": receipt,
+	})
+}
+
+// GetReceipt godoc
+// @Summary Get receipt
+// @Description Get a receipt's details
+// @Tags receipts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Receipt ID"
+// @Success 200 {object} utils.Response{data=object{receipt=models.Receipt}}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /receipts/{id} [get]
+func (h *ReceiptHandler) GetReceipt(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "INVALID_ID", "Invalid receipt ID")
+		return
+	}
+
+	receipt, err := h.receiptService.GetReceiptByID(id, userID)
+	if err != nil {
+		utils.Error(c, http.StatusNotFound, "NOT_FOUND", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusOK, gin.H{
+		"receipt": receipt,
+	})
+}
+
+// DownloadReceipt godoc
+// @Summary Download receipt
+// @Description Download the receipt file as it was uploaded
+// @Tags receipts
+// @Produce application/octet-stream
+// @Security BearerAuth
+// @Param id path string true "Receipt ID"
+// @Success 200 {file} file
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /receipts/{id}/download [get]
+func (h *ReceiptHandler) DownloadReceipt(c *gin.Context) {
+	h.serveReceipt(c, false)
+}
+
+// DownloadThumbnail godoc
+// @Summary Download receipt thumbnail
+// @Description Download a JPEG thumbnail of an image receipt
+// @Tags receipts
+// @Produce image/jpeg
+// @Security BearerAuth
+// @Param id path string true "Receipt ID"
+// @Success 200 {file} file
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /receipts/{id}/thumbnail [get]
+func (h *ReceiptHandler) DownloadThumbnail(c *gin.Context) {
+	h.serveReceipt(c, true)
+}
+
+// serveReceipt streams a receipt file or its thumbnail to the client
+func (h *ReceiptHandler) serveReceipt(c *gin.Context, thumbnail bool) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "INVALID_ID", "Invalid receipt ID")
+		return
+	}
+
+	file, err := h.receiptService.OpenReceipt(id, userID, thumbnail)
+	if err != nil {
+		utils.Error(c, http.StatusNotFound, "NOT_FOUND", err.Error())
+		return
+	}
+	defer file.Body.Close()
+
+	// Uploaded files are served with the type detected on upload and must
+	// not be re-sniffed by the browser
+	c.DataFromReader(http.StatusOK, file.Size, file.ContentType, file.Body, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("inline", map[string]string{"filename": file.Receipt.FileName}),
+		"X-Content-Type-Options": "nosniff",
+		"Cache-Control":          "private",
+	})
+}
+
+// DeleteReceipt godoc
+// @Summary Delete receipt
+// @Description Delete a receipt and its stored file
+// @Tags receipts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Receipt ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /receipts/{id} [delete]
+func (h *ReceiptHandler) DeleteReceipt(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "INVALID_ID", "Invalid receipt ID")
+		return
+	}
+
+	if err := h.receiptService.DeleteReceipt(id, userID); err != nil {
+		utils.Error(c, http.StatusBadRequest, "DELETE_FAILED", err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
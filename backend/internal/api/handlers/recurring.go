@@ -0,0 +1,351 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/api/middleware"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+	"github.com/nyunja/fity-budget-backend/internal/utils"
+)
+
+type RecurringHandler struct {
+	recurringService services.RecurringTransactionService
+}
+
+func NewRecurringHandler(recurringService services.RecurringTransactionService) *RecurringHandler {
+	return &RecurringHandler{recurringService: recurringService}
+}
+
+// Request/Response types
+type CreateRecurringRequest struct {
+	WalletID       *uuid.UUID   `json:"wallet_id"`
+	Amount         money.Amount `json:"amount" binding:"required,gt=0"`
+	Type           string       `json:"type" binding:"omitempty,oneof=income expense"`
+	Name           string       `json:"name" binding:"required"`
+	Method         string       `json:"method" binding:"required"`
+	Category       string       `json:"category" binding:"required"`
+	Notes          string       `json:"notes"`
+	Frequency      string       `json:"frequency" binding:"required,oneof=daily weekly monthly yearly"`
+	Interval       int          `json:"interval" binding:"omitempty,gte=1"`
+	DayOfMonth     int          `json:"day_of_month" binding:"omitempty,gte=-1,lte=31"`
+	StartDate      time.Time    `json:"start_date" binding:"required"`
+	EndDate        *time.Time   `json:"end_date"`
+	MaxOccurrences *int         `json:"max_occurrences" binding:"omitempty,gte=1"`
+	PostAsPending  bool         `json:"post_as_pending"`
+}
+
+type UpdateRecurringRequest struct {
+	WalletID       *uuid.UUID   `json:"wallet_id"`
+	Amount         money.Amount `json:"amount" binding:"omitempty,gt=0"`
+	Type           string       `json:"type" binding:"omitempty,oneof=income expense"`
+	Name           string       `json:"name"`
+	Method         string       `json:"method"`
+	Category       string       `json:"category"`
+	Notes          string       `json:"notes"`
+	Frequency      string       `json:"frequency" binding:"omitempty,oneof=daily weekly monthly yearly"`
+	Interval       int          `json:"interval" binding:"omitempty,gte=1"`
+	DayOfMonth     *int         `json:"day_of_month" binding:"omitempty,gte=-1,lte=31"`
+	StartDate      time.Time    `json:"start_date"`
+	EndDate        *time.Time   `json:"end_date"`
+	MaxOccurrences *int         `json:"max_occurrences" binding:"omitempty,gte=1"`
+	PostAsPending  *bool        `json:"post_as_pending"`
+	IsActive       *bool        `json:"is_active"`
+}
+
+// ListRecurring godoc
+// @Summary List recurring transactions
+// @Description Get all recurring transactions for the authenticated user, next due first
+// @Tags recurring
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=object{recurring=[]models.RecurringTransaction}}
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /recurring [get]
+func (h *RecurringHandler) ListRecurring(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	recurring, err := h.recurringService.GetUserRecurring(userID)
+	if err != nil {
+		utils.Error(c, http.StatusInternalServerError, "FETCH_FAILED", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusOK, gin.H{
+		"recurring": recurring,
+	})
+}
+
+// GetRecurring godoc
+// @Summary Get recurring transaction
+// @Description Get a single recurring transaction by ID
+// @Tags recurring
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Recurring transaction ID"
+// @Success 200 {object} utils.Response{data=object{recurring=models.RecurringTransaction}}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /recurring/{id} [get]
+func (h *RecurringHandler) GetRecurring(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "INVALID_ID", "Invalid recurring transaction ID")
+		return
+	}
+
+	recurring, err := h.recurringService.GetRecurringByID(id, userID)
+	if err != nil {
+		utils.Error(c, http.StatusNotFound, "NOT_FOUND", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusOK, gin.H{
+		"recurring": recurring,
+	})
+}
+
+// CreateRecurring godoc
+// @Summary Create recurring transaction
+// @Description Schedule a transaction that repeats every interval days, weeks, months or years from start_date. Monthly and yearly schedules fall on day_of_month (-1 for the last day), or the start date's day, moved back in shorter months. Due occurrences are posted by the scheduler as Completed transactions, or Pending ones when post_as_pending is set. Occurrences before today are not posted.
+// @Tags recurring
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateRecurringRequest true "Recurring transaction data"
+// @Success 201 {object} utils.Response{data=object{recurring=models.RecurringTransaction}}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /recurring [post]
+func (h *RecurringHandler) CreateRecurring(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	var req CreateRecurringRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	// Convert to service request
+	serviceReq := services.CreateRecurringRequest{
+		WalletID:       req.WalletID,
+		Amount:         req.Amount,
+		Type:           req.Type,
+		Name:           req.Name,
+		Method:         req.Method,
+		Category:       req.Category,
+		Notes:          req.Notes,
+		Frequency:      req.Frequency,
+		Interval:       req.Interval,
+		DayOfMonth:     req.DayOfMonth,
+		StartDate:      req.StartDate,
+		EndDate:        req.EndDate,
+		MaxOccurrences: req.MaxOccurrences,
+		PostAsPending:  req.PostAsPending,
+	}
+
+	recurring, err := h.recurringService.CreateRecurring(userID, serviceReq)
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "CREATE_FAILED", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusCreated, gin.H{
+		"recurring": recurring,
+	})
+}
+
+// UpdateRecurring godoc
+// @Summary Update recurring transaction
+// @Description Update a recurring transaction, or pause and resume it with is_active. Schedule changes apply from today and never repost past occurrences.
+// @Tags recurring
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Recurring transaction ID"
+// @Param request body UpdateRecurringRequest true "Recurring transaction update data"
+// @Success 200 {object} utils.Response{data=object{recurring=models.RecurringTransaction}}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /recurring/{id} [put]
+func (h *RecurringHandler) UpdateRecurring(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "INVALID_ID", "Invalid recurring transaction ID")
+		return
+	}
+
+	var req UpdateRecurringRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	// Convert to service request
+	serviceReq := services.UpdateRecurringRequest{
+		WalletID:       req.WalletID,
+		Amount:         req.Amount,
+		Type:           req.Type,
+		Name:           req.Name,
+		Method:         req.Method,
+		Category:       req.Category,
+		Notes:          req.Notes,
+		Frequency:      req.Frequency,
+		Interval:       req.Interval,
+		DayOfMonth:     req.DayOfMonth,
+		StartDate:      req.StartDate,
+		EndDate:        req.EndDate,
+		MaxOccurrences: req.MaxOccurrences,
+		PostAsPending:  req.PostAsPending,
+		IsActive:       req.IsActive,
+	}
+
+	recurring, err := h.recurringService.UpdateRecurring(id, userID, serviceReq)
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "UPDATE_FAILED", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusOK, gin.H{
+		"recurring": recurring,
+	})
+}
+
+// DeleteRecurring godoc
+// @Summary Delete recurring transaction
+// @Description Delete a recurring transaction. Transactions it already posted are kept.
+// @Tags recurring
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Recurring transaction ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /recurring/{id} [delete]
+func (h *RecurringHandler) DeleteRecurring(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "INVALID_ID", "Invalid recurring transaction ID")
+		return
+	}
+
+	if err := h.recurringService.DeleteRecurring(id, userID); err != nil {
+		utils.Error(c, http.StatusBadRequest, "DELETE_FAILED", err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// PreviewOccurrences godoc
+// @Summary Preview occurrences
+// @Description List the next dates a recurring transaction will be posted on
+// @Tags recurring
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Recurring transaction ID"
+// @Param limit query int false "Number of occurrences" default(10)
+// @Success 200 {object} utils.Response{data=object{occurrences=[]string}}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /recurring/{id}/occurrences [get]
+func (h *RecurringHandler) PreviewOccurrences(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "INVALID_ID", "Invalid recurring transaction ID")
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	occurrences, err := h.recurringService.PreviewOccurrences(id, userID, limit)
+	if err != nil {
+		utils.Error(c, http.StatusNotFound, "NOT_FOUND", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusOK, gin.H{
+		"occurrences": occurrences,
+	})
+}
+
+// GetUpcoming godoc
+// @Summary Upcoming recurring transactions
+// @Description List the occurrences of all active recurring transactions due in the next days, soonest first
+// @Tags recurring
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param days query int false "Number of days ahead" default(30)
+// @Success 200 {object} utils.Response{data=object{upcoming=[]services.UpcomingOccurrence}}
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /recurring/upcoming [get]
+func (h *RecurringHandler) GetUpcoming(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if days < 1 || days > 366 {
+		days = 30
+	}
+
+	upcoming, err := h.recurringService.GetUpcoming(userID, time.Now().AddDate(0, 0, days))
+	if err != nil {
+		utils.Error(c, http.StatusInternalServerError, "FETCH_FAILED", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusOK, gin.H{
+		"upcoming": upcoming,
+	})
+}
@@ -0,0 +1,312 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/api/middleware"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+	"github.com/nyunja/fity-budget-backend/internal/utils"
+)
+
+type RuleHandler struct {
+	ruleService services.RuleService
+}
+
+func NewRuleHandler(ruleService services.RuleService) *RuleHandler {
+	return &RuleHandler{ruleService: ruleService}
+}
+
+// Request/Response types
+type CreateRuleRequest struct {
+	Name        string        `json:"name" binding:"required,max=100"`
+	Priority    int           `json:"priority"`
+	MatchField  string        `json:"match_field" binding:"omitempty,oneof=name notes any"`
+	MatchType   string        `json:"match_type" binding:"omitempty,oneof=contains regex"`
+	Pattern     string        `json:"pattern" binding:"max=255"`
+	MinAmount   *money.Amount `json:"min_amount" binding:"omitempty,gte=0"`
+	MaxAmount   *money.Amount `json:"max_amount" binding:"omitempty,gte=0"`
+	Method      string        `json:"method" binding:"max=100"`
+	WalletID    *uuid.UUID    `json:"wallet_id"`
+	Type        string        `json:"type" binding:"omitempty,oneof=income expense"`
+	SetCategory string        `json:"set_category" binding:"max=100"`
+	SetNotes    string        `json:"set_notes"`
+	AddTags     []string      `json:"add_tags" binding:"omitempty,dive,max=50"`
+}
+
+type UpdateRuleRequest struct {
+	Name        string        `json:"name" binding:"omitempty,max=100"`
+	Priority    *int          `json:"priority"`
+	IsActive    *bool         `json:"is_active"`
+	MatchField  string        `json:"match_field" binding:"omitempty,oneof=name notes any"`
+	MatchType   string        `json:"match_type" binding:"omitempty,oneof=contains regex"`
+	Pattern     *string       `json:"pattern" binding:"omitempty,max=255"`
+	MinAmount   *money.Amount `json:"min_amount" binding:"omitempty,gte=0"`
+	MaxAmount   *money.Amount `json:"max_amount" binding:"omitempty,gte=0"`
+	Method      *string       `json:"method" binding:"omitempty,max=100"`
+	WalletID    *uuid.UUID    `json:"wallet_id"`
+	Type        *string       `json:"type" binding:"omitempty,oneof=income expense"`
+	SetCategory *string       `json:"set_category" binding:"omitempty,max=100"`
+	SetNotes    *string       `json:"set_notes"`
+	AddTags     *[]string     `json:"add_tags" binding:"omitempty,dive,max=50"`
+}
+
+type ApplyRulesRequest struct {
+	StartDate time.Time `json:"start_date" binding:"required"`
+	EndDate   time.Time `json:"end_date" binding:"required"`
+	DryRun    bool      `json:"dry_run"`
+}
+
+// ListRules godoc
+// @Summary List rules
+// @Description Get the authenticated user's auto-categorization rules in the order they run, lowest priority first
+// @Tags rules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=object{rules=[]models.Rule}}
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /rules [get]
+func (h *RuleHandler) ListRules(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	rules, err := h.ruleService.GetUserRules(userID)
+	if err != nil {
+		utils.Error(c, http.StatusInternalServerError, "FETCH_FAILED", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusOK, gin.H{
+		"rules": rules,
+	})
+}
+
+// CreateRule godoc
+// @Summary Create rule
+// @Description Create an auto-categorization rule. A transaction matches when its name, notes or both contain the pattern (or match it as a regular expression, ignoring case) and it meets the amount range, method, wallet and type set. Matching rules set the category and notes and add tags to new and imported transactions.
+// @Tags rules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateRuleRequest true "Rule data"
+// @Success 201 {object} utils.Response{data=object{rule=models.Rule}}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /rules [post]
+func (h *RuleHandler) CreateRule(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	var req CreateRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	// Convert to service request
+	serviceReq := services.CreateRuleRequest{
+		Name:        req.Name,
+		Priority:    req.Priority,
+		MatchField:  req.MatchField,
+		MatchType:   req.MatchType,
+		Pattern:     req.Pattern,
+		MinAmount:   req.MinAmount,
+		MaxAmount:   req.MaxAmount,
+		Method:      req.Method,
+		WalletID:    req.WalletID,
+		Type:        req.Type,
+		SetCategory: req.SetCategory,
+		SetNotes:    req.SetNotes,
+		AddTags:     req.AddTags,
+	}
+
+	rule, err := h.ruleService.CreateRule(userID, serviceReq)
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "CREATE_FAILED", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusCreated, gin.H{
+		"rule": rule,
+	})
+}
+
+// ApplyRules godoc
+// @Summary Re-apply rules
+// @Description Re-apply the active rules to the transactions dated from start_date up to, but not including, end_date. Matching rules overwrite the category (except on split transactions) and notes and add missing tags. With dry_run the changes are listed without being saved.
+// @Tags rules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body ApplyRulesRequest true "Date range"
+// @Success 200 {object} utils.Response{data=services.ApplyRulesResult}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /rules/apply [post]
+func (h *RuleHandler) ApplyRules(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	var req ApplyRulesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	result, err := h.ruleService.ApplyRules(userID, services.ApplyRulesRequest{
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+		DryRun:    req.DryRun,
+	})
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "APPLY_FAILED", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusOK, result)
+}
+
+// GetRule godoc
+// @Summary Get rule
+// @Description Get a single auto-categorization rule
+// @Tags rules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Rule ID"
+// @Success 200 {object} utils.Response{data=object{rule=models.Rule}}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /rules/{id} [get]
+func (h *RuleHandler) GetRule(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "INVALID_ID", "Invalid rule ID")
+		return
+	}
+
+	rule, err := h.ruleService.GetRuleByID(id, userID)
+	if err != nil {
+		utils.Error(c, http.StatusNotFound, "NOT_FOUND", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusOK, gin.H{
+		"rule": rule,
+	})
+}
+
+// UpdateRule godoc
+// @Summary Update rule
+// @Description Update a rule's conditions, actions, priority or active state. add_tags replaces the tags the rule adds.
+// @Tags rules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Rule ID"
+// @Param request body UpdateRuleRequest true "Rule update data"
+// @Success 200 {object} utils.Response{data=object{rule=models.Rule}}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /rules/{id} [put]
+func (h *RuleHandler) UpdateRule(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "INVALID_ID", "Invalid rule ID")
+		return
+	}
+
+	var req UpdateRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	// Convert to service request
+	serviceReq := services.UpdateRuleRequest{
+		Name:        req.Name,
+		Priority:    req.Priority,
+		IsActive:    req.IsActive,
+		MatchField:  req.MatchField,
+		MatchType:   req.MatchType,
+		Pattern:     req.Pattern,
+		MinAmount:   req.MinAmount,
+		MaxAmount:   req.MaxAmount,
+		Method:      req.Method,
+		WalletID:    req.WalletID,
+		Type:        req.Type,
+		SetCategory: req.SetCategory,
+		SetNotes:    req.SetNotes,
+		AddTags:     req.AddTags,
+	}
+
+	rule, err := h.ruleService.UpdateRule(id, userID, serviceReq)
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "UPDATE_FAILED", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusOK, gin.H{
+		"rule": rule,
+	})
+}
+
+// DeleteRule godoc
+// @Summary Delete rule
+// @Description Delete a rule. Changes it already made to transactions are kept.
+// @Tags rules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Rule ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /rules/{id} [delete]
+func (h *RuleHandler) DeleteRule(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "INVALID_ID", "Invalid rule ID")
+		return
+	}
+
+	if err := h.ruleService.DeleteRule(id, userID); err != nil {
+		utils.Error(c, http.StatusBadRequest, "DELETE_FAILED", err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/api/middleware"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+	"github.com/nyunja/fity-budget-backend/internal/utils"
+)
+
+type TagHandler struct {
+	tagService services.TagService
+}
+
+func NewTagHandler(tagService services.TagService) *TagHandler {
+	return &TagHandler{tagService: tagService}
+}
+
+// Request/Response types
+type CreateTagRequest struct {
+	Name  string `json:"name" binding:"required,max=50"`
+	Color string `json:"color" binding:"omitempty,max=20"`
+}
+
+type UpdateTagRequest struct {
+	Name  string `json:"name" binding:"omitempty,max=50"`
+	Color string `json:"color" binding:"omitempty,max=20"`
+}
+
+// ListTags godoc
+// @Summary List tags
+// @Description Get the authenticated user's tags ordered by name
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=object{tags=[]models.Tag}}
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /tags [get]
+func (h *TagHandler) ListTags(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	tags, err := h.tagService.GetUserTags(userID)
+	if err != nil {
+		utils.Error(c, http.StatusInternalServerError, "FETCH_FAILED", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusOK, gin.H{
+		"tags": tags,
+	})
+}
+
+// CreateTag godoc
+// @Summary Create tag
+// @Description Create a tag. Tag names are unique per user regardless of case.
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateTagRequest true "Tag data"
+// @Success 201 {object} utils.Response{data=object{tag=models.Tag}}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /tags [post]
+func (h *TagHandler) CreateTag(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	var req CreateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	tag, err := h.tagService.CreateTag(userID, services.CreateTagRequest{
+		Name:  req.Name,
+		Color: req.Color,
+	})
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "CREATE_FAILED", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusCreated, gin.H{
+		"tag": tag,
+	})
+}
+
+// GetTag godoc
+// @Summary Get tag
+// @Description Get a single tag
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tag ID"
+// @Success 200 {object} utils.Response{data=object{tag=models.Tag}}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /tags/{id} [get]
+func (h *TagHandler) GetTag(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "INVALID_ID", "Invalid tag ID")
+		return
+	}
+
+	tag, err := h.tagService.GetTagByID(id, userID)
+	if err != nil {
+		utils.Error(c, http.StatusNotFound, "NOT_FOUND", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusOK, gin.H{
+		"tag": tag,
+	})
+}
+
+// UpdateTag godoc
+// @Summary Update tag
+// @Description Rename or recolor a tag. Tagged transactions and rules follow the rename.
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tag ID"
+// @Param request body UpdateTagRequest true "Tag update data"
+// @Success 200 {object} utils.Response{data=object{tag=models.Tag}}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /tags/{id} [put]
+func (h *TagHandler) UpdateTag(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "INVALID_ID", "Invalid tag ID")
+		return
+	}
+
+	var req UpdateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	tag, err := h.tagService.UpdateTag(id, userID, services.UpdateTagRequest{
+		Name:  req.Name,
+		Color: req.Color,
+	})
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "UPDATE_FAILED", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusOK, gin.H{
+		"tag": tag,
+	})
+}
+
+// DeleteTag godoc
+// @Summary Delete tag
+// @Description Delete a tag and remove it from the transactions and rules that carry it
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tag ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /tags/{id} [delete]
+func (h *TagHandler) DeleteTag(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "INVALID_ID", "Invalid tag ID")
+		return
+	}
+
+	if err := h.tagService.DeleteTag(id, userID); err != nil {
+		utils.Error(c, http.StatusBadRequest, "DELETE_FAILED", err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -1,13 +1,17 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/nyunja/fity-budget-backend/internal/api/middleware"
+	"github.com/nyunja/fity-budget-backend/internal/money"
 	"github.com/nyunja/fity-budget-backend/internal/services"
 	"github.com/nyunja/fity-budget-backend/internal/utils"
 )
@@ -22,38 +26,65 @@ func NewTransactionHandler(transactionService services.TransactionService) *Tran
 
 // Request/Response types
 type CreateTransactionRequest struct {
-	WalletID        *uuid.UUID `json:"wallet_id"`
-	Amount          float64    `json:"amount" binding:"required,gt=0"`
-	Name     string     `json:"name" binding:"required"`
-	Method          string     `json:"method"`
-	Category        string     `json:"category" binding:"required"`
-	Status          string     `json:"status" binding:"omitempty,oneof=Completed Pending Failed"`
-	Notes           string     `json:"notes"`
-	ReceiptURL      string     `json:"receipt_url"`
-	TransactionDate *time.Time `json:"transaction_date"`
+	WalletID        *uuid.UUID   `json:"wallet_id"`
+	Amount          money.Amount `json:"amount" binding:"required,gt=0"`
+	Type            string       `json:"type" binding:"omitempty,oneof=income expense"`
+	Name            string       `json:"name" binding:"required"`
+	Method          string       `json:"method"`
+	Category        string       `json:"category"`
+	Status          string       `json:"status" binding:"omitempty,oneof=Completed Pending Failed"`
+	Notes           string       `json:"notes"`
+	ReceiptURL      string       `json:"receipt_url"`
+	TransactionDate *time.Time   `json:"transaction_date"`
+	// Splits divide the amount between categories and must add up to it
+	Splits []services.TransactionSplitRequest `json:"splits" binding:"omitempty,dive"`
+	// Tags names the tags to attach; missing tags are created
+	Tags []string `json:"tags" binding:"omitempty,dive,max=50"`
 }
 
 type UpdateTransactionRequest struct {
-	Amount          float64    `json:"amount" binding:"omitempty,gt=0"`
-	Name            string     `json:"name"`
-	Method          string     `json:"method"`
-	Category        string     `json:"category"`
-	Status          string     `json:"status" binding:"omitempty,oneof=Completed Pending Failed"`
-	Notes           string     `json:"notes"`
-	ReceiptURL      string     `json:"receipt_url"`
-	TransactionDate *time.Time `json:"transaction_date"`
+	WalletID        *uuid.UUID   `json:"wallet_id"`
+	Amount          money.Amount `json:"amount" binding:"omitempty,gt=0"`
+	Type            string       `json:"type" binding:"omitempty,oneof=income expense"`
+	Name            string       `json:"name"`
+	Method          string       `json:"method"`
+	Category        string       `json:"category"`
+	Status          string       `json:"status" binding:"omitempty,oneof=Completed Pending Failed"`
+	Notes           string       `json:"notes"`
+	ReceiptURL      string       `json:"receipt_url"`
+	TransactionDate *time.Time   `json:"transaction_date"`
+	// Splits replaces the split lines when present; an empty list removes them
+	Splits *[]services.TransactionSplitRequest `json:"splits" binding:"omitempty,dive"`
+	// Tags replaces the tags when present; an empty list removes them
+	Tags *[]string `json:"tags" binding:"omitempty,dive,max=50"`
 }
 
 // ListTransactions godoc
 // @Summary List transactions
-// @Description Get paginated list of user's transactions
+// @Description Get a filtered, sorted and paginated list of user's transactions. List filters accept repeated parameters or comma-separated values.
+// @Description Passing a cursor parameter (empty for the first page) switches to keyset pagination by transaction date, which returns next_cursor and prev_cursor instead of page counts.
 // @Tags transactions
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(20)
+// @Param cursor query string false "Opaque cursor from next_cursor or prev_cursor"
+// @Param start_date query string false "Earliest transaction date (YYYY-MM-DD)"
+// @Param end_date query string false "Latest transaction date, inclusive (YYYY-MM-DD)"
+// @Param category query []string false "Categories" collectionFormat(multi)
+// @Param wallet_id query []string false "Wallet IDs" collectionFormat(multi)
+// @Param tag_id query []string false "Tag IDs; matches transactions with any of them" collectionFormat(multi)
+// @Param status query []string false "Statuses (Completed, Pending, Failed)" collectionFormat(multi)
+// @Param type query []string false "Types (income, expense, transfer)" collectionFormat(multi)
+// @Param method query []string false "Payment methods" collectionFormat(multi)
+// @Param min_amount query number false "Minimum amount"
+// @Param max_amount query number false "Maximum amount"
+// @Param search query string false "Text to search for in name and notes"
+// @Param sort_by query string false "Sort field (transaction_date, amount, name, category, created_at)" default(transaction_date)
+// @Param sort_order query string false "Sort direction (asc, desc)" default(desc)
 // @Success 200 {object} utils.Response{data=object{data=[]models.Transaction,pagination=object}}
+// @Failure 400 {object} utils.Response
 // @Failure 401 {object} utils.Response
 // @Failure 500 {object} utils.Response
 // @Router /transactions [get]
@@ -75,15 +106,32 @@ func (h *TransactionHandler) ListTransactions(c *gin.Context) {
 		limit = 20
 	}
 
-	offset := (page - 1) * limit
+	query, err := parseTransactionListQuery(c)
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+	query.Limit = limit
+
+	if cursor, ok := c.GetQuery("cursor"); ok {
+		query.Cursor = cursor
+		h.listTransactionsByCursor(c, userID, query)
+		return
+	}
 
-	transactions, total, err := h.transactionService.GetUserTransactionsWithCount(userID, limit, offset)
+	query.Offset = (page - 1) * limit
+
+	transactions, total, err := h.transactionService.ListTransactions(userID, query)
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidTransactionQuery) {
+			utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+			return
+		}
 		utils.Error(c, http.StatusInternalServerError, "FETCH_FAILED", err.Error())
 		return
 	}
 
-	// Calculate pagination metadata
+	// Calculate pagination metadata over the filtered total
 	totalPages := (int(total) + limit - 1) / limit // Ceiling division
 	hasNext := page < totalPages
 	hasPrev := page > 1
@@ -101,6 +149,108 @@ func (h *TransactionHandler) ListTransactions(c *gin.Context) {
 	})
 }
 
+// listTransactionsByCursor responds with a keyset-paginated page of transactions
+func (h *TransactionHandler) listTransactionsByCursor(c *gin.Context, userID uuid.UUID, query services.TransactionListQuery) {
+	page, err := h.transactionService.ListTransactionsByCursor(userID, query)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidTransactionQuery) {
+			utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+			return
+		}
+		utils.Error(c, http.StatusInternalServerError, "FETCH_FAILED", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusOK, gin.H{
+		"data": page.Transactions,
+		"pagination": gin.H{
+			"limit":       query.Limit,
+			"next_cursor": page.NextCursor,
+			"prev_cursor": page.PrevCursor,
+			"has_next":    page.NextCursor != "",
+			"has_prev":    page.PrevCursor != "",
+		},
+	})
+}
+
+// parseTransactionListQuery reads the filter and sort parameters of a
+// transaction listing. Values are checked for format here; the service
+// validates their meaning.
+func parseTransactionListQuery(c *gin.Context) (services.TransactionListQuery, error) {
+	query := services.TransactionListQuery{
+		Categories: queryList(c, "category"),
+		Statuses:   queryList(c, "status"),
+		Types:      queryList(c, "type"),
+		Methods:    queryList(c, "method"),
+		Search:     c.Query("search"),
+		SortBy:     c.Query("sort_by"),
+		SortOrder:  c.Query("sort_order"),
+	}
+
+	if value := c.Query("start_date"); value != "" {
+		startDate, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return query, errors.New("start_date must be formatted as YYYY-MM-DD")
+		}
+		query.StartDate = startDate
+	}
+	if value := c.Query("end_date"); value != "" {
+		endDate, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return query, errors.New("end_date must be formatted as YYYY-MM-DD")
+		}
+		// The end date is inclusive, so list up to the start of the next day
+		query.EndDate = endDate.AddDate(0, 0, 1)
+	}
+
+	for _, value := range queryList(c, "wallet_id") {
+		walletID, err := uuid.Parse(value)
+		if err != nil {
+			return query, fmt.Errorf("invalid wallet_id %q", value)
+		}
+		query.WalletIDs = append(query.WalletIDs, walletID)
+	}
+
+	for _, value := range queryList(c, "tag_id") {
+		tagID, err := uuid.Parse(value)
+		if err != nil {
+			return query, fmt.Errorf("invalid tag_id %q", value)
+		}
+		query.TagIDs = append(query.TagIDs, tagID)
+	}
+
+	if value := c.Query("min_amount"); value != "" {
+		minAmount, err := money.Parse(value)
+		if err != nil {
+			return query, errors.New("min_amount must be a number")
+		}
+		query.MinAmount = &minAmount
+	}
+	if value := c.Query("max_amount"); value != "" {
+		maxAmount, err := money.Parse(value)
+		if err != nil {
+			return query, errors.New("max_amount must be a number")
+		}
+		query.MaxAmount = &maxAmount
+	}
+
+	return query, nil
+}
+
+// queryList collects a query parameter that may be repeated or hold
+// comma-separated values, skipping blanks
+func queryList(c *gin.Context, key string) []string {
+	var values []string
+	for _, raw := range c.QueryArray(key) {
+		for _, value := range strings.Split(raw, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
 // GetTransaction godoc
 // @Summary Get transaction
 // @Description Get a single transaction by ID
@@ -141,7 +291,7 @@ func (h *TransactionHandler) GetTransaction(c *gin.Context) {
 
 // CreateTransaction godoc
 // @Summary Create transaction
-// @Description Create a new transaction
+// @Description Create a new transaction. Optional splits divide the amount between categories and must add up to it; category then defaults to the largest split. The user's rules fill in a missing category and notes and add tags; a transaction left uncategorized is filed under Uncategorized, or Income for income.
 // @Tags transactions
 // @Accept json
 // @Produce json
@@ -175,6 +325,7 @@ func (h *TransactionHandler) CreateTransaction(c *gin.Context) {
 	serviceReq := services.CreateTransactionRequest{
 		WalletID:        req.WalletID,
 		Amount:          req.Amount,
+		Type:            req.Type,
 		Name:            req.Name,
 		Method:          req.Method,
 		Category:        req.Category,
@@ -182,6 +333,8 @@ func (h *TransactionHandler) CreateTransaction(c *gin.Context) {
 		Notes:           req.Notes,
 		ReceiptURL:      req.ReceiptURL,
 		TransactionDate: transactionDate,
+		Splits:          req.Splits,
+		Tags:            req.Tags,
 	}
 
 	transaction, err := h.transactionService.CreateTransaction(userID, serviceReq)
@@ -197,7 +350,7 @@ func (h *TransactionHandler) CreateTransaction(c *gin.Context) {
 
 // UpdateTransaction godoc
 // @Summary Update transaction
-// @Description Update an existing transaction
+// @Description Update an existing transaction. Splits, when present, replace the split lines and an empty list removes them.
 // @Tags transactions
 // @Accept json
 // @Produce json
@@ -235,7 +388,9 @@ func (h *TransactionHandler) UpdateTransaction(c *gin.Context) {
 	}
 
 	serviceReq := services.UpdateTransactionRequest{
+		WalletID:        req.WalletID,
 		Amount:          req.Amount,
+		Type:            req.Type,
 		Name:            req.Name,
 		Method:          req.Method,
 		Category:        req.Category,
@@ -243,6 +398,8 @@ func (h *TransactionHandler) UpdateTransaction(c *gin.Context) {
 		Notes:           req.Notes,
 		ReceiptURL:      req.ReceiptURL,
 		TransactionDate: transactionDate,
+		Splits:          req.Splits,
+		Tags:            req.Tags,
 	}
 
 	transaction, err := h.transactionService.UpdateTransaction(id, userID, serviceReq)
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/api/middleware"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+	"github.com/nyunja/fity-budget-backend/internal/utils"
+)
+
+type TrashHandler struct {
+	trashService services.TrashService
+}
+
+func NewTrashHandler(trashService services.TrashService) *TrashHandler {
+	return &TrashHandler{trashService: trashService}
+}
+
+// ListTrash godoc
+// @Summary List trash
+// @Description Get the authenticated user's deleted transactions, budgets, goals and wallets, most recently deleted first. purge_at is when the retention job removes an item for good.
+// @Tags trash
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=object{trash=services.Trash}}
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /trash [get]
+func (h *TrashHandler) ListTrash(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	trash, err := h.trashService.GetTrash(userID)
+	if err != nil {
+		utils.Error(c, http.StatusInternalServerError, "FETCH_FAILED", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusOK, gin.H{
+		"trash": trash,
+	})
+}
+
+// RestoreItem godoc
+// @Summary Restore deleted item
+// @Description Restore a deleted transaction, budget, goal or wallet. A restored transaction counts towards its wallet balance again, so its wallet must be restored first.
+// @Tags trash
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param type path string true "Item type" Enums(transaction, budget, goal, wallet)
+// @Param id path string true "Item ID"
+// @Success 200 {object} utils.Response{data=object{item=object}}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /trash/{type}/{id}/restore [post]
+func (h *TrashHandler) RestoreItem(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "INVALID_ID", "Invalid item ID")
+		return
+	}
+
+	item, err := h.trashService.RestoreItem(c.Param("type"), id, userID)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownTrashType) {
+			utils.Error(c, http.StatusBadRequest, "INVALID_TYPE", err.Error())
+			return
+		}
+		utils.Error(c, http.StatusBadRequest, "RESTORE_FAILED", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusOK, gin.H{
+		"item": item,
+	})
+}
+
+// PurgeItem godoc
+// @Summary Permanently delete item
+// @Description Permanently delete an item from the trash. Transactions are removed with their splits and receipt files. Wallets can only be purged once no transactions, transfers or imports refer to them.
+// @Tags trash
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param type path string true "Item type" Enums(transaction, budget, goal, wallet)
+// @Param id path string true "Item ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /trash/{type}/{id} [delete]
+func (h *TrashHandler) PurgeItem(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "INVALID_ID", "Invalid item ID")
+		return
+	}
+
+	if err := h.trashService.PurgeItem(c.Param("type"), id, userID); err != nil {
+		if errors.Is(err, services.ErrUnknownTrashType) {
+			utils.Error(c, http.StatusBadRequest, "INVALID_TYPE", err.Error())
+			return
+		}
+		utils.Error(c, http.StatusBadRequest, "DELETE_FAILED", err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -2,10 +2,13 @@ package handlers
 
 import (
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/nyunja/fity-budget-backend/internal/api/middleware"
+	"github.com/nyunja/fity-budget-backend/internal/money"
 	"github.com/nyunja/fity-budget-backend/internal/services"
 	"github.com/nyunja/fity-budget-backend/internal/utils"
 )
@@ -20,23 +23,33 @@ func NewWalletHandler(walletService services.WalletService) *WalletHandler {
 
 // Request/Response types
 type CreateWalletRequest struct {
-	Name          string  `json:"name" binding:"required"`
-	Type          string  `json:"type" binding:"required"`
-	Balance       float64 `json:"balance" binding:"omitempty,gte=0"`
-	Currency      string  `json:"currency"`
-	Color         string  `json:"color" binding:"required"`
-	AccountNumber string  `json:"account_number"`
-	IsDefault     bool    `json:"is_default"`
+	Name          string       `json:"name" binding:"required"`
+	Type          string       `json:"type" binding:"required"`
+	Balance       money.Amount `json:"balance" binding:"omitempty,gte=0"`
+	Currency      string       `json:"currency"`
+	Color         string       `json:"color" binding:"required"`
+	AccountNumber string       `json:"account_number"`
+	IsDefault     bool         `json:"is_default"`
+	OffBudget     bool         `json:"off_budget"`
 }
 
 type UpdateWalletRequest struct {
-	Name          string  `json:"name"`
-	Type          string  `json:"type"`
-	Balance       float64 `json:"balance" binding:"omitempty,gte=0"`
-	Currency      string  `json:"currency"`
-	Color         string  `json:"color"`
-	AccountNumber string  `json:"account_number"`
-	IsDefault     *bool   `json:"is_default"`
+	Name          string        `json:"name"`
+	Type          string        `json:"type"`
+	Balance       *money.Amount `json:"balance" binding:"omitempty,gte=0"`
+	Currency      string        `json:"currency"`
+	Color         string        `json:"color"`
+	AccountNumber string        `json:"account_number"`
+	IsDefault     *bool         `json:"is_default"`
+	OffBudget     *bool         `json:"off_budget"`
+}
+
+type CreateTransferRequest struct {
+	FromWalletID uuid.UUID    `json:"from_wallet_id" binding:"required"`
+	ToWalletID   uuid.UUID    `json:"to_wallet_id" binding:"required"`
+	Amount       money.Amount `json:"amount" binding:"required,gt=0"`
+	Notes        string       `json:"notes"`
+	TransferDate *time.Time   `json:"transfer_date"`
 }
 
 // ListWallets godoc
@@ -140,6 +153,7 @@ func (h *WalletHandler) CreateWallet(c *gin.Context) {
 		Color:         req.Color,
 		AccountNumber: req.AccountNumber,
 		IsDefault:     req.IsDefault,
+		OffBudget:     req.OffBudget,
 	}
 
 	wallet, err := h.walletService.CreateWallet(userID, serviceReq)
@@ -203,6 +217,7 @@ func (h *WalletHandler) UpdateWallet(c *gin.Context) {
 		Currency:      req.Currency,
 		Color:         req.Color,
 		AccountNumber: req.AccountNumber,
+		OffBudget:     req.OffBudget,
 	}
 
 	wallet, err := h.walletService.UpdateWallet(id, userID, serviceReq)
@@ -249,3 +264,177 @@ func (h *WalletHandler) DeleteWallet(c *gin.Context) {
 
 	c.Status(http.StatusNoContent)
 }
+
+// CreateTransfer godoc
+// @Summary Transfer between wallets
+// @Description Move money between two of the user's wallets atomically. Wallets in different currencies are credited at the exchange rate for the transfer date.
+// @Tags wallets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateTransferRequest true "Transfer data"
+// @Success 201 {object} utils.Response{data=object{transfer=models.Transfer}}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /wallets/transfers [post]
+func (h *WalletHandler) CreateTransfer(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	var req CreateTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	// Convert to service request
+	serviceReq := services.CreateTransferRequest{
+		FromWalletID: req.FromWalletID,
+		ToWalletID:   req.ToWalletID,
+		Amount:       req.Amount,
+		Notes:        req.Notes,
+	}
+	if req.TransferDate != nil {
+		serviceReq.TransferDate = *req.TransferDate
+	}
+
+	transfer, err := h.walletService.TransferBetweenWallets(userID, serviceReq)
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "TRANSFER_FAILED", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusCreated, gin.H{
+		"transfer": transfer,
+	})
+}
+
+// ListTransfers godoc
+// @Summary List transfers
+// @Description Get paginated list of the user's wallet-to-wallet transfers
+// @Tags wallets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} utils.Response{data=object{transfers=[]models.Transfer,pagination=object}}
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /wallets/transfers [get]
+func (h *WalletHandler) ListTransfers(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	// Get pagination parameters
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	offset := (page - 1) * limit
+
+	transfers, total, err := h.walletService.GetUserTransfers(userID, limit, offset)
+	if err != nil {
+		utils.Error(c, http.StatusInternalServerError, "FETCH_FAILED", err.Error())
+		return
+	}
+
+	totalPages := (int(total) + limit - 1) / limit
+
+	utils.Success(c, http.StatusOK, gin.H{
+		"transfers": transfers,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": totalPages,
+			"has_next":    page < totalPages,
+			"has_prev":    page > 1,
+		},
+	})
+}
+
+// GetTransfer godoc
+// @Summary Get transfer
+// @Description Get a single wallet-to-wallet transfer by ID
+// @Tags wallets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Transfer ID"
+// @Success 200 {object} utils.Response{data=object{transfer=models.Transfer}}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /wallets/transfers/{id} [get]
+func (h *WalletHandler) GetTransfer(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "INVALID_ID", "Invalid transfer ID")
+		return
+	}
+
+	transfer, err := h.walletService.GetTransferByID(id, userID)
+	if err != nil {
+		utils.Error(c, http.StatusNotFound, "NOT_FOUND", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusOK, gin.H{
+		"transfer": transfer,
+	})
+}
+
+// ReverseTransfer godoc
+// @Summary Reverse transfer
+// @Description Send the money of a completed transfer back to its source wallet
+// @Tags wallets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Transfer ID"
+// @Success 201 {object} utils.Response{data=object{transfer=models.Transfer}}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /wallets/transfers/{id}/reverse [post]
+func (h *WalletHandler) ReverseTransfer(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		utils.Error(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "INVALID_ID", "Invalid transfer ID")
+		return
+	}
+
+	reversal, err := h.walletService.ReverseTransfer(id, userID)
+	if err != nil {
+		utils.Error(c, http.StatusBadRequest, "REVERSE_FAILED", err.Error())
+		return
+	}
+
+	utils.Success(c, http.StatusCreated, gin.H{
+		"transfer": reversal,
+	})
+}
@@ -17,6 +17,18 @@ func SetupRoutes(
 	budgetHandler *handlers.BudgetHandler,
 	walletHandler *handlers.WalletHandler,
 	analyticsHandler *handlers.AnalyticsHandler,
+	exchangeRateHandler *handlers.ExchangeRateHandler,
+	importHandler *handlers.ImportHandler,
+	recurringHandler *handlers.RecurringHandler,
+	categoryHandler *handlers.CategoryHandler,
+	ruleHandler *handlers.RuleHandler,
+	tagHandler *handlers.TagHandler,
+	receiptHandler *handlers.ReceiptHandler,
+	duplicateHandler *handlers.DuplicateHandler,
+	trashHandler *handlers.TrashHandler,
+	bulkTransactionHandler *handlers.BulkTransactionHandler,
+	envelopeHandler *handlers.EnvelopeHandler,
+	budgetTemplateHandler *handlers.BudgetTemplateHandler,
 ) {
 	// Apply global middleware
 	router.Use(middleware.CORSMiddleware(cfg.CORS.Origins))
@@ -58,9 +70,14 @@ func SetupRoutes(
 			transactions.GET("", transactionHandler.ListTransactions)
 			transactions.POST("", transactionHandler.CreateTransaction)
 			transactions.GET("/stats", transactionHandler.GetTransactionStats)
+			transactions.GET("/duplicates", duplicateHandler.ListDuplicates)
+			transactions.POST("/bulk", bulkTransactionHandler.BulkUpdateTransactions)
 			transactions.GET("/:id", transactionHandler.GetTransaction)
 			transactions.PUT("/:id", transactionHandler.UpdateTransaction)
 			transactions.DELETE("/:id", transactionHandler.DeleteTransaction)
+			transactions.GET("/:id/receipts", receiptHandler.ListTransactionReceipts)
+			transactions.POST("/:id/receipts", receiptHandler.UploadReceipt)
+			transactions.POST("/:id/merge", duplicateHandler.MergeTransactions)
 		}
 
 		// Goal routes
@@ -80,9 +97,34 @@ func SetupRoutes(
 			budgets.GET("", budgetHandler.ListBudgets)
 			budgets.POST("", budgetHandler.CreateBudget)
 			budgets.GET("/summary", budgetHandler.GetBudgetSummary)
+			budgets.GET("/status", budgetHandler.GetBudgetStatuses)
+			budgets.GET("/history", budgetHandler.GetBudgetHistory)
+			budgets.POST("/rescale/preview", budgetTemplateHandler.PreviewRescale)
+			budgets.POST("/rescale", budgetTemplateHandler.RescaleBudgets)
 			budgets.GET("/:id", budgetHandler.GetBudget)
 			budgets.PUT("/:id", budgetHandler.UpdateBudget)
 			budgets.DELETE("/:id", budgetHandler.DeleteBudget)
+			budgets.GET("/:id/status", budgetHandler.GetBudgetStatus)
+			budgets.GET("/:id/ledger", budgetHandler.GetBudgetLedger)
+		}
+
+		// Budget template routes
+		budgetTemplates := protected.Group("/budget-templates")
+		{
+			budgetTemplates.GET("", budgetTemplateHandler.ListBudgetTemplates)
+			budgetTemplates.POST("", budgetTemplateHandler.CreateBudgetTemplate)
+			budgetTemplates.POST("/preview", budgetTemplateHandler.PreviewBudgets)
+			budgetTemplates.POST("/generate", budgetTemplateHandler.GenerateBudgets)
+			budgetTemplates.DELETE("/:id", budgetTemplateHandler.DeleteBudgetTemplate)
+		}
+
+		// Envelope budgeting routes
+		envelopes := protected.Group("/envelopes")
+		{
+			envelopes.GET("", envelopeHandler.GetEnvelopes)
+			envelopes.POST("/assign", envelopeHandler.AssignEnvelope)
+			envelopes.POST("/move", envelopeHandler.MoveEnvelope)
+			envelopes.POST("/cover", envelopeHandler.CoverEnvelope)
 		}
 
 		// Wallet routes
@@ -90,6 +132,10 @@ func SetupRoutes(
 		{
 			wallets.GET("", walletHandler.ListWallets)
 			wallets.POST("", walletHandler.CreateWallet)
+			wallets.GET("/transfers", walletHandler.ListTransfers)
+			wallets.POST("/transfers", walletHandler.CreateTransfer)
+			wallets.GET("/transfers/:id", walletHandler.GetTransfer)
+			wallets.POST("/transfers/:id/reverse", walletHandler.ReverseTransfer)
 			wallets.GET("/:id", walletHandler.GetWallet)
 			wallets.PUT("/:id", walletHandler.UpdateWallet)
 			wallets.DELETE("/:id", walletHandler.DeleteWallet)
@@ -101,9 +147,90 @@ func SetupRoutes(
 			analytics.GET("/dashboard", analyticsHandler.GetDashboardStats)
 			analytics.GET("/money-flow", analyticsHandler.GetMoneyFlow)
 			analytics.GET("/spending", analyticsHandler.GetSpendingAnalysis)
+			analytics.GET("/tags", analyticsHandler.GetTagSpending)
 			analytics.GET("/insights", analyticsHandler.GetInsights)
 			analytics.GET("/trends", analyticsHandler.GetTrends)
 			analytics.GET("/health", analyticsHandler.GetFinancialHealth)
 		}
+
+		// Exchange rate routes
+		exchangeRates := protected.Group("/exchange-rates")
+		{
+			exchangeRates.GET("", exchangeRateHandler.ListExchangeRates)
+			exchangeRates.GET("/convert", exchangeRateHandler.ConvertCurrency)
+		}
+
+		// Statement import routes
+		importRoutes := protected.Group("/imports")
+		{
+			importRoutes.GET("", importHandler.ListImports)
+			importRoutes.POST("", importHandler.PreviewImport)
+			importRoutes.POST("/mpesa", importHandler.ImportMpesaMessages)
+			importRoutes.GET("/:id", importHandler.GetImport)
+			importRoutes.POST("/:id/commit", importHandler.CommitImport)
+			importRoutes.POST("/:id/undo", importHandler.UndoImport)
+		}
+
+		// Recurring transaction routes
+		recurring := protected.Group("/recurring")
+		{
+			recurring.GET("", recurringHandler.ListRecurring)
+			recurring.POST("", recurringHandler.CreateRecurring)
+			recurring.GET("/upcoming", recurringHandler.GetUpcoming)
+			recurring.GET("/:id", recurringHandler.GetRecurring)
+			recurring.PUT("/:id", recurringHandler.UpdateRecurring)
+			recurring.DELETE("/:id", recurringHandler.DeleteRecurring)
+			recurring.GET("/:id/occurrences", recurringHandler.PreviewOccurrences)
+		}
+
+		// Category routes
+		categories := protected.Group("/categories")
+		{
+			categories.GET("", categoryHandler.ListCategories)
+			categories.POST("", categoryHandler.CreateCategory)
+			categories.POST("/sync", categoryHandler.SyncCategories)
+			categories.GET("/:id", categoryHandler.GetCategory)
+			categories.PUT("/:id", categoryHandler.UpdateCategory)
+			categories.DELETE("/:id", categoryHandler.DeleteCategory)
+			categories.POST("/:id/merge", categoryHandler.MergeCategory)
+		}
+
+		// Rule routes
+		rules := protected.Group("/rules")
+		{
+			rules.GET("", ruleHandler.ListRules)
+			rules.POST("", ruleHandler.CreateRule)
+			rules.POST("/apply", ruleHandler.ApplyRules)
+			rules.GET("/:id", ruleHandler.GetRule)
+			rules.PUT("/:id", ruleHandler.UpdateRule)
+			rules.DELETE("/:id", ruleHandler.DeleteRule)
+		}
+
+		// Tag routes
+		tags := protected.Group("/tags")
+		{
+			tags.GET("", tagHandler.ListTags)
+			tags.POST("", tagHandler.CreateTag)
+			tags.GET("/:id", tagHandler.GetTag)
+			tags.PUT("/:id", tagHandler.UpdateTag)
+			tags.DELETE("/:id", tagHandler.DeleteTag)
+		}
+
+		// Receipt routes
+		receipts := protected.Group("/receipts")
+		{
+			receipts.GET("/:id", receiptHandler.GetReceipt)
+			receipts.GET("/:id/download", receiptHandler.DownloadReceipt)
+			receipts.GET("/:id/thumbnail", receiptHandler.DownloadThumbnail)
+			receipts.DELETE("/:id", receiptHandler.DeleteReceipt)
+		}
+
+		// Trash routes
+		trash := protected.Group("/trash")
+		{
+			trash.GET("", trashHandler.ListTrash)
+			trash.POST("/:type/:id/restore", trashHandler.RestoreItem)
+			trash.DELETE("/:type/:id", trashHandler.PurgeItem)
+		}
 	}
 }
@@ -9,10 +9,13 @@ import (
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	JWT      JWTConfig
-	CORS     CORSConfig
+	Server    ServerConfig
+	Database  DatabaseConfig
+	JWT       JWTConfig
+	CORS      CORSConfig
+	Scheduler SchedulerConfig
+	Storage   StorageConfig
+	Trash     TrashConfig
 }
 
 type ServerConfig struct {
@@ -38,6 +41,18 @@ type CORSConfig struct {
 	Origins []string
 }
 
+type SchedulerConfig struct {
+	Interval string
+}
+
+type StorageConfig struct {
+	Path string
+}
+
+type TrashConfig struct {
+	RetentionDays string
+}
+
 func Load() *Config {
 	if err := godotenv.Load(); err != nil {
 		if err := godotenv.Load("backend/.env"); err != nil {
@@ -69,6 +84,15 @@ func Load() *Config {
 		CORS: CORSConfig{
 			Origins: strings.Split(getEnv("CORS_ORIGINS", "http://localhost:5173"), ","),
 		},
+		Scheduler: SchedulerConfig{
+			Interval: getEnv("SCHEDULER_INTERVAL", "1m"),
+		},
+		Storage: StorageConfig{
+			Path: getEnv("STORAGE_PATH", "uploads"),
+		},
+		Trash: TrashConfig{
+			RetentionDays: getEnv("TRASH_RETENTION_DAYS", "30"),
+		},
 	}
 }
 
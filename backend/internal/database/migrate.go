@@ -0,0 +1,181 @@
+package database
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// schemaMigration records a data migration that has already been applied
+type schemaMigration struct {
+	Version   string    `gorm:"type:varchar(100);primary_key"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// TableName specifies the table name for the schemaMigration model
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// dataMigration is a one-off data change that runs after the schema has been
+// auto-migrated. Each migration runs exactly once, inside its own transaction.
+type dataMigration struct {
+	Version string
+	Up      func(tx *gorm.DB) error
+}
+
+// dataMigrations lists all data migrations in the order they must be applied
+var dataMigrations = []dataMigration{
+	{Version: "20261016_01_backfill_transaction_type", Up: backfillTransactionType},
+	{Version: "20261016_02_backfill_transfer_currencies", Up: backfillTransferCurrencies},
+	{Version: "20261016_03_backfill_categories", Up: backfillCategories},
+	{Version: "20261016_04_backfill_user_currency", Up: backfillUserCurrency},
+}
+
+// Models returns every model managed by auto-migration
+func Models() []interface{} {
+	return []interface{}{
+		&models.User{},
+		&models.Wallet{},
+		&models.Transaction{},
+		&models.TransactionSplit{},
+		&models.SavingGoal{},
+		&models.Budget{},
+		&models.BudgetLimit{},
+		&models.BudgetLedgerEntry{},
+		&models.EnvelopeAllocation{},
+		&models.BudgetTemplate{},
+		&models.BudgetTemplateLine{},
+		&models.Transfer{},
+		&models.ExchangeRate{},
+		&models.ImportJob{},
+		&models.ImportRow{},
+		&models.RecurringTransaction{},
+		&models.Category{},
+		&models.Tag{},
+		&models.Rule{},
+		&models.Receipt{},
+	}
+}
+
+// Migrate brings the schema up to date and applies pending data migrations
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(Models()...); err != nil {
+		return err
+	}
+
+	if err := db.AutoMigrate(&schemaMigration{}); err != nil {
+		return err
+	}
+
+	for _, m := range dataMigrations {
+		var count int64
+		if err := db.Model(&schemaMigration{}).Where("version = ?", m.Version).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+
+		log.Printf("Applying data migration %s...", m.Version)
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&schemaMigration{Version: m.Version, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("data migration %s failed: %w", m.Version, err)
+		}
+	}
+
+	return nil
+}
+
+// backfillTransactionType derives the type of transactions created before the
+// column existed. Older rows used signed amounts (negative for spending) and an
+// "Income" category for money coming in; amounts are normalised to positive values.
+func backfillTransactionType(tx *gorm.DB) error {
+	if err := tx.Exec(`
+		UPDATE transactions
+		SET type = 'income'
+		WHERE amount > 0 AND LOWER(category) IN ('income', 'salary')
+	`).Error; err != nil {
+		return err
+	}
+
+	return tx.Exec(`
+		UPDATE transactions
+		SET type = 'expense', amount = ABS(amount)
+		WHERE amount < 0
+	`).Error
+}
+
+// backfillTransferCurrencies fills the currency columns of transfers made before
+// cross-currency transfers were supported. Those transfers always moved money
+// between wallets in the same currency, so the credited amount equals the
+// debited one at a rate of one.
+func backfillTransferCurrencies(tx *gorm.DB) error {
+	return tx.Exec(`
+		UPDATE transfers t
+		SET from_currency = w.currency, to_currency = w.currency, to_amount = t.amount, exchange_rate = 1
+		FROM wallets w
+		WHERE w.id = t.from_wallet_id AND (t.to_currency IS NULL OR t.to_currency = '')
+	`).Error
+}
+
+// backfillCategories creates categories for the category names users gave
+// their transactions and budgets before categories were managed. Names that
+// differ only in case become one category; a name is an income category when
+// only income uses it.
+func backfillCategories(tx *gorm.DB) error {
+	return tx.Exec(`
+		INSERT INTO categories (id, user_id, name, kind, created_at, updated_at)
+		SELECT gen_random_uuid(), user_id, MIN(name), MIN(kind), NOW(), NOW()
+		FROM (
+			SELECT user_id, category AS name, CASE WHEN type = 'income' THEN 'income' ELSE 'expense' END AS kind
+			FROM transactions
+			WHERE type <> 'transfer' AND deleted_at IS NULL
+			UNION ALL
+			SELECT t.user_id, s.category, CASE WHEN t.type = 'income' THEN 'income' ELSE 'expense' END
+			FROM transaction_splits s JOIN transactions t ON t.id = s.transaction_id
+			WHERE t.deleted_at IS NULL
+			UNION ALL
+			SELECT user_id, category, 'expense'
+			FROM budgets
+			WHERE deleted_at IS NULL
+		) used
+		WHERE name <> ''
+		GROUP BY user_id, LOWER(name)
+		ON CONFLICT DO NOTHING
+	`).Error
+}
+
+// backfillUserCurrency sets the base currency of users who never chose one to
+// the currency of their default wallet, or of their oldest wallet without a
+// default. Users used to default to USD, so USD only counts as chosen when the
+// user holds a USD wallet.
+func backfillUserCurrency(tx *gorm.DB) error {
+	return tx.Exec(`
+		UPDATE users u
+		SET currency = UPPER(w.currency)
+		FROM (
+			SELECT DISTINCT ON (user_id) user_id, currency
+			FROM wallets
+			WHERE deleted_at IS NULL AND currency <> ''
+			ORDER BY user_id, is_default DESC, created_at ASC
+		) w
+		WHERE w.user_id = u.id
+			AND UPPER(w.currency) <> COALESCE(u.currency, '')
+			AND (
+				u.currency IS NULL OR u.currency = ''
+				OR (u.currency = 'USD' AND NOT EXISTS (
+					SELECT 1 FROM wallets usd
+					WHERE usd.user_id = u.id AND usd.deleted_at IS NULL AND UPPER(usd.currency) = 'USD'
+				))
+			)
+	`).Error
+}
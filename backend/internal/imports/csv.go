@@ -0,0 +1,338 @@
+package imports
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// MaxRecords bounds the number of lines read from one statement
+const MaxRecords = 5000
+
+// headerSearchLines is how many leading lines may precede the header row.
+// Statements such as M-PESA exports start with a summary block.
+const headerSearchLines = 30
+
+// Mapping names the CSV columns holding each field, matched case-insensitively
+// against the header row. A statement either has a single signed Amount
+// column or separate Debit and Credit columns.
+type Mapping struct {
+	Date        string `json:"date"`
+	Description string `json:"description"`
+	Amount      string `json:"amount,omitempty"`
+	Debit       string `json:"debit,omitempty"`
+	Credit      string `json:"credit,omitempty"`
+	Balance     string `json:"balance,omitempty"`
+	Reference   string `json:"reference,omitempty"`
+	Category    string `json:"category,omitempty"`
+	// DateFormat such as "DD/MM/YYYY" or "YYYY-MM-DD HH:mm:ss". Common
+	// formats are recognised when it is empty.
+	DateFormat string `json:"date_format,omitempty"`
+}
+
+// Validate checks that the mapping names the columns a record needs
+func (m Mapping) Validate() error {
+	if m.Date == "" || m.Description == "" {
+		return fmt.Errorf("%w: date and description columns are required", ErrInvalidMapping)
+	}
+	if m.Amount == "" && m.Debit == "" && m.Credit == "" {
+		return fmt.Errorf("%w: an amount column or debit and credit columns are required", ErrInvalidMapping)
+	}
+	return nil
+}
+
+// columnAliases lists the header names each field is recognised by, covering
+// the exports of Equity, KCB, Co-op, M-PESA and most other banks
+var columnAliases = map[string][]string{
+	"date":        {"date", "transaction date", "trans date", "txn date", "completion time", "posting date", "booking date", "value date", "tran date"},
+	"description": {"description", "details", "narrative", "narration", "transaction details", "particulars", "memo", "payee", "transaction description"},
+	"amount":      {"amount", "transaction amount", "amount (kes)", "value"},
+	"debit":       {"debit", "debits", "withdrawn", "withdrawal", "withdrawals", "money out", "paid out", "debit amount", "dr"},
+	"credit":      {"credit", "credits", "paid in", "deposit", "deposits", "money in", "credit amount", "cr"},
+	"balance":     {"balance", "running balance", "closing balance", "book balance", "available balance", "ledger balance"},
+	"reference":   {"reference", "receipt no.", "receipt no", "receipt", "ref", "ref no", "ref. no.", "transaction id", "transaction reference", "bank reference", "cheque no"},
+	"category":    {"category"},
+}
+
+// DetectMapping guesses the mapping for a header row. It reports false when
+// the header lacks a date, a description or any amount column.
+func DetectMapping(header []string) (Mapping, bool) {
+	found := make(map[string]string)
+	for _, cell := range header {
+		name := normaliseHeader(cell)
+		for field, aliases := range columnAliases {
+			if _, taken := found[field]; taken {
+				continue
+			}
+			for _, alias := range aliases {
+				if name == alias {
+					found[field] = strings.TrimSpace(cell)
+					break
+				}
+			}
+		}
+	}
+
+	mapping := Mapping{
+		Date:        found["date"],
+		Description: found["description"],
+		Amount:      found["amount"],
+		Debit:       found["debit"],
+		Credit:      found["credit"],
+		Balance:     found["balance"],
+		Reference:   found["reference"],
+		Category:    found["category"],
+	}
+	// Separate debit and credit columns are more reliable than a guessed amount
+	if mapping.Debit != "" || mapping.Credit != "" {
+		mapping.Amount = ""
+	}
+	return mapping, mapping.Validate() == nil
+}
+
+// CSVResult is the outcome of reading a CSV statement
+type CSVResult struct {
+	Mapping Mapping
+	Records []*Record
+}
+
+// ParseCSV reads a CSV statement. With a nil mapping the header row and its
+// columns are detected; otherwise the header is the first row containing the
+// mapped date column. Comma, semicolon and tab delimiters are recognised.
+// Lines that cannot be read are returned with their Error set.
+func ParseCSV(r io.Reader, mapping *Mapping) (*CSVResult, error) {
+	if mapping != nil {
+		if err := mapping.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := readText(r)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(strings.NewReader(data))
+	reader.Comma = detectDelimiter(data)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	// Find the header row, skipping any preamble
+	var header []string
+	var columns map[string]int
+	var detected Mapping
+	for line := 1; header == nil; line++ {
+		row, err := reader.Read()
+		if err == io.EOF || line > headerSearchLines {
+			if mapping != nil {
+				return nil, fmt.Errorf("%w: no header row with a %q column", ErrInvalidMapping, mapping.Date)
+			}
+			return nil, fmt.Errorf("%w: could not find a header row with date, description and amount columns", ErrInvalidFile)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+		}
+
+		if mapping != nil {
+			columns = indexColumns(row)
+			if _, ok := columns[normaliseHeader(mapping.Date)]; ok {
+				header, detected = row, *mapping
+			}
+		} else if m, ok := DetectMapping(row); ok {
+			header, detected, columns = row, m, indexColumns(row)
+		}
+	}
+
+	fields, err := resolveColumns(detected, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CSVResult{Mapping: detected}
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+		}
+		if isBlank(row) {
+			continue
+		}
+		if len(result.Records) == MaxRecords {
+			return nil, fmt.Errorf("%w: more than %d lines", ErrInvalidFile, MaxRecords)
+		}
+
+		line, _ := reader.FieldPos(0)
+		record := fields.read(row, detected.DateFormat)
+		record.Line = line
+		result.Records = append(result.Records, record)
+	}
+
+	if len(result.Records) == 0 {
+		return nil, ErrNoRecords
+	}
+	return result, nil
+}
+
+// csvFields holds the column index of each mapped field, or -1 when unmapped
+type csvFields struct {
+	date, description, amount, debit, credit, balance, reference, category int
+}
+
+// resolveColumns finds the index of every mapped column in the header
+func resolveColumns(mapping Mapping, columns map[string]int) (csvFields, error) {
+	fields := csvFields{}
+	targets := []struct {
+		name  string
+		index *int
+	}{
+		{mapping.Date, &fields.date},
+		{mapping.Description, &fields.description},
+		{mapping.Amount, &fields.amount},
+		{mapping.Debit, &fields.debit},
+		{mapping.Credit, &fields.credit},
+		{mapping.Balance, &fields.balance},
+		{mapping.Reference, &fields.reference},
+		{mapping.Category, &fields.category},
+	}
+	for _, target := range targets {
+		*target.index = -1
+		if target.name == "" {
+			continue
+		}
+		index, ok := columns[normaliseHeader(target.name)]
+		if !ok {
+			return fields, fmt.Errorf("%w: no column named %q", ErrInvalidMapping, target.name)
+		}
+		*target.index = index
+	}
+	return fields, nil
+}
+
+// read turns one CSV row into a record
+func (f csvFields) read(row []string, dateFormat string) *Record {
+	record := &Record{
+		Description: strings.Join(strings.Fields(cell(row, f.description)), " "),
+		ExternalID:  cell(row, f.reference),
+		Category:    cell(row, f.category),
+	}
+
+	date, err := parseDate(cell(row, f.date), dateFormat)
+	if err != nil {
+		record.Error = err.Error()
+		return record
+	}
+	record.Date = date
+
+	if f.amount >= 0 {
+		amount, err := parseAmount(cell(row, f.amount))
+		if err != nil {
+			record.Error = "invalid amount " + quote(cell(row, f.amount))
+			return record
+		}
+		record.setSigned(amount)
+	} else {
+		// Debits and credits are usually both positive, but some exports
+		// sign withdrawals; either way a debit is money out
+		debit, credit := cell(row, f.debit), cell(row, f.credit)
+		switch {
+		case isNonZero(debit):
+			amount, err := parseAmount(debit)
+			if err != nil {
+				record.Error = "invalid debit " + quote(debit)
+				return record
+			}
+			record.setSigned(-amount.Abs())
+		case isNonZero(credit):
+			amount, err := parseAmount(credit)
+			if err != nil {
+				record.Error = "invalid credit " + quote(credit)
+				return record
+			}
+			record.setSigned(amount.Abs())
+		}
+	}
+	if record.Amount == 0 {
+		record.Error = "missing amount"
+		return record
+	}
+
+	if value := cell(row, f.balance); value != "" {
+		if balance, err := parseAmount(value); err == nil {
+			record.Balance = &balance
+		}
+	}
+	if record.Description == "" {
+		record.Error = "missing description"
+	}
+	return record
+}
+
+// detectDelimiter picks the most common of comma, semicolon and tab in the
+// first lines of the file
+func detectDelimiter(data string) rune {
+	counts := map[rune]int{',': 0, ';': 0, '\t': 0}
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for lines := 0; scanner.Scan() && lines < headerSearchLines; lines++ {
+		for delimiter := range counts {
+			counts[delimiter] += strings.Count(scanner.Text(), string(delimiter))
+		}
+	}
+
+	best := ','
+	for _, delimiter := range []rune{';', '\t'} {
+		if counts[delimiter] > counts[best] {
+			best = delimiter
+		}
+	}
+	return best
+}
+
+// indexColumns maps each normalised header name to its column
+func indexColumns(header []string) map[string]int {
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		key := normaliseHeader(name)
+		if _, exists := columns[key]; !exists {
+			columns[key] = i
+		}
+	}
+	return columns
+}
+
+// normaliseHeader lowercases a header and collapses its whitespace
+func normaliseHeader(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// cell returns the trimmed value of a column, or "" when it is unmapped or missing
+func cell(row []string, index int) string {
+	if index < 0 || index >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[index])
+}
+
+// isBlank reports whether every cell of a row is empty
+func isBlank(row []string) bool {
+	for _, value := range row {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// isNonZero reports whether a debit or credit cell holds a non-zero amount
+func isNonZero(value string) bool {
+	if value == "" {
+		return false
+	}
+	amount, err := parseAmount(value)
+	return err != nil || amount != 0
+}
@@ -0,0 +1,352 @@
+package imports
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+)
+
+// Categories given to M-PESA transactions whose kind implies one
+const (
+	mpesaFeeCategory        = "Fees"
+	mpesaAirtimeCategory    = "Airtime"
+	mpesaWithdrawalCategory = "Cash Withdrawal"
+	mpesaDepositCategory    = "Cash Deposit"
+)
+
+// MpesaFeeSuffix is appended to a transaction code to identify the
+// transaction cost charged with it
+const MpesaFeeSuffix = "-FEE"
+
+var (
+	// mpesaMessageStart finds the code that opens each confirmation SMS
+	mpesaMessageStart = regexp.MustCompile(`\b([A-Z0-9]{10})\s*[Cc]onfirmed\b\.?`)
+	// mpesaStatementRow finds the rows of a statement converted to text
+	mpesaStatementRow = regexp.MustCompile(`^\s*([A-Z0-9]{10})\s+(\d{4}-\d{2}-\d{2}\s+\d{2}:\d{2}:\d{2})\s+(.*)$`)
+
+	mpesaBalance = regexp.MustCompile(`(?i)balance is ` + mpesaAmount)
+	mpesaFee     = regexp.MustCompile(`(?i)transaction cost,? ` + mpesaAmount)
+	// mpesaPhone matches a trailing phone number, masked or not
+	mpesaPhone = regexp.MustCompile(`\s*-?\s*(?:\+?254|0)[\d*xX]{8,9}$`)
+	// mpesaStatementAmount matches a statement amount column such as "-1,500.00"
+	mpesaStatementAmount = regexp.MustCompile(`^-?[\d,]+\.\d{2}$`)
+)
+
+const (
+	mpesaAmount = `(?:Ksh|KES)\s?(?P<amount>[\d,]+(?:\.\d{1,2})?)`
+	mpesaWhen   = `on (?P<date>\d{1,2}/\d{1,2}/\d{2,4}) at (?P<time>\d{1,2}:\d{2}\s?[AP]M)`
+)
+
+// mpesaKind describes one kind of confirmation SMS
+type mpesaKind struct {
+	pattern  *regexp.Regexp
+	income   bool
+	category string
+	describe func(party, account string) string
+}
+
+// mpesaKinds are tried in order; the first whose pattern matches a message wins
+var mpesaKinds = []mpesaKind{
+	{
+		pattern:  regexp.MustCompile(`(?i)received ` + mpesaAmount + ` from (?P<party>.+?) ` + mpesaWhen),
+		income:   true,
+		describe: func(party, _ string) string { return "Received from " + party },
+	},
+	{
+		// Pay Bill
+		pattern:  regexp.MustCompile(`(?i)` + mpesaAmount + ` sent to (?P<party>.+?) for account (?P<account>.+?) ` + mpesaWhen),
+		describe: func(party, account string) string { return "Paid " + party + " account " + account },
+	},
+	{
+		pattern:  regexp.MustCompile(`(?i)` + mpesaAmount + ` sent to (?P<party>.+?) ` + mpesaWhen),
+		describe: func(party, _ string) string { return "Sent to " + party },
+	},
+	{
+		// Buy Goods till
+		pattern:  regexp.MustCompile(`(?i)` + mpesaAmount + ` paid to (?P<party>.+?)\.? ` + mpesaWhen),
+		describe: func(party, _ string) string { return "Paid " + party },
+	},
+	{
+		pattern:  regexp.MustCompile(`(?i)` + mpesaWhen + `\s?Withdraw ` + mpesaAmount + ` from (?P<party>.+?)\s?New M-PESA balance`),
+		category: mpesaWithdrawalCategory,
+		describe: func(party, _ string) string { return "Withdrawal at " + party },
+	},
+	{
+		pattern:  regexp.MustCompile(`(?i)bought ` + mpesaAmount + ` of airtime(?: for (?P<party>\S+))? ` + mpesaWhen),
+		category: mpesaAirtimeCategory,
+		describe: func(party, _ string) string {
+			if party == "" {
+				return "Airtime"
+			}
+			return "Airtime for " + party
+		},
+	},
+	{
+		pattern:  regexp.MustCompile(`(?i)` + mpesaWhen + `\s?Give ` + mpesaAmount + ` cash to (?P<party>.+?)\s?New M-PESA balance`),
+		income:   true,
+		category: mpesaDepositCategory,
+		describe: func(party, _ string) string { return "Deposit at " + party },
+	},
+}
+
+// ParseMpesa reads M-PESA confirmation SMS messages, pasted one after
+// another, and the rows of M-PESA statements converted to text. Each
+// transaction's code becomes its ExternalID, and a transaction cost becomes
+// a separate expense whose ExternalID is the code followed by MpesaFeeSuffix.
+// Messages repeated in the text are read once.
+func ParseMpesa(r io.Reader) ([]*Record, error) {
+	text, err := readText(r)
+	if err != nil {
+		return nil, err
+	}
+
+	records, consumed := parseMpesaStatement(text)
+	records = append(records, parseMpesaMessages(text, consumed)...)
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Line < records[j].Line
+	})
+
+	seen := make(map[string]bool)
+	unique := records[:0]
+	for _, record := range records {
+		if record.ExternalID != "" {
+			if seen[record.ExternalID] {
+				continue
+			}
+			seen[record.ExternalID] = true
+		}
+		unique = append(unique, record)
+	}
+
+	if len(unique) == 0 {
+		return nil, ErrNoRecords
+	}
+	if len(unique) > MaxRecords {
+		return nil, fmt.Errorf("%w: more than %d transactions", ErrInvalidFile, MaxRecords)
+	}
+	return unique, nil
+}
+
+// parseMpesaMessages reads the confirmation SMS messages in text, skipping
+// the lines already read as statement rows
+func parseMpesaMessages(text string, consumed map[int]bool) []*Record {
+	var records []*Record
+	matches := mpesaMessageStart.FindAllStringSubmatchIndex(text, -1)
+	line, counted := 1, 0
+	for i, match := range matches {
+		line += strings.Count(text[counted:match[0]], "\n")
+		counted = match[0]
+		if consumed[line] {
+			continue
+		}
+		end := len(text)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+		code := text[match[2]:match[3]]
+		message := strings.Join(strings.Fields(text[match[1]:end]), " ")
+		records = append(records, mpesaMessage(line, code, message)...)
+	}
+	return records
+}
+
+// mpesaMessage reads one confirmation SMS into a record and, when it
+// charged a transaction cost, a record for the fee
+func mpesaMessage(line int, code, message string) []*Record {
+	record := &Record{Line: line, ExternalID: code}
+
+	var kind *mpesaKind
+	var match []string
+	for i := range mpesaKinds {
+		if match = mpesaKinds[i].pattern.FindStringSubmatch(message); match != nil {
+			kind = &mpesaKinds[i]
+			break
+		}
+	}
+	if kind == nil {
+		record.Error = "unrecognised M-PESA message " + quote(message)
+		return []*Record{record}
+	}
+	group := func(name string) string {
+		if i := kind.pattern.SubexpIndex(name); i >= 0 {
+			return strings.TrimSpace(match[i])
+		}
+		return ""
+	}
+
+	party := mpesaPhone.ReplaceAllString(strings.TrimSuffix(group("party"), "."), "")
+	record.Description = kind.describe(party, group("account"))
+	record.Category = kind.category
+
+	date, err := parseMpesaTime(group("date"), group("time"))
+	if err != nil {
+		record.Error = err.Error()
+		return []*Record{record}
+	}
+	record.Date = date
+
+	amount, err := parseAmount(group("amount"))
+	if err != nil || amount == 0 {
+		record.Error = "invalid amount " + quote(group("amount"))
+		return []*Record{record}
+	}
+	if kind.income {
+		record.setSigned(amount)
+	} else {
+		record.setSigned(-amount)
+	}
+	if m := mpesaBalance.FindStringSubmatch(message); m != nil {
+		if balance, err := parseAmount(m[1]); err == nil {
+			record.Balance = &balance
+		}
+	}
+
+	records := []*Record{record}
+	if m := mpesaFee.FindStringSubmatch(message); m != nil {
+		if fee, err := parseAmount(m[1]); err == nil && fee > 0 {
+			records = append(records, mpesaFeeRecord(line, code, date, fee))
+		}
+	}
+	return records
+}
+
+// mpesaFeeRecord builds the expense for the transaction cost of a code
+func mpesaFeeRecord(line int, code string, date time.Time, fee money.Amount) *Record {
+	return &Record{
+		Line:        line,
+		Date:        date,
+		Description: "M-PESA transaction cost",
+		Amount:      fee,
+		Type:        models.TransactionTypeExpense,
+		Category:    mpesaFeeCategory,
+		ExternalID:  code + MpesaFeeSuffix,
+	}
+}
+
+// parseMpesaTime reads the date and time of an SMS, such as "5/3/24" and
+// "8:15 AM", day first
+func parseMpesaTime(date, clock string) (time.Time, error) {
+	clock = strings.ToUpper(strings.ReplaceAll(clock, " ", ""))
+	clock = clock[:len(clock)-2] + " " + clock[len(clock)-2:]
+
+	layout := "2/1/06 3:04 PM"
+	if parts := strings.Split(date, "/"); len(parts[len(parts)-1]) == 4 {
+		layout = "2/1/2006 3:04 PM"
+	}
+	t, err := time.Parse(layout, date+" "+clock)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unrecognised date %s", quote(date+" "+clock))
+	}
+	return t, nil
+}
+
+// parseMpesaStatement reads the rows of an M-PESA statement converted to
+// text. Details that wrap onto the following lines are joined to their row.
+// It returns the records and the lines they were read from.
+func parseMpesaStatement(text string) ([]*Record, map[int]bool) {
+	var records []*Record
+	consumed := make(map[int]bool)
+
+	lines := strings.Split(text, "\n")
+	for i := 0; i < len(lines); i++ {
+		match := mpesaStatementRow.FindStringSubmatch(lines[i])
+		if match == nil {
+			continue
+		}
+		line := i + 1
+		consumed[line] = true
+		row := match[3]
+		for i+1 < len(lines) && strings.TrimSpace(lines[i+1]) != "" &&
+			!mpesaStatementRow.MatchString(lines[i+1]) && !mpesaMessageStart.MatchString(lines[i+1]) {
+			i++
+			consumed[i+1] = true
+			row += " " + lines[i]
+		}
+		if record := mpesaStatementRecord(line, match[1], match[2], row); record != nil {
+			records = append(records, record)
+		}
+	}
+	return records, consumed
+}
+
+// mpesaStatementRecord reads the details, status, amount and balance
+// columns of a statement row. Rows without amounts, such as headers that
+// happen to match, are skipped.
+func mpesaStatementRecord(line int, code, completed, row string) *Record {
+	fields := strings.Fields(row)
+	start := -1
+	for i := 0; i+1 < len(fields); i++ {
+		if mpesaStatementAmount.MatchString(fields[i]) && mpesaStatementAmount.MatchString(fields[i+1]) {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return nil
+	}
+	end := start
+	for end < len(fields) && end-start < 3 && mpesaStatementAmount.MatchString(fields[end]) {
+		end++
+	}
+	// The last column is the balance; the others are paid in and withdrawn
+	amounts := fields[start : end-1]
+	details := append(append([]string{}, fields[:start]...), fields[end:]...)
+
+	status := ""
+	if start > 0 {
+		switch last := strings.ToLower(fields[start-1]); last {
+		case "completed", "failed", "cancelled", "reversed", "pending":
+			status = last
+			details = append(details[:start-1], details[start:]...)
+		}
+	}
+
+	record := &Record{Line: line, ExternalID: code, Description: strings.Join(details, " ")}
+	date, err := time.Parse("2006-01-02 15:04:05", strings.Join(strings.Fields(completed), " "))
+	if err != nil {
+		record.Error = "invalid date " + quote(completed)
+		return record
+	}
+	record.Date = date
+
+	var amount money.Amount
+	for _, value := range amounts {
+		if parsed, err := parseAmount(value); err == nil && parsed != 0 {
+			amount = parsed
+		}
+	}
+	if balance, err := parseAmount(fields[end-1]); err == nil {
+		record.Balance = &balance
+	}
+	record.setSigned(amount)
+
+	lower := strings.ToLower(record.Description)
+	switch {
+	case strings.Contains(lower, "charge"):
+		record.ExternalID = code + MpesaFeeSuffix
+		record.Category = mpesaFeeCategory
+		record.setSigned(-amount.Abs())
+	case strings.Contains(lower, "airtime"):
+		record.Category = mpesaAirtimeCategory
+	case strings.Contains(lower, "withdrawal"):
+		record.Category = mpesaWithdrawalCategory
+	case strings.Contains(lower, "deposit"):
+		record.Category = mpesaDepositCategory
+	}
+
+	switch {
+	case status != "" && status != "completed":
+		record.Error = "transaction " + status
+	case record.Amount == 0:
+		record.Error = "missing amount"
+	case record.Description == "":
+		record.Error = "missing description"
+	}
+	return record
+}
@@ -0,0 +1,188 @@
+package imports
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+)
+
+// ParseOFX reads the transactions of an OFX or QFX statement, in either the
+// SGML flavour (OFX 1.x, where leaf elements are not closed) or the XML
+// flavour (OFX 2.x). Every STMTTRN aggregate becomes a record whose
+// ExternalID is its FITID, so importing the same file twice is detected.
+func ParseOFX(r io.Reader) ([]*Record, error) {
+	text, err := readText(r)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.Contains(strings.ToUpper(text), "<OFX>") {
+		return nil, fmt.Errorf("%w: no <OFX> element", ErrInvalidFile)
+	}
+
+	var records []*Record
+	var current *ofxTransaction
+	flush := func() error {
+		if current == nil {
+			return nil
+		}
+		if len(records) == MaxRecords {
+			return fmt.Errorf("%w: more than %d transactions", ErrInvalidFile, MaxRecords)
+		}
+		records = append(records, current.record())
+		current = nil
+		return nil
+	}
+
+	line, counted := 1, 0
+	for pos := 0; ; {
+		start := strings.IndexByte(text[pos:], '<')
+		if start < 0 {
+			break
+		}
+		start += pos
+		end := strings.IndexByte(text[start:], '>')
+		if end < 0 {
+			break
+		}
+		end += start
+		line += strings.Count(text[counted:start], "\n")
+		counted = start
+		pos = end + 1
+
+		tag := strings.TrimSpace(text[start+1 : end])
+		if tag == "" || tag[0] == '?' || tag[0] == '!' {
+			continue
+		}
+		closing := tag[0] == '/'
+		name := strings.ToUpper(strings.Trim(tag, "/ "))
+
+		switch {
+		case name == "STMTTRN" && !closing:
+			// SGML files occasionally omit the closing tag
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			current = &ofxTransaction{line: line}
+		case name == "STMTTRN" && closing:
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		case current != nil && !closing:
+			value := text[pos:]
+			if next := strings.IndexByte(value, '<'); next >= 0 {
+				value = value[:next]
+			}
+			current.set(name, html.UnescapeString(strings.TrimSpace(value)))
+		}
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return nil, ErrNoRecords
+	}
+	assignStableIDs(records, models.ImportSourceOFX)
+	return records, nil
+}
+
+// ofxTransaction collects the elements of one STMTTRN aggregate
+type ofxTransaction struct {
+	line                                       int
+	trnType, posted, amount, fitID, name, memo string
+}
+
+// set records the first value seen for each element of interest. The
+// payee's NAME, when given as a PAYEE aggregate, is picked up the same way.
+func (t *ofxTransaction) set(element, value string) {
+	var field *string
+	switch element {
+	case "TRNTYPE":
+		field = &t.trnType
+	case "DTPOSTED":
+		field = &t.posted
+	case "TRNAMT":
+		field = &t.amount
+	case "FITID":
+		field = &t.fitID
+	case "NAME":
+		field = &t.name
+	case "MEMO":
+		field = &t.memo
+	default:
+		return
+	}
+	if *field == "" {
+		*field = value
+	}
+}
+
+// record turns the collected elements into a statement record
+func (t *ofxTransaction) record() *Record {
+	record := &Record{
+		Line:        t.line,
+		Description: describe(t.name, t.memo),
+		ExternalID:  t.fitID,
+	}
+	if record.Description == "" {
+		record.Description = t.trnType
+	}
+
+	date, err := parseOFXDate(t.posted)
+	if err != nil {
+		record.Error = "invalid date " + quote(t.posted)
+		return record
+	}
+	record.Date = date
+
+	amount, err := parseOFXAmount(t.amount)
+	if err != nil {
+		record.Error = "invalid amount " + quote(t.amount)
+		return record
+	}
+	record.setSigned(amount)
+	if record.Amount == 0 {
+		record.Error = "missing amount"
+		return record
+	}
+	if record.Description == "" {
+		record.Error = "missing description"
+	}
+	return record
+}
+
+// ofxDateLayouts are the OFX date precisions, keyed by length
+var ofxDateLayouts = map[int]string{
+	8:  "20060102",
+	12: "200601021504",
+	14: "20060102150405",
+}
+
+// parseOFXDate reads an OFX date such as "20240301", "20240301120000" or
+// "20240301120000.000[+3:EAT]". The time zone is dropped so that, like CSV
+// dates, the result keeps the wall-clock time the bank reported.
+func parseOFXDate(value string) (time.Time, error) {
+	if i := strings.IndexAny(value, ".["); i >= 0 {
+		value = value[:i]
+	}
+	layout, ok := ofxDateLayouts[len(value)]
+	if !ok {
+		return time.Time{}, fmt.Errorf("unrecognised date %s", quote(value))
+	}
+	return time.Parse(layout, value)
+}
+
+// parseOFXAmount reads a TRNAMT, which is signed and may use a comma as the
+// decimal separator
+func parseOFXAmount(value string) (money.Amount, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "+")
+	if strings.Contains(value, ",") && !strings.Contains(value, ".") {
+		value = strings.Replace(value, ",", ".", 1)
+	}
+	return money.Parse(strings.ReplaceAll(value, ",", ""))
+}
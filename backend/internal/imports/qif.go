@@ -0,0 +1,206 @@
+package imports
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nyunja/fity-budget-backend/internal/models"
+)
+
+// qifAccountTypes are the !Type sections that hold account transactions.
+// Investment, category, class and memorised lists are skipped.
+var qifAccountTypes = map[string]bool{
+	"bank":  true,
+	"cash":  true,
+	"ccard": true,
+	"oth a": true,
+	"oth l": true,
+}
+
+// ParseQIF reads the transactions of a QIF file. QIF has no transaction IDs,
+// so every record is given a stable ID derived from its contents. Dates are
+// read month-first, as Quicken writes them, unless some date in the file can
+// only be day-first.
+func ParseQIF(r io.Reader) ([]*Record, error) {
+	text, err := readText(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*qifEntry
+	var current *qifEntry
+	sawType, inAccount := false, false
+	for number, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		if line[0] == '!' {
+			header := strings.ToLower(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(header, "!type:"):
+				sawType = true
+				inAccount = qifAccountTypes[strings.TrimSpace(header[len("!type:"):])]
+			case strings.HasPrefix(header, "!account"):
+				// An account list precedes the transactions of each account
+				inAccount = false
+			}
+			current = nil
+			continue
+		}
+		if !inAccount {
+			continue
+		}
+
+		if current == nil {
+			if len(entries) == MaxRecords {
+				return nil, fmt.Errorf("%w: more than %d transactions", ErrInvalidFile, MaxRecords)
+			}
+			current = &qifEntry{line: number + 1}
+			entries = append(entries, current)
+		}
+		value := strings.TrimSpace(line[1:])
+		switch line[0] {
+		case '^':
+			current = nil
+		case 'D':
+			current.date = value
+		case 'T':
+			current.amount = value
+		case 'U':
+			if current.amount == "" {
+				current.amount = value
+			}
+		case 'P':
+			current.payee = value
+		case 'M':
+			current.memo = value
+		case 'L':
+			current.category = value
+		}
+	}
+
+	if !sawType {
+		return nil, fmt.Errorf("%w: no !Type header", ErrInvalidFile)
+	}
+
+	dayFirst := false
+	for _, entry := range entries {
+		if first, _, _ := qifDateParts(entry.date); first > 12 && first <= 31 {
+			dayFirst = true
+			break
+		}
+	}
+
+	records := make([]*Record, 0, len(entries))
+	for _, entry := range entries {
+		// A trailing ^ on its own opens an entry with nothing in it
+		if *entry == (qifEntry{line: entry.line}) {
+			continue
+		}
+		records = append(records, entry.record(dayFirst))
+	}
+	if len(records) == 0 {
+		return nil, ErrNoRecords
+	}
+	assignStableIDs(records, models.ImportSourceQIF)
+	return records, nil
+}
+
+// qifEntry collects the fields of one QIF transaction
+type qifEntry struct {
+	line                                int
+	date, amount, payee, memo, category string
+}
+
+// record turns the collected fields into a statement record
+func (e *qifEntry) record(dayFirst bool) *Record {
+	record := &Record{
+		Line:        e.line,
+		Description: describe(e.payee, e.memo),
+		Category:    e.category,
+	}
+	// Transfers name the other account in brackets
+	if strings.HasPrefix(e.category, "[") {
+		record.Category = "Transfer"
+	} else if i := strings.IndexAny(e.category, ":/"); i >= 0 {
+		// Keep the top-level category of "Food:Groceries" or "Food/Class"
+		record.Category = e.category[:i]
+	}
+
+	date, err := parseQIFDate(e.date, dayFirst)
+	if err != nil {
+		record.Error = err.Error()
+		return record
+	}
+	record.Date = date
+
+	amount, err := parseAmount(e.amount)
+	if err != nil {
+		record.Error = "invalid amount " + quote(e.amount)
+		return record
+	}
+	record.setSigned(amount)
+	if record.Amount == 0 {
+		record.Error = "missing amount"
+		return record
+	}
+	if record.Description == "" {
+		record.Error = "missing description"
+	}
+	return record
+}
+
+// qifDateParts splits a QIF date such as "3/ 1'24", "03/01/2024" or
+// "2024-03-01" into its numeric parts in file order
+func qifDateParts(value string) (first, second, third int) {
+	value = strings.NewReplacer(" ", "", "'", "/", "-", "/", ".", "/").Replace(value)
+	parts := strings.Split(value, "/")
+	if len(parts) != 3 {
+		return 0, 0, 0
+	}
+	numbers := make([]int, 3)
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return 0, 0, 0
+		}
+		numbers[i] = n
+	}
+	return numbers[0], numbers[1], numbers[2]
+}
+
+// parseQIFDate reads a QIF date. Two-digit years written after an
+// apostrophe are in the 2000s; otherwise years before 70 are.
+func parseQIFDate(value string, dayFirst bool) (time.Time, error) {
+	invalid := errors.New("unrecognised date " + quote(value))
+
+	first, second, third := qifDateParts(value)
+	var year, month, day int
+	switch {
+	case first > 31:
+		year, month, day = first, second, third
+	case dayFirst:
+		day, month, year = first, second, third
+	default:
+		month, day, year = first, second, third
+	}
+	if year < 100 {
+		if year < 70 || strings.Contains(value, "'") {
+			year += 2000
+		} else {
+			year += 1900
+		}
+	}
+
+	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if month < 1 || month > 12 || date.Day() != day || date.Month() != time.Month(month) {
+		return time.Time{}, invalid
+	}
+	return date, nil
+}
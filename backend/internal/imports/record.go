@@ -0,0 +1,198 @@
+// Package imports parses bank and mobile-money statement exports into
+// transaction records that can be previewed and committed to a wallet.
+package imports
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+)
+
+var (
+	// ErrNoRecords is returned when a file holds no transactions
+	ErrNoRecords = errors.New("no transactions found in file")
+	// ErrInvalidFile is returned when a file cannot be parsed in the given format
+	ErrInvalidFile = errors.New("invalid statement file")
+	// ErrInvalidMapping is returned when a column mapping is incomplete or
+	// names columns the file does not have
+	ErrInvalidMapping = errors.New("invalid column mapping")
+)
+
+// Record is one statement line. Amount is always positive; Type carries the
+// direction. Records that could not be read keep their line number and Error
+// so they can be reported in a preview.
+type Record struct {
+	Line        int
+	Date        time.Time
+	Description string
+	Amount      money.Amount
+	Type        string
+	Balance     *money.Amount
+	// ExternalID is the statement's own identifier for the line, such as a
+	// bank reference, when the file has one
+	ExternalID string
+	Category   string
+	Error      string
+}
+
+// setSigned fills in the amount and type of a record from a signed value,
+// where negative amounts are money leaving the account
+func (r *Record) setSigned(amount money.Amount) {
+	if amount < 0 {
+		r.Type = models.TransactionTypeExpense
+		r.Amount = -amount
+	} else {
+		r.Type = models.TransactionTypeIncome
+		r.Amount = amount
+	}
+}
+
+// readText reads a whole statement file, dropping any byte order mark.
+// Files that are not valid UTF-8, such as OFX files declaring CHARSET:1252,
+// are read as Latin-1.
+func readText(r io.Reader) (string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+	if utf8.Valid(data) {
+		return string(data), nil
+	}
+
+	runes := make([]rune, len(data))
+	for i, b := range data {
+		runes[i] = rune(b)
+	}
+	return string(runes), nil
+}
+
+// describe builds a description from a payee name and a memo, leaving out
+// the memo when it repeats the name
+func describe(name, memo string) string {
+	name = strings.Join(strings.Fields(name), " ")
+	memo = strings.Join(strings.Fields(memo), " ")
+	switch {
+	case name == "":
+		return memo
+	case memo == "" || strings.Contains(strings.ToLower(name), strings.ToLower(memo)):
+		return name
+	}
+	return name + " - " + memo
+}
+
+// assignStableIDs gives records that have no external ID one derived from
+// their date, amount, direction and description. Identical lines are
+// numbered in file order so they stay distinct, and reading the same file
+// again yields the same IDs.
+func assignStableIDs(records []*Record, source string) {
+	occurrences := make(map[string]int)
+	for _, record := range records {
+		if record.Error != "" || record.ExternalID != "" {
+			continue
+		}
+		key := fmt.Sprintf("%s|%s|%s|%s", record.Date.Format("2006-01-02"), record.Amount, record.Type, strings.ToLower(record.Description))
+		occurrences[key]++
+		sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%d", key, occurrences[key])))
+		record.ExternalID = source + ":" + hex.EncodeToString(sum[:12])
+	}
+}
+
+// amountReplacer strips currency markers and thousands separators from amounts
+var amountReplacer = strings.NewReplacer(",", "", " ", "", "\u00a0", "", "KES", "", "KSh", "", "Ksh", "", "USD", "", "$", "", "€", "", "£", "")
+
+// parseAmount reads a statement amount such as "1,250.00", "(80.00)", "-80",
+// "80.00 DR" or "KES 80". Debit markers and parentheses make it negative.
+func parseAmount(value string) (money.Amount, error) {
+	value = strings.TrimSpace(value)
+	negative := false
+
+	upper := strings.ToUpper(value)
+	switch {
+	case strings.HasSuffix(upper, "DR"):
+		negative = true
+		value = strings.TrimSpace(value[:len(value)-2])
+	case strings.HasSuffix(upper, "CR"):
+		value = strings.TrimSpace(value[:len(value)-2])
+	}
+	if strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")") {
+		negative = true
+		value = value[1 : len(value)-1]
+	}
+	value = amountReplacer.Replace(value)
+	if strings.HasSuffix(value, "-") {
+		negative = true
+		value = strings.TrimSuffix(value, "-")
+	}
+
+	amount, err := money.Parse(value)
+	if err != nil {
+		return 0, err
+	}
+	if negative {
+		amount = -amount.Abs()
+	}
+	return amount, nil
+}
+
+// dateLayouts are tried in order when no date format is given. Day-first
+// layouts come before month-first ones, as used by Kenyan banks.
+var dateLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"02/01/2006 15:04:05",
+	"02/01/2006 15:04",
+	"02/01/2006",
+	"2/1/2006",
+	"02-01-2006",
+	"02.01.2006",
+	"02-Jan-2006",
+	"02 Jan 2006",
+	"2 Jan 2006",
+	"02-Jan-06",
+	"Jan 2, 2006",
+	"01/02/2006",
+	"02/01/06",
+	time.RFC3339,
+}
+
+// dateFormatReplacer turns a user-facing format such as DD/MM/YYYY into a Go layout
+var dateFormatReplacer = strings.NewReplacer(
+	"YYYY", "2006", "YY", "06", "MMM", "Jan", "MM", "01", "DD", "02",
+	"HH", "15", "mm", "04", "ss", "05",
+)
+
+// parseDate reads a date with the given user-facing format, or by trying the
+// common statement layouts when format is empty
+func parseDate(value, format string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if format != "" {
+		return time.Parse(dateFormatReplacer.Replace(format), value)
+	}
+
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.New("unrecognised date " + quote(value))
+}
+
+// quote wraps a value in quotes for error messages, shortening long values
+func quote(value string) string {
+	if len(value) > 40 {
+		value = value[:40] + "..."
+	}
+	return `"` + value + `"`
+}
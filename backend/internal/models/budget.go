@@ -4,22 +4,39 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/money"
 	"gorm.io/gorm"
 )
 
+// Budget periods
+const (
+	BudgetPeriodWeekly    = "weekly"
+	BudgetPeriodMonthly   = "monthly"
+	BudgetPeriodQuarterly = "quarterly"
+	BudgetPeriodYearly    = "yearly"
+	BudgetPeriodCustom    = "custom"
+)
+
 type Budget struct {
-	ID             uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	UserID         uuid.UUID      `gorm:"type:uuid;not null;index" json:"user_id"`
-	Category       string         `gorm:"type:varchar(100);not null;index" json:"category"`
-	LimitAmount    float64        `gorm:"type:decimal(12,2);not null" json:"limit"`
-	Color          string         `gorm:"type:varchar(20);not null" json:"color"`
-	Icon           string         `gorm:"type:varchar(50)" json:"icon,omitempty"`
-	IsRollover     bool           `gorm:"default:false" json:"is_rollover"`
-	Type           string         `gorm:"type:varchar(20);default:'Variable'" json:"type"` // Fixed, Variable
-	AlertThreshold int            `gorm:"default:80" json:"alert_threshold"`               // Percentage (0-100)
-	CreatedAt      time.Time      `json:"created_at"`
-	UpdatedAt      time.Time      `json:"updated_at"`
-	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
+	ID               uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID           uuid.UUID      `gorm:"type:uuid;not null;index" json:"user_id"`
+	Category         string         `gorm:"type:varchar(100);not null;index" json:"category"`
+	LimitAmount      money.Amount   `gorm:"type:decimal(12,2);not null" json:"limit"`
+	Color            string         `gorm:"type:varchar(20);not null" json:"color"`
+	Icon             string         `gorm:"type:varchar(50)" json:"icon,omitempty"`
+	IsRollover       bool           `gorm:"default:false" json:"is_rollover"`
+	IsEnvelope       bool           `gorm:"default:false" json:"is_envelope"`                          // Funded by assigning income rather than a fixed limit
+	Type             string         `gorm:"type:varchar(20);default:'Variable'" json:"type"`           // Fixed, Variable
+	AlertThreshold   int            `gorm:"default:80" json:"alert_threshold"`                         // Percentage (0-100)
+	Period           string         `gorm:"type:varchar(20);not null;default:'monthly'" json:"period"` // weekly, monthly, quarterly, yearly, custom
+	PeriodAnchor     *time.Time     `gorm:"type:date" json:"period_anchor,omitempty"`
+	PeriodDays       int            `gorm:"default:0" json:"period_days,omitempty"` // Length of a custom period
+	NextSnapshotAt   *time.Time     `gorm:"type:date;index" json:"-"`               // When the current period closes and is snapshotted
+	SnapshotRetryAt  *time.Time     `gorm:"index" json:"-"`                         // When a failed snapshot is tried again
+	SnapshotFailures int            `gorm:"default:0" json:"-"`                     // Snapshot failures in a row
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"-"`
 
 	// Relationships
 	User User `gorm:"foreignKey:UserID" json:"user,omitempty"`
@@ -37,3 +54,79 @@ func (b *Budget) BeforeCreate(tx *gorm.DB) error {
 	}
 	return nil
 }
+
+// PeriodAt returns the budget period containing t as a half-open range of
+// UTC dates: start is the first day of the period and end the first day of
+// the next one. Periods repeat from the anchor date, so a weekly budget
+// anchored on a Monday runs Monday to Sunday and a monthly budget anchored on
+// the 25th runs from the 25th to the 24th, moved back in shorter months.
+// Without an anchor weeks start on Monday and the other periods follow the
+// calendar. A custom period repeats every PeriodDays days from its anchor.
+func (b *Budget) PeriodAt(t time.Time) (start, end time.Time) {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+
+	// 3 January 2000 was a Monday, so unanchored weeks start on Mondays
+	anchor := time.Date(2000, time.January, 3, 0, 0, 0, 0, time.UTC)
+	if b.Period != BudgetPeriodWeekly && b.Period != BudgetPeriodCustom {
+		anchor = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+	}
+	if b.PeriodAnchor != nil {
+		a := b.PeriodAnchor.UTC()
+		anchor = time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	}
+
+	var months int
+	switch b.Period {
+	case BudgetPeriodWeekly:
+		return periodOfDays(anchor, day, 7)
+	case BudgetPeriodCustom:
+		if b.PeriodDays > 0 {
+			return periodOfDays(anchor, day, b.PeriodDays)
+		}
+		months = 1
+	case BudgetPeriodQuarterly:
+		months = 3
+	case BudgetPeriodYearly:
+		months = 12
+	default:
+		months = 1
+	}
+
+	elapsed := (day.Year()-anchor.Year())*12 + int(day.Month()-anchor.Month())
+	n := floorDiv(elapsed, months)
+	start = addAnchoredMonths(anchor, n*months)
+	if start.After(day) {
+		n--
+		start = addAnchoredMonths(anchor, n*months)
+	}
+	return start, addAnchoredMonths(anchor, (n+1)*months)
+}
+
+// periodOfDays returns the period of length days, counted from anchor, that contains day
+func periodOfDays(anchor, day time.Time, days int) (time.Time, time.Time) {
+	elapsed := int(day.Sub(anchor).Hours() / 24)
+	start := anchor.AddDate(0, 0, floorDiv(elapsed, days)*days)
+	return start, start.AddDate(0, 0, days)
+}
+
+// addAnchoredMonths moves anchor by months, keeping its day of the month or
+// the last day of shorter months
+func addAnchoredMonths(anchor time.Time, months int) time.Time {
+	first := time.Date(anchor.Year(), anchor.Month()+time.Month(months), 1, 0, 0, 0, 0, time.UTC)
+	day := anchor.Day()
+	if lastDay := first.AddDate(0, 1, -1).Day(); day > lastDay {
+		day = lastDay
+	}
+	return first.AddDate(0, 0, day-1)
+}
+
+// floorDiv divides rounding towards negative infinity, so dates before the
+// anchor fall into earlier periods
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
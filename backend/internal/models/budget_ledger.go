@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"gorm.io/gorm"
+)
+
+// BudgetLedgerEntry records the final numbers of one closed budget period: the
+// limit it started from, what the previous period carried into it, what was
+// spent and what it carried on into the next period. Only rollover budgets
+// carry amounts, and overspending carries a negative amount.
+//
+// Entries are never changed once written. When the numbers of a recorded
+// period change, as they do when a transaction in it is edited, a correcting
+// entry with the next revision is appended; the latest revision holds the
+// period's current numbers and the earlier ones remain as its audit trail.
+type BudgetLedgerEntry struct {
+	ID             uuid.UUID    `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	BudgetID       uuid.UUID    `gorm:"type:uuid;not null;uniqueIndex:idx_budget_ledger_revision,priority:1" json:"budget_id"`
+	UserID         uuid.UUID    `gorm:"type:uuid;not null;index" json:"user_id"`
+	PeriodStart    time.Time    `gorm:"type:date;not null;uniqueIndex:idx_budget_ledger_revision,priority:2" json:"period_start"`
+	Revision       int          `gorm:"not null;default:1;uniqueIndex:idx_budget_ledger_revision,priority:3" json:"revision"`
+	PeriodEnd      time.Time    `gorm:"type:date;not null" json:"period_end"` // First day after the period
+	BaseLimit      money.Amount `gorm:"type:decimal(12,2);not null" json:"base_limit"`
+	CarriedIn      money.Amount `gorm:"type:decimal(12,2);not null" json:"carried_in"`
+	EffectiveLimit money.Amount `gorm:"type:decimal(12,2);not null" json:"effective_limit"`
+	SpentAmount    money.Amount `gorm:"type:decimal(12,2);not null" json:"spent_amount"`
+	CarriedOut     money.Amount `gorm:"type:decimal(12,2);not null" json:"carried_out"`
+	CreatedAt      time.Time    `json:"created_at"`
+}
+
+// TableName specifies the table name for the BudgetLedgerEntry model
+func (BudgetLedgerEntry) TableName() string {
+	return "budget_ledger_entries"
+}
+
+// BeforeCreate hook to generate UUID before creating a ledger entry
+func (e *BudgetLedgerEntry) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
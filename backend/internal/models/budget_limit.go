@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"gorm.io/gorm"
+)
+
+// BudgetLimit is one version of a budget's limit. It applies to the periods
+// starting on or after EffectiveFrom, until the next version takes over.
+type BudgetLimit struct {
+	ID            uuid.UUID    `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	BudgetID      uuid.UUID    `gorm:"type:uuid;not null;uniqueIndex:idx_budget_limits_budget_from,priority:1" json:"budget_id"`
+	EffectiveFrom time.Time    `gorm:"type:date;not null;uniqueIndex:idx_budget_limits_budget_from,priority:2" json:"effective_from"`
+	LimitAmount   money.Amount `gorm:"type:decimal(12,2);not null" json:"limit"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+}
+
+// TableName specifies the table name for the BudgetLimit model
+func (BudgetLimit) TableName() string {
+	return "budget_limits"
+}
+
+// BeforeCreate hook to generate UUID before creating a budget limit
+func (l *BudgetLimit) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// BudgetTemplate is a budget template a user saved: a share of their income
+// for each of a set of categories, used to generate budgets
+type BudgetTemplate struct {
+	ID        uuid.UUID            `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID    uuid.UUID            `gorm:"type:uuid;not null;uniqueIndex:idx_budget_templates_user_name,priority:1" json:"user_id"`
+	Name      string               `gorm:"type:varchar(100);not null;uniqueIndex:idx_budget_templates_user_name,priority:2" json:"name"`
+	Lines     []BudgetTemplateLine `gorm:"foreignKey:TemplateID" json:"lines"`
+	CreatedAt time.Time            `json:"created_at"`
+	UpdatedAt time.Time            `json:"updated_at"`
+}
+
+// TableName specifies the table name for the BudgetTemplate model
+func (BudgetTemplate) TableName() string {
+	return "budget_templates"
+}
+
+// BeforeCreate hook to generate UUID before creating a budget template
+func (t *BudgetTemplate) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
+
+// BudgetTemplateLine gives a category a percentage of the income
+type BudgetTemplateLine struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	TemplateID uuid.UUID `gorm:"type:uuid;not null;index" json:"template_id"`
+	Category   string    `gorm:"type:varchar(100);not null" json:"category"`
+	Percentage float64   `gorm:"type:decimal(5,2);not null" json:"percentage"`
+}
+
+// TableName specifies the table name for the BudgetTemplateLine model
+func (BudgetTemplateLine) TableName() string {
+	return "budget_template_lines"
+}
+
+// BeforeCreate hook to generate UUID before creating a budget template line
+func (l *BudgetTemplateLine) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Category kinds
+const (
+	CategoryKindExpense = "expense"
+	CategoryKindIncome  = "income"
+)
+
+// Category is one of a user's spending or income categories. Transactions,
+// split lines, budgets and goals refer to categories by name, so renaming or
+// merging a category rewrites those names. Categories nest one level deep: a
+// child's spending can be rolled up into its parent in budgets and reports.
+type Category struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_categories_user_name,priority:1" json:"user_id"`
+	ParentID  *uuid.UUID `gorm:"type:uuid;index" json:"parent_id,omitempty"`
+	Name      string     `gorm:"type:varchar(100);not null;uniqueIndex:idx_categories_user_name,priority:2" json:"name"`
+	Kind      string     `gorm:"type:varchar(20);not null;default:'expense'" json:"kind"` // expense, income
+	Icon      string     `gorm:"type:varchar(50)" json:"icon,omitempty"`
+	Color     string     `gorm:"type:varchar(20)" json:"color,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+
+	// Relationships
+	Children []*Category `gorm:"foreignKey:ParentID" json:"children,omitempty"`
+}
+
+// TableName specifies the table name for the Category model
+func (Category) TableName() string {
+	return "categories"
+}
+
+// BeforeCreate hook to generate UUID before creating a category
+func (c *Category) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
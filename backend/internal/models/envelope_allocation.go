@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"gorm.io/gorm"
+)
+
+// Envelope allocation kinds
+const (
+	EnvelopeAllocationAssign = "assign" // Income given to an envelope, or taken back from it
+	EnvelopeAllocationMove   = "move"   // Money moved between two envelopes
+	EnvelopeAllocationCover  = "cover"  // Money moved to cover an overspent envelope
+)
+
+// EnvelopeAllocation is money put into (positive) or taken out of (negative)
+// an envelope budget in a month. A move or cover is recorded as a pair of
+// allocations sharing a MoveID, one out of the source and one into the target.
+type EnvelopeAllocation struct {
+	ID        uuid.UUID    `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID    uuid.UUID    `gorm:"type:uuid;not null;index:idx_envelope_allocations_user_month,priority:1" json:"user_id"`
+	BudgetID  uuid.UUID    `gorm:"type:uuid;not null;index" json:"budget_id"`
+	Month     time.Time    `gorm:"type:date;not null;index:idx_envelope_allocations_user_month,priority:2" json:"month"` // First day of the month
+	Amount    money.Amount `gorm:"type:decimal(12,2);not null" json:"amount"`
+	Kind      string       `gorm:"type:varchar(20);not null" json:"kind"` // assign, move, cover
+	MoveID    *uuid.UUID   `gorm:"type:uuid;index" json:"move_id,omitempty"`
+	Note      string       `gorm:"type:varchar(255)" json:"note,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// TableName specifies the table name for the EnvelopeAllocation model
+func (EnvelopeAllocation) TableName() string {
+	return "envelope_allocations"
+}
+
+// BeforeCreate hook to generate UUID before creating an envelope allocation
+func (a *EnvelopeAllocation) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
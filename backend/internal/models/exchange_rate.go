@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"gorm.io/gorm"
+)
+
+// ExchangeRate is the price of one unit of BaseCurrency in QuoteCurrency on a
+// given day. Rates are shared reference data and are not owned by a user.
+type ExchangeRate struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	BaseCurrency  string     `gorm:"type:varchar(3);not null;uniqueIndex:idx_exchange_rates_pair_date" json:"base_currency"`
+	QuoteCurrency string     `gorm:"type:varchar(3);not null;uniqueIndex:idx_exchange_rates_pair_date" json:"quote_currency"`
+	Rate          money.Rate `gorm:"type:decimal(18,8);not null" json:"rate"`
+	RateDate      time.Time  `gorm:"type:date;not null;uniqueIndex:idx_exchange_rates_pair_date" json:"rate_date"`
+	Source        string     `gorm:"type:varchar(50)" json:"source,omitempty"` // csv, ecb
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for the ExchangeRate model
+func (ExchangeRate) TableName() string {
+	return "exchange_rates"
+}
+
+// BeforeCreate hook to generate UUID before creating an exchange rate
+func (r *ExchangeRate) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
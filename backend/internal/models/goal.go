@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/money"
 	"gorm.io/gorm"
 )
 
@@ -11,8 +12,8 @@ type SavingGoal struct {
 	ID            uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
 	UserID        uuid.UUID      `gorm:"type:uuid;not null;index" json:"user_id"`
 	Name          string         `gorm:"type:varchar(255);not null" json:"name"`
-	TargetAmount  float64        `gorm:"type:decimal(12,2);not null" json:"target"`
-	CurrentAmount float64        `gorm:"type:decimal(12,2);default:0.00" json:"current_amount"`
+	TargetAmount  money.Amount   `gorm:"type:decimal(12,2);not null" json:"target"`
+	CurrentAmount money.Amount   `gorm:"type:decimal(12,2);default:0.00" json:"current_amount"`
 	Color         string         `gorm:"type:varchar(20);not null" json:"color"`
 	Icon          string         `gorm:"type:varchar(50)" json:"icon,omitempty"`
 	Deadline      *time.Time     `gorm:"type:date" json:"deadline,omitempty"`
@@ -42,14 +43,11 @@ func (g *SavingGoal) BeforeCreate(tx *gorm.DB) error {
 
 // ProgressPercentage calculates the progress percentage
 func (g *SavingGoal) ProgressPercentage() float64 {
-	if g.TargetAmount == 0 {
-		return 0
-	}
-	return (g.CurrentAmount / g.TargetAmount) * 100
+	return g.CurrentAmount.Percent(g.TargetAmount)
 }
 
 // Remaining calculates the remaining amount to reach the goal
-func (g *SavingGoal) Remaining() float64 {
+func (g *SavingGoal) Remaining() money.Amount {
 	return g.TargetAmount - g.CurrentAmount
 }
 
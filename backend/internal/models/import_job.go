@@ -0,0 +1,101 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"gorm.io/gorm"
+)
+
+// Import job statuses
+const (
+	ImportStatusPreviewed = "Previewed"
+	ImportStatusCompleted = "Completed"
+	ImportStatusUndone    = "Undone"
+)
+
+// Import sources
+const (
+	ImportSourceCSV = "csv"
+	ImportSourceOFX = "ofx"
+	ImportSourceQFX = "qfx"
+	ImportSourceQIF = "qif"
+	// ImportSourceMpesa covers M-PESA SMS messages and statements saved as text
+	ImportSourceMpesa = "mpesa"
+)
+
+// ImportJob records a statement file imported into a wallet. The file is
+// parsed into rows that are previewed first; committing the job creates a
+// transaction per row, and undoing it removes them again.
+type ImportJob struct {
+	ID            uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID        uuid.UUID      `gorm:"type:uuid;not null;index" json:"user_id"`
+	WalletID      uuid.UUID      `gorm:"type:uuid;not null;index" json:"wallet_id"`
+	Source        string         `gorm:"type:varchar(20);not null" json:"source"` // csv, ofx, qfx, qif, mpesa
+	FileName      string         `gorm:"type:varchar(255)" json:"file_name"`
+	Status        string         `gorm:"type:varchar(20);not null;default:'Previewed';index" json:"status"` // Previewed, Completed, Undone
+	TotalRows     int            `gorm:"not null;default:0" json:"total_rows"`
+	DuplicateRows int            `gorm:"not null;default:0" json:"duplicate_rows"`
+	ErrorRows     int            `gorm:"not null;default:0" json:"error_rows"`
+	ImportedRows  int            `gorm:"not null;default:0" json:"imported_rows"`
+	CommittedAt   *time.Time     `json:"committed_at,omitempty"`
+	UndoneAt      *time.Time     `json:"undone_at,omitempty"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relationships
+	User   User         `gorm:"foreignKey:UserID" json:"-"`
+	Wallet *Wallet      `gorm:"foreignKey:WalletID" json:"wallet,omitempty"`
+	Rows   []*ImportRow `gorm:"foreignKey:JobID" json:"rows,omitempty"`
+}
+
+// TableName specifies the table name for the ImportJob model
+func (ImportJob) TableName() string {
+	return "import_jobs"
+}
+
+// BeforeCreate hook to generate UUID before creating an import job
+func (j *ImportJob) BeforeCreate(tx *gorm.DB) error {
+	if j.ID == uuid.Nil {
+		j.ID = uuid.New()
+	}
+	if j.Status == "" {
+		j.Status = ImportStatusPreviewed
+	}
+	return nil
+}
+
+// ImportRow is one parsed statement line of an import job. Rows that could not
+// be read keep their Error; rows matching an existing transaction point to it
+// through DuplicateOfID. Once committed, TransactionID links the row to the
+// transaction it created.
+type ImportRow struct {
+	ID              uuid.UUID     `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	JobID           uuid.UUID     `gorm:"type:uuid;not null;index" json:"job_id"`
+	Line            int           `gorm:"not null" json:"line"`
+	TransactionDate *time.Time    `json:"transaction_date,omitempty"`
+	Description     string        `gorm:"type:varchar(255)" json:"description"`
+	Amount          money.Amount  `gorm:"type:decimal(12,2)" json:"amount"`
+	Type            string        `gorm:"type:varchar(20)" json:"type,omitempty"` // income, expense
+	Category        string        `gorm:"type:varchar(100)" json:"category,omitempty"`
+	Balance         *money.Amount `gorm:"type:decimal(12,2)" json:"balance,omitempty"`
+	ExternalID      string        `gorm:"type:varchar(100)" json:"external_id,omitempty"`
+	DuplicateOfID   *uuid.UUID    `gorm:"type:uuid" json:"duplicate_of_id,omitempty"`
+	Error           string        `gorm:"type:varchar(255)" json:"error,omitempty"`
+	TransactionID   *uuid.UUID    `gorm:"type:uuid;index" json:"transaction_id,omitempty"`
+}
+
+// TableName specifies the table name for the ImportRow model
+func (ImportRow) TableName() string {
+	return "import_rows"
+}
+
+// BeforeCreate hook to generate UUID before creating an import row
+func (r *ImportRow) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
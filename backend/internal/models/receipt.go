@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Receipt is an image or PDF uploaded for a transaction. The file itself is
+// kept in file storage under StorageKey; images also get a JPEG thumbnail.
+type Receipt struct {
+	ID            uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID        uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
+	TransactionID uuid.UUID `gorm:"type:uuid;not null;index" json:"transaction_id"`
+	FileName      string    `gorm:"type:varchar(255);not null" json:"file_name"`
+	ContentType   string    `gorm:"type:varchar(100);not null" json:"content_type"`
+	Size          int64     `gorm:"not null" json:"size"`
+	StorageKey    string    `gorm:"type:varchar(500);not null" json:"-"`
+	ThumbnailKey  string    `gorm:"type:varchar(500)" json:"-"`
+	HasThumbnail  bool      `gorm:"-" json:"has_thumbnail"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for the Receipt model
+func (Receipt) TableName() string {
+	return "receipts"
+}
+
+// BeforeCreate hook to generate UUID before creating a receipt
+func (r *Receipt) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+// AfterFind hook to report whether the receipt has a thumbnail
+func (r *Receipt) AfterFind(tx *gorm.DB) error {
+	r.HasThumbnail = r.ThumbnailKey != ""
+	return nil
+}
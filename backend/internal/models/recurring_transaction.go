@@ -0,0 +1,159 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"gorm.io/gorm"
+)
+
+// Recurrence frequencies
+const (
+	FrequencyDaily   = "daily"
+	FrequencyWeekly  = "weekly"
+	FrequencyMonthly = "monthly"
+	FrequencyYearly  = "yearly"
+)
+
+// LastDayOfMonth as a DayOfMonth schedules occurrences on the last day of the month
+const LastDayOfMonth = -1
+
+// maxOccurrenceSearch bounds how far schedules are walked to find an occurrence
+const maxOccurrenceSearch = 100000
+
+// RecurringTransaction is a schedule of transactions repeated every Interval
+// days, weeks, months or years from StartDate, like an iCalendar RRULE.
+// Monthly and yearly schedules fall on DayOfMonth, or on the day of StartDate
+// when it is zero, moved back to the last day of shorter months. Occurrences
+// keep the time of day of StartDate.
+type RecurringTransaction struct {
+	ID             uuid.UUID    `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID         uuid.UUID    `gorm:"type:uuid;not null;index" json:"user_id"`
+	WalletID       *uuid.UUID   `gorm:"type:uuid;index" json:"wallet_id,omitempty"`
+	Amount         money.Amount `gorm:"type:decimal(12,2);not null" json:"amount"`
+	Type           string       `gorm:"type:varchar(20);not null;default:'expense'" json:"type"` // income, expense
+	Name           string       `gorm:"type:varchar(255);not null" json:"name"`
+	Method         string       `gorm:"type:varchar(100);not null" json:"method"`
+	Category       string       `gorm:"type:varchar(100);not null" json:"category"`
+	Notes          string       `gorm:"type:text" json:"notes,omitempty"`
+	Frequency      string       `gorm:"type:varchar(10);not null" json:"frequency"` // daily, weekly, monthly, yearly
+	Interval       int          `gorm:"column:repeat_interval;not null;default:1" json:"interval"`
+	DayOfMonth     int          `gorm:"default:0" json:"day_of_month,omitempty"` // 1-31, or -1 for the last day
+	StartDate      time.Time    `gorm:"not null" json:"start_date"`
+	EndDate        *time.Time   `json:"end_date,omitempty"`
+	MaxOccurrences *int         `json:"max_occurrences,omitempty"`
+	// PostAsPending posts occurrences as Pending for the user to confirm
+	PostAsPending bool `gorm:"default:false" json:"post_as_pending"`
+	IsActive      bool `gorm:"default:true;index" json:"is_active"`
+	// OccurrenceCount is the index of NextOccurrence in the schedule
+	OccurrenceCount int `gorm:"default:0" json:"occurrence_count"`
+	// NextOccurrence is the next date to post, or nil once the schedule has ended
+	NextOccurrence *time.Time     `gorm:"index" json:"next_occurrence,omitempty"`
+	LastOccurrence *time.Time     `json:"last_occurrence,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relationships
+	User   User    `gorm:"foreignKey:UserID" json:"-"`
+	Wallet *Wallet `gorm:"foreignKey:WalletID" json:"wallet,omitempty"`
+}
+
+// TableName specifies the table name for the RecurringTransaction model
+func (RecurringTransaction) TableName() string {
+	return "recurring_transactions"
+}
+
+// BeforeCreate hook to generate UUID before creating a recurring transaction
+func (r *RecurringTransaction) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+// Occurrence returns the nth occurrence of the schedule, counting from zero,
+// and whether the schedule reaches it before its end date and occurrence limit
+func (r *RecurringTransaction) Occurrence(n int) (time.Time, bool) {
+	if n < 0 || r.MaxOccurrences != nil && n >= *r.MaxOccurrences {
+		return time.Time{}, false
+	}
+	// A monthly or yearly day before the start day first falls in the next period
+	if r.occurrence(0).Before(r.StartDate) {
+		n++
+	}
+	date := r.occurrence(n)
+	if r.EndDate != nil && date.After(*r.EndDate) {
+		return time.Time{}, false
+	}
+	return date, true
+}
+
+// occurrence computes the nth date of the schedule from its start
+func (r *RecurringTransaction) occurrence(n int) time.Time {
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+	start := r.StartDate
+
+	var months int
+	switch r.Frequency {
+	case FrequencyDaily:
+		return start.AddDate(0, 0, n*interval)
+	case FrequencyWeekly:
+		return start.AddDate(0, 0, 7*n*interval)
+	case FrequencyMonthly:
+		months = n * interval
+	case FrequencyYearly:
+		months = 12 * n * interval
+	default:
+		return start
+	}
+
+	// Work from the first of the month so long months do not overflow
+	first := time.Date(start.Year(), start.Month()+time.Month(months), 1,
+		start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), start.Location())
+	lastDay := first.AddDate(0, 1, -1).Day()
+	day := r.DayOfMonth
+	if day == 0 {
+		day = start.Day()
+	}
+	if day == LastDayOfMonth || day > lastDay {
+		day = lastDay
+	}
+	return first.AddDate(0, 0, day-1)
+}
+
+// ScheduleFrom points the schedule at its first occurrence on or after from,
+// or ends it when there is none
+func (r *RecurringTransaction) ScheduleFrom(from time.Time) {
+	r.NextOccurrence = nil
+	for n := 0; n < maxOccurrenceSearch; n++ {
+		date, ok := r.Occurrence(n)
+		if !ok {
+			r.OccurrenceCount = n
+			return
+		}
+		if !date.Before(from) {
+			r.OccurrenceCount = n
+			r.NextOccurrence = &date
+			return
+		}
+	}
+}
+
+// Advance records NextOccurrence as posted and moves on to the following one
+func (r *RecurringTransaction) Advance() {
+	if r.NextOccurrence == nil {
+		return
+	}
+	posted := *r.NextOccurrence
+	r.LastOccurrence = &posted
+	r.OccurrenceCount++
+	r.NextOccurrence = nil
+	if date, ok := r.Occurrence(r.OccurrenceCount); ok {
+		r.NextOccurrence = &date
+	}
+}
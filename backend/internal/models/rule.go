@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"gorm.io/gorm"
+)
+
+// Rule text fields
+const (
+	RuleFieldName  = "name"
+	RuleFieldNotes = "notes"
+	RuleFieldAny   = "any"
+)
+
+// Rule match types
+const (
+	RuleMatchContains = "contains"
+	RuleMatchRegex    = "regex"
+)
+
+// Rule categorizes transactions automatically. A transaction matches when it
+// meets every condition the rule sets; the rule's actions then set its
+// category and notes and add tags. Rules run in priority order, lowest first.
+type Rule struct {
+	ID       uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID   uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
+	Name     string    `gorm:"type:varchar(100);not null" json:"name"`
+	Priority int       `gorm:"not null;default:0" json:"priority"`
+	IsActive bool      `gorm:"default:true" json:"is_active"`
+
+	// Conditions; unset conditions match every transaction
+	MatchField string        `gorm:"type:varchar(10);not null;default:'name'" json:"match_field"`    // name, notes, any
+	MatchType  string        `gorm:"type:varchar(10);not null;default:'contains'" json:"match_type"` // contains, regex
+	Pattern    string        `gorm:"type:varchar(255)" json:"pattern,omitempty"`                     // Text or regular expression, ignoring case
+	MinAmount  *money.Amount `gorm:"type:decimal(12,2)" json:"min_amount,omitempty"`
+	MaxAmount  *money.Amount `gorm:"type:decimal(12,2)" json:"max_amount,omitempty"`
+	Method     string        `gorm:"type:varchar(100)" json:"method,omitempty"`
+	WalletID   *uuid.UUID    `gorm:"type:uuid" json:"wallet_id,omitempty"`
+	Type       string        `gorm:"type:varchar(20)" json:"type,omitempty"` // income, expense
+
+	// Actions
+	SetCategory string `gorm:"type:varchar(100)" json:"set_category,omitempty"`
+	SetNotes    string `gorm:"type:text" json:"set_notes,omitempty"`
+	AddTags     []Tag  `gorm:"many2many:rule_tags" json:"add_tags,omitempty"`
+
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName specifies the table name for the Rule model
+func (Rule) TableName() string {
+	return "rules"
+}
+
+// BeforeCreate hook to generate UUID before creating a rule
+func (r *Rule) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	if r.MatchField == "" {
+		r.MatchField = RuleFieldName
+	}
+	if r.MatchType == "" {
+		r.MatchType = RuleMatchContains
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Tag is a free-form label a user attaches to transactions. Unlike a
+// category, a transaction can carry any number of tags.
+type Tag struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_tags_user_name,priority:1" json:"user_id"`
+	Name      string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_tags_user_name,priority:2" json:"name"`
+	Color     string    `gorm:"type:varchar(20)" json:"color,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for the Tag model
+func (Tag) TableName() string {
+	return "tags"
+}
+
+// BeforeCreate hook to generate UUID before creating a tag
+func (t *Tag) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
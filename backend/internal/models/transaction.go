@@ -4,28 +4,45 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/money"
 	"gorm.io/gorm"
 )
 
+// Transaction types. Amount is always stored as a positive value and the
+// type carries the direction of the money movement.
+const (
+	TransactionTypeIncome   = "income"
+	TransactionTypeExpense  = "expense"
+	TransactionTypeTransfer = "transfer"
+)
+
 type Transaction struct {
-	ID              uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	UserID          uuid.UUID      `gorm:"type:uuid;not null;index" json:"user_id"`
+	ID              uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid();index:idx_transactions_user_date_id,priority:3" json:"id"`
+	UserID          uuid.UUID      `gorm:"type:uuid;not null;index;index:idx_transactions_user_date_id,priority:1" json:"user_id"`
 	WalletID        *uuid.UUID     `gorm:"type:uuid;index" json:"wallet_id,omitempty"`
-	Amount          float64        `gorm:"type:decimal(12,2);not null" json:"amount"`
+	TransferID      *uuid.UUID     `gorm:"type:uuid;index" json:"transfer_id,omitempty"`
+	Amount          money.Amount   `gorm:"type:decimal(12,2);not null" json:"amount"`
+	Type            string         `gorm:"type:varchar(20);not null;default:'expense';index" json:"type"` // income, expense, transfer
 	Name            string         `gorm:"column:name;type:varchar(255);not null" json:"name"`
 	Method          string         `gorm:"type:varchar(100);not null" json:"method"`
 	Category        string         `gorm:"type:varchar(100);not null;index" json:"category"`
 	Status          string         `gorm:"type:varchar(20);default:'Completed';index" json:"status"` // Completed, Pending, Failed
 	Notes           string         `gorm:"type:text" json:"notes,omitempty"`
 	ReceiptURL      string         `gorm:"type:varchar(500)" json:"receipt_url,omitempty"`
-	TransactionDate time.Time      `gorm:"not null;index" json:"transaction_date"`
+	ExternalID      string         `gorm:"type:varchar(100);index" json:"external_id,omitempty"` // The statement's own ID when imported
+	ImportJobID     *uuid.UUID     `gorm:"type:uuid;index" json:"import_job_id,omitempty"`
+	RecurringID     *uuid.UUID     `gorm:"type:uuid;uniqueIndex:idx_transactions_recurring_occurrence,priority:1" json:"recurring_id,omitempty"` // The schedule that posted it, once per date
+	TransactionDate time.Time      `gorm:"not null;index;index:idx_transactions_user_date_id,priority:2;uniqueIndex:idx_transactions_recurring_occurrence,priority:2" json:"transaction_date"`
 	CreatedAt       time.Time      `json:"created_at"`
 	UpdatedAt       time.Time      `json:"updated_at"`
 	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
 
 	// Relationships
-	User   User    `gorm:"foreignKey:UserID" json:"user,omitempty"`
-	Wallet *Wallet `gorm:"foreignKey:WalletID" json:"wallet,omitempty"`
+	User     User               `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Wallet   *Wallet            `gorm:"foreignKey:WalletID" json:"wallet,omitempty"`
+	Splits   []TransactionSplit `gorm:"foreignKey:TransactionID" json:"splits,omitempty"`
+	Tags     []Tag              `gorm:"many2many:transaction_tags" json:"tags,omitempty"`
+	Receipts []Receipt          `gorm:"foreignKey:TransactionID" json:"receipts,omitempty"`
 }
 
 // TableName specifies the table name for the Transaction model
@@ -38,9 +55,44 @@ func (t *Transaction) BeforeCreate(tx *gorm.DB) error {
 	if t.ID == uuid.Nil {
 		t.ID = uuid.New()
 	}
+	// Default to expense, which is how untyped transactions were always treated
+	if t.Type == "" {
+		t.Type = TransactionTypeExpense
+	}
 	// Set transaction date to now if not provided
 	if t.TransactionDate.IsZero() {
 		t.TransactionDate = time.Now()
 	}
 	return nil
 }
+
+// IsIncome reports whether the transaction brings money in
+func (t *Transaction) IsIncome() bool {
+	return t.Type == TransactionTypeIncome
+}
+
+// IsExpense reports whether the transaction is spending
+func (t *Transaction) IsExpense() bool {
+	return t.Type == TransactionTypeExpense
+}
+
+// IsTransfer reports whether the transaction moves money between the user's own wallets
+func (t *Transaction) IsTransfer() bool {
+	return t.Type == TransactionTypeTransfer
+}
+
+// BalanceEffect returns the signed amount this transaction contributes to its
+// wallet balance. Only completed income and expenses attached to a wallet move
+// money; transfer legs are settled by the Transfer that owns them.
+func (t *Transaction) BalanceEffect() money.Amount {
+	if t.WalletID == nil || t.Status != "Completed" {
+		return 0
+	}
+	switch t.Type {
+	case TransactionTypeIncome:
+		return t.Amount
+	case TransactionTypeExpense:
+		return -t.Amount
+	}
+	return 0
+}
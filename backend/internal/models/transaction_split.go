@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"gorm.io/gorm"
+)
+
+// TransactionSplit attributes part of a transaction's amount to a category.
+// The splits of a transaction, when it has any, add up to its amount and
+// replace its own category in budgets and spending reports.
+type TransactionSplit struct {
+	ID            uuid.UUID    `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	TransactionID uuid.UUID    `gorm:"type:uuid;not null;index" json:"transaction_id"`
+	Category      string       `gorm:"type:varchar(100);not null;index" json:"category"`
+	Amount        money.Amount `gorm:"type:decimal(12,2);not null" json:"amount"`
+	Note          string       `gorm:"type:varchar(255)" json:"note,omitempty"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+}
+
+// TableName specifies the table name for the TransactionSplit model
+func (TransactionSplit) TableName() string {
+	return "transaction_splits"
+}
+
+// BeforeCreate hook to generate UUID before creating a split
+func (s *TransactionSplit) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
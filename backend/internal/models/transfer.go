@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"gorm.io/gorm"
+)
+
+// Transfer records money moved between two wallets owned by the same user.
+// Each transfer owns a pair of transfer transactions: one leaving the source
+// wallet and one arriving in the destination wallet. When the wallets hold
+// different currencies the exchange rate and the date it was quoted for are
+// kept with the transfer.
+type Transfer struct {
+	ID               uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID           uuid.UUID      `gorm:"type:uuid;not null;index" json:"user_id"`
+	FromWalletID     uuid.UUID      `gorm:"type:uuid;not null;index" json:"from_wallet_id"`
+	ToWalletID       uuid.UUID      `gorm:"type:uuid;not null;index" json:"to_wallet_id"`
+	Amount           money.Amount   `gorm:"type:decimal(12,2);not null" json:"amount"` // Debited, in the source currency
+	FromCurrency     string         `gorm:"type:varchar(10)" json:"from_currency"`
+	ToAmount         money.Amount   `gorm:"type:decimal(12,2)" json:"to_amount"` // Credited, in the destination currency
+	ToCurrency       string         `gorm:"type:varchar(10)" json:"to_currency"`
+	ExchangeRate     money.Rate     `gorm:"type:decimal(18,8)" json:"exchange_rate"`
+	RateDate         *time.Time     `gorm:"type:date" json:"rate_date,omitempty"`
+	Notes            string         `gorm:"type:text" json:"notes,omitempty"`
+	Status           string         `gorm:"type:varchar(20);default:'Completed';index" json:"status"` // Completed, Reversed
+	OutTransactionID *uuid.UUID     `gorm:"type:uuid" json:"out_transaction_id,omitempty"`
+	InTransactionID  *uuid.UUID     `gorm:"type:uuid" json:"in_transaction_id,omitempty"`
+	ReversalOfID     *uuid.UUID     `gorm:"type:uuid;index" json:"reversal_of_id,omitempty"`
+	ReversedAt       *time.Time     `json:"reversed_at,omitempty"`
+	TransferDate     time.Time      `gorm:"not null;index" json:"transfer_date"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relationships
+	User       User    `gorm:"foreignKey:UserID" json:"-"`
+	FromWallet *Wallet `gorm:"foreignKey:FromWalletID" json:"from_wallet,omitempty"`
+	ToWallet   *Wallet `gorm:"foreignKey:ToWalletID" json:"to_wallet,omitempty"`
+}
+
+// TableName specifies the table name for the Transfer model
+func (Transfer) TableName() string {
+	return "transfers"
+}
+
+// BeforeCreate hook to generate UUID before creating a transfer
+func (t *Transfer) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	if t.TransferDate.IsZero() {
+		t.TransferDate = time.Now()
+	}
+	return nil
+}
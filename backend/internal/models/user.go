@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/money"
 	"gorm.io/gorm"
 )
 
@@ -12,8 +13,8 @@ type User struct {
 	Name          string         `gorm:"type:varchar(255);not null" json:"name"`
 	Email         string         `gorm:"type:varchar(255);not null;uniqueIndex" json:"email"`
 	PasswordHash  string         `gorm:"type:varchar(255);not null" json:"-"` // "-" means don't include in JSON
-	MonthlyIncome float64        `gorm:"type:decimal(15,2);default:0" json:"monthly_income"`
-	Currency      string         `gorm:"type:varchar(3);default:'USD'" json:"currency"`
+	MonthlyIncome money.Amount   `gorm:"type:decimal(15,2);default:0" json:"monthly_income"`
+	Currency      string         `gorm:"type:varchar(3);default:'KES'" json:"currency"` // Base currency for reports, matches the wallet default
 	IsOnboarded   bool           `gorm:"default:false" json:"is_onboarded"`
 	CreatedAt     time.Time      `json:"created_at"`
 	UpdatedAt     time.Time      `json:"updated_at"`
@@ -4,19 +4,30 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/money"
 	"gorm.io/gorm"
 )
 
+// Wallet types
+const (
+	WalletTypeMobileMoney = "Mobile Money"
+	WalletTypeBank        = "Bank"
+	WalletTypeCash        = "Cash"
+	WalletTypeCredit      = "Credit"
+	WalletTypeSavings     = "Savings"
+)
+
 type Wallet struct {
 	ID            uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
 	UserID        uuid.UUID      `gorm:"type:uuid;not null;index" json:"user_id"`
 	Name          string         `gorm:"type:varchar(255);not null" json:"name"`
 	Type          string         `gorm:"type:varchar(50);not null;index" json:"type"` // Mobile Money, Bank, Cash, Credit, Savings
-	Balance       float64        `gorm:"type:decimal(12,2);default:0.00" json:"balance"`
+	Balance       money.Amount   `gorm:"type:decimal(12,2);default:0.00" json:"balance"`
 	Currency      string         `gorm:"type:varchar(10);default:'KES'" json:"currency"`
 	Color         string         `gorm:"type:varchar(20);not null" json:"color"`
 	AccountNumber string         `gorm:"type:varchar(100)" json:"account_number,omitempty"`
 	IsDefault     bool           `gorm:"default:false;index" json:"is_default"`
+	OffBudget     bool           `gorm:"default:false" json:"off_budget"` // Left out of envelope budgeting
 	LastSynced    *time.Time     `json:"last_synced,omitempty"`
 	CreatedAt     time.Time      `json:"created_at"`
 	UpdatedAt     time.Time      `json:"updated_at"`
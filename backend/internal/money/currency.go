@@ -0,0 +1,80 @@
+package money
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// DefaultCurrency is used for wallets created without an explicit currency
+const DefaultCurrency = "KES"
+
+// exponents lists ISO 4217 currencies whose minor unit is not hundredths.
+// Every other currency is assumed to have two decimal places.
+var exponents = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0,
+	"PYG": 0, "RWF": 0, "UGX": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+}
+
+// Exponent returns the number of decimal places used by a currency
+func Exponent(currency string) int {
+	if exp, ok := exponents[strings.ToUpper(currency)]; ok {
+		return exp
+	}
+	return 2
+}
+
+// Money is an amount together with the currency it is denominated in
+type Money struct {
+	Amount   Amount `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// New returns money in the given currency, rounded to the currency's precision
+func New(amount Amount, currency string) Money {
+	currency = strings.ToUpper(currency)
+	return Money{Amount: amount.Round(currency), Currency: currency}
+}
+
+// Add returns the sum of two values in the same currency
+func (m Money) Add(other Money) (Money, error) {
+	if !strings.EqualFold(m.Currency, other.Currency) {
+		return Money{}, ErrCurrencyMismatch
+	}
+	return Money{Amount: m.Amount + other.Amount, Currency: m.Currency}, nil
+}
+
+// Sub returns the difference of two values in the same currency
+func (m Money) Sub(other Money) (Money, error) {
+	if !strings.EqualFold(m.Currency, other.Currency) {
+		return Money{}, ErrCurrencyMismatch
+	}
+	return Money{Amount: m.Amount - other.Amount, Currency: m.Currency}, nil
+}
+
+// String formats the value using the currency's precision, e.g. "KES 1250.50"
+// or "JPY 1250"
+func (m Money) String() string {
+	exp := Exponent(m.Currency)
+	if exp > Scale {
+		exp = Scale
+	}
+	value := m.Amount.Round(m.Currency).String()
+	if exp < Scale {
+		// Drop the digits the currency does not use
+		value = value[:len(value)-(Scale-exp)]
+		value = strings.TrimSuffix(value, ".")
+	}
+	return fmt.Sprintf("%s %s", m.Currency, value)
+}
+
+// MinorUnits returns the value in the currency's own minor units, e.g. cents
+// for USD or yen for JPY
+func (m Money) MinorUnits() int64 {
+	exp := Exponent(m.Currency)
+	if exp >= Scale {
+		return int64(m.Amount) * int64(math.Pow10(exp-Scale))
+	}
+	return int64(m.Amount.Round(m.Currency)) / int64(math.Pow10(Scale-exp))
+}
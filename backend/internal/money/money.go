@@ -0,0 +1,273 @@
+package money
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Scale is the number of decimal places an Amount carries. It matches the
+// decimal(12,2) columns amounts are stored in.
+const Scale = 2
+
+// unit is the number of minor units in one major unit at Scale
+const unit = 100
+
+var (
+	// ErrInvalidAmount is returned when a value cannot be parsed as an amount
+	ErrInvalidAmount = errors.New("invalid monetary amount")
+	// ErrOverflow is returned when a value does not fit in an Amount
+	ErrOverflow = errors.New("monetary amount out of range")
+	// ErrCurrencyMismatch is returned when combining money in different currencies
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+)
+
+// Amount is an exact monetary value held as an integer number of minor units
+// (hundredths). It is stored as a decimal string in the database and encoded
+// as a plain JSON number, so it is a drop-in replacement for float64 fields.
+type Amount int64
+
+// FromMinor returns the amount for the given number of minor units
+func FromMinor(minor int64) Amount {
+	return Amount(minor)
+}
+
+// FromMajor returns the amount for the given number of whole units
+func FromMajor(major int64) Amount {
+	return Amount(major * unit)
+}
+
+// FromFloat converts a float to the nearest amount, rounding half to even.
+// It is meant for interop with code that still produces floats; amounts read
+// from requests or the database should go through Parse instead.
+func FromFloat(f float64) Amount {
+	a, err := Parse(strconv.FormatFloat(f, 'f', -1, 64))
+	if err != nil {
+		return 0
+	}
+	return a
+}
+
+// Parse reads a decimal string such as "1250", "-12.5" or "1.2e3". Digits
+// beyond Scale are rounded half to even.
+func Parse(s string) (Amount, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, ErrInvalidAmount
+	}
+
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return 0, ErrInvalidAmount
+	}
+
+	return fromRat(r.Mul(r, big.NewRat(unit, 1)))
+}
+
+// MustParse is like Parse but panics on invalid input. It is intended for
+// constants and tests.
+func MustParse(s string) Amount {
+	a, err := Parse(s)
+	if err != nil {
+		panic(fmt.Sprintf("money: %q: %v", s, err))
+	}
+	return a
+}
+
+// fromRat rounds a rational number of minor units half to even
+func fromRat(r *big.Rat) (Amount, error) {
+	num, den := r.Num(), r.Denom()
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+
+	// Compare twice the remainder with the denominator to decide the rounding
+	twice := new(big.Int).Abs(rem)
+	twice.Lsh(twice, 1)
+	switch cmp := twice.Cmp(den); {
+	case cmp > 0, cmp == 0 && quo.Bit(0) == 1:
+		if num.Sign() < 0 {
+			quo.Sub(quo, big.NewInt(1))
+		} else {
+			quo.Add(quo, big.NewInt(1))
+		}
+	}
+
+	if !quo.IsInt64() {
+		return 0, ErrOverflow
+	}
+	return Amount(quo.Int64()), nil
+}
+
+// Minor returns the amount as an integer number of minor units
+func (a Amount) Minor() int64 {
+	return int64(a)
+}
+
+// Float64 returns the amount as a float. Use it only for ratios and display,
+// never to do further arithmetic on money.
+func (a Amount) Float64() float64 {
+	return float64(a) / unit
+}
+
+// String formats the amount with exactly Scale decimal places, e.g. "-12.50"
+func (a Amount) String() string {
+	sign := ""
+	minor := int64(a)
+	if minor < 0 {
+		sign = "-"
+	}
+	u := uint64(minor)
+	if minor < 0 {
+		u = uint64(-(minor + 1)) + 1
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, u/unit, u%unit)
+}
+
+// Abs returns the absolute value of the amount
+func (a Amount) Abs() Amount {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+// MulRat multiplies the amount by num/den, rounding half to even. It panics
+// if den is zero.
+func (a Amount) MulRat(num, den int64) Amount {
+	if den == 0 {
+		panic("money: division by zero")
+	}
+	r := new(big.Rat).SetFrac(big.NewInt(int64(a)), big.NewInt(den))
+	r.Mul(r, big.NewRat(num, 1))
+	result, err := fromRat(r)
+	if err != nil {
+		if r.Sign() < 0 {
+			return math.MinInt64
+		}
+		return math.MaxInt64
+	}
+	return result
+}
+
+// Div divides the amount by n, rounding half to even
+func (a Amount) Div(n int64) Amount {
+	return a.MulRat(1, n)
+}
+
+// Percent returns a as a percentage of total, or 0 when total is zero
+func (a Amount) Percent(total Amount) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(a) / float64(total) * 100
+}
+
+// Allocate splits the amount in proportion to the given weights without
+// losing or creating minor units. Leftover units go to the earliest parts.
+func (a Amount) Allocate(weights ...int64) []Amount {
+	parts := make([]Amount, len(weights))
+
+	var total int64
+	for _, w := range weights {
+		total += w
+	}
+	if total == 0 {
+		return parts
+	}
+
+	remainder := a
+	for i, w := range weights {
+		parts[i] = a.MulRat(w, total)
+		remainder -= parts[i]
+	}
+
+	step := Amount(1)
+	if remainder < 0 {
+		step = -1
+	}
+	for i := 0; remainder != 0; i = (i + 1) % len(parts) {
+		if weights[i] == 0 {
+			continue
+		}
+		parts[i] += step
+		remainder -= step
+	}
+
+	return parts
+}
+
+// Round rounds the amount half to even to the precision of the currency.
+// Currencies with more decimal places than Scale keep Scale places.
+func (a Amount) Round(currency string) Amount {
+	exp := Exponent(currency)
+	if exp >= Scale {
+		return a
+	}
+	step := int64(math.Pow10(Scale - exp))
+	return a.MulRat(1, step).MulRat(step, 1)
+}
+
+// MarshalJSON encodes the amount as a JSON number without trailing zeros
+func (a Amount) MarshalJSON() ([]byte, error) {
+	s := a.String()
+	whole, frac, _ := strings.Cut(s, ".")
+	if frac = strings.TrimRight(frac, "0"); frac != "" {
+		return []byte(whole + "." + frac), nil
+	}
+	return []byte(whole), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a numeric string. Numbers are read
+// from their decimal text, so no precision is lost through float64.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+
+	parsed, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// Scan implements sql.Scanner for decimal columns
+func (a *Amount) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*a = 0
+		return nil
+	case []byte:
+		parsed, err := Parse(string(v))
+		if err != nil {
+			return err
+		}
+		*a = parsed
+	case string:
+		parsed, err := Parse(v)
+		if err != nil {
+			return err
+		}
+		*a = parsed
+	case int64:
+		*a = FromMajor(v)
+	case float64:
+		*a = FromFloat(v)
+	default:
+		return fmt.Errorf("money: cannot scan %T into Amount", value)
+	}
+	return nil
+}
+
+// Value implements driver.Valuer, writing the amount as an exact decimal string
+func (a Amount) Value() (driver.Value, error) {
+	return a.String(), nil
+}
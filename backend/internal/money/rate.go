@@ -0,0 +1,136 @@
+package money
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// RateScale is the number of decimal places an exchange Rate carries. It
+// matches the decimal(18,8) column rates are stored in.
+const RateScale = 8
+
+// rateUnit is the integer value of a rate of exactly one
+const rateUnit = 100000000
+
+// ErrInvalidRate is returned when a value cannot be parsed as an exchange rate
+var ErrInvalidRate = errors.New("invalid exchange rate")
+
+// Rate is an exchange rate: how many units of the quote currency one unit of
+// the base currency buys. Like Amount it is held as a scaled integer so that
+// conversions are exact and reproducible.
+type Rate int64
+
+// OneRate is the rate between a currency and itself
+const OneRate Rate = rateUnit
+
+// ParseRate reads a positive decimal rate such as "1.0956" or "164.52"
+func ParseRate(s string) (Rate, error) {
+	s = strings.TrimSpace(s)
+	r, ok := new(big.Rat).SetString(s)
+	if !ok || r.Sign() <= 0 {
+		return 0, ErrInvalidRate
+	}
+
+	scaled, err := fromRat(r.Mul(r, big.NewRat(rateUnit, 1)))
+	if err != nil || scaled <= 0 {
+		return 0, ErrInvalidRate
+	}
+	return Rate(scaled), nil
+}
+
+// String formats the rate with RateScale decimal places
+func (r Rate) String() string {
+	return fmt.Sprintf("%d.%08d", int64(r)/rateUnit, int64(r)%rateUnit)
+}
+
+// Float64 returns the rate as a float, for display only
+func (r Rate) Float64() float64 {
+	return float64(r) / rateUnit
+}
+
+// Inverse returns the rate in the opposite direction
+func (r Rate) Inverse() Rate {
+	if r <= 0 {
+		return 0
+	}
+	inverse, err := fromRat(big.NewRat(rateUnit*rateUnit, int64(r)))
+	if err != nil {
+		return 0
+	}
+	return Rate(inverse)
+}
+
+// Cross chains two rates, e.g. KES->EUR followed by EUR->USD gives KES->USD
+func (r Rate) Cross(next Rate) Rate {
+	product := new(big.Rat).SetFrac(big.NewInt(int64(r)), big.NewInt(rateUnit))
+	product.Mul(product, big.NewRat(int64(next), 1))
+	crossed, err := fromRat(product)
+	if err != nil {
+		return 0
+	}
+	return Rate(crossed)
+}
+
+// Convert applies the rate to an amount in the base currency, rounding the
+// result half to even
+func (a Amount) Convert(r Rate) Amount {
+	return a.MulRat(int64(r), rateUnit)
+}
+
+// MarshalJSON encodes the rate as a JSON number
+func (r Rate) MarshalJSON() ([]byte, error) {
+	s := strings.TrimRight(r.String(), "0")
+	return []byte(strings.TrimSuffix(s, ".")), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a numeric string
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+
+	parsed, err := ParseRate(s)
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+// Scan implements sql.Scanner for decimal columns
+func (r *Rate) Scan(value interface{}) error {
+	var s string
+	switch v := value.(type) {
+	case nil:
+		*r = 0
+		return nil
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Errorf("money: cannot scan %T into Rate", value)
+	}
+
+	parsed, err := ParseRate(s)
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+// Value implements driver.Valuer, writing the rate as an exact decimal string
+func (r Rate) Value() (driver.Value, error) {
+	return r.String(), nil
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BudgetLedgerRepository defines the interface for budget ledger data operations
+type BudgetLedgerRepository interface {
+	FindByBudgetID(budgetID uuid.UUID) ([]*models.BudgetLedgerEntry, error)
+	Append(entries []*models.BudgetLedgerEntry) error
+	WithTx(tx *gorm.DB) BudgetLedgerRepository
+}
+
+type budgetLedgerRepository struct {
+	db *gorm.DB
+}
+
+// NewBudgetLedgerRepository creates a new instance of BudgetLedgerRepository
+func NewBudgetLedgerRepository(db *gorm.DB) BudgetLedgerRepository {
+	return &budgetLedgerRepository{db: db}
+}
+
+// FindByBudgetID retrieves every revision of a budget's ledger, oldest period
+// first and each period's revisions in order
+func (r *budgetLedgerRepository) FindByBudgetID(budgetID uuid.UUID) ([]*models.BudgetLedgerEntry, error) {
+	var entries []*models.BudgetLedgerEntry
+	err := r.db.Where("budget_id = ?", budgetID).Order("period_start ASC, revision ASC").Find(&entries).Error
+	return entries, err
+}
+
+// Append inserts ledger entries. An entry whose budget, period and revision
+// are already recorded, as when two snapshot runs overlap, is skipped.
+func (r *budgetLedgerRepository) Append(entries []*models.BudgetLedgerEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(entries, 500).Error
+}
+
+// WithTx returns a repository bound to the given database transaction
+func (r *budgetLedgerRepository) WithTx(tx *gorm.DB) BudgetLedgerRepository {
+	return &budgetLedgerRepository{db: tx}
+}
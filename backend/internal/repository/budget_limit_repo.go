@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BudgetLimitRepository defines the interface for budget limit version data operations
+type BudgetLimitRepository interface {
+	FindByBudgetIDs(budgetIDs []uuid.UUID) ([]*models.BudgetLimit, error)
+	Upsert(limit *models.BudgetLimit) error
+	WithTx(tx *gorm.DB) BudgetLimitRepository
+}
+
+type budgetLimitRepository struct {
+	db *gorm.DB
+}
+
+// NewBudgetLimitRepository creates a new instance of BudgetLimitRepository
+func NewBudgetLimitRepository(db *gorm.DB) BudgetLimitRepository {
+	return &budgetLimitRepository{db: db}
+}
+
+// FindByBudgetIDs retrieves the limit versions of several budgets, oldest first
+func (r *budgetLimitRepository) FindByBudgetIDs(budgetIDs []uuid.UUID) ([]*models.BudgetLimit, error) {
+	var limits []*models.BudgetLimit
+	if len(budgetIDs) == 0 {
+		return limits, nil
+	}
+	err := r.db.Where("budget_id IN ?", budgetIDs).Order("effective_from ASC").Find(&limits).Error
+	return limits, err
+}
+
+// Upsert inserts a limit version, replacing the amount of any version the
+// budget already has from the same day
+func (r *budgetLimitRepository) Upsert(limit *models.BudgetLimit) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "budget_id"}, {Name: "effective_from"}},
+		DoUpdates: clause.AssignmentColumns([]string{"limit_amount", "updated_at"}),
+	}).Create(limit).Error
+}
+
+// WithTx returns a repository bound to the given database transaction
+func (r *budgetLimitRepository) WithTx(tx *gorm.DB) BudgetLimitRepository {
+	return &budgetLimitRepository{db: tx}
+}
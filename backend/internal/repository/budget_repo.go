@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/nyunja/fity-budget-backend/internal/models"
 	"gorm.io/gorm"
@@ -13,8 +15,17 @@ type BudgetRepository interface {
 	FindByUserID(userID uuid.UUID) ([]*models.Budget, error)
 	FindByUserIDAndCategory(userID uuid.UUID, category string) (*models.Budget, error)
 	FindAll() ([]*models.Budget, error)
+	FindSnapshotDue(now time.Time, limit int) ([]*models.Budget, error)
+	SetNextSnapshot(id uuid.UUID, next *time.Time) error
+	DeferSnapshot(id uuid.UUID, failures int, retryAt time.Time) error
 	Update(budget *models.Budget) error
 	Delete(id uuid.UUID) error
+	FindDeletedByUserID(userID uuid.UUID) ([]*models.Budget, error)
+	FindDeletedByID(id uuid.UUID) (*models.Budget, error)
+	FindDeletedBefore(cutoff time.Time, limit int) ([]*models.Budget, error)
+	Restore(id uuid.UUID) error
+	Purge(id uuid.UUID) error
+	WithTx(tx *gorm.DB) BudgetRepository
 }
 
 type budgetRepository struct {
@@ -50,10 +61,10 @@ func (r *budgetRepository) FindByUserID(userID uuid.UUID) ([]*models.Budget, err
 	return budgets, err
 }
 
-// FindByUserIDAndCategory retrieves a specific budget by user and category
+// FindByUserIDAndCategory retrieves a specific budget by user and category, ignoring case
 func (r *budgetRepository) FindByUserIDAndCategory(userID uuid.UUID, category string) (*models.Budget, error) {
 	var budget models.Budget
-	err := r.db.Where("user_id = ? AND category = ?", userID, category).First(&budget).Error
+	err := r.db.Where("user_id = ? AND LOWER(category) = LOWER(?)", userID, category).First(&budget).Error
 	if err != nil {
 		return nil, err
 	}
@@ -67,6 +78,42 @@ func (r *budgetRepository) FindAll() ([]*models.Budget, error) {
 	return budgets, err
 }
 
+// FindSnapshotDue retrieves budgets with a closed period waiting to be
+// snapshotted, including budgets that have never been snapshotted. Budgets
+// whose last snapshot failed wait until their retry is due.
+func (r *budgetRepository) FindSnapshotDue(now time.Time, limit int) ([]*models.Budget, error) {
+	var budgets []*models.Budget
+	err := r.db.Where("next_snapshot_at IS NULL OR next_snapshot_at <= ?", now).
+		Where("snapshot_retry_at IS NULL OR snapshot_retry_at <= ?", now).
+		Order("next_snapshot_at ASC NULLS FIRST").
+		Limit(limit).
+		Find(&budgets).Error
+	return budgets, err
+}
+
+// SetNextSnapshot records when a budget's current period closes and clears
+// any failed snapshot, leaving its other fields alone
+func (r *budgetRepository) SetNextSnapshot(id uuid.UUID, next *time.Time) error {
+	return r.db.Model(&models.Budget{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"next_snapshot_at":  next,
+			"snapshot_retry_at": nil,
+			"snapshot_failures": 0,
+		}).Error
+}
+
+// DeferSnapshot records a failed snapshot and when to try it again, leaving
+// the snapshot that is due as it is
+func (r *budgetRepository) DeferSnapshot(id uuid.UUID, failures int, retryAt time.Time) error {
+	return r.db.Model(&models.Budget{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"snapshot_retry_at": retryAt,
+			"snapshot_failures": failures,
+		}).Error
+}
+
 // Update modifies an existing budget
 func (r *budgetRepository) Update(budget *models.Budget) error {
 	return r.db.Save(budget).Error
@@ -76,3 +123,64 @@ func (r *budgetRepository) Update(budget *models.Budget) error {
 func (r *budgetRepository) Delete(id uuid.UUID) error {
 	return r.db.Delete(&models.Budget{}, id).Error
 }
+
+// FindDeletedByUserID retrieves a user's soft-deleted budgets, most recently deleted first
+func (r *budgetRepository) FindDeletedByUserID(userID uuid.UUID) ([]*models.Budget, error) {
+	var budgets []*models.Budget
+	err := r.db.Unscoped().
+		Where("user_id = ? AND deleted_at IS NOT NULL", userID).
+		Order("deleted_at DESC").
+		Find(&budgets).Error
+	return budgets, err
+}
+
+// FindDeletedByID retrieves a soft-deleted budget by its ID
+func (r *budgetRepository) FindDeletedByID(id uuid.UUID) (*models.Budget, error) {
+	var budget models.Budget
+	err := r.db.Unscoped().
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		First(&budget).Error
+	if err != nil {
+		return nil, err
+	}
+	return &budget, nil
+}
+
+// FindDeletedBefore retrieves up to limit budgets soft-deleted before cutoff, oldest first
+func (r *budgetRepository) FindDeletedBefore(cutoff time.Time, limit int) ([]*models.Budget, error) {
+	var budgets []*models.Budget
+	err := r.db.Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+		Order("deleted_at ASC").
+		Limit(limit).
+		Find(&budgets).Error
+	return budgets, err
+}
+
+// Restore undoes the soft delete of a budget
+func (r *budgetRepository) Restore(id uuid.UUID) error {
+	return r.db.Unscoped().Model(&models.Budget{}).
+		Where("id = ?", id).
+		Update("deleted_at", nil).Error
+}
+
+// Purge permanently deletes a budget along with its limit versions, ledger
+// and envelope allocations. Call it on a repository bound with WithTx so the
+// deletes commit together.
+func (r *budgetRepository) Purge(id uuid.UUID) error {
+	if err := r.db.Where("budget_id = ?", id).Delete(&models.BudgetLimit{}).Error; err != nil {
+		return err
+	}
+	if err := r.db.Where("budget_id = ?", id).Delete(&models.BudgetLedgerEntry{}).Error; err != nil {
+		return err
+	}
+	if err := r.db.Where("budget_id = ?", id).Delete(&models.EnvelopeAllocation{}).Error; err != nil {
+		return err
+	}
+	return r.db.Unscoped().Delete(&models.Budget{}, id).Error
+}
+
+// WithTx returns a repository bound to the given database transaction
+func (r *budgetRepository) WithTx(tx *gorm.DB) BudgetRepository {
+	return &budgetRepository{db: tx}
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// BudgetTemplateRepository defines the interface for saved budget template data operations
+type BudgetTemplateRepository interface {
+	Create(template *models.BudgetTemplate) error
+	FindByID(id uuid.UUID) (*models.BudgetTemplate, error)
+	FindByUserID(userID uuid.UUID) ([]*models.BudgetTemplate, error)
+	FindByUserIDAndName(userID uuid.UUID, name string) (*models.BudgetTemplate, error)
+	Delete(id uuid.UUID) error
+	WithTx(tx *gorm.DB) BudgetTemplateRepository
+}
+
+type budgetTemplateRepository struct {
+	db *gorm.DB
+}
+
+// NewBudgetTemplateRepository creates a new instance of BudgetTemplateRepository
+func NewBudgetTemplateRepository(db *gorm.DB) BudgetTemplateRepository {
+	return &budgetTemplateRepository{db: db}
+}
+
+// Create inserts a new template together with its lines
+func (r *budgetTemplateRepository) Create(template *models.BudgetTemplate) error {
+	return r.db.Create(template).Error
+}
+
+// FindByID retrieves a template and its lines by ID
+func (r *budgetTemplateRepository) FindByID(id uuid.UUID) (*models.BudgetTemplate, error) {
+	var template models.BudgetTemplate
+	err := r.db.Preload("Lines").Where("id = ?", id).First(&template).Error
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// FindByUserID retrieves all templates a user saved, by name
+func (r *budgetTemplateRepository) FindByUserID(userID uuid.UUID) ([]*models.BudgetTemplate, error) {
+	var templates []*models.BudgetTemplate
+	err := r.db.Preload("Lines").
+		Where("user_id = ?", userID).
+		Order("name ASC").
+		Find(&templates).Error
+	return templates, err
+}
+
+// FindByUserIDAndName retrieves a user's template by name, ignoring case
+func (r *budgetTemplateRepository) FindByUserIDAndName(userID uuid.UUID, name string) (*models.BudgetTemplate, error) {
+	var template models.BudgetTemplate
+	err := r.db.Where("user_id = ? AND LOWER(name) = LOWER(?)", userID, name).First(&template).Error
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// Delete permanently removes a template and its lines
+func (r *budgetTemplateRepository) Delete(id uuid.UUID) error {
+	if err := r.db.Where("template_id = ?", id).Delete(&models.BudgetTemplateLine{}).Error; err != nil {
+		return err
+	}
+	return r.db.Delete(&models.BudgetTemplate{}, id).Error
+}
+
+// WithTx returns a repository bound to the given database transaction
+func (r *budgetTemplateRepository) WithTx(tx *gorm.DB) BudgetTemplateRepository {
+	return &budgetTemplateRepository{db: tx}
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// CategoryRepository defines the interface for category data operations.
+// Category names are matched case-insensitively.
+type CategoryRepository interface {
+	Create(category *models.Category) error
+	FindByID(id uuid.UUID) (*models.Category, error)
+	FindByUserID(userID uuid.UUID) ([]*models.Category, error)
+	FindByUserIDAndName(userID uuid.UUID, name string) (*models.Category, error)
+	FindUsed(userID uuid.UUID) ([]*CategoryUsage, error)
+	CountReferences(userID uuid.UUID, name string) (int64, error)
+	RenameReferences(userID uuid.UUID, from, to string) error
+	Update(category *models.Category) error
+	Delete(id uuid.UUID) error
+	WithTx(tx *gorm.DB) CategoryRepository
+}
+
+// CategoryUsage is a category name found on a user's records, with the kind
+// of records that use it
+type CategoryUsage struct {
+	Name string
+	Kind string
+}
+
+type categoryRepository struct {
+	db *gorm.DB
+}
+
+// NewCategoryRepository creates a new instance of CategoryRepository
+func NewCategoryRepository(db *gorm.DB) CategoryRepository {
+	return &categoryRepository{db: db}
+}
+
+// Create inserts a new category into the database
+func (r *categoryRepository) Create(category *models.Category) error {
+	return r.db.Omit("Children").Create(category).Error
+}
+
+// FindByID retrieves a category by its ID
+func (r *categoryRepository) FindByID(id uuid.UUID) (*models.Category, error) {
+	var category models.Category
+	err := r.db.Where("id = ?", id).First(&category).Error
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// FindByUserID retrieves all categories for a specific user, ordered by name
+func (r *categoryRepository) FindByUserID(userID uuid.UUID) ([]*models.Category, error) {
+	var categories []*models.Category
+	err := r.db.Where("user_id = ?", userID).
+		Order("LOWER(name) ASC").
+		Find(&categories).Error
+	return categories, err
+}
+
+// FindByUserIDAndName retrieves a user's category by name, ignoring case
+func (r *categoryRepository) FindByUserIDAndName(userID uuid.UUID, name string) (*models.Category, error) {
+	var category models.Category
+	err := r.db.Where("user_id = ? AND LOWER(name) = LOWER(?)", userID, name).First(&category).Error
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// FindUsed retrieves the distinct category names on a user's transactions,
+// split lines and budgets. A name counts as income only when nothing but
+// income uses it.
+func (r *categoryRepository) FindUsed(userID uuid.UUID) ([]*CategoryUsage, error) {
+	var used []*CategoryUsage
+	err := r.db.Raw(`
+		SELECT name, MIN(kind) AS kind FROM (
+			SELECT category AS name, CASE WHEN type = ? THEN ? ELSE ? END AS kind
+			FROM transactions
+			WHERE user_id = ? AND type <> ? AND deleted_at IS NULL
+			UNION ALL
+			SELECT s.category, CASE WHEN t.type = ? THEN ? ELSE ? END
+			FROM transaction_splits s JOIN transactions t ON t.id = s.transaction_id
+			WHERE t.user_id = ? AND t.deleted_at IS NULL
+			UNION ALL
+			SELECT category, ? FROM budgets
+			WHERE user_id = ? AND deleted_at IS NULL
+		) used
+		WHERE name <> ''
+		GROUP BY name
+		ORDER BY name`,
+		models.TransactionTypeIncome, models.CategoryKindIncome, models.CategoryKindExpense,
+		userID, models.TransactionTypeTransfer,
+		models.TransactionTypeIncome, models.CategoryKindIncome, models.CategoryKindExpense,
+		userID,
+		models.CategoryKindExpense, userID).
+		Scan(&used).Error
+	return used, err
+}
+
+// CountReferences counts the transactions, split lines, budgets and goals of
+// a user that use a category name
+func (r *categoryRepository) CountReferences(userID uuid.UUID, name string) (int64, error) {
+	var count int64
+	err := r.db.Raw(`
+		SELECT
+			(SELECT COUNT(*) FROM transactions
+				WHERE user_id = ? AND LOWER(category) = LOWER(?) AND deleted_at IS NULL) +
+			(SELECT COUNT(*) FROM transaction_splits s JOIN transactions t ON t.id = s.transaction_id
+				WHERE t.user_id = ? AND LOWER(s.category) = LOWER(?) AND t.deleted_at IS NULL) +
+			(SELECT COUNT(*) FROM budgets
+				WHERE user_id = ? AND LOWER(category) = LOWER(?) AND deleted_at IS NULL) +
+			(SELECT COUNT(*) FROM saving_goals
+				WHERE user_id = ? AND LOWER(category) = LOWER(?) AND deleted_at IS NULL)`,
+		userID, name, userID, name, userID, name, userID, name).
+		Scan(&count).Error
+	return count, err
+}
+
+// RenameReferences points a user's transactions, split lines, recurring
+// transactions, budgets and goals that use one category name at another.
+// Deleted records are renamed too, so they stay consistent when restored.
+func (r *categoryRepository) RenameReferences(userID uuid.UUID, from, to string) error {
+	for _, model := range []interface{}{
+		&models.Transaction{}, &models.RecurringTransaction{}, &models.Budget{}, &models.SavingGoal{},
+	} {
+		err := r.db.Unscoped().Model(model).
+			Where("user_id = ? AND LOWER(category) = LOWER(?)", userID, from).
+			Update("category", to).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return r.db.Model(&models.TransactionSplit{}).
+		Where("LOWER(category) = LOWER(?)", from).
+		Where("transaction_id IN (?)", r.db.Unscoped().Model(&models.Transaction{}).
+			Select("id").
+			Where("user_id = ?", userID)).
+		Update("category", to).Error
+}
+
+// Update modifies an existing category
+func (r *categoryRepository) Update(category *models.Category) error {
+	return r.db.Omit("Children").Save(category).Error
+}
+
+// Delete removes a category from the database
+func (r *categoryRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.Category{}, id).Error
+}
+
+// WithTx returns a repository bound to the given database transaction
+func (r *categoryRepository) WithTx(tx *gorm.DB) CategoryRepository {
+	return &categoryRepository{db: tx}
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"gorm.io/gorm"
+)
+
+// EnvelopeAllocationRepository defines the interface for envelope allocation data operations
+type EnvelopeAllocationRepository interface {
+	Create(allocations []*models.EnvelopeAllocation) error
+	Totals(userID uuid.UUID) ([]*EnvelopeAllocationTotal, error)
+	WithTx(tx *gorm.DB) EnvelopeAllocationRepository
+}
+
+// EnvelopeAllocationTotal sums the allocations of one kind made to an
+// envelope in one month
+type EnvelopeAllocationTotal struct {
+	BudgetID uuid.UUID
+	Month    time.Time
+	Kind     string
+	Total    money.Amount
+}
+
+type envelopeAllocationRepository struct {
+	db *gorm.DB
+}
+
+// NewEnvelopeAllocationRepository creates a new instance of EnvelopeAllocationRepository
+func NewEnvelopeAllocationRepository(db *gorm.DB) EnvelopeAllocationRepository {
+	return &envelopeAllocationRepository{db: db}
+}
+
+// Create inserts allocations together, so both sides of a move are saved or neither is
+func (r *envelopeAllocationRepository) Create(allocations []*models.EnvelopeAllocation) error {
+	if len(allocations) == 0 {
+		return nil
+	}
+	return r.db.Create(&allocations).Error
+}
+
+// Totals sums a user's allocations per envelope, month and kind. Allocations
+// to a budget that was deleted or is no longer an envelope are left out, which
+// releases the money they held.
+func (r *envelopeAllocationRepository) Totals(userID uuid.UUID) ([]*EnvelopeAllocationTotal, error) {
+	var totals []*EnvelopeAllocationTotal
+	err := r.db.Model(&models.EnvelopeAllocation{}).
+		Select("envelope_allocations.budget_id, envelope_allocations.month, envelope_allocations.kind, COALESCE(SUM(envelope_allocations.amount), 0) AS total").
+		Joins("JOIN budgets ON budgets.id = envelope_allocations.budget_id AND budgets.deleted_at IS NULL AND budgets.is_envelope").
+		Where("envelope_allocations.user_id = ?", userID).
+		Group("envelope_allocations.budget_id, envelope_allocations.month, envelope_allocations.kind").
+		Scan(&totals).Error
+	return totals, err
+}
+
+// WithTx returns a repository bound to the given database transaction
+func (r *envelopeAllocationRepository) WithTx(tx *gorm.DB) EnvelopeAllocationRepository {
+	return &envelopeAllocationRepository{db: tx}
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ExchangeRateRepository defines the interface for exchange rate data operations
+type ExchangeRateRepository interface {
+	Upsert(rates []*models.ExchangeRate) error
+	FindLatest(baseCurrency, quoteCurrency string, on time.Time) (*models.ExchangeRate, error)
+	List(baseCurrency, quoteCurrency string, limit, offset int) ([]*models.ExchangeRate, error)
+	Count(baseCurrency, quoteCurrency string) (int64, error)
+}
+
+type exchangeRateRepository struct {
+	db *gorm.DB
+}
+
+// NewExchangeRateRepository creates a new instance of ExchangeRateRepository
+func NewExchangeRateRepository(db *gorm.DB) ExchangeRateRepository {
+	return &exchangeRateRepository{db: db}
+}
+
+// Upsert inserts rates, replacing any existing rate for the same currency pair and day
+func (r *exchangeRateRepository) Upsert(rates []*models.ExchangeRate) error {
+	if len(rates) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "base_currency"}, {Name: "quote_currency"}, {Name: "rate_date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate", "source", "updated_at"}),
+	}).CreateInBatches(rates, 500).Error
+}
+
+// FindLatest retrieves the most recent rate for a currency pair quoted on or before the given day
+func (r *exchangeRateRepository) FindLatest(baseCurrency, quoteCurrency string, on time.Time) (*models.ExchangeRate, error) {
+	var rate models.ExchangeRate
+	err := r.db.Where("base_currency = ? AND quote_currency = ? AND rate_date <= ?",
+		baseCurrency, quoteCurrency, on.Format("2006-01-02")).
+		Order("rate_date DESC").
+		First(&rate).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+// List retrieves a page of rates, newest first. Empty currencies match any currency.
+func (r *exchangeRateRepository) List(baseCurrency, quoteCurrency string, limit, offset int) ([]*models.ExchangeRate, error) {
+	var rates []*models.ExchangeRate
+	err := r.filter(baseCurrency, quoteCurrency).
+		Order("rate_date DESC, base_currency, quote_currency").
+		Limit(limit).
+		Offset(offset).
+		Find(&rates).Error
+	return rates, err
+}
+
+// Count counts the rates matching the given currencies
+func (r *exchangeRateRepository) Count(baseCurrency, quoteCurrency string) (int64, error) {
+	var count int64
+	err := r.filter(baseCurrency, quoteCurrency).Model(&models.ExchangeRate{}).Count(&count).Error
+	return count, err
+}
+
+// filter narrows a query to a currency pair, ignoring empty currencies
+func (r *exchangeRateRepository) filter(baseCurrency, quoteCurrency string) *gorm.DB {
+	query := r.db
+	if baseCurrency != "" {
+		query = query.Where("base_currency = ?", baseCurrency)
+	}
+	if quoteCurrency != "" {
+		query = query.Where("quote_currency = ?", quoteCurrency)
+	}
+	return query
+}
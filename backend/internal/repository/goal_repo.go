@@ -1,8 +1,11 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
 	"gorm.io/gorm"
 )
 
@@ -14,7 +17,13 @@ type GoalRepository interface {
 	FindAll() ([]*models.SavingGoal, error)
 	Update(goal *models.SavingGoal) error
 	Delete(id uuid.UUID) error
-	UpdateProgress(id uuid.UUID, amount float64) error
+	UpdateProgress(id uuid.UUID, amount money.Amount) error
+	FindDeletedByUserID(userID uuid.UUID) ([]*models.SavingGoal, error)
+	FindDeletedByID(id uuid.UUID) (*models.SavingGoal, error)
+	FindDeletedBefore(cutoff time.Time, limit int) ([]*models.SavingGoal, error)
+	Restore(id uuid.UUID) error
+	Purge(id uuid.UUID) error
+	WithTx(tx *gorm.DB) GoalRepository
 }
 
 type goalRepository struct {
@@ -67,8 +76,58 @@ func (r *goalRepository) Delete(id uuid.UUID) error {
 }
 
 // UpdateProgress adds an amount to the goal's current progress
-func (r *goalRepository) UpdateProgress(id uuid.UUID, amount float64) error {
+func (r *goalRepository) UpdateProgress(id uuid.UUID, amount money.Amount) error {
 	return r.db.Model(&models.SavingGoal{}).
 	Where("id = ?", id).
 	UpdateColumn("current_amount", gorm.Expr("current_amount + ?", amount)).Error
 }
+
+// FindDeletedByUserID retrieves a user's soft-deleted goals, most recently deleted first
+func (r *goalRepository) FindDeletedByUserID(userID uuid.UUID) ([]*models.SavingGoal, error) {
+	var goals []*models.SavingGoal
+	err := r.db.Unscoped().
+		Where("user_id = ? AND deleted_at IS NOT NULL", userID).
+		Order("deleted_at DESC").
+		Find(&goals).Error
+	return goals, err
+}
+
+// FindDeletedByID retrieves a soft-deleted goal by its ID
+func (r *goalRepository) FindDeletedByID(id uuid.UUID) (*models.SavingGoal, error) {
+	var goal models.SavingGoal
+	err := r.db.Unscoped().
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		First(&goal).Error
+	if err != nil {
+		return nil, err
+	}
+	return &goal, nil
+}
+
+// FindDeletedBefore retrieves up to limit goals soft-deleted before cutoff, oldest first
+func (r *goalRepository) FindDeletedBefore(cutoff time.Time, limit int) ([]*models.SavingGoal, error) {
+	var goals []*models.SavingGoal
+	err := r.db.Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+		Order("deleted_at ASC").
+		Limit(limit).
+		Find(&goals).Error
+	return goals, err
+}
+
+// Restore undoes the soft delete of a goal
+func (r *goalRepository) Restore(id uuid.UUID) error {
+	return r.db.Unscoped().Model(&models.SavingGoal{}).
+		Where("id = ?", id).
+		Update("deleted_at", nil).Error
+}
+
+// Purge permanently deletes a goal
+func (r *goalRepository) Purge(id uuid.UUID) error {
+	return r.db.Unscoped().Delete(&models.SavingGoal{}, id).Error
+}
+
+// WithTx returns a repository bound to the given database transaction
+func (r *goalRepository) WithTx(tx *gorm.DB) GoalRepository {
+	return &goalRepository{db: tx}
+}
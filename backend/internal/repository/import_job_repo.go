@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// ImportJobRepository defines the interface for statement import data operations
+type ImportJobRepository interface {
+	Create(job *models.ImportJob) error
+	FindByID(id uuid.UUID) (*models.ImportJob, error)
+	FindByUserID(userID uuid.UUID, limit, offset int) ([]*models.ImportJob, error)
+	CountByUserID(userID uuid.UUID) (int64, error)
+	Update(job *models.ImportJob) error
+	UpdateRows(rows []*models.ImportRow) error
+	WithTx(tx *gorm.DB) ImportJobRepository
+}
+
+type importJobRepository struct {
+	db *gorm.DB
+}
+
+// NewImportJobRepository creates a new instance of ImportJobRepository
+func NewImportJobRepository(db *gorm.DB) ImportJobRepository {
+	return &importJobRepository{db: db}
+}
+
+// Create inserts a new import job together with its rows
+func (r *importJobRepository) Create(job *models.ImportJob) error {
+	return r.db.Omit("User", "Wallet").Create(job).Error
+}
+
+// FindByID retrieves an import job with its rows in file order
+func (r *importJobRepository) FindByID(id uuid.UUID) (*models.ImportJob, error) {
+	var job models.ImportJob
+	err := r.db.Preload("Rows", func(db *gorm.DB) *gorm.DB {
+		return db.Order("line ASC")
+	}).
+		Where("id = ?", id).
+		First(&job).Error
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// FindByUserID retrieves a page of a user's import jobs, newest first, without their rows
+func (r *importJobRepository) FindByUserID(userID uuid.UUID, limit, offset int) ([]*models.ImportJob, error) {
+	var jobs []*models.ImportJob
+	err := r.db.Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&jobs).Error
+	return jobs, err
+}
+
+// CountByUserID counts all import jobs for a specific user
+func (r *importJobRepository) CountByUserID(userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.ImportJob{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// Update modifies an existing import job, leaving its rows untouched
+func (r *importJobRepository) Update(job *models.ImportJob) error {
+	return r.db.Omit("User", "Wallet", "Rows").Save(job).Error
+}
+
+// UpdateRows saves changes to the rows of an import job
+func (r *importJobRepository) UpdateRows(rows []*models.ImportRow) error {
+	for _, row := range rows {
+		if err := r.db.Save(row).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WithTx returns a repository bound to the given database transaction
+func (r *importJobRepository) WithTx(tx *gorm.DB) ImportJobRepository {
+	return &importJobRepository{db: tx}
+}
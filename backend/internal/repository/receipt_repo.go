@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// ReceiptRepository defines the interface for receipt data operations
+type ReceiptRepository interface {
+	Create(receipt *models.Receipt) error
+	FindByID(id uuid.UUID) (*models.Receipt, error)
+	FindByTransactionID(transactionID uuid.UUID) ([]*models.Receipt, error)
+	MoveToTransaction(fromTransactionID, toTransactionID uuid.UUID) error
+	Delete(id uuid.UUID) error
+	WithTx(tx *gorm.DB) ReceiptRepository
+}
+
+type receiptRepository struct {
+	db *gorm.DB
+}
+
+// NewReceiptRepository creates a new instance of ReceiptRepository
+func NewReceiptRepository(db *gorm.DB) ReceiptRepository {
+	return &receiptRepository{db: db}
+}
+
+// Create inserts a new receipt
+func (r *receiptRepository) Create(receipt *models.Receipt) error {
+	return r.db.Create(receipt).Error
+}
+
+// FindByID retrieves a receipt by its ID
+func (r *receiptRepository) FindByID(id uuid.UUID) (*models.Receipt, error) {
+	var receipt models.Receipt
+	err := r.db.Where("id = ?", id).First(&receipt).Error
+	if err != nil {
+		return nil, err
+	}
+	return &receipt, nil
+}
+
+// FindByTransactionID retrieves a transaction's receipts in upload order
+func (r *receiptRepository) FindByTransactionID(transactionID uuid.UUID) ([]*models.Receipt, error) {
+	var receipts []*models.Receipt
+	err := r.db.Where("transaction_id = ?", transactionID).Order("created_at ASC").Find(&receipts).Error
+	return receipts, err
+}
+
+// MoveToTransaction moves every receipt of one transaction to another
+func (r *receiptRepository) MoveToTransaction(fromTransactionID, toTransactionID uuid.UUID) error {
+	return r.db.Model(&models.Receipt{}).
+		Where("transaction_id = ?", fromTransactionID).
+		Update("transaction_id", toTransactionID).Error
+}
+
+// Delete removes a receipt record. The stored files are removed by the caller.
+func (r *receiptRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.Receipt{}, id).Error
+}
+
+// WithTx returns a repository bound to the given database transaction
+func (r *receiptRepository) WithTx(tx *gorm.DB) ReceiptRepository {
+	return &receiptRepository{db: tx}
+}
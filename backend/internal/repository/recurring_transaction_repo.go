@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RecurringTransactionRepository defines the interface for recurring transaction data operations
+type RecurringTransactionRepository interface {
+	Create(recurring *models.RecurringTransaction) error
+	FindByID(id uuid.UUID) (*models.RecurringTransaction, error)
+	FindByIDForUpdate(id uuid.UUID) (*models.RecurringTransaction, error)
+	FindByUserID(userID uuid.UUID) ([]*models.RecurringTransaction, error)
+	FindDue(now time.Time, limit int) ([]*models.RecurringTransaction, error)
+	Update(recurring *models.RecurringTransaction) error
+	Delete(id uuid.UUID) error
+	WithTx(tx *gorm.DB) RecurringTransactionRepository
+}
+
+type recurringTransactionRepository struct {
+	db *gorm.DB
+}
+
+// NewRecurringTransactionRepository creates a new instance of RecurringTransactionRepository
+func NewRecurringTransactionRepository(db *gorm.DB) RecurringTransactionRepository {
+	return &recurringTransactionRepository{db: db}
+}
+
+// Create inserts a new recurring transaction into the database
+func (r *recurringTransactionRepository) Create(recurring *models.RecurringTransaction) error {
+	return r.db.Omit("User", "Wallet").Create(recurring).Error
+}
+
+// FindByID retrieves a recurring transaction by its ID
+func (r *recurringTransactionRepository) FindByID(id uuid.UUID) (*models.RecurringTransaction, error) {
+	var recurring models.RecurringTransaction
+	err := r.db.Where("id = ?", id).First(&recurring).Error
+	if err != nil {
+		return nil, err
+	}
+	return &recurring, nil
+}
+
+// FindByIDForUpdate retrieves a recurring transaction and locks its row until the
+// surrounding transaction ends. It must be called on a repository bound with WithTx.
+func (r *recurringTransactionRepository) FindByIDForUpdate(id uuid.UUID) (*models.RecurringTransaction, error) {
+	var recurring models.RecurringTransaction
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&recurring).Error
+	if err != nil {
+		return nil, err
+	}
+	return &recurring, nil
+}
+
+// FindByUserID retrieves all recurring transactions for a specific user, soonest first
+func (r *recurringTransactionRepository) FindByUserID(userID uuid.UUID) ([]*models.RecurringTransaction, error) {
+	var recurring []*models.RecurringTransaction
+	err := r.db.Where("user_id = ?", userID).
+		Order("next_occurrence ASC NULLS LAST, created_at DESC").
+		Find(&recurring).Error
+	return recurring, err
+}
+
+// FindDue retrieves active recurring transactions with an occurrence due by now, oldest first
+func (r *recurringTransactionRepository) FindDue(now time.Time, limit int) ([]*models.RecurringTransaction, error) {
+	var recurring []*models.RecurringTransaction
+	err := r.db.Where("is_active = ? AND next_occurrence IS NOT NULL AND next_occurrence <= ?", true, now).
+		Order("next_occurrence ASC").
+		Limit(limit).
+		Find(&recurring).Error
+	return recurring, err
+}
+
+// Update modifies an existing recurring transaction
+func (r *recurringTransactionRepository) Update(recurring *models.RecurringTransaction) error {
+	return r.db.Omit("User", "Wallet").Save(recurring).Error
+}
+
+// Delete soft deletes a recurring transaction, keeping the transactions it posted
+func (r *recurringTransactionRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.RecurringTransaction{}, id).Error
+}
+
+// WithTx returns a repository bound to the given database transaction
+func (r *recurringTransactionRepository) WithTx(tx *gorm.DB) RecurringTransactionRepository {
+	return &recurringTransactionRepository{db: tx}
+}
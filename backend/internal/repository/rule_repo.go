@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// RuleRepository defines the interface for categorization rule data operations
+type RuleRepository interface {
+	Create(rule *models.Rule) error
+	FindByID(id uuid.UUID) (*models.Rule, error)
+	FindByUserID(userID uuid.UUID) ([]*models.Rule, error)
+	FindActiveByUserID(userID uuid.UUID) ([]*models.Rule, error)
+	Update(rule *models.Rule) error
+	Delete(id uuid.UUID) error
+	WithTx(tx *gorm.DB) RuleRepository
+}
+
+type ruleRepository struct {
+	db *gorm.DB
+}
+
+// NewRuleRepository creates a new instance of RuleRepository
+func NewRuleRepository(db *gorm.DB) RuleRepository {
+	return &ruleRepository{db: db}
+}
+
+// Create inserts a new rule together with the tags it adds
+func (r *ruleRepository) Create(rule *models.Rule) error {
+	return r.db.Create(rule).Error
+}
+
+// FindByID retrieves a rule by its ID
+func (r *ruleRepository) FindByID(id uuid.UUID) (*models.Rule, error) {
+	var rule models.Rule
+	err := r.db.Preload("AddTags").Where("id = ?", id).First(&rule).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// FindByUserID retrieves all rules for a specific user in the order they run
+func (r *ruleRepository) FindByUserID(userID uuid.UUID) ([]*models.Rule, error) {
+	var rules []*models.Rule
+	err := r.db.Preload("AddTags").
+		Where("user_id = ?", userID).
+		Order("priority ASC, created_at ASC").
+		Find(&rules).Error
+	return rules, err
+}
+
+// FindActiveByUserID retrieves a user's active rules in the order they run
+func (r *ruleRepository) FindActiveByUserID(userID uuid.UUID) ([]*models.Rule, error) {
+	var rules []*models.Rule
+	err := r.db.Preload("AddTags").
+		Where("user_id = ? AND is_active = ?", userID, true).
+		Order("priority ASC, created_at ASC").
+		Find(&rules).Error
+	return rules, err
+}
+
+// Update modifies an existing rule and replaces the tags it adds
+func (r *ruleRepository) Update(rule *models.Rule) error {
+	if err := r.db.Omit("AddTags").Save(rule).Error; err != nil {
+		return err
+	}
+	return r.db.Model(rule).Association("AddTags").Replace(rule.AddTags)
+}
+
+// Delete removes a rule from the database (soft delete)
+func (r *ruleRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.Rule{}, id).Error
+}
+
+// WithTx returns a repository bound to the given database transaction
+func (r *ruleRepository) WithTx(tx *gorm.DB) RuleRepository {
+	return &ruleRepository{db: tx}
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// TagRepository defines the interface for tag data operations. Tag names are
+// matched case-insensitively.
+type TagRepository interface {
+	Create(tag *models.Tag) error
+	FindByID(id uuid.UUID) (*models.Tag, error)
+	FindByUserID(userID uuid.UUID) ([]*models.Tag, error)
+	FindByUserIDAndName(userID uuid.UUID, name string) (*models.Tag, error)
+	FindOrCreate(userID uuid.UUID, names []string) ([]models.Tag, error)
+	Update(tag *models.Tag) error
+	Delete(id uuid.UUID) error
+	WithTx(tx *gorm.DB) TagRepository
+}
+
+type tagRepository struct {
+	db *gorm.DB
+}
+
+// NewTagRepository creates a new instance of TagRepository
+func NewTagRepository(db *gorm.DB) TagRepository {
+	return &tagRepository{db: db}
+}
+
+// Create inserts a new tag
+func (r *tagRepository) Create(tag *models.Tag) error {
+	return r.db.Create(tag).Error
+}
+
+// FindByID retrieves a tag by its ID
+func (r *tagRepository) FindByID(id uuid.UUID) (*models.Tag, error) {
+	var tag models.Tag
+	err := r.db.Where("id = ?", id).First(&tag).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// FindByUserID retrieves all tags for a specific user ordered by name
+func (r *tagRepository) FindByUserID(userID uuid.UUID) ([]*models.Tag, error) {
+	var tags []*models.Tag
+	err := r.db.Where("user_id = ?", userID).Order("LOWER(name) ASC").Find(&tags).Error
+	return tags, err
+}
+
+// FindByUserIDAndName retrieves a user's tag by name, ignoring case
+func (r *tagRepository) FindByUserIDAndName(userID uuid.UUID, name string) (*models.Tag, error) {
+	var tag models.Tag
+	err := r.db.Where("user_id = ? AND LOWER(name) = LOWER(?)", userID, name).First(&tag).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// FindOrCreate retrieves a user's tags by name, creating the ones that do not
+// exist yet. Names repeated in another case resolve to the same tag.
+func (r *tagRepository) FindOrCreate(userID uuid.UUID, names []string) ([]models.Tag, error) {
+	tags := make([]models.Tag, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[strings.ToLower(name)] {
+			continue
+		}
+		seen[strings.ToLower(name)] = true
+
+		tag, err := r.FindByUserIDAndName(userID, name)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			tag = &models.Tag{UserID: userID, Name: name}
+			err = r.db.Create(tag).Error
+		}
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, *tag)
+	}
+	return tags, nil
+}
+
+// Update modifies an existing tag
+func (r *tagRepository) Update(tag *models.Tag) error {
+	return r.db.Save(tag).Error
+}
+
+// Delete removes a tag and detaches it from transactions and rules
+func (r *tagRepository) Delete(id uuid.UUID) error {
+	if err := r.db.Exec("DELETE FROM transaction_tags WHERE tag_id = ?", id).Error; err != nil {
+		return err
+	}
+	if err := r.db.Exec("DELETE FROM rule_tags WHERE tag_id = ?", id).Error; err != nil {
+		return err
+	}
+	return r.db.Delete(&models.Tag{}, id).Error
+}
+
+// WithTx returns a repository bound to the given database transaction
+func (r *tagRepository) WithTx(tx *gorm.DB) TagRepository {
+	return &tagRepository{db: tx}
+}
@@ -1,20 +1,134 @@
 package repository
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // TransactionRepository defines the interface for transaction data operations
 type TransactionRepository interface {
 	Create(transaction *models.Transaction) error
+	CreateBatch(transactions []*models.Transaction) error
 	FindByID(id uuid.UUID) (*models.Transaction, error)
+	FindByIDForUpdate(id uuid.UUID) (*models.Transaction, error)
 	FindByUserID(userID uuid.UUID, limit, offset int) ([]*models.Transaction, error)
 	CountByUserID(userID uuid.UUID) (int64, error)
+	FindByFilter(filter TransactionFilter, sort TransactionSort, limit, offset int) ([]*models.Transaction, error)
+	CountByFilter(filter TransactionFilter) (int64, error)
+	FindByCursor(filter TransactionFilter, cursor TransactionCursor, limit int) ([]*models.Transaction, error)
+	Aggregate(filter TransactionFilter, groupBy ...TransactionGroup) ([]*TransactionAggregate, error)
 	FindAll() ([]*models.Transaction, error)
 	Update(transaction *models.Transaction) error
+	UpdateColumns(id uuid.UUID, columns map[string]interface{}) error
 	Delete(id uuid.UUID) error
+	DeleteByImportJobID(jobID uuid.UUID) error
+	ReplaceSplits(transactionID uuid.UUID, splits []models.TransactionSplit) error
+	AddTags(transactionID uuid.UUID, tags []models.Tag) error
+	ReplaceTags(transactionID uuid.UUID, tags []models.Tag) error
+	RemoveTags(transactionID uuid.UUID, tags []models.Tag) error
+	FindDeletedByUserID(userID uuid.UUID) ([]*models.Transaction, error)
+	FindDeletedByID(id uuid.UUID) (*models.Transaction, error)
+	FindDeletedBefore(cutoff time.Time, limit int) ([]*models.Transaction, error)
+	Restore(id uuid.UUID) (bool, error)
+	Purge(id uuid.UUID) error
+	WithTx(tx *gorm.DB) TransactionRepository
+}
+
+// TransactionFilter selects the transactions a query or aggregate covers. Zero
+// values leave a criterion unset. StartDate is inclusive and EndDate exclusive.
+type TransactionFilter struct {
+	UserID     uuid.UUID
+	StartDate  time.Time
+	EndDate    time.Time
+	Categories []string
+	WalletIDs  []uuid.UUID
+	Statuses   []string
+	Types      []string
+	Methods    []string
+	MinAmount  *money.Amount
+	MaxAmount  *money.Amount
+	// Search matches a case-insensitive substring of the name or notes
+	Search      string
+	ExternalIDs []string
+	ImportJobID *uuid.UUID
+	// TagIDs matches transactions carrying any of the tags
+	TagIDs []uuid.UUID
+}
+
+// Fields transactions can be sorted by
+const (
+	SortByDate      = "transaction_date"
+	SortByAmount    = "amount"
+	SortByName      = "name"
+	SortByCategory  = "category"
+	SortByCreatedAt = "created_at"
+)
+
+// TransactionSort orders a transaction listing. The zero value lists the
+// newest transactions first. Ties are broken by ID so pages are stable.
+type TransactionSort struct {
+	Field     string
+	Ascending bool
+}
+
+// IsValidSortField reports whether transactions can be sorted by field
+func IsValidSortField(field string) bool {
+	switch field {
+	case SortByDate, SortByAmount, SortByName, SortByCategory, SortByCreatedAt:
+		return true
+	}
+	return false
+}
+
+// TransactionCursor is a keyset position in a listing ordered by
+// (transaction_date, id). Unlike an offset it stays put when transactions are
+// added or removed while a user pages through the list.
+type TransactionCursor struct {
+	// TransactionDate and ID identify the row the page starts after. A zero
+	// ID starts from the beginning (or, with Backward, the end) of the list.
+	TransactionDate time.Time
+	ID              uuid.UUID
+	// Ascending lists the oldest transactions first
+	Ascending bool
+	// Backward returns the rows that precede the position instead
+	Backward bool
+}
+
+// TransactionGroup is a dimension transaction aggregates can be grouped by
+type TransactionGroup string
+
+// Supported aggregate groupings
+const (
+	GroupByType     TransactionGroup = "type"
+	GroupByCategory TransactionGroup = "category"
+	GroupByWallet   TransactionGroup = "wallet"
+	GroupByDay      TransactionGroup = "day"
+	GroupByMonth    TransactionGroup = "month"
+	GroupByTag      TransactionGroup = "tag"
+)
+
+// TransactionAggregate is one row of a grouped SUM/COUNT over transactions.
+// Only the fields of the requested groupings are set. Period holds the day
+// ("2006-01-02") or month ("2006-01") when grouping by date. When grouping or
+// filtering by category, split transactions contribute each split line to its
+// own category and count once in every category they touch. When grouping by
+// tag, a transaction counts in full under each of its tags and untagged
+// transactions are left out.
+type TransactionAggregate struct {
+	Type     string
+	Category string
+	WalletID *uuid.UUID
+	TagID    *uuid.UUID
+	Period   string
+	Total    money.Amount
+	Count    int64
 }
 
 type transactionRepository struct {
@@ -29,9 +143,31 @@ func (r *transactionRepository) Create(transaction *models.Transaction) error {
 	return r.db.Create(transaction).Error
 }
 
+// CreateBatch inserts several transactions in as few statements as possible
+func (r *transactionRepository) CreateBatch(transactions []*models.Transaction) error {
+	if len(transactions) == 0 {
+		return nil
+	}
+	return r.db.CreateInBatches(transactions, 500).Error
+}
+
 func (r *transactionRepository) FindByID(id uuid.UUID) (*models.Transaction, error) {
 	var transaction models.Transaction
-	err := r.db.Where("id = ?", id).First(&transaction).Error
+	err := r.db.Preload("Splits").Preload("Tags").Preload("Receipts").Where("id = ?", id).First(&transaction).Error
+	if err != nil {
+		return nil, err
+	}
+	return &transaction, nil
+}
+
+// FindByIDForUpdate retrieves a transaction and locks its row until the
+// surrounding transaction ends. It must be called on a repository bound with WithTx.
+func (r *transactionRepository) FindByIDForUpdate(id uuid.UUID) (*models.Transaction, error) {
+	var transaction models.Transaction
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Splits").Preload("Tags").Preload("Receipts").
+		Where("id = ?", id).
+		First(&transaction).Error
 	if err != nil {
 		return nil, err
 	}
@@ -40,8 +176,9 @@ func (r *transactionRepository) FindByID(id uuid.UUID) (*models.Transaction, err
 
 func (r *transactionRepository) FindByUserID(userID uuid.UUID, limit, offset int) ([]*models.Transaction, error) {
 	var transactions []*models.Transaction
-	err := r.db.Where("user_id = ?", userID).
-		Order("transaction_date DESC").
+	err := r.db.Preload("Splits").Preload("Tags").
+		Where("user_id = ?", userID).
+		Order("transaction_date DESC, id DESC").
 		Limit(limit).
 		Offset(offset).
 		Find(&transactions).Error
@@ -57,17 +194,314 @@ func (r *transactionRepository) CountByUserID(userID uuid.UUID) (int64, error) {
 	return count, err
 }
 
+// FindByFilter retrieves a page of transactions matching the filter in the given order
+func (r *transactionRepository) FindByFilter(filter TransactionFilter, sort TransactionSort, limit, offset int) ([]*models.Transaction, error) {
+	field := sort.Field
+	if !IsValidSortField(field) {
+		field = SortByDate
+	}
+	direction := "DESC"
+	if sort.Ascending {
+		direction = "ASC"
+	}
+
+	var transactions []*models.Transaction
+	err := r.applyFilter(r.db.Preload("Splits").Preload("Tags"), filter).
+		Order(fmt.Sprintf("%s %s, id %s", field, direction, direction)).
+		Limit(limit).
+		Offset(offset).
+		Find(&transactions).Error
+	if err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+// FindByCursor retrieves up to limit transactions matching the filter that
+// follow the cursor position, always returned in the listing's order
+func (r *transactionRepository) FindByCursor(filter TransactionFilter, cursor TransactionCursor, limit int) ([]*models.Transaction, error) {
+	// Walking backwards reads the rows before the position in reverse order
+	ascending := cursor.Ascending != cursor.Backward
+	direction, comparison := "DESC", "<"
+	if ascending {
+		direction, comparison = "ASC", ">"
+	}
+
+	query := r.applyFilter(r.db.Preload("Splits").Preload("Tags"), filter)
+	if cursor.ID != uuid.Nil {
+		query = query.Where(fmt.Sprintf("(transaction_date, id) %s (?, ?)", comparison), cursor.TransactionDate, cursor.ID)
+	}
+
+	var transactions []*models.Transaction
+	err := query.
+		Order(fmt.Sprintf("transaction_date %s, id %s", direction, direction)).
+		Limit(limit).
+		Find(&transactions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if cursor.Backward {
+		for i, j := 0, len(transactions)-1; i < j; i, j = i+1, j-1 {
+			transactions[i], transactions[j] = transactions[j], transactions[i]
+		}
+	}
+	return transactions, nil
+}
+
+// CountByFilter counts the transactions matching the filter
+func (r *transactionRepository) CountByFilter(filter TransactionFilter) (int64, error) {
+	var count int64
+	err := r.applyFilter(r.db.Model(&models.Transaction{}), filter).Count(&count).Error
+	return count, err
+}
+
+// Aggregate sums and counts the transactions matching the filter in the
+// database, with one row per combination of the requested groupings
+func (r *transactionRepository) Aggregate(filter TransactionFilter, groupBy ...TransactionGroup) ([]*TransactionAggregate, error) {
+	// Category totals are taken over split lines rather than whole transactions
+	byCategory := len(filter.Categories) > 0
+	byTag := false
+	var columns, groups []string
+	for _, group := range groupBy {
+		switch group {
+		case GroupByType:
+			columns, groups = append(columns, "type"), append(groups, "type")
+		case GroupByCategory:
+			byCategory = true
+			columns, groups = append(columns, "category"), append(groups, "category")
+		case GroupByWallet:
+			columns, groups = append(columns, "wallet_id"), append(groups, "wallet_id")
+		case GroupByDay:
+			columns = append(columns, "TO_CHAR(transaction_date, 'YYYY-MM-DD') AS period")
+			groups = append(groups, "TO_CHAR(transaction_date, 'YYYY-MM-DD')")
+		case GroupByMonth:
+			columns = append(columns, "TO_CHAR(transaction_date, 'YYYY-MM') AS period")
+			groups = append(groups, "TO_CHAR(transaction_date, 'YYYY-MM')")
+		case GroupByTag:
+			byTag = true
+			columns, groups = append(columns, "tt.tag_id"), append(groups, "tt.tag_id")
+		default:
+			return nil, fmt.Errorf("unsupported transaction grouping %q", group)
+		}
+	}
+	query := r.db.Model(&models.Transaction{})
+	if byCategory {
+		columns = append(columns, "COALESCE(SUM(amount), 0) AS total", "COUNT(DISTINCT id) AS count")
+		query = query.Table("(?) AS transactions", r.categoryLines())
+	} else {
+		columns = append(columns, "COALESCE(SUM(amount), 0) AS total", "COUNT(*) AS count")
+	}
+	if byTag {
+		query = query.Joins("JOIN transaction_tags tt ON tt.transaction_id = transactions.id")
+	}
+
+	query = r.applyFilter(query, filter).
+		Select(strings.Join(columns, ", "))
+	if len(groups) > 0 {
+		query = query.Group(strings.Join(groups, ", "))
+	}
+
+	var aggregates []*TransactionAggregate
+	if err := query.Scan(&aggregates).Error; err != nil {
+		return nil, err
+	}
+	return aggregates, nil
+}
+
+// categoryLines selects one row per split line of split transactions and one
+// row per unsplit transaction, with the columns filters and aggregates use.
+// The line's own category and amount replace those of its transaction.
+func (r *transactionRepository) categoryLines() *gorm.DB {
+	return r.db.Table("transactions AS t").
+		Select(`t.id, t.user_id, t.wallet_id, t.type, t.status, t.method, t.name, t.notes,
+			t.external_id, t.import_job_id, t.transaction_date, t.deleted_at,
+			COALESCE(s.category, t.category) AS category, COALESCE(s.amount, t.amount) AS amount`).
+		Joins("LEFT JOIN transaction_splits s ON s.transaction_id = t.id")
+}
+
+// lowerAll lowercases category names for case-insensitive matching
+func lowerAll(names []string) []string {
+	lowered := make([]string, len(names))
+	for i, name := range names {
+		lowered[i] = strings.ToLower(name)
+	}
+	return lowered
+}
+
+// applyFilter adds the filter's conditions to a query
+func (r *transactionRepository) applyFilter(query *gorm.DB, filter TransactionFilter) *gorm.DB {
+	if filter.UserID != uuid.Nil {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if !filter.StartDate.IsZero() {
+		query = query.Where("transaction_date >= ?", filter.StartDate)
+	}
+	if !filter.EndDate.IsZero() {
+		query = query.Where("transaction_date < ?", filter.EndDate)
+	}
+	if len(filter.Categories) > 0 {
+		query = query.Where("LOWER(category) IN ?", lowerAll(filter.Categories))
+	}
+	if len(filter.WalletIDs) > 0 {
+		query = query.Where("wallet_id IN ?", filter.WalletIDs)
+	}
+	if len(filter.Statuses) > 0 {
+		query = query.Where("status IN ?", filter.Statuses)
+	}
+	if len(filter.Types) > 0 {
+		query = query.Where("type IN ?", filter.Types)
+	}
+	if len(filter.Methods) > 0 {
+		query = query.Where("method IN ?", filter.Methods)
+	}
+	if filter.MinAmount != nil {
+		query = query.Where("amount >= ?", *filter.MinAmount)
+	}
+	if filter.MaxAmount != nil {
+		query = query.Where("amount <= ?", *filter.MaxAmount)
+	}
+	if len(filter.ExternalIDs) > 0 {
+		query = query.Where("external_id IN ?", filter.ExternalIDs)
+	}
+	if filter.ImportJobID != nil {
+		query = query.Where("import_job_id = ?", *filter.ImportJobID)
+	}
+	if len(filter.TagIDs) > 0 {
+		query = query.Where("id IN (SELECT transaction_id FROM transaction_tags WHERE tag_id IN ?)", filter.TagIDs)
+	}
+	if filter.Search != "" {
+		pattern := "%" + likeEscaper.Replace(filter.Search) + "%"
+		query = query.Where("(name ILIKE ? OR notes ILIKE ?)", pattern, pattern)
+	}
+	return query
+}
+
+// likeEscaper escapes the LIKE wildcards in user input so they match literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 func (r *transactionRepository) FindAll() ([]*models.Transaction, error) {
 	var transactions []*models.Transaction
 	err := r.db.Find(&transactions).Error
 	return transactions, err
 }
 
+// Update saves a transaction; its splits only change through ReplaceSplits
 func (r *transactionRepository) Update(transaction *models.Transaction) error {
-	return r.db.Save(transaction).Error
+	return r.db.Omit("Splits", "Tags", "Receipts").Save(transaction).Error
+}
+
+// UpdateColumns writes only the given columns of a transaction, leaving any
+// other column as it is stored
+func (r *transactionRepository) UpdateColumns(id uuid.UUID, columns map[string]interface{}) error {
+	return r.db.Model(&models.Transaction{}).Where("id = ?", id).Updates(columns).Error
 }
 
 func (r *transactionRepository) Delete(id uuid.UUID) error {
 	return r.db.Delete(&models.Transaction{}, id).Error
 }
 
+// DeleteByImportJobID deletes every transaction created by an import job
+func (r *transactionRepository) DeleteByImportJobID(jobID uuid.UUID) error {
+	return r.db.Where("import_job_id = ?", jobID).Delete(&models.Transaction{}).Error
+}
+
+// ReplaceSplits replaces all split lines of a transaction; no splits leaves it unsplit
+func (r *transactionRepository) ReplaceSplits(transactionID uuid.UUID, splits []models.TransactionSplit) error {
+	if err := r.db.Where("transaction_id = ?", transactionID).Delete(&models.TransactionSplit{}).Error; err != nil {
+		return err
+	}
+	if len(splits) == 0 {
+		return nil
+	}
+	for i := range splits {
+		splits[i].ID = uuid.Nil
+		splits[i].TransactionID = transactionID
+	}
+	return r.db.Create(&splits).Error
+}
+
+// AddTags attaches tags to a transaction, keeping the tags it already has
+func (r *transactionRepository) AddTags(transactionID uuid.UUID, tags []models.Tag) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	return r.db.Model(&models.Transaction{ID: transactionID}).Association("Tags").Append(tags)
+}
+
+// ReplaceTags sets the tags of a transaction, detaching any others
+func (r *transactionRepository) ReplaceTags(transactionID uuid.UUID, tags []models.Tag) error {
+	return r.db.Model(&models.Transaction{ID: transactionID}).Association("Tags").Replace(tags)
+}
+
+// RemoveTags detaches tags from a transaction, keeping its other tags
+func (r *transactionRepository) RemoveTags(transactionID uuid.UUID, tags []models.Tag) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	return r.db.Model(&models.Transaction{ID: transactionID}).Association("Tags").Delete(tags)
+}
+
+// FindDeletedByUserID retrieves a user's soft-deleted transactions, most
+// recently deleted first. Transfer legs are left out since they only change
+// through their transfer, and so are rows of an undone import, which stay
+// deleted with their import.
+func (r *transactionRepository) FindDeletedByUserID(userID uuid.UUID) ([]*models.Transaction, error) {
+	var transactions []*models.Transaction
+	err := r.db.Unscoped().
+		Where("user_id = ? AND deleted_at IS NOT NULL AND transfer_id IS NULL", userID).
+		Where("import_job_id IS NULL OR import_job_id NOT IN (?)",
+			r.db.Model(&models.ImportJob{}).Select("id").Where("status = ?", models.ImportStatusUndone)).
+		Order("deleted_at DESC").
+		Find(&transactions).Error
+	return transactions, err
+}
+
+// FindDeletedByID retrieves a soft-deleted transaction by its ID
+func (r *transactionRepository) FindDeletedByID(id uuid.UUID) (*models.Transaction, error) {
+	var transaction models.Transaction
+	err := r.db.Unscoped().
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		First(&transaction).Error
+	if err != nil {
+		return nil, err
+	}
+	return &transaction, nil
+}
+
+// FindDeletedBefore retrieves up to limit transactions soft-deleted before
+// cutoff, oldest first. Transfer legs are never returned.
+func (r *transactionRepository) FindDeletedBefore(cutoff time.Time, limit int) ([]*models.Transaction, error) {
+	var transactions []*models.Transaction
+	err := r.db.Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ? AND transfer_id IS NULL", cutoff).
+		Order("deleted_at ASC").
+		Limit(limit).
+		Find(&transactions).Error
+	return transactions, err
+}
+
+// Restore undoes the soft delete of a transaction and reports whether it was
+// still deleted, so that of two concurrent restores only one succeeds
+func (r *transactionRepository) Restore(id uuid.UUID) (bool, error) {
+	result := r.db.Unscoped().Model(&models.Transaction{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	return result.RowsAffected == 1, result.Error
+}
+
+// Purge permanently deletes a transaction together with its splits and tag links
+func (r *transactionRepository) Purge(id uuid.UUID) error {
+	if err := r.db.Where("transaction_id = ?", id).Delete(&models.TransactionSplit{}).Error; err != nil {
+		return err
+	}
+	if err := r.db.Exec("DELETE FROM transaction_tags WHERE transaction_id = ?", id).Error; err != nil {
+		return err
+	}
+	return r.db.Unscoped().Delete(&models.Transaction{}, id).Error
+}
+
+// WithTx returns a repository bound to the given database transaction
+func (r *transactionRepository) WithTx(tx *gorm.DB) TransactionRepository {
+	return &transactionRepository{db: tx}
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TransferRepository defines the interface for wallet transfer data operations
+type TransferRepository interface {
+	Create(transfer *models.Transfer) error
+	FindByID(id uuid.UUID) (*models.Transfer, error)
+	FindByIDForUpdate(id uuid.UUID) (*models.Transfer, error)
+	FindByUserID(userID uuid.UUID, limit, offset int) ([]*models.Transfer, error)
+	CountByUserID(userID uuid.UUID) (int64, error)
+	Update(transfer *models.Transfer) error
+	WithTx(tx *gorm.DB) TransferRepository
+}
+
+type transferRepository struct {
+	db *gorm.DB
+}
+
+// NewTransferRepository creates a new instance of TransferRepository
+func NewTransferRepository(db *gorm.DB) TransferRepository {
+	return &transferRepository{db: db}
+}
+
+// Create inserts a new transfer into the database
+func (r *transferRepository) Create(transfer *models.Transfer) error {
+	return r.db.Create(transfer).Error
+}
+
+// FindByID retrieves a transfer by its ID along with both wallets
+func (r *transferRepository) FindByID(id uuid.UUID) (*models.Transfer, error) {
+	var transfer models.Transfer
+	err := r.db.Preload("FromWallet").Preload("ToWallet").
+		Where("id = ?", id).
+		First(&transfer).Error
+	if err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+// FindByIDForUpdate retrieves a transfer without its wallets and locks its row
+// until the surrounding transaction ends. It must be called on a repository
+// bound with WithTx.
+func (r *transferRepository) FindByIDForUpdate(id uuid.UUID) (*models.Transfer, error) {
+	var transfer models.Transfer
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&transfer).Error
+	if err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+// FindByUserID retrieves a page of transfers for a specific user, newest first
+func (r *transferRepository) FindByUserID(userID uuid.UUID, limit, offset int) ([]*models.Transfer, error) {
+	var transfers []*models.Transfer
+	err := r.db.Preload("FromWallet").Preload("ToWallet").
+		Where("user_id = ?", userID).
+		Order("transfer_date DESC, created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&transfers).Error
+	return transfers, err
+}
+
+// CountByUserID counts all transfers for a specific user
+func (r *transferRepository) CountByUserID(userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Transfer{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// Update modifies an existing transfer
+func (r *transferRepository) Update(transfer *models.Transfer) error {
+	return r.db.Omit("FromWallet", "ToWallet", "User").Save(transfer).Error
+}
+
+// WithTx returns a repository bound to the given database transaction
+func (r *transferRepository) WithTx(tx *gorm.DB) TransferRepository {
+	return &transferRepository{db: tx}
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+)
+
+// TxManager defines the interface for running work inside a database transaction
+type TxManager interface {
+	WithinTransaction(fn func(tx *gorm.DB) error) error
+	WithTx(tx *gorm.DB) TxManager
+}
+
+type txManager struct {
+	db *gorm.DB
+}
+
+// NewTxManager creates a new instance of TxManager
+func NewTxManager(db *gorm.DB) TxManager {
+	return &txManager{db: db}
+}
+
+// WithinTransaction runs fn in a transaction, committing if it returns nil
+// and rolling back otherwise. Repositories bound with WithTx(tx) inside fn
+// take part in the same transaction.
+func (m *txManager) WithinTransaction(fn func(tx *gorm.DB) error) error {
+	return m.db.Transaction(fn)
+}
+
+// WithTx returns a manager bound to the given database transaction. Work it
+// runs nests inside that transaction as a savepoint.
+func (m *txManager) WithTx(tx *gorm.DB) TxManager {
+	return &txManager{db: tx}
+}
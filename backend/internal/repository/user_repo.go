@@ -3,17 +3,22 @@ package repository
 import (
 	"github.com/google/uuid"
 	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // UserRepository defines the interface for user data operations
 type UserRepository interface {
 	Create(user *models.User) error
 	FindByID(id uuid.UUID) (*models.User, error)
+	FindByIDForUpdate(id uuid.UUID) (*models.User, error)
 	FindByEmail(email string) (*models.User, error)
 	FindAll() ([]*models.User, error)
 	Update(user *models.User) error
+	UpdateMonthlyIncome(id uuid.UUID, income money.Amount) error
 	Delete(id uuid.UUID) error
+	WithTx(tx *gorm.DB) UserRepository
 }
 
 type userRepository struct {
@@ -37,6 +42,19 @@ func (r *userRepository) FindByID(id uuid.UUID) (*models.User, error) {
 	return &user, nil
 }
 
+// FindByIDForUpdate retrieves a user and locks their row until the surrounding
+// transaction ends. It must be called on a repository bound with WithTx.
+func (r *userRepository) FindByIDForUpdate(id uuid.UUID) (*models.User, error) {
+	var user models.User
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
 func (r *userRepository) FindByEmail(email string) (*models.User, error) {
 	var user models.User
 	err := r.db.Where("email = ?", email).First(&user).Error
@@ -56,6 +74,18 @@ func (r *userRepository) Update(user *models.User) error {
 	return r.db.Save(user).Error
 }
 
+// UpdateMonthlyIncome sets a user's monthly income, leaving their other fields alone
+func (r *userRepository) UpdateMonthlyIncome(id uuid.UUID, income money.Amount) error {
+	return r.db.Model(&models.User{}).
+		Where("id = ?", id).
+		Update("monthly_income", income).Error
+}
+
 func (r *userRepository) Delete(id uuid.UUID) error {
 	return r.db.Delete(&models.User{}, id).Error
 }
+
+// WithTx returns a repository bound to the given database transaction
+func (r *userRepository) WithTx(tx *gorm.DB) UserRepository {
+	return &userRepository{db: tx}
+}
@@ -1,21 +1,34 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // WalletRepository defines the interface for wallet data operations
 type WalletRepository interface {
 	Create(wallet *models.Wallet) error
 	FindByID(id uuid.UUID) (*models.Wallet, error)
+	FindByIDForUpdate(id uuid.UUID) (*models.Wallet, error)
 	FindByUserID(userID uuid.UUID) ([]*models.Wallet, error)
 	FindDefaultByUserID(userID uuid.UUID) (*models.Wallet, error)
 	FindAll() ([]*models.Wallet, error)
 	Update(wallet *models.Wallet) error
+	UpdateColumns(id uuid.UUID, columns map[string]interface{}) error
 	Delete(id uuid.UUID) error
-	UpdateBalance(id uuid.UUID, amount float64) error
+	UpdateBalance(id uuid.UUID, amount money.Amount) error
+	FindDeletedByUserID(userID uuid.UUID) ([]*models.Wallet, error)
+	FindDeletedByID(id uuid.UUID) (*models.Wallet, error)
+	FindDeletedBefore(cutoff time.Time, limit int) ([]*models.Wallet, error)
+	Restore(id uuid.UUID) error
+	Purge(id uuid.UUID) error
+	CountReferences(id uuid.UUID) (int64, error)
+	WithTx(tx *gorm.DB) WalletRepository
 }
 
 type walletRepository struct {
@@ -42,6 +55,19 @@ func (r *walletRepository) FindByID(id uuid.UUID) (*models.Wallet, error) {
 	return &wallet, nil
 }
 
+// FindByIDForUpdate retrieves a wallet and locks its row until the surrounding
+// transaction ends. It must be called on a repository bound with WithTx.
+func (r *walletRepository) FindByIDForUpdate(id uuid.UUID) (*models.Wallet, error) {
+	var wallet models.Wallet
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&wallet).Error
+	if err != nil {
+		return nil, err
+	}
+	return &wallet, nil
+}
+
 // FindByUserID retrieves all wallets for a specific user
 func (r *walletRepository) FindByUserID(userID uuid.UUID) ([]*models.Wallet, error) {
 	var wallets []*models.Wallet
@@ -73,15 +99,92 @@ func (r *walletRepository) Update(wallet *models.Wallet) error {
 	return r.db.Save(wallet).Error
 }
 
+// UpdateColumns writes only the given columns of a wallet, leaving any other
+// column, the balance in particular, as it is stored
+func (r *walletRepository) UpdateColumns(id uuid.UUID, columns map[string]interface{}) error {
+	return r.db.Model(&models.Wallet{}).Where("id = ?", id).Updates(columns).Error
+}
+
 // Delete removes a wallet from the database (soft delete)
 func (r *walletRepository) Delete(id uuid.UUID) error {
 	return r.db.Delete(&models.Wallet{}, id).Error
 }
 
 // UpdateBalance updates the wallet balance by adding the specified amount
-func (r *walletRepository) UpdateBalance(id uuid.UUID, amount float64) error {
+func (r *walletRepository) UpdateBalance(id uuid.UUID, amount money.Amount) error {
 	return r.db.Model(&models.Wallet{}).
 		Where("id = ?", id).
 		UpdateColumn("balance", gorm.Expr("balance + ?", amount)).
 		Error
 }
+
+// FindDeletedByUserID retrieves a user's soft-deleted wallets, most recently deleted first
+func (r *walletRepository) FindDeletedByUserID(userID uuid.UUID) ([]*models.Wallet, error) {
+	var wallets []*models.Wallet
+	err := r.db.Unscoped().
+		Where("user_id = ? AND deleted_at IS NOT NULL", userID).
+		Order("deleted_at DESC").
+		Find(&wallets).Error
+	return wallets, err
+}
+
+// FindDeletedByID retrieves a soft-deleted wallet by its ID
+func (r *walletRepository) FindDeletedByID(id uuid.UUID) (*models.Wallet, error) {
+	var wallet models.Wallet
+	err := r.db.Unscoped().
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		First(&wallet).Error
+	if err != nil {
+		return nil, err
+	}
+	return &wallet, nil
+}
+
+// FindDeletedBefore retrieves up to limit wallets soft-deleted before cutoff, oldest first
+func (r *walletRepository) FindDeletedBefore(cutoff time.Time, limit int) ([]*models.Wallet, error) {
+	var wallets []*models.Wallet
+	err := r.db.Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+		Order("deleted_at ASC").
+		Limit(limit).
+		Find(&wallets).Error
+	return wallets, err
+}
+
+// Restore undoes the soft delete of a wallet
+func (r *walletRepository) Restore(id uuid.UUID) error {
+	return r.db.Unscoped().Model(&models.Wallet{}).
+		Where("id = ?", id).
+		Update("deleted_at", nil).Error
+}
+
+// Purge permanently deletes a wallet
+func (r *walletRepository) Purge(id uuid.UUID) error {
+	return r.db.Unscoped().Delete(&models.Wallet{}, id).Error
+}
+
+// CountReferences counts the transactions, transfers, recurring transactions
+// and imports that point at a wallet, deleted ones included. A wallet can only
+// be purged once nothing refers to it.
+func (r *walletRepository) CountReferences(id uuid.UUID) (int64, error) {
+	var total int64
+	counts := []*gorm.DB{
+		r.db.Unscoped().Model(&models.Transaction{}).Where("wallet_id = ?", id),
+		r.db.Unscoped().Model(&models.Transfer{}).Where("from_wallet_id = ? OR to_wallet_id = ?", id, id),
+		r.db.Unscoped().Model(&models.RecurringTransaction{}).Where("wallet_id = ?", id),
+		r.db.Unscoped().Model(&models.ImportJob{}).Where("wallet_id = ?", id),
+	}
+	for _, query := range counts {
+		var count int64
+		if err := query.Count(&count).Error; err != nil {
+			return 0, err
+		}
+		total += count
+	}
+	return total, nil
+}
+
+// WithTx returns a repository bound to the given database transaction
+func (r *walletRepository) WithTx(tx *gorm.DB) WalletRepository {
+	return &walletRepository{db: tx}
+}
@@ -0,0 +1,55 @@
+// Package scheduler runs background jobs on a fixed interval.
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Job is a unit of background work. Run is given the time of the tick and
+// must be safe to repeat, since a job that fails or is interrupted is simply
+// run again on the next tick.
+type Job struct {
+	Name string
+	Run  func(now time.Time) error
+}
+
+// Scheduler runs its jobs one after another, once at start and then on every tick
+type Scheduler struct {
+	interval time.Duration
+	jobs     []Job
+}
+
+// New creates a scheduler that runs the jobs every interval
+func New(interval time.Duration, jobs ...Job) *Scheduler {
+	return &Scheduler{interval: interval, jobs: jobs}
+}
+
+// Start runs the jobs in a background goroutine until ctx is cancelled
+func (s *Scheduler) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		s.RunOnce(time.Now())
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				s.RunOnce(now)
+			}
+		}
+	}()
+}
+
+// RunOnce runs every job for the given time, logging failures so one failing
+// job does not hold back the others
+func (s *Scheduler) RunOnce(now time.Time) {
+	for _, job := range s.jobs {
+		if err := job.Run(now); err != nil {
+			log.Printf("Scheduled job %s failed: %v", job.Name, err)
+		}
+	}
+}
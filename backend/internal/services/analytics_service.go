@@ -1,17 +1,21 @@
 package services
 
 import (
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
 	"github.com/nyunja/fity-budget-backend/internal/repository"
 )
 
 // AnalyticsService defines the interface for analytics and reporting operations
 type AnalyticsService interface {
 	GetDashboardSummary(userID uuid.UUID) (*DashboardSummary, error)
-	GetSpendingByCategory(userID uuid.UUID, startDate, endDate time.Time) ([]*CategorySpending, error)
+	GetSpendingByCategory(userID uuid.UUID, startDate, endDate time.Time, rollup bool) (*SpendingByCategoryReport, error)
+	GetSpendingByTag(userID uuid.UUID, startDate, endDate time.Time) (*SpendingByTagReport, error)
 	GetIncomeVsExpense(userID uuid.UUID, period string) (*IncomeVsExpenseReport, error)
 	GetMonthlyTrends(userID uuid.UUID, months int) (*MonthlyTrends, error)
 	GetFinancialHealthScore(userID uuid.UUID) (*FinancialHealthScore, error)
@@ -22,79 +26,127 @@ type analyticsService struct {
 	walletRepo      repository.WalletRepository
 	budgetRepo      repository.BudgetRepository
 	goalRepo        repository.GoalRepository
+	userRepo        repository.UserRepository
+	rateRepo        repository.ExchangeRateRepository
+	categoryRepo    repository.CategoryRepository
+	tagRepo         repository.TagRepository
 }
 
+// Reports express every amount in the user's base currency. Amounts held in
+// other currencies are converted at the latest rate quoted on or before the
+// report date, and the rates used are listed in ExchangeRates.
+
 // DashboardSummary represents the main dashboard overview
 type DashboardSummary struct {
-	TotalBalance       float64                `json:"total_balance"`
-	TotalIncome        float64                `json:"total_income"`
-	TotalExpense       float64                `json:"total_expense"`
-	NetSavings         float64                `json:"net_savings"`
-	ActiveGoalsCount   int                    `json:"active_goals_count"`
-	TotalGoalsProgress float64                `json:"total_goals_progress"`
-	BudgetAlerts       int                    `json:"budget_alerts"`
-	RecentTransactions int                    `json:"recent_transactions"`
-	TopCategories      []*CategorySpending    `json:"top_categories"`
-	MonthComparison    *MonthComparisonData   `json:"month_comparison"`
+	TotalBalance          money.Amount         `json:"total_balance"`
+	TotalIncome           money.Amount         `json:"total_income"`
+	TotalExpense          money.Amount         `json:"total_expense"`
+	NetSavings            money.Amount         `json:"net_savings"`
+	ActiveGoalsCount      int                  `json:"active_goals_count"`
+	TotalGoalsProgress    float64              `json:"total_goals_progress"`
+	BudgetAlerts          int                  `json:"budget_alerts"`
+	RecentTransactions    int                  `json:"recent_transactions"`
+	TopCategories         []*CategorySpending  `json:"top_categories"`
+	MonthComparison       *MonthComparisonData `json:"month_comparison"`
+	Currency              string               `json:"currency"`
+	ExchangeRates         []*AppliedRate       `json:"exchange_rates,omitempty"`
+	UnconvertedCurrencies []string             `json:"unconverted_currencies,omitempty"`
 }
 
 // CategorySpending represents spending data for a category
 type CategorySpending struct {
-	Category    string  `json:"category"`
-	Amount      float64 `json:"amount"`
-	Count       int     `json:"count"`
-	Percentage  float64 `json:"percentage"`
-	BudgetLimit float64 `json:"budget_limit,omitempty"`
+	Category    string       `json:"category"`
+	Amount      money.Amount `json:"amount"`
+	Count       int          `json:"count"`
+	Percentage  float64      `json:"percentage"`
+	BudgetLimit money.Amount `json:"budget_limit,omitempty"`
+}
+
+// SpendingByCategoryReport represents spending broken down by category
+type SpendingByCategoryReport struct {
+	TotalSpending         money.Amount        `json:"total_spending"`
+	Categories            []*CategorySpending `json:"by_category"`
+	Currency              string              `json:"currency"`
+	ExchangeRates         []*AppliedRate      `json:"exchange_rates,omitempty"`
+	UnconvertedCurrencies []string            `json:"unconverted_currencies,omitempty"`
+}
+
+// TagSpending represents the spending carrying a tag, broken down by category
+type TagSpending struct {
+	TagID      uuid.UUID           `json:"tag_id"`
+	Tag        string              `json:"tag"`
+	Color      string              `json:"color,omitempty"`
+	Amount     money.Amount        `json:"amount"`
+	Count      int                 `json:"count"`
+	Categories []*CategorySpending `json:"by_category"`
+}
+
+// SpendingByTagReport represents spending broken down by tag. A transaction
+// with several tags counts in full under each of them.
+type SpendingByTagReport struct {
+	Tags                  []*TagSpending `json:"by_tag"`
+	Currency              string         `json:"currency"`
+	ExchangeRates         []*AppliedRate `json:"exchange_rates,omitempty"`
+	UnconvertedCurrencies []string       `json:"unconverted_currencies,omitempty"`
 }
 
 // IncomeVsExpenseReport represents income vs expense data
 type IncomeVsExpenseReport struct {
-	Period       string               `json:"period"`
-	TotalIncome  float64              `json:"total_income"`
-	TotalExpense float64              `json:"total_expense"`
-	NetAmount    float64              `json:"net_amount"`
-	SavingsRate  float64              `json:"savings_rate"`
-	DataPoints   []*IncomeExpenseData `json:"data_points"`
+	Period                string               `json:"period"`
+	TotalIncome           money.Amount         `json:"total_income"`
+	TotalExpense          money.Amount         `json:"total_expense"`
+	NetAmount             money.Amount         `json:"net_amount"`
+	SavingsRate           float64              `json:"savings_rate"`
+	DataPoints            []*IncomeExpenseData `json:"data_points"`
+	Currency              string               `json:"currency"`
+	ExchangeRates         []*AppliedRate       `json:"exchange_rates,omitempty"`
+	UnconvertedCurrencies []string             `json:"unconverted_currencies,omitempty"`
 }
 
 // IncomeExpenseData represents a single data point
 type IncomeExpenseData struct {
-	Date    string  `json:"date"`
-	Income  float64 `json:"income"`
-	Expense float64 `json:"expense"`
+	Date    string       `json:"date"`
+	Income  money.Amount `json:"income"`
+	Expense money.Amount `json:"expense"`
 }
 
 // MonthlyTrends represents monthly trend data
 type MonthlyTrends struct {
-	Months         []string         `json:"months"`
-	IncomeData     []float64        `json:"income_data"`
-	ExpenseData    []float64        `json:"expense_data"`
-	SavingsData    []float64        `json:"savings_data"`
-	AverageIncome  float64          `json:"average_income"`
-	AverageExpense float64          `json:"average_expense"`
-	TrendDirection string           `json:"trend_direction"`
+	Months                []string       `json:"months"`
+	IncomeData            []money.Amount `json:"income_data"`
+	ExpenseData           []money.Amount `json:"expense_data"`
+	SavingsData           []money.Amount `json:"savings_data"`
+	AverageIncome         money.Amount   `json:"average_income"`
+	AverageExpense        money.Amount   `json:"average_expense"`
+	TrendDirection        string         `json:"trend_direction"`
+	Currency              string         `json:"currency"`
+	ExchangeRates         []*AppliedRate `json:"exchange_rates,omitempty"`
+	UnconvertedCurrencies []string       `json:"unconverted_currencies,omitempty"`
 }
 
 // MonthComparisonData represents comparison between current and previous month
 type MonthComparisonData struct {
-	CurrentMonthIncome   float64 `json:"current_month_income"`
-	CurrentMonthExpense  float64 `json:"current_month_expense"`
-	PreviousMonthIncome  float64 `json:"previous_month_income"`
-	PreviousMonthExpense float64 `json:"previous_month_expense"`
-	IncomeChange         float64 `json:"income_change"`
-	ExpenseChange        float64 `json:"expense_change"`
+	CurrentMonthIncome   money.Amount `json:"current_month_income"`
+	CurrentMonthExpense  money.Amount `json:"current_month_expense"`
+	PreviousMonthIncome  money.Amount `json:"previous_month_income"`
+	PreviousMonthExpense money.Amount `json:"previous_month_expense"`
+	IncomeChange         float64      `json:"income_change"`
+	ExpenseChange        float64      `json:"expense_change"`
 }
 
 // FinancialHealthScore represents overall financial health metrics
 type FinancialHealthScore struct {
-	Score              int     `json:"score"`
-	Rating             string  `json:"rating"`
-	SavingsRatio       float64 `json:"savings_ratio"`
-	BudgetCompliance   float64 `json:"budget_compliance"`
-	GoalProgress       float64 `json:"goal_progress"`
-	DebtToIncome       float64 `json:"debt_to_income"`
-	EmergencyFundRatio float64 `json:"emergency_fund_ratio"`
-	Recommendations    []string `json:"recommendations"`
+	Score                 int            `json:"score"`
+	Rating                string         `json:"rating"`
+	SavingsRatio          float64        `json:"savings_ratio"`
+	BudgetCompliance      float64        `json:"budget_compliance"`
+	GoalProgress          float64        `json:"goal_progress"`
+	DebtToIncome          float64        `json:"debt_to_income"`
+	EmergencyFundRatio    float64        `json:"emergency_fund_ratio"`
+	Recommendations       []string       `json:"recommendations"`
+	Currency              string         `json:"currency"`
+	ExchangeRates         []*AppliedRate `json:"exchange_rates,omitempty"`
+	UnconvertedCurrencies []string       `json:"unconverted_currencies,omitempty"`
 }
 
 func NewAnalyticsService(
@@ -102,78 +154,101 @@ func NewAnalyticsService(
 	walletRepo repository.WalletRepository,
 	budgetRepo repository.BudgetRepository,
 	goalRepo repository.GoalRepository,
+	userRepo repository.UserRepository,
+	rateRepo repository.ExchangeRateRepository,
+	categoryRepo repository.CategoryRepository,
+	tagRepo repository.TagRepository,
 ) AnalyticsService {
 	return &analyticsService{
 		transactionRepo: transactionRepo,
 		walletRepo:      walletRepo,
 		budgetRepo:      budgetRepo,
 		goalRepo:        goalRepo,
+		userRepo:        userRepo,
+		rateRepo:        rateRepo,
+		categoryRepo:    categoryRepo,
+		tagRepo:         tagRepo,
 	}
 }
 
 // GetDashboardSummary retrieves the main dashboard summary
 func (s *analyticsService) GetDashboardSummary(userID uuid.UUID) (*DashboardSummary, error) {
 	summary := &DashboardSummary{}
+	now := time.Now()
 
 	// Get total balance from all wallets
 	wallets, err := s.walletRepo.FindByUserID(userID)
 	if err != nil {
 		return nil, err
 	}
+	report, err := s.newReport(userID, wallets, now)
+	if err != nil {
+		return nil, err
+	}
 	for _, wallet := range wallets {
-		summary.TotalBalance += wallet.Balance
+		balance, err := report.convert(wallet.Balance, wallet.Currency)
+		if err != nil {
+			return nil, err
+		}
+		summary.TotalBalance += balance
 	}
 
-	// Get current month's transactions
-	now := time.Now()
+	// Get current month's totals
 	startOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
 	endOfMonth := startOfMonth.AddDate(0, 1, 0)
 
-	transactions, err := s.transactionRepo.FindByUserID(userID, 10000, 0)
+	aggregates, err := s.transactionRepo.Aggregate(completedBetween(userID, startOfMonth, endOfMonth),
+		repository.GroupByType, repository.GroupByCategory, repository.GroupByWallet)
+	if err != nil {
+		return nil, err
+	}
+
+	// Split transactions appear under each of their categories, so count them separately
+	recent, err := s.transactionRepo.CountByFilter(completedBetween(userID, startOfMonth, endOfMonth))
+	if err != nil {
+		return nil, err
+	}
+	summary.RecentTransactions = int(recent)
+
+	index, err := s.categoryIndex(userID)
 	if err != nil {
 		return nil, err
 	}
 
 	categoryMap := make(map[string]*CategorySpending)
+	var totals flowTotals
 
-	for _, txn := range transactions {
-		if txn.Status == "Completed" {
-			// Current month stats
-			if txn.TransactionDate.After(startOfMonth) && txn.TransactionDate.Before(endOfMonth) {
-				// Note: Transaction model doesn't have Type field to distinguish income/expense
-				// All transactions are counted as expenses for now
-				summary.TotalExpense += txn.Amount
-
-				// Track category spending
-				if _, exists := categoryMap[txn.Category]; !exists {
-					categoryMap[txn.Category] = &CategorySpending{
-						Category: txn.Category,
-					}
-				}
-				categoryMap[txn.Category].Amount += txn.Amount
-				categoryMap[txn.Category].Count++
-				summary.RecentTransactions++
-			}
+	for _, aggregate := range aggregates {
+		amount, err := report.convertAggregate(aggregate)
+		if err != nil {
+			return nil, err
+		}
+		totals.add(aggregate.Type, amount)
+
+		// Only spending counts towards category breakdowns
+		if aggregate.Type != models.TransactionTypeExpense {
+			continue
 		}
+		addCategorySpending(categoryMap, index.name(aggregate.Category), amount, aggregate.Count)
 	}
 
+	summary.TotalIncome = totals.Income
+	summary.TotalExpense = totals.Expense
 	summary.NetSavings = summary.TotalIncome - summary.TotalExpense
 
 	// Convert category map to slice and calculate percentages
 	var topCategories []*CategorySpending
 	for _, cat := range categoryMap {
-		if summary.TotalExpense > 0 {
-			cat.Percentage = (cat.Amount / summary.TotalExpense) * 100
-		}
+		cat.Percentage = cat.Amount.Percent(summary.TotalExpense)
 		topCategories = append(topCategories, cat)
 	}
-	// Sort by amount (in real implementation, use proper sorting)
+	sortCategoriesByAmount(topCategories)
 	summary.TopCategories = topCategories
 
 	// Get goals data
 	goals, err := s.goalRepo.FindByUserID(userID)
 	if err == nil {
-		var totalTarget, totalCurrent float64
+		var totalTarget, totalCurrent money.Amount
 		for _, goal := range goals {
 			if goal.Status != "Completed" {
 				summary.ActiveGoalsCount++
@@ -181,94 +256,219 @@ func (s *analyticsService) GetDashboardSummary(userID uuid.UUID) (*DashboardSumm
 			totalTarget += goal.TargetAmount
 			totalCurrent += goal.CurrentAmount
 		}
-		if totalTarget > 0 {
-			summary.TotalGoalsProgress = (totalCurrent / totalTarget) * 100
-		}
+		summary.TotalGoalsProgress = totalCurrent.Percent(totalTarget)
 	}
 
 	// Get budget alerts
 	budgets, err := s.budgetRepo.FindByUserID(userID)
 	if err == nil {
 		for _, budget := range budgets {
-			// Check if budget is over limit
-			spent := float64(0)
-			for cat, catData := range categoryMap {
-				if cat == budget.Category {
-					spent = catData.Amount
-					break
+			// Check if budget is over limit, counting its subcategories
+			var spent money.Amount
+			for _, category := range index.family(budget.Category) {
+				if catData, exists := categoryMap[strings.ToLower(category)]; exists {
+					spent += catData.Amount
 				}
 			}
-			if spent > budget.LimitAmount || (spent/budget.LimitAmount*100) >= float64(budget.AlertThreshold) {
+			if budget.LimitAmount <= 0 {
+				continue
+			}
+			if spent > budget.LimitAmount || spent.Percent(budget.LimitAmount) >= float64(budget.AlertThreshold) {
 				summary.BudgetAlerts++
 			}
 		}
 	}
 
 	// Get month comparison
-	summary.MonthComparison = s.getMonthComparison(userID, transactions, startOfMonth)
+	summary.MonthComparison, err = s.getMonthComparison(userID, report, totals, startOfMonth)
+	if err != nil {
+		return nil, err
+	}
+
+	summary.Currency = report.base
+	summary.ExchangeRates = report.rates()
+	summary.UnconvertedCurrencies = report.unconverted()
 
 	return summary, nil
 }
 
-// GetSpendingByCategory retrieves spending breakdown by category
-func (s *analyticsService) GetSpendingByCategory(userID uuid.UUID, startDate, endDate time.Time) ([]*CategorySpending, error) {
-	transactions, err := s.transactionRepo.FindByUserID(userID, 10000, 0)
+// GetSpendingByCategory retrieves spending breakdown by category. With rollup,
+// spending in subcategories is reported under their parent category.
+func (s *analyticsService) GetSpendingByCategory(userID uuid.UUID, startDate, endDate time.Time, rollup bool) (*SpendingByCategoryReport, error) {
+	aggregates, err := s.transactionRepo.Aggregate(
+		completedBetween(userID, startDate, endDate, models.TransactionTypeExpense),
+		repository.GroupByCategory, repository.GroupByWallet)
+	if err != nil {
+		return nil, err
+	}
+
+	// Past periods are converted at the rates of their last day
+	reportDate := time.Now()
+	if endDate.Before(reportDate) {
+		reportDate = endDate
+	}
+	report, err := s.newReport(userID, nil, reportDate)
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := s.categoryIndex(userID)
 	if err != nil {
 		return nil, err
 	}
 
 	categoryMap := make(map[string]*CategorySpending)
-	totalExpense := float64(0)
+	var totalExpense money.Amount
 
-	for _, txn := range transactions {
-		if txn.Status == "Completed" &&
-			txn.TransactionDate.After(startDate) &&
-			txn.TransactionDate.Before(endDate) {
+	for _, aggregate := range aggregates {
+		amount, err := report.convertAggregate(aggregate)
+		if err != nil {
+			return nil, err
+		}
 
-			if _, exists := categoryMap[txn.Category]; !exists {
-				categoryMap[txn.Category] = &CategorySpending{
-					Category: txn.Category,
-				}
-			}
-			categoryMap[txn.Category].Amount += txn.Amount
-			categoryMap[txn.Category].Count++
-			totalExpense += txn.Amount
+		category := index.name(aggregate.Category)
+		if rollup {
+			category = index.rollup(aggregate.Category)
 		}
+		addCategorySpending(categoryMap, category, amount, aggregate.Count)
+		totalExpense += amount
 	}
 
 	// Get budget limits for categories
 	budgets, _ := s.budgetRepo.FindByUserID(userID)
-	budgetMap := make(map[string]float64)
+	budgetMap := make(map[string]money.Amount)
 	for _, budget := range budgets {
-		budgetMap[budget.Category] = budget.LimitAmount
+		budgetMap[strings.ToLower(budget.Category)] = budget.LimitAmount
 	}
 
 	// Convert to slice and calculate percentages
 	var categories []*CategorySpending
-	for _, cat := range categoryMap {
-		if totalExpense > 0 {
-			cat.Percentage = (cat.Amount / totalExpense) * 100
-		}
-		if limit, exists := budgetMap[cat.Category]; exists {
+	for key, cat := range categoryMap {
+		cat.Percentage = cat.Amount.Percent(totalExpense)
+		if limit, exists := budgetMap[key]; exists {
 			cat.BudgetLimit = limit
 		}
 		categories = append(categories, cat)
 	}
+	sortCategoriesByAmount(categories)
 
-	return categories, nil
+	return &SpendingByCategoryReport{
+		TotalSpending:         totalExpense,
+		Categories:            categories,
+		Currency:              report.base,
+		ExchangeRates:         report.rates(),
+		UnconvertedCurrencies: report.unconverted(),
+	}, nil
 }
 
-// GetIncomeVsExpense retrieves income vs expense report for a period
-func (s *analyticsService) GetIncomeVsExpense(userID uuid.UUID, period string) (*IncomeVsExpenseReport, error) {
-	report := &IncomeVsExpenseReport{
-		Period: period,
+// GetSpendingByTag retrieves the spending carrying each of the user's tags,
+// with the categories it was spent on
+func (s *analyticsService) GetSpendingByTag(userID uuid.UUID, startDate, endDate time.Time) (*SpendingByTagReport, error) {
+	filter := completedBetween(userID, startDate, endDate, models.TransactionTypeExpense)
+
+	// Tag totals come from whole transactions; the category breakdown from split lines
+	totals, err := s.transactionRepo.Aggregate(filter, repository.GroupByTag, repository.GroupByWallet)
+	if err != nil {
+		return nil, err
+	}
+	lines, err := s.transactionRepo.Aggregate(filter, repository.GroupByTag, repository.GroupByCategory, repository.GroupByWallet)
+	if err != nil {
+		return nil, err
 	}
 
-	transactions, err := s.transactionRepo.FindByUserID(userID, 10000, 0)
+	// Past periods are converted at the rates of their last day
+	reportDate := time.Now()
+	if endDate.Before(reportDate) {
+		reportDate = endDate
+	}
+	report, err := s.newReport(userID, nil, reportDate)
 	if err != nil {
 		return nil, err
 	}
 
+	index, err := s.categoryIndex(userID)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := s.tagRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tagMap := make(map[uuid.UUID]*TagSpending, len(tags))
+	categoryMaps := make(map[uuid.UUID]map[string]*CategorySpending, len(tags))
+	for _, tag := range tags {
+		tagMap[tag.ID] = &TagSpending{TagID: tag.ID, Tag: tag.Name, Color: tag.Color}
+		categoryMaps[tag.ID] = make(map[string]*CategorySpending)
+	}
+
+	for _, aggregate := range totals {
+		spending, exists := tagMap[tagIDOf(aggregate)]
+		if !exists {
+			continue
+		}
+		amount, err := report.convertAggregate(aggregate)
+		if err != nil {
+			return nil, err
+		}
+		spending.Amount += amount
+		spending.Count += int(aggregate.Count)
+	}
+	for _, aggregate := range lines {
+		categoryMap, exists := categoryMaps[tagIDOf(aggregate)]
+		if !exists {
+			continue
+		}
+		amount, err := report.convertAggregate(aggregate)
+		if err != nil {
+			return nil, err
+		}
+		addCategorySpending(categoryMap, index.name(aggregate.Category), amount, aggregate.Count)
+	}
+
+	// Tags without spending in the period are left out
+	result := []*TagSpending{}
+	for id, spending := range tagMap {
+		if spending.Count == 0 {
+			continue
+		}
+		spending.Categories = []*CategorySpending{}
+		for _, cat := range categoryMaps[id] {
+			cat.Percentage = cat.Amount.Percent(spending.Amount)
+			spending.Categories = append(spending.Categories, cat)
+		}
+		sortCategoriesByAmount(spending.Categories)
+		result = append(result, spending)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Amount != result[j].Amount {
+			return result[i].Amount > result[j].Amount
+		}
+		return result[i].Tag < result[j].Tag
+	})
+
+	return &SpendingByTagReport{
+		Tags:                  result,
+		Currency:              report.base,
+		ExchangeRates:         report.rates(),
+		UnconvertedCurrencies: report.unconverted(),
+	}, nil
+}
+
+// tagIDOf returns the tag an aggregate row was grouped under
+func tagIDOf(aggregate *repository.TransactionAggregate) uuid.UUID {
+	if aggregate.TagID == nil {
+		return uuid.Nil
+	}
+	return *aggregate.TagID
+}
+
+// GetIncomeVsExpense retrieves income vs expense report for a period
+func (s *analyticsService) GetIncomeVsExpense(userID uuid.UUID, period string) (*IncomeVsExpenseReport, error) {
+	report := &IncomeVsExpenseReport{
+		Period: period,
+	}
+
 	now := time.Now()
 	var startDate time.Time
 
@@ -283,34 +483,60 @@ func (s *analyticsService) GetIncomeVsExpense(userID uuid.UUID, period string) (
 		startDate = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
 	}
 
+	reportCurrency, err := s.newReport(userID, nil, now)
+	if err != nil {
+		return nil, err
+	}
+
+	aggregates, err := s.transactionRepo.Aggregate(
+		completedBetween(userID, startDate, time.Time{}, models.TransactionTypeIncome, models.TransactionTypeExpense),
+		repository.GroupByDay, repository.GroupByType, repository.GroupByWallet)
+	if err != nil {
+		return nil, err
+	}
+
 	dailyMap := make(map[string]*IncomeExpenseData)
+	var totals flowTotals
 
-	for _, txn := range transactions {
-		if txn.Status == "Completed" && txn.TransactionDate.After(startDate) {
-			dateKey := txn.TransactionDate.Format("2006-01-02")
+	for _, aggregate := range aggregates {
+		amount, err := reportCurrency.convertAggregate(aggregate)
+		if err != nil {
+			return nil, err
+		}
 
-			if _, exists := dailyMap[dateKey]; !exists {
-				dailyMap[dateKey] = &IncomeExpenseData{
-					Date: dateKey,
-				}
+		dateKey := aggregate.Period
+		if _, exists := dailyMap[dateKey]; !exists {
+			dailyMap[dateKey] = &IncomeExpenseData{
+				Date: dateKey,
 			}
+		}
 
-			// Note: Transaction model doesn't have Type field
-			// All transactions counted as expenses
-			report.TotalExpense += txn.Amount
-			dailyMap[dateKey].Expense += txn.Amount
+		totals.add(aggregate.Type, amount)
+		if aggregate.Type == models.TransactionTypeIncome {
+			dailyMap[dateKey].Income += amount
+		} else {
+			dailyMap[dateKey].Expense += amount
 		}
 	}
 
+	report.TotalIncome = totals.Income
+	report.TotalExpense = totals.Expense
 	report.NetAmount = report.TotalIncome - report.TotalExpense
 	if report.TotalIncome > 0 {
-		report.SavingsRate = (report.NetAmount / report.TotalIncome) * 100
+		report.SavingsRate = report.NetAmount.Percent(report.TotalIncome)
 	}
 
-	// Convert map to slice
+	// Convert map to slice ordered by date
 	for _, data := range dailyMap {
 		report.DataPoints = append(report.DataPoints, data)
 	}
+	sort.Slice(report.DataPoints, func(i, j int) bool {
+		return report.DataPoints[i].Date < report.DataPoints[j].Date
+	})
+
+	report.Currency = reportCurrency.base
+	report.ExchangeRates = reportCurrency.rates()
+	report.UnconvertedCurrencies = reportCurrency.unconverted()
 
 	return report, nil
 }
@@ -323,53 +549,68 @@ func (s *analyticsService) GetMonthlyTrends(userID uuid.UUID, months int) (*Mont
 
 	trends := &MonthlyTrends{
 		Months:      make([]string, 0),
-		IncomeData:  make([]float64, 0),
-		ExpenseData: make([]float64, 0),
-		SavingsData: make([]float64, 0),
+		IncomeData:  make([]money.Amount, 0),
+		ExpenseData: make([]money.Amount, 0),
+		SavingsData: make([]money.Amount, 0),
 	}
 
-	transactions, err := s.transactionRepo.FindByUserID(userID, 10000, 0)
+	now := time.Now()
+	report, err := s.newReport(userID, nil, now)
 	if err != nil {
 		return nil, err
 	}
 
-	now := time.Now()
+	// Anchor on the first of the month so AddDate never skips short months
+	currentMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
 	monthlyMap := make(map[string]*struct {
-		income  float64
-		expense float64
+		income  money.Amount
+		expense money.Amount
 	})
 
 	// Calculate for each month
 	for i := months - 1; i >= 0; i-- {
-		targetMonth := now.AddDate(0, -i, 0)
+		targetMonth := currentMonth.AddDate(0, -i, 0)
 		monthKey := targetMonth.Format("2006-01")
 		monthLabel := targetMonth.Format("Jan 2006")
 
 		trends.Months = append(trends.Months, monthLabel)
 		monthlyMap[monthKey] = &struct {
-			income  float64
-			expense float64
+			income  money.Amount
+			expense money.Amount
 		}{}
 	}
 
 	// Aggregate transactions by month
-	for _, txn := range transactions {
-		if txn.Status == "Completed" {
-			monthKey := txn.TransactionDate.Format("2006-01")
-			if data, exists := monthlyMap[monthKey]; exists {
-				// Note: Transaction model doesn't have Type field
-				// All transactions counted as expenses
-				data.expense += txn.Amount
-			}
+	aggregates, err := s.transactionRepo.Aggregate(
+		completedBetween(userID, currentMonth.AddDate(0, -(months-1), 0), currentMonth.AddDate(0, 1, 0),
+			models.TransactionTypeIncome, models.TransactionTypeExpense),
+		repository.GroupByMonth, repository.GroupByType, repository.GroupByWallet)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, aggregate := range aggregates {
+		data, exists := monthlyMap[aggregate.Period]
+		if !exists {
+			continue
+		}
+		amount, err := report.convertAggregate(aggregate)
+		if err != nil {
+			return nil, err
+		}
+		switch aggregate.Type {
+		case models.TransactionTypeIncome:
+			data.income += amount
+		case models.TransactionTypeExpense:
+			data.expense += amount
 		}
 	}
 
 	// Build the arrays in order
-	totalIncome := float64(0)
-	totalExpense := float64(0)
+	var totalIncome, totalExpense money.Amount
 
 	for i := months - 1; i >= 0; i-- {
-		targetMonth := now.AddDate(0, -i, 0)
+		targetMonth := currentMonth.AddDate(0, -i, 0)
 		monthKey := targetMonth.Format("2006-01")
 
 		if data, exists := monthlyMap[monthKey]; exists {
@@ -387,8 +628,8 @@ func (s *analyticsService) GetMonthlyTrends(userID uuid.UUID, months int) (*Mont
 	}
 
 	if months > 0 {
-		trends.AverageIncome = totalIncome / float64(months)
-		trends.AverageExpense = totalExpense / float64(months)
+		trends.AverageIncome = totalIncome.Div(int64(months))
+		trends.AverageExpense = totalExpense.Div(int64(months))
 	}
 
 	// Determine trend direction
@@ -404,6 +645,10 @@ func (s *analyticsService) GetMonthlyTrends(userID uuid.UUID, months int) (*Mont
 		}
 	}
 
+	trends.Currency = report.base
+	trends.ExchangeRates = report.rates()
+	trends.UnconvertedCurrencies = report.unconverted()
+
 	return trends, nil
 }
 
@@ -417,38 +662,51 @@ func (s *analyticsService) GetFinancialHealthScore(userID uuid.UUID) (*Financial
 	now := time.Now()
 	startOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
 
-	transactions, err := s.transactionRepo.FindByUserID(userID, 10000, 0)
+	wallets, _ := s.walletRepo.FindByUserID(userID)
+	report, err := s.newReport(userID, wallets, now)
 	if err != nil {
 		return nil, err
 	}
 
-	monthlyIncome := float64(0)
-	monthlyExpense := float64(0)
+	aggregates, err := s.transactionRepo.Aggregate(completedBetween(userID, startOfMonth, time.Time{}),
+		repository.GroupByType, repository.GroupByCategory, repository.GroupByWallet)
+	if err != nil {
+		return nil, err
+	}
 
-	for _, txn := range transactions {
-		if txn.Status == "Completed" && txn.TransactionDate.After(startOfMonth) {
-			// Note: Transaction model doesn't have Type field
-			// All transactions counted as expenses
-			monthlyExpense += txn.Amount
+	// Convert this month's totals once, keeping spending per category
+	var totals flowTotals
+	categorySpending := make(map[string]money.Amount)
+	for _, aggregate := range aggregates {
+		amount, err := report.convertAggregate(aggregate)
+		if err != nil {
+			return nil, err
+		}
+		totals.add(aggregate.Type, amount)
+		if aggregate.Type == models.TransactionTypeExpense {
+			categorySpending[strings.ToLower(aggregate.Category)] += amount
 		}
 	}
+	monthlyIncome := totals.Income
+	monthlyExpense := totals.Expense
 
 	// Calculate savings ratio
 	if monthlyIncome > 0 {
-		score.SavingsRatio = ((monthlyIncome - monthlyExpense) / monthlyIncome) * 100
+		score.SavingsRatio = (monthlyIncome - monthlyExpense).Percent(monthlyIncome)
 	}
 
-	// Calculate budget compliance
+	// Calculate budget compliance, counting subcategories towards their parent's budget
+	index, err := s.categoryIndex(userID)
+	if err != nil {
+		return nil, err
+	}
 	budgets, _ := s.budgetRepo.FindByUserID(userID)
 	if len(budgets) > 0 {
 		compliantCount := 0
 		for _, budget := range budgets {
-			spent := float64(0)
-			for _, txn := range transactions {
-				if txn.Category == budget.Category &&
-					txn.Status == "Completed" && txn.TransactionDate.After(startOfMonth) {
-					spent += txn.Amount
-				}
+			var spent money.Amount
+			for _, category := range index.family(budget.Category) {
+				spent += categorySpending[strings.ToLower(category)]
 			}
 			if spent <= budget.LimitAmount {
 				compliantCount++
@@ -460,27 +718,27 @@ func (s *analyticsService) GetFinancialHealthScore(userID uuid.UUID) (*Financial
 	// Calculate goal progress
 	goals, _ := s.goalRepo.FindByUserID(userID)
 	if len(goals) > 0 {
-		totalTarget := float64(0)
-		totalCurrent := float64(0)
+		var totalTarget, totalCurrent money.Amount
 		for _, goal := range goals {
 			totalTarget += goal.TargetAmount
 			totalCurrent += goal.CurrentAmount
 		}
-		if totalTarget > 0 {
-			score.GoalProgress = (totalCurrent / totalTarget) * 100
-		}
+		score.GoalProgress = totalCurrent.Percent(totalTarget)
 	}
 
 	// Get emergency fund (total wallet balance)
-	wallets, _ := s.walletRepo.FindByUserID(userID)
-	totalBalance := float64(0)
+	var totalBalance money.Amount
 	for _, wallet := range wallets {
-		totalBalance += wallet.Balance
+		balance, err := report.convert(wallet.Balance, wallet.Currency)
+		if err != nil {
+			return nil, err
+		}
+		totalBalance += balance
 	}
 
 	// Emergency fund should be 3-6 months of expenses
 	if monthlyExpense > 0 {
-		score.EmergencyFundRatio = totalBalance / (monthlyExpense * 3)
+		score.EmergencyFundRatio = float64(totalBalance) / float64(monthlyExpense*3)
 	}
 
 	// Calculate overall score (0-100)
@@ -549,40 +807,111 @@ func (s *analyticsService) GetFinancialHealthScore(userID uuid.UUID) (*Financial
 		score.Recommendations = append(score.Recommendations, "Increase contributions to your savings goals")
 	}
 
+	score.Currency = report.base
+	score.ExchangeRates = report.rates()
+	score.UnconvertedCurrencies = report.unconverted()
+
 	return score, nil
 }
 
 // Helper function to get month comparison data
-func (s *analyticsService) getMonthComparison(userID uuid.UUID, transactions []*models.Transaction, startOfMonth time.Time) *MonthComparisonData {
+func (s *analyticsService) getMonthComparison(userID uuid.UUID, report *reportCurrency, current flowTotals, startOfMonth time.Time) (*MonthComparisonData, error) {
 	comparison := &MonthComparisonData{}
 
-	endOfMonth := startOfMonth.AddDate(0, 1, 0)
 	startOfPrevMonth := startOfMonth.AddDate(0, -1, 0)
+	aggregates, err := s.transactionRepo.Aggregate(completedBetween(userID, startOfPrevMonth, startOfMonth),
+		repository.GroupByType, repository.GroupByWallet)
+	if err != nil {
+		return nil, err
+	}
 
-	for _, txn := range transactions {
-		if txn.Status == "Completed" {
-			// Current month
-			if txn.TransactionDate.After(startOfMonth) && txn.TransactionDate.Before(endOfMonth) {
-				// Note: Transaction model doesn't have Type field
-				// All transactions counted as expenses
-				comparison.CurrentMonthExpense += txn.Amount
-			}
-			// Previous month
-			if txn.TransactionDate.After(startOfPrevMonth) && txn.TransactionDate.Before(startOfMonth) {
-				// Note: Transaction model doesn't have Type field
-				// All transactions counted as expenses
-				comparison.PreviousMonthExpense += txn.Amount
-			}
+	var previous flowTotals
+	for _, aggregate := range aggregates {
+		amount, err := report.convertAggregate(aggregate)
+		if err != nil {
+			return nil, err
 		}
+		previous.add(aggregate.Type, amount)
 	}
 
+	comparison.CurrentMonthIncome = current.Income
+	comparison.CurrentMonthExpense = current.Expense
+	comparison.PreviousMonthIncome = previous.Income
+	comparison.PreviousMonthExpense = previous.Expense
+
 	// Calculate percentage changes
 	if comparison.PreviousMonthIncome > 0 {
-		comparison.IncomeChange = ((comparison.CurrentMonthIncome - comparison.PreviousMonthIncome) / comparison.PreviousMonthIncome) * 100
+		comparison.IncomeChange = (comparison.CurrentMonthIncome - comparison.PreviousMonthIncome).Percent(comparison.PreviousMonthIncome)
 	}
 	if comparison.PreviousMonthExpense > 0 {
-		comparison.ExpenseChange = ((comparison.CurrentMonthExpense - comparison.PreviousMonthExpense) / comparison.PreviousMonthExpense) * 100
+		comparison.ExpenseChange = (comparison.CurrentMonthExpense - comparison.PreviousMonthExpense).Percent(comparison.PreviousMonthExpense)
 	}
 
-	return comparison
+	return comparison, nil
+}
+
+// completedBetween filters a user's completed transactions dated from start up
+// to, but excluding, end. A zero end leaves the range open.
+func completedBetween(userID uuid.UUID, start, end time.Time, types ...string) repository.TransactionFilter {
+	return repository.TransactionFilter{
+		UserID:    userID,
+		StartDate: start,
+		EndDate:   end,
+		Statuses:  []string{"Completed"},
+		Types:     types,
+	}
+}
+
+// newReport prepares the conversion of a user's amounts into their base currency
+func (s *analyticsService) newReport(userID uuid.UUID, wallets []*models.Wallet, on time.Time) (*reportCurrency, error) {
+	return loadReportCurrency(s.userRepo, s.walletRepo, s.rateRepo, userID, wallets, on)
+}
+
+// flowTotals accumulates transaction amounts by direction. Transfers between
+// the user's own wallets are kept apart so they never count as income or spending.
+type flowTotals struct {
+	Income    money.Amount
+	Expense   money.Amount
+	Transfers money.Amount
+}
+
+// add adds an amount to the bucket matching the transaction type
+func (f *flowTotals) add(txnType string, amount money.Amount) {
+	switch txnType {
+	case models.TransactionTypeIncome:
+		f.Income += amount
+	case models.TransactionTypeTransfer:
+		f.Transfers += amount
+	default:
+		f.Expense += amount
+	}
+}
+
+// sortCategoriesByAmount orders categories from highest to lowest spending
+func sortCategoriesByAmount(categories []*CategorySpending) {
+	sort.Slice(categories, func(i, j int) bool {
+		return categories[i].Amount > categories[j].Amount
+	})
+}
+
+// addCategorySpending adds spending to a category's entry, matching category
+// names regardless of case
+func addCategorySpending(categoryMap map[string]*CategorySpending, category string, amount money.Amount, count int64) {
+	key := strings.ToLower(category)
+	if _, exists := categoryMap[key]; !exists {
+		categoryMap[key] = &CategorySpending{
+			Category: category,
+		}
+	}
+	categoryMap[key].Amount += amount
+	categoryMap[key].Count += int(count)
+}
+
+// categoryIndex loads the user's categories for resolving category names
+func (s *analyticsService) categoryIndex(userID uuid.UUID) (*categoryIndex, error) {
+	categories, err := s.categoryRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return newCategoryIndex(categories), nil
 }
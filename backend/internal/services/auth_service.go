@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
 	"github.com/nyunja/fity-budget-backend/internal/repository"
 	"github.com/nyunja/fity-budget-backend/internal/utils"
 	"golang.org/x/crypto/bcrypt"
@@ -17,7 +18,7 @@ type AuthService interface {
 	Login(email, password string) (*models.User, string, error)
 	GetUserByID(id uuid.UUID) (*models.User, error)
 	UpdateProfile(id uuid.UUID, name, email string) (*models.User, error)
-	CompleteOnboarding(id uuid.UUID, monthlyIncome float64, currency string) error
+	CompleteOnboarding(id uuid.UUID, monthlyIncome money.Amount, currency string) error
 }
 
 type authService struct {
@@ -109,13 +110,13 @@ func (s *authService) UpdateProfile(id uuid.UUID, name, email string) (*models.U
 	return user, nil
 }
 
-func (s *authService) CompleteOnboarding(id uuid.UUID, monthlyIncome float64, currency string) error {
+func (s *authService) CompleteOnboarding(id uuid.UUID, monthlyIncome money.Amount, currency string) error {
 	user, err := s.userRepo.FindByID(id)
 	if err != nil {
 		return errors.New("user not found")
 	}
 	user.IsOnboarded = true
-	user.MonthlyIncome = monthlyIncome
+	user.MonthlyIncome = monthlyIncome.Round(currency)
 	user.Currency = currency
 	if err := s.userRepo.Update(user); err != nil {
 		return err
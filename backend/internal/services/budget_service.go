@@ -2,11 +2,15 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
 	"github.com/nyunja/fity-budget-backend/internal/repository"
+	"gorm.io/gorm"
 )
 
 // BudgetService defines the interface for budget operations
@@ -16,64 +20,171 @@ type BudgetService interface {
 	GetBudgetByID(id, userID uuid.UUID) (*models.Budget, error)
 	UpdateBudget(id, userID uuid.UUID, req UpdateBudgetRequest) (*models.Budget, error)
 	DeleteBudget(id, userID uuid.UUID) error
-	CheckBudgetStatus(userID uuid.UUID) ([]*BudgetStatus, error)
-	GetBudgetSummary(userID uuid.UUID) (*BudgetSummary, error)
+	CheckBudgetStatus(userID uuid.UUID, at time.Time) ([]*BudgetStatus, error)
+	GetBudgetStatus(id, userID uuid.UUID, at time.Time) (*BudgetStatus, error)
+	GetBudgetSummary(userID uuid.UUID, at time.Time) (*BudgetSummary, error)
+	GetBudgetLedger(id, userID uuid.UUID) ([]*models.BudgetLedgerEntry, error)
+	GetBudgetHistory(userID uuid.UUID, periods int, at time.Time) ([]*BudgetHistory, error)
+	SnapshotClosedPeriods(now time.Time) (int, error)
+	WithTx(tx *gorm.DB) BudgetService
 }
 
+// snapshotBatchSize bounds how many budgets one scheduler run snapshots
+const snapshotBatchSize = 500
+
+// A budget whose snapshot failed is retried after snapshotRetryDelay, doubled
+// for each further failure in a row up to snapshotMaxRetryDelay
+const (
+	snapshotRetryDelay    = 15 * time.Minute
+	snapshotMaxRetryDelay = 24 * time.Hour
+)
+
 type budgetService struct {
 	budgetRepo      repository.BudgetRepository
+	limitRepo       repository.BudgetLimitRepository
+	ledgerRepo      repository.BudgetLedgerRepository
 	transactionRepo repository.TransactionRepository
+	categoryRepo    repository.CategoryRepository
+	userRepo        repository.UserRepository
+	walletRepo      repository.WalletRepository
+	rateRepo        repository.ExchangeRateRepository
+	txManager       repository.TxManager
 }
 
 // CreateBudgetRequest represents the data needed to create a budget
 type CreateBudgetRequest struct {
-	Category       string `json:"category" binding:"required"`
-	LimitAmount    float64 `json:"limit_amount" binding:"required,gt=0"`
-	Color          string `json:"color" binding:"required"`
-	Icon           string `json:"icon"`
-	IsRollover     bool   `json:"is_rollover"`
-	Type           string `json:"type" binding:"omitempty,oneof=Fixed Variable"`
-	AlertThreshold int    `json:"alert_threshold" binding:"omitempty,gte=0,lte=100"`
+	Category       string       `json:"category" binding:"required"`
+	LimitAmount    money.Amount `json:"limit_amount" binding:"required,gt=0"`
+	Color          string       `json:"color" binding:"required"`
+	Icon           string       `json:"icon"`
+	IsRollover     bool         `json:"is_rollover"`
+	IsEnvelope     bool         `json:"is_envelope"`
+	Type           string       `json:"type" binding:"omitempty,oneof=Fixed Variable"`
+	AlertThreshold int          `json:"alert_threshold" binding:"omitempty,gte=0,lte=100"`
+	Period         string       `json:"period" binding:"omitempty,oneof=weekly monthly quarterly yearly custom"`
+	PeriodAnchor   *time.Time   `json:"period_anchor"`
+	PeriodDays     int          `json:"period_days" binding:"omitempty,gte=1,lte=366"`
 }
 
 // UpdateBudgetRequest represents the data needed to update a budget
 type UpdateBudgetRequest struct {
-	Category       string  `json:"category"`
-	LimitAmount    float64 `json:"limit_amount" binding:"omitempty,gt=0"`
-	Color          string  `json:"color"`
-	Icon           string  `json:"icon"`
-	IsRollover     *bool   `json:"is_rollover"`
-	Type           string  `json:"type" binding:"omitempty,oneof=Fixed Variable"`
-	AlertThreshold *int    `json:"alert_threshold" binding:"omitempty,gte=0,lte=100"`
+	Category       string       `json:"category"`
+	LimitAmount    money.Amount `json:"limit_amount" binding:"omitempty,gt=0"`
+	Color          string       `json:"color"`
+	Icon           string       `json:"icon"`
+	IsRollover     *bool        `json:"is_rollover"`
+	IsEnvelope     *bool        `json:"is_envelope"`
+	Type           string       `json:"type" binding:"omitempty,oneof=Fixed Variable"`
+	AlertThreshold *int         `json:"alert_threshold" binding:"omitempty,gte=0,lte=100"`
+	Period         string       `json:"period" binding:"omitempty,oneof=weekly monthly quarterly yearly custom"`
+	PeriodAnchor   *time.Time   `json:"period_anchor"`
+	PeriodDays     *int         `json:"period_days" binding:"omitempty,gte=1,lte=366"`
 }
 
-// BudgetStatus represents the spending status of a budget
+// BudgetStatus represents the spending status of a budget over one of its
+// periods. PeriodEnd is the first day after the period. A rollover budget's
+// effective limit is its base limit plus what the previous period carried in;
+// LimitAmount is the effective limit spending is measured against. Spending
+// is converted into the user's base currency.
 type BudgetStatus struct {
-	BudgetID        uuid.UUID `json:"budget_id"`
-	Category        string    `json:"category"`
-	LimitAmount     float64   `json:"limit_amount"`
-	SpentAmount     float64   `json:"spent_amount"`
-	RemainingAmount float64   `json:"remaining_amount"`
-	PercentageUsed  float64   `json:"percentage_used"`
-	IsOverBudget    bool      `json:"is_over_budget"`
-	IsNearLimit     bool      `json:"is_near_limit"`
-	AlertThreshold  int       `json:"alert_threshold"`
+	BudgetID        uuid.UUID    `json:"budget_id"`
+	Category        string       `json:"category"`
+	Period          string       `json:"period"`
+	PeriodStart     time.Time    `json:"period_start"`
+	PeriodEnd       time.Time    `json:"period_end"`
+	BaseLimit       money.Amount `json:"base_limit"`
+	CarriedIn       money.Amount `json:"carried_in"`
+	EffectiveLimit  money.Amount `json:"effective_limit"`
+	LimitAmount     money.Amount `json:"limit_amount"`
+	SpentAmount     money.Amount `json:"spent_amount"`
+	RemainingAmount money.Amount `json:"remaining_amount"`
+	PercentageUsed  float64      `json:"percentage_used"`
+	IsOverBudget    bool         `json:"is_over_budget"`
+	IsNearLimit     bool         `json:"is_near_limit"`
+	AlertThreshold  int          `json:"alert_threshold"`
+	Currency        string       `json:"currency"`
+	// Currencies of spending left out for want of an exchange rate
+	UnconvertedCurrencies []string `json:"unconverted_currencies,omitempty"`
+}
+
+// BudgetHistory compares a budget's limit with the actual spending over its
+// recent periods, oldest first
+type BudgetHistory struct {
+	BudgetID uuid.UUID             `json:"budget_id"`
+	Category string                `json:"category"`
+	Period   string                `json:"period"`
+	Periods  []*BudgetPeriodActual `json:"periods"`
+	// Currencies of spending left out for want of an exchange rate
+	UnconvertedCurrencies []string `json:"unconverted_currencies,omitempty"`
+}
+
+// BudgetPeriodActual is a budget's limit against its actual spending over one
+// period. Variance is what was left of the effective limit, negative when the
+// budget was overspent. Closed periods that were snapshotted report their
+// snapshot.
+type BudgetPeriodActual struct {
+	PeriodStart    time.Time    `json:"period_start"`
+	PeriodEnd      time.Time    `json:"period_end"`
+	LimitAmount    money.Amount `json:"limit_amount"`
+	CarriedIn      money.Amount `json:"carried_in"`
+	EffectiveLimit money.Amount `json:"effective_limit"`
+	ActualAmount   money.Amount `json:"actual_amount"`
+	Variance       money.Amount `json:"variance"`
+	PercentageUsed float64      `json:"percentage_used"`
+	IsOverBudget   bool         `json:"is_over_budget"`
+	IsClosed       bool         `json:"is_closed"`
 }
 
 // BudgetSummary represents overall budget summary for a user
 type BudgetSummary struct {
-	TotalBudgets    int     `json:"total_budgets"`
-	TotalLimit      float64 `json:"total_limit"`
-	TotalSpent      float64 `json:"total_spent"`
-	TotalRemaining  float64 `json:"total_remaining"`
-	OverBudgetCount int     `json:"over_budget_count"`
-	NearLimitCount  int     `json:"near_limit_count"`
+	TotalBudgets    int          `json:"total_budgets"`
+	TotalLimit      money.Amount `json:"total_limit"`
+	TotalSpent      money.Amount `json:"total_spent"`
+	TotalRemaining  money.Amount `json:"total_remaining"`
+	OverBudgetCount int          `json:"over_budget_count"`
+	NearLimitCount  int          `json:"near_limit_count"`
+	Currency        string       `json:"currency"`
+	// Currencies of spending left out for want of an exchange rate
+	UnconvertedCurrencies []string `json:"unconverted_currencies,omitempty"`
 }
 
-func NewBudgetService(budgetRepo repository.BudgetRepository, transactionRepo repository.TransactionRepository) BudgetService {
+func NewBudgetService(
+	budgetRepo repository.BudgetRepository,
+	limitRepo repository.BudgetLimitRepository,
+	ledgerRepo repository.BudgetLedgerRepository,
+	transactionRepo repository.TransactionRepository,
+	categoryRepo repository.CategoryRepository,
+	userRepo repository.UserRepository,
+	walletRepo repository.WalletRepository,
+	rateRepo repository.ExchangeRateRepository,
+	txManager repository.TxManager,
+) BudgetService {
 	return &budgetService{
 		budgetRepo:      budgetRepo,
+		limitRepo:       limitRepo,
+		ledgerRepo:      ledgerRepo,
 		transactionRepo: transactionRepo,
+		categoryRepo:    categoryRepo,
+		userRepo:        userRepo,
+		walletRepo:      walletRepo,
+		rateRepo:        rateRepo,
+		txManager:       txManager,
+	}
+}
+
+// WithTx returns a service whose reads and writes take part in the given
+// database transaction
+func (s *budgetService) WithTx(tx *gorm.DB) BudgetService {
+	return &budgetService{
+		budgetRepo:      s.budgetRepo.WithTx(tx),
+		limitRepo:       s.limitRepo.WithTx(tx),
+		ledgerRepo:      s.ledgerRepo.WithTx(tx),
+		transactionRepo: s.transactionRepo.WithTx(tx),
+		categoryRepo:    s.categoryRepo.WithTx(tx),
+		userRepo:        s.userRepo.WithTx(tx),
+		walletRepo:      s.walletRepo.WithTx(tx),
+		rateRepo:        s.rateRepo,
+		txManager:       s.txManager.WithTx(tx),
 	}
 }
 
@@ -97,6 +208,12 @@ func (s *budgetService) CreateBudget(userID uuid.UUID, req CreateBudgetRequest)
 		budgetType = "Variable"
 	}
 
+	// Budgets run by calendar month unless told otherwise
+	period := req.Period
+	if period == "" {
+		period = models.BudgetPeriodMonthly
+	}
+
 	budget := models.Budget{
 		UserID:         userID,
 		Category:       req.Category,
@@ -104,11 +221,28 @@ func (s *budgetService) CreateBudget(userID uuid.UUID, req CreateBudgetRequest)
 		Color:          req.Color,
 		Icon:           req.Icon,
 		IsRollover:     req.IsRollover,
+		IsEnvelope:     req.IsEnvelope,
 		Type:           budgetType,
 		AlertThreshold: alertThreshold,
+		Period:         period,
+		PeriodAnchor:   req.PeriodAnchor,
+		PeriodDays:     req.PeriodDays,
+	}
+	if err := validateBudgetPeriod(&budget); err != nil {
+		return nil, err
 	}
 
-	if err := s.budgetRepo.Create(&budget); err != nil {
+	// The budget and its first limit version are saved together
+	err := s.txManager.WithinTransaction(func(tx *gorm.DB) error {
+		if err := s.budgetRepo.WithTx(tx).Create(&budget); err != nil {
+			return err
+		}
+
+		// Keep the limit per period so later changes leave past periods alone
+		start, _ := budget.PeriodAt(time.Now())
+		return s.limitRepo.WithTx(tx).Upsert(&models.BudgetLimit{BudgetID: budget.ID, EffectiveFrom: start, LimitAmount: budget.LimitAmount})
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -157,12 +291,13 @@ func (s *budgetService) UpdateBudget(id, userID uuid.UUID, req UpdateBudgetReque
 		// Check if new category already has a budget
 		if req.Category != budget.Category {
 			existingBudget, _ := s.budgetRepo.FindByUserIDAndCategory(userID, req.Category)
-			if existingBudget != nil {
+			if existingBudget != nil && existingBudget.ID != budget.ID {
 				return nil, errors.New("budget already exists for this category")
 			}
 		}
 		budget.Category = req.Category
 	}
+	previousLimit := budget.LimitAmount
 	if req.LimitAmount > 0 {
 		budget.LimitAmount = req.LimitAmount
 	}
@@ -175,20 +310,73 @@ func (s *budgetService) UpdateBudget(id, userID uuid.UUID, req UpdateBudgetReque
 	if req.IsRollover != nil {
 		budget.IsRollover = *req.IsRollover
 	}
+	if req.IsEnvelope != nil {
+		budget.IsEnvelope = *req.IsEnvelope
+	}
 	if req.Type != "" {
 		budget.Type = req.Type
 	}
 	if req.AlertThreshold != nil {
 		budget.AlertThreshold = *req.AlertThreshold
 	}
+	period, anchor, days := budget.Period, budget.PeriodAnchor, budget.PeriodDays
+	if req.Period != "" {
+		budget.Period = req.Period
+		// Only custom periods have a length of their own
+		if req.Period != models.BudgetPeriodCustom {
+			budget.PeriodDays = 0
+		}
+	}
+	if req.PeriodAnchor != nil {
+		budget.PeriodAnchor = req.PeriodAnchor
+	}
+	if req.PeriodDays != nil {
+		budget.PeriodDays = *req.PeriodDays
+	}
+	if err := validateBudgetPeriod(budget); err != nil {
+		return nil, err
+	}
+	if budget.Period != period || budget.PeriodDays != days || !sameDate(budget.PeriodAnchor, anchor) {
+		budget.NextSnapshotAt = nil
+	}
+
+	// The budget and its limit versions change together. The ledger keeps the
+	// periods already recorded; the snapshot job records the new ones.
+	err = s.txManager.WithinTransaction(func(tx *gorm.DB) error {
+		if err := s.budgetRepo.WithTx(tx).Update(budget); err != nil {
+			return err
+		}
 
-	if err := s.budgetRepo.Update(budget); err != nil {
+		if budget.LimitAmount == previousLimit {
+			return nil
+		}
+		return versionLimit(s.limitRepo.WithTx(tx), budget, previousLimit)
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return budget, nil
 }
 
+// versionLimit records a budget's new limit as a version applying from its
+// current period on. A budget from before limits were versioned first gets a
+// version keeping its previous limit for the periods before.
+func versionLimit(limitRepo repository.BudgetLimitRepository, budget *models.Budget, previousLimit money.Amount) error {
+	versions, err := limitRepo.FindByBudgetIDs([]uuid.UUID{budget.ID})
+	if err != nil {
+		return err
+	}
+	if len(versions) == 0 {
+		first, _ := budget.PeriodAt(budget.CreatedAt)
+		if err := limitRepo.Upsert(&models.BudgetLimit{BudgetID: budget.ID, EffectiveFrom: first, LimitAmount: previousLimit}); err != nil {
+			return err
+		}
+	}
+	start, _ := budget.PeriodAt(time.Now())
+	return limitRepo.Upsert(&models.BudgetLimit{BudgetID: budget.ID, EffectiveFrom: start, LimitAmount: budget.LimitAmount})
+}
+
 // DeleteBudget deletes a budget
 func (s *budgetService) DeleteBudget(id, userID uuid.UUID) error {
 	budget, err := s.budgetRepo.FindByID(id)
@@ -208,67 +396,618 @@ func (s *budgetService) DeleteBudget(id, userID uuid.UUID) error {
 	return nil
 }
 
-// CheckBudgetStatus checks the spending status of all user budgets
-func (s *budgetService) CheckBudgetStatus(userID uuid.UUID) ([]*BudgetStatus, error) {
+// validateBudgetPeriod checks that a custom period has an anchor and a length,
+// that no other period is given a length, and that envelopes run by calendar
+// month without rollover, since they carry their balance forward anyway
+func validateBudgetPeriod(budget *models.Budget) error {
+	if budget.IsEnvelope {
+		if budget.Period != models.BudgetPeriodMonthly || budget.PeriodAnchor != nil {
+			return errors.New("envelope budgets run by calendar month")
+		}
+		if budget.IsRollover {
+			return errors.New("envelope budgets always carry their balance and cannot also roll over")
+		}
+	}
+	if budget.Period != models.BudgetPeriodCustom {
+		if budget.PeriodDays != 0 {
+			return errors.New("period_days only applies to custom periods")
+		}
+		return nil
+	}
+	if budget.PeriodAnchor == nil {
+		return errors.New("period_anchor is required for custom periods")
+	}
+	if budget.PeriodDays < 1 {
+		return errors.New("period_days is required for custom periods")
+	}
+	return nil
+}
+
+// CheckBudgetStatus checks the spending status of all user budgets over the
+// period of each budget that contains at. A budget on a parent category also
+// covers spending in its subcategories.
+func (s *budgetService) CheckBudgetStatus(userID uuid.UUID, at time.Time) ([]*BudgetStatus, error) {
 	budgets, err := s.budgetRepo.FindByUserID(userID)
 	if err != nil {
 		return nil, err
 	}
 
-	var statuses []*BudgetStatus
+	return s.statusesAt(userID, budgets, at)
+}
 
-	// Get current month date range (default period)
-	now := time.Now()
-	startDate := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
-	endDate := startDate.AddDate(0, 1, 0)
+// GetBudgetStatus checks the spending status of one budget over its period containing at
+func (s *budgetService) GetBudgetStatus(id, userID uuid.UUID, at time.Time) (*BudgetStatus, error) {
+	budget, err := s.GetBudgetByID(id, userID)
+	if err != nil {
+		return nil, err
+	}
 
+	statuses, err := s.statusesAt(userID, []*models.Budget{budget}, at)
+	if err != nil {
+		return nil, err
+	}
+
+	return statuses[0], nil
+}
+
+// budgetLimits holds the limit versions of budgets by budget, oldest first
+type budgetLimits map[uuid.UUID][]*models.BudgetLimit
+
+// loadLimits loads the limit versions of the budgets in one query
+func (s *budgetService) loadLimits(budgets []*models.Budget) (budgetLimits, error) {
+	ids := make([]uuid.UUID, 0, len(budgets))
 	for _, budget := range budgets {
-		// Get transactions for this category
-		allTransactions, err := s.transactionRepo.FindByUserID(userID, 10000, 0)
+		ids = append(ids, budget.ID)
+	}
+	versions, err := s.limitRepo.FindByBudgetIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	limits := make(budgetLimits, len(budgets))
+	for _, version := range versions {
+		limits[version.BudgetID] = append(limits[version.BudgetID], version)
+	}
+	return limits, nil
+}
+
+// at returns a budget's limit for the period starting on start: the latest
+// version in force by then, the first version for earlier periods, or the
+// budget's own limit when it has no versions
+func (l budgetLimits) at(budget *models.Budget, start time.Time) money.Amount {
+	versions := l[budget.ID]
+	if len(versions) == 0 {
+		return budget.LimitAmount
+	}
+	limit := versions[0].LimitAmount
+	for _, version := range versions {
+		if version.EffectiveFrom.After(start) {
+			break
+		}
+		limit = version.LimitAmount
+	}
+	return limit
+}
+
+// budgetPeriod is the date range of a budget period
+type budgetPeriod struct {
+	start time.Time
+	end   time.Time
+}
+
+// statusesAt computes the status of each budget over its period containing
+// at. Budgets sharing a period have their spending summed in one query.
+func (s *budgetService) statusesAt(userID uuid.UUID, budgets []*models.Budget, at time.Time) ([]*BudgetStatus, error) {
+	var statuses []*BudgetStatus
+
+	if len(budgets) == 0 {
+		return statuses, nil
+	}
+
+	userCategories, err := s.categoryRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	index := newCategoryIndex(userCategories)
+
+	limits, err := s.loadLimits(budgets)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := loadReportCurrency(s.userRepo, s.walletRepo, s.rateRepo, userID, nil, at)
+	if err != nil {
+		return nil, err
+	}
+
+	periods := make([]budgetPeriod, len(budgets))
+	categories := make(map[budgetPeriod][]string)
+	for i, budget := range budgets {
+		start, end := budget.PeriodAt(at)
+		periods[i] = budgetPeriod{start: start, end: end}
+		categories[periods[i]] = append(categories[periods[i]], index.family(budget.Category)...)
+	}
+
+	// Sum each period's spending per category in the base currency; only
+	// completed spending counts, so income and transfers between wallets
+	// never consume a budget
+	spent := make(map[budgetPeriod]map[string]money.Amount, len(categories))
+	for period, periodCategories := range categories {
+		aggregates, err := s.transactionRepo.Aggregate(repository.TransactionFilter{
+			UserID:     userID,
+			StartDate:  period.start,
+			EndDate:    period.end,
+			Categories: periodCategories,
+			Statuses:   []string{"Completed"},
+			Types:      []string{models.TransactionTypeExpense},
+		}, repository.GroupByCategory, repository.GroupByWallet)
 		if err != nil {
 			return nil, err
 		}
 
-		// Calculate spent amount for this category in the period
-		var spentAmount float64
-		for _, txn := range allTransactions {
-			// Only count transactions that are completed and match category and date range
-			// Note: Transaction model doesn't have Type field, so we count all transactions
-			if txn.Status == "Completed" &&
-				txn.Category == budget.Category &&
-				txn.TransactionDate.After(startDate) &&
-				txn.TransactionDate.Before(endDate) {
-				spentAmount += txn.Amount
+		spent[period] = make(map[string]money.Amount, len(aggregates))
+		for _, aggregate := range aggregates {
+			amount, err := report.convertAggregate(aggregate)
+			if err != nil {
+				return nil, err
 			}
+			spent[period][strings.ToLower(aggregate.Category)] += amount
 		}
+	}
+
+	for i, budget := range budgets {
+		var spentAmount money.Amount
+		for _, category := range index.family(budget.Category) {
+			spentAmount += spent[periods[i]][strings.ToLower(category)]
+		}
+
+		var carriedIn money.Amount
+		if budget.IsRollover {
+			if carriedIn, _, err = s.rollover(userID, budget, limits, report, index.family(budget.Category), at); err != nil {
+				return nil, err
+			}
+		}
+		baseLimit := limits.at(budget, periods[i].start)
+		limit := baseLimit + carriedIn
 
 		// Calculate status metrics
-		remainingAmount := budget.LimitAmount - spentAmount
-		percentageUsed := (spentAmount / budget.LimitAmount) * 100
-		isOverBudget := spentAmount > budget.LimitAmount
+		remainingAmount := limit - spentAmount
+		percentageUsed := float64(0)
+		if limit > 0 {
+			percentageUsed = spentAmount.Percent(limit)
+		}
+		isOverBudget := spentAmount > limit
 		isNearLimit := percentageUsed >= float64(budget.AlertThreshold) && !isOverBudget
 
+		period := budget.Period
+		if period == "" {
+			period = models.BudgetPeriodMonthly
+		}
+
 		status := &BudgetStatus{
 			BudgetID:        budget.ID,
 			Category:        budget.Category,
-			LimitAmount:     budget.LimitAmount,
+			Period:          period,
+			PeriodStart:     periods[i].start,
+			PeriodEnd:       periods[i].end,
+			BaseLimit:       baseLimit,
+			CarriedIn:       carriedIn,
+			EffectiveLimit:  limit,
+			LimitAmount:     limit,
 			SpentAmount:     spentAmount,
 			RemainingAmount: remainingAmount,
 			PercentageUsed:  percentageUsed,
 			IsOverBudget:    isOverBudget,
 			IsNearLimit:     isNearLimit,
 			AlertThreshold:  budget.AlertThreshold,
+			Currency:        report.base,
 		}
 
 		statuses = append(statuses, status)
 	}
 
+	// Rollovers convert earlier periods too, so what was left out is known only now
+	unconverted := report.unconverted()
+	for _, status := range statuses {
+		status.UnconvertedCurrencies = unconverted
+	}
+
 	return statuses, nil
 }
 
-// GetBudgetSummary returns an overall budget summary for a user
-func (s *budgetService) GetBudgetSummary(userID uuid.UUID) (*BudgetSummary, error) {
-	statuses, err := s.CheckBudgetStatus(userID)
+// rollover works out what a rollover budget carries into its period
+// containing at. Starting from the period the budget was created in, each
+// period passes on what was left of its limit, or takes away what was
+// overspent. It also returns the ledger entries of the periods that have
+// closed, leaving it to the caller to record them.
+func (s *budgetService) rollover(userID uuid.UUID, budget *models.Budget, limits budgetLimits, report *reportCurrency, categories []string, at time.Time) (money.Amount, []*models.BudgetLedgerEntry, error) {
+	first, _ := budget.PeriodAt(budget.CreatedAt)
+	current, _ := budget.PeriodAt(at)
+	if budget.CreatedAt.IsZero() || !first.Before(current) {
+		return 0, nil, nil
+	}
+
+	spent, err := s.spendingByPeriod(userID, budget, report, categories, first, current)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var carried money.Amount
+	var closed []*models.BudgetLedgerEntry
+	now := time.Now()
+	for start := first; start.Before(current); {
+		_, end := budget.PeriodAt(start)
+		baseLimit := limits.at(budget, start)
+		entry := &models.BudgetLedgerEntry{
+			BudgetID:       budget.ID,
+			UserID:         userID,
+			PeriodStart:    start,
+			PeriodEnd:      end,
+			BaseLimit:      baseLimit,
+			CarriedIn:      carried,
+			EffectiveLimit: baseLimit + carried,
+			SpentAmount:    spent[start],
+		}
+		entry.CarriedOut = entry.EffectiveLimit - entry.SpentAmount
+		if !end.After(now) {
+			closed = append(closed, entry)
+		}
+		carried = entry.CarriedOut
+		start = end
+	}
+
+	return carried, closed, nil
+}
+
+// spendingByPeriod sums a budget's completed spending from start up to end,
+// keyed by the start of the budget period it fell in and converted into the
+// base currency. The spending is read by day and wallet in one query.
+func (s *budgetService) spendingByPeriod(userID uuid.UUID, budget *models.Budget, report *reportCurrency, categories []string, start, end time.Time) (map[time.Time]money.Amount, error) {
+	aggregates, err := s.transactionRepo.Aggregate(repository.TransactionFilter{
+		UserID:     userID,
+		StartDate:  start,
+		EndDate:    end,
+		Categories: categories,
+		Statuses:   []string{"Completed"},
+		Types:      []string{models.TransactionTypeExpense},
+	}, repository.GroupByDay, repository.GroupByWallet)
+	if err != nil {
+		return nil, err
+	}
+
+	spent := make(map[time.Time]money.Amount)
+	for _, aggregate := range aggregates {
+		day, err := time.Parse("2006-01-02", aggregate.Period)
+		if err != nil {
+			return nil, err
+		}
+		amount, err := report.convertAggregate(aggregate)
+		if err != nil {
+			return nil, err
+		}
+		periodStart, _ := budget.PeriodAt(day)
+		spent[periodStart] += amount
+	}
+	return spent, nil
+}
+
+// recordLedger appends the ledger entries of periods that are not recorded
+// yet, and a correcting revision for each period whose numbers changed since
+// its latest revision. It returns how many entries were appended.
+func (s *budgetService) recordLedger(budgetID uuid.UUID, entries []*models.BudgetLedgerEntry) (int, error) {
+	if len(entries) == 0 {
+		return 0, nil
+	}
+
+	recorded, err := s.ledgerRepo.FindByBudgetID(budgetID)
+	if err != nil {
+		return 0, err
+	}
+	// Revisions come in order, so the last one seen for a period is its latest
+	latest := make(map[string]*models.BudgetLedgerEntry, len(recorded))
+	for _, entry := range recorded {
+		latest[entry.PeriodStart.Format("2006-01-02")] = entry
+	}
+
+	var appended []*models.BudgetLedgerEntry
+	for _, entry := range entries {
+		entry.Revision = 1
+		if old, ok := latest[entry.PeriodStart.Format("2006-01-02")]; ok {
+			if old.PeriodEnd.Equal(entry.PeriodEnd) && old.BaseLimit == entry.BaseLimit && old.CarriedIn == entry.CarriedIn &&
+				old.SpentAmount == entry.SpentAmount && old.CarriedOut == entry.CarriedOut {
+				continue
+			}
+			entry.Revision = old.Revision + 1
+		}
+		appended = append(appended, entry)
+	}
+
+	return len(appended), s.ledgerRepo.Append(appended)
+}
+
+// GetBudgetLedger returns a budget's ledger as the snapshot job recorded it,
+// oldest period first with each period's corrections after the entry they
+// correct
+func (s *budgetService) GetBudgetLedger(id, userID uuid.UUID) ([]*models.BudgetLedgerEntry, error) {
+	budget, err := s.GetBudgetByID(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.ledgerRepo.FindByBudgetID(budget.ID)
+}
+
+// GetBudgetHistory reports each budget's limit against its actual spending
+// over up to periods periods, ending with the period containing at. Periods
+// before the budget was created are left out.
+func (s *budgetService) GetBudgetHistory(userID uuid.UUID, periods int, at time.Time) ([]*BudgetHistory, error) {
+	if periods < 1 {
+		return nil, errors.New("periods must be at least 1")
+	}
+
+	budgets, err := s.budgetRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]*BudgetHistory, 0, len(budgets))
+	if len(budgets) == 0 {
+		return history, nil
+	}
+
+	userCategories, err := s.categoryRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	index := newCategoryIndex(userCategories)
+
+	limits, err := s.loadLimits(budgets)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := loadReportCurrency(s.userRepo, s.walletRepo, s.rateRepo, userID, nil, at)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for _, budget := range budgets {
+		categories := index.family(budget.Category)
+
+		// Walk back from the period containing at
+		first, _ := budget.PeriodAt(budget.CreatedAt)
+		var ranges []budgetPeriod
+		for start, end := budget.PeriodAt(at); len(ranges) < periods; start, end = budget.PeriodAt(start.AddDate(0, 0, -1)) {
+			ranges = append([]budgetPeriod{{start: start, end: end}}, ranges...)
+			if !budget.CreatedAt.IsZero() && !start.After(first) {
+				break
+			}
+		}
+
+		// What a rollover budget carried into each period it has closed, and
+		// into the period containing at
+		carriedIn := make(map[string]money.Amount)
+		if budget.IsRollover {
+			carried, closed, err := s.rollover(userID, budget, limits, report, categories, at)
+			if err != nil {
+				return nil, err
+			}
+			for _, entry := range closed {
+				carriedIn[entry.PeriodStart.Format("2006-01-02")] = entry.CarriedIn
+			}
+			carriedIn[ranges[len(ranges)-1].start.Format("2006-01-02")] = carried
+		}
+
+		// A period's latest revision comes last and replaces the earlier ones
+		recorded, err := s.ledgerRepo.FindByBudgetID(budget.ID)
+		if err != nil {
+			return nil, err
+		}
+		snapshots := make(map[string]*models.BudgetLedgerEntry, len(recorded))
+		for _, entry := range recorded {
+			snapshots[entry.PeriodStart.Format("2006-01-02")] = entry
+		}
+
+		spent, err := s.spendingByPeriod(userID, budget, report, categories, ranges[0].start, ranges[len(ranges)-1].end)
+		if err != nil {
+			return nil, err
+		}
+
+		period := budget.Period
+		if period == "" {
+			period = models.BudgetPeriodMonthly
+		}
+		entry := &BudgetHistory{BudgetID: budget.ID, Category: budget.Category, Period: period}
+		for _, r := range ranges {
+			actual := &BudgetPeriodActual{
+				PeriodStart: r.start,
+				PeriodEnd:   r.end,
+				IsClosed:    !r.end.After(now),
+			}
+			// A period recorded before the budget's periods changed may start on
+			// the same day but end on another, and is not this period
+			if snapshot, ok := snapshots[r.start.Format("2006-01-02")]; ok && actual.IsClosed && snapshot.PeriodEnd.Equal(r.end) {
+				actual.LimitAmount = snapshot.BaseLimit
+				actual.CarriedIn = snapshot.CarriedIn
+				actual.EffectiveLimit = snapshot.EffectiveLimit
+				actual.ActualAmount = snapshot.SpentAmount
+			} else {
+				actual.LimitAmount = limits.at(budget, r.start)
+				actual.CarriedIn = carriedIn[r.start.Format("2006-01-02")]
+				actual.EffectiveLimit = actual.LimitAmount + actual.CarriedIn
+				actual.ActualAmount = spent[r.start]
+			}
+
+			actual.Variance = actual.EffectiveLimit - actual.ActualAmount
+			if actual.EffectiveLimit > 0 {
+				actual.PercentageUsed = actual.ActualAmount.Percent(actual.EffectiveLimit)
+			}
+			actual.IsOverBudget = actual.ActualAmount > actual.EffectiveLimit
+			entry.Periods = append(entry.Periods, actual)
+		}
+		history = append(history, entry)
+	}
+
+	unconverted := report.unconverted()
+	for _, budgetHistory := range history {
+		budgetHistory.UnconvertedCurrencies = unconverted
+	}
+
+	return history, nil
+}
+
+// SnapshotClosedPeriods records the final numbers of the periods that have
+// closed for each budget due, and when its current period closes. Run by the
+// scheduler; it returns how many ledger entries were recorded.
+func (s *budgetService) SnapshotClosedPeriods(now time.Time) (int, error) {
+	budgets, err := s.budgetRepo.FindSnapshotDue(now, snapshotBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	if len(budgets) == 0 {
+		return 0, nil
+	}
+
+	limits, err := s.loadLimits(budgets)
+	if err != nil {
+		return 0, err
+	}
+
+	snapshotted := 0
+	indexes := make(map[uuid.UUID]*categoryIndex)
+	reports := make(map[uuid.UUID]*reportCurrency)
+	var errs []error
+	for _, budget := range budgets {
+		index, ok := indexes[budget.UserID]
+		if !ok {
+			userCategories, err := s.categoryRepo.FindByUserID(budget.UserID)
+			if err != nil {
+				errs = append(errs, s.snapshotFailed(budget, now, err))
+				continue
+			}
+			index = newCategoryIndex(userCategories)
+			indexes[budget.UserID] = index
+		}
+		report, ok := reports[budget.UserID]
+		if !ok {
+			if report, err = loadReportCurrency(s.userRepo, s.walletRepo, s.rateRepo, budget.UserID, nil, now); err != nil {
+				errs = append(errs, s.snapshotFailed(budget, now, err))
+				continue
+			}
+			reports[budget.UserID] = report
+		}
+
+		recorded, err := s.snapshotBudget(budget, limits, report, index.family(budget.Category), now)
+		if err != nil {
+			errs = append(errs, s.snapshotFailed(budget, now, err))
+			continue
+		}
+		snapshotted += recorded
+	}
+
+	return snapshotted, errors.Join(errs...)
+}
+
+// snapshotFailed puts off retrying a budget whose snapshot failed, so that it
+// does not hold up the budgets queued behind it, and returns the error to
+// report. The snapshot that is due stays due, so no period is skipped.
+func (s *budgetService) snapshotFailed(budget *models.Budget, now time.Time, err error) error {
+	failures := budget.SnapshotFailures + 1
+	delay := snapshotRetryDelay
+	for i := 1; i < failures && delay < snapshotMaxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > snapshotMaxRetryDelay {
+		delay = snapshotMaxRetryDelay
+	}
+
+	err = fmt.Errorf("budget %s: %w", budget.ID, err)
+	if deferErr := s.budgetRepo.DeferSnapshot(budget.ID, failures, now.Add(delay)); deferErr != nil {
+		return errors.Join(err, deferErr)
+	}
+	return err
+}
+
+// snapshotBudget records every closed period of a budget from the one its
+// next snapshot was due for, so periods missed while the job was not running
+// are caught up. A budget never snapshotted starts from its first period.
+// It then moves the next snapshot to when the current period closes and
+// returns how many ledger entries were recorded.
+func (s *budgetService) snapshotBudget(budget *models.Budget, limits budgetLimits, report *reportCurrency, categories []string, now time.Time) (int, error) {
+	current, next := budget.PeriodAt(now)
+	from, _ := budget.PeriodAt(budget.CreatedAt)
+	if budget.NextSnapshotAt != nil {
+		// The snapshot was due when the period current at the last run closed
+		if due, _ := budget.PeriodAt(budget.NextSnapshotAt.AddDate(0, 0, -1)); due.After(from) {
+			from = due
+		}
+	}
+
+	recorded := 0
+	if from.Before(current) {
+		var err error
+		if recorded, err = s.snapshotPeriods(budget, limits, report, categories, from, current); err != nil {
+			return 0, err
+		}
+	}
+
+	return recorded, s.budgetRepo.SetNextSnapshot(budget.ID, &next)
+}
+
+// snapshotPeriods records a budget's closed periods from start up to end, or
+// a correction for each whose numbers changed since it was recorded. A
+// rollover budget's ledger is brought up to date as a whole, since each
+// period depends on the ones before.
+func (s *budgetService) snapshotPeriods(budget *models.Budget, limits budgetLimits, report *reportCurrency, categories []string, start, end time.Time) (int, error) {
+	if budget.IsRollover {
+		_, closed, err := s.rollover(budget.UserID, budget, limits, report, categories, end)
+		if err != nil {
+			return 0, err
+		}
+		if err := report.complete(); err != nil {
+			return 0, err
+		}
+		return s.recordLedger(budget.ID, closed)
+	}
+
+	spent, err := s.spendingByPeriod(budget.UserID, budget, report, categories, start, end)
+	if err != nil {
+		return 0, err
+	}
+	var entries []*models.BudgetLedgerEntry
+	for periodStart := start; periodStart.Before(end); {
+		_, periodEnd := budget.PeriodAt(periodStart)
+		baseLimit := limits.at(budget, periodStart)
+		entries = append(entries, &models.BudgetLedgerEntry{
+			BudgetID:       budget.ID,
+			UserID:         budget.UserID,
+			PeriodStart:    periodStart,
+			PeriodEnd:      periodEnd,
+			BaseLimit:      baseLimit,
+			EffectiveLimit: baseLimit,
+			SpentAmount:    spent[periodStart],
+		})
+		periodStart = periodEnd
+	}
+	if err := report.complete(); err != nil {
+		return 0, err
+	}
+	return s.recordLedger(budget.ID, entries)
+}
+
+// sameDate reports whether two optional dates fall on the same day
+func sameDate(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.UTC().Format("2006-01-02") == b.UTC().Format("2006-01-02")
+}
+
+// GetBudgetSummary returns an overall budget summary for a user over the
+// budget periods containing at
+func (s *budgetService) GetBudgetSummary(userID uuid.UUID, at time.Time) (*BudgetSummary, error) {
+	statuses, err := s.CheckBudgetStatus(userID, at)
 	if err != nil {
 		return nil, err
 	}
@@ -278,6 +1017,8 @@ func (s *budgetService) GetBudgetSummary(userID uuid.UUID) (*BudgetSummary, erro
 	}
 
 	for _, status := range statuses {
+		summary.Currency = status.Currency
+		summary.UnconvertedCurrencies = status.UnconvertedCurrencies
 		summary.TotalLimit += status.LimitAmount
 		summary.TotalSpent += status.SpentAmount
 		summary.TotalRemaining += status.RemainingAmount
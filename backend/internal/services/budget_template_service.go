@@ -0,0 +1,500 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"github.com/nyunja/fity-budget-backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// What applying a budget plan does to each budget
+const (
+	BudgetPlanCreate    = "create"
+	BudgetPlanUpdate    = "update"
+	BudgetPlanUnchanged = "unchanged"
+)
+
+// defaultBudgetColor colors generated budgets whose category has no color
+const defaultBudgetColor = "#4F46E5"
+
+// BudgetTemplateService defines the interface for generating budgets from
+// templates and rescaling them when income changes
+type BudgetTemplateService interface {
+	ListTemplates(userID uuid.UUID) (*BudgetTemplateList, error)
+	CreateTemplate(userID uuid.UUID, req CreateBudgetTemplateRequest) (*models.BudgetTemplate, error)
+	DeleteTemplate(id, userID uuid.UUID) error
+	PreviewBudgets(userID uuid.UUID, req GenerateBudgetsRequest) (*BudgetPlan, error)
+	GenerateBudgets(userID uuid.UUID, req GenerateBudgetsRequest) (*BudgetPlan, error)
+	PreviewRescale(userID uuid.UUID, income money.Amount) (*BudgetPlan, error)
+	RescaleBudgets(userID uuid.UUID, income money.Amount) (*BudgetPlan, error)
+}
+
+type budgetTemplateService struct {
+	templateRepo  repository.BudgetTemplateRepository
+	budgetRepo    repository.BudgetRepository
+	userRepo      repository.UserRepository
+	categoryRepo  repository.CategoryRepository
+	budgetService BudgetService
+	txManager     repository.TxManager
+}
+
+// BuiltinBudgetTemplate splits income between groups of categories, such as
+// needs, wants and savings. The user picks the categories in each group.
+type BuiltinBudgetTemplate struct {
+	Key         string                 `json:"key"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Groups      []*BudgetTemplateGroup `json:"groups"`
+}
+
+// BudgetTemplateGroup is a share of income for one group of a built-in template
+type BudgetTemplateGroup struct {
+	Key        string  `json:"key"`
+	Name       string  `json:"name"`
+	Percentage float64 `json:"percentage"`
+}
+
+// builtinBudgetTemplates are the templates every user can generate budgets from
+var builtinBudgetTemplates = []*BuiltinBudgetTemplate{
+	{
+		Key:         "50-30-20",
+		Name:        "50/30/20",
+		Description: "Half of income for needs, 30% for wants and 20% for savings",
+		Groups: []*BudgetTemplateGroup{
+			{Key: "needs", Name: "Needs", Percentage: 50},
+			{Key: "wants", Name: "Wants", Percentage: 30},
+			{Key: "savings", Name: "Savings", Percentage: 20},
+		},
+	},
+	{
+		Key:         "70-20-10",
+		Name:        "70/20/10",
+		Description: "70% of income for living expenses, 20% for savings and 10% for debt or giving",
+		Groups: []*BudgetTemplateGroup{
+			{Key: "living", Name: "Living", Percentage: 70},
+			{Key: "savings", Name: "Savings", Percentage: 20},
+			{Key: "giving", Name: "Debt & Giving", Percentage: 10},
+		},
+	},
+}
+
+// BudgetTemplateList holds the built-in templates and those the user saved
+type BudgetTemplateList struct {
+	BuiltIn []*BuiltinBudgetTemplate `json:"built_in"`
+	Saved   []*models.BudgetTemplate `json:"saved"`
+}
+
+// CreateBudgetTemplateRequest represents the data needed to save a template
+type CreateBudgetTemplateRequest struct {
+	Name  string
+	Lines []BudgetTemplateLineRequest
+}
+
+// BudgetTemplateLineRequest gives a category a percentage of income
+type BudgetTemplateLineRequest struct {
+	Category   string
+	Percentage float64
+}
+
+// GenerateBudgetsRequest picks a built-in template by Template, with the
+// categories for each of its groups, or a saved template by TemplateID.
+// Groups left without categories get one budget named after the group.
+// Without an income the user's monthly income is used.
+type GenerateBudgetsRequest struct {
+	Template   string
+	TemplateID *uuid.UUID
+	Groups     map[string][]string
+	Income     money.Amount
+}
+
+// BudgetPlan lists the budgets a template or rescale sets up, and what
+// applying it does to each
+type BudgetPlan struct {
+	Template    string           `json:"template,omitempty"`
+	Income      money.Amount     `json:"income"`
+	Total       money.Amount     `json:"total"`
+	Unallocated money.Amount     `json:"unallocated"`
+	Budgets     []*PlannedBudget `json:"budgets"`
+}
+
+// PlannedBudget is one budget of a plan. CurrentLimit and BudgetID are set
+// when the user already budgets for the category.
+type PlannedBudget struct {
+	Category     string       `json:"category"`
+	Group        string       `json:"group,omitempty"`
+	Percentage   float64      `json:"percentage"`
+	LimitAmount  money.Amount `json:"limit_amount"`
+	BudgetID     *uuid.UUID   `json:"budget_id,omitempty"`
+	CurrentLimit money.Amount `json:"current_limit"`
+	Action       string       `json:"action"`
+}
+
+func NewBudgetTemplateService(
+	templateRepo repository.BudgetTemplateRepository,
+	budgetRepo repository.BudgetRepository,
+	userRepo repository.UserRepository,
+	categoryRepo repository.CategoryRepository,
+	budgetService BudgetService,
+	txManager repository.TxManager,
+) BudgetTemplateService {
+	return &budgetTemplateService{
+		templateRepo:  templateRepo,
+		budgetRepo:    budgetRepo,
+		userRepo:      userRepo,
+		categoryRepo:  categoryRepo,
+		budgetService: budgetService,
+		txManager:     txManager,
+	}
+}
+
+// ListTemplates returns the built-in templates and the user's saved ones
+func (s *budgetTemplateService) ListTemplates(userID uuid.UUID) (*BudgetTemplateList, error) {
+	saved, err := s.templateRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return &BudgetTemplateList{BuiltIn: builtinBudgetTemplates, Saved: saved}, nil
+}
+
+// CreateTemplate saves a template of categories and the percentage of income
+// each gets. The percentages may not add up to more than 100.
+func (s *budgetTemplateService) CreateTemplate(userID uuid.UUID, req CreateBudgetTemplateRequest) (*models.BudgetTemplate, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+	if existing, _ := s.templateRepo.FindByUserIDAndName(userID, name); existing != nil {
+		return nil, errors.New("budget template already exists with this name")
+	}
+	if len(req.Lines) == 0 {
+		return nil, errors.New("a template needs at least one category")
+	}
+
+	template := &models.BudgetTemplate{UserID: userID, Name: name}
+	seen := make(map[string]bool)
+	var total int64
+	for _, line := range req.Lines {
+		category := strings.TrimSpace(line.Category)
+		if category == "" {
+			return nil, errors.New("category is required on every line")
+		}
+		if seen[strings.ToLower(category)] {
+			return nil, fmt.Errorf("category %s appears more than once", category)
+		}
+		seen[strings.ToLower(category)] = true
+		if line.Percentage <= 0 || line.Percentage > 100 {
+			return nil, fmt.Errorf("percentage for %s must be greater than 0 and at most 100", category)
+		}
+		// Percentages keep two decimals and are added up in hundredths
+		points := basisPoints(line.Percentage)
+		total += points
+		template.Lines = append(template.Lines, models.BudgetTemplateLine{Category: category, Percentage: float64(points) / 100})
+	}
+	if total > 10000 {
+		return nil, fmt.Errorf("percentages add up to %.2f, more than 100", float64(total)/100)
+	}
+
+	if err := s.templateRepo.Create(template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// DeleteTemplate deletes a saved template. Budgets generated from it stay.
+func (s *budgetTemplateService) DeleteTemplate(id, userID uuid.UUID) error {
+	template, err := s.templateRepo.FindByID(id)
+	if err != nil {
+		return errors.New("budget template not found")
+	}
+	if template.UserID != userID {
+		return errors.New("unauthorized access to budget template")
+	}
+
+	return s.txManager.WithinTransaction(func(tx *gorm.DB) error {
+		return s.templateRepo.WithTx(tx).Delete(id)
+	})
+}
+
+// PreviewBudgets works out the budgets a template would set up from the
+// income, without changing anything
+func (s *budgetTemplateService) PreviewBudgets(userID uuid.UUID, req GenerateBudgetsRequest) (*BudgetPlan, error) {
+	plan, _, err := s.planTemplate(userID, req)
+	return plan, err
+}
+
+// GenerateBudgets creates the budgets a template sets up and updates the
+// limits of the categories the user already budgets for. The income used
+// becomes the user's monthly income, so budgets rescale from it later.
+func (s *budgetTemplateService) GenerateBudgets(userID uuid.UUID, req GenerateBudgetsRequest) (*BudgetPlan, error) {
+	plan, user, err := s.planTemplate(userID, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyPlan(user, plan); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// PreviewRescale works out the budget limits scaled from the user's monthly
+// income to a new income, without changing anything
+func (s *budgetTemplateService) PreviewRescale(userID uuid.UUID, income money.Amount) (*BudgetPlan, error) {
+	plan, _, err := s.planRescale(userID, income)
+	return plan, err
+}
+
+// RescaleBudgets scales every budget limit by how much the user's monthly
+// income changed, and records the new income. Each new limit applies from
+// the budget's current period on.
+func (s *budgetTemplateService) RescaleBudgets(userID uuid.UUID, income money.Amount) (*BudgetPlan, error) {
+	plan, user, err := s.planRescale(userID, income)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyPlan(user, plan); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// planTemplate turns a template into budgets for the user's income
+func (s *budgetTemplateService) planTemplate(userID uuid.UUID, req GenerateBudgetsRequest) (*BudgetPlan, *models.User, error) {
+	if (req.Template != "") == (req.TemplateID != nil) {
+		return nil, nil, errors.New("choose a built-in template or a saved template_id")
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, nil, errors.New("user not found")
+	}
+	income := req.Income
+	if income == 0 {
+		income = user.MonthlyIncome
+	}
+	if income <= 0 {
+		return nil, nil, errors.New("income must be greater than zero; pass an income or set a monthly income")
+	}
+	income = income.Round(user.Currency)
+
+	plan := &BudgetPlan{Income: income}
+	if req.TemplateID != nil {
+		template, err := s.templateRepo.FindByID(*req.TemplateID)
+		if err != nil {
+			return nil, nil, errors.New("budget template not found")
+		}
+		if template.UserID != userID {
+			return nil, nil, errors.New("unauthorized access to budget template")
+		}
+		plan.Template = template.Name
+		for _, line := range template.Lines {
+			plan.Budgets = append(plan.Budgets, &PlannedBudget{
+				Category:    line.Category,
+				LimitAmount: income.MulRat(basisPoints(line.Percentage), 10000).Round(user.Currency),
+			})
+		}
+	} else {
+		var builtin *BuiltinBudgetTemplate
+		for _, template := range builtinBudgetTemplates {
+			if template.Key == req.Template {
+				builtin = template
+				break
+			}
+		}
+		if builtin == nil {
+			return nil, nil, fmt.Errorf("unknown budget template %q", req.Template)
+		}
+		plan.Template = builtin.Name
+		if plan.Budgets, err = planGroups(builtin, req.Groups, income, user.Currency); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, planned := range plan.Budgets {
+		if seen[strings.ToLower(planned.Category)] {
+			return nil, nil, fmt.Errorf("category %s appears more than once", planned.Category)
+		}
+		seen[strings.ToLower(planned.Category)] = true
+		if planned.LimitAmount <= 0 {
+			return nil, nil, fmt.Errorf("the budget for %s would be zero at this income", planned.Category)
+		}
+	}
+
+	budgets, err := s.budgetRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	comparePlan(plan, budgets)
+	return plan, user, nil
+}
+
+// planGroups splits each group's share of income evenly between the
+// categories chosen for it
+func planGroups(template *BuiltinBudgetTemplate, groups map[string][]string, income money.Amount, currency string) ([]*PlannedBudget, error) {
+	for key := range groups {
+		known := false
+		for _, group := range template.Groups {
+			known = known || group.Key == key
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown group %q for the %s template", key, template.Name)
+		}
+	}
+
+	var planned []*PlannedBudget
+	for _, group := range template.Groups {
+		var categories []string
+		for _, category := range groups[group.Key] {
+			if category = strings.TrimSpace(category); category != "" {
+				categories = append(categories, category)
+			}
+		}
+		if len(categories) == 0 {
+			categories = []string{group.Name}
+		}
+
+		weights := make([]int64, len(categories))
+		for i := range weights {
+			weights[i] = 1
+		}
+		amounts := income.MulRat(basisPoints(group.Percentage), 10000).Allocate(weights...)
+		for i, category := range categories {
+			planned = append(planned, &PlannedBudget{
+				Category:    category,
+				Group:       group.Name,
+				LimitAmount: amounts[i].Round(currency),
+			})
+		}
+	}
+	return planned, nil
+}
+
+// planRescale scales the limits of all the user's budgets from their monthly
+// income to the new one
+func (s *budgetTemplateService) planRescale(userID uuid.UUID, income money.Amount) (*BudgetPlan, *models.User, error) {
+	if income <= 0 {
+		return nil, nil, errors.New("income must be greater than zero")
+	}
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, nil, errors.New("user not found")
+	}
+	if user.MonthlyIncome <= 0 {
+		return nil, nil, errors.New("no monthly income on record to rescale from")
+	}
+	income = income.Round(user.Currency)
+
+	budgets, err := s.budgetRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	plan := &BudgetPlan{Income: income, Budgets: []*PlannedBudget{}}
+	for _, budget := range budgets {
+		limit := budget.LimitAmount.MulRat(int64(income), int64(user.MonthlyIncome)).Round(user.Currency)
+		if limit <= 0 {
+			return nil, nil, fmt.Errorf("the budget for %s would be zero at this income", budget.Category)
+		}
+		plan.Budgets = append(plan.Budgets, &PlannedBudget{Category: budget.Category, LimitAmount: limit})
+	}
+
+	comparePlan(plan, budgets)
+	return plan, user, nil
+}
+
+// comparePlan matches planned budgets to the user's budgets by category,
+// ignoring case, sets what applying the plan does to each and totals the
+// plan with each budget's share of the income
+func comparePlan(plan *BudgetPlan, budgets []*models.Budget) {
+	existing := make(map[string]*models.Budget, len(budgets))
+	for _, budget := range budgets {
+		existing[strings.ToLower(budget.Category)] = budget
+	}
+
+	for _, planned := range plan.Budgets {
+		plan.Total += planned.LimitAmount
+		planned.Percentage = math.Round(planned.LimitAmount.Percent(plan.Income)*100) / 100
+
+		budget, ok := existing[strings.ToLower(planned.Category)]
+		if !ok {
+			planned.Action = BudgetPlanCreate
+			continue
+		}
+		id := budget.ID
+		planned.BudgetID = &id
+		planned.Category = budget.Category
+		planned.CurrentLimit = budget.LimitAmount
+		if budget.LimitAmount == planned.LimitAmount {
+			planned.Action = BudgetPlanUnchanged
+		} else {
+			planned.Action = BudgetPlanUpdate
+		}
+	}
+	plan.Unallocated = plan.Income - plan.Total
+}
+
+// applyPlan creates and updates budgets as planned and records the income
+// they were planned from, all in one transaction so a failing budget leaves
+// nothing applied. New budgets take the color of their category when it has
+// one.
+func (s *budgetTemplateService) applyPlan(user *models.User, plan *BudgetPlan) error {
+	userCategories, err := s.categoryRepo.FindByUserID(user.ID)
+	if err != nil {
+		return err
+	}
+	index := newCategoryIndex(userCategories)
+
+	return s.txManager.WithinTransaction(func(tx *gorm.DB) error {
+		budgetService := s.budgetService.WithTx(tx)
+		for _, planned := range plan.Budgets {
+			switch planned.Action {
+			case BudgetPlanCreate:
+				color := defaultBudgetColor
+				if category, ok := index.byName[strings.ToLower(planned.Category)]; ok {
+					planned.Category = category.Name
+					if category.Color != "" {
+						color = category.Color
+					}
+				}
+				budget, err := budgetService.CreateBudget(user.ID, CreateBudgetRequest{
+					Category:    planned.Category,
+					LimitAmount: planned.LimitAmount,
+					Color:       color,
+				})
+				if err != nil {
+					return fmt.Errorf("budget %s: %w", planned.Category, err)
+				}
+				planned.BudgetID = &budget.ID
+			case BudgetPlanUpdate:
+				if _, err := budgetService.UpdateBudget(*planned.BudgetID, user.ID, UpdateBudgetRequest{LimitAmount: planned.LimitAmount}); err != nil {
+					return fmt.Errorf("budget %s: %w", planned.Category, err)
+				}
+			}
+		}
+		return recordIncome(s.userRepo.WithTx(tx), user, plan.Income)
+	})
+}
+
+// recordIncome saves the income budgets were planned from as the user's
+// monthly income
+func recordIncome(userRepo repository.UserRepository, user *models.User, income money.Amount) error {
+	if user.MonthlyIncome == income {
+		return nil
+	}
+	if err := userRepo.UpdateMonthlyIncome(user.ID, income); err != nil {
+		return err
+	}
+	user.MonthlyIncome = income
+	return nil
+}
+
+// basisPoints converts a percentage with up to two decimals to hundredths of a percent
+func basisPoints(percentage float64) int64 {
+	return int64(math.Round(percentage * 100))
+}
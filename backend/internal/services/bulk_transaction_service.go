@@ -0,0 +1,385 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"github.com/nyunja/fity-budget-backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Operations a bulk request can apply
+const (
+	BulkRecategorize = "recategorize"
+	BulkSetStatus    = "set_status"
+	BulkMoveWallet   = "move_wallet"
+	BulkAddTags      = "add_tags"
+	BulkRemoveTags   = "remove_tags"
+	BulkDelete       = "delete"
+)
+
+// Outcomes reported for each transaction of a bulk request
+const (
+	BulkResultUpdated   = "updated"
+	BulkResultDeleted   = "deleted"
+	BulkResultUnchanged = "unchanged"
+	BulkResultFailed    = "failed"
+)
+
+// maxBulkTransactions bounds how many transactions one bulk request may touch
+const maxBulkTransactions = 1000
+
+// BulkTransactionService defines the interface for changing many transactions at once
+type BulkTransactionService interface {
+	BulkUpdate(userID uuid.UUID, req BulkTransactionRequest) (*BulkTransactionResult, error)
+}
+
+type bulkTransactionService struct {
+	transactionRepo repository.TransactionRepository
+	walletRepo      repository.WalletRepository
+	tagRepo         repository.TagRepository
+	txManager       repository.TxManager
+}
+
+// BulkTransactionRequest selects transactions by IDs or by a filter, never
+// both, and names the operation to apply along with its parameter: Category
+// for recategorize, Status for set_status, WalletID for move_wallet and Tags
+// for add_tags and remove_tags.
+type BulkTransactionRequest struct {
+	IDs       []uuid.UUID
+	Filter    *TransactionListQuery
+	Operation string
+	Category  string
+	Status    string
+	WalletID  *uuid.UUID
+	Tags      []string
+}
+
+// BulkTransactionResult reports what a bulk request did to each transaction
+type BulkTransactionResult struct {
+	Operation string                    `json:"operation"`
+	Matched   int                       `json:"matched"`
+	Succeeded int                       `json:"succeeded"`
+	Failed    int                       `json:"failed"`
+	Results   []*BulkTransactionOutcome `json:"results"`
+}
+
+// BulkTransactionOutcome is the result for one transaction. Error explains a
+// failed outcome.
+type BulkTransactionOutcome struct {
+	ID     uuid.UUID `json:"id"`
+	Status string    `json:"status"`
+	Error  string    `json:"error,omitempty"`
+}
+
+func NewBulkTransactionService(
+	transactionRepo repository.TransactionRepository,
+	walletRepo repository.WalletRepository,
+	tagRepo repository.TagRepository,
+	txManager repository.TxManager,
+) BulkTransactionService {
+	return &bulkTransactionService{
+		transactionRepo: transactionRepo,
+		walletRepo:      walletRepo,
+		tagRepo:         tagRepo,
+		txManager:       txManager,
+	}
+}
+
+// BulkUpdate applies one operation to every selected transaction. Each
+// transaction is checked on its own: those that are missing, belong to
+// someone else, are transfer legs or cannot take the change are reported as
+// failed and left alone. The rest are changed, and their wallets rebalanced,
+// in a single database transaction, so a database error changes nothing.
+// Every transaction is read under a row lock within that transaction, so a
+// concurrent edit or delete is never overwritten or reversed a second time.
+func (s *bulkTransactionService) BulkUpdate(userID uuid.UUID, req BulkTransactionRequest) (*BulkTransactionResult, error) {
+	op, err := s.newBulkOperation(userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := s.selectIDs(userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	outcomes := make([]*BulkTransactionOutcome, len(ids))
+	for i, id := range ids {
+		outcomes[i] = &BulkTransactionOutcome{ID: id}
+	}
+	result := &BulkTransactionResult{
+		Operation: req.Operation,
+		Matched:   len(outcomes),
+		Results:   outcomes,
+	}
+
+	err = s.txManager.WithinTransaction(func(tx *gorm.DB) error {
+		transactionRepo := s.transactionRepo.WithTx(tx)
+		if req.Operation == BulkAddTags {
+			tags, err := s.tagRepo.WithTx(tx).FindOrCreate(userID, op.tagNames)
+			if err != nil {
+				return err
+			}
+			op.tags = tags
+		}
+
+		// Lock the transactions in a stable order so overlapping bulk requests
+		// cannot deadlock; outcomes are still reported in the request's order
+		lockOrder := make([]*BulkTransactionOutcome, len(outcomes))
+		copy(lockOrder, outcomes)
+		sort.Slice(lockOrder, func(i, j int) bool {
+			return lockOrder[i].ID.String() < lockOrder[j].ID.String()
+		})
+
+		deltas := make(map[uuid.UUID]money.Amount)
+		for _, outcome := range lockOrder {
+			transaction, err := lockBulkTransaction(transactionRepo, outcome.ID, userID)
+			if err == nil {
+				outcome.Status, err = op.apply(transactionRepo, transaction, deltas)
+			}
+			if err != nil {
+				var itemErr *bulkItemError
+				if !errors.As(err, &itemErr) {
+					return err
+				}
+				outcome.Status = BulkResultFailed
+				outcome.Error = itemErr.Error()
+			}
+		}
+		return applyBalanceDeltas(s.walletRepo.WithTx(tx), deltas)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, outcome := range outcomes {
+		if outcome.Status == BulkResultFailed {
+			result.Failed++
+		} else {
+			result.Succeeded++
+		}
+	}
+
+	return result, nil
+}
+
+// selectIDs resolves the request to the IDs of the transactions to change,
+// in the order their outcomes are reported
+func (s *bulkTransactionService) selectIDs(userID uuid.UUID, req BulkTransactionRequest) ([]uuid.UUID, error) {
+	if (len(req.IDs) > 0) == (req.Filter != nil) {
+		return nil, errors.New("select transactions by ids or by filter")
+	}
+
+	var ids []uuid.UUID
+	if req.Filter != nil {
+		filter, _, err := req.Filter.toFilter(userID)
+		if err != nil {
+			return nil, err
+		}
+		count, err := s.transactionRepo.CountByFilter(filter)
+		if err != nil {
+			return nil, err
+		}
+		if count > maxBulkTransactions {
+			return nil, fmt.Errorf("filter matches %d transactions, more than %d; narrow the filter", count, maxBulkTransactions)
+		}
+		if count == 0 {
+			return nil, nil
+		}
+		candidates, err := s.transactionRepo.FindByFilter(filter, repository.TransactionSort{Field: repository.SortByDate, Ascending: true}, int(count), 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, transaction := range candidates {
+			ids = append(ids, transaction.ID)
+		}
+		return ids, nil
+	}
+
+	seen := make(map[uuid.UUID]bool)
+	for _, id := range req.IDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) > maxBulkTransactions {
+		return nil, fmt.Errorf("at most %d transactions can be changed at once", maxBulkTransactions)
+	}
+	return ids, nil
+}
+
+// lockBulkTransaction reads a transaction under a row lock and checks that the
+// user may change it. A transaction deleted since it was selected is reported
+// as not found. transactionRepo must be bound to the surrounding transaction.
+func lockBulkTransaction(transactionRepo repository.TransactionRepository, id, userID uuid.UUID) (*models.Transaction, error) {
+	transaction, err := transactionRepo.FindByIDForUpdate(id)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && transaction == nil):
+		return nil, &bulkItemError{"transaction not found"}
+	case err != nil:
+		return nil, err
+	case transaction.UserID != userID:
+		return nil, &bulkItemError{"unauthorized access to transaction"}
+	case transaction.TransferID != nil:
+		return nil, &bulkItemError{"transfer transactions cannot be changed; reverse the transfer instead"}
+	}
+	return transaction, nil
+}
+
+// bulkItemError is a reason a single transaction cannot take a bulk change.
+// It fails that transaction only, where any other error aborts the request.
+type bulkItemError struct {
+	reason string
+}
+
+func (e *bulkItemError) Error() string {
+	return e.reason
+}
+
+// bulkOperation is a validated bulk operation and its parameter
+type bulkOperation struct {
+	name     string
+	category string
+	status   string
+	wallet   *models.Wallet
+	tagNames []string
+	tags     []models.Tag
+}
+
+// newBulkOperation checks the operation and its parameter before anything is loaded
+func (s *bulkTransactionService) newBulkOperation(userID uuid.UUID, req BulkTransactionRequest) (*bulkOperation, error) {
+	op := &bulkOperation{name: req.Operation}
+
+	switch req.Operation {
+	case BulkRecategorize:
+		op.category = strings.TrimSpace(req.Category)
+		if op.category == "" {
+			return nil, errors.New("category is required to recategorize")
+		}
+	case BulkSetStatus:
+		if req.Status != "Completed" && req.Status != "Pending" && req.Status != "Failed" {
+			return nil, errors.New("status must be Completed, Pending or Failed")
+		}
+		op.status = req.Status
+	case BulkMoveWallet:
+		if req.WalletID == nil {
+			return nil, errors.New("wallet_id is required to move transactions")
+		}
+		wallet, err := s.walletRepo.FindByID(*req.WalletID)
+		if err != nil {
+			return nil, errors.New("wallet not found")
+		}
+		if wallet.UserID != userID {
+			return nil, errors.New("unauthorized access to wallet")
+		}
+		op.wallet = wallet
+	case BulkAddTags, BulkRemoveTags:
+		for _, name := range req.Tags {
+			if name = strings.TrimSpace(name); name != "" {
+				op.tagNames = append(op.tagNames, name)
+			}
+		}
+		if len(op.tagNames) == 0 {
+			return nil, errors.New("tags are required to add or remove tags")
+		}
+		if req.Operation == BulkRemoveTags {
+			// Tags the user does not have cannot be on any transaction
+			for _, name := range op.tagNames {
+				if tag, err := s.tagRepo.FindByUserIDAndName(userID, name); err == nil && tag != nil {
+					op.tags = append(op.tags, *tag)
+				}
+			}
+		}
+	case BulkDelete:
+	default:
+		return nil, fmt.Errorf("unknown bulk operation %q", req.Operation)
+	}
+
+	return op, nil
+}
+
+// apply changes one transaction and adds its balance change to deltas. It
+// returns a *bulkItemError when this transaction cannot take the change.
+func (op *bulkOperation) apply(transactionRepo repository.TransactionRepository, transaction *models.Transaction, deltas map[uuid.UUID]money.Amount) (string, error) {
+	previous := *transaction
+
+	switch op.name {
+	case BulkRecategorize:
+		if len(transaction.Splits) > 0 {
+			return "", &bulkItemError{"split transactions are recategorized line by line"}
+		}
+		if transaction.Category == op.category {
+			return BulkResultUnchanged, nil
+		}
+		transaction.Category = op.category
+
+	case BulkSetStatus:
+		if transaction.Status == op.status {
+			return BulkResultUnchanged, nil
+		}
+		transaction.Status = op.status
+
+	case BulkMoveWallet:
+		if transaction.WalletID != nil && *transaction.WalletID == op.wallet.ID {
+			return BulkResultUnchanged, nil
+		}
+		// Keep the amount to the precision of the new wallet's currency
+		amount := transaction.Amount.Round(op.wallet.Currency)
+		if amount <= 0 {
+			return "", &bulkItemError{"amount must be greater than zero in the wallet's currency"}
+		}
+		if amount != transaction.Amount && len(transaction.Splits) > 0 {
+			return "", &bulkItemError{"the transaction is split and its amount would change in the wallet's currency"}
+		}
+		transaction.Amount = amount
+		transaction.WalletID = &op.wallet.ID
+
+	case BulkAddTags:
+		missing := missingTags(transaction.Tags, op.tags)
+		if len(missing) == 0 {
+			return BulkResultUnchanged, nil
+		}
+		if err := transactionRepo.AddTags(transaction.ID, missing); err != nil {
+			return "", err
+		}
+		return BulkResultUpdated, nil
+
+	case BulkRemoveTags:
+		var present []models.Tag
+		for _, tag := range op.tags {
+			for _, existing := range transaction.Tags {
+				if existing.ID == tag.ID {
+					present = append(present, tag)
+					break
+				}
+			}
+		}
+		if len(present) == 0 {
+			return BulkResultUnchanged, nil
+		}
+		if err := transactionRepo.RemoveTags(transaction.ID, present); err != nil {
+			return "", err
+		}
+		return BulkResultUpdated, nil
+
+	case BulkDelete:
+		if err := transactionRepo.Delete(transaction.ID); err != nil {
+			return "", err
+		}
+		addBalanceChanges(deltas, &previous, nil)
+		return BulkResultDeleted, nil
+	}
+
+	if err := transactionRepo.Update(transaction); err != nil {
+		return "", err
+	}
+	addBalanceChanges(deltas, &previous, transaction)
+	return BulkResultUpdated, nil
+}
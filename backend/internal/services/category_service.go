@@ -0,0 +1,492 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// CategoryService defines the interface for category operations
+type CategoryService interface {
+	CreateCategory(userID uuid.UUID, req CreateCategoryRequest) (*models.Category, error)
+	GetUserCategories(userID uuid.UUID) ([]*models.Category, error)
+	GetCategoryByID(id, userID uuid.UUID) (*models.Category, error)
+	UpdateCategory(id, userID uuid.UUID, req UpdateCategoryRequest) (*models.Category, error)
+	DeleteCategory(id, userID uuid.UUID) error
+	MergeCategory(id, userID, targetID uuid.UUID) (*models.Category, error)
+	SyncCategories(userID uuid.UUID) ([]*models.Category, error)
+}
+
+type categoryService struct {
+	categoryRepo repository.CategoryRepository
+	budgetRepo   repository.BudgetRepository
+	limitRepo    repository.BudgetLimitRepository
+	txManager    repository.TxManager
+}
+
+// CreateCategoryRequest represents the data needed to create a category
+type CreateCategoryRequest struct {
+	Name     string
+	ParentID *uuid.UUID
+	Kind     string
+	Icon     string
+	Color    string
+}
+
+// UpdateCategoryRequest represents the data needed to update a category.
+// Zero values and nil pointers leave a field unchanged; TopLevel detaches a
+// category from its parent.
+type UpdateCategoryRequest struct {
+	Name     string
+	ParentID *uuid.UUID
+	TopLevel bool
+	Kind     string
+	Icon     string
+	Color    string
+}
+
+func NewCategoryService(
+	categoryRepo repository.CategoryRepository,
+	budgetRepo repository.BudgetRepository,
+	limitRepo repository.BudgetLimitRepository,
+	txManager repository.TxManager,
+) CategoryService {
+	return &categoryService{
+		categoryRepo: categoryRepo,
+		budgetRepo:   budgetRepo,
+		limitRepo:    limitRepo,
+		txManager:    txManager,
+	}
+}
+
+// CreateCategory creates a new category, optionally under a top-level parent
+func (s *categoryService) CreateCategory(userID uuid.UUID, req CreateCategoryRequest) (*models.Category, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("category name is required")
+	}
+
+	kind := req.Kind
+	if kind == "" {
+		kind = models.CategoryKindExpense
+	}
+	if err := validateCategoryKind(kind); err != nil {
+		return nil, err
+	}
+
+	if existing, _ := s.categoryRepo.FindByUserIDAndName(userID, name); existing != nil {
+		return nil, errors.New("category already exists")
+	}
+
+	category := models.Category{
+		UserID:   userID,
+		ParentID: req.ParentID,
+		Name:     name,
+		Kind:     kind,
+		Icon:     req.Icon,
+		Color:    req.Color,
+	}
+
+	if req.ParentID != nil {
+		parent, err := s.findParent(userID, *req.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		// Subcategories share their parent's kind
+		if req.Kind != "" && req.Kind != parent.Kind {
+			return nil, errors.New("a subcategory must have the same kind as its parent")
+		}
+		category.Kind = parent.Kind
+	}
+
+	if err := s.categoryRepo.Create(&category); err != nil {
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+// GetUserCategories retrieves a user's top-level categories with their subcategories
+func (s *categoryService) GetUserCategories(userID uuid.UUID) ([]*models.Category, error) {
+	categories, err := s.categoryRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildCategoryTree(categories), nil
+}
+
+// GetCategoryByID retrieves a specific category with its subcategories
+func (s *categoryService) GetCategoryByID(id, userID uuid.UUID) (*models.Category, error) {
+	category, err := s.findCategory(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := s.categoryRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	category.Children = childrenOf(categories, category.ID)
+
+	return category, nil
+}
+
+// UpdateCategory updates a category. Renaming it renames the category on the
+// user's transactions, split lines, budgets and goals as well.
+func (s *categoryService) UpdateCategory(id, userID uuid.UUID, req UpdateCategoryRequest) (*models.Category, error) {
+	category, err := s.findCategory(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := s.categoryRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	children := childrenOf(categories, category.ID)
+
+	oldName := category.Name
+	if name := strings.TrimSpace(req.Name); name != "" && name != category.Name {
+		existing, _ := s.categoryRepo.FindByUserIDAndName(userID, name)
+		if existing != nil && existing.ID != category.ID {
+			return nil, errors.New("category already exists")
+		}
+		category.Name = name
+	}
+
+	switch {
+	case req.TopLevel:
+		category.ParentID = nil
+	case req.ParentID != nil:
+		if *req.ParentID == category.ID {
+			return nil, errors.New("a category cannot be its own parent")
+		}
+		if len(children) > 0 {
+			return nil, errors.New("a category with subcategories cannot become a subcategory")
+		}
+		parent, err := s.findParent(userID, *req.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		category.ParentID = &parent.ID
+		category.Kind = parent.Kind
+	}
+
+	if req.Kind != "" {
+		if err := validateCategoryKind(req.Kind); err != nil {
+			return nil, err
+		}
+		if category.ParentID != nil && req.Kind != category.Kind {
+			return nil, errors.New("a subcategory must have the same kind as its parent")
+		}
+		category.Kind = req.Kind
+	}
+	if req.Icon != "" {
+		category.Icon = req.Icon
+	}
+	if req.Color != "" {
+		category.Color = req.Color
+	}
+
+	err = s.txManager.WithinTransaction(func(tx *gorm.DB) error {
+		categoryRepo := s.categoryRepo.WithTx(tx)
+
+		if category.Name != oldName {
+			if err := categoryRepo.RenameReferences(userID, oldName, category.Name); err != nil {
+				return err
+			}
+		}
+
+		// Subcategories follow their parent's kind
+		for _, child := range children {
+			if child.Kind == category.Kind {
+				continue
+			}
+			child.Kind = category.Kind
+			if err := categoryRepo.Update(child); err != nil {
+				return err
+			}
+		}
+
+		return categoryRepo.Update(category)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	category.Children = children
+	return category, nil
+}
+
+// DeleteCategory deletes a category that has no subcategories and is not used
+// by any record. Categories in use are merged into another category instead.
+func (s *categoryService) DeleteCategory(id, userID uuid.UUID) error {
+	category, err := s.findCategory(id, userID)
+	if err != nil {
+		return err
+	}
+
+	categories, err := s.categoryRepo.FindByUserID(userID)
+	if err != nil {
+		return err
+	}
+	if len(childrenOf(categories, category.ID)) > 0 {
+		return errors.New("category has subcategories")
+	}
+
+	references, err := s.categoryRepo.CountReferences(userID, category.Name)
+	if err != nil {
+		return err
+	}
+	if references > 0 {
+		return errors.New("category is in use; merge it into another category instead")
+	}
+
+	return s.categoryRepo.Delete(id)
+}
+
+// MergeCategory folds a category into a target category of the same kind. The
+// source's records and subcategories move to the target, and its budget limit
+// is added to the target's budget from the current period on. The source is
+// then deleted.
+func (s *categoryService) MergeCategory(id, userID, targetID uuid.UUID) (*models.Category, error) {
+	if id == targetID {
+		return nil, errors.New("cannot merge a category into itself")
+	}
+
+	source, err := s.findCategory(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.findCategory(targetID, userID)
+	if err != nil {
+		return nil, errors.New("target category not found")
+	}
+	if source.Kind != target.Kind {
+		return nil, errors.New("cannot merge categories of different kinds")
+	}
+	if target.ParentID != nil && *target.ParentID == source.ID {
+		return nil, errors.New("cannot merge a category into one of its subcategories")
+	}
+
+	categories, err := s.categoryRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	children := childrenOf(categories, source.ID)
+
+	// Subcategories stay one level deep: they join the target, or the
+	// target's parent when the target is a subcategory itself
+	newParentID := target.ID
+	if target.ParentID != nil {
+		newParentID = *target.ParentID
+	}
+
+	err = s.txManager.WithinTransaction(func(tx *gorm.DB) error {
+		categoryRepo := s.categoryRepo.WithTx(tx)
+		budgetRepo := s.budgetRepo.WithTx(tx)
+
+		for _, child := range children {
+			child.ParentID = &newParentID
+			if err := categoryRepo.Update(child); err != nil {
+				return err
+			}
+		}
+
+		// A category has at most one budget, so two budgets become one
+		sourceBudget, _ := budgetRepo.FindByUserIDAndCategory(userID, source.Name)
+		targetBudget, _ := budgetRepo.FindByUserIDAndCategory(userID, target.Name)
+		if sourceBudget != nil && targetBudget != nil {
+			previousLimit := targetBudget.LimitAmount
+			targetBudget.LimitAmount += sourceBudget.LimitAmount
+			if err := budgetRepo.Update(targetBudget); err != nil {
+				return err
+			}
+			if err := versionLimit(s.limitRepo.WithTx(tx), targetBudget, previousLimit); err != nil {
+				return err
+			}
+			if err := budgetRepo.Delete(sourceBudget.ID); err != nil {
+				return err
+			}
+		}
+
+		if err := categoryRepo.RenameReferences(userID, source.Name, target.Name); err != nil {
+			return err
+		}
+
+		return categoryRepo.Delete(source.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetCategoryByID(target.ID, userID)
+}
+
+// SyncCategories creates a category for every category name the user's
+// transactions and budgets use that has none yet, and returns the new ones
+func (s *categoryService) SyncCategories(userID uuid.UUID) ([]*models.Category, error) {
+	used, err := s.categoryRepo.FindUsed(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.categoryRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(existing))
+	for _, category := range existing {
+		known[strings.ToLower(category.Name)] = true
+	}
+
+	created := make([]*models.Category, 0)
+	err = s.txManager.WithinTransaction(func(tx *gorm.DB) error {
+		categoryRepo := s.categoryRepo.WithTx(tx)
+
+		for _, usage := range used {
+			name := strings.TrimSpace(usage.Name)
+			if name == "" || known[strings.ToLower(name)] {
+				continue
+			}
+			known[strings.ToLower(name)] = true
+
+			category := &models.Category{
+				UserID: userID,
+				Name:   name,
+				Kind:   usage.Kind,
+			}
+			if err := categoryRepo.Create(category); err != nil {
+				return err
+			}
+			created = append(created, category)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// findCategory retrieves a category and verifies it belongs to the user
+func (s *categoryService) findCategory(id, userID uuid.UUID) (*models.Category, error) {
+	category, err := s.categoryRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("category not found")
+	}
+
+	// Verify category belongs to user
+	if category.UserID != userID {
+		return nil, errors.New("unauthorized access to category")
+	}
+
+	return category, nil
+}
+
+// findParent retrieves a category that can take subcategories
+func (s *categoryService) findParent(userID, parentID uuid.UUID) (*models.Category, error) {
+	parent, err := s.categoryRepo.FindByID(parentID)
+	if err != nil || parent.UserID != userID {
+		return nil, errors.New("parent category not found")
+	}
+	if parent.ParentID != nil {
+		return nil, errors.New("subcategories cannot have subcategories")
+	}
+	return parent, nil
+}
+
+func validateCategoryKind(kind string) error {
+	if kind != models.CategoryKindExpense && kind != models.CategoryKindIncome {
+		return errors.New("category kind must be expense or income")
+	}
+	return nil
+}
+
+// childrenOf returns the subcategories of a category
+func childrenOf(categories []*models.Category, parentID uuid.UUID) []*models.Category {
+	var children []*models.Category
+	for _, category := range categories {
+		if category.ParentID != nil && *category.ParentID == parentID {
+			children = append(children, category)
+		}
+	}
+	return children
+}
+
+// buildCategoryTree nests subcategories under their parents and returns the
+// top-level categories
+func buildCategoryTree(categories []*models.Category) []*models.Category {
+	roots := make([]*models.Category, 0)
+	for _, category := range categories {
+		if category.ParentID == nil {
+			category.Children = childrenOf(categories, category.ID)
+			roots = append(roots, category)
+		}
+	}
+	return roots
+}
+
+// categoryIndex resolves category names on records, ignoring case, to the
+// user's categories
+type categoryIndex struct {
+	byName map[string]*models.Category
+	byID   map[uuid.UUID]*models.Category
+}
+
+func newCategoryIndex(categories []*models.Category) *categoryIndex {
+	index := &categoryIndex{
+		byName: make(map[string]*models.Category, len(categories)),
+		byID:   make(map[uuid.UUID]*models.Category, len(categories)),
+	}
+	for _, category := range categories {
+		index.byName[strings.ToLower(category.Name)] = category
+		index.byID[category.ID] = category
+	}
+	return index
+}
+
+// name returns the category's own spelling of a name, or the name itself when
+// the user has no such category
+func (idx *categoryIndex) name(name string) string {
+	if category, ok := idx.byName[strings.ToLower(name)]; ok {
+		return category.Name
+	}
+	return name
+}
+
+// rollup returns the name spending in a category is reported under when
+// subcategories roll up: the parent's name for subcategories
+func (idx *categoryIndex) rollup(name string) string {
+	category, ok := idx.byName[strings.ToLower(name)]
+	if !ok {
+		return name
+	}
+	if category.ParentID != nil {
+		if parent, ok := idx.byID[*category.ParentID]; ok {
+			return parent.Name
+		}
+	}
+	return category.Name
+}
+
+// family returns a category name followed by the names of its subcategories
+func (idx *categoryIndex) family(name string) []string {
+	names := []string{name}
+	category, ok := idx.byName[strings.ToLower(name)]
+	if !ok {
+		return names
+	}
+	for _, child := range idx.byID {
+		if child.ParentID != nil && *child.ParentID == category.ID {
+			names = append(names, child.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,414 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"github.com/nyunja/fity-budget-backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Duplicate detection settings
+const (
+	// defaultDuplicateWindowDays is how many days apart two transactions may
+	// be and still count as the same spend
+	defaultDuplicateWindowDays = 3
+	maxDuplicateWindowDays     = 14
+	// defaultDuplicateLookback is the range scanned when no start date is given
+	defaultDuplicateLookback = 90 * 24 * time.Hour
+	// maxDuplicateScan bounds how many transactions one scan may cover
+	maxDuplicateScan = 10000
+	// minNameSimilarity is the bigram similarity above which two names are
+	// taken to describe the same payee
+	minNameSimilarity = 0.6
+)
+
+// Confidence levels of a duplicate group
+const (
+	DuplicateConfidenceHigh   = "high"
+	DuplicateConfidenceMedium = "medium"
+)
+
+// DuplicateService defines the interface for finding and merging duplicate transactions
+type DuplicateService interface {
+	FindDuplicates(userID uuid.UUID, req FindDuplicatesRequest) ([]*DuplicateGroup, error)
+	MergeDuplicates(id, userID uuid.UUID, req MergeDuplicatesRequest) (*models.Transaction, error)
+}
+
+type duplicateService struct {
+	transactionRepo repository.TransactionRepository
+	walletRepo      repository.WalletRepository
+	receiptRepo     repository.ReceiptRepository
+	txManager       repository.TxManager
+}
+
+// FindDuplicatesRequest selects the range scanned for duplicates. EndDate is
+// exclusive; zero dates scan the last 90 days. WindowDays defaults to 3.
+type FindDuplicatesRequest struct {
+	StartDate  time.Time
+	EndDate    time.Time
+	WindowDays int
+}
+
+// DuplicateGroup is a set of transactions that likely record the same money
+// movement: same wallet, type and amount, dated within the window of each
+// other and with similar names. Transactions are ordered oldest first.
+type DuplicateGroup struct {
+	WalletID     *uuid.UUID            `json:"wallet_id,omitempty"`
+	Type         string                `json:"type"`
+	Amount       money.Amount          `json:"amount"`
+	Confidence   string                `json:"confidence"` // high when a pair shares a statement reference or a date and name
+	Transactions []*models.Transaction `json:"transactions"`
+}
+
+// MergeDuplicatesRequest lists the transactions to merge into the kept one
+type MergeDuplicatesRequest struct {
+	DuplicateIDs []uuid.UUID
+}
+
+func NewDuplicateService(
+	transactionRepo repository.TransactionRepository,
+	walletRepo repository.WalletRepository,
+	receiptRepo repository.ReceiptRepository,
+	txManager repository.TxManager,
+) DuplicateService {
+	return &duplicateService{
+		transactionRepo: transactionRepo,
+		walletRepo:      walletRepo,
+		receiptRepo:     receiptRepo,
+		txManager:       txManager,
+	}
+}
+
+// duplicateBucket holds the fields duplicates must share exactly
+type duplicateBucket struct {
+	walletID uuid.UUID
+	txnType  string
+	amount   money.Amount
+}
+
+// FindDuplicates groups a user's income and expenses that likely duplicate
+// each other. Groups are ordered by their latest transaction, newest first.
+func (s *duplicateService) FindDuplicates(userID uuid.UUID, req FindDuplicatesRequest) ([]*DuplicateGroup, error) {
+	window := req.WindowDays
+	if window == 0 {
+		window = defaultDuplicateWindowDays
+	}
+	if window < 1 || window > maxDuplicateWindowDays {
+		return nil, fmt.Errorf("window_days must be between 1 and %d", maxDuplicateWindowDays)
+	}
+
+	endDate := req.EndDate
+	if endDate.IsZero() {
+		endDate = time.Now()
+	}
+	startDate := req.StartDate
+	if startDate.IsZero() {
+		startDate = endDate.Add(-defaultDuplicateLookback)
+	}
+	if !startDate.Before(endDate) {
+		return nil, errors.New("start date must be before end date")
+	}
+
+	// Transfer legs are owned by their transfer and never duplicate anything
+	filter := repository.TransactionFilter{
+		UserID:    userID,
+		StartDate: startDate,
+		EndDate:   endDate,
+		Types:     []string{models.TransactionTypeIncome, models.TransactionTypeExpense},
+	}
+	count, err := s.transactionRepo.CountByFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	if count > maxDuplicateScan {
+		return nil, fmt.Errorf("range covers %d transactions, more than %d; narrow the date range", count, maxDuplicateScan)
+	}
+	groups := []*DuplicateGroup{}
+	if count == 0 {
+		return groups, nil
+	}
+
+	transactions, err := s.transactionRepo.FindByFilter(filter, repository.TransactionSort{Field: repository.SortByDate, Ascending: true}, int(count), 0)
+	if err != nil {
+		return nil, err
+	}
+
+	buckets := make(map[duplicateBucket][]int)
+	for i, transaction := range transactions {
+		key := duplicateBucket{txnType: transaction.Type, amount: transaction.Amount}
+		if transaction.WalletID != nil {
+			key.walletID = *transaction.WalletID
+		}
+		buckets[key] = append(buckets[key], i)
+	}
+
+	// Link every likely pair and group transactions connected through them
+	parent := make([]int, len(transactions))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	var confident []int
+
+	maxGap := time.Duration(window) * 24 * time.Hour
+	for _, indexes := range buckets {
+		// Indexes are in date order, so later transactions only get further away
+		for i, a := range indexes {
+			for _, b := range indexes[i+1:] {
+				if transactions[b].TransactionDate.Sub(transactions[a].TransactionDate) > maxGap {
+					break
+				}
+				sure, ok := likelyDuplicates(transactions[a], transactions[b])
+				if !ok {
+					continue
+				}
+				if rootA, rootB := find(a), find(b); rootA != rootB {
+					parent[rootB] = rootA
+				}
+				if sure {
+					confident = append(confident, a)
+				}
+			}
+		}
+	}
+
+	sizes := make(map[int]int)
+	for i := range transactions {
+		sizes[find(i)]++
+	}
+	high := make(map[int]bool)
+	for _, i := range confident {
+		high[find(i)] = true
+	}
+
+	byRoot := make(map[int]*DuplicateGroup)
+	for i, transaction := range transactions {
+		root := find(i)
+		if sizes[root] < 2 {
+			continue
+		}
+		group, ok := byRoot[root]
+		if !ok {
+			group = &DuplicateGroup{
+				WalletID:   transaction.WalletID,
+				Type:       transaction.Type,
+				Amount:     transaction.Amount,
+				Confidence: DuplicateConfidenceMedium,
+			}
+			if high[root] {
+				group.Confidence = DuplicateConfidenceHigh
+			}
+			byRoot[root] = group
+			groups = append(groups, group)
+		}
+		group.Transactions = append(group.Transactions, transaction)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		a, b := groups[i].Transactions, groups[j].Transactions
+		return a[len(a)-1].TransactionDate.After(b[len(b)-1].TransactionDate)
+	})
+	return groups, nil
+}
+
+// likelyDuplicates reports whether two transactions with the same wallet,
+// type and amount, dated close together, record the same money movement, and
+// whether that is near certain
+func likelyDuplicates(a, b *models.Transaction) (confident, ok bool) {
+	// A schedule posts each date once, so its occurrences are separate payments
+	if a.RecurringID != nil && b.RecurringID != nil && *a.RecurringID == *b.RecurringID {
+		return false, false
+	}
+	// Statement references identify a payment, so they decide when both have one
+	if a.ExternalID != "" && b.ExternalID != "" {
+		return true, a.ExternalID == b.ExternalID
+	}
+
+	nameA, nameB := normalizeName(a.Name), normalizeName(b.Name)
+	if nameA == nameB {
+		sameDay := a.TransactionDate.Format("2006-01-02") == b.TransactionDate.Format("2006-01-02")
+		return sameDay, true
+	}
+	if len(nameA) >= 3 && len(nameB) >= 3 && (strings.Contains(nameA, nameB) || strings.Contains(nameB, nameA)) {
+		return false, true
+	}
+	return false, nameSimilarity(nameA, nameB) >= minNameSimilarity
+}
+
+// normalizeName lowercases a transaction name and reduces it to its letters
+// and digits separated by single spaces
+func normalizeName(name string) string {
+	fields := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(fields, " ")
+}
+
+// nameSimilarity is the Sørensen–Dice coefficient of the character bigrams of
+// two names, from 0 for nothing in common to 1 for the same letters
+func nameSimilarity(a, b string) float64 {
+	bigramsA, bigramsB := bigrams(a), bigrams(b)
+	if len(bigramsA) == 0 || len(bigramsB) == 0 {
+		return 0
+	}
+
+	counts := make(map[string]int, len(bigramsA))
+	for _, bigram := range bigramsA {
+		counts[bigram]++
+	}
+	shared := 0
+	for _, bigram := range bigramsB {
+		if counts[bigram] > 0 {
+			counts[bigram]--
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(bigramsA)+len(bigramsB))
+}
+
+// bigrams returns the pairs of adjacent characters of a name, ignoring spaces
+func bigrams(name string) []string {
+	runes := []rune(strings.ReplaceAll(name, " ", ""))
+	if len(runes) < 2 {
+		return nil
+	}
+	pairs := make([]string, 0, len(runes)-1)
+	for i := 0; i < len(runes)-1; i++ {
+		pairs = append(pairs, string(runes[i:i+2]))
+	}
+	return pairs
+}
+
+// MergeDuplicates keeps one transaction and deletes its duplicates. The kept
+// transaction gains the duplicates' notes, tags and receipts, and the
+// duplicates' effect on wallet balances is reversed, all in one database
+// transaction. Every row is read under a lock, so a duplicate deleted by a
+// concurrent merge or delete fails the merge instead of being reversed twice.
+func (s *duplicateService) MergeDuplicates(id, userID uuid.UUID, req MergeDuplicatesRequest) (*models.Transaction, error) {
+	if len(req.DuplicateIDs) == 0 {
+		return nil, errors.New("at least one duplicate is required")
+	}
+
+	var duplicateIDs []uuid.UUID
+	seen := map[uuid.UUID]bool{id: true}
+	for _, duplicateID := range req.DuplicateIDs {
+		if duplicateID == id {
+			return nil, errors.New("a transaction cannot be merged into itself")
+		}
+		if seen[duplicateID] {
+			continue
+		}
+		seen[duplicateID] = true
+		duplicateIDs = append(duplicateIDs, duplicateID)
+	}
+
+	err := s.txManager.WithinTransaction(func(tx *gorm.DB) error {
+		transactionRepo := s.transactionRepo.WithTx(tx)
+		walletRepo := s.walletRepo.WithTx(tx)
+		receiptRepo := s.receiptRepo.WithTx(tx)
+
+		kept, err := getMergeable(transactionRepo, id, userID)
+		if err != nil {
+			return err
+		}
+		var duplicates []*models.Transaction
+		for _, duplicateID := range duplicateIDs {
+			duplicate, err := getMergeable(transactionRepo, duplicateID, userID)
+			if err != nil {
+				return err
+			}
+			duplicates = append(duplicates, duplicate)
+		}
+
+		var tags []models.Tag
+		notes := []string{kept.Notes}
+		receiptURL := kept.ReceiptURL
+		for _, duplicate := range duplicates {
+			tags = append(tags, duplicate.Tags...)
+			notes = append(notes, duplicate.Notes)
+			if receiptURL == "" {
+				receiptURL = duplicate.ReceiptURL
+			}
+		}
+
+		// Only the merged columns are written so the rest of the kept row is
+		// left as stored
+		columns := map[string]interface{}{
+			"notes":       combineNotes(notes),
+			"receipt_url": receiptURL,
+		}
+		if err := transactionRepo.UpdateColumns(kept.ID, columns); err != nil {
+			return err
+		}
+		if err := transactionRepo.AddTags(kept.ID, missingTags(kept.Tags, tags)); err != nil {
+			return err
+		}
+		for _, duplicate := range duplicates {
+			if err := receiptRepo.MoveToTransaction(duplicate.ID, kept.ID); err != nil {
+				return err
+			}
+			if err := transactionRepo.Delete(duplicate.ID); err != nil {
+				return err
+			}
+			if err := applyBalanceChanges(walletRepo, duplicate, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.transactionRepo.FindByID(id)
+}
+
+// getMergeable retrieves a live transaction the user may merge and locks its
+// row. transactionRepo must be bound to the surrounding database transaction.
+func getMergeable(transactionRepo repository.TransactionRepository, id, userID uuid.UUID) (*models.Transaction, error) {
+	transaction, err := transactionRepo.FindByIDForUpdate(id)
+	if err != nil {
+		return nil, errors.New("transaction not found")
+	}
+
+	// Verify transaction belongs to user
+	if transaction.UserID != userID {
+		return nil, errors.New("unauthorized access to transaction")
+	}
+
+	// Transfer legs are owned by their transfer and only change through a reversal
+	if transaction.TransferID != nil {
+		return nil, errors.New("transfer transactions cannot be merged")
+	}
+
+	return transaction, nil
+}
+
+// combineNotes joins the distinct non-empty notes in order, one per line
+func combineNotes(notes []string) string {
+	var combined []string
+	seen := make(map[string]bool)
+	for _, note := range notes {
+		note = strings.TrimSpace(note)
+		if note == "" || seen[note] {
+			continue
+		}
+		seen[note] = true
+		combined = append(combined, note)
+	}
+	return strings.Join(combined, "\n")
+}
@@ -0,0 +1,489 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"github.com/nyunja/fity-budget-backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// EnvelopeService defines the interface for zero-based envelope budgeting.
+// Income is given out to envelope budgets until nothing is left to assign,
+// and envelopes keep their balance from month to month.
+type EnvelopeService interface {
+	GetEnvelopes(userID uuid.UUID, month time.Time) (*EnvelopeMonth, error)
+	Assign(userID uuid.UUID, req AssignEnvelopeRequest) (*EnvelopeMonth, error)
+	Move(userID uuid.UUID, req MoveEnvelopeRequest) (*EnvelopeMonth, error)
+	Cover(userID uuid.UUID, req CoverEnvelopeRequest) (*EnvelopeMonth, error)
+}
+
+type envelopeService struct {
+	budgetRepo      repository.BudgetRepository
+	allocationRepo  repository.EnvelopeAllocationRepository
+	walletRepo      repository.WalletRepository
+	transactionRepo repository.TransactionRepository
+	categoryRepo    repository.CategoryRepository
+	userRepo        repository.UserRepository
+	rateRepo        repository.ExchangeRateRepository
+	txManager       repository.TxManager
+}
+
+// AssignEnvelopeRequest gives money that is ready to assign to an envelope in
+// a month. A negative amount takes money back out of the envelope.
+type AssignEnvelopeRequest struct {
+	BudgetID uuid.UUID
+	Month    time.Time
+	Amount   money.Amount
+	Note     string
+}
+
+// MoveEnvelopeRequest moves money from one envelope to another in a month
+type MoveEnvelopeRequest struct {
+	FromBudgetID uuid.UUID
+	ToBudgetID   uuid.UUID
+	Month        time.Time
+	Amount       money.Amount
+	Note         string
+}
+
+// CoverEnvelopeRequest covers an overspent envelope from another one. Without
+// an amount the whole overspending is covered.
+type CoverEnvelopeRequest struct {
+	BudgetID     uuid.UUID
+	FromBudgetID uuid.UUID
+	Month        time.Time
+	Amount       money.Amount
+	Note         string
+}
+
+// EnvelopeMonth is the state of a user's envelopes in a month. Income and
+// Assigned are this month's; ReadyToAssign is all income received up to the
+// end of the month less everything assigned so far, so assigning ahead to a
+// later month also leaves less to assign now. Amounts are in the user's base
+// currency.
+type EnvelopeMonth struct {
+	Month         time.Time    `json:"month"`
+	Income        money.Amount `json:"income"`
+	Assigned      money.Amount `json:"assigned"`
+	ReadyToAssign money.Amount `json:"ready_to_assign"`
+	Overspent     money.Amount `json:"overspent"`
+	Envelopes     []*Envelope  `json:"envelopes"`
+	Currency      string       `json:"currency"`
+	// Currencies of income and spending left out for want of an exchange rate
+	UnconvertedCurrencies []string `json:"unconverted_currencies,omitempty"`
+}
+
+// Envelope is one envelope budget in a month. CarriedIn is what was left in
+// it at the end of the previous month, or what it was overspent by. Moved
+// nets the money moved in and out, including to cover overspending.
+type Envelope struct {
+	BudgetID    uuid.UUID    `json:"budget_id"`
+	Category    string       `json:"category"`
+	Target      money.Amount `json:"target"`
+	CarriedIn   money.Amount `json:"carried_in"`
+	Assigned    money.Amount `json:"assigned"`
+	Moved       money.Amount `json:"moved"`
+	Spent       money.Amount `json:"spent"`
+	Available   money.Amount `json:"available"`
+	IsOverspent bool         `json:"is_overspent"`
+}
+
+func NewEnvelopeService(
+	budgetRepo repository.BudgetRepository,
+	allocationRepo repository.EnvelopeAllocationRepository,
+	walletRepo repository.WalletRepository,
+	transactionRepo repository.TransactionRepository,
+	categoryRepo repository.CategoryRepository,
+	userRepo repository.UserRepository,
+	rateRepo repository.ExchangeRateRepository,
+	txManager repository.TxManager,
+) EnvelopeService {
+	return &envelopeService{
+		budgetRepo:      budgetRepo,
+		allocationRepo:  allocationRepo,
+		walletRepo:      walletRepo,
+		transactionRepo: transactionRepo,
+		categoryRepo:    categoryRepo,
+		userRepo:        userRepo,
+		rateRepo:        rateRepo,
+		txManager:       txManager,
+	}
+}
+
+// GetEnvelopes returns the envelopes and the money ready to assign in the
+// month containing the given date
+func (s *envelopeService) GetEnvelopes(userID uuid.UUID, month time.Time) (*EnvelopeMonth, error) {
+	return s.monthOf(userID, month)
+}
+
+// Assign gives an envelope money that is ready to assign, or takes back money
+// the envelope has not spent
+func (s *envelopeService) Assign(userID uuid.UUID, req AssignEnvelopeRequest) (*EnvelopeMonth, error) {
+	if req.Amount == 0 {
+		return nil, errors.New("amount must not be zero")
+	}
+	if _, err := s.findEnvelope(req.BudgetID, userID); err != nil {
+		return nil, err
+	}
+
+	var month time.Time
+	err := s.locked(userID, func(s *envelopeService) error {
+		current, err := s.monthOf(userID, req.Month)
+		if err != nil {
+			return err
+		}
+		month = current.Month
+		if req.Amount > 0 && req.Amount > current.ReadyToAssign {
+			return fmt.Errorf("only %s is ready to assign", current.ReadyToAssign)
+		}
+		if req.Amount < 0 {
+			if available := current.envelope(req.BudgetID).Available; -req.Amount > available {
+				if available < 0 {
+					available = 0
+				}
+				return fmt.Errorf("only %s is available in the envelope to take back", available)
+			}
+		}
+
+		return s.allocationRepo.Create([]*models.EnvelopeAllocation{{
+			UserID:   userID,
+			BudgetID: req.BudgetID,
+			Month:    current.Month,
+			Amount:   req.Amount,
+			Kind:     models.EnvelopeAllocationAssign,
+			Note:     req.Note,
+		}})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.monthOf(userID, month)
+}
+
+// Move moves money between two envelopes. The source can only give what it has available.
+func (s *envelopeService) Move(userID uuid.UUID, req MoveEnvelopeRequest) (*EnvelopeMonth, error) {
+	if req.Amount <= 0 {
+		return nil, errors.New("amount must be greater than zero")
+	}
+	if err := s.checkTransfer(userID, req.FromBudgetID, req.ToBudgetID); err != nil {
+		return nil, err
+	}
+
+	var month time.Time
+	err := s.locked(userID, func(s *envelopeService) error {
+		current, err := s.monthOf(userID, req.Month)
+		if err != nil {
+			return err
+		}
+		month = current.Month
+		return s.transfer(userID, current, req.FromBudgetID, req.ToBudgetID, req.Amount, models.EnvelopeAllocationMove, req.Note)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.monthOf(userID, month)
+}
+
+// Cover moves money from another envelope into an overspent one, by default
+// enough to bring it back to zero
+func (s *envelopeService) Cover(userID uuid.UUID, req CoverEnvelopeRequest) (*EnvelopeMonth, error) {
+	if req.Amount < 0 {
+		return nil, errors.New("amount must be greater than zero")
+	}
+	if err := s.checkTransfer(userID, req.FromBudgetID, req.BudgetID); err != nil {
+		return nil, err
+	}
+
+	var month time.Time
+	err := s.locked(userID, func(s *envelopeService) error {
+		current, err := s.monthOf(userID, req.Month)
+		if err != nil {
+			return err
+		}
+		month = current.Month
+		overspent := current.envelope(req.BudgetID)
+		if !overspent.IsOverspent {
+			return errors.New("envelope is not overspent")
+		}
+
+		amount := req.Amount
+		if amount == 0 {
+			amount = -overspent.Available
+		}
+		return s.transfer(userID, current, req.FromBudgetID, req.BudgetID, amount, models.EnvelopeAllocationCover, req.Note)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.monthOf(userID, month)
+}
+
+// locked runs fn in a transaction holding a lock on the user's row, so
+// concurrent assigns and moves of the same user check what is ready to
+// assign and available one after the other. fn gets a service whose
+// repositories take part in the transaction.
+func (s *envelopeService) locked(userID uuid.UUID, fn func(s *envelopeService) error) error {
+	return s.txManager.WithinTransaction(func(tx *gorm.DB) error {
+		if _, err := s.userRepo.WithTx(tx).FindByIDForUpdate(userID); err != nil {
+			return err
+		}
+		return fn(&envelopeService{
+			budgetRepo:      s.budgetRepo.WithTx(tx),
+			allocationRepo:  s.allocationRepo.WithTx(tx),
+			walletRepo:      s.walletRepo.WithTx(tx),
+			transactionRepo: s.transactionRepo.WithTx(tx),
+			categoryRepo:    s.categoryRepo.WithTx(tx),
+			userRepo:        s.userRepo.WithTx(tx),
+			rateRepo:        s.rateRepo,
+			txManager:       s.txManager.WithTx(tx),
+		})
+	})
+}
+
+// checkTransfer checks both envelopes of a transfer belong to the user
+func (s *envelopeService) checkTransfer(userID, fromID, toID uuid.UUID) error {
+	if fromID == toID {
+		return errors.New("cannot move money to the same envelope")
+	}
+	if _, err := s.findEnvelope(fromID, userID); err != nil {
+		return err
+	}
+	_, err := s.findEnvelope(toID, userID)
+	return err
+}
+
+// transfer records money leaving one envelope and entering another as a
+// pair of allocations sharing a move ID
+func (s *envelopeService) transfer(userID uuid.UUID, current *EnvelopeMonth, fromID, toID uuid.UUID, amount money.Amount, kind, note string) error {
+	if available := current.envelope(fromID).Available; amount > available {
+		if available < 0 {
+			available = 0
+		}
+		return fmt.Errorf("only %s is available in the envelope to move", available)
+	}
+
+	moveID := uuid.New()
+	return s.allocationRepo.Create([]*models.EnvelopeAllocation{
+		{UserID: userID, BudgetID: fromID, Month: current.Month, Amount: -amount, Kind: kind, MoveID: &moveID, Note: note},
+		{UserID: userID, BudgetID: toID, Month: current.Month, Amount: amount, Kind: kind, MoveID: &moveID, Note: note},
+	})
+}
+
+// findEnvelope loads a budget of the user that is run as an envelope
+func (s *envelopeService) findEnvelope(id, userID uuid.UUID) (*models.Budget, error) {
+	budget, err := s.budgetRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("budget not found")
+	}
+	if budget.UserID != userID {
+		return nil, errors.New("unauthorized access to budget")
+	}
+	if !budget.IsEnvelope {
+		return nil, fmt.Errorf("budget %s is not an envelope", budget.Category)
+	}
+	return budget, nil
+}
+
+// monthOf works out the envelopes in the calendar month containing at.
+// Income and spending count from the first month any envelope was created,
+// and only when completed in a wallet that is on budget; each envelope only
+// counts its own category's spending from the month it was created. Income
+// and spending are converted from the currency of their wallet into the
+// user's base currency.
+func (s *envelopeService) monthOf(userID uuid.UUID, at time.Time) (*EnvelopeMonth, error) {
+	if at.IsZero() {
+		at = time.Now()
+	}
+	month, end := calendarMonth(at)
+	result := &EnvelopeMonth{Month: month, Envelopes: []*Envelope{}}
+
+	budgets, err := s.budgetRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	var envelopes []*models.Budget
+	first := month
+	for _, budget := range budgets {
+		if !budget.IsEnvelope {
+			continue
+		}
+		envelopes = append(envelopes, budget)
+		if created, _ := calendarMonth(budget.CreatedAt); !budget.CreatedAt.IsZero() && created.Before(first) {
+			first = created
+		}
+	}
+
+	wallets, err := s.walletRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	report, err := loadReportCurrency(s.userRepo, s.walletRepo, s.rateRepo, userID, wallets, at)
+	if err != nil {
+		return nil, err
+	}
+	result.Currency = report.base
+
+	offBudget := make(map[uuid.UUID]bool)
+	for _, wallet := range wallets {
+		if wallet.OffBudget {
+			offBudget[wallet.ID] = true
+		}
+	}
+	onBudget := func(aggregate *repository.TransactionAggregate) bool {
+		return aggregate.WalletID == nil || !offBudget[*aggregate.WalletID]
+	}
+
+	incomes, err := s.transactionRepo.Aggregate(repository.TransactionFilter{
+		UserID:    userID,
+		StartDate: first,
+		EndDate:   end,
+		Statuses:  []string{"Completed"},
+		Types:     []string{models.TransactionTypeIncome},
+	}, repository.GroupByWallet, repository.GroupByMonth)
+	if err != nil {
+		return nil, err
+	}
+	var income money.Amount
+	for _, aggregate := range incomes {
+		if !onBudget(aggregate) {
+			continue
+		}
+		amount, err := report.convertAggregate(aggregate)
+		if err != nil {
+			return nil, err
+		}
+		income += amount
+		if aggregate.Period == month.Format("2006-01") {
+			result.Income += amount
+		}
+	}
+
+	// Assigned money stays assigned whichever month it went to. Moves between
+	// live envelopes cancel out, so summing every kind leaves what the live
+	// envelopes hold, and money held by a deleted envelope is ready again.
+	totals, err := s.allocationRepo.Totals(userID)
+	if err != nil {
+		return nil, err
+	}
+	var assigned money.Amount
+	carried := make(map[uuid.UUID]money.Amount)
+	thisMonth := make(map[uuid.UUID]map[string]money.Amount)
+	for _, total := range totals {
+		assigned += total.Total
+		allocated, _ := calendarMonth(total.Month)
+		switch {
+		case allocated.Before(month):
+			carried[total.BudgetID] += total.Total
+		case allocated.Equal(month):
+			if thisMonth[total.BudgetID] == nil {
+				thisMonth[total.BudgetID] = make(map[string]money.Amount)
+			}
+			thisMonth[total.BudgetID][total.Kind] += total.Total
+		}
+	}
+	result.ReadyToAssign = income - assigned
+
+	if len(envelopes) == 0 {
+		result.UnconvertedCurrencies = report.unconverted()
+		return result, nil
+	}
+
+	userCategories, err := s.categoryRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	index := newCategoryIndex(userCategories)
+	var categories []string
+	for _, budget := range envelopes {
+		categories = append(categories, index.family(budget.Category)...)
+	}
+
+	expenses, err := s.transactionRepo.Aggregate(repository.TransactionFilter{
+		UserID:     userID,
+		StartDate:  first,
+		EndDate:    end,
+		Categories: categories,
+		Statuses:   []string{"Completed"},
+		Types:      []string{models.TransactionTypeExpense},
+	}, repository.GroupByCategory, repository.GroupByWallet, repository.GroupByMonth)
+	if err != nil {
+		return nil, err
+	}
+	spent := make(map[string]map[string]money.Amount)
+	for _, aggregate := range expenses {
+		if !onBudget(aggregate) {
+			continue
+		}
+		amount, err := report.convertAggregate(aggregate)
+		if err != nil {
+			return nil, err
+		}
+		category := strings.ToLower(aggregate.Category)
+		if spent[category] == nil {
+			spent[category] = make(map[string]money.Amount)
+		}
+		spent[category][aggregate.Period] += amount
+	}
+
+	for _, budget := range envelopes {
+		created, _ := calendarMonth(budget.CreatedAt)
+		since := created.Format("2006-01")
+		current := month.Format("2006-01")
+
+		envelope := &Envelope{
+			BudgetID:  budget.ID,
+			Category:  budget.Category,
+			Target:    budget.LimitAmount,
+			CarriedIn: carried[budget.ID],
+			Assigned:  thisMonth[budget.ID][models.EnvelopeAllocationAssign],
+			Moved:     thisMonth[budget.ID][models.EnvelopeAllocationMove] + thisMonth[budget.ID][models.EnvelopeAllocationCover],
+		}
+		for _, category := range index.family(budget.Category) {
+			for period, amount := range spent[strings.ToLower(category)] {
+				switch {
+				case period == current:
+					envelope.Spent += amount
+				case period >= since && period < current:
+					envelope.CarriedIn -= amount
+				}
+			}
+		}
+		envelope.Available = envelope.CarriedIn + envelope.Assigned + envelope.Moved - envelope.Spent
+		envelope.IsOverspent = envelope.Available < 0
+
+		result.Assigned += envelope.Assigned
+		if envelope.IsOverspent {
+			result.Overspent -= envelope.Available
+		}
+		result.Envelopes = append(result.Envelopes, envelope)
+	}
+	result.UnconvertedCurrencies = report.unconverted()
+
+	return result, nil
+}
+
+// envelope returns the envelope of a budget in the month, or an empty one
+func (m *EnvelopeMonth) envelope(budgetID uuid.UUID) *Envelope {
+	for _, envelope := range m.Envelopes {
+		if envelope.BudgetID == budgetID {
+			return envelope
+		}
+	}
+	return &Envelope{BudgetID: budgetID}
+}
+
+// calendarMonth returns the UTC calendar month containing t as a half-open range
+func calendarMonth(t time.Time) (start, end time.Time) {
+	t = t.UTC()
+	start = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 1, 0)
+}
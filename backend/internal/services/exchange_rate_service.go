@@ -0,0 +1,505 @@
+package services
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"github.com/nyunja/fity-budget-backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Supported exchange rate file formats
+const (
+	RateFormatCSV = "csv"
+	RateFormatECB = "ecb"
+)
+
+// ErrNoExchangeRate is returned when no stored rate links two currencies
+var ErrNoExchangeRate = errors.New("no exchange rate available")
+
+// pivotCurrencies are tried, in order, to build a cross rate when no rate is
+// stored for a currency pair. ECB reference rates are all quoted against EUR.
+var pivotCurrencies = []string{"EUR", "USD"}
+
+// ExchangeRateService defines the interface for exchange rate operations
+type ExchangeRateService interface {
+	ImportRates(r io.Reader, format string) (*RateImportResult, error)
+	ListRates(baseCurrency, quoteCurrency string, limit, offset int) ([]*models.ExchangeRate, int64, error)
+	Convert(amount money.Amount, from, to string, on time.Time) (money.Amount, *AppliedRate, error)
+}
+
+type exchangeRateService struct {
+	rateRepo repository.ExchangeRateRepository
+}
+
+// RateImportResult summarises an exchange rate import
+type RateImportResult struct {
+	Format     string    `json:"format"`
+	Imported   int       `json:"imported"`
+	Currencies []string  `json:"currencies"`
+	FirstDate  time.Time `json:"first_date"`
+	LastDate   time.Time `json:"last_date"`
+}
+
+// AppliedRate describes the exchange rate used to convert an amount and the
+// day it was quoted for
+type AppliedRate struct {
+	From     string     `json:"from"`
+	To       string     `json:"to"`
+	Rate     money.Rate `json:"rate"`
+	RateDate time.Time  `json:"rate_date"`
+}
+
+func NewExchangeRateService(rateRepo repository.ExchangeRateRepository) ExchangeRateService {
+	return &exchangeRateService{rateRepo: rateRepo}
+}
+
+// ImportRates parses a rate file in the given format and stores its rates,
+// replacing rates already stored for the same currency pair and day
+func (s *exchangeRateService) ImportRates(r io.Reader, format string) (*RateImportResult, error) {
+	var rates []*models.ExchangeRate
+	var err error
+
+	switch strings.ToLower(format) {
+	case RateFormatCSV:
+		rates, err = parseRateCSV(r)
+	case RateFormatECB, "xml":
+		format = RateFormatECB
+		rates, err = parseRateECB(r)
+	default:
+		return nil, fmt.Errorf("unsupported exchange rate format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(rates) == 0 {
+		return nil, errors.New("no exchange rates found in file")
+	}
+
+	if err := s.rateRepo.Upsert(rates); err != nil {
+		return nil, err
+	}
+
+	result := &RateImportResult{
+		Format:    strings.ToLower(format),
+		Imported:  len(rates),
+		FirstDate: rates[0].RateDate,
+		LastDate:  rates[0].RateDate,
+	}
+	seen := make(map[string]bool)
+	for _, rate := range rates {
+		for _, currency := range []string{rate.BaseCurrency, rate.QuoteCurrency} {
+			if !seen[currency] {
+				seen[currency] = true
+				result.Currencies = append(result.Currencies, currency)
+			}
+		}
+		if rate.RateDate.Before(result.FirstDate) {
+			result.FirstDate = rate.RateDate
+		}
+		if rate.RateDate.After(result.LastDate) {
+			result.LastDate = rate.RateDate
+		}
+	}
+	sort.Strings(result.Currencies)
+
+	return result, nil
+}
+
+// ListRates retrieves a page of stored rates and the total count
+func (s *exchangeRateService) ListRates(baseCurrency, quoteCurrency string, limit, offset int) ([]*models.ExchangeRate, int64, error) {
+	// Set default limit if not provided or invalid
+	if limit <= 0 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	baseCurrency = strings.ToUpper(baseCurrency)
+	quoteCurrency = strings.ToUpper(quoteCurrency)
+
+	rates, err := s.rateRepo.List(baseCurrency, quoteCurrency, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.rateRepo.Count(baseCurrency, quoteCurrency)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return rates, total, nil
+}
+
+// Convert converts an amount between currencies at the latest rate quoted on
+// or before the given day
+func (s *exchangeRateService) Convert(amount money.Amount, from, to string, on time.Time) (money.Amount, *AppliedRate, error) {
+	return newCurrencyConverter(s.rateRepo).convert(amount, from, to, on)
+}
+
+// parseRateCSV reads rates from a CSV file with a header row naming the
+// date, base, quote and rate columns, e.g. "date,base,quote,rate"
+func parseRateCSV(r io.Reader) ([]*models.ExchangeRate, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.New("exchange rate CSV is empty")
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		switch name {
+		case "base_currency":
+			name = "base"
+		case "quote_currency":
+			name = "quote"
+		case "rate_date":
+			name = "date"
+		}
+		columns[name] = i
+	}
+	for _, required := range []string{"date", "base", "quote", "rate"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("exchange rate CSV is missing the %q column", required)
+		}
+	}
+
+	var rates []*models.ExchangeRate
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+
+		rate, err := newExchangeRate(
+			record[columns["date"]],
+			record[columns["base"]],
+			record[columns["quote"]],
+			record[columns["rate"]],
+			RateFormatCSV,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		rates = append(rates, rate)
+	}
+
+	return rates, nil
+}
+
+// ecbEnvelope mirrors the European Central Bank reference rate feeds, where
+// each day is a Cube holding one Cube per currency quoted against EUR
+type ecbEnvelope struct {
+	Days []struct {
+		Time  string `xml:"time,attr"`
+		Rates []struct {
+			Currency string `xml:"currency,attr"`
+			Rate     string `xml:"rate,attr"`
+		} `xml:"Cube"`
+	} `xml:"Cube>Cube"`
+}
+
+// parseRateECB reads rates from an ECB-style XML file
+func parseRateECB(r io.Reader) ([]*models.ExchangeRate, error) {
+	var envelope ecbEnvelope
+	if err := xml.NewDecoder(r).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("invalid ECB rate file: %v", err)
+	}
+
+	var rates []*models.ExchangeRate
+	for _, day := range envelope.Days {
+		for _, quote := range day.Rates {
+			rate, err := newExchangeRate(day.Time, "EUR", quote.Currency, quote.Rate, RateFormatECB)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %v", day.Time, quote.Currency, err)
+			}
+			rates = append(rates, rate)
+		}
+	}
+
+	return rates, nil
+}
+
+// newExchangeRate validates the fields of one imported rate
+func newExchangeRate(date, base, quote, value, source string) (*models.ExchangeRate, error) {
+	rateDate, err := time.Parse("2006-01-02", strings.TrimSpace(date))
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q", date)
+	}
+
+	base = strings.ToUpper(strings.TrimSpace(base))
+	quote = strings.ToUpper(strings.TrimSpace(quote))
+	if len(base) != 3 || len(quote) != 3 {
+		return nil, fmt.Errorf("invalid currency pair %q/%q", base, quote)
+	}
+	if base == quote {
+		return nil, fmt.Errorf("base and quote currency are both %s", base)
+	}
+
+	rate, err := money.ParseRate(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rate %q", value)
+	}
+
+	return &models.ExchangeRate{
+		BaseCurrency:  base,
+		QuoteCurrency: quote,
+		Rate:          rate,
+		RateDate:      rateDate,
+		Source:        source,
+	}, nil
+}
+
+// currencyConverter converts amounts using the exchange rate store. Rates are
+// cached, so one converter should only live as long as a single operation.
+type currencyConverter struct {
+	rateRepo repository.ExchangeRateRepository
+	cache    map[string]*AppliedRate
+}
+
+func newCurrencyConverter(rateRepo repository.ExchangeRateRepository) *currencyConverter {
+	return &currencyConverter{
+		rateRepo: rateRepo,
+		cache:    make(map[string]*AppliedRate),
+	}
+}
+
+// convert converts an amount and rounds it to the precision of the target currency
+func (c *currencyConverter) convert(amount money.Amount, from, to string, on time.Time) (money.Amount, *AppliedRate, error) {
+	applied, err := c.rate(from, to, on)
+	if err != nil {
+		return 0, nil, err
+	}
+	return amount.Convert(applied.Rate).Round(applied.To), applied, nil
+}
+
+// rate finds the rate from one currency to another on a given day. A rate
+// stored for the opposite direction is inverted, and when neither exists a
+// cross rate is built through one of the pivot currencies.
+func (c *currencyConverter) rate(from, to string, on time.Time) (*AppliedRate, error) {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	day := on.Format("2006-01-02")
+
+	key := from + "/" + to + "@" + day
+	if cached, ok := c.cache[key]; ok {
+		return cached, nil
+	}
+
+	if from == to {
+		applied := &AppliedRate{From: from, To: to, Rate: money.OneRate, RateDate: on}
+		c.cache[key] = applied
+		return applied, nil
+	}
+
+	applied, err := c.pair(from, to, on)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pivot := range pivotCurrencies {
+		if applied != nil {
+			break
+		}
+		if pivot == from || pivot == to {
+			continue
+		}
+
+		first, err := c.pair(from, pivot, on)
+		if err != nil {
+			return nil, err
+		}
+		if first == nil {
+			continue
+		}
+		second, err := c.pair(pivot, to, on)
+		if err != nil {
+			return nil, err
+		}
+		if second == nil {
+			continue
+		}
+
+		// A cross rate is only as fresh as the older of its two legs
+		rateDate := first.RateDate
+		if second.RateDate.Before(rateDate) {
+			rateDate = second.RateDate
+		}
+		applied = &AppliedRate{From: from, To: to, Rate: first.Rate.Cross(second.Rate), RateDate: rateDate}
+	}
+
+	if applied == nil {
+		return nil, fmt.Errorf("%w from %s to %s on %s", ErrNoExchangeRate, from, to, day)
+	}
+
+	c.cache[key] = applied
+	return applied, nil
+}
+
+// pair looks up a stored rate for a currency pair in either direction,
+// preferring the more recent quote. It returns nil if neither is stored.
+func (c *currencyConverter) pair(from, to string, on time.Time) (*AppliedRate, error) {
+	direct, err := c.rateRepo.FindLatest(from, to, on)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	inverse, err := c.rateRepo.FindLatest(to, from, on)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if direct != nil && (inverse == nil || !inverse.RateDate.After(direct.RateDate)) {
+		return &AppliedRate{From: from, To: to, Rate: direct.Rate, RateDate: direct.RateDate}, nil
+	}
+	if inverse != nil {
+		return &AppliedRate{From: from, To: to, Rate: inverse.Rate.Inverse(), RateDate: inverse.RateDate}, nil
+	}
+	return nil, nil
+}
+
+// reportCurrency converts the amounts of a single report into the user's base
+// currency and remembers which rates were used. Amounts in a currency with no
+// stored rate to the base currency are left out of the report, and their
+// currency is remembered so the report can say what it could not count.
+type reportCurrency struct {
+	base             string
+	on               time.Time
+	converter        *currencyConverter
+	walletCurrencies map[uuid.UUID]string
+	applied          map[string]*AppliedRate
+	missing          map[string]bool
+}
+
+func newReportCurrency(base string, on time.Time, converter *currencyConverter) *reportCurrency {
+	if base == "" {
+		base = money.DefaultCurrency
+	}
+	return &reportCurrency{
+		base:             strings.ToUpper(base),
+		on:               on,
+		converter:        converter,
+		walletCurrencies: make(map[uuid.UUID]string),
+		applied:          make(map[string]*AppliedRate),
+		missing:          make(map[string]bool),
+	}
+}
+
+// loadReportCurrency prepares the conversion of a user's amounts into their
+// base currency as of the given day. Wallets are loaded when not supplied so
+// that transactions can be matched to the currency of their wallet.
+func loadReportCurrency(
+	userRepo repository.UserRepository,
+	walletRepo repository.WalletRepository,
+	rateRepo repository.ExchangeRateRepository,
+	userID uuid.UUID,
+	wallets []*models.Wallet,
+	on time.Time,
+) (*reportCurrency, error) {
+	user, err := userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if wallets == nil {
+		wallets, err = walletRepo.FindByUserID(userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var base string
+	if user != nil {
+		base = user.Currency
+	}
+	report := newReportCurrency(base, on, newCurrencyConverter(rateRepo))
+	for _, wallet := range wallets {
+		report.walletCurrencies[wallet.ID] = wallet.Currency
+	}
+
+	return report, nil
+}
+
+// convert converts an amount held in the given currency into the base
+// currency. Without a rate for the currency the amount counts as zero.
+func (r *reportCurrency) convert(amount money.Amount, currency string) (money.Amount, error) {
+	currency = strings.ToUpper(currency)
+	if currency == "" || currency == r.base {
+		return amount, nil
+	}
+
+	converted, applied, err := r.converter.convert(amount, currency, r.base, r.on)
+	if errors.Is(err, ErrNoExchangeRate) {
+		r.missing[currency] = true
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	r.applied[currency] = applied
+	return converted, nil
+}
+
+// convertTransaction converts a transaction's amount from the currency of its
+// wallet into the base currency. Transactions without a wallet are assumed to
+// be in the base currency already.
+func (r *reportCurrency) convertTransaction(txn *models.Transaction) (money.Amount, error) {
+	if txn.WalletID == nil {
+		return txn.Amount, nil
+	}
+	return r.convert(txn.Amount, r.walletCurrencies[*txn.WalletID])
+}
+
+// convertAggregate converts an aggregate total from the currency of its wallet
+// into the base currency
+func (r *reportCurrency) convertAggregate(aggregate *repository.TransactionAggregate) (money.Amount, error) {
+	if aggregate.WalletID == nil {
+		return aggregate.Total, nil
+	}
+	return r.convert(aggregate.Total, r.walletCurrencies[*aggregate.WalletID])
+}
+
+// rates returns the rates used so far, ordered by source currency
+func (r *reportCurrency) rates() []*AppliedRate {
+	rates := make([]*AppliedRate, 0, len(r.applied))
+	for _, applied := range r.applied {
+		rates = append(rates, applied)
+	}
+	sort.Slice(rates, func(i, j int) bool {
+		return rates[i].From < rates[j].From
+	})
+	return rates
+}
+
+// unconverted returns the currencies left out for want of a rate, in order
+func (r *reportCurrency) unconverted() []string {
+	currencies := make([]string, 0, len(r.missing))
+	for currency := range r.missing {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+	return currencies
+}
+
+// complete returns ErrNoExchangeRate if any amount was left out, for callers
+// that must not record a report missing some of its amounts
+func (r *reportCurrency) complete() error {
+	if len(r.missing) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w from %s to %s", ErrNoExchangeRate, strings.Join(r.unconverted(), ", "), r.base)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
 	"github.com/nyunja/fity-budget-backend/internal/repository"
 )
 
@@ -16,7 +17,7 @@ type GoalService interface {
 	GetGoalByID(id, userID uuid.UUID) (*models.SavingGoal, error)
 	UpdateGoal(id, userID uuid.UUID, req UpdateGoalRequest) (*models.SavingGoal, error)
 	DeleteGoal(id, userID uuid.UUID) error
-	AddProgress(id, userID uuid.UUID, amount float64) (*models.SavingGoal, error)
+	AddProgress(id, userID uuid.UUID, amount money.Amount) (*models.SavingGoal, error)
 	GetGoalProgress(userID uuid.UUID) (*GoalProgressSummary, error)
 }
 
@@ -26,38 +27,38 @@ type goalService struct {
 
 // CreateGoalRequest represents the data needed to create a savings goal
 type CreateGoalRequest struct {
-	Name          string     `json:"name" binding:"required"`
-	TargetAmount  float64    `json:"target_amount" binding:"required,gt=0"`
-	CurrentAmount float64    `json:"current_amount" binding:"omitempty,gte=0"`
-	Color         string     `json:"color" binding:"required"`
-	Icon          string     `json:"icon"`
-	Deadline      *time.Time `json:"deadline"`
-	Priority      string     `json:"priority" binding:"omitempty,oneof=High Medium Low"`
-	Category      string     `json:"category"`
-	Status        string     `json:"status" binding:"omitempty,oneof=Active Paused Completed"`
+	Name          string       `json:"name" binding:"required"`
+	TargetAmount  money.Amount `json:"target_amount" binding:"required,gt=0"`
+	CurrentAmount money.Amount `json:"current_amount" binding:"omitempty,gte=0"`
+	Color         string       `json:"color" binding:"required"`
+	Icon          string       `json:"icon"`
+	Deadline      *time.Time   `json:"deadline"`
+	Priority      string       `json:"priority" binding:"omitempty,oneof=High Medium Low"`
+	Category      string       `json:"category"`
+	Status        string       `json:"status" binding:"omitempty,oneof=Active Paused Completed"`
 }
 
 // UpdateGoalRequest represents the data needed to update a savings goal
 type UpdateGoalRequest struct {
-	Name          string     `json:"name"`
-	TargetAmount  float64    `json:"target_amount" binding:"omitempty,gt=0"`
-	CurrentAmount float64    `json:"current_amount" binding:"omitempty,gte=0"`
-	Color         string     `json:"color"`
-	Icon          string     `json:"icon"`
-	Deadline      *time.Time `json:"deadline"`
-	Priority      string     `json:"priority" binding:"omitempty,oneof=High Medium Low"`
-	Category      string     `json:"category"`
-	Status        string     `json:"status" binding:"omitempty,oneof=Active Paused Completed"`
+	Name          string       `json:"name"`
+	TargetAmount  money.Amount `json:"target_amount" binding:"omitempty,gt=0"`
+	CurrentAmount money.Amount `json:"current_amount" binding:"omitempty,gte=0"`
+	Color         string       `json:"color"`
+	Icon          string       `json:"icon"`
+	Deadline      *time.Time   `json:"deadline"`
+	Priority      string       `json:"priority" binding:"omitempty,oneof=High Medium Low"`
+	Category      string       `json:"category"`
+	Status        string       `json:"status" binding:"omitempty,oneof=Active Paused Completed"`
 }
 
 // GoalProgressSummary represents overall progress for all user goals
 type GoalProgressSummary struct {
-	TotalGoals      int     `json:"total_goals"`
-	CompletedGoals  int     `json:"completed_goals"`
-	ActiveGoals     int     `json:"active_goals"`
-	TotalTarget     float64 `json:"total_target"`
-	TotalSaved      float64 `json:"total_saved"`
-	OverallProgress float64 `json:"overall_progress"`
+	TotalGoals      int          `json:"total_goals"`
+	CompletedGoals  int          `json:"completed_goals"`
+	ActiveGoals     int          `json:"active_goals"`
+	TotalTarget     money.Amount `json:"total_target"`
+	TotalSaved      money.Amount `json:"total_saved"`
+	OverallProgress float64      `json:"overall_progress"`
 }
 
 func NewGoalService(goalRepo repository.GoalRepository) GoalService {
@@ -221,7 +222,7 @@ func (s *goalService) DeleteGoal(id, userID uuid.UUID) error {
 }
 
 // AddProgress adds an amount to the goal's current progress
-func (s *goalService) AddProgress(id, userID uuid.UUID, amount float64) (*models.SavingGoal, error) {
+func (s *goalService) AddProgress(id, userID uuid.UUID, amount money.Amount) (*models.SavingGoal, error) {
 	if amount <= 0 {
 		return nil, errors.New("amount must be greater than zero")
 	}
@@ -287,7 +288,7 @@ func (s *goalService) GetGoalProgress(userID uuid.UUID) (*GoalProgressSummary, e
 
 	// Calculate overall progress percentage
 	if summary.TotalTarget > 0 {
-		summary.OverallProgress = (summary.TotalSaved / summary.TotalTarget) * 100
+		summary.OverallProgress = summary.TotalSaved.Percent(summary.TotalTarget)
 	}
 
 	return summary, nil
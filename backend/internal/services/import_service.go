@@ -0,0 +1,562 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/imports"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"github.com/nyunja/fity-budget-backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// ErrUnsupportedImportFormat is returned for statement files in a format
+// that cannot be imported
+var ErrUnsupportedImportFormat = errors.New("unsupported import format")
+
+// ImportService defines the interface for statement import operations
+type ImportService interface {
+	PreviewImport(userID uuid.UUID, req PreviewImportRequest) (*ImportPreview, error)
+	CommitImport(id, userID uuid.UUID, req CommitImportRequest) (*models.ImportJob, error)
+	UndoImport(id, userID uuid.UUID) (*models.ImportJob, error)
+	GetImport(id, userID uuid.UUID) (*models.ImportJob, error)
+	ListImports(userID uuid.UUID, limit, offset int) ([]*models.ImportJob, int64, error)
+	ImportMpesaMessages(userID uuid.UUID, req ImportMpesaMessagesRequest) (*models.ImportJob, error)
+}
+
+type importService struct {
+	importRepo      repository.ImportJobRepository
+	transactionRepo repository.TransactionRepository
+	walletRepo      repository.WalletRepository
+	ruleRepo        repository.RuleRepository
+	txManager       repository.TxManager
+}
+
+// PreviewImportRequest represents a statement file to be previewed
+type PreviewImportRequest struct {
+	WalletID uuid.UUID
+	FileName string
+	// Format of the file; inferred from the file name when empty
+	Format string
+	File   io.Reader
+	// Mapping of CSV columns; detected from the header row when nil
+	Mapping *imports.Mapping
+}
+
+// CommitImportRequest represents the options for committing an import
+type CommitImportRequest struct {
+	// IncludeDuplicates imports rows that match existing transactions too
+	IncludeDuplicates bool `json:"include_duplicates"`
+}
+
+// ImportMpesaMessagesRequest represents pasted M-PESA messages or statement text
+type ImportMpesaMessagesRequest struct {
+	// WalletID defaults to the user's mobile money wallet
+	WalletID *uuid.UUID `json:"wallet_id"`
+	Text     string     `json:"text"`
+}
+
+// ImportPreview is a parsed, not yet committed import job together with the
+// column mapping used to read it
+type ImportPreview struct {
+	Job     *models.ImportJob `json:"import"`
+	Mapping *imports.Mapping  `json:"mapping,omitempty"`
+}
+
+func NewImportService(
+	importRepo repository.ImportJobRepository,
+	transactionRepo repository.TransactionRepository,
+	walletRepo repository.WalletRepository,
+	ruleRepo repository.RuleRepository,
+	txManager repository.TxManager,
+) ImportService {
+	return &importService{
+		importRepo:      importRepo,
+		transactionRepo: transactionRepo,
+		walletRepo:      walletRepo,
+		ruleRepo:        ruleRepo,
+		txManager:       txManager,
+	}
+}
+
+// PreviewImport parses a statement file into a new import job and flags the
+// rows that duplicate transactions already in the wallet. Nothing is
+// written to the wallet until the job is committed.
+func (s *importService) PreviewImport(userID uuid.UUID, req PreviewImportRequest) (*ImportPreview, error) {
+	wallet, err := s.walletRepo.FindByID(req.WalletID)
+	if err != nil {
+		return nil, errors.New("wallet not found")
+	}
+	if wallet.UserID != userID {
+		return nil, errors.New("unauthorized access to wallet")
+	}
+
+	format := strings.ToLower(req.Format)
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(req.FileName)), ".")
+	}
+	// Statements saved as text are M-PESA statements
+	if format == "txt" {
+		format = models.ImportSourceMpesa
+	}
+
+	preview := &ImportPreview{}
+	var records []*imports.Record
+	switch format {
+	case models.ImportSourceCSV:
+		result, err := imports.ParseCSV(req.File, req.Mapping)
+		if err != nil {
+			return nil, err
+		}
+		records = result.Records
+		preview.Mapping = &result.Mapping
+	case models.ImportSourceOFX, models.ImportSourceQFX:
+		if records, err = imports.ParseOFX(req.File); err != nil {
+			return nil, err
+		}
+	case models.ImportSourceQIF:
+		if records, err = imports.ParseQIF(req.File); err != nil {
+			return nil, err
+		}
+	case models.ImportSourceMpesa:
+		if records, err = imports.ParseMpesa(req.File); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedImportFormat, format)
+	}
+
+	job := &models.ImportJob{
+		UserID:   userID,
+		WalletID: wallet.ID,
+		Source:   format,
+		FileName: truncate(req.FileName, 255),
+		Status:   models.ImportStatusPreviewed,
+		Rows:     importRows(records),
+	}
+	if err := s.markDuplicates(s.transactionRepo, job, wallet); err != nil {
+		return nil, err
+	}
+	job.TotalRows, job.ErrorRows, job.DuplicateRows = countRows(job.Rows)
+
+	if err := s.importRepo.Create(job); err != nil {
+		return nil, errors.New("failed to save import")
+	}
+
+	preview.Job = job
+	return preview, nil
+}
+
+// CommitImport creates a transaction for every readable row of a previewed
+// job and moves the wallet balance by their total. Duplicates are checked
+// again, since transactions may have been added after the preview, and are
+// skipped unless the request includes them. The user's rules categorize and
+// tag the transactions the statement leaves uncategorized.
+func (s *importService) CommitImport(id, userID uuid.UUID, req CommitImportRequest) (*models.ImportJob, error) {
+	job, err := s.GetImport(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := loadRuleSet(s.ruleRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.txManager.WithinTransaction(func(tx *gorm.DB) error {
+		walletRepo := s.walletRepo.WithTx(tx)
+		transactionRepo := s.transactionRepo.WithTx(tx)
+		importRepo := s.importRepo.WithTx(tx)
+
+		// Locking the wallet serialises imports into it
+		wallet, err := walletRepo.FindByIDForUpdate(job.WalletID)
+		if err != nil {
+			return errors.New("wallet not found")
+		}
+		if job, err = importRepo.FindByID(id); err != nil {
+			return errors.New("import not found")
+		}
+		if job.Status != models.ImportStatusPreviewed {
+			return fmt.Errorf("import has already been %s", strings.ToLower(job.Status))
+		}
+
+		if err := s.markDuplicates(transactionRepo, job, wallet); err != nil {
+			return err
+		}
+
+		var transactions []*models.Transaction
+		var imported []*models.ImportRow
+		var delta money.Amount
+		for _, row := range job.Rows {
+			if row.Error != "" || row.DuplicateOfID != nil && !req.IncludeDuplicates {
+				continue
+			}
+			transaction := importedTransaction(row, job, wallet)
+			rules.fill(transaction)
+			if transaction.Category == "" {
+				transaction.Category = defaultCategory(transaction.Type)
+			}
+			if transaction.Amount <= 0 {
+				row.Error = "amount rounds to zero in " + wallet.Currency
+				continue
+			}
+			row.TransactionID = &transaction.ID
+			transactions = append(transactions, transaction)
+			imported = append(imported, row)
+			delta += transaction.BalanceEffect()
+		}
+
+		if err := transactionRepo.CreateBatch(transactions); err != nil {
+			return errors.New("failed to create transactions")
+		}
+
+		now := time.Now()
+		wallet.LastSynced = &now
+		if err := walletRepo.Update(wallet); err != nil {
+			return errors.New("failed to update wallet")
+		}
+		if err := applyBalanceDeltas(walletRepo, map[uuid.UUID]money.Amount{wallet.ID: delta}); err != nil {
+			return err
+		}
+
+		job.Status = models.ImportStatusCompleted
+		job.CommittedAt = &now
+		job.ImportedRows = len(imported)
+		job.TotalRows, job.ErrorRows, job.DuplicateRows = countRows(job.Rows)
+		if err := importRepo.UpdateRows(job.Rows); err != nil {
+			return errors.New("failed to update import")
+		}
+		return importRepo.Update(job)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// UndoImport deletes the transactions a committed job created and reverses
+// their effect on wallet balances, including any edits made since
+func (s *importService) UndoImport(id, userID uuid.UUID) (*models.ImportJob, error) {
+	job, err := s.GetImport(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.txManager.WithinTransaction(func(tx *gorm.DB) error {
+		walletRepo := s.walletRepo.WithTx(tx)
+		transactionRepo := s.transactionRepo.WithTx(tx)
+		importRepo := s.importRepo.WithTx(tx)
+
+		if _, err := walletRepo.FindByIDForUpdate(job.WalletID); err != nil {
+			return errors.New("wallet not found")
+		}
+		if job, err = importRepo.FindByID(id); err != nil {
+			return errors.New("import not found")
+		}
+		if job.Status != models.ImportStatusCompleted {
+			return errors.New("only completed imports can be undone")
+		}
+
+		transactions, err := transactionRepo.FindByFilter(repository.TransactionFilter{
+			UserID:      userID,
+			ImportJobID: &job.ID,
+		}, repository.TransactionSort{}, -1, -1)
+		if err != nil {
+			return err
+		}
+
+		deltas := make(map[uuid.UUID]money.Amount)
+		for _, transaction := range transactions {
+			if effect := transaction.BalanceEffect(); effect != 0 {
+				deltas[*transaction.WalletID] -= effect
+			}
+		}
+
+		if err := transactionRepo.DeleteByImportJobID(job.ID); err != nil {
+			return errors.New("failed to delete imported transactions")
+		}
+		if err := applyBalanceDeltas(walletRepo, deltas); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		job.Status = models.ImportStatusUndone
+		job.UndoneAt = &now
+		return importRepo.Update(job)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// ImportMpesaMessages reads pasted M-PESA confirmation messages or statement
+// text and imports them straight into a wallet, by default the user's mobile
+// money wallet. Messages whose code is already in the wallet are skipped, so
+// the same messages can be pasted again. The import can be undone like any
+// other.
+func (s *importService) ImportMpesaMessages(userID uuid.UUID, req ImportMpesaMessagesRequest) (*models.ImportJob, error) {
+	if strings.TrimSpace(req.Text) == "" {
+		return nil, imports.ErrNoRecords
+	}
+
+	walletID := req.WalletID
+	if walletID == nil {
+		wallet, err := s.mobileMoneyWallet(userID)
+		if err != nil {
+			return nil, err
+		}
+		walletID = &wallet.ID
+	}
+
+	preview, err := s.PreviewImport(userID, PreviewImportRequest{
+		WalletID: *walletID,
+		Format:   models.ImportSourceMpesa,
+		File:     strings.NewReader(req.Text),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.CommitImport(preview.Job.ID, userID, CommitImportRequest{})
+}
+
+// mobileMoneyWallet finds the wallet M-PESA messages go to when none is
+// named: the user's default wallet if it is a mobile money wallet, otherwise
+// their first one
+func (s *importService) mobileMoneyWallet(userID uuid.UUID) (*models.Wallet, error) {
+	wallets, err := s.walletRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var found *models.Wallet
+	for _, wallet := range wallets {
+		if wallet.Type != models.WalletTypeMobileMoney {
+			continue
+		}
+		if found == nil || wallet.IsDefault {
+			found = wallet
+		}
+	}
+	if found == nil {
+		return nil, errors.New("no mobile money wallet found, choose a wallet_id")
+	}
+	return found, nil
+}
+
+// GetImport retrieves an import job with its rows
+func (s *importService) GetImport(id, userID uuid.UUID) (*models.ImportJob, error) {
+	job, err := s.importRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("import not found")
+	}
+
+	// Verify import belongs to user
+	if job.UserID != userID {
+		return nil, errors.New("unauthorized access to import")
+	}
+
+	return job, nil
+}
+
+// ListImports retrieves a page of a user's import jobs and their total count
+func (s *importService) ListImports(userID uuid.UUID, limit, offset int) ([]*models.ImportJob, int64, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	jobs, err := s.importRepo.FindByUserID(userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.importRepo.CountByUserID(userID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return jobs, total, nil
+}
+
+// markDuplicates flags the rows of a job that match a transaction already in
+// the wallet: first by the statement's external ID, then by the same day,
+// amount and type. Each existing transaction matches at most one row, so
+// genuinely repeated purchases are only flagged as often as they were recorded.
+// A row and a transaction that both carry external IDs, which differ, are
+// different transactions and never match.
+func (s *importService) markDuplicates(transactionRepo repository.TransactionRepository, job *models.ImportJob, wallet *models.Wallet) error {
+	var first, last time.Time
+	var externalIDs []string
+	for _, row := range job.Rows {
+		row.DuplicateOfID = nil
+		if row.Error != "" {
+			continue
+		}
+		date := *row.TransactionDate
+		if first.IsZero() || date.Before(first) {
+			first = date
+		}
+		if date.After(last) {
+			last = date
+		}
+		if row.ExternalID != "" {
+			externalIDs = append(externalIDs, row.ExternalID)
+		}
+	}
+	if first.IsZero() {
+		return nil
+	}
+
+	// Compare whole days, widened by a day either side for timezone differences
+	filter := repository.TransactionFilter{
+		UserID:    job.UserID,
+		WalletIDs: []uuid.UUID{wallet.ID},
+		StartDate: startOfDay(first).AddDate(0, 0, -1),
+		EndDate:   startOfDay(last).AddDate(0, 0, 2),
+	}
+	existing, err := transactionRepo.FindByFilter(filter, repository.TransactionSort{Ascending: true}, -1, -1)
+	if err != nil {
+		return err
+	}
+	if len(externalIDs) > 0 {
+		byExternalID, err := transactionRepo.FindByFilter(repository.TransactionFilter{
+			UserID:      job.UserID,
+			WalletIDs:   []uuid.UUID{wallet.ID},
+			ExternalIDs: externalIDs,
+		}, repository.TransactionSort{Ascending: true}, -1, -1)
+		if err != nil {
+			return err
+		}
+		existing = append(existing, byExternalID...)
+	}
+
+	matched := make(map[uuid.UUID]bool)
+	external := make(map[string]*models.Transaction)
+	similar := make(map[string][]*models.Transaction)
+	for _, transaction := range existing {
+		if transaction.ExternalID != "" {
+			external[transaction.ExternalID] = transaction
+		}
+		key := duplicateKey(transaction.TransactionDate, transaction.Amount, transaction.Type)
+		similar[key] = append(similar[key], transaction)
+	}
+
+	// External IDs are exact, so they claim their matches before similar rows do
+	for _, row := range job.Rows {
+		if row.Error != "" || row.ExternalID == "" {
+			continue
+		}
+		if transaction, ok := external[row.ExternalID]; ok && !matched[transaction.ID] {
+			matched[transaction.ID] = true
+			row.DuplicateOfID = &transaction.ID
+		}
+	}
+	for _, row := range job.Rows {
+		if row.Error != "" || row.DuplicateOfID != nil {
+			continue
+		}
+		key := duplicateKey(*row.TransactionDate, row.Amount.Round(wallet.Currency), row.Type)
+		for _, transaction := range similar[key] {
+			if row.ExternalID != "" && transaction.ExternalID != "" {
+				continue
+			}
+			if !matched[transaction.ID] {
+				matched[transaction.ID] = true
+				row.DuplicateOfID = &transaction.ID
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// duplicateKey identifies transactions on the same day for the same amount and direction
+func duplicateKey(date time.Time, amount money.Amount, txnType string) string {
+	return date.Format("2006-01-02") + "|" + amount.String() + "|" + txnType
+}
+
+// startOfDay truncates a time to midnight in its own location
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// importRows turns parsed statement records into import rows in file order
+func importRows(records []*imports.Record) []*models.ImportRow {
+	rows := make([]*models.ImportRow, 0, len(records))
+	for _, record := range records {
+		row := &models.ImportRow{
+			Line:        record.Line,
+			Description: truncate(record.Description, 255),
+			Amount:      record.Amount,
+			Type:        record.Type,
+			Category:    truncate(record.Category, 100),
+			Balance:     record.Balance,
+			ExternalID:  truncate(record.ExternalID, 100),
+			Error:       truncate(record.Error, 255),
+		}
+		if !record.Date.IsZero() {
+			date := record.Date
+			row.TransactionDate = &date
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// importedTransaction builds the transaction a committed row creates. Its
+// category is left empty when the statement has none.
+func importedTransaction(row *models.ImportRow, job *models.ImportJob, wallet *models.Wallet) *models.Transaction {
+	return &models.Transaction{
+		ID:              uuid.New(),
+		UserID:          job.UserID,
+		WalletID:        &wallet.ID,
+		Amount:          row.Amount.Round(wallet.Currency),
+		Type:            row.Type,
+		Name:            row.Description,
+		Method:          wallet.Name,
+		Category:        row.Category,
+		Status:          "Completed",
+		TransactionDate: *row.TransactionDate,
+		ExternalID:      row.ExternalID,
+		ImportJobID:     &job.ID,
+	}
+}
+
+// countRows tallies the rows of a job, those that could not be read and
+// those that duplicate existing transactions
+func countRows(rows []*models.ImportRow) (total, errored, duplicates int) {
+	for _, row := range rows {
+		switch {
+		case row.Error != "":
+			errored++
+		case row.DuplicateOfID != nil:
+			duplicates++
+		}
+	}
+	return len(rows), errored, duplicates
+}
+
+// truncate shortens s to at most n bytes without splitting a character
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,343 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // Register the GIF decoder for thumbnails
+	"image/jpeg"
+	_ "image/png" // Register the PNG decoder for thumbnails
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/repository"
+	"github.com/nyunja/fity-budget-backend/internal/storage"
+)
+
+const (
+	// MaxReceiptSize bounds the size of an uploaded receipt file
+	MaxReceiptSize = 10 << 20
+	// thumbnailSize is the longest side of a receipt thumbnail in pixels
+	thumbnailSize = 320
+	// maxReceiptPixels bounds the dimensions of images that get a thumbnail,
+	// so a small file cannot decode into a huge image
+	maxReceiptPixels = 50_000_000
+)
+
+var (
+	// ErrReceiptTooLarge is returned for receipt files over MaxReceiptSize
+	ErrReceiptTooLarge = errors.New("receipt files must be at most 10 MB")
+	// ErrUnsupportedReceiptType is returned for receipt files that are not a
+	// supported image or a PDF
+	ErrUnsupportedReceiptType = errors.New("receipts must be JPEG, PNG, GIF or WebP images or PDF files")
+)
+
+// receiptExtensions maps the accepted receipt content types to the file
+// extension they are stored under
+var receiptExtensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/gif":       ".gif",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+}
+
+// ReceiptService defines the interface for receipt operations
+type ReceiptService interface {
+	UploadReceipt(userID, transactionID uuid.UUID, req UploadReceiptRequest) (*models.Receipt, error)
+	GetTransactionReceipts(transactionID, userID uuid.UUID) ([]*models.Receipt, error)
+	GetReceiptByID(id, userID uuid.UUID) (*models.Receipt, error)
+	OpenReceipt(id, userID uuid.UUID, thumbnail bool) (*ReceiptFile, error)
+	DeleteReceipt(id, userID uuid.UUID) error
+	DeleteTransactionReceipts(transactionID uuid.UUID) error
+}
+
+type receiptService struct {
+	receiptRepo     repository.ReceiptRepository
+	transactionRepo repository.TransactionRepository
+	store           storage.Storage
+}
+
+// UploadReceiptRequest represents an uploaded receipt file
+type UploadReceiptRequest struct {
+	FileName string
+	File     io.Reader
+}
+
+// ReceiptFile is an opened receipt or thumbnail. The caller must close Body.
+type ReceiptFile struct {
+	Receipt     *models.Receipt
+	ContentType string
+	Size        int64 // -1 when unknown
+	Body        io.ReadCloser
+}
+
+func NewReceiptService(
+	receiptRepo repository.ReceiptRepository,
+	transactionRepo repository.TransactionRepository,
+	store storage.Storage,
+) ReceiptService {
+	return &receiptService{
+		receiptRepo:     receiptRepo,
+		transactionRepo: transactionRepo,
+		store:           store,
+	}
+}
+
+// UploadReceipt stores a receipt for a transaction. The content type is
+// sniffed from the file itself rather than trusted from the client, and
+// images get a JPEG thumbnail.
+func (s *receiptService) UploadReceipt(userID, transactionID uuid.UUID, req UploadReceiptRequest) (*models.Receipt, error) {
+	if _, err := s.getTransaction(transactionID, userID); err != nil {
+		return nil, err
+	}
+
+	// Read one byte past the limit to tell a file at the limit from a larger one
+	data, err := io.ReadAll(io.LimitReader(req.File, MaxReceiptSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxReceiptSize {
+		return nil, ErrReceiptTooLarge
+	}
+	if len(data) == 0 {
+		return nil, errors.New("receipt file is empty")
+	}
+
+	contentType := http.DetectContentType(data)
+	ext, ok := receiptExtensions[contentType]
+	if !ok {
+		return nil, fmt.Errorf("%w, got %s", ErrUnsupportedReceiptType, contentType)
+	}
+
+	var thumbnail []byte
+	if contentType != "application/pdf" && contentType != "image/webp" {
+		if thumbnail, err = makeThumbnail(data); err != nil {
+			return nil, err
+		}
+	}
+
+	receipt := &models.Receipt{
+		ID:            uuid.New(),
+		UserID:        userID,
+		TransactionID: transactionID,
+		FileName:      receiptFileName(req.FileName, ext),
+		ContentType:   contentType,
+		Size:          int64(len(data)),
+	}
+	receipt.StorageKey = fmt.Sprintf("receipts/%s/%s%s", userID, receipt.ID, ext)
+
+	if err := s.store.Put(receipt.StorageKey, bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("failed to store receipt: %w", err)
+	}
+	if thumbnail != nil {
+		receipt.ThumbnailKey = fmt.Sprintf("receipts/%s/%s_thumb.jpg", userID, receipt.ID)
+		receipt.HasThumbnail = true
+		if err := s.store.Put(receipt.ThumbnailKey, bytes.NewReader(thumbnail)); err != nil {
+			s.removeFiles(receipt)
+			return nil, fmt.Errorf("failed to store receipt thumbnail: %w", err)
+		}
+	}
+
+	if err := s.receiptRepo.Create(receipt); err != nil {
+		s.removeFiles(receipt)
+		return nil, err
+	}
+
+	return receipt, nil
+}
+
+// GetTransactionReceipts retrieves a transaction's receipts
+func (s *receiptService) GetTransactionReceipts(transactionID, userID uuid.UUID) ([]*models.Receipt, error) {
+	if _, err := s.getTransaction(transactionID, userID); err != nil {
+		return nil, err
+	}
+	return s.receiptRepo.FindByTransactionID(transactionID)
+}
+
+// GetReceiptByID retrieves a specific receipt
+func (s *receiptService) GetReceiptByID(id, userID uuid.UUID) (*models.Receipt, error) {
+	receipt, err := s.receiptRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("receipt not found")
+	}
+
+	// Verify receipt belongs to user
+	if receipt.UserID != userID {
+		return nil, errors.New("unauthorized access to receipt")
+	}
+
+	return receipt, nil
+}
+
+// OpenReceipt opens a receipt file, or its thumbnail, for download
+func (s *receiptService) OpenReceipt(id, userID uuid.UUID, thumbnail bool) (*ReceiptFile, error) {
+	receipt, err := s.GetReceiptByID(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	file := &ReceiptFile{Receipt: receipt, ContentType: receipt.ContentType, Size: receipt.Size}
+	key := receipt.StorageKey
+	if thumbnail {
+		if receipt.ThumbnailKey == "" {
+			return nil, errors.New("receipt has no thumbnail")
+		}
+		file.ContentType = "image/jpeg"
+		file.Size = -1
+		key = receipt.ThumbnailKey
+	}
+
+	file.Body, err = s.store.Open(key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, errors.New("receipt file not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// DeleteReceipt deletes a receipt and its stored files
+func (s *receiptService) DeleteReceipt(id, userID uuid.UUID) error {
+	receipt, err := s.GetReceiptByID(id, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.receiptRepo.Delete(id); err != nil {
+		return err
+	}
+	return s.removeFiles(receipt)
+}
+
+// DeleteTransactionReceipts deletes all receipts of a transaction along with
+// their files. It is meant for transactions that are being removed for good,
+// so it does not check ownership.
+func (s *receiptService) DeleteTransactionReceipts(transactionID uuid.UUID) error {
+	receipts, err := s.receiptRepo.FindByTransactionID(transactionID)
+	if err != nil {
+		return err
+	}
+
+	for _, receipt := range receipts {
+		if err := s.receiptRepo.Delete(receipt.ID); err != nil {
+			return err
+		}
+		if err := s.removeFiles(receipt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getTransaction retrieves a transaction and verifies it belongs to the user
+func (s *receiptService) getTransaction(transactionID, userID uuid.UUID) (*models.Transaction, error) {
+	transaction, err := s.transactionRepo.FindByID(transactionID)
+	if err != nil {
+		return nil, errors.New("transaction not found")
+	}
+
+	// Verify transaction belongs to user
+	if transaction.UserID != userID {
+		return nil, errors.New("unauthorized access to transaction")
+	}
+
+	return transaction, nil
+}
+
+// removeFiles deletes a receipt's file and thumbnail from storage
+func (s *receiptService) removeFiles(receipt *models.Receipt) error {
+	err := s.store.Delete(receipt.StorageKey)
+	if receipt.ThumbnailKey != "" {
+		if thumbErr := s.store.Delete(receipt.ThumbnailKey); err == nil {
+			err = thumbErr
+		}
+	}
+	return err
+}
+
+// receiptFileName cleans the client's file name for display and downloads,
+// falling back to a generic name with the stored extension
+func receiptFileName(name, ext string) string {
+	name = strings.TrimSpace(filepath.Base(strings.ReplaceAll(name, "\\", "/")))
+	if name == "" || name == "." || name == "/" {
+		return "receipt" + ext
+	}
+	if len(name) > 255 {
+		name = name[len(name)-255:]
+	}
+	return name
+}
+
+// makeThumbnail decodes a JPEG, PNG or GIF image and encodes a JPEG of it that
+// fits within thumbnailSize pixels
+func makeThumbnail(data []byte) ([]byte, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.New("receipt image could not be read")
+	}
+	if config.Width*config.Height > maxReceiptPixels {
+		return nil, errors.New("receipt image dimensions are too large")
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.New("receipt image could not be read")
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, scaleDown(img, thumbnailSize), &jpeg.Options{Quality: 80}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// scaleDown shrinks an image to fit within size×size pixels, averaging the
+// source pixels each output pixel covers. Smaller images keep their size.
+// Transparent areas are flattened onto white since JPEG has no alpha.
+func scaleDown(img image.Image, size int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	outWidth, outHeight := width, height
+	if width > size || height > size {
+		if width >= height {
+			outWidth, outHeight = size, max(1, height*size/width)
+		} else {
+			outWidth, outHeight = max(1, width*size/height), size
+		}
+	}
+
+	out := image.NewRGBA(image.Rect(0, 0, outWidth, outHeight))
+	for y := 0; y < outHeight; y++ {
+		y0, y1 := bounds.Min.Y+y*height/outHeight, bounds.Min.Y+(y+1)*height/outHeight
+		for x := 0; x < outWidth; x++ {
+			x0, x1 := bounds.Min.X+x*width/outWidth, bounds.Min.X+(x+1)*width/outWidth
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := img.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			// Colors are alpha-premultiplied, so adding the missing coverage
+			// as white composites the pixel onto a white background
+			white := 0xffff - a/n
+			out.Set(x, y, color.RGBA64{
+				R: uint16(r/n + white),
+				G: uint16(g/n + white),
+				B: uint16(b/n + white),
+				A: 0xffff,
+			})
+		}
+	}
+	return out
+}
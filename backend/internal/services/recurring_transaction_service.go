@@ -0,0 +1,516 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"github.com/nyunja/fity-budget-backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+const (
+	// dueBatchSize bounds how many schedules one scheduler run posts
+	dueBatchSize = 500
+	// maxPostsPerRun bounds how many occurrences of one schedule a run posts;
+	// the rest are caught up by the following runs
+	maxPostsPerRun = 100
+	// maxUpcoming bounds how many upcoming occurrences are listed
+	maxUpcoming = 500
+)
+
+// RecurringTransactionService defines the interface for recurring transaction operations
+type RecurringTransactionService interface {
+	CreateRecurring(userID uuid.UUID, req CreateRecurringRequest) (*models.RecurringTransaction, error)
+	GetUserRecurring(userID uuid.UUID) ([]*models.RecurringTransaction, error)
+	GetRecurringByID(id, userID uuid.UUID) (*models.RecurringTransaction, error)
+	UpdateRecurring(id, userID uuid.UUID, req UpdateRecurringRequest) (*models.RecurringTransaction, error)
+	DeleteRecurring(id, userID uuid.UUID) error
+	PreviewOccurrences(id, userID uuid.UUID, limit int) ([]time.Time, error)
+	GetUpcoming(userID uuid.UUID, until time.Time) ([]*UpcomingOccurrence, error)
+	PostDueOccurrences(now time.Time) (int, error)
+}
+
+type recurringTransactionService struct {
+	recurringRepo   repository.RecurringTransactionRepository
+	transactionRepo repository.TransactionRepository
+	walletRepo      repository.WalletRepository
+	txManager       repository.TxManager
+}
+
+// CreateRecurringRequest represents the data needed to create a recurring transaction
+type CreateRecurringRequest struct {
+	WalletID       *uuid.UUID
+	Amount         money.Amount
+	Type           string
+	Name           string
+	Method         string
+	Category       string
+	Notes          string
+	Frequency      string
+	Interval       int
+	DayOfMonth     int
+	StartDate      time.Time
+	EndDate        *time.Time
+	MaxOccurrences *int
+	PostAsPending  bool
+}
+
+// UpdateRecurringRequest represents the data needed to update a recurring
+// transaction. Zero values and nil pointers leave a field unchanged.
+type UpdateRecurringRequest struct {
+	WalletID       *uuid.UUID
+	Amount         money.Amount
+	Type           string
+	Name           string
+	Method         string
+	Category       string
+	Notes          string
+	Frequency      string
+	Interval       int
+	DayOfMonth     *int
+	StartDate      time.Time
+	EndDate        *time.Time
+	MaxOccurrences *int
+	PostAsPending  *bool
+	IsActive       *bool
+}
+
+// UpcomingOccurrence is a future posting of a recurring transaction
+type UpcomingOccurrence struct {
+	RecurringID uuid.UUID    `json:"recurring_id"`
+	WalletID    *uuid.UUID   `json:"wallet_id,omitempty"`
+	Name        string       `json:"name"`
+	Amount      money.Amount `json:"amount"`
+	Type        string       `json:"type"`
+	Category    string       `json:"category"`
+	Status      string       `json:"status"`
+	Date        time.Time    `json:"date"`
+}
+
+func NewRecurringTransactionService(
+	recurringRepo repository.RecurringTransactionRepository,
+	transactionRepo repository.TransactionRepository,
+	walletRepo repository.WalletRepository,
+	txManager repository.TxManager,
+) RecurringTransactionService {
+	return &recurringTransactionService{
+		recurringRepo:   recurringRepo,
+		transactionRepo: transactionRepo,
+		walletRepo:      walletRepo,
+		txManager:       txManager,
+	}
+}
+
+// CreateRecurring creates a new recurring transaction. Occurrences dated before
+// today are not posted.
+func (s *recurringTransactionService) CreateRecurring(userID uuid.UUID, req CreateRecurringRequest) (*models.RecurringTransaction, error) {
+	txnType := req.Type
+	if txnType == "" {
+		txnType = models.TransactionTypeExpense
+	}
+
+	interval := req.Interval
+	if interval == 0 {
+		interval = 1
+	}
+
+	recurring := &models.RecurringTransaction{
+		UserID:         userID,
+		WalletID:       req.WalletID,
+		Amount:         req.Amount,
+		Type:           txnType,
+		Name:           req.Name,
+		Method:         req.Method,
+		Category:       req.Category,
+		Notes:          req.Notes,
+		Frequency:      req.Frequency,
+		Interval:       interval,
+		DayOfMonth:     req.DayOfMonth,
+		StartDate:      req.StartDate,
+		EndDate:        req.EndDate,
+		MaxOccurrences: req.MaxOccurrences,
+		PostAsPending:  req.PostAsPending,
+		IsActive:       true,
+	}
+
+	if err := s.validate(recurring); err != nil {
+		return nil, err
+	}
+
+	recurring.ScheduleFrom(s.scheduleStart(recurring))
+
+	if err := s.recurringRepo.Create(recurring); err != nil {
+		return nil, err
+	}
+
+	return recurring, nil
+}
+
+// GetUserRecurring retrieves all recurring transactions for a user
+func (s *recurringTransactionService) GetUserRecurring(userID uuid.UUID) ([]*models.RecurringTransaction, error) {
+	return s.recurringRepo.FindByUserID(userID)
+}
+
+// GetRecurringByID retrieves a specific recurring transaction
+func (s *recurringTransactionService) GetRecurringByID(id, userID uuid.UUID) (*models.RecurringTransaction, error) {
+	recurring, err := s.recurringRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("recurring transaction not found")
+	}
+
+	// Verify recurring transaction belongs to user
+	if recurring.UserID != userID {
+		return nil, errors.New("unauthorized access to recurring transaction")
+	}
+
+	return recurring, nil
+}
+
+// UpdateRecurring updates a recurring transaction. Changes to the schedule, or
+// resuming it, apply from today and never repost past occurrences. The row is
+// locked while it is changed so an edit racing the scheduler keeps the
+// occurrences the scheduler posted.
+func (s *recurringTransactionService) UpdateRecurring(id, userID uuid.UUID, req UpdateRecurringRequest) (*models.RecurringTransaction, error) {
+	var recurring *models.RecurringTransaction
+	err := s.txManager.WithinTransaction(func(tx *gorm.DB) error {
+		recurringRepo := s.recurringRepo.WithTx(tx)
+
+		var err error
+		recurring, err = recurringRepo.FindByIDForUpdate(id)
+		if err != nil {
+			return errors.New("recurring transaction not found")
+		}
+
+		// Verify recurring transaction belongs to user
+		if recurring.UserID != userID {
+			return errors.New("unauthorized access to recurring transaction")
+		}
+
+		return s.applyUpdate(recurringRepo, recurring, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return recurring, nil
+}
+
+// applyUpdate applies the requested changes to a locked recurring transaction and saves it
+func (s *recurringTransactionService) applyUpdate(recurringRepo repository.RecurringTransactionRepository, recurring *models.RecurringTransaction, req UpdateRecurringRequest) error {
+
+	// Update fields if provided
+	if req.WalletID != nil {
+		recurring.WalletID = req.WalletID
+	}
+	if req.Amount > 0 {
+		recurring.Amount = req.Amount
+	}
+	if req.Type != "" {
+		recurring.Type = req.Type
+	}
+	if req.Name != "" {
+		recurring.Name = req.Name
+	}
+	if req.Method != "" {
+		recurring.Method = req.Method
+	}
+	if req.Category != "" {
+		recurring.Category = req.Category
+	}
+	if req.Notes != "" {
+		recurring.Notes = req.Notes
+	}
+	if req.PostAsPending != nil {
+		recurring.PostAsPending = *req.PostAsPending
+	}
+
+	reschedule := false
+	if req.Frequency != "" {
+		recurring.Frequency = req.Frequency
+		reschedule = true
+	}
+	if req.Interval != 0 {
+		recurring.Interval = req.Interval
+		reschedule = true
+	}
+	if req.DayOfMonth != nil {
+		recurring.DayOfMonth = *req.DayOfMonth
+		reschedule = true
+	}
+	if !req.StartDate.IsZero() {
+		recurring.StartDate = req.StartDate
+		reschedule = true
+	}
+	if req.EndDate != nil {
+		recurring.EndDate = req.EndDate
+		reschedule = true
+	}
+	if req.MaxOccurrences != nil {
+		recurring.MaxOccurrences = req.MaxOccurrences
+		reschedule = true
+	}
+	if req.IsActive != nil {
+		reschedule = reschedule || *req.IsActive && !recurring.IsActive
+		recurring.IsActive = *req.IsActive
+	}
+
+	if err := s.validate(recurring); err != nil {
+		return err
+	}
+
+	if reschedule {
+		from := s.scheduleStart(recurring)
+		// Continue after the last posting so no date is posted twice
+		if recurring.LastOccurrence != nil && !recurring.LastOccurrence.Before(from) {
+			from = recurring.LastOccurrence.Add(time.Nanosecond)
+		}
+		recurring.ScheduleFrom(from)
+	}
+
+	return recurringRepo.Update(recurring)
+}
+
+// DeleteRecurring deletes a recurring transaction. Transactions it already
+// posted are kept.
+func (s *recurringTransactionService) DeleteRecurring(id, userID uuid.UUID) error {
+	if _, err := s.GetRecurringByID(id, userID); err != nil {
+		return err
+	}
+
+	return s.recurringRepo.Delete(id)
+}
+
+// PreviewOccurrences lists the next dates a recurring transaction will post on
+func (s *recurringTransactionService) PreviewOccurrences(id, userID uuid.UUID, limit int) ([]time.Time, error) {
+	recurring, err := s.GetRecurringByID(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	dates := []time.Time{}
+	if !recurring.IsActive || recurring.NextOccurrence == nil {
+		return dates, nil
+	}
+	for n := recurring.OccurrenceCount; len(dates) < limit; n++ {
+		date, ok := recurring.Occurrence(n)
+		if !ok {
+			break
+		}
+		dates = append(dates, date)
+	}
+
+	return dates, nil
+}
+
+// GetUpcoming lists the occurrences of all the user's active recurring
+// transactions up to until, soonest first
+func (s *recurringTransactionService) GetUpcoming(userID uuid.UUID, until time.Time) ([]*UpcomingOccurrence, error) {
+	schedules, err := s.recurringRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	upcoming := []*UpcomingOccurrence{}
+	for _, recurring := range schedules {
+		if !recurring.IsActive || recurring.NextOccurrence == nil {
+			continue
+		}
+		for n := recurring.OccurrenceCount; n < recurring.OccurrenceCount+maxUpcoming; n++ {
+			date, ok := recurring.Occurrence(n)
+			if !ok || date.After(until) {
+				break
+			}
+			upcoming = append(upcoming, &UpcomingOccurrence{
+				RecurringID: recurring.ID,
+				WalletID:    recurring.WalletID,
+				Name:        recurring.Name,
+				Amount:      recurring.Amount,
+				Type:        recurring.Type,
+				Category:    recurring.Category,
+				Status:      occurrenceStatus(recurring),
+				Date:        date,
+			})
+		}
+	}
+
+	sort.SliceStable(upcoming, func(i, j int) bool {
+		return upcoming[i].Date.Before(upcoming[j].Date)
+	})
+	if len(upcoming) > maxUpcoming {
+		upcoming = upcoming[:maxUpcoming]
+	}
+
+	return upcoming, nil
+}
+
+// PostDueOccurrences creates the transactions of every occurrence due by now
+// and returns how many were posted. Each schedule is posted in its own
+// database transaction that also advances it, so a restart or a concurrent
+// run never posts an occurrence twice.
+func (s *recurringTransactionService) PostDueOccurrences(now time.Time) (int, error) {
+	due, err := s.recurringRepo.FindDue(now, dueBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	posted := 0
+	var errs []error
+	for _, recurring := range due {
+		n, err := s.postOccurrences(recurring.ID, now)
+		posted += n
+		if err != nil {
+			errs = append(errs, fmt.Errorf("recurring transaction %s: %w", recurring.ID, err))
+		}
+	}
+
+	return posted, errors.Join(errs...)
+}
+
+// postOccurrences posts the due occurrences of one recurring transaction
+func (s *recurringTransactionService) postOccurrences(id uuid.UUID, now time.Time) (int, error) {
+	posted := 0
+	err := s.txManager.WithinTransaction(func(tx *gorm.DB) error {
+		recurringRepo := s.recurringRepo.WithTx(tx)
+		walletRepo := s.walletRepo.WithTx(tx)
+		transactionRepo := s.transactionRepo.WithTx(tx)
+
+		recurring, err := recurringRepo.FindByIDForUpdate(id)
+		if err != nil {
+			return err
+		}
+
+		// Another run may have posted these occurrences since they were found
+		if !recurring.IsActive || recurring.NextOccurrence == nil || recurring.NextOccurrence.After(now) {
+			return nil
+		}
+
+		var wallet *models.Wallet
+		if recurring.WalletID != nil {
+			wallet, err = walletRepo.FindByID(*recurring.WalletID)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				// The wallet was deleted, so pause the schedule until it is moved
+				recurring.IsActive = false
+				return recurringRepo.Update(recurring)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		for posted < maxPostsPerRun && recurring.NextOccurrence != nil && !recurring.NextOccurrence.After(now) {
+			transaction := occurrenceTransaction(recurring, wallet, *recurring.NextOccurrence)
+			if err := transactionRepo.Create(transaction); err != nil {
+				return err
+			}
+			if err := applyBalanceChanges(walletRepo, nil, transaction); err != nil {
+				return err
+			}
+			recurring.Advance()
+			posted++
+		}
+
+		return recurringRepo.Update(recurring)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return posted, nil
+}
+
+// validate checks a recurring transaction's fields and schedule, and that its
+// wallet belongs to the user
+func (s *recurringTransactionService) validate(recurring *models.RecurringTransaction) error {
+	if recurring.Type != models.TransactionTypeIncome && recurring.Type != models.TransactionTypeExpense {
+		return errors.New("recurring transactions must be income or expense")
+	}
+
+	switch recurring.Frequency {
+	case models.FrequencyDaily, models.FrequencyWeekly:
+		if recurring.DayOfMonth != 0 {
+			return errors.New("day_of_month only applies to monthly and yearly schedules")
+		}
+	case models.FrequencyMonthly, models.FrequencyYearly:
+		if recurring.DayOfMonth < models.LastDayOfMonth || recurring.DayOfMonth > 31 {
+			return errors.New("day_of_month must be between 1 and 31, or -1 for the last day")
+		}
+	default:
+		return errors.New("frequency must be daily, weekly, monthly or yearly")
+	}
+	if recurring.Interval < 1 {
+		return errors.New("interval must be at least 1")
+	}
+	if recurring.StartDate.IsZero() {
+		return errors.New("start_date is required")
+	}
+	if recurring.EndDate != nil && recurring.EndDate.Before(recurring.StartDate) {
+		return errors.New("end_date must not be before start_date")
+	}
+	if recurring.MaxOccurrences != nil && *recurring.MaxOccurrences < 1 {
+		return errors.New("max_occurrences must be at least 1")
+	}
+
+	amount := recurring.Amount
+	if recurring.WalletID != nil {
+		wallet, err := s.walletRepo.FindByID(*recurring.WalletID)
+		if err != nil {
+			return errors.New("wallet not found")
+		}
+		if wallet.UserID != recurring.UserID {
+			return errors.New("unauthorized access to wallet")
+		}
+
+		// Amounts are kept to the precision of the wallet's currency
+		amount = amount.Round(wallet.Currency)
+	}
+	if amount <= 0 {
+		return errors.New("amount must be greater than zero")
+	}
+
+	return nil
+}
+
+// scheduleStart is the earliest time a recurring transaction may next post on,
+// the later of its start date and the start of today
+func (s *recurringTransactionService) scheduleStart(recurring *models.RecurringTransaction) time.Time {
+	today := startOfDay(time.Now().In(recurring.StartDate.Location()))
+	if recurring.StartDate.After(today) {
+		return recurring.StartDate
+	}
+	return today
+}
+
+// occurrenceStatus is the status occurrences of a recurring transaction are posted with
+func occurrenceStatus(recurring *models.RecurringTransaction) string {
+	if recurring.PostAsPending {
+		return "Pending"
+	}
+	return "Completed"
+}
+
+// occurrenceTransaction builds the transaction posted for one occurrence
+func occurrenceTransaction(recurring *models.RecurringTransaction, wallet *models.Wallet, date time.Time) *models.Transaction {
+	amount := recurring.Amount
+	if wallet != nil {
+		amount = amount.Round(wallet.Currency)
+	}
+	recurringID := recurring.ID
+	return &models.Transaction{
+		UserID:          recurring.UserID,
+		WalletID:        recurring.WalletID,
+		Amount:          amount,
+		Type:            recurring.Type,
+		Name:            recurring.Name,
+		Method:          recurring.Method,
+		Category:        recurring.Category,
+		Status:          occurrenceStatus(recurring),
+		Notes:           recurring.Notes,
+		RecurringID:     &recurringID,
+		TransactionDate: date,
+	}
+}
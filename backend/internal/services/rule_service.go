@@ -0,0 +1,572 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"github.com/nyunja/fity-budget-backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// maxRuleScan bounds how many transactions one retroactive apply may cover
+const maxRuleScan = 10000
+
+// RuleService defines the interface for auto-categorization rule operations
+type RuleService interface {
+	CreateRule(userID uuid.UUID, req CreateRuleRequest) (*models.Rule, error)
+	GetUserRules(userID uuid.UUID) ([]*models.Rule, error)
+	GetRuleByID(id, userID uuid.UUID) (*models.Rule, error)
+	UpdateRule(id, userID uuid.UUID, req UpdateRuleRequest) (*models.Rule, error)
+	DeleteRule(id, userID uuid.UUID) error
+	ApplyRules(userID uuid.UUID, req ApplyRulesRequest) (*ApplyRulesResult, error)
+}
+
+type ruleService struct {
+	ruleRepo        repository.RuleRepository
+	tagRepo         repository.TagRepository
+	transactionRepo repository.TransactionRepository
+	txManager       repository.TxManager
+}
+
+// CreateRuleRequest represents the data needed to create a rule
+type CreateRuleRequest struct {
+	Name       string
+	Priority   int
+	MatchField string
+	MatchType  string
+	Pattern    string
+	MinAmount  *money.Amount
+	MaxAmount  *money.Amount
+	Method     string
+	WalletID   *uuid.UUID
+	Type       string
+	// Actions
+	SetCategory string
+	SetNotes    string
+	AddTags     []string
+}
+
+// UpdateRuleRequest represents the data needed to update a rule. Zero values
+// and nil pointers leave a field unchanged.
+type UpdateRuleRequest struct {
+	Name       string
+	Priority   *int
+	IsActive   *bool
+	MatchField string
+	MatchType  string
+	Pattern    *string
+	MinAmount  *money.Amount
+	MaxAmount  *money.Amount
+	Method     *string
+	WalletID   *uuid.UUID
+	Type       *string
+	// Actions
+	SetCategory *string
+	SetNotes    *string
+	// AddTags replaces the tags the rule adds when set
+	AddTags *[]string
+}
+
+// ApplyRulesRequest selects the transactions rules are re-applied to. EndDate
+// is exclusive.
+type ApplyRulesRequest struct {
+	StartDate time.Time
+	EndDate   time.Time
+	// DryRun reports the changes without saving them
+	DryRun bool
+}
+
+// ApplyRulesResult lists the changes a retroactive apply made, or would make
+type ApplyRulesResult struct {
+	DryRun  bool          `json:"dry_run"`
+	Scanned int           `json:"scanned"`
+	Changed int           `json:"changed"`
+	Changes []*RuleChange `json:"changes"`
+}
+
+// RuleChange is the change rules make to one transaction
+type RuleChange struct {
+	TransactionID   uuid.UUID    `json:"transaction_id"`
+	Name            string       `json:"name"`
+	TransactionDate time.Time    `json:"transaction_date"`
+	RuleIDs         []uuid.UUID  `json:"rule_ids"`
+	Category        *FieldChange `json:"category,omitempty"`
+	Notes           *FieldChange `json:"notes,omitempty"`
+	AddedTags       []string     `json:"added_tags,omitempty"`
+}
+
+// FieldChange is the old and new value of a changed field
+type FieldChange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+func NewRuleService(
+	ruleRepo repository.RuleRepository,
+	tagRepo repository.TagRepository,
+	transactionRepo repository.TransactionRepository,
+	txManager repository.TxManager,
+) RuleService {
+	return &ruleService{
+		ruleRepo:        ruleRepo,
+		tagRepo:         tagRepo,
+		transactionRepo: transactionRepo,
+		txManager:       txManager,
+	}
+}
+
+// CreateRule creates a new active rule
+func (s *ruleService) CreateRule(userID uuid.UUID, req CreateRuleRequest) (*models.Rule, error) {
+	matchField := req.MatchField
+	if matchField == "" {
+		matchField = models.RuleFieldName
+	}
+	matchType := req.MatchType
+	if matchType == "" {
+		matchType = models.RuleMatchContains
+	}
+
+	rule := &models.Rule{
+		UserID:      userID,
+		Name:        strings.TrimSpace(req.Name),
+		Priority:    req.Priority,
+		IsActive:    true,
+		MatchField:  matchField,
+		MatchType:   matchType,
+		Pattern:     strings.TrimSpace(req.Pattern),
+		MinAmount:   req.MinAmount,
+		MaxAmount:   req.MaxAmount,
+		Method:      strings.TrimSpace(req.Method),
+		WalletID:    req.WalletID,
+		Type:        req.Type,
+		SetCategory: strings.TrimSpace(req.SetCategory),
+		SetNotes:    req.SetNotes,
+	}
+
+	err := s.txManager.WithinTransaction(func(tx *gorm.DB) error {
+		tags, err := s.tagRepo.WithTx(tx).FindOrCreate(userID, req.AddTags)
+		if err != nil {
+			return err
+		}
+		rule.AddTags = tags
+
+		if err := validateRule(rule); err != nil {
+			return err
+		}
+		return s.ruleRepo.WithTx(tx).Create(rule)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+// GetUserRules retrieves all rules for a user in the order they run
+func (s *ruleService) GetUserRules(userID uuid.UUID) ([]*models.Rule, error) {
+	return s.ruleRepo.FindByUserID(userID)
+}
+
+// GetRuleByID retrieves a specific rule
+func (s *ruleService) GetRuleByID(id, userID uuid.UUID) (*models.Rule, error) {
+	rule, err := s.ruleRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("rule not found")
+	}
+
+	// Verify rule belongs to user
+	if rule.UserID != userID {
+		return nil, errors.New("unauthorized access to rule")
+	}
+
+	return rule, nil
+}
+
+// UpdateRule updates a rule's conditions and actions
+func (s *ruleService) UpdateRule(id, userID uuid.UUID, req UpdateRuleRequest) (*models.Rule, error) {
+	rule, err := s.GetRuleByID(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update fields if provided
+	if req.Name != "" {
+		rule.Name = strings.TrimSpace(req.Name)
+	}
+	if req.Priority != nil {
+		rule.Priority = *req.Priority
+	}
+	if req.IsActive != nil {
+		rule.IsActive = *req.IsActive
+	}
+	if req.MatchField != "" {
+		rule.MatchField = req.MatchField
+	}
+	if req.MatchType != "" {
+		rule.MatchType = req.MatchType
+	}
+	if req.Pattern != nil {
+		rule.Pattern = strings.TrimSpace(*req.Pattern)
+	}
+	if req.MinAmount != nil {
+		rule.MinAmount = req.MinAmount
+	}
+	if req.MaxAmount != nil {
+		rule.MaxAmount = req.MaxAmount
+	}
+	if req.Method != nil {
+		rule.Method = strings.TrimSpace(*req.Method)
+	}
+	if req.WalletID != nil {
+		rule.WalletID = req.WalletID
+	}
+	if req.Type != nil {
+		rule.Type = *req.Type
+	}
+	if req.SetCategory != nil {
+		rule.SetCategory = strings.TrimSpace(*req.SetCategory)
+	}
+	if req.SetNotes != nil {
+		rule.SetNotes = *req.SetNotes
+	}
+
+	err = s.txManager.WithinTransaction(func(tx *gorm.DB) error {
+		if req.AddTags != nil {
+			tags, err := s.tagRepo.WithTx(tx).FindOrCreate(userID, *req.AddTags)
+			if err != nil {
+				return err
+			}
+			rule.AddTags = tags
+		}
+
+		if err := validateRule(rule); err != nil {
+			return err
+		}
+		return s.ruleRepo.WithTx(tx).Update(rule)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+// DeleteRule deletes a rule. Changes it already made to transactions are kept.
+func (s *ruleService) DeleteRule(id, userID uuid.UUID) error {
+	if _, err := s.GetRuleByID(id, userID); err != nil {
+		return err
+	}
+
+	return s.ruleRepo.Delete(id)
+}
+
+// ApplyRules re-applies the user's active rules to the transactions dated in
+// the requested range. Unlike rules run on new transactions, they overwrite
+// the category and notes; the category of a split transaction is kept.
+func (s *ruleService) ApplyRules(userID uuid.UUID, req ApplyRulesRequest) (*ApplyRulesResult, error) {
+	if req.StartDate.IsZero() || req.EndDate.IsZero() {
+		return nil, errors.New("start and end dates are required")
+	}
+	if !req.StartDate.Before(req.EndDate) {
+		return nil, errors.New("start date must be before end date")
+	}
+
+	rules, err := loadRuleSet(s.ruleRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := repository.TransactionFilter{
+		UserID:    userID,
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+	}
+	count, err := s.transactionRepo.CountByFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	if count > maxRuleScan {
+		return nil, fmt.Errorf("range covers %d transactions, more than %d; narrow the date range", count, maxRuleScan)
+	}
+
+	result := &ApplyRulesResult{DryRun: req.DryRun, Changes: []*RuleChange{}}
+	if count == 0 || len(rules) == 0 {
+		return result, nil
+	}
+
+	transactions, err := s.transactionRepo.FindByFilter(filter, repository.TransactionSort{Field: repository.SortByDate, Ascending: true}, int(count), 0)
+	if err != nil {
+		return nil, err
+	}
+	result.Scanned = len(transactions)
+
+	type pending struct {
+		transactionID uuid.UUID
+		columns       map[string]interface{}
+		tags          []models.Tag
+	}
+	var updates []pending
+	for _, transaction := range transactions {
+		outcome := rules.evaluate(transaction)
+		if outcome == nil {
+			continue
+		}
+
+		change := &RuleChange{
+			TransactionID:   transaction.ID,
+			Name:            transaction.Name,
+			TransactionDate: transaction.TransactionDate,
+			RuleIDs:         outcome.ruleIDs,
+		}
+		if outcome.category != "" && len(transaction.Splits) == 0 && outcome.category != transaction.Category {
+			change.Category = &FieldChange{From: transaction.Category, To: outcome.category}
+			transaction.Category = outcome.category
+		}
+		if outcome.notes != "" && outcome.notes != transaction.Notes {
+			change.Notes = &FieldChange{From: transaction.Notes, To: outcome.notes}
+			transaction.Notes = outcome.notes
+		}
+		added := missingTags(transaction.Tags, outcome.tags)
+		for _, tag := range added {
+			change.AddedTags = append(change.AddedTags, tag.Name)
+		}
+
+		if change.Category == nil && change.Notes == nil && len(added) == 0 {
+			continue
+		}
+		// Only the columns a rule changed are written, so an edit made to the
+		// transaction since it was read is kept
+		columns := map[string]interface{}{}
+		if change.Category != nil {
+			columns["category"] = change.Category.To
+		}
+		if change.Notes != nil {
+			columns["notes"] = change.Notes.To
+		}
+		result.Changes = append(result.Changes, change)
+		updates = append(updates, pending{transactionID: transaction.ID, columns: columns, tags: added})
+	}
+	result.Changed = len(result.Changes)
+
+	if req.DryRun || len(updates) == 0 {
+		return result, nil
+	}
+
+	err = s.txManager.WithinTransaction(func(tx *gorm.DB) error {
+		transactionRepo := s.transactionRepo.WithTx(tx)
+		for _, update := range updates {
+			if len(update.columns) > 0 {
+				if err := transactionRepo.UpdateColumns(update.transactionID, update.columns); err != nil {
+					return err
+				}
+			}
+			if err := transactionRepo.AddTags(update.transactionID, update.tags); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// validateRule checks that a rule has at least one condition and one action
+// and that its pattern can be matched
+func validateRule(rule *models.Rule) error {
+	if rule.Name == "" {
+		return errors.New("name is required")
+	}
+	switch rule.MatchField {
+	case models.RuleFieldName, models.RuleFieldNotes, models.RuleFieldAny:
+	default:
+		return errors.New("match field must be name, notes or any")
+	}
+	switch rule.MatchType {
+	case models.RuleMatchContains:
+	case models.RuleMatchRegex:
+		if _, err := compileRulePattern(rule.Pattern); err != nil {
+			return errors.New("invalid pattern: " + err.Error())
+		}
+	default:
+		return errors.New("match type must be contains or regex")
+	}
+	if rule.Type != "" && rule.Type != models.TransactionTypeIncome && rule.Type != models.TransactionTypeExpense {
+		return errors.New("type must be income or expense")
+	}
+	if rule.MinAmount != nil && *rule.MinAmount < 0 || rule.MaxAmount != nil && *rule.MaxAmount < 0 {
+		return errors.New("amounts cannot be negative")
+	}
+	if rule.MinAmount != nil && rule.MaxAmount != nil && *rule.MinAmount > *rule.MaxAmount {
+		return errors.New("min amount cannot exceed max amount")
+	}
+
+	if rule.Pattern == "" && rule.MinAmount == nil && rule.MaxAmount == nil &&
+		rule.Method == "" && rule.WalletID == nil && rule.Type == "" {
+		return errors.New("rule needs at least one condition")
+	}
+	if rule.SetCategory == "" && rule.SetNotes == "" && len(rule.AddTags) == 0 {
+		return errors.New("rule needs at least one action")
+	}
+	return nil
+}
+
+// compileRulePattern compiles a regex pattern that ignores case
+func compileRulePattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("(?i)" + pattern)
+}
+
+// compiledRule is a rule ready to be matched against transactions
+type compiledRule struct {
+	rule    *models.Rule
+	pattern *regexp.Regexp
+}
+
+// ruleSet is a user's active rules in priority order
+type ruleSet []compiledRule
+
+// ruleOutcome is what a rule set does to one transaction. The first matching
+// rule with a category or notes sets it; tags from every matching rule add up.
+type ruleOutcome struct {
+	ruleIDs  []uuid.UUID
+	category string
+	notes    string
+	tags     []models.Tag
+}
+
+// loadRuleSet loads a user's active rules. Rules whose pattern no longer
+// compiles are skipped.
+func loadRuleSet(ruleRepo repository.RuleRepository, userID uuid.UUID) (ruleSet, error) {
+	rules, err := ruleRepo.FindActiveByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	set := make(ruleSet, 0, len(rules))
+	for _, rule := range rules {
+		compiled := compiledRule{rule: rule}
+		if rule.MatchType == models.RuleMatchRegex && rule.Pattern != "" {
+			if compiled.pattern, err = compileRulePattern(rule.Pattern); err != nil {
+				continue
+			}
+		}
+		set = append(set, compiled)
+	}
+	return set, nil
+}
+
+// evaluate runs the rules against a transaction. It returns nil when no rule
+// matches; transfers are never matched.
+func (rs ruleSet) evaluate(transaction *models.Transaction) *ruleOutcome {
+	if transaction.Type == models.TransactionTypeTransfer {
+		return nil
+	}
+
+	var outcome *ruleOutcome
+	for _, compiled := range rs {
+		if !compiled.matches(transaction) {
+			continue
+		}
+		if outcome == nil {
+			outcome = &ruleOutcome{}
+		}
+		rule := compiled.rule
+		outcome.ruleIDs = append(outcome.ruleIDs, rule.ID)
+		if outcome.category == "" {
+			outcome.category = rule.SetCategory
+		}
+		if outcome.notes == "" {
+			outcome.notes = rule.SetNotes
+		}
+		outcome.tags = append(outcome.tags, missingTags(outcome.tags, rule.AddTags)...)
+	}
+	return outcome
+}
+
+// fill applies the rules to a new transaction. Only an empty category or
+// empty notes are set, and never the category of a split transaction.
+func (rs ruleSet) fill(transaction *models.Transaction) {
+	outcome := rs.evaluate(transaction)
+	if outcome == nil {
+		return
+	}
+	if transaction.Category == "" && len(transaction.Splits) == 0 {
+		transaction.Category = outcome.category
+	}
+	if transaction.Notes == "" {
+		transaction.Notes = outcome.notes
+	}
+	transaction.Tags = append(transaction.Tags, missingTags(transaction.Tags, outcome.tags)...)
+}
+
+// matches reports whether a transaction meets every condition of the rule
+func (c compiledRule) matches(transaction *models.Transaction) bool {
+	rule := c.rule
+	if rule.Type != "" && rule.Type != transaction.Type {
+		return false
+	}
+	if rule.WalletID != nil && (transaction.WalletID == nil || *transaction.WalletID != *rule.WalletID) {
+		return false
+	}
+	if rule.Method != "" && !strings.EqualFold(rule.Method, transaction.Method) {
+		return false
+	}
+	if rule.MinAmount != nil && transaction.Amount < *rule.MinAmount {
+		return false
+	}
+	if rule.MaxAmount != nil && transaction.Amount > *rule.MaxAmount {
+		return false
+	}
+	if rule.Pattern == "" {
+		return true
+	}
+
+	var texts []string
+	switch rule.MatchField {
+	case models.RuleFieldNotes:
+		texts = []string{transaction.Notes}
+	case models.RuleFieldAny:
+		texts = []string{transaction.Name, transaction.Notes}
+	default:
+		texts = []string{transaction.Name}
+	}
+	for _, text := range texts {
+		if c.pattern != nil && c.pattern.MatchString(text) ||
+			c.pattern == nil && strings.Contains(strings.ToLower(text), strings.ToLower(rule.Pattern)) {
+			return true
+		}
+	}
+	return false
+}
+
+// missingTags returns the tags in add that are not in have
+func missingTags(have, add []models.Tag) []models.Tag {
+	var missing []models.Tag
+	for _, tag := range add {
+		found := false
+		for _, existing := range have {
+			if existing.ID == tag.ID {
+				found = true
+				break
+			}
+		}
+		for _, existing := range missing {
+			if existing.ID == tag.ID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, tag)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// TagService defines the interface for tag operations
+type TagService interface {
+	CreateTag(userID uuid.UUID, req CreateTagRequest) (*models.Tag, error)
+	GetUserTags(userID uuid.UUID) ([]*models.Tag, error)
+	GetTagByID(id, userID uuid.UUID) (*models.Tag, error)
+	UpdateTag(id, userID uuid.UUID, req UpdateTagRequest) (*models.Tag, error)
+	DeleteTag(id, userID uuid.UUID) error
+}
+
+type tagService struct {
+	tagRepo   repository.TagRepository
+	txManager repository.TxManager
+}
+
+// CreateTagRequest represents the data needed to create a tag
+type CreateTagRequest struct {
+	Name  string
+	Color string
+}
+
+// UpdateTagRequest represents the data needed to update a tag. Empty values
+// leave a field unchanged.
+type UpdateTagRequest struct {
+	Name  string
+	Color string
+}
+
+func NewTagService(tagRepo repository.TagRepository, txManager repository.TxManager) TagService {
+	return &tagService{
+		tagRepo:   tagRepo,
+		txManager: txManager,
+	}
+}
+
+// CreateTag creates a new tag. Tag names are unique per user regardless of case.
+func (s *tagService) CreateTag(userID uuid.UUID, req CreateTagRequest) (*models.Tag, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+	if existing, _ := s.tagRepo.FindByUserIDAndName(userID, name); existing != nil {
+		return nil, errors.New("tag already exists")
+	}
+
+	tag := &models.Tag{
+		UserID: userID,
+		Name:   name,
+		Color:  req.Color,
+	}
+
+	if err := s.tagRepo.Create(tag); err != nil {
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+// GetUserTags retrieves all tags for a user ordered by name
+func (s *tagService) GetUserTags(userID uuid.UUID) ([]*models.Tag, error) {
+	return s.tagRepo.FindByUserID(userID)
+}
+
+// GetTagByID retrieves a specific tag
+func (s *tagService) GetTagByID(id, userID uuid.UUID) (*models.Tag, error) {
+	tag, err := s.tagRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("tag not found")
+	}
+
+	// Verify tag belongs to user
+	if tag.UserID != userID {
+		return nil, errors.New("unauthorized access to tag")
+	}
+
+	return tag, nil
+}
+
+// UpdateTag renames or recolors a tag. Transactions and rules refer to tags by
+// ID, so they follow a rename.
+func (s *tagService) UpdateTag(id, userID uuid.UUID, req UpdateTagRequest) (*models.Tag, error) {
+	tag, err := s.GetTagByID(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update fields if provided
+	if name := strings.TrimSpace(req.Name); name != "" && name != tag.Name {
+		existing, _ := s.tagRepo.FindByUserIDAndName(userID, name)
+		if existing != nil && existing.ID != tag.ID {
+			return nil, errors.New("tag already exists")
+		}
+		tag.Name = name
+	}
+	if req.Color != "" {
+		tag.Color = req.Color
+	}
+
+	if err := s.tagRepo.Update(tag); err != nil {
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+// DeleteTag deletes a tag and removes it from the transactions and rules
+// that carry it
+func (s *tagService) DeleteTag(id, userID uuid.UUID) error {
+	if _, err := s.GetTagByID(id, userID); err != nil {
+		return err
+	}
+
+	return s.txManager.WithinTransaction(func(tx *gorm.DB) error {
+		return s.tagRepo.WithTx(tx).Delete(id)
+	})
+}
@@ -1,12 +1,19 @@
 package services
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
 	"github.com/nyunja/fity-budget-backend/internal/repository"
+	"gorm.io/gorm"
 )
 
 // TransactionService defines the interface for transaction operations
@@ -14,6 +21,8 @@ type TransactionService interface {
 	CreateTransaction(userID uuid.UUID, req CreateTransactionRequest) (*models.Transaction, error)
 	GetUserTransactions(userID uuid.UUID, limit, offset int) ([]*models.Transaction, error)
 	GetUserTransactionsWithCount(userID uuid.UUID, limit, offset int) ([]*models.Transaction, int64, error)
+	ListTransactions(userID uuid.UUID, query TransactionListQuery) ([]*models.Transaction, int64, error)
+	ListTransactionsByCursor(userID uuid.UUID, query TransactionListQuery) (*TransactionPage, error)
 	GetTransactionByID(id, userID uuid.UUID) (*models.Transaction, error)
 	UpdateTransaction(id, userID uuid.UUID, req UpdateTransactionRequest) (*models.Transaction, error)
 	DeleteTransaction(id, userID uuid.UUID) error
@@ -23,59 +32,173 @@ type TransactionService interface {
 type transactionService struct {
 	transactionRepo repository.TransactionRepository
 	walletRepo      repository.WalletRepository
+	userRepo        repository.UserRepository
+	rateRepo        repository.ExchangeRateRepository
+	ruleRepo        repository.RuleRepository
+	tagRepo         repository.TagRepository
+	txManager       repository.TxManager
 }
 
 // CreateTransactionRequest represents the data needed to create a transaction
 type CreateTransactionRequest struct {
-	WalletID        *uuid.UUID `json:"wallet_id"`
-	Amount          float64    `json:"amount" binding:"required,gt=0"`
-	Name            string     `json:"name" binding:"required"`
-	Method          string     `json:"method" binding:"required"`
-	Category        string     `json:"category" binding:"required"`
-	Status          string     `json:"status" binding:"omitempty,oneof=Completed Pending Failed"`
-	Notes           string     `json:"notes"`
-	ReceiptURL      string     `json:"receipt_url"`
-	TransactionDate time.Time  `json:"transaction_date"`
+	WalletID        *uuid.UUID   `json:"wallet_id"`
+	Amount          money.Amount `json:"amount" binding:"required,gt=0"`
+	Type            string       `json:"type" binding:"omitempty,oneof=income expense"`
+	Name            string       `json:"name" binding:"required"`
+	Method          string       `json:"method" binding:"required"`
+	Category        string       `json:"category"`
+	Status          string       `json:"status" binding:"omitempty,oneof=Completed Pending Failed"`
+	Notes           string       `json:"notes"`
+	ReceiptURL      string       `json:"receipt_url"`
+	TransactionDate time.Time    `json:"transaction_date"`
+	// Splits optionally divide the amount between categories
+	Splits []TransactionSplitRequest `json:"splits"`
+	// Tags names the tags to attach; missing tags are created
+	Tags []string `json:"tags"`
 }
 
 // UpdateTransactionRequest represents the data needed to update a transaction
 type UpdateTransactionRequest struct {
-	Amount          float64    `json:"amount" binding:"omitempty,gt=0"`
-	Name            string     `json:"name"`
-	Method          string     `json:"method"`
-	Category        string     `json:"category"`
-	Status          string     `json:"status" binding:"omitempty,oneof=Completed Pending Failed"`
-	Notes           string     `json:"notes"`
-	ReceiptURL      string     `json:"receipt_url"`
-	TransactionDate time.Time  `json:"transaction_date"`
+	WalletID        *uuid.UUID   `json:"wallet_id"`
+	Amount          money.Amount `json:"amount" binding:"omitempty,gt=0"`
+	Type            string       `json:"type" binding:"omitempty,oneof=income expense"`
+	Name            string       `json:"name"`
+	Method          string       `json:"method"`
+	Category        string       `json:"category"`
+	Status          string       `json:"status" binding:"omitempty,oneof=Completed Pending Failed"`
+	Notes           string       `json:"notes"`
+	ReceiptURL      string       `json:"receipt_url"`
+	TransactionDate time.Time    `json:"transaction_date"`
+	// Splits replaces the split lines when set; an empty list removes them
+	Splits *[]TransactionSplitRequest `json:"splits"`
+	// Tags replaces the tags when set; an empty list removes them
+	Tags *[]string `json:"tags"`
+}
+
+// TransactionSplitRequest is one split line of a transaction
+type TransactionSplitRequest struct {
+	Category string       `json:"category" binding:"required"`
+	Amount   money.Amount `json:"amount" binding:"required,gt=0"`
+	Note     string       `json:"note"`
+}
+
+// Categories given to transactions that have none and that no rule categorizes
+const (
+	defaultIncomeCategory  = "Income"
+	defaultExpenseCategory = "Uncategorized"
+)
+
+// defaultCategory returns the category for an uncategorized transaction of a type
+func defaultCategory(txnType string) string {
+	if txnType == models.TransactionTypeIncome {
+		return defaultIncomeCategory
+	}
+	return defaultExpenseCategory
+}
+
+// maxSplits bounds the number of split lines on one transaction
+const maxSplits = 50
+
+// ErrInvalidTransactionQuery is returned when transaction list filters or
+// sorting are invalid
+var ErrInvalidTransactionQuery = errors.New("invalid transaction query")
+
+// maxSearchLength bounds the free-text search term
+const maxSearchLength = 100
+
+// TransactionListQuery filters, sorts and pages a user's transactions. Zero
+// values leave a filter unset. EndDate is exclusive. Offset is used by page
+// listings and Cursor by cursor listings.
+type TransactionListQuery struct {
+	StartDate  time.Time
+	EndDate    time.Time
+	Categories []string
+	WalletIDs  []uuid.UUID
+	Statuses   []string
+	Types      []string
+	Methods    []string
+	MinAmount  *money.Amount
+	MaxAmount  *money.Amount
+	TagIDs     []uuid.UUID
+	Search     string
+	SortBy     string
+	SortOrder  string
+	Limit      int
+	Offset     int
+	Cursor     string
+}
+
+// TransactionPage is one page of a cursor-paginated transaction listing. The
+// cursors are opaque and empty when there is nothing further in that direction.
+type TransactionPage struct {
+	Transactions []*models.Transaction `json:"data"`
+	NextCursor   string                `json:"next_cursor"`
+	PrevCursor   string                `json:"prev_cursor"`
+}
+
+// transactionCursor is the decoded form of a page cursor
+type transactionCursor struct {
+	TransactionDate time.Time `json:"d"`
+	ID              uuid.UUID `json:"id"`
+	Ascending       bool      `json:"a,omitempty"`
+	Backward        bool      `json:"b,omitempty"`
 }
 
 // TransactionStats represents aggregated transaction statistics
 type TransactionStats struct {
-	TotalIncome      float64 `json:"total_income"`
-	TotalExpense     float64 `json:"total_expense"`
-	NetBalance       float64 `json:"net_balance"`
-	TransactionCount int     `json:"transaction_count"`
+	TotalIncome           money.Amount   `json:"total_income"`
+	TotalExpense          money.Amount   `json:"total_expense"`
+	TotalTransfers        money.Amount   `json:"total_transfers"`
+	NetBalance            money.Amount   `json:"net_balance"`
+	TransactionCount      int            `json:"transaction_count"`
+	Currency              string         `json:"currency"`
+	ExchangeRates         []*AppliedRate `json:"exchange_rates,omitempty"`
+	UnconvertedCurrencies []string       `json:"unconverted_currencies,omitempty"`
 }
 
-func NewTransactionService(transactionRepo repository.TransactionRepository, walletRepo repository.WalletRepository) TransactionService {
+func NewTransactionService(
+	transactionRepo repository.TransactionRepository,
+	walletRepo repository.WalletRepository,
+	userRepo repository.UserRepository,
+	rateRepo repository.ExchangeRateRepository,
+	ruleRepo repository.RuleRepository,
+	tagRepo repository.TagRepository,
+	txManager repository.TxManager,
+) TransactionService {
 	return &transactionService{
 		transactionRepo: transactionRepo,
 		walletRepo:      walletRepo,
+		userRepo:        userRepo,
+		rateRepo:        rateRepo,
+		ruleRepo:        ruleRepo,
+		tagRepo:         tagRepo,
+		txManager:       txManager,
 	}
 }
 
-// CreateTransaction creates a new transaction and updates the wallet balance
+// CreateTransaction creates a new transaction and updates the wallet balance.
+// The user's rules fill in the category and notes when they are not given and
+// add tags; a transaction no rule categorizes gets a default category.
 func (s *transactionService) CreateTransaction(userID uuid.UUID, req CreateTransactionRequest) (*models.Transaction, error) {
+	amount := req.Amount
+
 	// Verify wallet belongs to user if provided
+	var wallet *models.Wallet
 	if req.WalletID != nil {
-		wallet, err := s.walletRepo.FindByID(*req.WalletID)
+		var err error
+		wallet, err = s.walletRepo.FindByID(*req.WalletID)
 		if err != nil {
 			return nil, errors.New("wallet not found")
 		}
 		if wallet.UserID != userID {
 			return nil, errors.New("unauthorized access to wallet")
 		}
+
+		// Amounts are kept to the precision of the wallet's currency
+		amount = amount.Round(wallet.Currency)
+	}
+	if amount <= 0 {
+		return nil, errors.New("amount must be greater than zero")
 	}
 
 	// Set default status if not provided
@@ -84,37 +207,74 @@ func (s *transactionService) CreateTransaction(userID uuid.UUID, req CreateTrans
 		status = "Completed"
 	}
 
+	// Set default type if not provided
+	txnType := req.Type
+	if txnType == "" {
+		txnType = models.TransactionTypeExpense
+	}
+	if !isValidTransactionType(txnType) {
+		return nil, errors.New("invalid transaction type")
+	}
+	if txnType == models.TransactionTypeTransfer {
+		return nil, errors.New("use the wallet transfer endpoint to move money between wallets")
+	}
+
 	// Set transaction date to now if not provided
 	transactionDate := req.TransactionDate
 	if transactionDate.IsZero() {
 		transactionDate = time.Now()
 	}
 
+	splits, err := buildSplits(req.Splits, amount, wallet)
+	if err != nil {
+		return nil, err
+	}
+
+	// A split transaction is filed under its largest split unless a category is given
+	category := req.Category
+	if category == "" && len(splits) > 0 {
+		category = largestSplit(splits).Category
+	}
+
+	rules, err := loadRuleSet(s.ruleRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
 	transaction := models.Transaction{
 		UserID:          userID,
 		WalletID:        req.WalletID,
-		Amount:          req.Amount,
+		Amount:          amount,
+		Type:            txnType,
 		Name:            req.Name,
 		Method:          req.Method,
-		Category:        req.Category,
+		Category:        category,
 		Status:          status,
 		Notes:           req.Notes,
 		ReceiptURL:      req.ReceiptURL,
 		TransactionDate: transactionDate,
+		Splits:          splits,
 	}
-
-	if err := s.transactionRepo.Create(&transaction); err != nil {
-		return nil, err
+	rules.fill(&transaction)
+	if transaction.Category == "" {
+		transaction.Category = defaultCategory(txnType)
 	}
 
-	// Update wallet balance only if transaction is completed and wallet is specified
-	if status == "Completed" && req.WalletID != nil {
-		// Note: The model doesn't have a Type field to determine if income/expense
-		// This would need to be determined by the Method or another field
-		// For now, we'll comment this out until the business logic is clarified
-		// if err := s.walletRepo.UpdateBalance(*req.WalletID, balanceChange); err != nil {
-		// 	return nil, errors.New("failed to update wallet balance")
-		// }
+	// Create the transaction and move the wallet balance together
+	err = s.txManager.WithinTransaction(func(tx *gorm.DB) error {
+		tags, err := s.tagRepo.WithTx(tx).FindOrCreate(userID, req.Tags)
+		if err != nil {
+			return err
+		}
+		transaction.Tags = append(transaction.Tags, missingTags(transaction.Tags, tags)...)
+
+		if err := s.transactionRepo.WithTx(tx).Create(&transaction); err != nil {
+			return err
+		}
+		return applyBalanceChanges(s.walletRepo.WithTx(tx), nil, &transaction)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &transaction, nil
@@ -163,111 +323,500 @@ func (s *transactionService) GetUserTransactionsWithCount(userID uuid.UUID, limi
 	return transactions, total, nil
 }
 
-// GetTransactionByID retrieves a specific transaction by ID
-func (s *transactionService) GetTransactionByID(id, userID uuid.UUID) (*models.Transaction, error) {
-	transaction, err := s.transactionRepo.FindByID(id)
+// ListTransactions retrieves a filtered, sorted page of a user's transactions
+// together with the number of transactions matching the filters
+func (s *transactionService) ListTransactions(userID uuid.UUID, query TransactionListQuery) ([]*models.Transaction, int64, error) {
+	filter, order, err := query.toFilter(userID)
 	if err != nil {
-		return nil, errors.New("transaction not found")
+		return nil, 0, err
 	}
 
-	// Verify transaction belongs to user
-	if transaction.UserID != userID {
-		return nil, errors.New("unauthorized access to transaction")
+	if query.Limit <= 0 {
+		query.Limit = 50
+	}
+	if query.Offset < 0 {
+		query.Offset = 0
 	}
 
-	return transaction, nil
+	transactions, err := s.transactionRepo.FindByFilter(filter, order, query.Limit, query.Offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.transactionRepo.CountByFilter(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return transactions, total, nil
 }
 
-// UpdateTransaction updates an existing transaction
-func (s *transactionService) UpdateTransaction(id, userID uuid.UUID, req UpdateTransactionRequest) (*models.Transaction, error) {
-	transaction, err := s.transactionRepo.FindByID(id)
+// ListTransactionsByCursor retrieves the page of a user's transactions that
+// follows query.Cursor, ordered by transaction date. An empty cursor starts at
+// the first page. Keyset paging never skips or repeats transactions when new
+// ones are added between requests.
+func (s *transactionService) ListTransactionsByCursor(userID uuid.UUID, query TransactionListQuery) (*TransactionPage, error) {
+	filter, order, err := query.toFilter(userID)
 	if err != nil {
-		return nil, errors.New("transaction not found")
+		return nil, err
+	}
+	if order.Field != "" && order.Field != repository.SortByDate {
+		return nil, fmt.Errorf("%w: cursor pagination can only sort by %s", ErrInvalidTransactionQuery, repository.SortByDate)
 	}
 
-	// Verify transaction belongs to user
-	if transaction.UserID != userID {
-		return nil, errors.New("unauthorized access to transaction")
+	position := repository.TransactionCursor{Ascending: order.Ascending}
+	if query.Cursor != "" {
+		cursor, err := decodeTransactionCursor(query.Cursor)
+		if err != nil || cursor.Ascending != order.Ascending {
+			return nil, fmt.Errorf("%w: invalid cursor", ErrInvalidTransactionQuery)
+		}
+		position.TransactionDate = cursor.TransactionDate
+		position.ID = cursor.ID
+		position.Backward = cursor.Backward
 	}
 
-	// Update fields if provided
-	if req.Amount > 0 {
-		transaction.Amount = req.Amount
+	if query.Limit <= 0 {
+		query.Limit = 50
 	}
-	if req.Name != "" {
-		transaction.Name = req.Name
+
+	// Read one extra row to learn whether another page follows
+	transactions, err := s.transactionRepo.FindByCursor(filter, position, query.Limit+1)
+	if err != nil {
+		return nil, err
 	}
-	if req.Method != "" {
-		transaction.Method = req.Method
+	hasMore := len(transactions) > query.Limit
+	if hasMore {
+		if position.Backward {
+			transactions = transactions[1:]
+		} else {
+			transactions = transactions[:query.Limit]
+		}
 	}
-	if req.Category != "" {
-		transaction.Category = req.Category
+
+	page := &TransactionPage{Transactions: transactions}
+	if len(transactions) == 0 {
+		return page, nil
 	}
-	if req.Status != "" {
-		transaction.Status = req.Status
+
+	first, last := transactions[0], transactions[len(transactions)-1]
+	if position.Backward {
+		page.NextCursor = encodeTransactionCursor(last, order.Ascending, false)
+		if hasMore {
+			page.PrevCursor = encodeTransactionCursor(first, order.Ascending, true)
+		}
+	} else {
+		if hasMore {
+			page.NextCursor = encodeTransactionCursor(last, order.Ascending, false)
+		}
+		if query.Cursor != "" {
+			page.PrevCursor = encodeTransactionCursor(first, order.Ascending, true)
+		}
 	}
-	if req.Notes != "" {
-		transaction.Notes = req.Notes
+
+	return page, nil
+}
+
+// encodeTransactionCursor builds the opaque cursor for the page on either side
+// of a transaction
+func encodeTransactionCursor(transaction *models.Transaction, ascending, backward bool) string {
+	data, _ := json.Marshal(transactionCursor{
+		TransactionDate: transaction.TransactionDate,
+		ID:              transaction.ID,
+		Ascending:       ascending,
+		Backward:        backward,
+	})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeTransactionCursor reads a cursor produced by encodeTransactionCursor
+func decodeTransactionCursor(value string) (*transactionCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
 	}
-	if req.ReceiptURL != "" {
-		transaction.ReceiptURL = req.ReceiptURL
+
+	var cursor transactionCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, err
 	}
-	if !req.TransactionDate.IsZero() {
-		transaction.TransactionDate = req.TransactionDate
+	if cursor.ID == uuid.Nil || cursor.TransactionDate.IsZero() {
+		return nil, errors.New("incomplete cursor")
 	}
+	return &cursor, nil
+}
 
-	if err := s.transactionRepo.Update(transaction); err != nil {
-		return nil, err
+// toFilter validates the query and translates it into a repository filter and sort
+func (q TransactionListQuery) toFilter(userID uuid.UUID) (repository.TransactionFilter, repository.TransactionSort, error) {
+	filter := repository.TransactionFilter{
+		UserID:     userID,
+		StartDate:  q.StartDate,
+		EndDate:    q.EndDate,
+		Categories: q.Categories,
+		WalletIDs:  q.WalletIDs,
+		Statuses:   q.Statuses,
+		Types:      q.Types,
+		Methods:    q.Methods,
+		MinAmount:  q.MinAmount,
+		MaxAmount:  q.MaxAmount,
+		TagIDs:     q.TagIDs,
+		Search:     strings.TrimSpace(q.Search),
+	}
+	var order repository.TransactionSort
+
+	if !q.StartDate.IsZero() && !q.EndDate.IsZero() && !q.StartDate.Before(q.EndDate) {
+		return filter, order, fmt.Errorf("%w: start date must be before end date", ErrInvalidTransactionQuery)
+	}
+	if q.MinAmount != nil && *q.MinAmount < 0 || q.MaxAmount != nil && *q.MaxAmount < 0 {
+		return filter, order, fmt.Errorf("%w: amounts cannot be negative", ErrInvalidTransactionQuery)
+	}
+	if q.MinAmount != nil && q.MaxAmount != nil && *q.MinAmount > *q.MaxAmount {
+		return filter, order, fmt.Errorf("%w: min amount cannot exceed max amount", ErrInvalidTransactionQuery)
+	}
+	for _, status := range q.Statuses {
+		if status != "Completed" && status != "Pending" && status != "Failed" {
+			return filter, order, fmt.Errorf("%w: unknown status %q", ErrInvalidTransactionQuery, status)
+		}
+	}
+	for _, t := range q.Types {
+		if !isValidTransactionType(t) {
+			return filter, order, fmt.Errorf("%w: unknown type %q", ErrInvalidTransactionQuery, t)
+		}
+	}
+	if len(filter.Search) > maxSearchLength {
+		return filter, order, fmt.Errorf("%w: search must be at most %d characters", ErrInvalidTransactionQuery, maxSearchLength)
 	}
 
-	return transaction, nil
+	if q.SortBy != "" {
+		if !repository.IsValidSortField(q.SortBy) {
+			return filter, order, fmt.Errorf("%w: cannot sort by %q", ErrInvalidTransactionQuery, q.SortBy)
+		}
+		order.Field = q.SortBy
+	}
+	switch strings.ToLower(q.SortOrder) {
+	case "", "desc":
+	case "asc":
+		order.Ascending = true
+	default:
+		return filter, order, fmt.Errorf("%w: sort order must be asc or desc", ErrInvalidTransactionQuery)
+	}
+
+	return filter, order, nil
 }
 
-// DeleteTransaction deletes a transaction
-func (s *transactionService) DeleteTransaction(id, userID uuid.UUID) error {
+// GetTransactionByID retrieves a specific transaction by ID
+func (s *transactionService) GetTransactionByID(id, userID uuid.UUID) (*models.Transaction, error) {
 	transaction, err := s.transactionRepo.FindByID(id)
 	if err != nil {
-		return errors.New("transaction not found")
+		return nil, errors.New("transaction not found")
 	}
 
 	// Verify transaction belongs to user
 	if transaction.UserID != userID {
-		return errors.New("unauthorized access to transaction")
+		return nil, errors.New("unauthorized access to transaction")
 	}
 
-	if err := s.transactionRepo.Delete(id); err != nil {
-		return err
+	return transaction, nil
+}
+
+// UpdateTransaction updates an existing transaction. The stored row is read
+// and locked inside the database transaction so concurrent edits rebalance
+// wallets from the state they actually replace.
+func (s *transactionService) UpdateTransaction(id, userID uuid.UUID, req UpdateTransactionRequest) (*models.Transaction, error) {
+	var transaction *models.Transaction
+	err := s.txManager.WithinTransaction(func(tx *gorm.DB) error {
+		transactionRepo := s.transactionRepo.WithTx(tx)
+		walletRepo := s.walletRepo.WithTx(tx)
+
+		var err error
+		transaction, err = transactionRepo.FindByIDForUpdate(id)
+		if err != nil {
+			return errors.New("transaction not found")
+		}
+
+		// Verify transaction belongs to user
+		if transaction.UserID != userID {
+			return errors.New("unauthorized access to transaction")
+		}
+
+		// Transfer legs are owned by their transfer and only change through a reversal
+		if transaction.TransferID != nil {
+			return errors.New("transfer transactions cannot be edited; reverse the transfer instead")
+		}
+
+		// Keep a copy of the transaction as stored so its balance effect can be reversed
+		previous := *transaction
+
+		// Verify the new wallet belongs to user if the transaction is being moved
+		var wallet *models.Wallet
+		if req.WalletID != nil {
+			wallet, err = walletRepo.FindByID(*req.WalletID)
+			if err != nil {
+				return errors.New("wallet not found")
+			}
+			if wallet.UserID != userID {
+				return errors.New("unauthorized access to wallet")
+			}
+			transaction.WalletID = req.WalletID
+		}
+
+		// Update fields if provided
+		if req.Amount > 0 {
+			transaction.Amount = req.Amount
+		}
+
+		// Keep the amount to the precision of the wallet's currency
+		if transaction.WalletID != nil && (req.Amount > 0 || req.WalletID != nil) {
+			if wallet == nil {
+				wallet, err = walletRepo.FindByID(*transaction.WalletID)
+				if err != nil {
+					return errors.New("wallet not found")
+				}
+			}
+			transaction.Amount = transaction.Amount.Round(wallet.Currency)
+			if transaction.Amount <= 0 {
+				return errors.New("amount must be greater than zero")
+			}
+		}
+		if req.Type != "" {
+			if !isValidTransactionType(req.Type) {
+				return errors.New("invalid transaction type")
+			}
+			if req.Type == models.TransactionTypeTransfer {
+				return errors.New("use the wallet transfer endpoint to move money between wallets")
+			}
+			transaction.Type = req.Type
+		}
+		if req.Name != "" {
+			transaction.Name = req.Name
+		}
+		if req.Method != "" {
+			transaction.Method = req.Method
+		}
+		if req.Category != "" {
+			transaction.Category = req.Category
+		}
+		if req.Status != "" {
+			transaction.Status = req.Status
+		}
+		if req.Notes != "" {
+			transaction.Notes = req.Notes
+		}
+		if req.ReceiptURL != "" {
+			transaction.ReceiptURL = req.ReceiptURL
+		}
+		if !req.TransactionDate.IsZero() {
+			transaction.TransactionDate = req.TransactionDate
+		}
+
+		// Split lines must keep adding up to the amount
+		if req.Splits != nil {
+			if wallet == nil && transaction.WalletID != nil {
+				wallet, err = walletRepo.FindByID(*transaction.WalletID)
+				if err != nil {
+					return errors.New("wallet not found")
+				}
+			}
+			transaction.Splits, err = buildSplits(*req.Splits, transaction.Amount, wallet)
+			if err != nil {
+				return err
+			}
+		} else if len(transaction.Splits) > 0 && transaction.Amount != previous.Amount {
+			return errors.New("the transaction is split; send splits that add up to the new amount")
+		}
+
+		// Save the changes and rebalance the affected wallets together
+		if err := transactionRepo.Update(transaction); err != nil {
+			return err
+		}
+		if req.Splits != nil {
+			if err := transactionRepo.ReplaceSplits(transaction.ID, transaction.Splits); err != nil {
+				return err
+			}
+		}
+		if req.Tags != nil {
+			tags, err := s.tagRepo.WithTx(tx).FindOrCreate(userID, *req.Tags)
+			if err != nil {
+				return err
+			}
+			if err := transactionRepo.ReplaceTags(transaction.ID, tags); err != nil {
+				return err
+			}
+			transaction.Tags = tags
+		}
+		return applyBalanceChanges(walletRepo, &previous, transaction)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	return transaction, nil
+}
+
+// DeleteTransaction deletes a transaction and reverses its effect on the wallet
+// balance, as read under a row lock so a concurrent delete cannot reverse it twice
+func (s *transactionService) DeleteTransaction(id, userID uuid.UUID) error {
+	return s.txManager.WithinTransaction(func(tx *gorm.DB) error {
+		transactionRepo := s.transactionRepo.WithTx(tx)
+		transaction, err := transactionRepo.FindByIDForUpdate(id)
+		if err != nil {
+			return errors.New("transaction not found")
+		}
+
+		// Verify transaction belongs to user
+		if transaction.UserID != userID {
+			return errors.New("unauthorized access to transaction")
+		}
+
+		// Transfer legs are owned by their transfer and only change through a reversal
+		if transaction.TransferID != nil {
+			return errors.New("transfer transactions cannot be deleted; reverse the transfer instead")
+		}
+
+		if err := transactionRepo.Delete(id); err != nil {
+			return err
+		}
+		return applyBalanceChanges(s.walletRepo.WithTx(tx), transaction, nil)
+	})
 }
 
 // GetTransactionStats calculates transaction statistics for a user within a date range
 func (s *transactionService) GetTransactionStats(userID uuid.UUID, startDate, endDate time.Time) (*TransactionStats, error) {
-	// Get all transactions for the user (we'll filter in memory for simplicity)
-	// In production, you'd want to add a repository method with date filtering
-	transactions, err := s.transactionRepo.FindByUserID(userID, 10000, 0)
+	report, err := loadReportCurrency(s.userRepo, s.walletRepo, s.rateRepo, userID, nil, time.Now())
 	if err != nil {
 		return nil, err
 	}
 
-	stats := &TransactionStats{}
+	// Only completed transactions count; zero dates leave the range open
+	aggregates, err := s.transactionRepo.Aggregate(completedBetween(userID, startDate, endDate),
+		repository.GroupByType, repository.GroupByWallet)
+	if err != nil {
+		return nil, err
+	}
 
-	for _, txn := range transactions {
-		// Filter by date range and only count completed transactions
-		if txn.Status == "Completed" &&
-			(startDate.IsZero() || txn.TransactionDate.After(startDate) || txn.TransactionDate.Equal(startDate)) &&
-			(endDate.IsZero() || txn.TransactionDate.Before(endDate) || txn.TransactionDate.Equal(endDate)) {
+	stats := &TransactionStats{}
+	var totals flowTotals
 
-			stats.TransactionCount++
-			// Note: The Transaction model doesn't have a Type field
-			// Income vs Expense would need to be determined by Method or Category
-			// For now, we'll just count all as expenses
-			stats.TotalExpense += txn.Amount
+	for _, aggregate := range aggregates {
+		amount, err := report.convertAggregate(aggregate)
+		if err != nil {
+			return nil, err
 		}
+		stats.TransactionCount += int(aggregate.Count)
+		totals.add(aggregate.Type, amount)
 	}
 
+	stats.TotalIncome = totals.Income
+	stats.TotalExpense = totals.Expense
+	stats.TotalTransfers = totals.Transfers
 	stats.NetBalance = stats.TotalIncome - stats.TotalExpense
+	stats.Currency = report.base
+	stats.ExchangeRates = report.rates()
+	stats.UnconvertedCurrencies = report.unconverted()
 
 	return stats, nil
 }
+
+// isValidTransactionType reports whether t is one of the supported transaction types
+func isValidTransactionType(t string) bool {
+	switch t {
+	case models.TransactionTypeIncome, models.TransactionTypeExpense, models.TransactionTypeTransfer:
+		return true
+	}
+	return false
+}
+
+// buildSplits validates split lines against the transaction amount, rounding
+// each line to the wallet currency. No lines leave the transaction unsplit.
+func buildSplits(lines []TransactionSplitRequest, amount money.Amount, wallet *models.Wallet) ([]models.TransactionSplit, error) {
+	if len(lines) == 0 {
+		return nil, nil
+	}
+	if len(lines) < 2 {
+		return nil, errors.New("a split transaction needs at least two split lines")
+	}
+	if len(lines) > maxSplits {
+		return nil, fmt.Errorf("a transaction can have at most %d split lines", maxSplits)
+	}
+
+	splits := make([]models.TransactionSplit, 0, len(lines))
+	var total money.Amount
+	for i, line := range lines {
+		category := strings.TrimSpace(line.Category)
+		if category == "" {
+			return nil, fmt.Errorf("split line %d needs a category", i+1)
+		}
+		lineAmount := line.Amount
+		if wallet != nil {
+			lineAmount = lineAmount.Round(wallet.Currency)
+		}
+		if lineAmount <= 0 {
+			return nil, fmt.Errorf("split line %d amount must be greater than zero", i+1)
+		}
+		total += lineAmount
+		splits = append(splits, models.TransactionSplit{
+			Category: category,
+			Amount:   lineAmount,
+			Note:     line.Note,
+		})
+	}
+
+	if total != amount {
+		return nil, fmt.Errorf("split amounts add up to %s but the transaction amount is %s", total, amount)
+	}
+
+	return splits, nil
+}
+
+// largestSplit returns the split line with the largest amount, the first on ties
+func largestSplit(splits []models.TransactionSplit) models.TransactionSplit {
+	largest := splits[0]
+	for _, split := range splits[1:] {
+		if split.Amount > largest.Amount {
+			largest = split
+		}
+	}
+	return largest
+}
+
+// applyBalanceChanges moves wallet balances from the effect of the previous
+// version of a transaction to the effect of the current one. Either side may be
+// nil for creates and deletes. Amount, status, type and wallet changes are all
+// handled by reversing the old effect and applying the new one.
+func applyBalanceChanges(walletRepo repository.WalletRepository, previous, current *models.Transaction) error {
+	deltas := make(map[uuid.UUID]money.Amount)
+	addBalanceChanges(deltas, previous, current)
+	return applyBalanceDeltas(walletRepo, deltas)
+}
+
+// addBalanceChanges adds the change from previous to current to the per-wallet
+// deltas, so the changes of several transactions can be applied at once
+func addBalanceChanges(deltas map[uuid.UUID]money.Amount, previous, current *models.Transaction) {
+	if previous != nil && previous.BalanceEffect() != 0 {
+		deltas[*previous.WalletID] -= previous.BalanceEffect()
+	}
+	if current != nil && current.BalanceEffect() != 0 {
+		deltas[*current.WalletID] += current.BalanceEffect()
+	}
+}
+
+// applyBalanceDeltas adds the net change for each wallet to its balance
+func applyBalanceDeltas(walletRepo repository.WalletRepository, deltas map[uuid.UUID]money.Amount) error {
+	// Update wallets in a stable order so concurrent requests lock rows consistently
+	walletIDs := make([]uuid.UUID, 0, len(deltas))
+	for walletID, delta := range deltas {
+		if delta != 0 {
+			walletIDs = append(walletIDs, walletID)
+		}
+	}
+	sort.Slice(walletIDs, func(i, j int) bool {
+		return walletIDs[i].String() < walletIDs[j].String()
+	})
+
+	for _, walletID := range walletIDs {
+		if err := walletRepo.UpdateBalance(walletID, deltas[walletID]); err != nil {
+			return errors.New("failed to update wallet balance")
+		}
+	}
+
+	return nil
+}
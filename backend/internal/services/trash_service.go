@@ -0,0 +1,453 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Kinds of records that can sit in the trash
+const (
+	TrashTypeTransaction = "transaction"
+	TrashTypeBudget      = "budget"
+	TrashTypeGoal        = "goal"
+	TrashTypeWallet      = "wallet"
+)
+
+// trashPurgeBatch caps how many records of each kind one PurgeExpired run
+// removes; anything left over is picked up by the next run
+const trashPurgeBatch = 500
+
+// ErrUnknownTrashType is returned for a trash item type other than the TrashType constants
+var ErrUnknownTrashType = errors.New("unknown trash item type")
+
+// TrashService defines the interface for browsing, restoring and purging
+// soft-deleted records
+type TrashService interface {
+	GetTrash(userID uuid.UUID) (*Trash, error)
+	RestoreItem(itemType string, id, userID uuid.UUID) (interface{}, error)
+	PurgeItem(itemType string, id, userID uuid.UUID) error
+	PurgeExpired(now time.Time) (int, error)
+}
+
+type trashService struct {
+	transactionRepo repository.TransactionRepository
+	budgetRepo      repository.BudgetRepository
+	goalRepo        repository.GoalRepository
+	walletRepo      repository.WalletRepository
+	importJobRepo   repository.ImportJobRepository
+	receiptService  ReceiptService
+	txManager       repository.TxManager
+	retention       time.Duration
+}
+
+// Trash lists a user's deleted records by type, most recently deleted first
+type Trash struct {
+	Transactions []*TrashItem `json:"transactions"`
+	Budgets      []*TrashItem `json:"budgets"`
+	Goals        []*TrashItem `json:"goals"`
+	Wallets      []*TrashItem `json:"wallets"`
+}
+
+// TrashItem is a deleted record. PurgeAt is when the retention job will
+// remove it for good and is unset when retention is disabled.
+type TrashItem struct {
+	Type      string      `json:"type"`
+	ID        uuid.UUID   `json:"id"`
+	DeletedAt time.Time   `json:"deleted_at"`
+	PurgeAt   *time.Time  `json:"purge_at,omitempty"`
+	Item      interface{} `json:"item"`
+}
+
+// NewTrashService creates a trash service. Records deleted longer than
+// retention ago are purged by PurgeExpired; zero keeps them until they are
+// purged by hand.
+func NewTrashService(
+	transactionRepo repository.TransactionRepository,
+	budgetRepo repository.BudgetRepository,
+	goalRepo repository.GoalRepository,
+	walletRepo repository.WalletRepository,
+	importJobRepo repository.ImportJobRepository,
+	receiptService ReceiptService,
+	txManager repository.TxManager,
+	retention time.Duration,
+) TrashService {
+	return &trashService{
+		transactionRepo: transactionRepo,
+		budgetRepo:      budgetRepo,
+		goalRepo:        goalRepo,
+		walletRepo:      walletRepo,
+		importJobRepo:   importJobRepo,
+		receiptService:  receiptService,
+		txManager:       txManager,
+		retention:       retention,
+	}
+}
+
+// GetTrash lists the user's deleted transactions, budgets, goals and wallets
+func (s *trashService) GetTrash(userID uuid.UUID) (*Trash, error) {
+	trash := &Trash{
+		Transactions: []*TrashItem{},
+		Budgets:      []*TrashItem{},
+		Goals:        []*TrashItem{},
+		Wallets:      []*TrashItem{},
+	}
+
+	transactions, err := s.transactionRepo.FindDeletedByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, transaction := range transactions {
+		trash.Transactions = append(trash.Transactions, s.newItem(TrashTypeTransaction, transaction.ID, transaction.DeletedAt, transaction))
+	}
+
+	budgets, err := s.budgetRepo.FindDeletedByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, budget := range budgets {
+		trash.Budgets = append(trash.Budgets, s.newItem(TrashTypeBudget, budget.ID, budget.DeletedAt, budget))
+	}
+
+	goals, err := s.goalRepo.FindDeletedByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, goal := range goals {
+		trash.Goals = append(trash.Goals, s.newItem(TrashTypeGoal, goal.ID, goal.DeletedAt, goal))
+	}
+
+	wallets, err := s.walletRepo.FindDeletedByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, wallet := range wallets {
+		trash.Wallets = append(trash.Wallets, s.newItem(TrashTypeWallet, wallet.ID, wallet.DeletedAt, wallet))
+	}
+
+	return trash, nil
+}
+
+func (s *trashService) newItem(itemType string, id uuid.UUID, deletedAt gorm.DeletedAt, record interface{}) *TrashItem {
+	item := &TrashItem{
+		Type:      itemType,
+		ID:        id,
+		DeletedAt: deletedAt.Time,
+		Item:      record,
+	}
+	if s.retention > 0 {
+		purgeAt := deletedAt.Time.Add(s.retention)
+		item.PurgeAt = &purgeAt
+	}
+	return item
+}
+
+// RestoreItem brings a deleted record back and returns it. A restored
+// transaction counts towards its wallet balance again.
+func (s *trashService) RestoreItem(itemType string, id, userID uuid.UUID) (interface{}, error) {
+	switch itemType {
+	case TrashTypeTransaction:
+		return s.restoreTransaction(id, userID)
+	case TrashTypeBudget:
+		return s.restoreBudget(id, userID)
+	case TrashTypeGoal:
+		return s.restoreGoal(id, userID)
+	case TrashTypeWallet:
+		return s.restoreWallet(id, userID)
+	default:
+		return nil, ErrUnknownTrashType
+	}
+}
+
+// restoreTransaction brings a transaction back and applies its balance effect
+// again. The row is only restored while it is still deleted, so a repeated
+// restore fails instead of changing the wallet twice.
+func (s *trashService) restoreTransaction(id, userID uuid.UUID) (*models.Transaction, error) {
+	var transaction *models.Transaction
+	err := s.txManager.WithinTransaction(func(tx *gorm.DB) error {
+		transactionRepo := s.transactionRepo.WithTx(tx)
+		walletRepo := s.walletRepo.WithTx(tx)
+
+		var err error
+		if transaction, err = deletedTransaction(transactionRepo, id, userID); err != nil {
+			return err
+		}
+		// Undoing an import locks the same wallet, so its status is settled here
+		if transaction.WalletID != nil {
+			if _, err := walletRepo.FindByIDForUpdate(*transaction.WalletID); err != nil {
+				return errors.New("the transaction's wallet is deleted; restore the wallet first")
+			}
+		}
+		if transaction.ImportJobID != nil {
+			job, err := s.importJobRepo.WithTx(tx).FindByID(*transaction.ImportJobID)
+			if err == nil && job != nil && job.Status == models.ImportStatusUndone {
+				return errors.New("the transaction belongs to an undone import and cannot be restored")
+			}
+		}
+
+		restored, err := transactionRepo.Restore(id)
+		if err != nil {
+			return err
+		}
+		if !restored {
+			return errors.New("transaction not found in trash")
+		}
+		return applyBalanceChanges(walletRepo, nil, transaction)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	transaction.DeletedAt = gorm.DeletedAt{}
+	return transaction, nil
+}
+
+func (s *trashService) restoreBudget(id, userID uuid.UUID) (*models.Budget, error) {
+	budget, err := s.deletedBudget(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only one budget per category may be active
+	if existing, _ := s.budgetRepo.FindByUserIDAndCategory(userID, budget.Category); existing != nil {
+		return nil, errors.New("budget already exists for this category")
+	}
+
+	if err := s.budgetRepo.Restore(id); err != nil {
+		return nil, err
+	}
+
+	budget.DeletedAt = gorm.DeletedAt{}
+	return budget, nil
+}
+
+func (s *trashService) restoreGoal(id, userID uuid.UUID) (*models.SavingGoal, error) {
+	goal, err := s.deletedGoal(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.goalRepo.Restore(id); err != nil {
+		return nil, err
+	}
+
+	goal.DeletedAt = gorm.DeletedAt{}
+	return goal, nil
+}
+
+func (s *trashService) restoreWallet(id, userID uuid.UUID) (*models.Wallet, error) {
+	wallet, err := s.deletedWallet(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.txManager.WithinTransaction(func(tx *gorm.DB) error {
+		walletRepo := s.walletRepo.WithTx(tx)
+		if err := walletRepo.Restore(id); err != nil {
+			return err
+		}
+		wallet.DeletedAt = gorm.DeletedAt{}
+
+		// The default moved to another wallet on delete; keep it there
+		if !wallet.IsDefault {
+			return nil
+		}
+		current, _ := walletRepo.FindDefaultByUserID(userID)
+		if current == nil || current.ID == wallet.ID {
+			return nil
+		}
+		wallet.IsDefault = false
+		return walletRepo.UpdateColumns(wallet.ID, map[string]interface{}{"is_default": false})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return wallet, nil
+}
+
+// PurgeItem permanently deletes a record from the trash
+func (s *trashService) PurgeItem(itemType string, id, userID uuid.UUID) error {
+	switch itemType {
+	case TrashTypeTransaction:
+		if _, err := deletedTransaction(s.transactionRepo, id, userID); err != nil {
+			return err
+		}
+		return s.purgeTransaction(id)
+	case TrashTypeBudget:
+		if _, err := s.deletedBudget(id, userID); err != nil {
+			return err
+		}
+		return s.purgeBudget(id)
+	case TrashTypeGoal:
+		if _, err := s.deletedGoal(id, userID); err != nil {
+			return err
+		}
+		return s.purgeGoal(id)
+	case TrashTypeWallet:
+		if _, err := s.deletedWallet(id, userID); err != nil {
+			return err
+		}
+		return s.purgeWallet(id)
+	default:
+		return ErrUnknownTrashType
+	}
+}
+
+// purgeTransaction deletes a transaction's receipts and their files first.
+// If removing the rows then fails, the transaction stays in the trash and
+// the purge can simply be retried.
+func (s *trashService) purgeTransaction(id uuid.UUID) error {
+	if err := s.receiptService.DeleteTransactionReceipts(id); err != nil {
+		return err
+	}
+	return s.txManager.WithinTransaction(func(tx *gorm.DB) error {
+		return s.transactionRepo.WithTx(tx).Purge(id)
+	})
+}
+
+// purgeBudget deletes a budget and the records kept with it together
+func (s *trashService) purgeBudget(id uuid.UUID) error {
+	return s.txManager.WithinTransaction(func(tx *gorm.DB) error {
+		return s.budgetRepo.WithTx(tx).Purge(id)
+	})
+}
+
+func (s *trashService) purgeGoal(id uuid.UUID) error {
+	return s.txManager.WithinTransaction(func(tx *gorm.DB) error {
+		return s.goalRepo.WithTx(tx).Purge(id)
+	})
+}
+
+// errWalletInUse is returned when a wallet to purge is still referenced
+var errWalletInUse = errors.New("wallet still has transactions, transfers or imports and cannot be purged")
+
+// purgeWallet deletes a wallet once nothing refers to it, checking and
+// deleting in the same transaction
+func (s *trashService) purgeWallet(id uuid.UUID) error {
+	return s.txManager.WithinTransaction(func(tx *gorm.DB) error {
+		walletRepo := s.walletRepo.WithTx(tx)
+		references, err := walletRepo.CountReferences(id)
+		if err != nil {
+			return err
+		}
+		if references > 0 {
+			return errWalletInUse
+		}
+		return walletRepo.Purge(id)
+	})
+}
+
+// PurgeExpired permanently deletes records that have been in the trash longer
+// than the retention period and returns how many were removed. Wallets that
+// are still referenced stay in the trash.
+func (s *trashService) PurgeExpired(now time.Time) (int, error) {
+	if s.retention <= 0 {
+		return 0, nil
+	}
+	cutoff := now.Add(-s.retention)
+	purged := 0
+
+	// Transactions go first so the wallets they pointed at can follow
+	transactions, err := s.transactionRepo.FindDeletedBefore(cutoff, trashPurgeBatch)
+	if err != nil {
+		return purged, err
+	}
+	for _, transaction := range transactions {
+		if err := s.purgeTransaction(transaction.ID); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+
+	budgets, err := s.budgetRepo.FindDeletedBefore(cutoff, trashPurgeBatch)
+	if err != nil {
+		return purged, err
+	}
+	for _, budget := range budgets {
+		if err := s.purgeBudget(budget.ID); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+
+	goals, err := s.goalRepo.FindDeletedBefore(cutoff, trashPurgeBatch)
+	if err != nil {
+		return purged, err
+	}
+	for _, goal := range goals {
+		if err := s.purgeGoal(goal.ID); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+
+	wallets, err := s.walletRepo.FindDeletedBefore(cutoff, trashPurgeBatch)
+	if err != nil {
+		return purged, err
+	}
+	for _, wallet := range wallets {
+		err := s.purgeWallet(wallet.ID)
+		if errors.Is(err, errWalletInUse) {
+			continue
+		}
+		if err != nil {
+			return purged, err
+		}
+		purged++
+	}
+
+	return purged, nil
+}
+
+func deletedTransaction(transactionRepo repository.TransactionRepository, id, userID uuid.UUID) (*models.Transaction, error) {
+	transaction, err := transactionRepo.FindDeletedByID(id)
+	if err != nil || transaction == nil {
+		return nil, errors.New("transaction not found in trash")
+	}
+	if transaction.UserID != userID {
+		return nil, errors.New("unauthorized access to transaction")
+	}
+	// Transfer legs only change through their transfer
+	if transaction.TransferID != nil {
+		return nil, errors.New("transfer transactions cannot be restored or purged")
+	}
+	return transaction, nil
+}
+
+func (s *trashService) deletedBudget(id, userID uuid.UUID) (*models.Budget, error) {
+	budget, err := s.budgetRepo.FindDeletedByID(id)
+	if err != nil || budget == nil {
+		return nil, errors.New("budget not found in trash")
+	}
+	if budget.UserID != userID {
+		return nil, errors.New("unauthorized access to budget")
+	}
+	return budget, nil
+}
+
+func (s *trashService) deletedGoal(id, userID uuid.UUID) (*models.SavingGoal, error) {
+	goal, err := s.goalRepo.FindDeletedByID(id)
+	if err != nil || goal == nil {
+		return nil, errors.New("goal not found in trash")
+	}
+	if goal.UserID != userID {
+		return nil, errors.New("unauthorized access to goal")
+	}
+	return goal, nil
+}
+
+func (s *trashService) deletedWallet(id, userID uuid.UUID) (*models.Wallet, error) {
+	wallet, err := s.walletRepo.FindDeletedByID(id)
+	if err != nil || wallet == nil {
+		return nil, errors.New("wallet not found in trash")
+	}
+	if wallet.UserID != userID {
+		return nil, errors.New("unauthorized access to wallet")
+	}
+	return wallet, nil
+}
@@ -2,10 +2,13 @@ package services
 
 import (
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
 	"github.com/nyunja/fity-budget-backend/internal/repository"
+	"gorm.io/gorm"
 )
 
 // WalletService defines the interface for wallet operations
@@ -17,37 +20,65 @@ type WalletService interface {
 	UpdateWallet(id, userID uuid.UUID, req UpdateWalletRequest) (*models.Wallet, error)
 	DeleteWallet(id, userID uuid.UUID) error
 	SetDefaultWallet(id, userID uuid.UUID) (*models.Wallet, error)
-	TransferBetweenWallets(fromWalletID, toWalletID, userID uuid.UUID, amount float64) error
+	TransferBetweenWallets(userID uuid.UUID, req CreateTransferRequest) (*models.Transfer, error)
+	GetUserTransfers(userID uuid.UUID, limit, offset int) ([]*models.Transfer, int64, error)
+	GetTransferByID(id, userID uuid.UUID) (*models.Transfer, error)
+	ReverseTransfer(id, userID uuid.UUID) (*models.Transfer, error)
 }
 
 type walletService struct {
-	walletRepo repository.WalletRepository
+	walletRepo      repository.WalletRepository
+	transactionRepo repository.TransactionRepository
+	transferRepo    repository.TransferRepository
+	rateRepo        repository.ExchangeRateRepository
+	txManager       repository.TxManager
 }
 
 // CreateWalletRequest represents the data needed to create a wallet
 type CreateWalletRequest struct {
-	Name          string  `json:"name" binding:"required"`
-	Type          string  `json:"type" binding:"required"`
-	Balance       float64 `json:"balance" binding:"omitempty,gte=0"`
-	Currency      string  `json:"currency"`
-	Color         string  `json:"color" binding:"required"`
-	AccountNumber string  `json:"account_number"`
-	IsDefault     bool    `json:"is_default"`
+	Name          string       `json:"name" binding:"required"`
+	Type          string       `json:"type" binding:"required"`
+	Balance       money.Amount `json:"balance" binding:"omitempty,gte=0"`
+	Currency      string       `json:"currency"`
+	Color         string       `json:"color" binding:"required"`
+	AccountNumber string       `json:"account_number"`
+	IsDefault     bool         `json:"is_default"`
+	OffBudget     bool         `json:"off_budget"`
 }
 
 // UpdateWalletRequest represents the data needed to update a wallet
 type UpdateWalletRequest struct {
-	Name          string  `json:"name"`
-	Type          string  `json:"type"`
-	Balance       float64 `json:"balance" binding:"omitempty,gte=0"`
-	Currency      string  `json:"currency"`
-	Color         string  `json:"color"`
-	AccountNumber string  `json:"account_number"`
+	Name          string        `json:"name"`
+	Type          string        `json:"type"`
+	Balance       *money.Amount `json:"balance" binding:"omitempty,gte=0"`
+	Currency      string        `json:"currency"`
+	Color         string        `json:"color"`
+	AccountNumber string        `json:"account_number"`
+	OffBudget     *bool         `json:"off_budget"`
 }
 
-func NewWalletService(walletRepo repository.WalletRepository) WalletService {
+// CreateTransferRequest represents the data needed to move money between two wallets
+type CreateTransferRequest struct {
+	FromWalletID uuid.UUID    `json:"from_wallet_id" binding:"required"`
+	ToWalletID   uuid.UUID    `json:"to_wallet_id" binding:"required"`
+	Amount       money.Amount `json:"amount" binding:"required,gt=0"`
+	Notes        string       `json:"notes"`
+	TransferDate time.Time    `json:"transfer_date"`
+}
+
+func NewWalletService(
+	walletRepo repository.WalletRepository,
+	transactionRepo repository.TransactionRepository,
+	transferRepo repository.TransferRepository,
+	rateRepo repository.ExchangeRateRepository,
+	txManager repository.TxManager,
+) WalletService {
 	return &walletService{
-		walletRepo: walletRepo,
+		walletRepo:      walletRepo,
+		transactionRepo: transactionRepo,
+		transferRepo:    transferRepo,
+		rateRepo:        rateRepo,
+		txManager:       txManager,
 	}
 }
 
@@ -66,8 +97,7 @@ func (s *walletService) CreateWallet(userID uuid.UUID, req CreateWalletRequest)
 	if isDefault {
 		for _, wallet := range existingWallets {
 			if wallet.IsDefault {
-				wallet.IsDefault = false
-				if err := s.walletRepo.Update(wallet); err != nil {
+				if err := s.setDefault(wallet.ID, false); err != nil {
 					return nil, errors.New("failed to update existing default wallet")
 				}
 			}
@@ -77,18 +107,19 @@ func (s *walletService) CreateWallet(userID uuid.UUID, req CreateWalletRequest)
 	// Set default currency if not provided
 	currency := req.Currency
 	if currency == "" {
-		currency = "KES"
+		currency = money.DefaultCurrency
 	}
 
 	wallet := models.Wallet{
 		UserID:        userID,
 		Name:          req.Name,
 		Type:          req.Type,
-		Balance:       req.Balance,
+		Balance:       req.Balance.Round(currency),
 		Currency:      currency,
 		Color:         req.Color,
 		AccountNumber: req.AccountNumber,
 		IsDefault:     isDefault,
+		OffBudget:     req.OffBudget,
 	}
 
 	if err := s.walletRepo.Create(&wallet); err != nil {
@@ -145,31 +176,61 @@ func (s *walletService) UpdateWallet(id, userID uuid.UUID, req UpdateWalletReque
 		return nil, errors.New("unauthorized access to wallet")
 	}
 
-	// Update fields if provided
+	// Amounts already recorded against the wallet are in its currency, so the
+	// currency can only change while nothing has been recorded
+	if req.Currency != "" && req.Currency != wallet.Currency {
+		if wallet.Balance != 0 {
+			return nil, errors.New("cannot change the currency of a wallet with a balance")
+		}
+		references, err := s.walletRepo.CountReferences(wallet.ID)
+		if err != nil {
+			return nil, err
+		}
+		if references > 0 {
+			return nil, errors.New("cannot change the currency of a wallet with transactions")
+		}
+	}
+
+	// Update fields if provided. Only the changed columns are written, so a
+	// balance moved by a concurrent transaction is not overwritten.
+	columns := make(map[string]interface{})
 	if req.Name != "" {
 		wallet.Name = req.Name
+		columns["name"] = req.Name
 	}
 	if req.Type != "" {
 		wallet.Type = req.Type
+		columns["type"] = req.Type
 	}
 	if req.Currency != "" {
 		wallet.Currency = req.Currency
+		columns["currency"] = req.Currency
 	}
 	if req.Color != "" {
 		wallet.Color = req.Color
+		columns["color"] = req.Color
 	}
-	if req.Balance >= 0 {
-		wallet.Balance = req.Balance
+	if req.Balance != nil {
+		wallet.Balance = req.Balance.Round(wallet.Currency)
+		columns["balance"] = wallet.Balance
 	}
 	if req.AccountNumber != "" {
 		wallet.AccountNumber = req.AccountNumber
+		columns["account_number"] = req.AccountNumber
+	}
+	if req.OffBudget != nil {
+		wallet.OffBudget = *req.OffBudget
+		columns["off_budget"] = *req.OffBudget
 	}
 
-	if err := s.walletRepo.Update(wallet); err != nil {
+	if len(columns) == 0 {
+		return wallet, nil
+	}
+	if err := s.walletRepo.UpdateColumns(wallet.ID, columns); err != nil {
 		return nil, err
 	}
 
-	return wallet, nil
+	return s.walletRepo.FindByID(wallet.ID)
 }
 
 // DeleteWallet deletes a wallet
@@ -199,8 +260,7 @@ func (s *walletService) DeleteWallet(id, userID uuid.UUID) error {
 		// Find another wallet to set as default (excluding the one being deleted)
 		for _, otherWallet := range otherWallets {
 			if otherWallet.ID != id {
-				otherWallet.IsDefault = true
-				if err := s.walletRepo.Update(otherWallet); err != nil {
+				if err := s.setDefault(otherWallet.ID, true); err != nil {
 					return errors.New("failed to set new default wallet")
 				}
 				break
@@ -240,67 +300,320 @@ func (s *walletService) SetDefaultWallet(id, userID uuid.UUID) (*models.Wallet,
 
 	for _, userWallet := range userWallets {
 		if userWallet.IsDefault {
-			userWallet.IsDefault = false
-			if err := s.walletRepo.Update(userWallet); err != nil {
+			if err := s.setDefault(userWallet.ID, false); err != nil {
 				return nil, errors.New("failed to update existing default wallet")
 			}
 		}
 	}
 
 	// Set this wallet as default
-	wallet.IsDefault = true
-	if err := s.walletRepo.Update(wallet); err != nil {
+	if err := s.setDefault(wallet.ID, true); err != nil {
 		return nil, err
 	}
+	wallet.IsDefault = true
 
 	return wallet, nil
 }
 
-// TransferBetweenWallets transfers funds between two wallets
-func (s *walletService) TransferBetweenWallets(fromWalletID, toWalletID, userID uuid.UUID, amount float64) error {
-	if amount <= 0 {
-		return errors.New("transfer amount must be greater than zero")
+// setDefault writes only the is_default flag of a wallet
+func (s *walletService) setDefault(id uuid.UUID, isDefault bool) error {
+	return s.walletRepo.UpdateColumns(id, map[string]interface{}{"is_default": isDefault})
+}
+
+// TransferBetweenWallets moves funds between two of the user's wallets. The
+// debit, the credit, the transfer record and its pair of transfer transactions
+// are written in a single database transaction with both wallet rows locked.
+// Wallets in different currencies are credited at the exchange rate quoted on
+// or before the transfer date.
+func (s *walletService) TransferBetweenWallets(userID uuid.UUID, req CreateTransferRequest) (*models.Transfer, error) {
+	if req.Amount <= 0 {
+		return nil, errors.New("transfer amount must be greater than zero")
+	}
+	if req.FromWalletID == req.ToWalletID {
+		return nil, errors.New("cannot transfer to the same wallet")
+	}
+
+	transferDate := req.TransferDate
+	if transferDate.IsZero() {
+		transferDate = time.Now()
+	}
+
+	var transfer *models.Transfer
+	err := s.txManager.WithinTransaction(func(tx *gorm.DB) error {
+		var err error
+		transfer, err = s.moveFunds(tx, userID, fundsMovement{
+			FromWalletID: req.FromWalletID,
+			ToWalletID:   req.ToWalletID,
+			Amount:       req.Amount,
+			Notes:        req.Notes,
+			TransferDate: transferDate,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// Get and verify source wallet
-	fromWallet, err := s.walletRepo.FindByID(fromWalletID)
+	return s.transferRepo.FindByID(transfer.ID)
+}
+
+// GetUserTransfers retrieves a page of transfers and the total count for a user
+func (s *walletService) GetUserTransfers(userID uuid.UUID, limit, offset int) ([]*models.Transfer, int64, error) {
+	// Set default limit if not provided or invalid
+	if limit <= 0 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	transfers, err := s.transferRepo.FindByUserID(userID, limit, offset)
 	if err != nil {
-		return errors.New("source wallet not found")
+		return nil, 0, err
 	}
-	if fromWallet.UserID != userID {
-		return errors.New("unauthorized access to source wallet")
+
+	total, err := s.transferRepo.CountByUserID(userID)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	// Get and verify destination wallet
-	toWallet, err := s.walletRepo.FindByID(toWalletID)
+	return transfers, total, nil
+}
+
+// GetTransferByID retrieves a specific transfer by ID
+func (s *walletService) GetTransferByID(id, userID uuid.UUID) (*models.Transfer, error) {
+	transfer, err := s.transferRepo.FindByID(id)
 	if err != nil {
-		return errors.New("destination wallet not found")
+		return nil, errors.New("transfer not found")
+	}
+
+	// Verify transfer belongs to user
+	if transfer.UserID != userID {
+		return nil, errors.New("unauthorized access to transfer")
+	}
+
+	return transfer, nil
+}
+
+// ReverseTransfer sends the money of a completed transfer back to its source
+// wallet. The reversal is recorded as a new transfer linked to the original,
+// which is marked as reversed, so both movements stay in the history. A
+// cross-currency transfer is undone at its original rate, so both wallets
+// end up exactly where they started.
+func (s *walletService) ReverseTransfer(id, userID uuid.UUID) (*models.Transfer, error) {
+	if _, err := s.GetTransferByID(id, userID); err != nil {
+		return nil, err
+	}
+
+	var reversal *models.Transfer
+	err := s.txManager.WithinTransaction(func(tx *gorm.DB) error {
+		// Check the transfer again under a row lock so concurrent reversals
+		// cannot both move the funds back
+		transferRepo := s.transferRepo.WithTx(tx)
+		original, err := transferRepo.FindByIDForUpdate(id)
+		if err != nil {
+			return errors.New("transfer not found")
+		}
+		if original.UserID != userID {
+			return errors.New("unauthorized access to transfer")
+		}
+		if original.Status == "Reversed" {
+			return errors.New("transfer has already been reversed")
+		}
+		if original.ReversalOfID != nil {
+			return errors.New("cannot reverse a reversal transfer")
+		}
+
+		reversal, err = s.moveFunds(tx, userID, reversalOf(original))
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		original.Status = "Reversed"
+		original.ReversedAt = &now
+		return transferRepo.Update(original)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.transferRepo.FindByID(reversal.ID)
+}
+
+// fundsMovement describes a single transfer performed by moveFunds
+type fundsMovement struct {
+	FromWalletID uuid.UUID
+	ToWalletID   uuid.UUID
+	Amount       money.Amount
+	Notes        string
+	TransferDate time.Time
+	ReversalOfID *uuid.UUID
+
+	// Credit and Rate pin the amount credited to the destination wallet. When
+	// Rate is nil the amount is converted at the rate for TransferDate.
+	Credit money.Amount
+	Rate   *AppliedRate
+}
+
+// reversalOf describes the movement that undoes a transfer
+func reversalOf(original *models.Transfer) fundsMovement {
+	movement := fundsMovement{
+		FromWalletID: original.ToWalletID,
+		ToWalletID:   original.FromWalletID,
+		Amount:       original.ToAmount,
+		Notes:        "Reversal of transfer " + original.ID.String(),
+		TransferDate: time.Now(),
+		ReversalOfID: &original.ID,
+		Credit:       original.Amount,
+		Rate: &AppliedRate{
+			From: original.ToCurrency,
+			To:   original.FromCurrency,
+			Rate: original.ExchangeRate.Inverse(),
+		},
+	}
+	if original.RateDate != nil {
+		movement.Rate.RateDate = *original.RateDate
+	}
+
+	// Transfers made before currencies were recorded moved the same amount
+	if movement.Amount <= 0 || original.ExchangeRate <= 0 {
+		movement.Amount = original.Amount
+		movement.Rate = nil
+	}
+
+	return movement
+}
+
+// moveFunds performs a transfer inside an open database transaction
+func (s *walletService) moveFunds(tx *gorm.DB, userID uuid.UUID, movement fundsMovement) (*models.Transfer, error) {
+	fromWalletID, toWalletID := movement.FromWalletID, movement.ToWalletID
+	notes, transferDate := movement.Notes, movement.TransferDate
+
+	walletRepo := s.walletRepo.WithTx(tx)
+	transactionRepo := s.transactionRepo.WithTx(tx)
+	transferRepo := s.transferRepo.WithTx(tx)
+
+	// Lock both wallets in a stable order so opposite transfers cannot deadlock
+	lockOrder := []uuid.UUID{fromWalletID, toWalletID}
+	if toWalletID.String() < fromWalletID.String() {
+		lockOrder = []uuid.UUID{toWalletID, fromWalletID}
+	}
+	locked := make(map[uuid.UUID]*models.Wallet, 2)
+	for _, walletID := range lockOrder {
+		wallet, err := walletRepo.FindByIDForUpdate(walletID)
+		if err != nil {
+			if walletID == fromWalletID {
+				return nil, errors.New("source wallet not found")
+			}
+			return nil, errors.New("destination wallet not found")
+		}
+		locked[walletID] = wallet
+	}
+
+	fromWallet, toWallet := locked[fromWalletID], locked[toWalletID]
+	if fromWallet.UserID != userID {
+		return nil, errors.New("unauthorized access to source wallet")
 	}
 	if toWallet.UserID != userID {
-		return errors.New("unauthorized access to destination wallet")
+		return nil, errors.New("unauthorized access to destination wallet")
+	}
+
+	// Transfers are kept to the precision of the source wallet's currency
+	amount := movement.Amount.Round(fromWallet.Currency)
+	if amount <= 0 {
+		return nil, errors.New("transfer amount must be greater than zero")
 	}
 
 	// Check if source wallet has sufficient balance
 	if fromWallet.Balance < amount {
-		return errors.New("insufficient balance in source wallet")
+		return nil, errors.New("insufficient balance in source wallet")
+	}
+
+	transfer := &models.Transfer{
+		UserID:       userID,
+		FromWalletID: fromWalletID,
+		ToWalletID:   toWalletID,
+		Amount:       amount,
+		FromCurrency: fromWallet.Currency,
+		ToAmount:     amount,
+		ToCurrency:   toWallet.Currency,
+		ExchangeRate: money.OneRate,
+		Notes:        notes,
+		Status:       "Completed",
+		ReversalOfID: movement.ReversalOfID,
+		TransferDate: transferDate,
 	}
 
-	// Warn if currencies are different
+	// Convert the credit into the destination wallet's currency
 	if fromWallet.Currency != toWallet.Currency {
-		// In a production app, you'd want to handle currency conversion here
-		// For now, we'll just log a warning but allow the transfer
+		credit, applied := movement.Credit, movement.Rate
+		if applied == nil {
+			var err error
+			credit, applied, err = newCurrencyConverter(s.rateRepo).convert(amount, fromWallet.Currency, toWallet.Currency, transferDate)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if credit <= 0 {
+			return nil, errors.New("transfer amount is too small to convert")
+		}
+
+		rateDate := applied.RateDate
+		transfer.ToAmount = credit
+		transfer.ExchangeRate = applied.Rate
+		transfer.RateDate = &rateDate
+	}
+	if err := transferRepo.Create(transfer); err != nil {
+		return nil, err
 	}
 
-	// Perform the transfer
-	if err := s.walletRepo.UpdateBalance(fromWalletID, -amount); err != nil {
-		return errors.New("failed to deduct from source wallet")
+	outgoing := &models.Transaction{
+		UserID:          userID,
+		WalletID:        &fromWalletID,
+		TransferID:      &transfer.ID,
+		Amount:          amount,
+		Type:            models.TransactionTypeTransfer,
+		Name:            "Transfer to " + toWallet.Name,
+		Method:          "Wallet Transfer",
+		Category:        "Transfer",
+		Status:          "Completed",
+		Notes:           notes,
+		TransactionDate: transferDate,
+	}
+	incoming := &models.Transaction{
+		UserID:          userID,
+		WalletID:        &toWalletID,
+		TransferID:      &transfer.ID,
+		Amount:          transfer.ToAmount,
+		Type:            models.TransactionTypeTransfer,
+		Name:            "Transfer from " + fromWallet.Name,
+		Method:          "Wallet Transfer",
+		Category:        "Transfer",
+		Status:          "Completed",
+		Notes:           notes,
+		TransactionDate: transferDate,
+	}
+	if err := transactionRepo.Create(outgoing); err != nil {
+		return nil, err
+	}
+	if err := transactionRepo.Create(incoming); err != nil {
+		return nil, err
 	}
 
-	if err := s.walletRepo.UpdateBalance(toWalletID, amount); err != nil {
-		// Attempt to revert the deduction from source wallet
-		s.walletRepo.UpdateBalance(fromWalletID, amount)
-		return errors.New("failed to add to destination wallet")
+	transfer.OutTransactionID = &outgoing.ID
+	transfer.InTransactionID = &incoming.ID
+	if err := transferRepo.Update(transfer); err != nil {
+		return nil, err
 	}
 
-	return nil
+	// Perform the transfer
+	if err := walletRepo.UpdateBalance(fromWalletID, -amount); err != nil {
+		return nil, errors.New("failed to deduct from source wallet")
+	}
+	if err := walletRepo.UpdateBalance(toWalletID, transfer.ToAmount); err != nil {
+		return nil, errors.New("failed to add to destination wallet")
+	}
+
+	return transfer, nil
 }
@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalStorage stores files in a directory on the local filesystem
+type LocalStorage struct {
+	root string
+}
+
+// NewLocalStorage returns a Storage rooted at dir, creating it if needed
+func NewLocalStorage(dir string) (*LocalStorage, error) {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+	return &LocalStorage{root: root}, nil
+}
+
+// Put writes the file to a temporary name first so readers never see a
+// partially written file
+func (s *LocalStorage) Put(key string, r io.Reader) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// Open opens the file stored under key
+func (s *LocalStorage) Open(key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return file, err
+}
+
+// Delete removes the file stored under key
+func (s *LocalStorage) Delete(key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// path maps a key to a file under the root, refusing keys that would escape it
+func (s *LocalStorage) path(key string) (string, error) {
+	path := filepath.Join(s.root, filepath.FromSlash(key))
+	if key == "" || !strings.HasPrefix(path, s.root+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid storage key %q", key)
+	}
+	return path, nil
+}
@@ -0,0 +1,22 @@
+// Package storage keeps uploaded files, such as receipts, behind a small
+// interface so the backend holding them can be swapped without touching the
+// services that use it.
+package storage
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrNotFound is returned when no file is stored under a key
+var ErrNotFound = errors.New("file not found")
+
+// Storage stores files under slash-separated keys
+type Storage interface {
+	// Put stores the contents of r under key, replacing any existing file
+	Put(key string, r io.Reader) error
+	// Open returns the file stored under key. The caller must close it.
+	Open(key string) (io.ReadCloser, error)
+	// Delete removes the file stored under key. Deleting a missing file is not an error.
+	Delete(key string) error
+}
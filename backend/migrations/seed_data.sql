@@ -67,62 +67,62 @@ BEGIN
         SELECT id INTO v_cash_id FROM wallets WHERE user_id = v_user_id AND name = 'Cash';
 
         -- Insert transactions
-        INSERT INTO transactions (id, user_id, wallet_id, amount, name, method, category, status, notes, receipt_url, transaction_date, created_at, updated_at)
+        INSERT INTO transactions (id, user_id, wallet_id, amount, type, name, method, category, status, notes, receipt_url, transaction_date, created_at, updated_at)
         VALUES
             -- Recent transactions (July 2024)
-            (gen_random_uuid(), v_user_id, v_equity_id, -10.00, 'YouTube', 'VISA **3254', 'Subscription', 'Completed', NULL, NULL, '2024-07-25 12:30:00', '2024-07-25 12:30:00', '2024-07-25 12:30:00'),
-            (gen_random_uuid(), v_user_id, v_mpesa_id, -150.00, 'Reserved', 'Mastercard **2154', 'Shopping', 'Pending', NULL, NULL, '2024-07-26 15:00:00', '2024-07-26 15:00:00', '2024-07-26 15:00:00'),
-            (gen_random_uuid(), v_user_id, v_cash_id, -80.00, 'Yaposhka', 'Mastercard **2154', 'Cafe & Restaurants', 'Completed', NULL, NULL, '2024-07-27 09:00:00', '2024-07-27 09:00:00', '2024-07-27 09:00:00'),
-            (gen_random_uuid(), v_user_id, v_equity_id, 2400.00, 'Salary', 'Bank Transfer', 'Income', 'Completed', NULL, NULL, '2024-07-28 10:15:00', '2024-07-28 10:15:00', '2024-07-28 10:15:00'),
+            (gen_random_uuid(), v_user_id, v_equity_id, 10.00, 'expense', 'YouTube', 'VISA **3254', 'Subscription', 'Completed', NULL, NULL, '2024-07-25 12:30:00', '2024-07-25 12:30:00', '2024-07-25 12:30:00'),
+            (gen_random_uuid(), v_user_id, v_mpesa_id, 150.00, 'expense', 'Reserved', 'Mastercard **2154', 'Shopping', 'Pending', NULL, NULL, '2024-07-26 15:00:00', '2024-07-26 15:00:00', '2024-07-26 15:00:00'),
+            (gen_random_uuid(), v_user_id, v_cash_id, 80.00, 'expense', 'Yaposhka', 'Mastercard **2154', 'Cafe & Restaurants', 'Completed', NULL, NULL, '2024-07-27 09:00:00', '2024-07-27 09:00:00', '2024-07-27 09:00:00'),
+            (gen_random_uuid(), v_user_id, v_equity_id, 2400.00, 'income', 'Salary', 'Bank Transfer', 'Income', 'Completed', NULL, NULL, '2024-07-28 10:15:00', '2024-07-28 10:15:00', '2024-07-28 10:15:00'),
 
             -- Additional transactions
-            (gen_random_uuid(), v_user_id, v_mpesa_id, -45.50, 'Uber Eats', 'VISA **3254', 'Food & Groceries', 'Completed', NULL, NULL, '2024-07-24 18:20:00', '2024-07-24 18:20:00', '2024-07-24 18:20:00'),
-            (gen_random_uuid(), v_user_id, v_equity_id, -120.00, 'Nike Store', 'Mastercard **2154', 'Shopping', 'Completed', NULL, NULL, '2024-07-24 14:00:00', '2024-07-24 14:00:00', '2024-07-24 14:00:00'),
-            (gen_random_uuid(), v_user_id, v_cash_id, -15.00, 'Starbucks', 'VISA **3254', 'Cafe & Restaurants', 'Completed', NULL, NULL, '2024-07-23 09:30:00', '2024-07-23 09:30:00', '2024-07-23 09:30:00'),
-            (gen_random_uuid(), v_user_id, v_mpesa_id, -200.00, 'Electric Bill', 'Bank Transfer', 'Utilities', 'Completed', NULL, NULL, '2024-07-22 20:15:00', '2024-07-22 20:15:00', '2024-07-22 20:15:00'),
-            (gen_random_uuid(), v_user_id, v_mpesa_id, 500.00, 'Freelance', 'Bank Transfer', 'Income', 'Completed', NULL, NULL, '2024-07-21 11:00:00', '2024-07-21 11:00:00', '2024-07-21 11:00:00'),
-            (gen_random_uuid(), v_user_id, v_cash_id, -60.00, 'Gas Station', 'Mastercard **2154', 'Transportation', 'Pending', NULL, NULL, '2024-07-20 16:45:00', '2024-07-20 16:45:00', '2024-07-20 16:45:00'),
-            (gen_random_uuid(), v_user_id, v_equity_id, -12.99, 'Netflix', 'VISA **3254', 'Subscription', 'Completed', NULL, NULL, '2024-07-19 13:20:00', '2024-07-19 13:20:00', '2024-07-19 13:20:00'),
-            (gen_random_uuid(), v_user_id, v_cash_id, -85.00, 'Grocery Store', 'Mastercard **2154', 'Food & Groceries', 'Completed', NULL, NULL, '2024-07-18 10:00:00', '2024-07-18 10:00:00', '2024-07-18 10:00:00'),
-            (gen_random_uuid(), v_user_id, v_mpesa_id, -25.00, 'Gym Membership', 'VISA **3254', 'Health & Beauty', 'Completed', NULL, NULL, '2024-07-17 09:00:00', '2024-07-17 09:00:00', '2024-07-17 09:00:00'),
-            (gen_random_uuid(), v_user_id, v_equity_id, 150.00, 'Refund', 'VISA **3254', 'Income', 'Completed', NULL, NULL, '2024-07-16 15:30:00', '2024-07-16 15:30:00', '2024-07-16 15:30:00'),
+            (gen_random_uuid(), v_user_id, v_mpesa_id, 45.50, 'expense', 'Uber Eats', 'VISA **3254', 'Food & Groceries', 'Completed', NULL, NULL, '2024-07-24 18:20:00', '2024-07-24 18:20:00', '2024-07-24 18:20:00'),
+            (gen_random_uuid(), v_user_id, v_equity_id, 120.00, 'expense', 'Nike Store', 'Mastercard **2154', 'Shopping', 'Completed', NULL, NULL, '2024-07-24 14:00:00', '2024-07-24 14:00:00', '2024-07-24 14:00:00'),
+            (gen_random_uuid(), v_user_id, v_cash_id, 15.00, 'expense', 'Starbucks', 'VISA **3254', 'Cafe & Restaurants', 'Completed', NULL, NULL, '2024-07-23 09:30:00', '2024-07-23 09:30:00', '2024-07-23 09:30:00'),
+            (gen_random_uuid(), v_user_id, v_mpesa_id, 200.00, 'expense', 'Electric Bill', 'Bank Transfer', 'Utilities', 'Completed', NULL, NULL, '2024-07-22 20:15:00', '2024-07-22 20:15:00', '2024-07-22 20:15:00'),
+            (gen_random_uuid(), v_user_id, v_mpesa_id, 500.00, 'income', 'Freelance', 'Bank Transfer', 'Income', 'Completed', NULL, NULL, '2024-07-21 11:00:00', '2024-07-21 11:00:00', '2024-07-21 11:00:00'),
+            (gen_random_uuid(), v_user_id, v_cash_id, 60.00, 'expense', 'Gas Station', 'Mastercard **2154', 'Transportation', 'Pending', NULL, NULL, '2024-07-20 16:45:00', '2024-07-20 16:45:00', '2024-07-20 16:45:00'),
+            (gen_random_uuid(), v_user_id, v_equity_id, 12.99, 'expense', 'Netflix', 'VISA **3254', 'Subscription', 'Completed', NULL, NULL, '2024-07-19 13:20:00', '2024-07-19 13:20:00', '2024-07-19 13:20:00'),
+            (gen_random_uuid(), v_user_id, v_cash_id, 85.00, 'expense', 'Grocery Store', 'Mastercard **2154', 'Food & Groceries', 'Completed', NULL, NULL, '2024-07-18 10:00:00', '2024-07-18 10:00:00', '2024-07-18 10:00:00'),
+            (gen_random_uuid(), v_user_id, v_mpesa_id, 25.00, 'expense', 'Gym Membership', 'VISA **3254', 'Health & Beauty', 'Completed', NULL, NULL, '2024-07-17 09:00:00', '2024-07-17 09:00:00', '2024-07-17 09:00:00'),
+            (gen_random_uuid(), v_user_id, v_equity_id, 150.00, 'income', 'Refund', 'VISA **3254', 'Income', 'Completed', NULL, NULL, '2024-07-16 15:30:00', '2024-07-16 15:30:00', '2024-07-16 15:30:00'),
 
             -- Historical transactions for money flow chart (Jan - Jun 2024)
             -- January
-            (gen_random_uuid(), v_user_id, v_equity_id, 9500.00, 'Salary', 'Bank Transfer', 'Income', 'Completed', NULL, NULL, '2024-01-05 10:00:00', '2024-01-05 10:00:00', '2024-01-05 10:00:00'),
-            (gen_random_uuid(), v_user_id, v_mpesa_id, -3000.00, 'Rent', 'Bank Transfer', 'Utilities', 'Completed', NULL, NULL, '2024-01-10 09:00:00', '2024-01-10 09:00:00', '2024-01-10 09:00:00'),
-            (gen_random_uuid(), v_user_id, v_cash_id, -2000.00, 'Groceries', 'Cash', 'Food & Groceries', 'Completed', NULL, NULL, '2024-01-15 14:00:00', '2024-01-15 14:00:00', '2024-01-15 14:00:00'),
-            (gen_random_uuid(), v_user_id, v_equity_id, -3000.00, 'Various Expenses', 'VISA **3254', 'Shopping', 'Completed', NULL, NULL, '2024-01-20 16:00:00', '2024-01-20 16:00:00', '2024-01-20 16:00:00'),
+            (gen_random_uuid(), v_user_id, v_equity_id, 9500.00, 'income', 'Salary', 'Bank Transfer', 'Income', 'Completed', NULL, NULL, '2024-01-05 10:00:00', '2024-01-05 10:00:00', '2024-01-05 10:00:00'),
+            (gen_random_uuid(), v_user_id, v_mpesa_id, 3000.00, 'expense', 'Rent', 'Bank Transfer', 'Utilities', 'Completed', NULL, NULL, '2024-01-10 09:00:00', '2024-01-10 09:00:00', '2024-01-10 09:00:00'),
+            (gen_random_uuid(), v_user_id, v_cash_id, 2000.00, 'expense', 'Groceries', 'Cash', 'Food & Groceries', 'Completed', NULL, NULL, '2024-01-15 14:00:00', '2024-01-15 14:00:00', '2024-01-15 14:00:00'),
+            (gen_random_uuid(), v_user_id, v_equity_id, 3000.00, 'expense', 'Various Expenses', 'VISA **3254', 'Shopping', 'Completed', NULL, NULL, '2024-01-20 16:00:00', '2024-01-20 16:00:00', '2024-01-20 16:00:00'),
 
             -- February
-            (gen_random_uuid(), v_user_id, v_equity_id, 10500.00, 'Salary + Bonus', 'Bank Transfer', 'Income', 'Completed', NULL, NULL, '2024-02-05 10:00:00', '2024-02-05 10:00:00', '2024-02-05 10:00:00'),
-            (gen_random_uuid(), v_user_id, v_mpesa_id, -5000.00, 'Emergency Repair', 'Bank Transfer', 'Utilities', 'Completed', NULL, NULL, '2024-02-12 11:00:00', '2024-02-12 11:00:00', '2024-02-12 11:00:00'),
-            (gen_random_uuid(), v_user_id, v_cash_id, -3500.00, 'Shopping', 'Cash', 'Shopping', 'Completed', NULL, NULL, '2024-02-14 15:00:00', '2024-02-14 15:00:00', '2024-02-14 15:00:00'),
-            (gen_random_uuid(), v_user_id, v_equity_id, -3500.00, 'Various Expenses', 'VISA **3254', 'Entertainment', 'Completed', NULL, NULL, '2024-02-20 18:00:00', '2024-02-20 18:00:00', '2024-02-20 18:00:00'),
+            (gen_random_uuid(), v_user_id, v_equity_id, 10500.00, 'income', 'Salary + Bonus', 'Bank Transfer', 'Income', 'Completed', NULL, NULL, '2024-02-05 10:00:00', '2024-02-05 10:00:00', '2024-02-05 10:00:00'),
+            (gen_random_uuid(), v_user_id, v_mpesa_id, 5000.00, 'expense', 'Emergency Repair', 'Bank Transfer', 'Utilities', 'Completed', NULL, NULL, '2024-02-12 11:00:00', '2024-02-12 11:00:00', '2024-02-12 11:00:00'),
+            (gen_random_uuid(), v_user_id, v_cash_id, 3500.00, 'expense', 'Shopping', 'Cash', 'Shopping', 'Completed', NULL, NULL, '2024-02-14 15:00:00', '2024-02-14 15:00:00', '2024-02-14 15:00:00'),
+            (gen_random_uuid(), v_user_id, v_equity_id, 3500.00, 'expense', 'Various Expenses', 'VISA **3254', 'Entertainment', 'Completed', NULL, NULL, '2024-02-20 18:00:00', '2024-02-20 18:00:00', '2024-02-20 18:00:00'),
 
             -- March
-            (gen_random_uuid(), v_user_id, v_equity_id, 10500.00, 'Salary', 'Bank Transfer', 'Income', 'Completed', NULL, NULL, '2024-03-05 10:00:00', '2024-03-05 10:00:00', '2024-03-05 10:00:00'),
-            (gen_random_uuid(), v_user_id, v_mpesa_id, -3500.00, 'Rent', 'Bank Transfer', 'Utilities', 'Completed', NULL, NULL, '2024-03-10 09:00:00', '2024-03-10 09:00:00', '2024-03-10 09:00:00'),
-            (gen_random_uuid(), v_user_id, v_cash_id, -2500.00, 'Groceries', 'Cash', 'Food & Groceries', 'Completed', NULL, NULL, '2024-03-15 14:00:00', '2024-03-15 14:00:00', '2024-03-15 14:00:00'),
-            (gen_random_uuid(), v_user_id, v_equity_id, -3500.00, 'Various Expenses', 'VISA **3254', 'Shopping', 'Completed', NULL, NULL, '2024-03-20 16:00:00', '2024-03-20 16:00:00', '2024-03-20 16:00:00'),
+            (gen_random_uuid(), v_user_id, v_equity_id, 10500.00, 'income', 'Salary', 'Bank Transfer', 'Income', 'Completed', NULL, NULL, '2024-03-05 10:00:00', '2024-03-05 10:00:00', '2024-03-05 10:00:00'),
+            (gen_random_uuid(), v_user_id, v_mpesa_id, 3500.00, 'expense', 'Rent', 'Bank Transfer', 'Utilities', 'Completed', NULL, NULL, '2024-03-10 09:00:00', '2024-03-10 09:00:00', '2024-03-10 09:00:00'),
+            (gen_random_uuid(), v_user_id, v_cash_id, 2500.00, 'expense', 'Groceries', 'Cash', 'Food & Groceries', 'Completed', NULL, NULL, '2024-03-15 14:00:00', '2024-03-15 14:00:00', '2024-03-15 14:00:00'),
+            (gen_random_uuid(), v_user_id, v_equity_id, 3500.00, 'expense', 'Various Expenses', 'VISA **3254', 'Shopping', 'Completed', NULL, NULL, '2024-03-20 16:00:00', '2024-03-20 16:00:00', '2024-03-20 16:00:00'),
 
             -- April
-            (gen_random_uuid(), v_user_id, v_equity_id, 14000.00, 'Salary + Commission', 'Bank Transfer', 'Income', 'Completed', NULL, NULL, '2024-04-05 10:00:00', '2024-04-05 10:00:00', '2024-04-05 10:00:00'),
-            (gen_random_uuid(), v_user_id, v_mpesa_id, -4000.00, 'Rent', 'Bank Transfer', 'Utilities', 'Completed', NULL, NULL, '2024-04-10 09:00:00', '2024-04-10 09:00:00', '2024-04-10 09:00:00'),
-            (gen_random_uuid(), v_user_id, v_cash_id, -3500.00, 'Groceries', 'Cash', 'Food & Groceries', 'Completed', NULL, NULL, '2024-04-15 14:00:00', '2024-04-15 14:00:00', '2024-04-15 14:00:00'),
-            (gen_random_uuid(), v_user_id, v_equity_id, -5000.00, 'Electronics', 'VISA **3254', 'Shopping', 'Completed', NULL, NULL, '2024-04-20 16:00:00', '2024-04-20 16:00:00', '2024-04-20 16:00:00'),
+            (gen_random_uuid(), v_user_id, v_equity_id, 14000.00, 'income', 'Salary + Commission', 'Bank Transfer', 'Income', 'Completed', NULL, NULL, '2024-04-05 10:00:00', '2024-04-05 10:00:00', '2024-04-05 10:00:00'),
+            (gen_random_uuid(), v_user_id, v_mpesa_id, 4000.00, 'expense', 'Rent', 'Bank Transfer', 'Utilities', 'Completed', NULL, NULL, '2024-04-10 09:00:00', '2024-04-10 09:00:00', '2024-04-10 09:00:00'),
+            (gen_random_uuid(), v_user_id, v_cash_id, 3500.00, 'expense', 'Groceries', 'Cash', 'Food & Groceries', 'Completed', NULL, NULL, '2024-04-15 14:00:00', '2024-04-15 14:00:00', '2024-04-15 14:00:00'),
+            (gen_random_uuid(), v_user_id, v_equity_id, 5000.00, 'expense', 'Electronics', 'VISA **3254', 'Shopping', 'Completed', NULL, NULL, '2024-04-20 16:00:00', '2024-04-20 16:00:00', '2024-04-20 16:00:00'),
 
             -- May
-            (gen_random_uuid(), v_user_id, v_equity_id, 12500.00, 'Salary', 'Bank Transfer', 'Income', 'Completed', NULL, NULL, '2024-05-05 10:00:00', '2024-05-05 10:00:00', '2024-05-05 10:00:00'),
-            (gen_random_uuid(), v_user_id, v_mpesa_id, -4000.00, 'Rent', 'Bank Transfer', 'Utilities', 'Completed', NULL, NULL, '2024-05-10 09:00:00', '2024-05-10 09:00:00', '2024-05-10 09:00:00'),
-            (gen_random_uuid(), v_user_id, v_cash_id, -3000.00, 'Groceries', 'Cash', 'Food & Groceries', 'Completed', NULL, NULL, '2024-05-15 14:00:00', '2024-05-15 14:00:00', '2024-05-15 14:00:00'),
-            (gen_random_uuid(), v_user_id, v_equity_id, -5000.00, 'Various Expenses', 'VISA **3254', 'Entertainment', 'Completed', NULL, NULL, '2024-05-20 16:00:00', '2024-05-20 16:00:00', '2024-05-20 16:00:00'),
+            (gen_random_uuid(), v_user_id, v_equity_id, 12500.00, 'income', 'Salary', 'Bank Transfer', 'Income', 'Completed', NULL, NULL, '2024-05-05 10:00:00', '2024-05-05 10:00:00', '2024-05-05 10:00:00'),
+            (gen_random_uuid(), v_user_id, v_mpesa_id, 4000.00, 'expense', 'Rent', 'Bank Transfer', 'Utilities', 'Completed', NULL, NULL, '2024-05-10 09:00:00', '2024-05-10 09:00:00', '2024-05-10 09:00:00'),
+            (gen_random_uuid(), v_user_id, v_cash_id, 3000.00, 'expense', 'Groceries', 'Cash', 'Food & Groceries', 'Completed', NULL, NULL, '2024-05-15 14:00:00', '2024-05-15 14:00:00', '2024-05-15 14:00:00'),
+            (gen_random_uuid(), v_user_id, v_equity_id, 5000.00, 'expense', 'Various Expenses', 'VISA **3254', 'Entertainment', 'Completed', NULL, NULL, '2024-05-20 16:00:00', '2024-05-20 16:00:00', '2024-05-20 16:00:00'),
 
             -- June
-            (gen_random_uuid(), v_user_id, v_equity_id, 7500.00, 'Salary (Half Month)', 'Bank Transfer', 'Income', 'Completed', NULL, NULL, '2024-06-05 10:00:00', '2024-06-05 10:00:00', '2024-06-05 10:00:00'),
-            (gen_random_uuid(), v_user_id, v_mpesa_id, -2500.00, 'Rent', 'Bank Transfer', 'Utilities', 'Completed', NULL, NULL, '2024-06-10 09:00:00', '2024-06-10 09:00:00', '2024-06-10 09:00:00'),
-            (gen_random_uuid(), v_user_id, v_cash_id, -1500.00, 'Groceries', 'Cash', 'Food & Groceries', 'Completed', NULL, NULL, '2024-06-15 14:00:00', '2024-06-15 14:00:00', '2024-06-15 14:00:00'),
-            (gen_random_uuid(), v_user_id, v_equity_id, -2000.00, 'Various Expenses', 'VISA **3254', 'Shopping', 'Completed', NULL, NULL, '2024-06-20 16:00:00', '2024-06-20 16:00:00', '2024-06-20 16:00:00')
+            (gen_random_uuid(), v_user_id, v_equity_id, 7500.00, 'income', 'Salary (Half Month)', 'Bank Transfer', 'Income', 'Completed', NULL, NULL, '2024-06-05 10:00:00', '2024-06-05 10:00:00', '2024-06-05 10:00:00'),
+            (gen_random_uuid(), v_user_id, v_mpesa_id, 2500.00, 'expense', 'Rent', 'Bank Transfer', 'Utilities', 'Completed', NULL, NULL, '2024-06-10 09:00:00', '2024-06-10 09:00:00', '2024-06-10 09:00:00'),
+            (gen_random_uuid(), v_user_id, v_cash_id, 1500.00, 'expense', 'Groceries', 'Cash', 'Food & Groceries', 'Completed', NULL, NULL, '2024-06-15 14:00:00', '2024-06-15 14:00:00', '2024-06-15 14:00:00'),
+            (gen_random_uuid(), v_user_id, v_equity_id, 2000.00, 'expense', 'Various Expenses', 'VISA **3254', 'Shopping', 'Completed', NULL, NULL, '2024-06-20 16:00:00', '2024-06-20 16:00:00', '2024-06-20 16:00:00')
         ON CONFLICT DO NOTHING;
     END;
 END $$;
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
 )
 
 func TestBudgetIntegration_CreateBudget(t *testing.T) {
@@ -41,8 +42,8 @@ func TestBudgetIntegration_CreateBudget(t *testing.T) {
 				if err := testDB.Where("user_id = ? AND category = ?", user.ID, "Food & Groceries").First(&budget).Error; err != nil {
 					t.Errorf("Budget not found in database: %v", err)
 				}
-				if budget.LimitAmount != 15000.00 {
-					t.Errorf("Expected limit 15000.00, got %f", budget.LimitAmount)
+				if budget.LimitAmount != money.FromMajor(15000) {
+					t.Errorf("Expected limit 15000.00, got %v", budget.LimitAmount)
 				}
 				if budget.AlertThreshold != 80 {
 					t.Errorf("Expected alert threshold 80, got %d", budget.AlertThreshold)
@@ -62,7 +63,7 @@ func TestBudgetIntegration_CreateBudget(t *testing.T) {
 				existingBudget := &models.Budget{
 					UserID:      user.ID,
 					Category:    "Transport",
-					LimitAmount: 5000.00,
+					LimitAmount: money.FromMajor(5000),
 					Color:       "#3B82F6",
 				}
 				testDB.Create(existingBudget)
@@ -120,19 +121,19 @@ func TestBudgetIntegration_ListBudgets(t *testing.T) {
 		{
 			UserID:      user.ID,
 			Category:    "Food",
-			LimitAmount: 15000.00,
+			LimitAmount: money.FromMajor(15000),
 			Color:       "#10B981",
 		},
 		{
 			UserID:      user.ID,
 			Category:    "Transport",
-			LimitAmount: 5000.00,
+			LimitAmount: money.FromMajor(5000),
 			Color:       "#3B82F6",
 		},
 		{
 			UserID:      user.ID,
 			Category:    "Entertainment",
-			LimitAmount: 3000.00,
+			LimitAmount: money.FromMajor(3000),
 			Color:       "#8B5CF6",
 		},
 	}
@@ -174,7 +175,7 @@ func TestBudgetIntegration_GetBudget(t *testing.T) {
 	budget := models.Budget{
 		UserID:      user.ID,
 		Category:    "Shopping",
-		LimitAmount: 10000.00,
+		LimitAmount: money.FromMajor(10000),
 		Color:       "#10B981",
 	}
 	testDB.Create(&budget)
@@ -244,7 +245,7 @@ func TestBudgetIntegration_UpdateBudget(t *testing.T) {
 	budget := models.Budget{
 		UserID:         user.ID,
 		Category:       "Utilities",
-		LimitAmount:    8000.00,
+		LimitAmount:    money.FromMajor(8000),
 		AlertThreshold: 75,
 		Color:          "#F59E0B",
 	}
@@ -273,8 +274,8 @@ func TestBudgetIntegration_UpdateBudget(t *testing.T) {
 				// Verify budget was updated in database
 				var updatedBudget models.Budget
 				testDB.First(&updatedBudget, budget.ID)
-				if updatedBudget.LimitAmount != 10000.00 {
-					t.Errorf("Expected limit 10000.00, got %f", updatedBudget.LimitAmount)
+				if updatedBudget.LimitAmount != money.FromMajor(10000) {
+					t.Errorf("Expected limit 10000.00, got %v", updatedBudget.LimitAmount)
 				}
 				if updatedBudget.AlertThreshold != 85 {
 					t.Errorf("Expected alert threshold 85, got %d", updatedBudget.AlertThreshold)
@@ -328,19 +329,19 @@ func TestBudgetIntegration_GetBudgetSummary(t *testing.T) {
 		{
 			UserID:      user.ID,
 			Category:    "Food",
-			LimitAmount: 15000.00,
+			LimitAmount: money.FromMajor(15000),
 			Color:       "#10B981",
 		},
 		{
 			UserID:      user.ID,
 			Category:    "Transport",
-			LimitAmount: 5000.00,
+			LimitAmount: money.FromMajor(5000),
 			Color:       "#3B82F6",
 		},
 		{
 			UserID:      user.ID,
 			Category:    "Entertainment",
-			LimitAmount: 3000.00,
+			LimitAmount: money.FromMajor(3000),
 			Color:       "#8B5CF6",
 		},
 	}
@@ -404,7 +405,7 @@ func TestBudgetIntegration_DeleteBudget(t *testing.T) {
 	budget := models.Budget{
 		UserID:      user.ID,
 		Category:    "To Delete",
-		LimitAmount: 5000.00,
+		LimitAmount: money.FromMajor(5000),
 		Color:       "#EF4444",
 	}
 	testDB.Create(&budget)
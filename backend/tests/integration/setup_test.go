@@ -12,9 +12,12 @@ import (
 	"github.com/nyunja/fity-budget-backend/internal/api/handlers"
 	"github.com/nyunja/fity-budget-backend/internal/api/routes"
 	"github.com/nyunja/fity-budget-backend/internal/config"
+	"github.com/nyunja/fity-budget-backend/internal/database"
 	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
 	"github.com/nyunja/fity-budget-backend/internal/repository"
 	"github.com/nyunja/fity-budget-backend/internal/services"
+	"github.com/nyunja/fity-budget-backend/internal/storage"
 	"github.com/nyunja/fity-budget-backend/internal/utils"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -88,13 +91,7 @@ func setup() {
 	}
 
 	// Run migrations
-	err = testDB.AutoMigrate(
-		&models.User{},
-		&models.Wallet{},
-		&models.Transaction{},
-		&models.SavingGoal{},
-		&models.Budget{},
-	)
+	err = database.Migrate(testDB)
 	if err != nil {
 		log.Fatalf("Failed to run migrations: %v", err)
 	}
@@ -104,16 +101,51 @@ func setup() {
 	transactionRepo := repository.NewTransactionRepository(testDB)
 	goalRepo := repository.NewGoalRepository(testDB)
 	budgetRepo := repository.NewBudgetRepository(testDB)
+	budgetLimitRepo := repository.NewBudgetLimitRepository(testDB)
+	budgetLedgerRepo := repository.NewBudgetLedgerRepository(testDB)
+	envelopeAllocationRepo := repository.NewEnvelopeAllocationRepository(testDB)
+	budgetTemplateRepo := repository.NewBudgetTemplateRepository(testDB)
 	walletRepo := repository.NewWalletRepository(testDB)
+	transferRepo := repository.NewTransferRepository(testDB)
+	exchangeRateRepo := repository.NewExchangeRateRepository(testDB)
+	importJobRepo := repository.NewImportJobRepository(testDB)
+	recurringRepo := repository.NewRecurringTransactionRepository(testDB)
+	categoryRepo := repository.NewCategoryRepository(testDB)
+	tagRepo := repository.NewTagRepository(testDB)
+	ruleRepo := repository.NewRuleRepository(testDB)
+	receiptRepo := repository.NewReceiptRepository(testDB)
+	txManager := repository.NewTxManager(testDB)
+
+	// Receipts are stored in a temporary directory
+	storageDir, err := os.MkdirTemp("", "fity-budget-receipts")
+	if err != nil {
+		log.Fatalf("Failed to create storage directory: %v", err)
+	}
+	receiptStorage, err := storage.NewLocalStorage(storageDir)
+	if err != nil {
+		log.Fatalf("Failed to initialize file storage: %v", err)
+	}
 
 	// Initialize services
 	jwtExpiry, _ := time.ParseDuration(testConfig.JWT.Expiry)
 	authService := services.NewAuthService(userRepo, walletRepo, testConfig.JWT.Secret, jwtExpiry)
-	transactionService := services.NewTransactionService(transactionRepo, walletRepo)
+	transactionService := services.NewTransactionService(transactionRepo, walletRepo, userRepo, exchangeRateRepo, ruleRepo, tagRepo, txManager)
 	goalService := services.NewGoalService(goalRepo)
-	budgetService := services.NewBudgetService(budgetRepo, transactionRepo)
-	walletService := services.NewWalletService(walletRepo)
-	analyticsService := services.NewAnalyticsService(transactionRepo, walletRepo, budgetRepo, goalRepo)
+	budgetService := services.NewBudgetService(budgetRepo, budgetLimitRepo, budgetLedgerRepo, transactionRepo, categoryRepo, userRepo, walletRepo, exchangeRateRepo, txManager)
+	walletService := services.NewWalletService(walletRepo, transactionRepo, transferRepo, exchangeRateRepo, txManager)
+	analyticsService := services.NewAnalyticsService(transactionRepo, walletRepo, budgetRepo, goalRepo, userRepo, exchangeRateRepo, categoryRepo, tagRepo)
+	exchangeRateService := services.NewExchangeRateService(exchangeRateRepo)
+	importService := services.NewImportService(importJobRepo, transactionRepo, walletRepo, ruleRepo, txManager)
+	recurringService := services.NewRecurringTransactionService(recurringRepo, transactionRepo, walletRepo, txManager)
+	categoryService := services.NewCategoryService(categoryRepo, budgetRepo, budgetLimitRepo, txManager)
+	ruleService := services.NewRuleService(ruleRepo, tagRepo, transactionRepo, txManager)
+	tagService := services.NewTagService(tagRepo, txManager)
+	receiptService := services.NewReceiptService(receiptRepo, transactionRepo, receiptStorage)
+	duplicateService := services.NewDuplicateService(transactionRepo, walletRepo, receiptRepo, txManager)
+	bulkTransactionService := services.NewBulkTransactionService(transactionRepo, walletRepo, tagRepo, txManager)
+	envelopeService := services.NewEnvelopeService(budgetRepo, envelopeAllocationRepo, walletRepo, transactionRepo, categoryRepo, userRepo, exchangeRateRepo, txManager)
+	budgetTemplateService := services.NewBudgetTemplateService(budgetTemplateRepo, budgetRepo, userRepo, categoryRepo, budgetService, txManager)
+	trashService := services.NewTrashService(transactionRepo, budgetRepo, goalRepo, walletRepo, importJobRepo, receiptService, txManager, 30*24*time.Hour)
 
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler(authService)
@@ -122,6 +154,18 @@ func setup() {
 	budgetHandler := handlers.NewBudgetHandler(budgetService)
 	walletHandler := handlers.NewWalletHandler(walletService)
 	analyticsHandler := handlers.NewAnalyticsHandler(analyticsService)
+	exchangeRateHandler := handlers.NewExchangeRateHandler(exchangeRateService)
+	importHandler := handlers.NewImportHandler(importService)
+	recurringHandler := handlers.NewRecurringHandler(recurringService)
+	categoryHandler := handlers.NewCategoryHandler(categoryService)
+	ruleHandler := handlers.NewRuleHandler(ruleService)
+	tagHandler := handlers.NewTagHandler(tagService)
+	receiptHandler := handlers.NewReceiptHandler(receiptService)
+	duplicateHandler := handlers.NewDuplicateHandler(duplicateService)
+	trashHandler := handlers.NewTrashHandler(trashService)
+	bulkTransactionHandler := handlers.NewBulkTransactionHandler(bulkTransactionService)
+	envelopeHandler := handlers.NewEnvelopeHandler(envelopeService)
+	budgetTemplateHandler := handlers.NewBudgetTemplateHandler(budgetTemplateService)
 
 	// Setup router
 	testRouter = gin.New()
@@ -134,6 +178,18 @@ func setup() {
 		budgetHandler,
 		walletHandler,
 		analyticsHandler,
+		exchangeRateHandler,
+		importHandler,
+		recurringHandler,
+		categoryHandler,
+		ruleHandler,
+		tagHandler,
+		receiptHandler,
+		duplicateHandler,
+		trashHandler,
+		bulkTransactionHandler,
+		envelopeHandler,
+		budgetTemplateHandler,
 	)
 
 	log.Println("Test setup completed successfully")
@@ -154,11 +210,27 @@ func teardown() {
 
 // cleanDatabase removes all data from tables
 func cleanDatabase() {
+	testDB.Exec("TRUNCATE TABLE import_rows CASCADE")
+	testDB.Exec("TRUNCATE TABLE import_jobs CASCADE")
+	testDB.Exec("TRUNCATE TABLE recurring_transactions CASCADE")
+	testDB.Exec("TRUNCATE TABLE categories CASCADE")
+	testDB.Exec("TRUNCATE TABLE rule_tags CASCADE")
+	testDB.Exec("TRUNCATE TABLE rules CASCADE")
+	testDB.Exec("TRUNCATE TABLE transaction_tags CASCADE")
+	testDB.Exec("TRUNCATE TABLE tags CASCADE")
+	testDB.Exec("TRUNCATE TABLE receipts CASCADE")
+	testDB.Exec("TRUNCATE TABLE transaction_splits CASCADE")
 	testDB.Exec("TRUNCATE TABLE transactions CASCADE")
 	testDB.Exec("TRUNCATE TABLE saving_goals CASCADE")
+	testDB.Exec("TRUNCATE TABLE budget_template_lines CASCADE")
+	testDB.Exec("TRUNCATE TABLE budget_templates CASCADE")
+	testDB.Exec("TRUNCATE TABLE envelope_allocations CASCADE")
+	testDB.Exec("TRUNCATE TABLE budget_ledger_entries CASCADE")
+	testDB.Exec("TRUNCATE TABLE budget_limits CASCADE")
 	testDB.Exec("TRUNCATE TABLE budgets CASCADE")
 	testDB.Exec("TRUNCATE TABLE wallets CASCADE")
 	testDB.Exec("TRUNCATE TABLE users CASCADE")
+	testDB.Exec("TRUNCATE TABLE exchange_rates")
 }
 
 // cleanDatabaseForTest cleans the database before each test
@@ -194,7 +266,7 @@ func createTestUser(t *testing.T, email string) (*models.User, string) {
 }
 
 // createTestWallet creates a test wallet for a user
-func createTestWallet(t *testing.T, userID uuid.UUID, name string, balance float64) *models.Wallet {
+func createTestWallet(t *testing.T, userID uuid.UUID, name string, balance money.Amount) *models.Wallet {
 	t.Helper()
 
 	wallet := &models.Wallet{
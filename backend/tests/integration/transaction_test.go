@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
 )
 
 func TestTransactionIntegration_CreateTransaction(t *testing.T) {
@@ -17,7 +18,7 @@ func TestTransactionIntegration_CreateTransaction(t *testing.T) {
 
 	// Create test user and wallet
 	user, token := createTestUser(t, "trans@example.com")
-	wallet := createTestWallet(t, user.ID, "Main Wallet", 10000.00)
+	wallet := createTestWallet(t, user.ID, "Main Wallet", money.FromMajor(10000))
 
 	tests := []struct {
 		name           string
@@ -46,16 +47,16 @@ func TestTransactionIntegration_CreateTransaction(t *testing.T) {
 				if err := testDB.Where("user_id = ?", user.ID).First(&transaction).Error; err != nil {
 					t.Errorf("Transaction not found in database: %v", err)
 				}
-				if transaction.Amount != 500.00 {
-					t.Errorf("Expected amount 500.00, got %f", transaction.Amount)
+				if transaction.Amount != money.FromMajor(500) {
+					t.Errorf("Expected amount 500.00, got %v", transaction.Amount)
 				}
 
 				// Verify wallet balance was updated
 				var updatedWallet models.Wallet
 				testDB.First(&updatedWallet, wallet.ID)
-				expectedBalance := 10000.00 - 500.00
+				expectedBalance := money.FromMajor(10000 - 500)
 				if updatedWallet.Balance != expectedBalance {
-					t.Errorf("Expected wallet balance %f, got %f", expectedBalance, updatedWallet.Balance)
+					t.Errorf("Expected wallet balance %v, got %v", expectedBalance, updatedWallet.Balance)
 				}
 			},
 		},
@@ -78,7 +79,7 @@ func TestTransactionIntegration_CreateTransaction(t *testing.T) {
 				// Verify wallet balance increased
 				var updatedWallet models.Wallet
 				testDB.First(&updatedWallet, wallet.ID)
-				if updatedWallet.Balance <= 10000.00 {
+				if updatedWallet.Balance <= money.FromMajor(10000) {
 					t.Error("Expected wallet balance to increase")
 				}
 			},
@@ -147,14 +148,14 @@ func TestTransactionIntegration_ListTransactions(t *testing.T) {
 
 	// Create test user and wallet
 	user, token := createTestUser(t, "list@example.com")
-	wallet := createTestWallet(t, user.ID, "Main Wallet", 10000.00)
+	wallet := createTestWallet(t, user.ID, "Main Wallet", money.FromMajor(10000))
 
 	// Create some test transactions
 	transactions := []models.Transaction{
 		{
 			UserID:          user.ID,
 			WalletID:        &wallet.ID,
-			Amount:          500.00,
+			Amount:          money.FromMajor(500),
 			Name:            "Groceries",
 			Method:          "Cash",
 			Category:        "Food",
@@ -164,7 +165,7 @@ func TestTransactionIntegration_ListTransactions(t *testing.T) {
 		{
 			UserID:          user.ID,
 			WalletID:        &wallet.ID,
-			Amount:          3000.00,
+			Amount:          money.FromMajor(3000),
 			Name:            "Monthly salary",
 			Method:          "Bank Transfer",
 			Category:        "Salary",
@@ -174,7 +175,7 @@ func TestTransactionIntegration_ListTransactions(t *testing.T) {
 		{
 			UserID:          user.ID,
 			WalletID:        &wallet.ID,
-			Amount:          200.00,
+			Amount:          money.FromMajor(200),
 			Name:            "Uber ride",
 			Method:          "Credit Card",
 			Category:        "Transport",
@@ -274,13 +275,13 @@ func TestTransactionIntegration_GetTransaction(t *testing.T) {
 
 	// Create test user and wallet
 	user, token := createTestUser(t, "get@example.com")
-	wallet := createTestWallet(t, user.ID, "Main Wallet", 10000.00)
+	wallet := createTestWallet(t, user.ID, "Main Wallet", money.FromMajor(10000))
 
 	// Create a test transaction
 	transaction := models.Transaction{
 		UserID:          user.ID,
 		WalletID:        &wallet.ID,
-		Amount:          500.00,
+		Amount:          money.FromMajor(500),
 		Name:            "Test transaction",
 		Method:          "Cash",
 		Category:        "Food",
@@ -363,13 +364,13 @@ func TestTransactionIntegration_UpdateTransaction(t *testing.T) {
 
 	// Create test user and wallet
 	user, token := createTestUser(t, "update@example.com")
-	wallet := createTestWallet(t, user.ID, "Main Wallet", 10000.00)
+	wallet := createTestWallet(t, user.ID, "Main Wallet", money.FromMajor(10000))
 
 	// Create a test transaction
 	transaction := models.Transaction{
 		UserID:          user.ID,
 		WalletID:        &wallet.ID,
-		Amount:          500.00,
+		Amount:          money.FromMajor(500),
 		Name:            "Original transaction",
 		Method:          "Cash",
 		Category:        "Food",
@@ -404,8 +405,8 @@ func TestTransactionIntegration_UpdateTransaction(t *testing.T) {
 				if updatedTransaction.Notes != "Updated description" {
 					t.Errorf("Expected notes 'Updated description', got '%s'", updatedTransaction.Notes)
 				}
-				if updatedTransaction.Amount != 600.00 {
-					t.Errorf("Expected amount 600.00, got %f", updatedTransaction.Amount)
+				if updatedTransaction.Amount != money.FromMajor(600) {
+					t.Errorf("Expected amount 600.00, got %v", updatedTransaction.Amount)
 				}
 			},
 		},
@@ -457,13 +458,13 @@ func TestTransactionIntegration_DeleteTransaction(t *testing.T) {
 
 	// Create test user and wallet
 	user, token := createTestUser(t, "delete@example.com")
-	wallet := createTestWallet(t, user.ID, "Main Wallet", 10000.00)
+	wallet := createTestWallet(t, user.ID, "Main Wallet", money.FromMajor(10000))
 
 	// Create a test transaction
 	transaction := models.Transaction{
 		UserID:          user.ID,
 		WalletID:        &wallet.ID,
-		Amount:          500.00,
+		Amount:          money.FromMajor(500),
 		Name:            "To be deleted",
 		Method:          "Cash",
 		Category:        "Food",
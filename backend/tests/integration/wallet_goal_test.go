@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
 )
 
 // Wallet Integration Tests
@@ -45,8 +46,8 @@ func TestWalletIntegration_CreateWallet(t *testing.T) {
 				if err := testDB.Where("user_id = ? AND name = ?", user.ID, "M-PESA").First(&wallet).Error; err != nil {
 					t.Errorf("Wallet not found in database: %v", err)
 				}
-				if wallet.Balance != 5000.00 {
-					t.Errorf("Expected balance 5000.00, got %f", wallet.Balance)
+				if wallet.Balance != money.FromMajor(5000) {
+					t.Errorf("Expected balance 5000.00, got %v", wallet.Balance)
 				}
 			},
 		},
@@ -91,9 +92,9 @@ func TestWalletIntegration_ListWallets(t *testing.T) {
 	user, token := createTestUser(t, "listwallet@example.com")
 
 	// Create multiple wallets
-	createTestWallet(t, user.ID, "Bank Account", 10000.00)
-	createTestWallet(t, user.ID, "M-PESA", 5000.00)
-	createTestWallet(t, user.ID, "Cash", 1000.00)
+	createTestWallet(t, user.ID, "Bank Account", money.FromMajor(10000))
+	createTestWallet(t, user.ID, "M-PESA", money.FromMajor(5000))
+	createTestWallet(t, user.ID, "Cash", money.FromMajor(1000))
 
 	req, _ := http.NewRequest("GET", "/api/v1/wallets", nil)
 	req.Header.Set("Authorization", "Bearer "+token)
@@ -123,7 +124,7 @@ func TestWalletIntegration_GetWallet(t *testing.T) {
 	cleanDatabaseForTest(t)
 
 	user, token := createTestUser(t, "getwallet@example.com")
-	wallet := createTestWallet(t, user.ID, "Test Wallet", 5000.00)
+	wallet := createTestWallet(t, user.ID, "Test Wallet", money.FromMajor(5000))
 
 	tests := []struct {
 		name           string
@@ -211,11 +212,11 @@ func TestGoalIntegration_CreateGoal(t *testing.T) {
 				if err := testDB.Where("user_id = ? AND name = ?", user.ID, "New Laptop").First(&goal).Error; err != nil {
 					t.Errorf("Goal not found in database: %v", err)
 				}
-				if goal.TargetAmount != 50000.00 {
-					t.Errorf("Expected target 50000.00, got %f", goal.TargetAmount)
+				if goal.TargetAmount != money.FromMajor(50000) {
+					t.Errorf("Expected target 50000.00, got %v", goal.TargetAmount)
 				}
 				if goal.CurrentAmount != 0 {
-					t.Errorf("Expected current amount 0, got %f", goal.CurrentAmount)
+					t.Errorf("Expected current amount 0, got %v", goal.CurrentAmount)
 				}
 			},
 		},
@@ -269,16 +270,16 @@ func TestGoalIntegration_ListGoals(t *testing.T) {
 		{
 			UserID:        user.ID,
 			Name:          "Laptop",
-			TargetAmount:  50000.00,
-			CurrentAmount: 10000.00,
+			TargetAmount:  money.FromMajor(50000),
+			CurrentAmount: money.FromMajor(10000),
 			Deadline:      &deadline1,
 			Status:        "in_progress",
 		},
 		{
 			UserID:        user.ID,
 			Name:          "Vacation",
-			TargetAmount:  30000.00,
-			CurrentAmount: 5000.00,
+			TargetAmount:  money.FromMajor(30000),
+			CurrentAmount: money.FromMajor(5000),
 			Deadline:      &deadline2,
 			Status:        "in_progress",
 		},
@@ -322,8 +323,8 @@ func TestGoalIntegration_UpdateProgress(t *testing.T) {
 	goal := models.SavingGoal{
 		UserID:        user.ID,
 		Name:          "Test Goal",
-		TargetAmount:  10000.00,
-		CurrentAmount: 2000.00,
+		TargetAmount:  money.FromMajor(10000),
+		CurrentAmount: money.FromMajor(2000),
 		Deadline:      &deadline,
 		Status:        "in_progress",
 	}
@@ -349,9 +350,9 @@ func TestGoalIntegration_UpdateProgress(t *testing.T) {
 				// Verify goal progress was updated
 				var updatedGoal models.SavingGoal
 				testDB.First(&updatedGoal, goal.ID)
-				expectedAmount := 2000.00 + 1000.00
+				expectedAmount := money.FromMajor(2000 + 1000)
 				if updatedGoal.CurrentAmount != expectedAmount {
-					t.Errorf("Expected current amount %f, got %f", expectedAmount, updatedGoal.CurrentAmount)
+					t.Errorf("Expected current amount %v, got %v", expectedAmount, updatedGoal.CurrentAmount)
 				}
 			},
 		},
@@ -401,8 +402,8 @@ func TestGoalIntegration_DeleteGoal(t *testing.T) {
 	goal := models.SavingGoal{
 		UserID:        user.ID,
 		Name:          "To Delete",
-		TargetAmount:  5000.00,
-		CurrentAmount: 1000.00,
+		TargetAmount:  money.FromMajor(5000),
+		CurrentAmount: money.FromMajor(1000),
 		Deadline:      &deadline,
 		Status:        "in_progress",
 	}
@@ -10,6 +10,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/nyunja/fity-budget-backend/internal/api/handlers"
 	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
 	"github.com/nyunja/fity-budget-backend/tests/unit/mocks"
 	"github.com/nyunja/fity-budget-backend/tests/unit/testutils"
 )
@@ -458,7 +459,7 @@ func TestAuthHandler_CompleteOnboarding(t *testing.T) {
 				c.Set("userID", testutils.TestUserID)
 			},
 			mockSetup: func(m *mocks.MockAuthService) {
-				m.CompleteOnboardingFunc = func(id uuid.UUID, monthlyIncome float64, currency string) error {
+				m.CompleteOnboardingFunc = func(id uuid.UUID, monthlyIncome money.Amount, currency string) error {
 					return nil
 				}
 			},
@@ -479,7 +480,7 @@ func TestAuthHandler_CompleteOnboarding(t *testing.T) {
 				c.Set("userID", testutils.TestUserID)
 			},
 			mockSetup: func(m *mocks.MockAuthService) {
-				m.CompleteOnboardingFunc = func(id uuid.UUID, monthlyIncome float64, currency string) error {
+				m.CompleteOnboardingFunc = func(id uuid.UUID, monthlyIncome money.Amount, currency string) error {
 					return errors.New("user not found")
 				}
 			},
@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/api/handlers"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+	"github.com/nyunja/fity-budget-backend/tests/unit/mocks"
+	"github.com/nyunja/fity-budget-backend/tests/unit/testutils"
+)
+
+func TestBudgetTemplateHandler_CreateBudgetTemplate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		body           map[string]interface{}
+		mockSetup      func(*mocks.MockBudgetTemplateService)
+		expectedStatus int
+	}{
+		{
+			name: "save a template",
+			body: map[string]interface{}{"name": "Family", "lines": []map[string]interface{}{
+				{"category": "Rent", "percentage": 40}, {"category": "Food", "percentage": 25.5},
+			}},
+			mockSetup: func(m *mocks.MockBudgetTemplateService) {
+				m.CreateTemplateFunc = func(userID uuid.UUID, req services.CreateBudgetTemplateRequest) (*models.BudgetTemplate, error) {
+					if req.Name != "Family" || len(req.Lines) != 2 || req.Lines[1].Percentage != 25.5 {
+						t.Errorf("unexpected service request %+v", req)
+					}
+					return &models.BudgetTemplate{ID: uuid.New(), UserID: userID, Name: req.Name}, nil
+				}
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "no lines",
+			body:           map[string]interface{}{"name": "Family"},
+			mockSetup:      func(m *mocks.MockBudgetTemplateService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "percentage over 100",
+			body: map[string]interface{}{"name": "Family", "lines": []map[string]interface{}{
+				{"category": "Rent", "percentage": 140},
+			}},
+			mockSetup:      func(m *mocks.MockBudgetTemplateService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "lines add up to more than 100",
+			body: map[string]interface{}{"name": "Family", "lines": []map[string]interface{}{
+				{"category": "Rent", "percentage": 60}, {"category": "Food", "percentage": 60},
+			}},
+			mockSetup: func(m *mocks.MockBudgetTemplateService) {
+				m.CreateTemplateFunc = func(userID uuid.UUID, req services.CreateBudgetTemplateRequest) (*models.BudgetTemplate, error) {
+					return nil, errors.New("percentages add up to 120.00, more than 100")
+				}
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.MockBudgetTemplateService{}
+			tt.mockSetup(mockService)
+			handler := handlers.NewBudgetTemplateHandler(mockService)
+
+			router := testutils.SetupTestRouter()
+			router.POST("/budget-templates", func(c *gin.Context) {
+				c.Set("userID", testutils.TestUserID)
+				handler.CreateBudgetTemplate(c)
+			})
+
+			w := testutils.MakeRequest(router, "POST", "/budget-templates", tt.body, nil)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestBudgetTemplateHandler_PreviewBudgets(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		body           map[string]interface{}
+		mockSetup      func(*mocks.MockBudgetTemplateService)
+		expectedStatus int
+	}{
+		{
+			name: "built-in template",
+			body: map[string]interface{}{"template": "50-30-20", "income": 1000, "groups": map[string][]string{"needs": {"Rent", "Food"}}},
+			mockSetup: func(m *mocks.MockBudgetTemplateService) {
+				m.PreviewBudgetsFunc = func(userID uuid.UUID, req services.GenerateBudgetsRequest) (*services.BudgetPlan, error) {
+					if req.Template != "50-30-20" || req.Income != money.FromMajor(1000) || len(req.Groups["needs"]) != 2 {
+						t.Errorf("unexpected service request %+v", req)
+					}
+					return &services.BudgetPlan{Income: req.Income}, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "unknown template",
+			body:           map[string]interface{}{"template": "60-40"},
+			mockSetup:      func(m *mocks.MockBudgetTemplateService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "no income",
+			body: map[string]interface{}{"template": "70-20-10"},
+			mockSetup: func(m *mocks.MockBudgetTemplateService) {
+				m.PreviewBudgetsFunc = func(userID uuid.UUID, req services.GenerateBudgetsRequest) (*services.BudgetPlan, error) {
+					return nil, errors.New("income must be greater than zero; pass an income or set a monthly income")
+				}
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.MockBudgetTemplateService{}
+			tt.mockSetup(mockService)
+			handler := handlers.NewBudgetTemplateHandler(mockService)
+
+			router := testutils.SetupTestRouter()
+			router.POST("/budget-templates/preview", func(c *gin.Context) {
+				c.Set("userID", testutils.TestUserID)
+				handler.PreviewBudgets(c)
+			})
+
+			w := testutils.MakeRequest(router, "POST", "/budget-templates/preview", tt.body, nil)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestBudgetTemplateHandler_RescaleBudgets(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		body           map[string]interface{}
+		mockSetup      func(*mocks.MockBudgetTemplateService)
+		expectedStatus int
+	}{
+		{
+			name: "new income",
+			body: map[string]interface{}{"income": 1500},
+			mockSetup: func(m *mocks.MockBudgetTemplateService) {
+				m.RescaleBudgetsFunc = func(userID uuid.UUID, income money.Amount) (*services.BudgetPlan, error) {
+					if income != money.FromMajor(1500) {
+						t.Errorf("Expected an income of 1500, got %v", income)
+					}
+					return &services.BudgetPlan{Income: income}, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing income",
+			body:           map[string]interface{}{},
+			mockSetup:      func(m *mocks.MockBudgetTemplateService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "no income on record",
+			body: map[string]interface{}{"income": 1500},
+			mockSetup: func(m *mocks.MockBudgetTemplateService) {
+				m.RescaleBudgetsFunc = func(userID uuid.UUID, income money.Amount) (*services.BudgetPlan, error) {
+					return nil, errors.New("no monthly income on record to rescale from")
+				}
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.MockBudgetTemplateService{}
+			tt.mockSetup(mockService)
+			handler := handlers.NewBudgetTemplateHandler(mockService)
+
+			router := testutils.SetupTestRouter()
+			router.POST("/budgets/rescale", func(c *gin.Context) {
+				c.Set("userID", testutils.TestUserID)
+				handler.RescaleBudgets(c)
+			})
+
+			w := testutils.MakeRequest(router, "POST", "/budgets/rescale", tt.body, nil)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/api/handlers"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+	"github.com/nyunja/fity-budget-backend/tests/unit/mocks"
+	"github.com/nyunja/fity-budget-backend/tests/unit/testutils"
+)
+
+func TestBulkTransactionHandler_BulkUpdateTransactions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	transactionID := uuid.New()
+
+	tests := []struct {
+		name           string
+		body           map[string]interface{}
+		mockSetup      func(*mocks.MockBulkTransactionService)
+		expectedStatus int
+	}{
+		{
+			name: "recategorize by ids",
+			body: map[string]interface{}{
+				"ids":       []string{transactionID.String()},
+				"operation": "recategorize",
+				"category":  "Groceries",
+			},
+			mockSetup: func(m *mocks.MockBulkTransactionService) {
+				m.BulkUpdateFunc = func(userID uuid.UUID, req services.BulkTransactionRequest) (*services.BulkTransactionResult, error) {
+					if len(req.IDs) != 1 || req.IDs[0] != transactionID || req.Category != "Groceries" || req.Filter != nil {
+						t.Errorf("unexpected service request %+v", req)
+					}
+					return &services.BulkTransactionResult{Operation: req.Operation, Matched: 1, Succeeded: 1}, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "delete by filter",
+			body: map[string]interface{}{
+				"filter":    map[string]interface{}{"category": []string{"Food"}, "start_date": "2026-03-01", "end_date": "2026-03-31"},
+				"operation": "delete",
+			},
+			mockSetup: func(m *mocks.MockBulkTransactionService) {
+				m.BulkUpdateFunc = func(userID uuid.UUID, req services.BulkTransactionRequest) (*services.BulkTransactionResult, error) {
+					if req.Filter == nil || len(req.Filter.Categories) != 1 || req.Filter.EndDate.Day() != 1 || req.Filter.EndDate.Month() != 4 {
+						t.Errorf("unexpected service request %+v", req)
+					}
+					return &services.BulkTransactionResult{Operation: req.Operation}, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "unknown operation",
+			body:           map[string]interface{}{"ids": []string{transactionID.String()}, "operation": "archive"},
+			mockSetup:      func(m *mocks.MockBulkTransactionService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid filter date",
+			body:           map[string]interface{}{"filter": map[string]interface{}{"start_date": "03/01/2026"}, "operation": "delete"},
+			mockSetup:      func(m *mocks.MockBulkTransactionService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "filter matches too many",
+			body: map[string]interface{}{"filter": map[string]interface{}{}, "operation": "delete"},
+			mockSetup: func(m *mocks.MockBulkTransactionService) {
+				m.BulkUpdateFunc = func(userID uuid.UUID, req services.BulkTransactionRequest) (*services.BulkTransactionResult, error) {
+					return nil, errors.New("filter matches 5000 transactions, more than 1000; narrow the filter")
+				}
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.MockBulkTransactionService{}
+			tt.mockSetup(mockService)
+			handler := handlers.NewBulkTransactionHandler(mockService)
+
+			router := testutils.SetupTestRouter()
+			router.POST("/transactions/bulk", func(c *gin.Context) {
+				c.Set("userID", testutils.TestUserID)
+				handler.BulkUpdateTransactions(c)
+			})
+
+			w := testutils.MakeRequest(router, "POST", "/transactions/bulk", tt.body, nil)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
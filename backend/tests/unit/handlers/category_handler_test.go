@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/api/handlers"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+	"github.com/nyunja/fity-budget-backend/tests/unit/mocks"
+	"github.com/nyunja/fity-budget-backend/tests/unit/testutils"
+)
+
+func TestCategoryHandler_CreateCategory(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	parentID := uuid.New()
+
+	tests := []struct {
+		name           string
+		body           map[string]interface{}
+		mockSetup      func(*mocks.MockCategoryService)
+		expectedStatus int
+	}{
+		{
+			name: "successful create",
+			body: map[string]interface{}{
+				"name":      "Groceries",
+				"parent_id": parentID,
+				"icon":      "cart",
+				"color":     "#22C55E",
+			},
+			mockSetup: func(m *mocks.MockCategoryService) {
+				m.CreateCategoryFunc = func(userID uuid.UUID, req services.CreateCategoryRequest) (*models.Category, error) {
+					if req.ParentID == nil || *req.ParentID != parentID || req.Icon != "cart" {
+						t.Errorf("unexpected service request %+v", req)
+					}
+					return &models.Category{ID: uuid.New(), UserID: userID, ParentID: req.ParentID, Name: req.Name, Kind: models.CategoryKindExpense}, nil
+				}
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "missing name",
+			body:           map[string]interface{}{"kind": "expense"},
+			mockSetup:      func(m *mocks.MockCategoryService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown kind",
+			body:           map[string]interface{}{"name": "Savings", "kind": "transfer"},
+			mockSetup:      func(m *mocks.MockCategoryService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "duplicate category",
+			body: map[string]interface{}{"name": "Food"},
+			mockSetup: func(m *mocks.MockCategoryService) {
+				m.CreateCategoryFunc = func(userID uuid.UUID, req services.CreateCategoryRequest) (*models.Category, error) {
+					return nil, errors.New("category already exists")
+				}
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.MockCategoryService{}
+			tt.mockSetup(mockService)
+			handler := handlers.NewCategoryHandler(mockService)
+
+			router := testutils.SetupTestRouter()
+			router.POST("/categories", func(c *gin.Context) {
+				c.Set("userID", testutils.TestUserID)
+				handler.CreateCategory(c)
+			})
+
+			w := testutils.MakeRequest(router, "POST", "/categories", tt.body, nil)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestCategoryHandler_MergeCategory(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	sourceID := uuid.New()
+	targetID := uuid.New()
+
+	tests := []struct {
+		name           string
+		path           string
+		body           map[string]interface{}
+		mockSetup      func(*mocks.MockCategoryService)
+		expectedStatus int
+	}{
+		{
+			name: "successful merge",
+			path: "/categories/" + sourceID.String() + "/merge",
+			body: map[string]interface{}{"target_id": targetID},
+			mockSetup: func(m *mocks.MockCategoryService) {
+				m.MergeCategoryFunc = func(id, userID, target uuid.UUID) (*models.Category, error) {
+					if id != sourceID || target != targetID {
+						t.Errorf("Expected merge of %s into %s, got %s into %s", sourceID, targetID, id, target)
+					}
+					return &models.Category{ID: target, UserID: userID, Name: "Food"}, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid ID",
+			path:           "/categories/not-a-uuid/merge",
+			body:           map[string]interface{}{"target_id": targetID},
+			mockSetup:      func(m *mocks.MockCategoryService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing target",
+			path:           "/categories/" + sourceID.String() + "/merge",
+			body:           map[string]interface{}{},
+			mockSetup:      func(m *mocks.MockCategoryService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "different kinds",
+			path: "/categories/" + sourceID.String() + "/merge",
+			body: map[string]interface{}{"target_id": targetID},
+			mockSetup: func(m *mocks.MockCategoryService) {
+				m.MergeCategoryFunc = func(id, userID, target uuid.UUID) (*models.Category, error) {
+					return nil, errors.New("cannot merge categories of different kinds")
+				}
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.MockCategoryService{}
+			tt.mockSetup(mockService)
+			handler := handlers.NewCategoryHandler(mockService)
+
+			router := testutils.SetupTestRouter()
+			router.POST("/categories/:id/merge", func(c *gin.Context) {
+				c.Set("userID", testutils.TestUserID)
+				handler.MergeCategory(c)
+			})
+
+			w := testutils.MakeRequest(router, "POST", tt.path, tt.body, nil)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestCategoryHandler_DeleteCategory(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	categoryID := uuid.New()
+
+	tests := []struct {
+		name           string
+		mockSetup      func(*mocks.MockCategoryService)
+		expectedStatus int
+	}{
+		{
+			name:           "successful delete",
+			mockSetup:      func(m *mocks.MockCategoryService) {},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "category in use",
+			mockSetup: func(m *mocks.MockCategoryService) {
+				m.DeleteCategoryFunc = func(id, userID uuid.UUID) error {
+					return errors.New("category is in use; merge it into another category instead")
+				}
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.MockCategoryService{}
+			tt.mockSetup(mockService)
+			handler := handlers.NewCategoryHandler(mockService)
+
+			router := testutils.SetupTestRouter()
+			router.DELETE("/categories/:id", func(c *gin.Context) {
+				c.Set("userID", testutils.TestUserID)
+				handler.DeleteCategory(c)
+			})
+
+			w := testutils.MakeRequest(router, "DELETE", "/categories/"+categoryID.String(), nil, nil)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/api/handlers"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+	"github.com/nyunja/fity-budget-backend/tests/unit/mocks"
+	"github.com/nyunja/fity-budget-backend/tests/unit/testutils"
+)
+
+func TestDuplicateHandler_ListDuplicates(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		queryParams    string
+		mockSetup      func(*mocks.MockDuplicateService)
+		expectedStatus int
+	}{
+		{
+			name:        "default range",
+			queryParams: "",
+			mockSetup: func(m *mocks.MockDuplicateService) {
+				m.FindDuplicatesFunc = func(userID uuid.UUID, req services.FindDuplicatesRequest) ([]*services.DuplicateGroup, error) {
+					if !req.StartDate.IsZero() || !req.EndDate.IsZero() || req.WindowDays != 0 {
+						t.Errorf("Expected the service defaults, got %+v", req)
+					}
+					return []*services.DuplicateGroup{}, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:        "range and window",
+			queryParams: "?start_date=2026-10-01&end_date=2026-10-15&window_days=5",
+			mockSetup: func(m *mocks.MockDuplicateService) {
+				m.FindDuplicatesFunc = func(userID uuid.UUID, req services.FindDuplicatesRequest) ([]*services.DuplicateGroup, error) {
+					if req.StartDate.Day() != 1 || req.EndDate.Day() != 16 || req.WindowDays != 5 {
+						t.Errorf("Expected an inclusive end date and a 5 day window, got %+v", req)
+					}
+					return []*services.DuplicateGroup{}, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid window",
+			queryParams:    "?window_days=week",
+			mockSetup:      func(m *mocks.MockDuplicateService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "range too large",
+			queryParams: "?start_date=2020-01-01",
+			mockSetup: func(m *mocks.MockDuplicateService) {
+				m.FindDuplicatesFunc = func(userID uuid.UUID, req services.FindDuplicatesRequest) ([]*services.DuplicateGroup, error) {
+					return nil, errors.New("narrow the date range")
+				}
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.MockDuplicateService{}
+			tt.mockSetup(mockService)
+			handler := handlers.NewDuplicateHandler(mockService)
+
+			router := testutils.SetupTestRouter()
+			router.GET("/transactions/duplicates", func(c *gin.Context) {
+				c.Set("userID", testutils.TestUserID)
+				handler.ListDuplicates(c)
+			})
+
+			w := testutils.MakeRequest(router, "GET", "/transactions/duplicates"+tt.queryParams, nil, nil)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestDuplicateHandler_MergeTransactions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	keptID := uuid.New()
+	duplicateID := uuid.New()
+
+	tests := []struct {
+		name           string
+		body           map[string]interface{}
+		mockSetup      func(*mocks.MockDuplicateService)
+		expectedStatus int
+	}{
+		{
+			name: "successful merge",
+			body: map[string]interface{}{"duplicate_ids": []string{duplicateID.String()}},
+			mockSetup: func(m *mocks.MockDuplicateService) {
+				m.MergeDuplicatesFunc = func(id, userID uuid.UUID, req services.MergeDuplicatesRequest) (*models.Transaction, error) {
+					if id != keptID || len(req.DuplicateIDs) != 1 || req.DuplicateIDs[0] != duplicateID {
+						t.Errorf("unexpected merge of %v into %s", req.DuplicateIDs, id)
+					}
+					return &models.Transaction{ID: id, UserID: userID}, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "no duplicates",
+			body:           map[string]interface{}{"duplicate_ids": []string{}},
+			mockSetup:      func(m *mocks.MockDuplicateService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "transfer leg",
+			body: map[string]interface{}{"duplicate_ids": []string{duplicateID.String()}},
+			mockSetup: func(m *mocks.MockDuplicateService) {
+				m.MergeDuplicatesFunc = func(id, userID uuid.UUID, req services.MergeDuplicatesRequest) (*models.Transaction, error) {
+					return nil, errors.New("transfer transactions cannot be merged")
+				}
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.MockDuplicateService{}
+			tt.mockSetup(mockService)
+			handler := handlers.NewDuplicateHandler(mockService)
+
+			router := testutils.SetupTestRouter()
+			router.POST("/transactions/:id/merge", func(c *gin.Context) {
+				c.Set("userID", testutils.TestUserID)
+				handler.MergeTransactions(c)
+			})
+
+			w := testutils.MakeRequest(router, "POST", "/transactions/"+keptID.String()+"/merge", tt.body, nil)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
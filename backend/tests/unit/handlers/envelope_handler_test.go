@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/api/handlers"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+	"github.com/nyunja/fity-budget-backend/tests/unit/mocks"
+	"github.com/nyunja/fity-budget-backend/tests/unit/testutils"
+)
+
+func TestEnvelopeHandler_GetEnvelopes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		query          string
+		wantMonth      time.Month
+		expectedStatus int
+	}{
+		{name: "requested month", query: "?month=2026-03", wantMonth: time.March, expectedStatus: http.StatusOK},
+		{name: "current month", query: "", wantMonth: time.Now().Month(), expectedStatus: http.StatusOK},
+		{name: "invalid month", query: "?month=2026-03-01", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.MockEnvelopeService{
+				GetEnvelopesFunc: func(userID uuid.UUID, month time.Time) (*services.EnvelopeMonth, error) {
+					if month.Month() != tt.wantMonth {
+						t.Errorf("Expected month %v, got %v", tt.wantMonth, month.Month())
+					}
+					return &services.EnvelopeMonth{Month: month}, nil
+				},
+			}
+			handler := handlers.NewEnvelopeHandler(mockService)
+
+			router := testutils.SetupTestRouter()
+			router.GET("/envelopes", func(c *gin.Context) {
+				c.Set("userID", testutils.TestUserID)
+				handler.GetEnvelopes(c)
+			})
+
+			w := testutils.MakeRequest(router, "GET", "/envelopes"+tt.query, nil, nil)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestEnvelopeHandler_AssignEnvelope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	budgetID := uuid.New()
+
+	tests := []struct {
+		name           string
+		body           map[string]interface{}
+		mockSetup      func(*mocks.MockEnvelopeService)
+		expectedStatus int
+	}{
+		{
+			name: "assign to an envelope",
+			body: map[string]interface{}{"budget_id": budgetID.String(), "month": "2026-02", "amount": 250},
+			mockSetup: func(m *mocks.MockEnvelopeService) {
+				m.AssignFunc = func(userID uuid.UUID, req services.AssignEnvelopeRequest) (*services.EnvelopeMonth, error) {
+					if req.BudgetID != budgetID || req.Amount != money.FromMajor(250) || req.Month.Month() != time.February {
+						t.Errorf("unexpected service request %+v", req)
+					}
+					return &services.EnvelopeMonth{}, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing amount",
+			body:           map[string]interface{}{"budget_id": budgetID.String()},
+			mockSetup:      func(m *mocks.MockEnvelopeService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid month",
+			body:           map[string]interface{}{"budget_id": budgetID.String(), "month": "February", "amount": 250},
+			mockSetup:      func(m *mocks.MockEnvelopeService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "more than ready to assign",
+			body: map[string]interface{}{"budget_id": budgetID.String(), "amount": 250},
+			mockSetup: func(m *mocks.MockEnvelopeService) {
+				m.AssignFunc = func(userID uuid.UUID, req services.AssignEnvelopeRequest) (*services.EnvelopeMonth, error) {
+					return nil, errors.New("only 100.00 is ready to assign")
+				}
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.MockEnvelopeService{}
+			tt.mockSetup(mockService)
+			handler := handlers.NewEnvelopeHandler(mockService)
+
+			router := testutils.SetupTestRouter()
+			router.POST("/envelopes/assign", func(c *gin.Context) {
+				c.Set("userID", testutils.TestUserID)
+				handler.AssignEnvelope(c)
+			})
+
+			w := testutils.MakeRequest(router, "POST", "/envelopes/assign", tt.body, nil)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestEnvelopeHandler_CoverEnvelope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	overspentID, fromID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name           string
+		body           map[string]interface{}
+		mockSetup      func(*mocks.MockEnvelopeService)
+		expectedStatus int
+	}{
+		{
+			name: "cover the whole overspending",
+			body: map[string]interface{}{"budget_id": overspentID.String(), "from_budget_id": fromID.String()},
+			mockSetup: func(m *mocks.MockEnvelopeService) {
+				m.CoverFunc = func(userID uuid.UUID, req services.CoverEnvelopeRequest) (*services.EnvelopeMonth, error) {
+					if req.BudgetID != overspentID || req.FromBudgetID != fromID || req.Amount != 0 {
+						t.Errorf("unexpected service request %+v", req)
+					}
+					return &services.EnvelopeMonth{}, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "negative amount",
+			body:           map[string]interface{}{"budget_id": overspentID.String(), "from_budget_id": fromID.String(), "amount": -10},
+			mockSetup:      func(m *mocks.MockEnvelopeService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "envelope is not overspent",
+			body: map[string]interface{}{"budget_id": overspentID.String(), "from_budget_id": fromID.String()},
+			mockSetup: func(m *mocks.MockEnvelopeService) {
+				m.CoverFunc = func(userID uuid.UUID, req services.CoverEnvelopeRequest) (*services.EnvelopeMonth, error) {
+					return nil, errors.New("envelope is not overspent")
+				}
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.MockEnvelopeService{}
+			tt.mockSetup(mockService)
+			handler := handlers.NewEnvelopeHandler(mockService)
+
+			router := testutils.SetupTestRouter()
+			router.POST("/envelopes/cover", func(c *gin.Context) {
+				c.Set("userID", testutils.TestUserID)
+				handler.CoverEnvelope(c)
+			})
+
+			w := testutils.MakeRequest(router, "POST", "/envelopes/cover", tt.body, nil)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nyunja/fity-budget-backend/internal/api/handlers"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+	"github.com/nyunja/fity-budget-backend/tests/unit/mocks"
+	"github.com/nyunja/fity-budget-backend/tests/unit/testutils"
+)
+
+func TestExchangeRateHandler_ConvertCurrency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		queryParams    string
+		mockSetup      func(*mocks.MockExchangeRateService)
+		expectedStatus int
+		checkResponse  func(t *testing.T, body map[string]interface{})
+	}{
+		{
+			name:        "successful conversion",
+			queryParams: "?amount=10&from=usd&to=kes&date=2026-10-15",
+			mockSetup: func(m *mocks.MockExchangeRateService) {
+				m.ConvertFunc = func(amount money.Amount, from, to string, on time.Time) (money.Amount, *services.AppliedRate, error) {
+					if from != "USD" || to != "KES" || on.Format("2006-01-02") != "2026-10-15" {
+						t.Errorf("Unexpected conversion request %s %s->%s on %v", amount, from, to, on)
+					}
+					rate, _ := money.ParseRate("129.5")
+					return amount.Convert(rate), &services.AppliedRate{From: from, To: to, Rate: rate, RateDate: on}, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, body map[string]interface{}) {
+				data := body["data"].(map[string]interface{})
+				if data["converted_amount"].(float64) != 1295 {
+					t.Errorf("Expected converted amount 1295, got %v", data["converted_amount"])
+				}
+				rate := data["exchange_rate"].(map[string]interface{})
+				if rate["rate"].(float64) != 129.5 {
+					t.Errorf("Expected rate 129.5, got %v", rate["rate"])
+				}
+			},
+		},
+		{
+			name:           "invalid currency",
+			queryParams:    "?amount=10&from=dollars&to=KES",
+			mockSetup:      func(m *mocks.MockExchangeRateService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid amount",
+			queryParams:    "?amount=ten&from=USD&to=KES",
+			mockSetup:      func(m *mocks.MockExchangeRateService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "no rate available",
+			queryParams: "?amount=10&from=USD&to=XOF",
+			mockSetup: func(m *mocks.MockExchangeRateService) {
+				m.ConvertFunc = func(amount money.Amount, from, to string, on time.Time) (money.Amount, *services.AppliedRate, error) {
+					return 0, nil, fmt.Errorf("%w from %s to %s", services.ErrNoExchangeRate, from, to)
+				}
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.MockExchangeRateService{}
+			tt.mockSetup(mockService)
+			handler := handlers.NewExchangeRateHandler(mockService)
+
+			router := testutils.SetupTestRouter()
+			router.GET("/exchange-rates/convert", func(c *gin.Context) {
+				c.Set("userID", testutils.TestUserID)
+				handler.ConvertCurrency(c)
+			})
+
+			w := testutils.MakeRequest(router, "GET", "/exchange-rates/convert"+tt.queryParams, nil, nil)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			if tt.checkResponse != nil {
+				var response map[string]interface{}
+				if err := testutils.ParseJSONResponse(w, &response); err != nil {
+					t.Fatalf("Failed to parse response: %v", err)
+				}
+				tt.checkResponse(t, response)
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/nyunja/fity-budget-backend/internal/api/handlers"
 	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
 	"github.com/nyunja/fity-budget-backend/internal/services"
 	"github.com/nyunja/fity-budget-backend/tests/unit/mocks"
 	"github.com/nyunja/fity-budget-backend/tests/unit/testutils"
@@ -38,8 +39,8 @@ func TestGoalHandler_ListGoals(t *testing.T) {
 							ID:            testutils.TestGoalID,
 							UserID:        userID,
 							Name:          "MacBook Pro",
-							TargetAmount:  2500.00,
-							CurrentAmount: 850.00,
+							TargetAmount:  money.FromMajor(2500),
+							CurrentAmount: money.FromMajor(850),
 							Color:         "#6366F1",
 							Priority:      "High",
 							Status:        "Active",
@@ -130,8 +131,8 @@ func TestGoalHandler_GetGoal(t *testing.T) {
 						ID:            id,
 						UserID:        userID,
 						Name:          "MacBook Pro",
-						TargetAmount:  2500.00,
-						CurrentAmount: 850.00,
+						TargetAmount:  money.FromMajor(2500),
+						CurrentAmount: money.FromMajor(850),
 						Status:        "Active",
 					}, nil
 				}
@@ -449,12 +450,12 @@ func TestGoalHandler_UpdateProgress(t *testing.T) {
 				c.Set("userID", testutils.TestUserID)
 			},
 			mockSetup: func(m *mocks.MockGoalService) {
-				m.AddProgressFunc = func(id, userID uuid.UUID, amount float64) (*models.SavingGoal, error) {
+				m.AddProgressFunc = func(id, userID uuid.UUID, amount money.Amount) (*models.SavingGoal, error) {
 					return &models.SavingGoal{
 						ID:            id,
 						UserID:        userID,
-						CurrentAmount: 1000.00,
-						TargetAmount:  2500.00,
+						CurrentAmount: money.FromMajor(1000),
+						TargetAmount:  money.FromMajor(2500),
 					}, nil
 				}
 			},
@@ -492,7 +493,7 @@ func TestGoalHandler_UpdateProgress(t *testing.T) {
 				c.Set("userID", testutils.TestUserID)
 			},
 			mockSetup: func(m *mocks.MockGoalService) {
-				m.AddProgressFunc = func(id, userID uuid.UUID, amount float64) (*models.SavingGoal, error) {
+				m.AddProgressFunc = func(id, userID uuid.UUID, amount money.Amount) (*models.SavingGoal, error) {
 					return nil, errors.New("cannot add progress to completed goal")
 				}
 			},
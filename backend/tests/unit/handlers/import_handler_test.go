@@ -0,0 +1,340 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/api/handlers"
+	"github.com/nyunja/fity-budget-backend/internal/imports"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+	"github.com/nyunja/fity-budget-backend/tests/unit/mocks"
+	"github.com/nyunja/fity-budget-backend/tests/unit/testutils"
+)
+
+// makeUploadRequest posts a multipart form with an optional statement file
+func makeUploadRequest(router *gin.Engine, path string, fields map[string]string, fileName, content string) *httptest.ResponseRecorder {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for key, value := range fields {
+		writer.WriteField(key, value)
+	}
+	if fileName != "" {
+		part, _ := writer.CreateFormFile("file", fileName)
+		io.WriteString(part, content)
+	}
+	writer.Close()
+
+	req, _ := http.NewRequest("POST", path, body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestImportHandler_PreviewImport(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	statement := "Date,Description,Amount\n2024-03-01,Coffee,-3.50\n"
+
+	tests := []struct {
+		name           string
+		fields         map[string]string
+		fileName       string
+		mockSetup      func(*mocks.MockImportService)
+		expectedStatus int
+		checkResponse  func(t *testing.T, body map[string]interface{})
+	}{
+		{
+			name:     "successful preview with mapping",
+			fields:   map[string]string{"wallet_id": testutils.TestWalletID.String(), "mapping": `{"date":"Date","description":"Description","amount":"Amount"}`},
+			fileName: "statement.csv",
+			mockSetup: func(m *mocks.MockImportService) {
+				m.PreviewImportFunc = func(userID uuid.UUID, req services.PreviewImportRequest) (*services.ImportPreview, error) {
+					if req.WalletID != testutils.TestWalletID || req.FileName != "statement.csv" {
+						return nil, fmt.Errorf("unexpected request %+v", req)
+					}
+					if req.Mapping == nil || req.Mapping.Amount != "Amount" {
+						return nil, errors.New("mapping not passed through")
+					}
+					data, _ := io.ReadAll(req.File)
+					if string(data) != statement {
+						return nil, errors.New("file not passed through")
+					}
+					return &services.ImportPreview{
+						Job:     &models.ImportJob{ID: uuid.New(), UserID: userID, Status: models.ImportStatusPreviewed, TotalRows: 1},
+						Mapping: req.Mapping,
+					}, nil
+				}
+			},
+			expectedStatus: http.StatusCreated,
+			checkResponse: func(t *testing.T, body map[string]interface{}) {
+				data := body["data"].(map[string]interface{})
+				job := data["import"].(map[string]interface{})
+				if job["status"] != models.ImportStatusPreviewed {
+					t.Errorf("Expected status Previewed, got %v", job["status"])
+				}
+				mapping := data["mapping"].(map[string]interface{})
+				if mapping["amount"] != "Amount" {
+					t.Errorf("Expected mapping in response, got %v", mapping)
+				}
+			},
+		},
+		{
+			name:           "missing wallet",
+			fields:         map[string]string{},
+			fileName:       "statement.csv",
+			mockSetup:      func(m *mocks.MockImportService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing file",
+			fields:         map[string]string{"wallet_id": testutils.TestWalletID.String()},
+			mockSetup:      func(m *mocks.MockImportService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "malformed mapping",
+			fields:         map[string]string{"wallet_id": testutils.TestWalletID.String(), "mapping": "date=Date"},
+			fileName:       "statement.csv",
+			mockSetup:      func(m *mocks.MockImportService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:     "unreadable file",
+			fields:   map[string]string{"wallet_id": testutils.TestWalletID.String()},
+			fileName: "statement.csv",
+			mockSetup: func(m *mocks.MockImportService) {
+				m.PreviewImportFunc = func(userID uuid.UUID, req services.PreviewImportRequest) (*services.ImportPreview, error) {
+					return nil, fmt.Errorf("%w: could not find a header row", imports.ErrInvalidFile)
+				}
+			},
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, body map[string]interface{}) {
+				if code := body["error"].(map[string]interface{})["code"]; code != "INVALID_FILE" {
+					t.Errorf("Expected INVALID_FILE, got %v", code)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.MockImportService{}
+			tt.mockSetup(mockService)
+			handler := handlers.NewImportHandler(mockService)
+
+			router := testutils.SetupTestRouter()
+			router.POST("/imports", func(c *gin.Context) {
+				c.Set("userID", testutils.TestUserID)
+				handler.PreviewImport(c)
+			})
+
+			w := makeUploadRequest(router, "/imports", tt.fields, tt.fileName, statement)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+
+			var response map[string]interface{}
+			if err := testutils.ParseJSONResponse(w, &response); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+
+			if tt.checkResponse != nil {
+				tt.checkResponse(t, response)
+			}
+		})
+	}
+}
+
+func TestImportHandler_CommitImport(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	jobID := uuid.New()
+
+	tests := []struct {
+		name           string
+		path           string
+		requestBody    interface{}
+		mockSetup      func(*mocks.MockImportService)
+		expectedStatus int
+	}{
+		{
+			name:        "successful commit including duplicates",
+			path:        "/imports/" + jobID.String() + "/commit",
+			requestBody: map[string]interface{}{"include_duplicates": true},
+			mockSetup: func(m *mocks.MockImportService) {
+				m.CommitImportFunc = func(id, userID uuid.UUID, req services.CommitImportRequest) (*models.ImportJob, error) {
+					if id != jobID || !req.IncludeDuplicates {
+						return nil, errors.New("unexpected request")
+					}
+					return &models.ImportJob{ID: id, UserID: userID, Status: models.ImportStatusCompleted}, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "successful commit without body",
+			path: "/imports/" + jobID.String() + "/commit",
+			mockSetup: func(m *mocks.MockImportService) {
+				m.CommitImportFunc = func(id, userID uuid.UUID, req services.CommitImportRequest) (*models.ImportJob, error) {
+					return &models.ImportJob{ID: id, UserID: userID, Status: models.ImportStatusCompleted}, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "already committed",
+			path: "/imports/" + jobID.String() + "/commit",
+			mockSetup: func(m *mocks.MockImportService) {
+				m.CommitImportFunc = func(id, userID uuid.UUID, req services.CommitImportRequest) (*models.ImportJob, error) {
+					return nil, errors.New("import has already been completed")
+				}
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid id",
+			path:           "/imports/not-a-uuid/commit",
+			mockSetup:      func(m *mocks.MockImportService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.MockImportService{}
+			tt.mockSetup(mockService)
+			handler := handlers.NewImportHandler(mockService)
+
+			router := testutils.SetupTestRouter()
+			router.POST("/imports/:id/commit", func(c *gin.Context) {
+				c.Set("userID", testutils.TestUserID)
+				handler.CommitImport(c)
+			})
+
+			w := testutils.MakeRequest(router, "POST", tt.path, tt.requestBody, nil)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestImportHandler_UndoAndGetImport(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	jobID := uuid.New()
+	mockService := &mocks.MockImportService{
+		UndoImportFunc: func(id, userID uuid.UUID) (*models.ImportJob, error) {
+			return nil, errors.New("only completed imports can be undone")
+		},
+		GetImportFunc: func(id, userID uuid.UUID) (*models.ImportJob, error) {
+			return nil, errors.New("import not found")
+		},
+		ListImportsFunc: func(userID uuid.UUID, limit, offset int) ([]*models.ImportJob, int64, error) {
+			return []*models.ImportJob{{ID: jobID, UserID: userID}}, 21, nil
+		},
+	}
+	handler := handlers.NewImportHandler(mockService)
+
+	router := testutils.SetupTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", testutils.TestUserID)
+	})
+	router.GET("/imports", handler.ListImports)
+	router.GET("/imports/:id", handler.GetImport)
+	router.POST("/imports/:id/undo", handler.UndoImport)
+
+	if w := testutils.MakeRequest(router, "POST", "/imports/"+jobID.String()+"/undo", nil, nil); w.Code != http.StatusBadRequest {
+		t.Errorf("undo: expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	if w := testutils.MakeRequest(router, "GET", "/imports/"+jobID.String(), nil, nil); w.Code != http.StatusNotFound {
+		t.Errorf("get: expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+
+	w := testutils.MakeRequest(router, "GET", "/imports?limit=20", nil, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("list: expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	var response map[string]interface{}
+	if err := testutils.ParseJSONResponse(w, &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	pagination := response["data"].(map[string]interface{})["pagination"].(map[string]interface{})
+	if pagination["total_pages"].(float64) != 2 || pagination["has_next"] != true {
+		t.Errorf("Unexpected pagination %v", pagination)
+	}
+}
+
+func TestImportHandler_ImportMpesaMessages(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		requestBody    interface{}
+		mockSetup      func(*mocks.MockImportService)
+		expectedStatus int
+	}{
+		{
+			name:        "successful import into the mobile money wallet",
+			requestBody: map[string]interface{}{"text": "QCA1B2C3D4 Confirmed. Ksh500.00 sent to JOHN DOE on 5/3/24 at 8:15 AM."},
+			mockSetup: func(m *mocks.MockImportService) {
+				m.ImportMpesaMessagesFunc = func(userID uuid.UUID, req services.ImportMpesaMessagesRequest) (*models.ImportJob, error) {
+					if req.WalletID != nil || req.Text == "" {
+						return nil, errors.New("unexpected request")
+					}
+					return &models.ImportJob{ID: uuid.New(), UserID: userID, Source: models.ImportSourceMpesa, Status: models.ImportStatusCompleted}, nil
+				}
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "missing text",
+			requestBody:    map[string]interface{}{"wallet_id": testutils.TestWalletID.String()},
+			mockSetup:      func(m *mocks.MockImportService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "no messages recognised",
+			requestBody: map[string]interface{}{"text": "hello"},
+			mockSetup: func(m *mocks.MockImportService) {
+				m.ImportMpesaMessagesFunc = func(userID uuid.UUID, req services.ImportMpesaMessagesRequest) (*models.ImportJob, error) {
+					return nil, imports.ErrNoRecords
+				}
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.MockImportService{}
+			tt.mockSetup(mockService)
+			handler := handlers.NewImportHandler(mockService)
+
+			router := testutils.SetupTestRouter()
+			router.POST("/imports/mpesa", func(c *gin.Context) {
+				c.Set("userID", testutils.TestUserID)
+				handler.ImportMpesaMessages(c)
+			})
+
+			w := testutils.MakeRequest(router, "POST", "/imports/mpesa", tt.requestBody, nil)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/api/handlers"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+	"github.com/nyunja/fity-budget-backend/tests/unit/mocks"
+	"github.com/nyunja/fity-budget-backend/tests/unit/testutils"
+)
+
+func TestReceiptHandler_UploadReceipt(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	transactionID := uuid.New()
+
+	tests := []struct {
+		name           string
+		id             string
+		fileName       string
+		content        string
+		mockSetup      func(*mocks.MockReceiptService)
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name:     "successful upload",
+			id:       transactionID.String(),
+			fileName: "invoice.pdf",
+			content:  "%PDF-1.4\n%%EOF\n",
+			mockSetup: func(m *mocks.MockReceiptService) {
+				m.UploadReceiptFunc = func(userID, id uuid.UUID, req services.UploadReceiptRequest) (*models.Receipt, error) {
+					data, _ := io.ReadAll(req.File)
+					if id != transactionID || req.FileName != "invoice.pdf" || string(data) != "%PDF-1.4\n%%EOF\n" {
+						t.Errorf("unexpected upload %s %q", id, req.FileName)
+					}
+					return &models.Receipt{ID: uuid.New(), TransactionID: id, FileName: req.FileName, ContentType: "application/pdf"}, nil
+				}
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "missing file",
+			id:             transactionID.String(),
+			mockSetup:      func(m *mocks.MockReceiptService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "VALIDATION_ERROR",
+		},
+		{
+			name:           "invalid transaction id",
+			id:             "latest",
+			fileName:       "invoice.pdf",
+			content:        "%PDF-1.4\n",
+			mockSetup:      func(m *mocks.MockReceiptService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "INVALID_ID",
+		},
+		{
+			name:     "unsupported file type",
+			id:       transactionID.String(),
+			fileName: "receipt.png",
+			content:  "<html></html>",
+			mockSetup: func(m *mocks.MockReceiptService) {
+				m.UploadReceiptFunc = func(userID, id uuid.UUID, req services.UploadReceiptRequest) (*models.Receipt, error) {
+					return nil, services.ErrUnsupportedReceiptType
+				}
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "INVALID_FILE",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.MockReceiptService{}
+			tt.mockSetup(mockService)
+			handler := handlers.NewReceiptHandler(mockService)
+
+			router := testutils.SetupTestRouter()
+			router.POST("/transactions/:id/receipts", func(c *gin.Context) {
+				c.Set("userID", testutils.TestUserID)
+				handler.UploadReceipt(c)
+			})
+
+			w := makeUploadRequest(router, "/transactions/"+tt.id+"/receipts", nil, tt.fileName, tt.content)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedCode != "" && !strings.Contains(w.Body.String(), tt.expectedCode) {
+				t.Errorf("Expected error code %s, got %s", tt.expectedCode, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestReceiptHandler_DownloadReceipt(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	receiptID := uuid.New()
+
+	tests := []struct {
+		name           string
+		path           string
+		mockSetup      func(*mocks.MockReceiptService)
+		expectedStatus int
+		expectedType   string
+	}{
+		{
+			name: "download file",
+			path: "/receipts/" + receiptID.String() + "/download",
+			mockSetup: func(m *mocks.MockReceiptService) {
+				m.OpenReceiptFunc = func(id, userID uuid.UUID, thumbnail bool) (*services.ReceiptFile, error) {
+					if thumbnail {
+						t.Error("Expected the original file")
+					}
+					return &services.ReceiptFile{
+						Receipt:     &models.Receipt{ID: id, FileName: "invoice.pdf"},
+						ContentType: "application/pdf",
+						Size:        15,
+						Body:        io.NopCloser(strings.NewReader("%PDF-1.4\n%%EOF\n")),
+					}, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+			expectedType:   "application/pdf",
+		},
+		{
+			name: "download thumbnail",
+			path: "/receipts/" + receiptID.String() + "/thumbnail",
+			mockSetup: func(m *mocks.MockReceiptService) {
+				m.OpenReceiptFunc = func(id, userID uuid.UUID, thumbnail bool) (*services.ReceiptFile, error) {
+					if !thumbnail {
+						t.Error("Expected the thumbnail")
+					}
+					return &services.ReceiptFile{
+						Receipt:     &models.Receipt{ID: id, FileName: "naivas.png"},
+						ContentType: "image/jpeg",
+						Size:        -1,
+						Body:        io.NopCloser(strings.NewReader("\xff\xd8\xff")),
+					}, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+			expectedType:   "image/jpeg",
+		},
+		{
+			name: "another user's receipt",
+			path: "/receipts/" + receiptID.String() + "/download",
+			mockSetup: func(m *mocks.MockReceiptService) {
+				m.OpenReceiptFunc = func(id, userID uuid.UUID, thumbnail bool) (*services.ReceiptFile, error) {
+					return nil, errors.New("unauthorized access to receipt")
+				}
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.MockReceiptService{}
+			tt.mockSetup(mockService)
+			handler := handlers.NewReceiptHandler(mockService)
+
+			router := testutils.SetupTestRouter()
+			router.GET("/receipts/:id/download", func(c *gin.Context) {
+				c.Set("userID", testutils.TestUserID)
+				handler.DownloadReceipt(c)
+			})
+			router.GET("/receipts/:id/thumbnail", func(c *gin.Context) {
+				c.Set("userID", testutils.TestUserID)
+				handler.DownloadThumbnail(c)
+			})
+
+			w := testutils.MakeRequest(router, "GET", tt.path, nil, nil)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedType != "" {
+				if got := w.Header().Get("Content-Type"); got != tt.expectedType {
+					t.Errorf("Expected content type %s, got %s", tt.expectedType, got)
+				}
+				if w.Header().Get("X-Content-Type-Options") != "nosniff" {
+					t.Error("Expected downloads to disable content sniffing")
+				}
+			}
+		})
+	}
+}
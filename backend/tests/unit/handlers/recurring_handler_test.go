@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/api/handlers"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+	"github.com/nyunja/fity-budget-backend/tests/unit/mocks"
+	"github.com/nyunja/fity-budget-backend/tests/unit/testutils"
+)
+
+func TestRecurringHandler_CreateRecurring(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	validBody := map[string]interface{}{
+		"wallet_id":       testutils.TestWalletID,
+		"amount":          1500,
+		"name":            "Rent",
+		"method":          "Bank",
+		"category":        "Housing",
+		"frequency":       "monthly",
+		"day_of_month":    -1,
+		"start_date":      "2030-01-01T00:00:00Z",
+		"post_as_pending": true,
+	}
+
+	tests := []struct {
+		name           string
+		body           map[string]interface{}
+		mockSetup      func(*mocks.MockRecurringTransactionService)
+		expectedStatus int
+	}{
+		{
+			name: "successful create",
+			body: validBody,
+			mockSetup: func(m *mocks.MockRecurringTransactionService) {
+				m.CreateRecurringFunc = func(userID uuid.UUID, req services.CreateRecurringRequest) (*models.RecurringTransaction, error) {
+					if req.DayOfMonth != models.LastDayOfMonth || !req.PostAsPending || req.Amount != money.FromMajor(1500) {
+						t.Errorf("unexpected service request %+v", req)
+					}
+					return &models.RecurringTransaction{ID: uuid.New(), UserID: userID, Name: req.Name, Frequency: req.Frequency}, nil
+				}
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "unknown frequency",
+			body: map[string]interface{}{
+				"amount":     100,
+				"name":       "Rent",
+				"method":     "Bank",
+				"category":   "Housing",
+				"frequency":  "hourly",
+				"start_date": "2030-01-01T00:00:00Z",
+			},
+			mockSetup:      func(m *mocks.MockRecurringTransactionService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "missing start date",
+			body: map[string]interface{}{
+				"amount":    100,
+				"name":      "Rent",
+				"method":    "Bank",
+				"category":  "Housing",
+				"frequency": "monthly",
+			},
+			mockSetup:      func(m *mocks.MockRecurringTransactionService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "service error",
+			body: validBody,
+			mockSetup: func(m *mocks.MockRecurringTransactionService) {
+				m.CreateRecurringFunc = func(userID uuid.UUID, req services.CreateRecurringRequest) (*models.RecurringTransaction, error) {
+					return nil, errors.New("unauthorized access to wallet")
+				}
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.MockRecurringTransactionService{}
+			tt.mockSetup(mockService)
+			handler := handlers.NewRecurringHandler(mockService)
+
+			router := testutils.SetupTestRouter()
+			router.POST("/recurring", func(c *gin.Context) {
+				c.Set("userID", testutils.TestUserID)
+				handler.CreateRecurring(c)
+			})
+
+			w := testutils.MakeRequest(router, "POST", "/recurring", tt.body, nil)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestRecurringHandler_PreviewOccurrences(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	recurringID := uuid.New()
+
+	tests := []struct {
+		name           string
+		path           string
+		mockSetup      func(*mocks.MockRecurringTransactionService)
+		expectedStatus int
+		expectedCount  int
+	}{
+		{
+			name: "successful preview",
+			path: "/recurring/" + recurringID.String() + "/occurrences?limit=3",
+			mockSetup: func(m *mocks.MockRecurringTransactionService) {
+				m.PreviewOccurrencesFunc = func(id, userID uuid.UUID, limit int) ([]time.Time, error) {
+					dates := make([]time.Time, limit)
+					for i := range dates {
+						dates[i] = time.Date(2030, time.Month(i+1), 1, 0, 0, 0, 0, time.UTC)
+					}
+					return dates, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+			expectedCount:  3,
+		},
+		{
+			name:           "invalid ID",
+			path:           "/recurring/not-a-uuid/occurrences",
+			mockSetup:      func(m *mocks.MockRecurringTransactionService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "not found",
+			path: "/recurring/" + recurringID.String() + "/occurrences",
+			mockSetup: func(m *mocks.MockRecurringTransactionService) {
+				m.PreviewOccurrencesFunc = func(id, userID uuid.UUID, limit int) ([]time.Time, error) {
+					return nil, errors.New("recurring transaction not found")
+				}
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.MockRecurringTransactionService{}
+			tt.mockSetup(mockService)
+			handler := handlers.NewRecurringHandler(mockService)
+
+			router := testutils.SetupTestRouter()
+			router.GET("/recurring/:id/occurrences", func(c *gin.Context) {
+				c.Set("userID", testutils.TestUserID)
+				handler.PreviewOccurrences(c)
+			})
+
+			w := testutils.MakeRequest(router, "GET", tt.path, nil, nil)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response map[string]interface{}
+			if err := testutils.ParseJSONResponse(w, &response); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			occurrences := response["data"].(map[string]interface{})["occurrences"].([]interface{})
+			if len(occurrences) != tt.expectedCount {
+				t.Errorf("Expected %d occurrences, got %d", tt.expectedCount, len(occurrences))
+			}
+		})
+	}
+}
+
+func TestRecurringHandler_GetUpcoming(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var until time.Time
+	mockService := &mocks.MockRecurringTransactionService{
+		GetUpcomingFunc: func(userID uuid.UUID, u time.Time) ([]*services.UpcomingOccurrence, error) {
+			until = u
+			return []*services.UpcomingOccurrence{
+				{RecurringID: uuid.New(), Name: "Rent", Amount: money.FromMajor(1500), Status: "Completed", Date: time.Now().AddDate(0, 0, 3)},
+			}, nil
+		},
+	}
+	handler := handlers.NewRecurringHandler(mockService)
+
+	router := testutils.SetupTestRouter()
+	router.GET("/recurring/upcoming", func(c *gin.Context) {
+		c.Set("userID", testutils.TestUserID)
+		handler.GetUpcoming(c)
+	})
+
+	w := testutils.MakeRequest(router, "GET", "/recurring/upcoming?days=7", nil, nil)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if days := time.Until(until).Hours() / 24; days < 6.9 || days > 7.1 {
+		t.Errorf("Expected upcoming occurrences for 7 days, got %.1f", days)
+	}
+
+	var response map[string]interface{}
+	if err := testutils.ParseJSONResponse(w, &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	upcoming := response["data"].(map[string]interface{})["upcoming"].([]interface{})
+	if len(upcoming) != 1 {
+		t.Errorf("Expected 1 upcoming occurrence, got %d", len(upcoming))
+	}
+}
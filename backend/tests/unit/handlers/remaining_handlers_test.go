@@ -10,6 +10,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/nyunja/fity-budget-backend/internal/api/handlers"
 	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
 	"github.com/nyunja/fity-budget-backend/internal/services"
 	"github.com/nyunja/fity-budget-backend/tests/unit/mocks"
 	"github.com/nyunja/fity-budget-backend/tests/unit/testutils"
@@ -113,12 +114,12 @@ func TestBudgetHandler_GetBudgetSummary(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	mockService := &mocks.MockBudgetService{}
-	mockService.GetBudgetSummaryFunc = func(userID uuid.UUID) (*services.BudgetSummary, error) {
+	mockService.GetBudgetSummaryFunc = func(userID uuid.UUID, at time.Time) (*services.BudgetSummary, error) {
 		return &services.BudgetSummary{
 			TotalBudgets:    3,
-			TotalLimit:      5000.00,
-			TotalSpent:      3200.00,
-			TotalRemaining:  1800.00,
+			TotalLimit:      money.FromMajor(5000),
+			TotalSpent:      money.FromMajor(3200),
+			TotalRemaining:  money.FromMajor(1800),
 			OverBudgetCount: 0,
 			NearLimitCount:  1,
 		}, nil
@@ -147,6 +148,159 @@ func TestBudgetHandler_GetBudgetSummary(t *testing.T) {
 	}
 }
 
+func TestBudgetHandler_GetBudgetStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	budgetID := uuid.New()
+
+	tests := []struct {
+		name           string
+		path           string
+		mockSetup      func(*mocks.MockBudgetService)
+		expectedStatus int
+	}{
+		{
+			name: "past period",
+			path: "/budgets/" + budgetID.String() + "/status?date=2026-01-31",
+			mockSetup: func(m *mocks.MockBudgetService) {
+				m.GetBudgetStatusFunc = func(id, userID uuid.UUID, at time.Time) (*services.BudgetStatus, error) {
+					if id != budgetID || !at.Equal(time.Date(2026, time.January, 31, 0, 0, 0, 0, time.UTC)) {
+						t.Errorf("unexpected status request for %v at %v", id, at)
+					}
+					return &services.BudgetStatus{BudgetID: id, Period: "monthly"}, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid date",
+			path:           "/budgets/" + budgetID.String() + "/status?date=31/01/2026",
+			mockSetup:      func(m *mocks.MockBudgetService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "budget not found",
+			path: "/budgets/" + budgetID.String() + "/status",
+			mockSetup: func(m *mocks.MockBudgetService) {
+				m.GetBudgetStatusFunc = func(id, userID uuid.UUID, at time.Time) (*services.BudgetStatus, error) {
+					return nil, errors.New("budget not found")
+				}
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.MockBudgetService{}
+			tt.mockSetup(mockService)
+			handler := handlers.NewBudgetHandler(mockService)
+
+			router := testutils.SetupTestRouter()
+			router.GET("/budgets/:id/status", func(c *gin.Context) {
+				c.Set("userID", testutils.TestUserID)
+				handler.GetBudgetStatus(c)
+			})
+
+			w := testutils.MakeRequest(router, "GET", tt.path, nil, nil)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestBudgetHandler_GetBudgetLedger(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	budgetID := uuid.New()
+
+	tests := []struct {
+		name           string
+		mockSetup      func(*mocks.MockBudgetService)
+		expectedStatus int
+	}{
+		{
+			name: "ledger",
+			mockSetup: func(m *mocks.MockBudgetService) {
+				m.GetBudgetLedgerFunc = func(id, userID uuid.UUID) ([]*models.BudgetLedgerEntry, error) {
+					return []*models.BudgetLedgerEntry{{BudgetID: id, CarriedOut: money.FromMajor(200)}}, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "other user's budget",
+			mockSetup: func(m *mocks.MockBudgetService) {
+				m.GetBudgetLedgerFunc = func(id, userID uuid.UUID) ([]*models.BudgetLedgerEntry, error) {
+					return nil, errors.New("unauthorized access to budget")
+				}
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.MockBudgetService{}
+			tt.mockSetup(mockService)
+			handler := handlers.NewBudgetHandler(mockService)
+
+			router := testutils.SetupTestRouter()
+			router.GET("/budgets/:id/ledger", func(c *gin.Context) {
+				c.Set("userID", testutils.TestUserID)
+				handler.GetBudgetLedger(c)
+			})
+
+			w := testutils.MakeRequest(router, "GET", "/budgets/"+budgetID.String()+"/ledger", nil, nil)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestBudgetHandler_GetBudgetHistory(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		query          string
+		wantPeriods    int
+		expectedStatus int
+	}{
+		{name: "default periods", query: "", wantPeriods: 6, expectedStatus: http.StatusOK},
+		{name: "requested periods", query: "?periods=12&date=2026-03-31", wantPeriods: 12, expectedStatus: http.StatusOK},
+		{name: "capped periods", query: "?periods=100", wantPeriods: 24, expectedStatus: http.StatusOK},
+		{name: "invalid date", query: "?date=March", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.MockBudgetService{}
+			mockService.GetBudgetHistoryFunc = func(userID uuid.UUID, periods int, at time.Time) ([]*services.BudgetHistory, error) {
+				if periods != tt.wantPeriods {
+					t.Errorf("Expected %d periods, got %d", tt.wantPeriods, periods)
+				}
+				return []*services.BudgetHistory{}, nil
+			}
+			handler := handlers.NewBudgetHandler(mockService)
+
+			router := testutils.SetupTestRouter()
+			router.GET("/budgets/history", func(c *gin.Context) {
+				c.Set("userID", testutils.TestUserID)
+				handler.GetBudgetHistory(c)
+			})
+
+			w := testutils.MakeRequest(router, "GET", "/budgets/history"+tt.query, nil, nil)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
 // Wallet Handler Tests
 func TestWalletHandler_CreateWallet(t *testing.T) {
 	gin.SetMode(gin.TestMode)
@@ -249,7 +403,7 @@ func TestWalletHandler_ListWallets(t *testing.T) {
 				UserID:    userID,
 				Name:      "M-PESA",
 				Type:      "Mobile Money",
-				Balance:   12450.00,
+				Balance:   money.FromMajor(12450),
 				Currency:  "KES",
 				IsDefault: true,
 			},
@@ -279,6 +433,87 @@ func TestWalletHandler_ListWallets(t *testing.T) {
 	}
 }
 
+func TestWalletHandler_CreateTransfer(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	secondWalletID := uuid.MustParse("880e8400-e29b-41d4-a716-446655440002")
+
+	tests := []struct {
+		name           string
+		requestBody    interface{}
+		mockSetup      func(*mocks.MockWalletService)
+		expectedStatus int
+	}{
+		{
+			name: "successful transfer",
+			requestBody: map[string]interface{}{
+				"from_wallet_id": testutils.TestWalletID.String(),
+				"to_wallet_id":   secondWalletID.String(),
+				"amount":         500.00,
+				"notes":          "Savings",
+			},
+			mockSetup: func(m *mocks.MockWalletService) {
+				m.TransferBetweenWalletsFunc = func(userID uuid.UUID, req services.CreateTransferRequest) (*models.Transfer, error) {
+					return &models.Transfer{
+						ID:           uuid.New(),
+						UserID:       userID,
+						FromWalletID: req.FromWalletID,
+						ToWalletID:   req.ToWalletID,
+						Amount:       req.Amount,
+						Notes:        req.Notes,
+						Status:       "Completed",
+					}, nil
+				}
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "validation error - non-positive amount",
+			requestBody: map[string]interface{}{
+				"from_wallet_id": testutils.TestWalletID.String(),
+				"to_wallet_id":   secondWalletID.String(),
+				"amount":         -10.00,
+			},
+			mockSetup:      func(m *mocks.MockWalletService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "service error - insufficient balance",
+			requestBody: map[string]interface{}{
+				"from_wallet_id": testutils.TestWalletID.String(),
+				"to_wallet_id":   secondWalletID.String(),
+				"amount":         500.00,
+			},
+			mockSetup: func(m *mocks.MockWalletService) {
+				m.TransferBetweenWalletsFunc = func(userID uuid.UUID, req services.CreateTransferRequest) (*models.Transfer, error) {
+					return nil, errors.New("insufficient balance in source wallet")
+				}
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.MockWalletService{}
+			tt.mockSetup(mockService)
+			handler := handlers.NewWalletHandler(mockService)
+
+			router := testutils.SetupTestRouter()
+			router.POST("/wallets/transfers", func(c *gin.Context) {
+				c.Set("userID", testutils.TestUserID)
+				handler.CreateTransfer(c)
+			})
+
+			w := testutils.MakeRequest(router, "POST", "/wallets/transfers", tt.requestBody, nil)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
+
 // Analytics Handler Tests
 func TestAnalyticsHandler_GetDashboard(t *testing.T) {
 	gin.SetMode(gin.TestMode)
@@ -286,10 +521,10 @@ func TestAnalyticsHandler_GetDashboard(t *testing.T) {
 	mockService := &mocks.MockAnalyticsService{}
 	mockService.GetDashboardSummaryFunc = func(userID uuid.UUID) (*services.DashboardSummary, error) {
 		return &services.DashboardSummary{
-			TotalBalance: 15700.00,
-			TotalIncome:  8500.00,
-			TotalExpense: 6222.00,
-			NetSavings:   2278.00,
+			TotalBalance: money.FromMajor(15700),
+			TotalIncome:  money.FromMajor(8500),
+			TotalExpense: money.FromMajor(6222),
+			NetSavings:   money.FromMajor(2278),
 		}, nil
 	}
 
@@ -334,14 +569,18 @@ func TestAnalyticsHandler_GetSpendingAnalysis(t *testing.T) {
 				c.Set("userID", testutils.TestUserID)
 			},
 			mockSetup: func(m *mocks.MockAnalyticsService) {
-				m.GetSpendingByCategoryFunc = func(userID uuid.UUID, startDate, endDate time.Time) ([]*services.CategorySpending, error) {
-					return []*services.CategorySpending{
-						{
-							Category:   "Food & Groceries",
-							Amount:     800.00,
-							Percentage: 12.85,
-							Count:      12,
+				m.GetSpendingByCategoryFunc = func(userID uuid.UUID, startDate, endDate time.Time, rollup bool) (*services.SpendingByCategoryReport, error) {
+					return &services.SpendingByCategoryReport{
+						TotalSpending: money.FromMajor(800),
+						Categories: []*services.CategorySpending{
+							{
+								Category:   "Food & Groceries",
+								Amount:     money.FromMajor(800),
+								Percentage: 12.85,
+								Count:      12,
+							},
 						},
+						Currency: "KES",
 					}, nil
 				}
 			},
@@ -359,7 +598,7 @@ func TestAnalyticsHandler_GetSpendingAnalysis(t *testing.T) {
 				c.Set("userID", testutils.TestUserID)
 			},
 			mockSetup: func(m *mocks.MockAnalyticsService) {
-				m.GetSpendingByCategoryFunc = func(userID uuid.UUID, startDate, endDate time.Time) ([]*services.CategorySpending, error) {
+				m.GetSpendingByCategoryFunc = func(userID uuid.UUID, startDate, endDate time.Time, rollup bool) (*services.SpendingByCategoryReport, error) {
 					return nil, errors.New("database error")
 				}
 			},
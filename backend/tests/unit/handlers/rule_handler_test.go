@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/api/handlers"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+	"github.com/nyunja/fity-budget-backend/tests/unit/mocks"
+	"github.com/nyunja/fity-budget-backend/tests/unit/testutils"
+)
+
+func TestRuleHandler_CreateRule(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		body           map[string]interface{}
+		mockSetup      func(*mocks.MockRuleService)
+		expectedStatus int
+	}{
+		{
+			name: "successful create",
+			body: map[string]interface{}{
+				"name":         "Uber",
+				"priority":     2,
+				"match_type":   "regex",
+				"pattern":      "^uber",
+				"min_amount":   100,
+				"set_category": "Transport",
+				"add_tags":     []string{"travel"},
+			},
+			mockSetup: func(m *mocks.MockRuleService) {
+				m.CreateRuleFunc = func(userID uuid.UUID, req services.CreateRuleRequest) (*models.Rule, error) {
+					if req.Priority != 2 || req.MatchType != "regex" || req.MinAmount == nil || *req.MinAmount != money.FromMajor(100) || len(req.AddTags) != 1 {
+						t.Errorf("unexpected service request %+v", req)
+					}
+					return &models.Rule{ID: uuid.New(), UserID: userID, Name: req.Name}, nil
+				}
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "missing name",
+			body:           map[string]interface{}{"pattern": "uber", "set_category": "Transport"},
+			mockSetup:      func(m *mocks.MockRuleService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown match field",
+			body:           map[string]interface{}{"name": "Uber", "match_field": "category", "pattern": "uber", "set_category": "Transport"},
+			mockSetup:      func(m *mocks.MockRuleService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "rule without action",
+			body: map[string]interface{}{"name": "Uber", "pattern": "uber"},
+			mockSetup: func(m *mocks.MockRuleService) {
+				m.CreateRuleFunc = func(userID uuid.UUID, req services.CreateRuleRequest) (*models.Rule, error) {
+					return nil, errors.New("rule needs at least one action")
+				}
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.MockRuleService{}
+			tt.mockSetup(mockService)
+			handler := handlers.NewRuleHandler(mockService)
+
+			router := testutils.SetupTestRouter()
+			router.POST("/rules", func(c *gin.Context) {
+				c.Set("userID", testutils.TestUserID)
+				handler.CreateRule(c)
+			})
+
+			w := testutils.MakeRequest(router, "POST", "/rules", tt.body, nil)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestRuleHandler_ApplyRules(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		body           map[string]interface{}
+		mockSetup      func(*mocks.MockRuleService)
+		expectedStatus int
+	}{
+		{
+			name: "dry run",
+			body: map[string]interface{}{"start_date": "2026-03-01T00:00:00Z", "end_date": "2026-04-01T00:00:00Z", "dry_run": true},
+			mockSetup: func(m *mocks.MockRuleService) {
+				m.ApplyRulesFunc = func(userID uuid.UUID, req services.ApplyRulesRequest) (*services.ApplyRulesResult, error) {
+					if !req.DryRun || req.StartDate.Month() != 3 || req.EndDate.Month() != 4 {
+						t.Errorf("unexpected service request %+v", req)
+					}
+					return &services.ApplyRulesResult{DryRun: true, Changes: []*services.RuleChange{}}, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing end date",
+			body:           map[string]interface{}{"start_date": "2026-03-01T00:00:00Z"},
+			mockSetup:      func(m *mocks.MockRuleService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "range too large",
+			body: map[string]interface{}{"start_date": "2020-01-01T00:00:00Z", "end_date": "2026-01-01T00:00:00Z"},
+			mockSetup: func(m *mocks.MockRuleService) {
+				m.ApplyRulesFunc = func(userID uuid.UUID, req services.ApplyRulesRequest) (*services.ApplyRulesResult, error) {
+					return nil, errors.New("narrow the date range")
+				}
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.MockRuleService{}
+			tt.mockSetup(mockService)
+			handler := handlers.NewRuleHandler(mockService)
+
+			router := testutils.SetupTestRouter()
+			router.POST("/rules/apply", func(c *gin.Context) {
+				c.Set("userID", testutils.TestUserID)
+				handler.ApplyRules(c)
+			})
+
+			w := testutils.MakeRequest(router, "POST", "/rules/apply", tt.body, nil)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
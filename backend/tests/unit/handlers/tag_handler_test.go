@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/api/handlers"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+	"github.com/nyunja/fity-budget-backend/tests/unit/mocks"
+	"github.com/nyunja/fity-budget-backend/tests/unit/testutils"
+)
+
+func TestTagHandler_CreateTag(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		body           map[string]interface{}
+		mockSetup      func(*mocks.MockTagService)
+		expectedStatus int
+	}{
+		{
+			name: "successful create",
+			body: map[string]interface{}{"name": "Reimbursable", "color": "#F59E0B"},
+			mockSetup: func(m *mocks.MockTagService) {
+				m.CreateTagFunc = func(userID uuid.UUID, req services.CreateTagRequest) (*models.Tag, error) {
+					if req.Name != "Reimbursable" || req.Color != "#F59E0B" {
+						t.Errorf("unexpected service request %+v", req)
+					}
+					return &models.Tag{ID: uuid.New(), UserID: userID, Name: req.Name, Color: req.Color}, nil
+				}
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "missing name",
+			body:           map[string]interface{}{"color": "#F59E0B"},
+			mockSetup:      func(m *mocks.MockTagService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "duplicate name",
+			body: map[string]interface{}{"name": "reimbursable"},
+			mockSetup: func(m *mocks.MockTagService) {
+				m.CreateTagFunc = func(userID uuid.UUID, req services.CreateTagRequest) (*models.Tag, error) {
+					return nil, errors.New("tag already exists")
+				}
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.MockTagService{}
+			tt.mockSetup(mockService)
+			handler := handlers.NewTagHandler(mockService)
+
+			router := testutils.SetupTestRouter()
+			router.POST("/tags", func(c *gin.Context) {
+				c.Set("userID", testutils.TestUserID)
+				handler.CreateTag(c)
+			})
+
+			w := testutils.MakeRequest(router, "POST", "/tags", tt.body, nil)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestTagHandler_DeleteTag(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tagID := uuid.New()
+
+	tests := []struct {
+		name           string
+		id             string
+		mockSetup      func(*mocks.MockTagService)
+		expectedStatus int
+	}{
+		{
+			name: "successful delete",
+			id:   tagID.String(),
+			mockSetup: func(m *mocks.MockTagService) {
+				m.DeleteTagFunc = func(id, userID uuid.UUID) error {
+					if id != tagID {
+						t.Errorf("Expected tag %s, got %s", tagID, id)
+					}
+					return nil
+				}
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "invalid id",
+			id:             "travel",
+			mockSetup:      func(m *mocks.MockTagService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "tag not found",
+			id:   tagID.String(),
+			mockSetup: func(m *mocks.MockTagService) {
+				m.DeleteTagFunc = func(id, userID uuid.UUID) error {
+					return errors.New("tag not found")
+				}
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.MockTagService{}
+			tt.mockSetup(mockService)
+			handler := handlers.NewTagHandler(mockService)
+
+			router := testutils.SetupTestRouter()
+			router.DELETE("/tags/:id", func(c *gin.Context) {
+				c.Set("userID", testutils.TestUserID)
+				handler.DeleteTag(c)
+			})
+
+			w := testutils.MakeRequest(router, "DELETE", "/tags/"+tt.id, nil, nil)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"testing"
 	"time"
@@ -10,6 +11,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/nyunja/fity-budget-backend/internal/api/handlers"
 	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
 	"github.com/nyunja/fity-budget-backend/internal/services"
 	"github.com/nyunja/fity-budget-backend/tests/unit/mocks"
 	"github.com/nyunja/fity-budget-backend/tests/unit/testutils"
@@ -33,12 +35,12 @@ func TestTransactionHandler_ListTransactions(t *testing.T) {
 				c.Set("userID", testutils.TestUserID)
 			},
 			mockSetup: func(m *mocks.MockTransactionService) {
-				m.GetUserTransactionsWithCountFunc = func(userID uuid.UUID, limit, offset int) ([]*models.Transaction, int64, error) {
+				m.ListTransactionsFunc = func(userID uuid.UUID, query services.TransactionListQuery) ([]*models.Transaction, int64, error) {
 					return []*models.Transaction{
 						{
 							ID:              testutils.TestTransactionID,
 							UserID:          userID,
-							Amount:          150.00,
+							Amount:          money.FromMajor(150),
 							Name:            "Test Transaction",
 							Category:        "Shopping",
 							Status:          "Completed",
@@ -73,9 +75,9 @@ func TestTransactionHandler_ListTransactions(t *testing.T) {
 				c.Set("userID", testutils.TestUserID)
 			},
 			mockSetup: func(m *mocks.MockTransactionService) {
-				m.GetUserTransactionsWithCountFunc = func(userID uuid.UUID, limit, offset int) ([]*models.Transaction, int64, error) {
-					if limit != 10 || offset != 10 {
-						t.Errorf("Expected limit=10 and offset=10, got limit=%d, offset=%d", limit, offset)
+				m.ListTransactionsFunc = func(userID uuid.UUID, query services.TransactionListQuery) ([]*models.Transaction, int64, error) {
+					if query.Limit != 10 || query.Offset != 10 {
+						t.Errorf("Expected limit=10 and offset=10, got limit=%d, offset=%d", query.Limit, query.Offset)
 					}
 					return []*models.Transaction{}, 25, nil
 				}
@@ -104,6 +106,211 @@ func TestTransactionHandler_ListTransactions(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:        "list with filters and sorting",
+			queryParams: "?start_date=2026-10-01&end_date=2026-10-15&category=Shopping,Transportation&category=Food%20%26%20Groceries&wallet_id=" + testutils.TestWalletID.String() + "&status=Completed&method=M-Pesa&min_amount=100&max_amount=2500.50&search=%20coffee%20&sort_by=amount&sort_order=asc",
+			setupContext: func(c *gin.Context) {
+				c.Set("userID", testutils.TestUserID)
+			},
+			mockSetup: func(m *mocks.MockTransactionService) {
+				m.ListTransactionsFunc = func(userID uuid.UUID, query services.TransactionListQuery) ([]*models.Transaction, int64, error) {
+					if !query.StartDate.Equal(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)) {
+						t.Errorf("Expected start date 2026-10-01, got %v", query.StartDate)
+					}
+					if !query.EndDate.Equal(time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)) {
+						t.Errorf("Expected the end date to include 2026-10-15, got %v", query.EndDate)
+					}
+					if len(query.Categories) != 3 || query.Categories[2] != "Food & Groceries" {
+						t.Errorf("Expected 3 categories, got %v", query.Categories)
+					}
+					if len(query.WalletIDs) != 1 || query.WalletIDs[0] != testutils.TestWalletID {
+						t.Errorf("Expected wallet filter %s, got %v", testutils.TestWalletID, query.WalletIDs)
+					}
+					if len(query.Statuses) != 1 || len(query.Methods) != 1 || query.Methods[0] != "M-Pesa" {
+						t.Errorf("Expected status and method filters, got %v and %v", query.Statuses, query.Methods)
+					}
+					if query.MinAmount == nil || *query.MinAmount != money.FromMajor(100) ||
+						query.MaxAmount == nil || *query.MaxAmount != money.MustParse("2500.50") {
+						t.Errorf("Expected amounts between 100 and 2500.50, got %v and %v", query.MinAmount, query.MaxAmount)
+					}
+					if query.Search != " coffee " || query.SortBy != "amount" || query.SortOrder != "asc" {
+						t.Errorf("Expected search and sort to be passed through, got %q, %q, %q", query.Search, query.SortBy, query.SortOrder)
+					}
+					return []*models.Transaction{}, 3, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, body map[string]interface{}) {
+				data := body["data"].(map[string]interface{})
+				pagination := data["pagination"].(map[string]interface{})
+				if pagination["total"].(float64) != 3 {
+					t.Errorf("Expected the filtered total 3, got %v", pagination["total"])
+				}
+			},
+		},
+		{
+			name:        "invalid date filter",
+			queryParams: "?start_date=01-10-2026",
+			setupContext: func(c *gin.Context) {
+				c.Set("userID", testutils.TestUserID)
+			},
+			mockSetup: func(m *mocks.MockTransactionService) {
+				m.ListTransactionsFunc = func(userID uuid.UUID, query services.TransactionListQuery) ([]*models.Transaction, int64, error) {
+					t.Error("Service should not be called for an invalid filter")
+					return nil, 0, nil
+				}
+			},
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, body map[string]interface{}) {
+				errorData := body["error"].(map[string]interface{})
+				if errorData["code"] != "VALIDATION_ERROR" {
+					t.Errorf("Expected error code VALIDATION_ERROR, got %v", errorData["code"])
+				}
+			},
+		},
+		{
+			name:        "invalid wallet filter",
+			queryParams: "?wallet_id=not-a-uuid",
+			setupContext: func(c *gin.Context) {
+				c.Set("userID", testutils.TestUserID)
+			},
+			mockSetup:      func(m *mocks.MockTransactionService) {},
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, body map[string]interface{}) {
+				if body["success"].(bool) {
+					t.Error("Expected success to be false")
+				}
+			},
+		},
+		{
+			name:        "invalid amount filter",
+			queryParams: "?min_amount=lots",
+			setupContext: func(c *gin.Context) {
+				c.Set("userID", testutils.TestUserID)
+			},
+			mockSetup:      func(m *mocks.MockTransactionService) {},
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, body map[string]interface{}) {
+				if body["success"].(bool) {
+					t.Error("Expected success to be false")
+				}
+			},
+		},
+		{
+			name:        "tag filter",
+			queryParams: "?tag_id=990e8400-e29b-41d4-a716-446655440001,990e8400-e29b-41d4-a716-446655440002",
+			setupContext: func(c *gin.Context) {
+				c.Set("userID", testutils.TestUserID)
+			},
+			mockSetup: func(m *mocks.MockTransactionService) {
+				m.ListTransactionsFunc = func(userID uuid.UUID, query services.TransactionListQuery) ([]*models.Transaction, int64, error) {
+					if len(query.TagIDs) != 2 || query.TagIDs[0].String() != "990e8400-e29b-41d4-a716-446655440001" {
+						t.Errorf("Expected two tag IDs, got %v", query.TagIDs)
+					}
+					return []*models.Transaction{}, 0, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, body map[string]interface{}) {
+				if !body["success"].(bool) {
+					t.Error("Expected success to be true")
+				}
+			},
+		},
+		{
+			name:        "invalid tag filter",
+			queryParams: "?tag_id=travel",
+			setupContext: func(c *gin.Context) {
+				c.Set("userID", testutils.TestUserID)
+			},
+			mockSetup:      func(m *mocks.MockTransactionService) {},
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, body map[string]interface{}) {
+				if body["success"].(bool) {
+					t.Error("Expected success to be false")
+				}
+			},
+		},
+		{
+			name:        "filter rejected by service",
+			queryParams: "?sort_by=password",
+			setupContext: func(c *gin.Context) {
+				c.Set("userID", testutils.TestUserID)
+			},
+			mockSetup: func(m *mocks.MockTransactionService) {
+				m.ListTransactionsFunc = func(userID uuid.UUID, query services.TransactionListQuery) ([]*models.Transaction, int64, error) {
+					return nil, 0, fmt.Errorf("%w: cannot sort by %q", services.ErrInvalidTransactionQuery, query.SortBy)
+				}
+			},
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, body map[string]interface{}) {
+				errorData := body["error"].(map[string]interface{})
+				if errorData["code"] != "VALIDATION_ERROR" {
+					t.Errorf("Expected error code VALIDATION_ERROR, got %v", errorData["code"])
+				}
+			},
+		},
+		{
+			name:        "first page with cursor pagination",
+			queryParams: "?cursor=&limit=2&category=Shopping",
+			setupContext: func(c *gin.Context) {
+				c.Set("userID", testutils.TestUserID)
+			},
+			mockSetup: func(m *mocks.MockTransactionService) {
+				m.ListTransactionsFunc = func(userID uuid.UUID, query services.TransactionListQuery) ([]*models.Transaction, int64, error) {
+					t.Error("Page listing should not be used in cursor mode")
+					return nil, 0, nil
+				}
+				m.ListTransactionsByCursorFunc = func(userID uuid.UUID, query services.TransactionListQuery) (*services.TransactionPage, error) {
+					if query.Cursor != "" || query.Limit != 2 || len(query.Categories) != 1 {
+						t.Errorf("Expected an empty cursor, limit 2 and the category filter, got %+v", query)
+					}
+					return &services.TransactionPage{
+						Transactions: []*models.Transaction{{ID: testutils.TestTransactionID}, {ID: uuid.New()}},
+						NextCursor:   "next-token",
+					}, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, body map[string]interface{}) {
+				data := body["data"].(map[string]interface{})
+				if len(data["data"].([]interface{})) != 2 {
+					t.Errorf("Expected 2 transactions, got %v", data["data"])
+				}
+				pagination := data["pagination"].(map[string]interface{})
+				if pagination["next_cursor"] != "next-token" || pagination["prev_cursor"] != "" {
+					t.Errorf("Expected only a next cursor, got %v", pagination)
+				}
+				if pagination["has_next"].(bool) != true || pagination["has_prev"].(bool) != false {
+					t.Errorf("Expected has_next without has_prev, got %v", pagination)
+				}
+				if _, ok := pagination["total"]; ok {
+					t.Error("Expected no total in cursor mode")
+				}
+			},
+		},
+		{
+			name:        "invalid cursor",
+			queryParams: "?cursor=bogus",
+			setupContext: func(c *gin.Context) {
+				c.Set("userID", testutils.TestUserID)
+			},
+			mockSetup: func(m *mocks.MockTransactionService) {
+				m.ListTransactionsByCursorFunc = func(userID uuid.UUID, query services.TransactionListQuery) (*services.TransactionPage, error) {
+					if query.Cursor != "bogus" {
+						t.Errorf("Expected the cursor to be passed through, got %q", query.Cursor)
+					}
+					return nil, fmt.Errorf("%w: invalid cursor", services.ErrInvalidTransactionQuery)
+				}
+			},
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, body map[string]interface{}) {
+				errorData := body["error"].(map[string]interface{})
+				if errorData["code"] != "VALIDATION_ERROR" {
+					t.Errorf("Expected error code VALIDATION_ERROR, got %v", errorData["code"])
+				}
+			},
+		},
 		{
 			name:        "service error",
 			queryParams: "",
@@ -111,7 +318,7 @@ func TestTransactionHandler_ListTransactions(t *testing.T) {
 				c.Set("userID", testutils.TestUserID)
 			},
 			mockSetup: func(m *mocks.MockTransactionService) {
-				m.GetUserTransactionsWithCountFunc = func(userID uuid.UUID, limit, offset int) ([]*models.Transaction, int64, error) {
+				m.ListTransactionsFunc = func(userID uuid.UUID, query services.TransactionListQuery) ([]*models.Transaction, int64, error) {
 					return nil, 0, errors.New("database error")
 				}
 			},
@@ -177,7 +384,7 @@ func TestTransactionHandler_GetTransaction(t *testing.T) {
 					return &models.Transaction{
 						ID:       id,
 						UserID:   userID,
-						Amount:   150.00,
+						Amount:   money.FromMajor(150),
 						Name:     "Test Transaction",
 						Category: "Shopping",
 						Status:   "Completed",
@@ -305,6 +512,56 @@ func TestTransactionHandler_CreateTransaction(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "split transaction without a category",
+			requestBody: map[string]interface{}{
+				"amount": 3000,
+				"name":   "Naivas",
+				"method": "Card",
+				"splits": []map[string]interface{}{
+					{"category": "Food & Groceries", "amount": 2500},
+					{"category": "Alcohol", "amount": 500, "note": "Wine"},
+				},
+			},
+			setupContext: func(c *gin.Context) {
+				c.Set("userID", testutils.TestUserID)
+			},
+			mockSetup: func(m *mocks.MockTransactionService) {
+				m.CreateTransactionFunc = func(userID uuid.UUID, req services.CreateTransactionRequest) (*models.Transaction, error) {
+					if len(req.Splits) != 2 || req.Splits[1].Note != "Wine" {
+						t.Errorf("Expected the splits to be passed on, got %+v", req.Splits)
+					}
+					return &models.Transaction{ID: testutils.TestTransactionID, UserID: userID, Amount: req.Amount, Category: req.Splits[0].Category}, nil
+				}
+			},
+			expectedStatus: http.StatusCreated,
+			checkResponse: func(t *testing.T, body map[string]interface{}) {
+				if !body["success"].(bool) {
+					t.Error("Expected success to be true")
+				}
+			},
+		},
+		{
+			name: "validation error - split line without an amount",
+			requestBody: map[string]interface{}{
+				"amount": 3000,
+				"name":   "Naivas",
+				"splits": []map[string]interface{}{
+					{"category": "Food & Groceries", "amount": 3000},
+					{"category": "Alcohol"},
+				},
+			},
+			setupContext: func(c *gin.Context) {
+				c.Set("userID", testutils.TestUserID)
+			},
+			mockSetup:      func(m *mocks.MockTransactionService) {},
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, body map[string]interface{}) {
+				if body["success"].(bool) {
+					t.Error("Expected success to be false")
+				}
+			},
+		},
 		{
 			name: "validation error - missing required fields",
 			requestBody: map[string]interface{}{
@@ -340,6 +597,26 @@ func TestTransactionHandler_CreateTransaction(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "validation error - invalid type",
+			requestBody: map[string]interface{}{
+				"amount":   50.00,
+				"type":     "refund",
+				"name":     "Test",
+				"method":   "Cash",
+				"category": "Test",
+			},
+			setupContext: func(c *gin.Context) {
+				c.Set("userID", testutils.TestUserID)
+			},
+			mockSetup:      func(m *mocks.MockTransactionService) {},
+			expectedStatus: http.StatusBadRequest,
+			checkResponse: func(t *testing.T, body map[string]interface{}) {
+				if body["success"].(bool) {
+					t.Error("Expected success to be false")
+				}
+			},
+		},
 		{
 			name: "service error",
 			requestBody: map[string]interface{}{
@@ -569,9 +846,9 @@ func TestTransactionHandler_GetTransactionStats(t *testing.T) {
 			mockSetup: func(m *mocks.MockTransactionService) {
 				m.GetTransactionStatsFunc = func(userID uuid.UUID, startDate, endDate time.Time) (*services.TransactionStats, error) {
 					return &services.TransactionStats{
-						TotalIncome:      5000.00,
-						TotalExpense:     3200.00,
-						NetBalance:       1800.00,
+						TotalIncome:      money.FromMajor(5000),
+						TotalExpense:     money.FromMajor(3200),
+						NetBalance:       money.FromMajor(1800),
 						TransactionCount: 45,
 					}, nil
 				}
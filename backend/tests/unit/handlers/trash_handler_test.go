@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/api/handlers"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+	"github.com/nyunja/fity-budget-backend/tests/unit/mocks"
+	"github.com/nyunja/fity-budget-backend/tests/unit/testutils"
+)
+
+func TestTrashHandler_RestoreItem(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	itemID := uuid.New()
+
+	tests := []struct {
+		name           string
+		path           string
+		mockSetup      func(*mocks.MockTrashService)
+		expectedStatus int
+	}{
+		{
+			name: "successful restore",
+			path: "/trash/transaction/" + itemID.String() + "/restore",
+			mockSetup: func(m *mocks.MockTrashService) {
+				m.RestoreItemFunc = func(itemType string, id, userID uuid.UUID) (interface{}, error) {
+					if itemType != services.TrashTypeTransaction || id != itemID {
+						t.Errorf("unexpected restore of %s %s", itemType, id)
+					}
+					return &models.Transaction{ID: id, UserID: userID}, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid id",
+			path:           "/trash/transaction/not-a-uuid/restore",
+			mockSetup:      func(m *mocks.MockTrashService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "unknown type",
+			path: "/trash/category/" + itemID.String() + "/restore",
+			mockSetup: func(m *mocks.MockTrashService) {
+				m.RestoreItemFunc = func(itemType string, id, userID uuid.UUID) (interface{}, error) {
+					return nil, services.ErrUnknownTrashType
+				}
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "wallet still deleted",
+			path: "/trash/transaction/" + itemID.String() + "/restore",
+			mockSetup: func(m *mocks.MockTrashService) {
+				m.RestoreItemFunc = func(itemType string, id, userID uuid.UUID) (interface{}, error) {
+					return nil, errors.New("the transaction's wallet is deleted; restore the wallet first")
+				}
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.MockTrashService{}
+			tt.mockSetup(mockService)
+			handler := handlers.NewTrashHandler(mockService)
+
+			router := testutils.SetupTestRouter()
+			router.POST("/trash/:type/:id/restore", func(c *gin.Context) {
+				c.Set("userID", testutils.TestUserID)
+				handler.RestoreItem(c)
+			})
+
+			w := testutils.MakeRequest(router, "POST", tt.path, nil, nil)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestTrashHandler_PurgeItem(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	itemID := uuid.New()
+
+	tests := []struct {
+		name           string
+		mockSetup      func(*mocks.MockTrashService)
+		expectedStatus int
+	}{
+		{
+			name: "successful purge",
+			mockSetup: func(m *mocks.MockTrashService) {
+				m.PurgeItemFunc = func(itemType string, id, userID uuid.UUID) error {
+					return nil
+				}
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "wallet still referenced",
+			mockSetup: func(m *mocks.MockTrashService) {
+				m.PurgeItemFunc = func(itemType string, id, userID uuid.UUID) error {
+					return errors.New("wallet still has transactions, transfers or imports and cannot be purged")
+				}
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.MockTrashService{}
+			tt.mockSetup(mockService)
+			handler := handlers.NewTrashHandler(mockService)
+
+			router := testutils.SetupTestRouter()
+			router.DELETE("/trash/:type/:id", func(c *gin.Context) {
+				c.Set("userID", testutils.TestUserID)
+				handler.PurgeItem(c)
+			})
+
+			w := testutils.MakeRequest(router, "DELETE", "/trash/wallet/"+itemID.String(), nil, nil)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package imports
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nyunja/fity-budget-backend/internal/imports"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+)
+
+const mpesaStatement = `MPESA FULL STATEMENT
+Customer Name:,JANE DOE
+Statement Period:,01 Mar 2024 - 31 Mar 2024
+
+Receipt No.,Completion Time,Details,Transaction Status,Paid In,Withdrawn,Balance
+SC12ABC3DE,2024-03-02 08:15:11,Customer Transfer to 0712xxx345 - JOHN,Completed,,"-1,500.00","8,500.00"
+SC13FGH4IJ,2024-03-05 17:40:02,Funds received from 0722xxx678 - MARY,Completed,"2,000.00",,"10,500.00"
+SC14KLM5NO,2024-03-06 12:00:00,Pay Bill Online to 888880 - KPLC,Completed,,-350.00,"10,150.00"
+`
+
+func TestParseCSV_DetectsMpesaStatement(t *testing.T) {
+	result, err := imports.ParseCSV(strings.NewReader(mpesaStatement), nil)
+	if err != nil {
+		t.Fatalf("ParseCSV() error = %v", err)
+	}
+
+	if result.Mapping.Date != "Completion Time" || result.Mapping.Debit != "Withdrawn" ||
+		result.Mapping.Credit != "Paid In" || result.Mapping.Reference != "Receipt No." {
+		t.Errorf("Mapping = %+v", result.Mapping)
+	}
+	if len(result.Records) != 3 {
+		t.Fatalf("len(Records) = %d, want 3", len(result.Records))
+	}
+
+	first := result.Records[0]
+	if first.Line != 6 {
+		t.Errorf("Line = %d, want 6", first.Line)
+	}
+	if first.Type != models.TransactionTypeExpense || first.Amount != money.FromMajor(1500) {
+		t.Errorf("first record = %s %s, want Expense 1500.00", first.Type, first.Amount)
+	}
+	if first.ExternalID != "SC12ABC3DE" {
+		t.Errorf("ExternalID = %q", first.ExternalID)
+	}
+	if first.Balance == nil || *first.Balance != money.FromMajor(8500) {
+		t.Errorf("Balance = %v, want 8500.00", first.Balance)
+	}
+	if want := time.Date(2024, 3, 2, 8, 15, 11, 0, time.UTC); !first.Date.Equal(want) {
+		t.Errorf("Date = %v, want %v", first.Date, want)
+	}
+
+	second := result.Records[1]
+	if second.Type != models.TransactionTypeIncome || second.Amount != money.FromMajor(2000) {
+		t.Errorf("second record = %s %s, want Income 2000.00", second.Type, second.Amount)
+	}
+}
+
+func TestParseCSV_BankDebitCreditColumns(t *testing.T) {
+	data := "\ufeffTransaction Date;Narrative;Debit;Credit;Running Balance\n" +
+		"05/03/2024;POS PURCHASE NAIVAS;1,250.00;;\n" +
+		"06/03/2024;SALARY MARCH;;85000.00;\n" +
+		"07/03/2024;ATM WITHDRAWAL;(2000.00);;\n"
+
+	result, err := imports.ParseCSV(strings.NewReader(data), nil)
+	if err != nil {
+		t.Fatalf("ParseCSV() error = %v", err)
+	}
+	if len(result.Records) != 3 {
+		t.Fatalf("len(Records) = %d, want 3", len(result.Records))
+	}
+
+	tests := []struct {
+		txnType string
+		amount  money.Amount
+		day     int
+	}{
+		{models.TransactionTypeExpense, money.FromMajor(1250), 5},
+		{models.TransactionTypeIncome, money.FromMajor(85000), 6},
+		{models.TransactionTypeExpense, money.FromMajor(2000), 7},
+	}
+	for i, tt := range tests {
+		record := result.Records[i]
+		if record.Error != "" {
+			t.Errorf("record %d Error = %q", i, record.Error)
+		}
+		if record.Type != tt.txnType || record.Amount != tt.amount {
+			t.Errorf("record %d = %s %s, want %s %s", i, record.Type, record.Amount, tt.txnType, tt.amount)
+		}
+		// Day-first dates are preferred for slash-separated values
+		if record.Date.Month() != time.March || record.Date.Day() != tt.day {
+			t.Errorf("record %d Date = %v, want %d March", i, record.Date, tt.day)
+		}
+	}
+}
+
+func TestParseCSV_ExplicitMapping(t *testing.T) {
+	data := "When,What,How Much,Ref\n" +
+		"03.04.2024,Groceries,-45.50,A1\n" +
+		"04.04.2024,Refund,12.00,A2\n"
+
+	mapping := &imports.Mapping{
+		Date:        "when",
+		Description: "What",
+		Amount:      "How Much",
+		Reference:   "Ref",
+		DateFormat:  "DD.MM.YYYY",
+	}
+	result, err := imports.ParseCSV(strings.NewReader(data), mapping)
+	if err != nil {
+		t.Fatalf("ParseCSV() error = %v", err)
+	}
+	if len(result.Records) != 2 {
+		t.Fatalf("len(Records) = %d, want 2", len(result.Records))
+	}
+	if record := result.Records[0]; record.Type != models.TransactionTypeExpense || record.Amount != money.MustParse("45.50") {
+		t.Errorf("first record = %s %s, want Expense 45.50", record.Type, record.Amount)
+	}
+	if want := time.Date(2024, 4, 3, 0, 0, 0, 0, time.UTC); !result.Records[0].Date.Equal(want) {
+		t.Errorf("Date = %v, want %v", result.Records[0].Date, want)
+	}
+	if result.Records[1].Type != models.TransactionTypeIncome {
+		t.Errorf("second record Type = %s, want Income", result.Records[1].Type)
+	}
+}
+
+func TestParseCSV_ReportsUnreadableRows(t *testing.T) {
+	data := "Date,Description,Amount\n" +
+		"2024-03-01,Coffee,-3.50\n" +
+		"yesterday,Lunch,-8.00\n" +
+		"2024-03-02,Books,lots\n" +
+		"2024-03-03,,-1.00\n" +
+		",,\n" +
+		"2024-03-04,Nothing,0\n"
+
+	result, err := imports.ParseCSV(strings.NewReader(data), nil)
+	if err != nil {
+		t.Fatalf("ParseCSV() error = %v", err)
+	}
+	// The blank line is skipped, every other line is kept
+	if len(result.Records) != 5 {
+		t.Fatalf("len(Records) = %d, want 5", len(result.Records))
+	}
+
+	wantErrors := []string{"", "unrecognised date", "invalid amount", "missing description", "missing amount"}
+	for i, want := range wantErrors {
+		got := result.Records[i].Error
+		if want == "" && got != "" || !strings.Contains(got, want) {
+			t.Errorf("record %d Error = %q, want %q", i, got, want)
+		}
+	}
+	if result.Records[1].Line != 3 || result.Records[4].Line != 7 {
+		t.Errorf("Lines = %d, %d, want 3, 7", result.Records[1].Line, result.Records[4].Line)
+	}
+}
+
+func TestParseCSV_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		mapping *imports.Mapping
+		wantErr error
+	}{
+		{
+			name:    "no header",
+			data:    "foo,bar\n1,2\n",
+			wantErr: imports.ErrInvalidFile,
+		},
+		{
+			name:    "header only",
+			data:    "Date,Description,Amount\n",
+			wantErr: imports.ErrNoRecords,
+		},
+		{
+			name:    "incomplete mapping",
+			data:    "Date,Description,Amount\n2024-03-01,Coffee,-3.50\n",
+			mapping: &imports.Mapping{Date: "Date"},
+			wantErr: imports.ErrInvalidMapping,
+		},
+		{
+			name:    "mapped column missing",
+			data:    "Date,Description,Amount\n2024-03-01,Coffee,-3.50\n",
+			mapping: &imports.Mapping{Date: "Date", Description: "Description", Amount: "Value"},
+			wantErr: imports.ErrInvalidMapping,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := imports.ParseCSV(strings.NewReader(tt.data), tt.mapping)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ParseCSV() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDetectMapping(t *testing.T) {
+	mapping, ok := imports.DetectMapping([]string{" Value Date ", "Particulars", "Amount", "Balance"})
+	if !ok {
+		t.Fatal("DetectMapping() ok = false, want true")
+	}
+	if mapping.Date != "Value Date" || mapping.Description != "Particulars" || mapping.Amount != "Amount" || mapping.Balance != "Balance" {
+		t.Errorf("DetectMapping() = %+v", mapping)
+	}
+
+	if _, ok := imports.DetectMapping([]string{"Date", "Amount"}); ok {
+		t.Error("DetectMapping() without a description column ok = true, want false")
+	}
+}
+
+func FuzzParseCSV(f *testing.F) {
+	f.Add(mpesaStatement)
+	f.Add("Date;Narrative;Debit;Credit\n05/03/2024;POS;1,250.00;\n")
+	f.Add("Date,Description,Amount\n\"2024-03-01,Coffee,-3.50\n")
+
+	f.Fuzz(func(t *testing.T, data string) {
+		result, err := imports.ParseCSV(strings.NewReader(data), nil)
+		if err != nil {
+			return
+		}
+		checkRecords(t, result.Records)
+	})
+}
@@ -0,0 +1,153 @@
+package imports
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nyunja/fity-budget-backend/internal/imports"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+)
+
+const mpesaMessages = `QCA1B2C3D4 Confirmed. Ksh1,500.00 sent to JOHN DOE 0712345678 on 5/3/24 at 8:15 AM. New M-PESA balance is Ksh8,500.00. Transaction cost, Ksh13.00. Amount you can transact within the day is 298,500.00.
+
+QCB1B2C3D5 Confirmed.You have received Ksh2,000.00 from MARY WANJIKU 0722***678 on 5/3/24 at 5:40 PM  New M-PESA balance is Ksh10,487.00.
+QCC1B2C3D6 Confirmed. Ksh350.00 sent to KPLC PREPAID for account 54321678 on 6/3/24 at 12:00 PM New M-PESA balance is Ksh10,137.00. Transaction cost, Ksh0.00.
+QCD1B2C3D7 Confirmed. Ksh450.00 paid to NAIVAS WESTLANDS. on 7/3/24 at 1:05 PM.New M-PESA balance is Ksh9,687.00. Transaction cost, Ksh0.00.
+QCE1B2C3D8 Confirmed.on 8/3/24 at 10:00 AMWithdraw Ksh2,000.00 from 123456 - ABC AGENT Nairobi New M-PESA balance is Ksh7,658.00. Transaction cost, Ksh29.00.
+QCF1B2C3D9 confirmed.You bought Ksh100.00 of airtime on 9/3/24 at 9:00 AM.New M-PESA balance is Ksh7,558.00. Transaction cost, Ksh0.00.
+QCG1B2C3E0 Confirmed. On 10/3/24 at 3:00 PM Give Ksh5,000.00 cash to XYZ AGENT Westlands New M-PESA balance is Ksh12,558.00.
+QCA1B2C3D4 Confirmed. Ksh1,500.00 sent to JOHN DOE 0712345678 on 5/3/24 at 8:15 AM. New M-PESA balance is Ksh8,500.00. Transaction cost, Ksh13.00.
+`
+
+func TestParseMpesa_Messages(t *testing.T) {
+	records, err := imports.ParseMpesa(strings.NewReader(mpesaMessages))
+	if err != nil {
+		t.Fatalf("ParseMpesa() error = %v", err)
+	}
+
+	tests := []struct {
+		externalID  string
+		txnType     string
+		amount      money.Amount
+		description string
+		category    string
+	}{
+		{"QCA1B2C3D4", models.TransactionTypeExpense, money.FromMajor(1500), "Sent to JOHN DOE", ""},
+		{"QCA1B2C3D4-FEE", models.TransactionTypeExpense, money.FromMajor(13), "M-PESA transaction cost", "Fees"},
+		{"QCB1B2C3D5", models.TransactionTypeIncome, money.FromMajor(2000), "Received from MARY WANJIKU", ""},
+		{"QCC1B2C3D6", models.TransactionTypeExpense, money.FromMajor(350), "Paid KPLC PREPAID account 54321678", ""},
+		{"QCD1B2C3D7", models.TransactionTypeExpense, money.FromMajor(450), "Paid NAIVAS WESTLANDS", ""},
+		{"QCE1B2C3D8", models.TransactionTypeExpense, money.FromMajor(2000), "Withdrawal at 123456 - ABC AGENT Nairobi", "Cash Withdrawal"},
+		{"QCE1B2C3D8-FEE", models.TransactionTypeExpense, money.FromMajor(29), "M-PESA transaction cost", "Fees"},
+		{"QCF1B2C3D9", models.TransactionTypeExpense, money.FromMajor(100), "Airtime", "Airtime"},
+		{"QCG1B2C3E0", models.TransactionTypeIncome, money.FromMajor(5000), "Deposit at XYZ AGENT Westlands", "Cash Deposit"},
+	}
+	// The repeated first message is read once
+	if len(records) != len(tests) {
+		t.Fatalf("len(records) = %d, want %d", len(records), len(tests))
+	}
+	for i, tt := range tests {
+		record := records[i]
+		if record.Error != "" {
+			t.Errorf("record %d Error = %q", i, record.Error)
+			continue
+		}
+		if record.ExternalID != tt.externalID || record.Type != tt.txnType || record.Amount != tt.amount ||
+			record.Description != tt.description || record.Category != tt.category {
+			t.Errorf("record %d = %s %s %s %q %q, want %s %s %s %q %q", i,
+				record.ExternalID, record.Type, record.Amount, record.Description, record.Category,
+				tt.externalID, tt.txnType, tt.amount, tt.description, tt.category)
+		}
+	}
+
+	first := records[0]
+	if want := time.Date(2024, 3, 5, 8, 15, 0, 0, time.UTC); !first.Date.Equal(want) {
+		t.Errorf("Date = %v, want %v", first.Date, want)
+	}
+	if first.Balance == nil || *first.Balance != money.FromMajor(8500) {
+		t.Errorf("Balance = %v, want 8500.00", first.Balance)
+	}
+	if records[2].Line != 3 {
+		t.Errorf("Line = %d, want 3", records[2].Line)
+	}
+}
+
+func TestParseMpesa_Statement(t *testing.T) {
+	statement := `M-PESA STATEMENT
+Receipt No. Completion Time Details Transaction Status Paid In Withdrawn Balance
+SC12ABC3DE 2024-03-02 08:15:11 Customer Transfer to 0712xxx345 - Completed -1,500.00 8,500.00
+JOHN DOE
+SC12ABC3DE 2024-03-02 08:15:11 Customer Transfer of Funds Charge Completed -13.00 8,487.00
+SC13FGH4IJ 2024-03-05 17:40:02 Funds received from 0722xxx678 - MARY Completed 2,000.00 10,487.00
+SC14KLM5NO 2024-03-06 12:00:00 Pay Bill Online to 888880 - KPLC Failed -350.00 10,487.00
+`
+	records, err := imports.ParseMpesa(strings.NewReader(statement))
+	if err != nil {
+		t.Fatalf("ParseMpesa() error = %v", err)
+	}
+	if len(records) != 4 {
+		t.Fatalf("len(records) = %d, want 4", len(records))
+	}
+
+	sent := records[0]
+	if sent.Description != "Customer Transfer to 0712xxx345 - JOHN DOE" || sent.Amount != money.FromMajor(1500) || sent.Type != models.TransactionTypeExpense {
+		t.Errorf("sent = %q %s %s", sent.Description, sent.Type, sent.Amount)
+	}
+	if sent.Line != 3 || sent.Balance == nil || *sent.Balance != money.FromMajor(8500) {
+		t.Errorf("sent line %d balance %v", sent.Line, sent.Balance)
+	}
+	if want := time.Date(2024, 3, 2, 8, 15, 11, 0, time.UTC); !sent.Date.Equal(want) {
+		t.Errorf("Date = %v, want %v", sent.Date, want)
+	}
+
+	// The charge shares the code of its transfer and matches the fee of its SMS
+	charge := records[1]
+	if charge.ExternalID != "SC12ABC3DE"+imports.MpesaFeeSuffix || charge.Category != "Fees" || charge.Amount != money.FromMajor(13) {
+		t.Errorf("charge = %s %q %s", charge.ExternalID, charge.Category, charge.Amount)
+	}
+	if records[2].Type != models.TransactionTypeIncome {
+		t.Errorf("received Type = %s, want Income", records[2].Type)
+	}
+	if records[3].Error != "transaction failed" {
+		t.Errorf("failed row Error = %q", records[3].Error)
+	}
+}
+
+func TestParseMpesa_Errors(t *testing.T) {
+	if _, err := imports.ParseMpesa(strings.NewReader("Hello, your order has shipped")); !errors.Is(err, imports.ErrNoRecords) {
+		t.Errorf("ParseMpesa() error = %v, want ErrNoRecords", err)
+	}
+
+	records, err := imports.ParseMpesa(strings.NewReader("QCA1B2C3D4 Confirmed. Your M-PESA PIN has been changed."))
+	if err != nil {
+		t.Fatalf("ParseMpesa() error = %v", err)
+	}
+	if records[0].Error == "" || records[0].ExternalID != "QCA1B2C3D4" {
+		t.Errorf("unrecognised message = %+v", records[0])
+	}
+}
+
+func FuzzParseMpesa(f *testing.F) {
+	f.Add(mpesaMessages)
+	f.Add("SC12ABC3DE 2024-03-02 08:15:11 Charge Completed -13.00 8,487.00\n")
+	f.Add("QCA1B2C3D4 Confirmed. Ksh0 sent to A on 31/2/24 at 8:15 am.")
+	f.Add("QCE1B2C3D8 Confirmed.on 8/3/2024 at 10:00 PMWithdraw Ksh2 from X New M-PESA balance is Ksh")
+
+	f.Fuzz(func(t *testing.T, data string) {
+		records, err := imports.ParseMpesa(strings.NewReader(data))
+		if err != nil {
+			return
+		}
+		checkRecords(t, records)
+		seen := make(map[string]bool)
+		for _, record := range records {
+			if record.ExternalID == "" || seen[record.ExternalID] {
+				t.Errorf("ExternalID %q missing or repeated", record.ExternalID)
+			}
+			seen[record.ExternalID] = true
+		}
+	})
+}
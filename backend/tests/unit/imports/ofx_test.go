@@ -0,0 +1,192 @@
+package imports
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nyunja/fity-budget-backend/internal/imports"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+)
+
+const sgmlStatement = `OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+ENCODING:USASCII
+CHARSET:1252
+
+<OFX>
+<SIGNONMSGSRSV1><SONRS><STATUS><CODE>0<SEVERITY>INFO</STATUS><DTSERVER>20240331120000</SONRS></SIGNONMSGSRSV1>
+<BANKMSGSRSV1><STMTTRNRS><STMTRS><CURDEF>KES
+<BANKTRANLIST>
+<DTSTART>20240301<DTEND>20240331
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20240302083000.000[+3:EAT]
+<TRNAMT>-1,250.50
+<FITID>2024030201
+<NAME>NAIVAS WESTLANDS
+<MEMO>POS PURCHASE
+</STMTTRN>
+<STMTTRN>
+<TRNTYPE>CREDIT
+<DTPOSTED>20240305
+<TRNAMT>85000.00
+<FITID>2024030502
+<NAME>SALARY &amp; ALLOWANCES
+</STMTTRN>
+</BANKTRANLIST>
+<LEDGERBAL><BALAMT>83749.50<DTASOF>20240331</LEDGERBAL>
+</STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>
+`
+
+const xmlStatement = `<?xml version="1.0" encoding="UTF-8" standalone="no"?>
+<?OFX OFXHEADER="200" VERSION="211" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>
+<OFX>
+  <CREDITCARDMSGSRSV1>
+    <CCSTMTTRNRS>
+      <CCSTMTRS>
+        <BANKTRANLIST>
+          <STMTTRN>
+            <TRNTYPE>DEBIT</TRNTYPE>
+            <DTPOSTED>20240310</DTPOSTED>
+            <TRNAMT>-45,99</TRNAMT>
+            <FITID>CC-0001</FITID>
+            <PAYEE><NAME>Java House</NAME></PAYEE>
+          </STMTTRN>
+          <STMTTRN>
+            <TRNTYPE>FEE</TRNTYPE>
+            <DTPOSTED>20240311</DTPOSTED>
+            <TRNAMT>-5.00</TRNAMT>
+          </STMTTRN>
+        </BANKTRANLIST>
+      </CCSTMTRS>
+    </CCSTMTTRNRS>
+  </CREDITCARDMSGSRSV1>
+</OFX>
+`
+
+func TestParseOFX_SGML(t *testing.T) {
+	records, err := imports.ParseOFX(strings.NewReader(sgmlStatement))
+	if err != nil {
+		t.Fatalf("ParseOFX() error = %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("len(records) = %d, want 2", len(records))
+	}
+
+	first := records[0]
+	if first.Error != "" {
+		t.Fatalf("Error = %q", first.Error)
+	}
+	if first.Line != 12 {
+		t.Errorf("Line = %d, want 12", first.Line)
+	}
+	if first.Type != models.TransactionTypeExpense || first.Amount != money.MustParse("1250.50") {
+		t.Errorf("first record = %s %s, want Expense 1250.50", first.Type, first.Amount)
+	}
+	if first.ExternalID != "2024030201" {
+		t.Errorf("ExternalID = %q, want the FITID", first.ExternalID)
+	}
+	if first.Description != "NAIVAS WESTLANDS - POS PURCHASE" {
+		t.Errorf("Description = %q", first.Description)
+	}
+	// The bank's wall-clock time is kept
+	if want := time.Date(2024, 3, 2, 8, 30, 0, 0, time.UTC); !first.Date.Equal(want) {
+		t.Errorf("Date = %v, want %v", first.Date, want)
+	}
+
+	second := records[1]
+	if second.Type != models.TransactionTypeIncome || second.Description != "SALARY & ALLOWANCES" {
+		t.Errorf("second record = %s %q", second.Type, second.Description)
+	}
+}
+
+func TestParseOFX_XML(t *testing.T) {
+	records, err := imports.ParseOFX(strings.NewReader(xmlStatement))
+	if err != nil {
+		t.Fatalf("ParseOFX() error = %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("len(records) = %d, want 2", len(records))
+	}
+
+	if records[0].Amount != money.MustParse("45.99") || records[0].Description != "Java House" {
+		t.Errorf("first record = %s %q", records[0].Amount, records[0].Description)
+	}
+	// Transactions without a FITID or name fall back to a derived ID and their type
+	fee := records[1]
+	if fee.Description != "FEE" || !strings.HasPrefix(fee.ExternalID, "ofx:") {
+		t.Errorf("fee record = %q %q", fee.Description, fee.ExternalID)
+	}
+
+	again, err := imports.ParseOFX(strings.NewReader(xmlStatement))
+	if err != nil {
+		t.Fatalf("ParseOFX() error = %v", err)
+	}
+	if again[1].ExternalID != fee.ExternalID {
+		t.Error("derived IDs should be the same when a file is read again")
+	}
+}
+
+func TestParseOFX_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr error
+	}{
+		{name: "not ofx", data: "Date,Description,Amount\n", wantErr: imports.ErrInvalidFile},
+		{name: "no transactions", data: "<OFX><BANKTRANLIST></BANKTRANLIST></OFX>", wantErr: imports.ErrNoRecords},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := imports.ParseOFX(strings.NewReader(tt.data)); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ParseOFX() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// checkRecords verifies the invariants every parser guarantees
+func checkRecords(t *testing.T, records []*imports.Record) {
+	t.Helper()
+	if len(records) > imports.MaxRecords {
+		t.Fatalf("len(records) = %d, above MaxRecords", len(records))
+	}
+	for _, record := range records {
+		if record.Line < 1 {
+			t.Errorf("Line = %d, want a line number", record.Line)
+		}
+		if record.Error != "" {
+			continue
+		}
+		if record.Amount <= 0 {
+			t.Errorf("Amount = %s, want a positive amount", record.Amount)
+		}
+		if record.Type != models.TransactionTypeIncome && record.Type != models.TransactionTypeExpense {
+			t.Errorf("Type = %q", record.Type)
+		}
+		if record.Description == "" || record.Date.IsZero() {
+			t.Errorf("readable record without description or date: %+v", record)
+		}
+	}
+}
+
+func FuzzParseOFX(f *testing.F) {
+	f.Add(sgmlStatement)
+	f.Add(xmlStatement)
+	f.Add("<OFX><STMTTRN><TRNAMT>1<DTPOSTED>2024")
+	f.Add("<OFX><STMTTRN><NAME>&#xZZ;<TRNAMT>-.5e3<DTPOSTED>20241301</STMTTRN>")
+
+	f.Fuzz(func(t *testing.T, data string) {
+		records, err := imports.ParseOFX(strings.NewReader(data))
+		if err != nil {
+			return
+		}
+		checkRecords(t, records)
+	})
+}
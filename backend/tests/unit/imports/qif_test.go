@@ -0,0 +1,129 @@
+package imports
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nyunja/fity-budget-backend/internal/imports"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+)
+
+const qifStatement = `!Type:Cat
+NGroceries
+E
+^
+!Type:Bank
+D3/ 1'24
+T-1,250.00
+PNaivas
+LGroceries:Food
+^
+D03/02/2024
+T-20.00
+PKPLC Tokens
+MPrepaid
+^
+D03/02/2024
+T-20.00
+PKPLC Tokens
+MPrepaid
+^
+D3/5/24
+U5000.00
+T5000.00
+PEmployer
+L[Savings]
+^
+`
+
+func TestParseQIF(t *testing.T) {
+	records, err := imports.ParseQIF(strings.NewReader(qifStatement))
+	if err != nil {
+		t.Fatalf("ParseQIF() error = %v", err)
+	}
+	if len(records) != 4 {
+		t.Fatalf("len(records) = %d, want 4", len(records))
+	}
+
+	first := records[0]
+	if first.Error != "" {
+		t.Fatalf("Error = %q", first.Error)
+	}
+	if first.Line != 6 || first.Description != "Naivas" || first.Category != "Groceries" {
+		t.Errorf("first record = line %d %q %q", first.Line, first.Description, first.Category)
+	}
+	if first.Type != models.TransactionTypeExpense || first.Amount != money.FromMajor(1250) {
+		t.Errorf("first record = %s %s, want Expense 1250.00", first.Type, first.Amount)
+	}
+	// Dates are month-first unless the file shows otherwise
+	if want := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC); !first.Date.Equal(want) {
+		t.Errorf("Date = %v, want %v", first.Date, want)
+	}
+
+	if records[1].ExternalID == "" || records[1].ExternalID == records[2].ExternalID {
+		t.Error("identical lines should get distinct derived IDs")
+	}
+	if records[1].Description != "KPLC Tokens - Prepaid" {
+		t.Errorf("Description = %q", records[1].Description)
+	}
+	if records[3].Type != models.TransactionTypeIncome || records[3].Category != "Transfer" {
+		t.Errorf("last record = %s %q", records[3].Type, records[3].Category)
+	}
+}
+
+func TestParseQIF_DayFirstDates(t *testing.T) {
+	data := "!Type:Bank\nD13/03/2024\nT-10\nPLunch\n^\nD02/03/2024\nT-5\nPBus\n^\n"
+
+	records, err := imports.ParseQIF(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("ParseQIF() error = %v", err)
+	}
+	if want := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC); !records[1].Date.Equal(want) {
+		t.Errorf("Date = %v, want %v", records[1].Date, want)
+	}
+}
+
+func TestParseQIF_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr error
+	}{
+		{name: "no type header", data: "D3/1/24\nT-1\n^\n", wantErr: imports.ErrInvalidFile},
+		{name: "only categories", data: "!Type:Cat\nNFood\n^\n", wantErr: imports.ErrNoRecords},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := imports.ParseQIF(strings.NewReader(tt.data)); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ParseQIF() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	records, err := imports.ParseQIF(strings.NewReader("!Type:Bank\nD31/02/2024\nT-1\nPX\n^\n"))
+	if err != nil {
+		t.Fatalf("ParseQIF() error = %v", err)
+	}
+	if records[0].Error == "" {
+		t.Error("an impossible date should be reported on its record")
+	}
+}
+
+func FuzzParseQIF(f *testing.F) {
+	f.Add(qifStatement)
+	f.Add("!Type:Bank\nD13/03/2024\nT-10\nPLunch\n^\n")
+	f.Add("!Type:CCard\nD2024-03-01\nT(5.00)\nM\n^\n!Account\nNChecking\n^")
+	f.Add("!Type:Bank\n^\n^\nD0/0/0\nT0\n")
+
+	f.Fuzz(func(t *testing.T, data string) {
+		records, err := imports.ParseQIF(strings.NewReader(data))
+		if err != nil {
+			return
+		}
+		checkRecords(t, records)
+	})
+}
@@ -10,7 +10,8 @@ import (
 // MockAnalyticsService is a mock implementation of AnalyticsService
 type MockAnalyticsService struct {
 	GetDashboardSummaryFunc      func(userID uuid.UUID) (*services.DashboardSummary, error)
-	GetSpendingByCategoryFunc    func(userID uuid.UUID, startDate, endDate time.Time) ([]*services.CategorySpending, error)
+	GetSpendingByCategoryFunc    func(userID uuid.UUID, startDate, endDate time.Time, rollup bool) (*services.SpendingByCategoryReport, error)
+	GetSpendingByTagFunc         func(userID uuid.UUID, startDate, endDate time.Time) (*services.SpendingByTagReport, error)
 	GetIncomeVsExpenseFunc       func(userID uuid.UUID, period string) (*services.IncomeVsExpenseReport, error)
 	GetMonthlyTrendsFunc         func(userID uuid.UUID, months int) (*services.MonthlyTrends, error)
 	GetFinancialHealthScoreFunc  func(userID uuid.UUID) (*services.FinancialHealthScore, error)
@@ -23,9 +24,16 @@ func (m *MockAnalyticsService) GetDashboardSummary(userID uuid.UUID) (*services.
 	return nil, nil
 }
 
-func (m *MockAnalyticsService) GetSpendingByCategory(userID uuid.UUID, startDate, endDate time.Time) ([]*services.CategorySpending, error) {
+func (m *MockAnalyticsService) GetSpendingByCategory(userID uuid.UUID, startDate, endDate time.Time, rollup bool) (*services.SpendingByCategoryReport, error) {
 	if m.GetSpendingByCategoryFunc != nil {
-		return m.GetSpendingByCategoryFunc(userID, startDate, endDate)
+		return m.GetSpendingByCategoryFunc(userID, startDate, endDate, rollup)
+	}
+	return nil, nil
+}
+
+func (m *MockAnalyticsService) GetSpendingByTag(userID uuid.UUID, startDate, endDate time.Time) (*services.SpendingByTagReport, error) {
+	if m.GetSpendingByTagFunc != nil {
+		return m.GetSpendingByTagFunc(userID, startDate, endDate)
 	}
 	return nil, nil
 }
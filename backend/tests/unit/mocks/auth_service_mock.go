@@ -3,6 +3,7 @@ package mocks
 import (
 	"github.com/google/uuid"
 	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
 )
 
 // MockAuthService is a mock implementation of AuthService
@@ -11,7 +12,7 @@ type MockAuthService struct {
 	LoginFunc              func(email, password string) (*models.User, string, error)
 	GetUserByIDFunc        func(id uuid.UUID) (*models.User, error)
 	UpdateProfileFunc      func(id uuid.UUID, name, email string) (*models.User, error)
-	CompleteOnboardingFunc func(id uuid.UUID, monthlyIncome float64, currency string) error
+	CompleteOnboardingFunc func(id uuid.UUID, monthlyIncome money.Amount, currency string) error
 }
 
 func (m *MockAuthService) Register(name, email, password string) (*models.User, string, error) {
@@ -42,7 +43,7 @@ func (m *MockAuthService) UpdateProfile(id uuid.UUID, name, email string) (*mode
 	return nil, nil
 }
 
-func (m *MockAuthService) CompleteOnboarding(id uuid.UUID, monthlyIncome float64, currency string) error {
+func (m *MockAuthService) CompleteOnboarding(id uuid.UUID, monthlyIncome money.Amount, currency string) error {
 	if m.CompleteOnboardingFunc != nil {
 		return m.CompleteOnboardingFunc(id, monthlyIncome, currency)
 	}
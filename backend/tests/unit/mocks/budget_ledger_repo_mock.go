@@ -0,0 +1,33 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// MockBudgetLedgerRepository is a mock implementation of BudgetLedgerRepository
+type MockBudgetLedgerRepository struct {
+	FindByBudgetIDFunc func(budgetID uuid.UUID) ([]*models.BudgetLedgerEntry, error)
+	AppendFunc         func(entries []*models.BudgetLedgerEntry) error
+}
+
+func (m *MockBudgetLedgerRepository) FindByBudgetID(budgetID uuid.UUID) ([]*models.BudgetLedgerEntry, error) {
+	if m.FindByBudgetIDFunc != nil {
+		return m.FindByBudgetIDFunc(budgetID)
+	}
+	return nil, nil
+}
+
+func (m *MockBudgetLedgerRepository) Append(entries []*models.BudgetLedgerEntry) error {
+	if m.AppendFunc != nil {
+		return m.AppendFunc(entries)
+	}
+	return nil
+}
+
+// WithTx returns the mock itself so calls made inside a transaction stay observable
+func (m *MockBudgetLedgerRepository) WithTx(tx *gorm.DB) repository.BudgetLedgerRepository {
+	return m
+}
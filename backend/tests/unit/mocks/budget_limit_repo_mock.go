@@ -0,0 +1,33 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// MockBudgetLimitRepository is a mock implementation of BudgetLimitRepository
+type MockBudgetLimitRepository struct {
+	FindByBudgetIDsFunc func(budgetIDs []uuid.UUID) ([]*models.BudgetLimit, error)
+	UpsertFunc          func(limit *models.BudgetLimit) error
+}
+
+func (m *MockBudgetLimitRepository) FindByBudgetIDs(budgetIDs []uuid.UUID) ([]*models.BudgetLimit, error) {
+	if m.FindByBudgetIDsFunc != nil {
+		return m.FindByBudgetIDsFunc(budgetIDs)
+	}
+	return nil, nil
+}
+
+func (m *MockBudgetLimitRepository) Upsert(limit *models.BudgetLimit) error {
+	if m.UpsertFunc != nil {
+		return m.UpsertFunc(limit)
+	}
+	return nil
+}
+
+// WithTx returns the mock itself so calls made inside a transaction stay observable
+func (m *MockBudgetLimitRepository) WithTx(tx *gorm.DB) repository.BudgetLimitRepository {
+	return m
+}
@@ -0,0 +1,138 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// MockBudgetRepository is a mock implementation of BudgetRepository
+type MockBudgetRepository struct {
+	CreateFunc                  func(budget *models.Budget) error
+	FindByIDFunc                func(id uuid.UUID) (*models.Budget, error)
+	FindByUserIDFunc            func(userID uuid.UUID) ([]*models.Budget, error)
+	FindByUserIDAndCategoryFunc func(userID uuid.UUID, category string) (*models.Budget, error)
+	FindAllFunc                 func() ([]*models.Budget, error)
+	FindSnapshotDueFunc         func(now time.Time, limit int) ([]*models.Budget, error)
+	SetNextSnapshotFunc         func(id uuid.UUID, next *time.Time) error
+	DeferSnapshotFunc           func(id uuid.UUID, failures int, retryAt time.Time) error
+	UpdateFunc                  func(budget *models.Budget) error
+	DeleteFunc                  func(id uuid.UUID) error
+	FindDeletedByUserIDFunc     func(userID uuid.UUID) ([]*models.Budget, error)
+	FindDeletedByIDFunc         func(id uuid.UUID) (*models.Budget, error)
+	FindDeletedBeforeFunc       func(cutoff time.Time, limit int) ([]*models.Budget, error)
+	RestoreFunc                 func(id uuid.UUID) error
+	PurgeFunc                   func(id uuid.UUID) error
+}
+
+func (m *MockBudgetRepository) Create(budget *models.Budget) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(budget)
+	}
+	return nil
+}
+
+func (m *MockBudgetRepository) FindByID(id uuid.UUID) (*models.Budget, error) {
+	if m.FindByIDFunc != nil {
+		return m.FindByIDFunc(id)
+	}
+	return nil, nil
+}
+
+func (m *MockBudgetRepository) FindByUserID(userID uuid.UUID) ([]*models.Budget, error) {
+	if m.FindByUserIDFunc != nil {
+		return m.FindByUserIDFunc(userID)
+	}
+	return nil, nil
+}
+
+func (m *MockBudgetRepository) FindByUserIDAndCategory(userID uuid.UUID, category string) (*models.Budget, error) {
+	if m.FindByUserIDAndCategoryFunc != nil {
+		return m.FindByUserIDAndCategoryFunc(userID, category)
+	}
+	return nil, nil
+}
+
+func (m *MockBudgetRepository) FindAll() ([]*models.Budget, error) {
+	if m.FindAllFunc != nil {
+		return m.FindAllFunc()
+	}
+	return nil, nil
+}
+
+func (m *MockBudgetRepository) FindSnapshotDue(now time.Time, limit int) ([]*models.Budget, error) {
+	if m.FindSnapshotDueFunc != nil {
+		return m.FindSnapshotDueFunc(now, limit)
+	}
+	return nil, nil
+}
+
+func (m *MockBudgetRepository) SetNextSnapshot(id uuid.UUID, next *time.Time) error {
+	if m.SetNextSnapshotFunc != nil {
+		return m.SetNextSnapshotFunc(id, next)
+	}
+	return nil
+}
+
+func (m *MockBudgetRepository) DeferSnapshot(id uuid.UUID, failures int, retryAt time.Time) error {
+	if m.DeferSnapshotFunc != nil {
+		return m.DeferSnapshotFunc(id, failures, retryAt)
+	}
+	return nil
+}
+
+func (m *MockBudgetRepository) Update(budget *models.Budget) error {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(budget)
+	}
+	return nil
+}
+
+func (m *MockBudgetRepository) Delete(id uuid.UUID) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(id)
+	}
+	return nil
+}
+
+func (m *MockBudgetRepository) FindDeletedByUserID(userID uuid.UUID) ([]*models.Budget, error) {
+	if m.FindDeletedByUserIDFunc != nil {
+		return m.FindDeletedByUserIDFunc(userID)
+	}
+	return nil, nil
+}
+
+func (m *MockBudgetRepository) FindDeletedByID(id uuid.UUID) (*models.Budget, error) {
+	if m.FindDeletedByIDFunc != nil {
+		return m.FindDeletedByIDFunc(id)
+	}
+	return nil, nil
+}
+
+func (m *MockBudgetRepository) FindDeletedBefore(cutoff time.Time, limit int) ([]*models.Budget, error) {
+	if m.FindDeletedBeforeFunc != nil {
+		return m.FindDeletedBeforeFunc(cutoff, limit)
+	}
+	return nil, nil
+}
+
+func (m *MockBudgetRepository) Restore(id uuid.UUID) error {
+	if m.RestoreFunc != nil {
+		return m.RestoreFunc(id)
+	}
+	return nil
+}
+
+func (m *MockBudgetRepository) Purge(id uuid.UUID) error {
+	if m.PurgeFunc != nil {
+		return m.PurgeFunc(id)
+	}
+	return nil
+}
+
+func (m *MockBudgetRepository) WithTx(tx *gorm.DB) repository.BudgetRepository {
+	return m
+}
@@ -1,20 +1,27 @@
 package mocks
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/nyunja/fity-budget-backend/internal/models"
 	"github.com/nyunja/fity-budget-backend/internal/services"
+	"gorm.io/gorm"
 )
 
 // MockBudgetService is a mock implementation of BudgetService
 type MockBudgetService struct {
-	CreateBudgetFunc      func(userID uuid.UUID, req services.CreateBudgetRequest) (*models.Budget, error)
-	GetUserBudgetsFunc    func(userID uuid.UUID) ([]*models.Budget, error)
-	GetBudgetByIDFunc     func(id, userID uuid.UUID) (*models.Budget, error)
-	UpdateBudgetFunc      func(id, userID uuid.UUID, req services.UpdateBudgetRequest) (*models.Budget, error)
-	DeleteBudgetFunc      func(id, userID uuid.UUID) error
-	CheckBudgetStatusFunc func(userID uuid.UUID) ([]*services.BudgetStatus, error)
-	GetBudgetSummaryFunc  func(userID uuid.UUID) (*services.BudgetSummary, error)
+	CreateBudgetFunc          func(userID uuid.UUID, req services.CreateBudgetRequest) (*models.Budget, error)
+	GetUserBudgetsFunc        func(userID uuid.UUID) ([]*models.Budget, error)
+	GetBudgetByIDFunc         func(id, userID uuid.UUID) (*models.Budget, error)
+	UpdateBudgetFunc          func(id, userID uuid.UUID, req services.UpdateBudgetRequest) (*models.Budget, error)
+	DeleteBudgetFunc          func(id, userID uuid.UUID) error
+	CheckBudgetStatusFunc     func(userID uuid.UUID, at time.Time) ([]*services.BudgetStatus, error)
+	GetBudgetStatusFunc       func(id, userID uuid.UUID, at time.Time) (*services.BudgetStatus, error)
+	GetBudgetSummaryFunc      func(userID uuid.UUID, at time.Time) (*services.BudgetSummary, error)
+	GetBudgetLedgerFunc       func(id, userID uuid.UUID) ([]*models.BudgetLedgerEntry, error)
+	GetBudgetHistoryFunc      func(userID uuid.UUID, periods int, at time.Time) ([]*services.BudgetHistory, error)
+	SnapshotClosedPeriodsFunc func(now time.Time) (int, error)
 }
 
 func (m *MockBudgetService) CreateBudget(userID uuid.UUID, req services.CreateBudgetRequest) (*models.Budget, error) {
@@ -52,16 +59,49 @@ func (m *MockBudgetService) DeleteBudget(id, userID uuid.UUID) error {
 	return nil
 }
 
-func (m *MockBudgetService) CheckBudgetStatus(userID uuid.UUID) ([]*services.BudgetStatus, error) {
+func (m *MockBudgetService) CheckBudgetStatus(userID uuid.UUID, at time.Time) ([]*services.BudgetStatus, error) {
 	if m.CheckBudgetStatusFunc != nil {
-		return m.CheckBudgetStatusFunc(userID)
+		return m.CheckBudgetStatusFunc(userID, at)
+	}
+	return nil, nil
+}
+
+func (m *MockBudgetService) GetBudgetStatus(id, userID uuid.UUID, at time.Time) (*services.BudgetStatus, error) {
+	if m.GetBudgetStatusFunc != nil {
+		return m.GetBudgetStatusFunc(id, userID, at)
 	}
 	return nil, nil
 }
 
-func (m *MockBudgetService) GetBudgetSummary(userID uuid.UUID) (*services.BudgetSummary, error) {
+func (m *MockBudgetService) GetBudgetSummary(userID uuid.UUID, at time.Time) (*services.BudgetSummary, error) {
 	if m.GetBudgetSummaryFunc != nil {
-		return m.GetBudgetSummaryFunc(userID)
+		return m.GetBudgetSummaryFunc(userID, at)
+	}
+	return nil, nil
+}
+
+func (m *MockBudgetService) GetBudgetLedger(id, userID uuid.UUID) ([]*models.BudgetLedgerEntry, error) {
+	if m.GetBudgetLedgerFunc != nil {
+		return m.GetBudgetLedgerFunc(id, userID)
 	}
 	return nil, nil
 }
+
+func (m *MockBudgetService) GetBudgetHistory(userID uuid.UUID, periods int, at time.Time) ([]*services.BudgetHistory, error) {
+	if m.GetBudgetHistoryFunc != nil {
+		return m.GetBudgetHistoryFunc(userID, periods, at)
+	}
+	return nil, nil
+}
+
+func (m *MockBudgetService) SnapshotClosedPeriods(now time.Time) (int, error) {
+	if m.SnapshotClosedPeriodsFunc != nil {
+		return m.SnapshotClosedPeriodsFunc(now)
+	}
+	return 0, nil
+}
+
+// WithTx returns the mock itself so calls made inside a transaction stay observable
+func (m *MockBudgetService) WithTx(tx *gorm.DB) services.BudgetService {
+	return m
+}
@@ -0,0 +1,57 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// MockBudgetTemplateRepository is a mock implementation of BudgetTemplateRepository
+type MockBudgetTemplateRepository struct {
+	CreateFunc              func(template *models.BudgetTemplate) error
+	FindByIDFunc            func(id uuid.UUID) (*models.BudgetTemplate, error)
+	FindByUserIDFunc        func(userID uuid.UUID) ([]*models.BudgetTemplate, error)
+	FindByUserIDAndNameFunc func(userID uuid.UUID, name string) (*models.BudgetTemplate, error)
+	DeleteFunc              func(id uuid.UUID) error
+}
+
+func (m *MockBudgetTemplateRepository) Create(template *models.BudgetTemplate) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(template)
+	}
+	return nil
+}
+
+func (m *MockBudgetTemplateRepository) FindByID(id uuid.UUID) (*models.BudgetTemplate, error) {
+	if m.FindByIDFunc != nil {
+		return m.FindByIDFunc(id)
+	}
+	return nil, nil
+}
+
+func (m *MockBudgetTemplateRepository) FindByUserID(userID uuid.UUID) ([]*models.BudgetTemplate, error) {
+	if m.FindByUserIDFunc != nil {
+		return m.FindByUserIDFunc(userID)
+	}
+	return nil, nil
+}
+
+func (m *MockBudgetTemplateRepository) FindByUserIDAndName(userID uuid.UUID, name string) (*models.BudgetTemplate, error) {
+	if m.FindByUserIDAndNameFunc != nil {
+		return m.FindByUserIDAndNameFunc(userID, name)
+	}
+	return nil, nil
+}
+
+func (m *MockBudgetTemplateRepository) Delete(id uuid.UUID) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(id)
+	}
+	return nil
+}
+
+// WithTx returns the mock itself so calls made inside a transaction stay observable
+func (m *MockBudgetTemplateRepository) WithTx(tx *gorm.DB) repository.BudgetTemplateRepository {
+	return m
+}
@@ -0,0 +1,68 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+)
+
+// MockBudgetTemplateService is a mock implementation of BudgetTemplateService
+type MockBudgetTemplateService struct {
+	ListTemplatesFunc   func(userID uuid.UUID) (*services.BudgetTemplateList, error)
+	CreateTemplateFunc  func(userID uuid.UUID, req services.CreateBudgetTemplateRequest) (*models.BudgetTemplate, error)
+	DeleteTemplateFunc  func(id, userID uuid.UUID) error
+	PreviewBudgetsFunc  func(userID uuid.UUID, req services.GenerateBudgetsRequest) (*services.BudgetPlan, error)
+	GenerateBudgetsFunc func(userID uuid.UUID, req services.GenerateBudgetsRequest) (*services.BudgetPlan, error)
+	PreviewRescaleFunc  func(userID uuid.UUID, income money.Amount) (*services.BudgetPlan, error)
+	RescaleBudgetsFunc  func(userID uuid.UUID, income money.Amount) (*services.BudgetPlan, error)
+}
+
+func (m *MockBudgetTemplateService) ListTemplates(userID uuid.UUID) (*services.BudgetTemplateList, error) {
+	if m.ListTemplatesFunc != nil {
+		return m.ListTemplatesFunc(userID)
+	}
+	return nil, nil
+}
+
+func (m *MockBudgetTemplateService) CreateTemplate(userID uuid.UUID, req services.CreateBudgetTemplateRequest) (*models.BudgetTemplate, error) {
+	if m.CreateTemplateFunc != nil {
+		return m.CreateTemplateFunc(userID, req)
+	}
+	return nil, nil
+}
+
+func (m *MockBudgetTemplateService) DeleteTemplate(id, userID uuid.UUID) error {
+	if m.DeleteTemplateFunc != nil {
+		return m.DeleteTemplateFunc(id, userID)
+	}
+	return nil
+}
+
+func (m *MockBudgetTemplateService) PreviewBudgets(userID uuid.UUID, req services.GenerateBudgetsRequest) (*services.BudgetPlan, error) {
+	if m.PreviewBudgetsFunc != nil {
+		return m.PreviewBudgetsFunc(userID, req)
+	}
+	return nil, nil
+}
+
+func (m *MockBudgetTemplateService) GenerateBudgets(userID uuid.UUID, req services.GenerateBudgetsRequest) (*services.BudgetPlan, error) {
+	if m.GenerateBudgetsFunc != nil {
+		return m.GenerateBudgetsFunc(userID, req)
+	}
+	return nil, nil
+}
+
+func (m *MockBudgetTemplateService) PreviewRescale(userID uuid.UUID, income money.Amount) (*services.BudgetPlan, error) {
+	if m.PreviewRescaleFunc != nil {
+		return m.PreviewRescaleFunc(userID, income)
+	}
+	return nil, nil
+}
+
+func (m *MockBudgetTemplateService) RescaleBudgets(userID uuid.UUID, income money.Amount) (*services.BudgetPlan, error) {
+	if m.RescaleBudgetsFunc != nil {
+		return m.RescaleBudgetsFunc(userID, income)
+	}
+	return nil, nil
+}
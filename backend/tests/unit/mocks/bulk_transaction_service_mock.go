@@ -0,0 +1,18 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+)
+
+// MockBulkTransactionService is a mock implementation of BulkTransactionService
+type MockBulkTransactionService struct {
+	BulkUpdateFunc func(userID uuid.UUID, req services.BulkTransactionRequest) (*services.BulkTransactionResult, error)
+}
+
+func (m *MockBulkTransactionService) BulkUpdate(userID uuid.UUID, req services.BulkTransactionRequest) (*services.BulkTransactionResult, error) {
+	if m.BulkUpdateFunc != nil {
+		return m.BulkUpdateFunc(userID, req)
+	}
+	return nil, nil
+}
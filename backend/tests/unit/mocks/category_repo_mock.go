@@ -0,0 +1,88 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// MockCategoryRepository is a mock implementation of CategoryRepository
+type MockCategoryRepository struct {
+	CreateFunc              func(category *models.Category) error
+	FindByIDFunc            func(id uuid.UUID) (*models.Category, error)
+	FindByUserIDFunc        func(userID uuid.UUID) ([]*models.Category, error)
+	FindByUserIDAndNameFunc func(userID uuid.UUID, name string) (*models.Category, error)
+	FindUsedFunc            func(userID uuid.UUID) ([]*repository.CategoryUsage, error)
+	CountReferencesFunc     func(userID uuid.UUID, name string) (int64, error)
+	RenameReferencesFunc    func(userID uuid.UUID, from, to string) error
+	UpdateFunc              func(category *models.Category) error
+	DeleteFunc              func(id uuid.UUID) error
+}
+
+func (m *MockCategoryRepository) Create(category *models.Category) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(category)
+	}
+	return nil
+}
+
+func (m *MockCategoryRepository) FindByID(id uuid.UUID) (*models.Category, error) {
+	if m.FindByIDFunc != nil {
+		return m.FindByIDFunc(id)
+	}
+	return nil, nil
+}
+
+func (m *MockCategoryRepository) FindByUserID(userID uuid.UUID) ([]*models.Category, error) {
+	if m.FindByUserIDFunc != nil {
+		return m.FindByUserIDFunc(userID)
+	}
+	return nil, nil
+}
+
+func (m *MockCategoryRepository) FindByUserIDAndName(userID uuid.UUID, name string) (*models.Category, error) {
+	if m.FindByUserIDAndNameFunc != nil {
+		return m.FindByUserIDAndNameFunc(userID, name)
+	}
+	return nil, nil
+}
+
+func (m *MockCategoryRepository) FindUsed(userID uuid.UUID) ([]*repository.CategoryUsage, error) {
+	if m.FindUsedFunc != nil {
+		return m.FindUsedFunc(userID)
+	}
+	return nil, nil
+}
+
+func (m *MockCategoryRepository) CountReferences(userID uuid.UUID, name string) (int64, error) {
+	if m.CountReferencesFunc != nil {
+		return m.CountReferencesFunc(userID, name)
+	}
+	return 0, nil
+}
+
+func (m *MockCategoryRepository) RenameReferences(userID uuid.UUID, from, to string) error {
+	if m.RenameReferencesFunc != nil {
+		return m.RenameReferencesFunc(userID, from, to)
+	}
+	return nil
+}
+
+func (m *MockCategoryRepository) Update(category *models.Category) error {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(category)
+	}
+	return nil
+}
+
+func (m *MockCategoryRepository) Delete(id uuid.UUID) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(id)
+	}
+	return nil
+}
+
+func (m *MockCategoryRepository) WithTx(tx *gorm.DB) repository.CategoryRepository {
+	return m
+}
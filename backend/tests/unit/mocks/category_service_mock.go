@@ -0,0 +1,67 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+)
+
+// MockCategoryService is a mock implementation of CategoryService
+type MockCategoryService struct {
+	CreateCategoryFunc    func(userID uuid.UUID, req services.CreateCategoryRequest) (*models.Category, error)
+	GetUserCategoriesFunc func(userID uuid.UUID) ([]*models.Category, error)
+	GetCategoryByIDFunc   func(id, userID uuid.UUID) (*models.Category, error)
+	UpdateCategoryFunc    func(id, userID uuid.UUID, req services.UpdateCategoryRequest) (*models.Category, error)
+	DeleteCategoryFunc    func(id, userID uuid.UUID) error
+	MergeCategoryFunc     func(id, userID, targetID uuid.UUID) (*models.Category, error)
+	SyncCategoriesFunc    func(userID uuid.UUID) ([]*models.Category, error)
+}
+
+func (m *MockCategoryService) CreateCategory(userID uuid.UUID, req services.CreateCategoryRequest) (*models.Category, error) {
+	if m.CreateCategoryFunc != nil {
+		return m.CreateCategoryFunc(userID, req)
+	}
+	return nil, nil
+}
+
+func (m *MockCategoryService) GetUserCategories(userID uuid.UUID) ([]*models.Category, error) {
+	if m.GetUserCategoriesFunc != nil {
+		return m.GetUserCategoriesFunc(userID)
+	}
+	return nil, nil
+}
+
+func (m *MockCategoryService) GetCategoryByID(id, userID uuid.UUID) (*models.Category, error) {
+	if m.GetCategoryByIDFunc != nil {
+		return m.GetCategoryByIDFunc(id, userID)
+	}
+	return nil, nil
+}
+
+func (m *MockCategoryService) UpdateCategory(id, userID uuid.UUID, req services.UpdateCategoryRequest) (*models.Category, error) {
+	if m.UpdateCategoryFunc != nil {
+		return m.UpdateCategoryFunc(id, userID, req)
+	}
+	return nil, nil
+}
+
+func (m *MockCategoryService) DeleteCategory(id, userID uuid.UUID) error {
+	if m.DeleteCategoryFunc != nil {
+		return m.DeleteCategoryFunc(id, userID)
+	}
+	return nil
+}
+
+func (m *MockCategoryService) MergeCategory(id, userID, targetID uuid.UUID) (*models.Category, error) {
+	if m.MergeCategoryFunc != nil {
+		return m.MergeCategoryFunc(id, userID, targetID)
+	}
+	return nil, nil
+}
+
+func (m *MockCategoryService) SyncCategories(userID uuid.UUID) ([]*models.Category, error) {
+	if m.SyncCategoriesFunc != nil {
+		return m.SyncCategoriesFunc(userID)
+	}
+	return nil, nil
+}
@@ -0,0 +1,27 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+)
+
+// MockDuplicateService is a mock implementation of DuplicateService
+type MockDuplicateService struct {
+	FindDuplicatesFunc  func(userID uuid.UUID, req services.FindDuplicatesRequest) ([]*services.DuplicateGroup, error)
+	MergeDuplicatesFunc func(id, userID uuid.UUID, req services.MergeDuplicatesRequest) (*models.Transaction, error)
+}
+
+func (m *MockDuplicateService) FindDuplicates(userID uuid.UUID, req services.FindDuplicatesRequest) ([]*services.DuplicateGroup, error) {
+	if m.FindDuplicatesFunc != nil {
+		return m.FindDuplicatesFunc(userID, req)
+	}
+	return nil, nil
+}
+
+func (m *MockDuplicateService) MergeDuplicates(id, userID uuid.UUID, req services.MergeDuplicatesRequest) (*models.Transaction, error) {
+	if m.MergeDuplicatesFunc != nil {
+		return m.MergeDuplicatesFunc(id, userID, req)
+	}
+	return nil, nil
+}
@@ -0,0 +1,33 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// MockEnvelopeAllocationRepository is a mock implementation of EnvelopeAllocationRepository
+type MockEnvelopeAllocationRepository struct {
+	CreateFunc func(allocations []*models.EnvelopeAllocation) error
+	TotalsFunc func(userID uuid.UUID) ([]*repository.EnvelopeAllocationTotal, error)
+}
+
+func (m *MockEnvelopeAllocationRepository) Create(allocations []*models.EnvelopeAllocation) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(allocations)
+	}
+	return nil
+}
+
+func (m *MockEnvelopeAllocationRepository) Totals(userID uuid.UUID) ([]*repository.EnvelopeAllocationTotal, error) {
+	if m.TotalsFunc != nil {
+		return m.TotalsFunc(userID)
+	}
+	return nil, nil
+}
+
+// WithTx returns the mock itself so calls made inside a transaction stay observable
+func (m *MockEnvelopeAllocationRepository) WithTx(tx *gorm.DB) repository.EnvelopeAllocationRepository {
+	return m
+}
@@ -0,0 +1,44 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+)
+
+// MockEnvelopeService is a mock implementation of EnvelopeService
+type MockEnvelopeService struct {
+	GetEnvelopesFunc func(userID uuid.UUID, month time.Time) (*services.EnvelopeMonth, error)
+	AssignFunc       func(userID uuid.UUID, req services.AssignEnvelopeRequest) (*services.EnvelopeMonth, error)
+	MoveFunc         func(userID uuid.UUID, req services.MoveEnvelopeRequest) (*services.EnvelopeMonth, error)
+	CoverFunc        func(userID uuid.UUID, req services.CoverEnvelopeRequest) (*services.EnvelopeMonth, error)
+}
+
+func (m *MockEnvelopeService) GetEnvelopes(userID uuid.UUID, month time.Time) (*services.EnvelopeMonth, error) {
+	if m.GetEnvelopesFunc != nil {
+		return m.GetEnvelopesFunc(userID, month)
+	}
+	return nil, nil
+}
+
+func (m *MockEnvelopeService) Assign(userID uuid.UUID, req services.AssignEnvelopeRequest) (*services.EnvelopeMonth, error) {
+	if m.AssignFunc != nil {
+		return m.AssignFunc(userID, req)
+	}
+	return nil, nil
+}
+
+func (m *MockEnvelopeService) Move(userID uuid.UUID, req services.MoveEnvelopeRequest) (*services.EnvelopeMonth, error) {
+	if m.MoveFunc != nil {
+		return m.MoveFunc(userID, req)
+	}
+	return nil, nil
+}
+
+func (m *MockEnvelopeService) Cover(userID uuid.UUID, req services.CoverEnvelopeRequest) (*services.EnvelopeMonth, error) {
+	if m.CoverFunc != nil {
+		return m.CoverFunc(userID, req)
+	}
+	return nil, nil
+}
@@ -0,0 +1,43 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/nyunja/fity-budget-backend/internal/models"
+)
+
+// MockExchangeRateRepository is a mock implementation of ExchangeRateRepository
+type MockExchangeRateRepository struct {
+	UpsertFunc     func(rates []*models.ExchangeRate) error
+	FindLatestFunc func(baseCurrency, quoteCurrency string, on time.Time) (*models.ExchangeRate, error)
+	ListFunc       func(baseCurrency, quoteCurrency string, limit, offset int) ([]*models.ExchangeRate, error)
+	CountFunc      func(baseCurrency, quoteCurrency string) (int64, error)
+}
+
+func (m *MockExchangeRateRepository) Upsert(rates []*models.ExchangeRate) error {
+	if m.UpsertFunc != nil {
+		return m.UpsertFunc(rates)
+	}
+	return nil
+}
+
+func (m *MockExchangeRateRepository) FindLatest(baseCurrency, quoteCurrency string, on time.Time) (*models.ExchangeRate, error) {
+	if m.FindLatestFunc != nil {
+		return m.FindLatestFunc(baseCurrency, quoteCurrency, on)
+	}
+	return nil, nil
+}
+
+func (m *MockExchangeRateRepository) List(baseCurrency, quoteCurrency string, limit, offset int) ([]*models.ExchangeRate, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(baseCurrency, quoteCurrency, limit, offset)
+	}
+	return nil, nil
+}
+
+func (m *MockExchangeRateRepository) Count(baseCurrency, quoteCurrency string) (int64, error) {
+	if m.CountFunc != nil {
+		return m.CountFunc(baseCurrency, quoteCurrency)
+	}
+	return 0, nil
+}
@@ -0,0 +1,38 @@
+package mocks
+
+import (
+	"io"
+	"time"
+
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+)
+
+// MockExchangeRateService is a mock implementation of ExchangeRateService
+type MockExchangeRateService struct {
+	ImportRatesFunc func(r io.Reader, format string) (*services.RateImportResult, error)
+	ListRatesFunc   func(baseCurrency, quoteCurrency string, limit, offset int) ([]*models.ExchangeRate, int64, error)
+	ConvertFunc     func(amount money.Amount, from, to string, on time.Time) (money.Amount, *services.AppliedRate, error)
+}
+
+func (m *MockExchangeRateService) ImportRates(r io.Reader, format string) (*services.RateImportResult, error) {
+	if m.ImportRatesFunc != nil {
+		return m.ImportRatesFunc(r, format)
+	}
+	return nil, nil
+}
+
+func (m *MockExchangeRateService) ListRates(baseCurrency, quoteCurrency string, limit, offset int) ([]*models.ExchangeRate, int64, error) {
+	if m.ListRatesFunc != nil {
+		return m.ListRatesFunc(baseCurrency, quoteCurrency, limit, offset)
+	}
+	return nil, 0, nil
+}
+
+func (m *MockExchangeRateService) Convert(amount money.Amount, from, to string, on time.Time) (money.Amount, *services.AppliedRate, error) {
+	if m.ConvertFunc != nil {
+		return m.ConvertFunc(amount, from, to, on)
+	}
+	return 0, nil, nil
+}
@@ -0,0 +1,116 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"github.com/nyunja/fity-budget-backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// MockGoalRepository is a mock implementation of GoalRepository
+type MockGoalRepository struct {
+	CreateFunc              func(goal *models.SavingGoal) error
+	FindByIDFunc            func(id uuid.UUID) (*models.SavingGoal, error)
+	FindByUserIDFunc        func(userID uuid.UUID) ([]*models.SavingGoal, error)
+	FindAllFunc             func() ([]*models.SavingGoal, error)
+	UpdateFunc              func(goal *models.SavingGoal) error
+	DeleteFunc              func(id uuid.UUID) error
+	UpdateProgressFunc      func(id uuid.UUID, amount money.Amount) error
+	FindDeletedByUserIDFunc func(userID uuid.UUID) ([]*models.SavingGoal, error)
+	FindDeletedByIDFunc     func(id uuid.UUID) (*models.SavingGoal, error)
+	FindDeletedBeforeFunc   func(cutoff time.Time, limit int) ([]*models.SavingGoal, error)
+	RestoreFunc             func(id uuid.UUID) error
+	PurgeFunc               func(id uuid.UUID) error
+}
+
+func (m *MockGoalRepository) Create(goal *models.SavingGoal) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(goal)
+	}
+	return nil
+}
+
+func (m *MockGoalRepository) FindByID(id uuid.UUID) (*models.SavingGoal, error) {
+	if m.FindByIDFunc != nil {
+		return m.FindByIDFunc(id)
+	}
+	return nil, nil
+}
+
+func (m *MockGoalRepository) FindByUserID(userID uuid.UUID) ([]*models.SavingGoal, error) {
+	if m.FindByUserIDFunc != nil {
+		return m.FindByUserIDFunc(userID)
+	}
+	return nil, nil
+}
+
+func (m *MockGoalRepository) FindAll() ([]*models.SavingGoal, error) {
+	if m.FindAllFunc != nil {
+		return m.FindAllFunc()
+	}
+	return nil, nil
+}
+
+func (m *MockGoalRepository) Update(goal *models.SavingGoal) error {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(goal)
+	}
+	return nil
+}
+
+func (m *MockGoalRepository) Delete(id uuid.UUID) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(id)
+	}
+	return nil
+}
+
+func (m *MockGoalRepository) UpdateProgress(id uuid.UUID, amount money.Amount) error {
+	if m.UpdateProgressFunc != nil {
+		return m.UpdateProgressFunc(id, amount)
+	}
+	return nil
+}
+
+func (m *MockGoalRepository) FindDeletedByUserID(userID uuid.UUID) ([]*models.SavingGoal, error) {
+	if m.FindDeletedByUserIDFunc != nil {
+		return m.FindDeletedByUserIDFunc(userID)
+	}
+	return nil, nil
+}
+
+func (m *MockGoalRepository) FindDeletedByID(id uuid.UUID) (*models.SavingGoal, error) {
+	if m.FindDeletedByIDFunc != nil {
+		return m.FindDeletedByIDFunc(id)
+	}
+	return nil, nil
+}
+
+func (m *MockGoalRepository) FindDeletedBefore(cutoff time.Time, limit int) ([]*models.SavingGoal, error) {
+	if m.FindDeletedBeforeFunc != nil {
+		return m.FindDeletedBeforeFunc(cutoff, limit)
+	}
+	return nil, nil
+}
+
+func (m *MockGoalRepository) Restore(id uuid.UUID) error {
+	if m.RestoreFunc != nil {
+		return m.RestoreFunc(id)
+	}
+	return nil
+}
+
+func (m *MockGoalRepository) Purge(id uuid.UUID) error {
+	if m.PurgeFunc != nil {
+		return m.PurgeFunc(id)
+	}
+	return nil
+}
+
+// WithTx returns the mock itself so calls made inside a transaction stay observable
+func (m *MockGoalRepository) WithTx(tx *gorm.DB) repository.GoalRepository {
+	return m
+}
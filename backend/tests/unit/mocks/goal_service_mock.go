@@ -3,6 +3,7 @@ package mocks
 import (
 	"github.com/google/uuid"
 	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
 	"github.com/nyunja/fity-budget-backend/internal/services"
 )
 
@@ -13,7 +14,7 @@ type MockGoalService struct {
 	GetGoalByIDFunc    func(id, userID uuid.UUID) (*models.SavingGoal, error)
 	UpdateGoalFunc     func(id, userID uuid.UUID, req services.UpdateGoalRequest) (*models.SavingGoal, error)
 	DeleteGoalFunc     func(id, userID uuid.UUID) error
-	AddProgressFunc    func(id, userID uuid.UUID, amount float64) (*models.SavingGoal, error)
+	AddProgressFunc    func(id, userID uuid.UUID, amount money.Amount) (*models.SavingGoal, error)
 	GetGoalProgressFunc func(userID uuid.UUID) (*services.GoalProgressSummary, error)
 }
 
@@ -52,7 +53,7 @@ func (m *MockGoalService) DeleteGoal(id, userID uuid.UUID) error {
 	return nil
 }
 
-func (m *MockGoalService) AddProgress(id, userID uuid.UUID, amount float64) (*models.SavingGoal, error) {
+func (m *MockGoalService) AddProgress(id, userID uuid.UUID, amount money.Amount) (*models.SavingGoal, error) {
 	if m.AddProgressFunc != nil {
 		return m.AddProgressFunc(id, userID, amount)
 	}
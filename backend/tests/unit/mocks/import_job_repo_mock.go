@@ -0,0 +1,64 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// MockImportJobRepository is a mock implementation of ImportJobRepository
+type MockImportJobRepository struct {
+	CreateFunc        func(job *models.ImportJob) error
+	FindByIDFunc      func(id uuid.UUID) (*models.ImportJob, error)
+	FindByUserIDFunc  func(userID uuid.UUID, limit, offset int) ([]*models.ImportJob, error)
+	CountByUserIDFunc func(userID uuid.UUID) (int64, error)
+	UpdateFunc        func(job *models.ImportJob) error
+	UpdateRowsFunc    func(rows []*models.ImportRow) error
+}
+
+func (m *MockImportJobRepository) Create(job *models.ImportJob) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(job)
+	}
+	return nil
+}
+
+func (m *MockImportJobRepository) FindByID(id uuid.UUID) (*models.ImportJob, error) {
+	if m.FindByIDFunc != nil {
+		return m.FindByIDFunc(id)
+	}
+	return nil, nil
+}
+
+func (m *MockImportJobRepository) FindByUserID(userID uuid.UUID, limit, offset int) ([]*models.ImportJob, error) {
+	if m.FindByUserIDFunc != nil {
+		return m.FindByUserIDFunc(userID, limit, offset)
+	}
+	return nil, nil
+}
+
+func (m *MockImportJobRepository) CountByUserID(userID uuid.UUID) (int64, error) {
+	if m.CountByUserIDFunc != nil {
+		return m.CountByUserIDFunc(userID)
+	}
+	return 0, nil
+}
+
+func (m *MockImportJobRepository) Update(job *models.ImportJob) error {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(job)
+	}
+	return nil
+}
+
+func (m *MockImportJobRepository) UpdateRows(rows []*models.ImportRow) error {
+	if m.UpdateRowsFunc != nil {
+		return m.UpdateRowsFunc(rows)
+	}
+	return nil
+}
+
+func (m *MockImportJobRepository) WithTx(tx *gorm.DB) repository.ImportJobRepository {
+	return m
+}
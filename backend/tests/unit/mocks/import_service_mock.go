@@ -0,0 +1,59 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+)
+
+// MockImportService is a mock implementation of ImportService
+type MockImportService struct {
+	PreviewImportFunc       func(userID uuid.UUID, req services.PreviewImportRequest) (*services.ImportPreview, error)
+	CommitImportFunc        func(id, userID uuid.UUID, req services.CommitImportRequest) (*models.ImportJob, error)
+	UndoImportFunc          func(id, userID uuid.UUID) (*models.ImportJob, error)
+	GetImportFunc           func(id, userID uuid.UUID) (*models.ImportJob, error)
+	ListImportsFunc         func(userID uuid.UUID, limit, offset int) ([]*models.ImportJob, int64, error)
+	ImportMpesaMessagesFunc func(userID uuid.UUID, req services.ImportMpesaMessagesRequest) (*models.ImportJob, error)
+}
+
+func (m *MockImportService) PreviewImport(userID uuid.UUID, req services.PreviewImportRequest) (*services.ImportPreview, error) {
+	if m.PreviewImportFunc != nil {
+		return m.PreviewImportFunc(userID, req)
+	}
+	return nil, nil
+}
+
+func (m *MockImportService) CommitImport(id, userID uuid.UUID, req services.CommitImportRequest) (*models.ImportJob, error) {
+	if m.CommitImportFunc != nil {
+		return m.CommitImportFunc(id, userID, req)
+	}
+	return nil, nil
+}
+
+func (m *MockImportService) UndoImport(id, userID uuid.UUID) (*models.ImportJob, error) {
+	if m.UndoImportFunc != nil {
+		return m.UndoImportFunc(id, userID)
+	}
+	return nil, nil
+}
+
+func (m *MockImportService) GetImport(id, userID uuid.UUID) (*models.ImportJob, error) {
+	if m.GetImportFunc != nil {
+		return m.GetImportFunc(id, userID)
+	}
+	return nil, nil
+}
+
+func (m *MockImportService) ListImports(userID uuid.UUID, limit, offset int) ([]*models.ImportJob, int64, error) {
+	if m.ListImportsFunc != nil {
+		return m.ListImportsFunc(userID, limit, offset)
+	}
+	return nil, 0, nil
+}
+
+func (m *MockImportService) ImportMpesaMessages(userID uuid.UUID, req services.ImportMpesaMessagesRequest) (*models.ImportJob, error) {
+	if m.ImportMpesaMessagesFunc != nil {
+		return m.ImportMpesaMessagesFunc(userID, req)
+	}
+	return nil, nil
+}
@@ -0,0 +1,57 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// MockReceiptRepository is a mock implementation of ReceiptRepository
+type MockReceiptRepository struct {
+	CreateFunc              func(receipt *models.Receipt) error
+	FindByIDFunc            func(id uuid.UUID) (*models.Receipt, error)
+	FindByTransactionIDFunc func(transactionID uuid.UUID) ([]*models.Receipt, error)
+	MoveToTransactionFunc   func(fromTransactionID, toTransactionID uuid.UUID) error
+	DeleteFunc              func(id uuid.UUID) error
+}
+
+func (m *MockReceiptRepository) Create(receipt *models.Receipt) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(receipt)
+	}
+	return nil
+}
+
+func (m *MockReceiptRepository) FindByID(id uuid.UUID) (*models.Receipt, error) {
+	if m.FindByIDFunc != nil {
+		return m.FindByIDFunc(id)
+	}
+	return nil, nil
+}
+
+func (m *MockReceiptRepository) FindByTransactionID(transactionID uuid.UUID) ([]*models.Receipt, error) {
+	if m.FindByTransactionIDFunc != nil {
+		return m.FindByTransactionIDFunc(transactionID)
+	}
+	return nil, nil
+}
+
+func (m *MockReceiptRepository) MoveToTransaction(fromTransactionID, toTransactionID uuid.UUID) error {
+	if m.MoveToTransactionFunc != nil {
+		return m.MoveToTransactionFunc(fromTransactionID, toTransactionID)
+	}
+	return nil
+}
+
+func (m *MockReceiptRepository) Delete(id uuid.UUID) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(id)
+	}
+	return nil
+}
+
+// WithTx returns the mock itself so calls made inside a transaction stay observable
+func (m *MockReceiptRepository) WithTx(tx *gorm.DB) repository.ReceiptRepository {
+	return m
+}
@@ -0,0 +1,59 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+)
+
+// MockReceiptService is a mock implementation of ReceiptService
+type MockReceiptService struct {
+	UploadReceiptFunc             func(userID, transactionID uuid.UUID, req services.UploadReceiptRequest) (*models.Receipt, error)
+	GetTransactionReceiptsFunc    func(transactionID, userID uuid.UUID) ([]*models.Receipt, error)
+	GetReceiptByIDFunc            func(id, userID uuid.UUID) (*models.Receipt, error)
+	OpenReceiptFunc               func(id, userID uuid.UUID, thumbnail bool) (*services.ReceiptFile, error)
+	DeleteReceiptFunc             func(id, userID uuid.UUID) error
+	DeleteTransactionReceiptsFunc func(transactionID uuid.UUID) error
+}
+
+func (m *MockReceiptService) UploadReceipt(userID, transactionID uuid.UUID, req services.UploadReceiptRequest) (*models.Receipt, error) {
+	if m.UploadReceiptFunc != nil {
+		return m.UploadReceiptFunc(userID, transactionID, req)
+	}
+	return nil, nil
+}
+
+func (m *MockReceiptService) GetTransactionReceipts(transactionID, userID uuid.UUID) ([]*models.Receipt, error) {
+	if m.GetTransactionReceiptsFunc != nil {
+		return m.GetTransactionReceiptsFunc(transactionID, userID)
+	}
+	return nil, nil
+}
+
+func (m *MockReceiptService) GetReceiptByID(id, userID uuid.UUID) (*models.Receipt, error) {
+	if m.GetReceiptByIDFunc != nil {
+		return m.GetReceiptByIDFunc(id, userID)
+	}
+	return nil, nil
+}
+
+func (m *MockReceiptService) OpenReceipt(id, userID uuid.UUID, thumbnail bool) (*services.ReceiptFile, error) {
+	if m.OpenReceiptFunc != nil {
+		return m.OpenReceiptFunc(id, userID, thumbnail)
+	}
+	return nil, nil
+}
+
+func (m *MockReceiptService) DeleteReceipt(id, userID uuid.UUID) error {
+	if m.DeleteReceiptFunc != nil {
+		return m.DeleteReceiptFunc(id, userID)
+	}
+	return nil
+}
+
+func (m *MockReceiptService) DeleteTransactionReceipts(transactionID uuid.UUID) error {
+	if m.DeleteTransactionReceiptsFunc != nil {
+		return m.DeleteTransactionReceiptsFunc(transactionID)
+	}
+	return nil
+}
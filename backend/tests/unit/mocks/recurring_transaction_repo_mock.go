@@ -0,0 +1,74 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// MockRecurringTransactionRepository is a mock implementation of RecurringTransactionRepository
+type MockRecurringTransactionRepository struct {
+	CreateFunc            func(recurring *models.RecurringTransaction) error
+	FindByIDFunc          func(id uuid.UUID) (*models.RecurringTransaction, error)
+	FindByIDForUpdateFunc func(id uuid.UUID) (*models.RecurringTransaction, error)
+	FindByUserIDFunc      func(userID uuid.UUID) ([]*models.RecurringTransaction, error)
+	FindDueFunc           func(now time.Time, limit int) ([]*models.RecurringTransaction, error)
+	UpdateFunc            func(recurring *models.RecurringTransaction) error
+	DeleteFunc            func(id uuid.UUID) error
+}
+
+func (m *MockRecurringTransactionRepository) Create(recurring *models.RecurringTransaction) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(recurring)
+	}
+	return nil
+}
+
+func (m *MockRecurringTransactionRepository) FindByID(id uuid.UUID) (*models.RecurringTransaction, error) {
+	if m.FindByIDFunc != nil {
+		return m.FindByIDFunc(id)
+	}
+	return nil, nil
+}
+
+func (m *MockRecurringTransactionRepository) FindByIDForUpdate(id uuid.UUID) (*models.RecurringTransaction, error) {
+	if m.FindByIDForUpdateFunc != nil {
+		return m.FindByIDForUpdateFunc(id)
+	}
+	return m.FindByID(id)
+}
+
+func (m *MockRecurringTransactionRepository) FindByUserID(userID uuid.UUID) ([]*models.RecurringTransaction, error) {
+	if m.FindByUserIDFunc != nil {
+		return m.FindByUserIDFunc(userID)
+	}
+	return nil, nil
+}
+
+func (m *MockRecurringTransactionRepository) FindDue(now time.Time, limit int) ([]*models.RecurringTransaction, error) {
+	if m.FindDueFunc != nil {
+		return m.FindDueFunc(now, limit)
+	}
+	return nil, nil
+}
+
+func (m *MockRecurringTransactionRepository) Update(recurring *models.RecurringTransaction) error {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(recurring)
+	}
+	return nil
+}
+
+func (m *MockRecurringTransactionRepository) Delete(id uuid.UUID) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(id)
+	}
+	return nil
+}
+
+func (m *MockRecurringTransactionRepository) WithTx(tx *gorm.DB) repository.RecurringTransactionRepository {
+	return m
+}
@@ -0,0 +1,77 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+)
+
+// MockRecurringTransactionService is a mock implementation of RecurringTransactionService
+type MockRecurringTransactionService struct {
+	CreateRecurringFunc    func(userID uuid.UUID, req services.CreateRecurringRequest) (*models.RecurringTransaction, error)
+	GetUserRecurringFunc   func(userID uuid.UUID) ([]*models.RecurringTransaction, error)
+	GetRecurringByIDFunc   func(id, userID uuid.UUID) (*models.RecurringTransaction, error)
+	UpdateRecurringFunc    func(id, userID uuid.UUID, req services.UpdateRecurringRequest) (*models.RecurringTransaction, error)
+	DeleteRecurringFunc    func(id, userID uuid.UUID) error
+	PreviewOccurrencesFunc func(id, userID uuid.UUID, limit int) ([]time.Time, error)
+	GetUpcomingFunc        func(userID uuid.UUID, until time.Time) ([]*services.UpcomingOccurrence, error)
+	PostDueOccurrencesFunc func(now time.Time) (int, error)
+}
+
+func (m *MockRecurringTransactionService) CreateRecurring(userID uuid.UUID, req services.CreateRecurringRequest) (*models.RecurringTransaction, error) {
+	if m.CreateRecurringFunc != nil {
+		return m.CreateRecurringFunc(userID, req)
+	}
+	return nil, nil
+}
+
+func (m *MockRecurringTransactionService) GetUserRecurring(userID uuid.UUID) ([]*models.RecurringTransaction, error) {
+	if m.GetUserRecurringFunc != nil {
+		return m.GetUserRecurringFunc(userID)
+	}
+	return nil, nil
+}
+
+func (m *MockRecurringTransactionService) GetRecurringByID(id, userID uuid.UUID) (*models.RecurringTransaction, error) {
+	if m.GetRecurringByIDFunc != nil {
+		return m.GetRecurringByIDFunc(id, userID)
+	}
+	return nil, nil
+}
+
+func (m *MockRecurringTransactionService) UpdateRecurring(id, userID uuid.UUID, req services.UpdateRecurringRequest) (*models.RecurringTransaction, error) {
+	if m.UpdateRecurringFunc != nil {
+		return m.UpdateRecurringFunc(id, userID, req)
+	}
+	return nil, nil
+}
+
+func (m *MockRecurringTransactionService) DeleteRecurring(id, userID uuid.UUID) error {
+	if m.DeleteRecurringFunc != nil {
+		return m.DeleteRecurringFunc(id, userID)
+	}
+	return nil
+}
+
+func (m *MockRecurringTransactionService) PreviewOccurrences(id, userID uuid.UUID, limit int) ([]time.Time, error) {
+	if m.PreviewOccurrencesFunc != nil {
+		return m.PreviewOccurrencesFunc(id, userID, limit)
+	}
+	return nil, nil
+}
+
+func (m *MockRecurringTransactionService) GetUpcoming(userID uuid.UUID, until time.Time) ([]*services.UpcomingOccurrence, error) {
+	if m.GetUpcomingFunc != nil {
+		return m.GetUpcomingFunc(userID, until)
+	}
+	return nil, nil
+}
+
+func (m *MockRecurringTransactionService) PostDueOccurrences(now time.Time) (int, error) {
+	if m.PostDueOccurrencesFunc != nil {
+		return m.PostDueOccurrencesFunc(now)
+	}
+	return 0, nil
+}
@@ -0,0 +1,65 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// MockRuleRepository is a mock implementation of RuleRepository
+type MockRuleRepository struct {
+	CreateFunc             func(rule *models.Rule) error
+	FindByIDFunc           func(id uuid.UUID) (*models.Rule, error)
+	FindByUserIDFunc       func(userID uuid.UUID) ([]*models.Rule, error)
+	FindActiveByUserIDFunc func(userID uuid.UUID) ([]*models.Rule, error)
+	UpdateFunc             func(rule *models.Rule) error
+	DeleteFunc             func(id uuid.UUID) error
+}
+
+func (m *MockRuleRepository) Create(rule *models.Rule) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(rule)
+	}
+	return nil
+}
+
+func (m *MockRuleRepository) FindByID(id uuid.UUID) (*models.Rule, error) {
+	if m.FindByIDFunc != nil {
+		return m.FindByIDFunc(id)
+	}
+	return nil, nil
+}
+
+func (m *MockRuleRepository) FindByUserID(userID uuid.UUID) ([]*models.Rule, error) {
+	if m.FindByUserIDFunc != nil {
+		return m.FindByUserIDFunc(userID)
+	}
+	return nil, nil
+}
+
+func (m *MockRuleRepository) FindActiveByUserID(userID uuid.UUID) ([]*models.Rule, error) {
+	if m.FindActiveByUserIDFunc != nil {
+		return m.FindActiveByUserIDFunc(userID)
+	}
+	return nil, nil
+}
+
+func (m *MockRuleRepository) Update(rule *models.Rule) error {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(rule)
+	}
+	return nil
+}
+
+func (m *MockRuleRepository) Delete(id uuid.UUID) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(id)
+	}
+	return nil
+}
+
+// WithTx returns the mock itself so calls made inside a transaction stay observable
+func (m *MockRuleRepository) WithTx(tx *gorm.DB) repository.RuleRepository {
+	return m
+}
@@ -0,0 +1,59 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+)
+
+// MockRuleService is a mock implementation of RuleService
+type MockRuleService struct {
+	CreateRuleFunc   func(userID uuid.UUID, req services.CreateRuleRequest) (*models.Rule, error)
+	GetUserRulesFunc func(userID uuid.UUID) ([]*models.Rule, error)
+	GetRuleByIDFunc  func(id, userID uuid.UUID) (*models.Rule, error)
+	UpdateRuleFunc   func(id, userID uuid.UUID, req services.UpdateRuleRequest) (*models.Rule, error)
+	DeleteRuleFunc   func(id, userID uuid.UUID) error
+	ApplyRulesFunc   func(userID uuid.UUID, req services.ApplyRulesRequest) (*services.ApplyRulesResult, error)
+}
+
+func (m *MockRuleService) CreateRule(userID uuid.UUID, req services.CreateRuleRequest) (*models.Rule, error) {
+	if m.CreateRuleFunc != nil {
+		return m.CreateRuleFunc(userID, req)
+	}
+	return nil, nil
+}
+
+func (m *MockRuleService) GetUserRules(userID uuid.UUID) ([]*models.Rule, error) {
+	if m.GetUserRulesFunc != nil {
+		return m.GetUserRulesFunc(userID)
+	}
+	return nil, nil
+}
+
+func (m *MockRuleService) GetRuleByID(id, userID uuid.UUID) (*models.Rule, error) {
+	if m.GetRuleByIDFunc != nil {
+		return m.GetRuleByIDFunc(id, userID)
+	}
+	return nil, nil
+}
+
+func (m *MockRuleService) UpdateRule(id, userID uuid.UUID, req services.UpdateRuleRequest) (*models.Rule, error) {
+	if m.UpdateRuleFunc != nil {
+		return m.UpdateRuleFunc(id, userID, req)
+	}
+	return nil, nil
+}
+
+func (m *MockRuleService) DeleteRule(id, userID uuid.UUID) error {
+	if m.DeleteRuleFunc != nil {
+		return m.DeleteRuleFunc(id, userID)
+	}
+	return nil
+}
+
+func (m *MockRuleService) ApplyRules(userID uuid.UUID, req services.ApplyRulesRequest) (*services.ApplyRulesResult, error) {
+	if m.ApplyRulesFunc != nil {
+		return m.ApplyRulesFunc(userID, req)
+	}
+	return nil, nil
+}
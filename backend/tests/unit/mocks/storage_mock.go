@@ -0,0 +1,33 @@
+package mocks
+
+import (
+	"io"
+)
+
+// MockStorage is a mock implementation of storage.Storage
+type MockStorage struct {
+	PutFunc    func(key string, r io.Reader) error
+	OpenFunc   func(key string) (io.ReadCloser, error)
+	DeleteFunc func(key string) error
+}
+
+func (m *MockStorage) Put(key string, r io.Reader) error {
+	if m.PutFunc != nil {
+		return m.PutFunc(key, r)
+	}
+	return nil
+}
+
+func (m *MockStorage) Open(key string) (io.ReadCloser, error) {
+	if m.OpenFunc != nil {
+		return m.OpenFunc(key)
+	}
+	return nil, nil
+}
+
+func (m *MockStorage) Delete(key string) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(key)
+	}
+	return nil
+}
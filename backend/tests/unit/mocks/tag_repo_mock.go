@@ -0,0 +1,73 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// MockTagRepository is a mock implementation of TagRepository
+type MockTagRepository struct {
+	CreateFunc              func(tag *models.Tag) error
+	FindByIDFunc            func(id uuid.UUID) (*models.Tag, error)
+	FindByUserIDFunc        func(userID uuid.UUID) ([]*models.Tag, error)
+	FindByUserIDAndNameFunc func(userID uuid.UUID, name string) (*models.Tag, error)
+	FindOrCreateFunc        func(userID uuid.UUID, names []string) ([]models.Tag, error)
+	UpdateFunc              func(tag *models.Tag) error
+	DeleteFunc              func(id uuid.UUID) error
+}
+
+func (m *MockTagRepository) Create(tag *models.Tag) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(tag)
+	}
+	return nil
+}
+
+func (m *MockTagRepository) FindByID(id uuid.UUID) (*models.Tag, error) {
+	if m.FindByIDFunc != nil {
+		return m.FindByIDFunc(id)
+	}
+	return nil, nil
+}
+
+func (m *MockTagRepository) FindByUserID(userID uuid.UUID) ([]*models.Tag, error) {
+	if m.FindByUserIDFunc != nil {
+		return m.FindByUserIDFunc(userID)
+	}
+	return nil, nil
+}
+
+func (m *MockTagRepository) FindByUserIDAndName(userID uuid.UUID, name string) (*models.Tag, error) {
+	if m.FindByUserIDAndNameFunc != nil {
+		return m.FindByUserIDAndNameFunc(userID, name)
+	}
+	return nil, nil
+}
+
+func (m *MockTagRepository) FindOrCreate(userID uuid.UUID, names []string) ([]models.Tag, error) {
+	if m.FindOrCreateFunc != nil {
+		return m.FindOrCreateFunc(userID, names)
+	}
+	return nil, nil
+}
+
+func (m *MockTagRepository) Update(tag *models.Tag) error {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(tag)
+	}
+	return nil
+}
+
+func (m *MockTagRepository) Delete(id uuid.UUID) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(id)
+	}
+	return nil
+}
+
+// WithTx returns the mock itself so calls made inside a transaction stay observable
+func (m *MockTagRepository) WithTx(tx *gorm.DB) repository.TagRepository {
+	return m
+}
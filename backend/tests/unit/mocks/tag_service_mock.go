@@ -0,0 +1,51 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+)
+
+// MockTagService is a mock implementation of TagService
+type MockTagService struct {
+	CreateTagFunc   func(userID uuid.UUID, req services.CreateTagRequest) (*models.Tag, error)
+	GetUserTagsFunc func(userID uuid.UUID) ([]*models.Tag, error)
+	GetTagByIDFunc  func(id, userID uuid.UUID) (*models.Tag, error)
+	UpdateTagFunc   func(id, userID uuid.UUID, req services.UpdateTagRequest) (*models.Tag, error)
+	DeleteTagFunc   func(id, userID uuid.UUID) error
+}
+
+func (m *MockTagService) CreateTag(userID uuid.UUID, req services.CreateTagRequest) (*models.Tag, error) {
+	if m.CreateTagFunc != nil {
+		return m.CreateTagFunc(userID, req)
+	}
+	return nil, nil
+}
+
+func (m *MockTagService) GetUserTags(userID uuid.UUID) ([]*models.Tag, error) {
+	if m.GetUserTagsFunc != nil {
+		return m.GetUserTagsFunc(userID)
+	}
+	return nil, nil
+}
+
+func (m *MockTagService) GetTagByID(id, userID uuid.UUID) (*models.Tag, error) {
+	if m.GetTagByIDFunc != nil {
+		return m.GetTagByIDFunc(id, userID)
+	}
+	return nil, nil
+}
+
+func (m *MockTagService) UpdateTag(id, userID uuid.UUID, req services.UpdateTagRequest) (*models.Tag, error) {
+	if m.UpdateTagFunc != nil {
+		return m.UpdateTagFunc(id, userID, req)
+	}
+	return nil, nil
+}
+
+func (m *MockTagService) DeleteTag(id, userID uuid.UUID) error {
+	if m.DeleteTagFunc != nil {
+		return m.DeleteTagFunc(id, userID)
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// MockTransactionRepository is a mock implementation of TransactionRepository
+type MockTransactionRepository struct {
+	CreateFunc              func(transaction *models.Transaction) error
+	CreateBatchFunc         func(transactions []*models.Transaction) error
+	FindByIDFunc            func(id uuid.UUID) (*models.Transaction, error)
+	FindByIDForUpdateFunc   func(id uuid.UUID) (*models.Transaction, error)
+	FindByUserIDFunc        func(userID uuid.UUID, limit, offset int) ([]*models.Transaction, error)
+	CountByUserIDFunc       func(userID uuid.UUID) (int64, error)
+	FindByFilterFunc        func(filter repository.TransactionFilter, sort repository.TransactionSort, limit, offset int) ([]*models.Transaction, error)
+	CountByFilterFunc       func(filter repository.TransactionFilter) (int64, error)
+	FindByCursorFunc        func(filter repository.TransactionFilter, cursor repository.TransactionCursor, limit int) ([]*models.Transaction, error)
+	AggregateFunc           func(filter repository.TransactionFilter, groupBy ...repository.TransactionGroup) ([]*repository.TransactionAggregate, error)
+	FindAllFunc             func() ([]*models.Transaction, error)
+	UpdateFunc              func(transaction *models.Transaction) error
+	UpdateColumnsFunc       func(id uuid.UUID, columns map[string]interface{}) error
+	DeleteFunc              func(id uuid.UUID) error
+	DeleteByImportJobIDFunc func(jobID uuid.UUID) error
+	ReplaceSplitsFunc       func(transactionID uuid.UUID, splits []models.TransactionSplit) error
+	AddTagsFunc             func(transactionID uuid.UUID, tags []models.Tag) error
+	FindDeletedByUserIDFunc func(userID uuid.UUID) ([]*models.Transaction, error)
+	FindDeletedByIDFunc     func(id uuid.UUID) (*models.Transaction, error)
+	FindDeletedBeforeFunc   func(cutoff time.Time, limit int) ([]*models.Transaction, error)
+	RestoreFunc             func(id uuid.UUID) (bool, error)
+	PurgeFunc               func(id uuid.UUID) error
+	ReplaceTagsFunc         func(transactionID uuid.UUID, tags []models.Tag) error
+	RemoveTagsFunc          func(transactionID uuid.UUID, tags []models.Tag) error
+}
+
+func (m *MockTransactionRepository) Create(transaction *models.Transaction) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(transaction)
+	}
+	return nil
+}
+
+func (m *MockTransactionRepository) CreateBatch(transactions []*models.Transaction) error {
+	if m.CreateBatchFunc != nil {
+		return m.CreateBatchFunc(transactions)
+	}
+	return nil
+}
+
+func (m *MockTransactionRepository) FindByID(id uuid.UUID) (*models.Transaction, error) {
+	if m.FindByIDFunc != nil {
+		return m.FindByIDFunc(id)
+	}
+	return nil, nil
+}
+
+// FindByIDForUpdate falls back to FindByIDFunc when no locking behaviour is needed
+func (m *MockTransactionRepository) FindByIDForUpdate(id uuid.UUID) (*models.Transaction, error) {
+	if m.FindByIDForUpdateFunc != nil {
+		return m.FindByIDForUpdateFunc(id)
+	}
+	return m.FindByID(id)
+}
+
+func (m *MockTransactionRepository) FindByUserID(userID uuid.UUID, limit, offset int) ([]*models.Transaction, error) {
+	if m.FindByUserIDFunc != nil {
+		return m.FindByUserIDFunc(userID, limit, offset)
+	}
+	return nil, nil
+}
+
+func (m *MockTransactionRepository) CountByUserID(userID uuid.UUID) (int64, error) {
+	if m.CountByUserIDFunc != nil {
+		return m.CountByUserIDFunc(userID)
+	}
+	return 0, nil
+}
+
+func (m *MockTransactionRepository) FindByFilter(filter repository.TransactionFilter, sort repository.TransactionSort, limit, offset int) ([]*models.Transaction, error) {
+	if m.FindByFilterFunc != nil {
+		return m.FindByFilterFunc(filter, sort, limit, offset)
+	}
+	return nil, nil
+}
+
+func (m *MockTransactionRepository) FindByCursor(filter repository.TransactionFilter, cursor repository.TransactionCursor, limit int) ([]*models.Transaction, error) {
+	if m.FindByCursorFunc != nil {
+		return m.FindByCursorFunc(filter, cursor, limit)
+	}
+	return nil, nil
+}
+
+func (m *MockTransactionRepository) CountByFilter(filter repository.TransactionFilter) (int64, error) {
+	if m.CountByFilterFunc != nil {
+		return m.CountByFilterFunc(filter)
+	}
+	return 0, nil
+}
+
+func (m *MockTransactionRepository) Aggregate(filter repository.TransactionFilter, groupBy ...repository.TransactionGroup) ([]*repository.TransactionAggregate, error) {
+	if m.AggregateFunc != nil {
+		return m.AggregateFunc(filter, groupBy...)
+	}
+	return nil, nil
+}
+
+func (m *MockTransactionRepository) FindAll() ([]*models.Transaction, error) {
+	if m.FindAllFunc != nil {
+		return m.FindAllFunc()
+	}
+	return nil, nil
+}
+
+func (m *MockTransactionRepository) Update(transaction *models.Transaction) error {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(transaction)
+	}
+	return nil
+}
+
+func (m *MockTransactionRepository) UpdateColumns(id uuid.UUID, columns map[string]interface{}) error {
+	if m.UpdateColumnsFunc != nil {
+		return m.UpdateColumnsFunc(id, columns)
+	}
+	return nil
+}
+
+func (m *MockTransactionRepository) Delete(id uuid.UUID) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(id)
+	}
+	return nil
+}
+
+func (m *MockTransactionRepository) DeleteByImportJobID(jobID uuid.UUID) error {
+	if m.DeleteByImportJobIDFunc != nil {
+		return m.DeleteByImportJobIDFunc(jobID)
+	}
+	return nil
+}
+
+func (m *MockTransactionRepository) ReplaceSplits(transactionID uuid.UUID, splits []models.TransactionSplit) error {
+	if m.ReplaceSplitsFunc != nil {
+		return m.ReplaceSplitsFunc(transactionID, splits)
+	}
+	return nil
+}
+
+func (m *MockTransactionRepository) AddTags(transactionID uuid.UUID, tags []models.Tag) error {
+	if m.AddTagsFunc != nil {
+		return m.AddTagsFunc(transactionID, tags)
+	}
+	return nil
+}
+
+func (m *MockTransactionRepository) ReplaceTags(transactionID uuid.UUID, tags []models.Tag) error {
+	if m.ReplaceTagsFunc != nil {
+		return m.ReplaceTagsFunc(transactionID, tags)
+	}
+	return nil
+}
+
+func (m *MockTransactionRepository) RemoveTags(transactionID uuid.UUID, tags []models.Tag) error {
+	if m.RemoveTagsFunc != nil {
+		return m.RemoveTagsFunc(transactionID, tags)
+	}
+	return nil
+}
+
+func (m *MockTransactionRepository) FindDeletedByUserID(userID uuid.UUID) ([]*models.Transaction, error) {
+	if m.FindDeletedByUserIDFunc != nil {
+		return m.FindDeletedByUserIDFunc(userID)
+	}
+	return nil, nil
+}
+
+func (m *MockTransactionRepository) FindDeletedByID(id uuid.UUID) (*models.Transaction, error) {
+	if m.FindDeletedByIDFunc != nil {
+		return m.FindDeletedByIDFunc(id)
+	}
+	return nil, nil
+}
+
+func (m *MockTransactionRepository) FindDeletedBefore(cutoff time.Time, limit int) ([]*models.Transaction, error) {
+	if m.FindDeletedBeforeFunc != nil {
+		return m.FindDeletedBeforeFunc(cutoff, limit)
+	}
+	return nil, nil
+}
+
+func (m *MockTransactionRepository) Restore(id uuid.UUID) (bool, error) {
+	if m.RestoreFunc != nil {
+		return m.RestoreFunc(id)
+	}
+	return false, nil
+}
+
+func (m *MockTransactionRepository) Purge(id uuid.UUID) error {
+	if m.PurgeFunc != nil {
+		return m.PurgeFunc(id)
+	}
+	return nil
+}
+
+// WithTx returns the mock itself so calls made inside a transaction stay observable
+func (m *MockTransactionRepository) WithTx(tx *gorm.DB) repository.TransactionRepository {
+	return m
+}
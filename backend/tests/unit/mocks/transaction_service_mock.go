@@ -13,6 +13,8 @@ type MockTransactionService struct {
 	CreateTransactionFunc            func(userID uuid.UUID, req services.CreateTransactionRequest) (*models.Transaction, error)
 	GetUserTransactionsFunc          func(userID uuid.UUID, limit, offset int) ([]*models.Transaction, error)
 	GetUserTransactionsWithCountFunc func(userID uuid.UUID, limit, offset int) ([]*models.Transaction, int64, error)
+	ListTransactionsFunc             func(userID uuid.UUID, query services.TransactionListQuery) ([]*models.Transaction, int64, error)
+	ListTransactionsByCursorFunc     func(userID uuid.UUID, query services.TransactionListQuery) (*services.TransactionPage, error)
 	GetTransactionByIDFunc           func(id, userID uuid.UUID) (*models.Transaction, error)
 	UpdateTransactionFunc            func(id, userID uuid.UUID, req services.UpdateTransactionRequest) (*models.Transaction, error)
 	DeleteTransactionFunc            func(id, userID uuid.UUID) error
@@ -40,6 +42,20 @@ func (m *MockTransactionService) GetUserTransactionsWithCount(userID uuid.UUID,
 	return nil, 0, nil
 }
 
+func (m *MockTransactionService) ListTransactions(userID uuid.UUID, query services.TransactionListQuery) ([]*models.Transaction, int64, error) {
+	if m.ListTransactionsFunc != nil {
+		return m.ListTransactionsFunc(userID, query)
+	}
+	return nil, 0, nil
+}
+
+func (m *MockTransactionService) ListTransactionsByCursor(userID uuid.UUID, query services.TransactionListQuery) (*services.TransactionPage, error) {
+	if m.ListTransactionsByCursorFunc != nil {
+		return m.ListTransactionsByCursorFunc(userID, query)
+	}
+	return nil, nil
+}
+
 func (m *MockTransactionService) GetTransactionByID(id, userID uuid.UUID) (*models.Transaction, error) {
 	if m.GetTransactionByIDFunc != nil {
 		return m.GetTransactionByIDFunc(id, userID)
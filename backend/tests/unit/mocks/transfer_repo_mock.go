@@ -0,0 +1,65 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// MockTransferRepository is a mock implementation of TransferRepository
+type MockTransferRepository struct {
+	CreateFunc            func(transfer *models.Transfer) error
+	FindByIDFunc          func(id uuid.UUID) (*models.Transfer, error)
+	FindByIDForUpdateFunc func(id uuid.UUID) (*models.Transfer, error)
+	FindByUserIDFunc      func(userID uuid.UUID, limit, offset int) ([]*models.Transfer, error)
+	CountByUserIDFunc     func(userID uuid.UUID) (int64, error)
+	UpdateFunc            func(transfer *models.Transfer) error
+}
+
+func (m *MockTransferRepository) Create(transfer *models.Transfer) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(transfer)
+	}
+	return nil
+}
+
+func (m *MockTransferRepository) FindByID(id uuid.UUID) (*models.Transfer, error) {
+	if m.FindByIDFunc != nil {
+		return m.FindByIDFunc(id)
+	}
+	return nil, nil
+}
+
+// FindByIDForUpdate falls back to FindByIDFunc when no locking behaviour is needed
+func (m *MockTransferRepository) FindByIDForUpdate(id uuid.UUID) (*models.Transfer, error) {
+	if m.FindByIDForUpdateFunc != nil {
+		return m.FindByIDForUpdateFunc(id)
+	}
+	return m.FindByID(id)
+}
+
+func (m *MockTransferRepository) FindByUserID(userID uuid.UUID, limit, offset int) ([]*models.Transfer, error) {
+	if m.FindByUserIDFunc != nil {
+		return m.FindByUserIDFunc(userID, limit, offset)
+	}
+	return nil, nil
+}
+
+func (m *MockTransferRepository) CountByUserID(userID uuid.UUID) (int64, error) {
+	if m.CountByUserIDFunc != nil {
+		return m.CountByUserIDFunc(userID)
+	}
+	return 0, nil
+}
+
+func (m *MockTransferRepository) Update(transfer *models.Transfer) error {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(transfer)
+	}
+	return nil
+}
+
+func (m *MockTransferRepository) WithTx(tx *gorm.DB) repository.TransferRepository {
+	return m
+}
@@ -0,0 +1,44 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+)
+
+// MockTrashService is a mock implementation of TrashService
+type MockTrashService struct {
+	GetTrashFunc     func(userID uuid.UUID) (*services.Trash, error)
+	RestoreItemFunc  func(itemType string, id, userID uuid.UUID) (interface{}, error)
+	PurgeItemFunc    func(itemType string, id, userID uuid.UUID) error
+	PurgeExpiredFunc func(now time.Time) (int, error)
+}
+
+func (m *MockTrashService) GetTrash(userID uuid.UUID) (*services.Trash, error) {
+	if m.GetTrashFunc != nil {
+		return m.GetTrashFunc(userID)
+	}
+	return nil, nil
+}
+
+func (m *MockTrashService) RestoreItem(itemType string, id, userID uuid.UUID) (interface{}, error) {
+	if m.RestoreItemFunc != nil {
+		return m.RestoreItemFunc(itemType, id, userID)
+	}
+	return nil, nil
+}
+
+func (m *MockTrashService) PurgeItem(itemType string, id, userID uuid.UUID) error {
+	if m.PurgeItemFunc != nil {
+		return m.PurgeItemFunc(itemType, id, userID)
+	}
+	return nil
+}
+
+func (m *MockTrashService) PurgeExpired(now time.Time) (int, error) {
+	if m.PurgeExpiredFunc != nil {
+		return m.PurgeExpiredFunc(now)
+	}
+	return 0, nil
+}
@@ -0,0 +1,24 @@
+package mocks
+
+import (
+	"github.com/nyunja/fity-budget-backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// MockTxManager is a mock implementation of TxManager
+type MockTxManager struct {
+	WithinTransactionFunc func(fn func(tx *gorm.DB) error) error
+}
+
+// WithinTransaction runs fn directly unless a custom implementation is set
+func (m *MockTxManager) WithinTransaction(fn func(tx *gorm.DB) error) error {
+	if m.WithinTransactionFunc != nil {
+		return m.WithinTransactionFunc(fn)
+	}
+	return fn(nil)
+}
+
+// WithTx returns the mock itself so nested transactions run through it too
+func (m *MockTxManager) WithTx(tx *gorm.DB) repository.TxManager {
+	return m
+}
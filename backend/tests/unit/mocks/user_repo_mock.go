@@ -0,0 +1,83 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"github.com/nyunja/fity-budget-backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// MockUserRepository is a mock implementation of UserRepository
+type MockUserRepository struct {
+	CreateFunc              func(user *models.User) error
+	FindByIDFunc            func(id uuid.UUID) (*models.User, error)
+	FindByIDForUpdateFunc   func(id uuid.UUID) (*models.User, error)
+	FindByEmailFunc         func(email string) (*models.User, error)
+	FindAllFunc             func() ([]*models.User, error)
+	UpdateFunc              func(user *models.User) error
+	UpdateMonthlyIncomeFunc func(id uuid.UUID, income money.Amount) error
+	DeleteFunc              func(id uuid.UUID) error
+}
+
+func (m *MockUserRepository) Create(user *models.User) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(user)
+	}
+	return nil
+}
+
+func (m *MockUserRepository) FindByID(id uuid.UUID) (*models.User, error) {
+	if m.FindByIDFunc != nil {
+		return m.FindByIDFunc(id)
+	}
+	return nil, nil
+}
+
+// FindByIDForUpdate falls back to FindByIDFunc when no locking behaviour is needed
+func (m *MockUserRepository) FindByIDForUpdate(id uuid.UUID) (*models.User, error) {
+	if m.FindByIDForUpdateFunc != nil {
+		return m.FindByIDForUpdateFunc(id)
+	}
+	return m.FindByID(id)
+}
+
+func (m *MockUserRepository) FindByEmail(email string) (*models.User, error) {
+	if m.FindByEmailFunc != nil {
+		return m.FindByEmailFunc(email)
+	}
+	return nil, nil
+}
+
+func (m *MockUserRepository) FindAll() ([]*models.User, error) {
+	if m.FindAllFunc != nil {
+		return m.FindAllFunc()
+	}
+	return nil, nil
+}
+
+func (m *MockUserRepository) Update(user *models.User) error {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(user)
+	}
+	return nil
+}
+
+func (m *MockUserRepository) UpdateMonthlyIncome(id uuid.UUID, income money.Amount) error {
+	if m.UpdateMonthlyIncomeFunc != nil {
+		return m.UpdateMonthlyIncomeFunc(id, income)
+	}
+	return nil
+}
+
+func (m *MockUserRepository) Delete(id uuid.UUID) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(id)
+	}
+	return nil
+}
+
+// WithTx returns the mock itself so calls made inside a transaction stay observable
+func (m *MockUserRepository) WithTx(tx *gorm.DB) repository.UserRepository {
+	return m
+}
@@ -0,0 +1,149 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"github.com/nyunja/fity-budget-backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// MockWalletRepository is a mock implementation of WalletRepository
+type MockWalletRepository struct {
+	CreateFunc              func(wallet *models.Wallet) error
+	FindByIDFunc            func(id uuid.UUID) (*models.Wallet, error)
+	FindByIDForUpdateFunc   func(id uuid.UUID) (*models.Wallet, error)
+	FindByUserIDFunc        func(userID uuid.UUID) ([]*models.Wallet, error)
+	FindDefaultByUserIDFunc func(userID uuid.UUID) (*models.Wallet, error)
+	FindAllFunc             func() ([]*models.Wallet, error)
+	UpdateFunc              func(wallet *models.Wallet) error
+	UpdateColumnsFunc       func(id uuid.UUID, columns map[string]interface{}) error
+	DeleteFunc              func(id uuid.UUID) error
+	UpdateBalanceFunc       func(id uuid.UUID, amount money.Amount) error
+	FindDeletedByUserIDFunc func(userID uuid.UUID) ([]*models.Wallet, error)
+	FindDeletedByIDFunc     func(id uuid.UUID) (*models.Wallet, error)
+	FindDeletedBeforeFunc   func(cutoff time.Time, limit int) ([]*models.Wallet, error)
+	RestoreFunc             func(id uuid.UUID) error
+	PurgeFunc               func(id uuid.UUID) error
+	CountReferencesFunc     func(id uuid.UUID) (int64, error)
+}
+
+func (m *MockWalletRepository) Create(wallet *models.Wallet) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(wallet)
+	}
+	return nil
+}
+
+func (m *MockWalletRepository) FindByID(id uuid.UUID) (*models.Wallet, error) {
+	if m.FindByIDFunc != nil {
+		return m.FindByIDFunc(id)
+	}
+	return nil, nil
+}
+
+// FindByIDForUpdate falls back to FindByIDFunc when no locking behaviour is needed
+func (m *MockWalletRepository) FindByIDForUpdate(id uuid.UUID) (*models.Wallet, error) {
+	if m.FindByIDForUpdateFunc != nil {
+		return m.FindByIDForUpdateFunc(id)
+	}
+	return m.FindByID(id)
+}
+
+func (m *MockWalletRepository) FindByUserID(userID uuid.UUID) ([]*models.Wallet, error) {
+	if m.FindByUserIDFunc != nil {
+		return m.FindByUserIDFunc(userID)
+	}
+	return nil, nil
+}
+
+func (m *MockWalletRepository) FindDefaultByUserID(userID uuid.UUID) (*models.Wallet, error) {
+	if m.FindDefaultByUserIDFunc != nil {
+		return m.FindDefaultByUserIDFunc(userID)
+	}
+	return nil, nil
+}
+
+func (m *MockWalletRepository) FindAll() ([]*models.Wallet, error) {
+	if m.FindAllFunc != nil {
+		return m.FindAllFunc()
+	}
+	return nil, nil
+}
+
+func (m *MockWalletRepository) Update(wallet *models.Wallet) error {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(wallet)
+	}
+	return nil
+}
+
+func (m *MockWalletRepository) UpdateColumns(id uuid.UUID, columns map[string]interface{}) error {
+	if m.UpdateColumnsFunc != nil {
+		return m.UpdateColumnsFunc(id, columns)
+	}
+	return nil
+}
+
+func (m *MockWalletRepository) Delete(id uuid.UUID) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(id)
+	}
+	return nil
+}
+
+func (m *MockWalletRepository) UpdateBalance(id uuid.UUID, amount money.Amount) error {
+	if m.UpdateBalanceFunc != nil {
+		return m.UpdateBalanceFunc(id, amount)
+	}
+	return nil
+}
+
+func (m *MockWalletRepository) FindDeletedByUserID(userID uuid.UUID) ([]*models.Wallet, error) {
+	if m.FindDeletedByUserIDFunc != nil {
+		return m.FindDeletedByUserIDFunc(userID)
+	}
+	return nil, nil
+}
+
+func (m *MockWalletRepository) FindDeletedByID(id uuid.UUID) (*models.Wallet, error) {
+	if m.FindDeletedByIDFunc != nil {
+		return m.FindDeletedByIDFunc(id)
+	}
+	return nil, nil
+}
+
+func (m *MockWalletRepository) FindDeletedBefore(cutoff time.Time, limit int) ([]*models.Wallet, error) {
+	if m.FindDeletedBeforeFunc != nil {
+		return m.FindDeletedBeforeFunc(cutoff, limit)
+	}
+	return nil, nil
+}
+
+func (m *MockWalletRepository) Restore(id uuid.UUID) error {
+	if m.RestoreFunc != nil {
+		return m.RestoreFunc(id)
+	}
+	return nil
+}
+
+func (m *MockWalletRepository) Purge(id uuid.UUID) error {
+	if m.PurgeFunc != nil {
+		return m.PurgeFunc(id)
+	}
+	return nil
+}
+
+func (m *MockWalletRepository) CountReferences(id uuid.UUID) (int64, error) {
+	if m.CountReferencesFunc != nil {
+		return m.CountReferencesFunc(id)
+	}
+	return 0, nil
+}
+
+// WithTx returns the mock itself so calls made inside a transaction stay observable
+func (m *MockWalletRepository) WithTx(tx *gorm.DB) repository.WalletRepository {
+	return m
+}
@@ -15,7 +15,10 @@ type MockWalletService struct {
 	UpdateWalletFunc           func(id, userID uuid.UUID, req services.UpdateWalletRequest) (*models.Wallet, error)
 	DeleteWalletFunc           func(id, userID uuid.UUID) error
 	SetDefaultWalletFunc       func(id, userID uuid.UUID) (*models.Wallet, error)
-	TransferBetweenWalletsFunc func(fromWalletID, toWalletID, userID uuid.UUID, amount float64) error
+	TransferBetweenWalletsFunc func(userID uuid.UUID, req services.CreateTransferRequest) (*models.Transfer, error)
+	GetUserTransfersFunc       func(userID uuid.UUID, limit, offset int) ([]*models.Transfer, int64, error)
+	GetTransferByIDFunc        func(id, userID uuid.UUID) (*models.Transfer, error)
+	ReverseTransferFunc        func(id, userID uuid.UUID) (*models.Transfer, error)
 }
 
 func (m *MockWalletService) CreateWallet(userID uuid.UUID, req services.CreateWalletRequest) (*models.Wallet, error) {
@@ -67,9 +70,30 @@ func (m *MockWalletService) SetDefaultWallet(id, userID uuid.UUID) (*models.Wall
 	return nil, nil
 }
 
-func (m *MockWalletService) TransferBetweenWallets(fromWalletID, toWalletID, userID uuid.UUID, amount float64) error {
+func (m *MockWalletService) TransferBetweenWallets(userID uuid.UUID, req services.CreateTransferRequest) (*models.Transfer, error) {
 	if m.TransferBetweenWalletsFunc != nil {
-		return m.TransferBetweenWalletsFunc(fromWalletID, toWalletID, userID, amount)
+		return m.TransferBetweenWalletsFunc(userID, req)
 	}
-	return nil
+	return nil, nil
+}
+
+func (m *MockWalletService) GetUserTransfers(userID uuid.UUID, limit, offset int) ([]*models.Transfer, int64, error) {
+	if m.GetUserTransfersFunc != nil {
+		return m.GetUserTransfersFunc(userID, limit, offset)
+	}
+	return nil, 0, nil
+}
+
+func (m *MockWalletService) GetTransferByID(id, userID uuid.UUID) (*models.Transfer, error) {
+	if m.GetTransferByIDFunc != nil {
+		return m.GetTransferByIDFunc(id, userID)
+	}
+	return nil, nil
+}
+
+func (m *MockWalletService) ReverseTransfer(id, userID uuid.UUID) (*models.Transfer, error) {
+	if m.ReverseTransferFunc != nil {
+		return m.ReverseTransferFunc(id, userID)
+	}
+	return nil, nil
 }
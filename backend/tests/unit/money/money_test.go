@@ -0,0 +1,266 @@
+package money
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/nyunja/fity-budget-backend/internal/money"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected money.Amount
+		wantErr  bool
+	}{
+		{input: "1250", expected: money.FromMinor(125000)},
+		{input: "12.5", expected: money.FromMinor(1250)},
+		{input: "-0.07", expected: money.FromMinor(-7)},
+		{input: "1.2e3", expected: money.FromMinor(120000)},
+		{input: "0.125", expected: money.FromMinor(12)},
+		{input: "0.135", expected: money.FromMinor(14)},
+		{input: "-0.125", expected: money.FromMinor(-12)},
+		{input: "2.675", expected: money.FromMinor(268)},
+		{input: "", wantErr: true},
+		{input: "ten", wantErr: true},
+		{input: "1e30", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := money.Parse(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("Expected %d minor units, got %d", tt.expected.Minor(), got.Minor())
+			}
+		})
+	}
+}
+
+func TestAmount_SumsExactly(t *testing.T) {
+	var total money.Amount
+	for i := 0; i < 10; i++ {
+		total += money.MustParse("0.10")
+	}
+	if total != money.FromMajor(1) {
+		t.Errorf("Expected 1.00, got %s", total)
+	}
+}
+
+func TestAmount_String(t *testing.T) {
+	tests := map[money.Amount]string{
+		money.FromMinor(0):      "0.00",
+		money.FromMinor(5):      "0.05",
+		money.FromMinor(-5):     "-0.05",
+		money.FromMajor(12450):  "12450.00",
+		money.MustParse("-3.5"): "-3.50",
+	}
+	for amount, expected := range tests {
+		if got := amount.String(); got != expected {
+			t.Errorf("Expected %q, got %q", expected, got)
+		}
+	}
+}
+
+func TestAmount_JSON(t *testing.T) {
+	var payload struct {
+		Amount money.Amount `json:"amount"`
+	}
+
+	for _, input := range []string{`{"amount": 150.25}`, `{"amount": "150.25"}`} {
+		if err := json.Unmarshal([]byte(input), &payload); err != nil {
+			t.Fatalf("Unexpected error for %s: %v", input, err)
+		}
+		if payload.Amount != money.FromMinor(15025) {
+			t.Errorf("Expected 150.25 from %s, got %s", input, payload.Amount)
+		}
+	}
+
+	if err := json.Unmarshal([]byte(`{"amount": "abc"}`), &payload); err == nil {
+		t.Error("Expected error for a non-numeric amount")
+	}
+
+	tests := map[money.Amount]string{
+		money.FromMajor(500):     `500`,
+		money.FromMinor(15025):   `150.25`,
+		money.MustParse("12.50"): `12.5`,
+		money.FromMinor(-7):      `-0.07`,
+		money.FromMinor(0):       `0`,
+	}
+	for amount, expected := range tests {
+		data, err := json.Marshal(amount)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if string(data) != expected {
+			t.Errorf("Expected %s, got %s", expected, data)
+		}
+	}
+}
+
+func TestAmount_ScanAndValue(t *testing.T) {
+	var a money.Amount
+	if err := a.Scan([]byte("1234.56")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if a != money.FromMinor(123456) {
+		t.Errorf("Expected 1234.56, got %s", a)
+	}
+
+	if err := a.Scan(nil); err != nil || a != 0 {
+		t.Errorf("Expected NULL to scan as zero, got %s (%v)", a, err)
+	}
+
+	value, err := money.FromMinor(-1050).Value()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if value != "-10.50" {
+		t.Errorf("Expected \"-10.50\", got %v", value)
+	}
+}
+
+func TestAmount_MulRatRoundsHalfToEven(t *testing.T) {
+	tests := []struct {
+		amount   money.Amount
+		num, den int64
+		expected money.Amount
+	}{
+		{amount: money.FromMinor(100), num: 1, den: 3, expected: money.FromMinor(33)},
+		{amount: money.FromMinor(5), num: 1, den: 2, expected: money.FromMinor(2)},
+		{amount: money.FromMinor(15), num: 1, den: 10, expected: money.FromMinor(2)},
+		{amount: money.FromMinor(-5), num: 1, den: 2, expected: money.FromMinor(-2)},
+		{amount: money.FromMajor(5000), num: 20, den: 100, expected: money.FromMajor(1000)},
+	}
+	for _, tt := range tests {
+		if got := tt.amount.MulRat(tt.num, tt.den); got != tt.expected {
+			t.Errorf("%s * %d/%d: expected %s, got %s", tt.amount, tt.num, tt.den, tt.expected, got)
+		}
+	}
+}
+
+func TestAmount_Allocate(t *testing.T) {
+	parts := money.FromMajor(100).Allocate(1, 1, 1)
+
+	var total money.Amount
+	for _, part := range parts {
+		total += part
+	}
+	if total != money.FromMajor(100) {
+		t.Errorf("Expected parts to add up to 100.00, got %s", total)
+	}
+	if parts[0] != money.FromMinor(3334) || parts[1] != money.FromMinor(3333) || parts[2] != money.FromMinor(3333) {
+		t.Errorf("Expected 33.34, 33.33, 33.33, got %v", parts)
+	}
+
+	parts = money.FromMajor(10).Allocate(50, 30, 20)
+	if parts[0] != money.FromMajor(5) || parts[1] != money.FromMajor(3) || parts[2] != money.FromMajor(2) {
+		t.Errorf("Expected 5, 3, 2, got %v", parts)
+	}
+}
+
+func TestAmount_RoundToCurrency(t *testing.T) {
+	tests := []struct {
+		amount   money.Amount
+		currency string
+		expected money.Amount
+	}{
+		{amount: money.MustParse("1250.49"), currency: "JPY", expected: money.FromMajor(1250)},
+		{amount: money.MustParse("1250.50"), currency: "JPY", expected: money.FromMajor(1250)},
+		{amount: money.MustParse("1251.50"), currency: "ugx", expected: money.FromMajor(1252)},
+		{amount: money.MustParse("1250.49"), currency: "KES", expected: money.MustParse("1250.49")},
+		{amount: money.MustParse("1250.49"), currency: "KWD", expected: money.MustParse("1250.49")},
+	}
+	for _, tt := range tests {
+		if got := tt.amount.Round(tt.currency); got != tt.expected {
+			t.Errorf("%s in %s: expected %s, got %s", tt.amount, tt.currency, tt.expected, got)
+		}
+	}
+}
+
+func TestMoney(t *testing.T) {
+	kes := money.New(money.MustParse("100.50"), "kes")
+	if kes.Currency != "KES" {
+		t.Errorf("Expected currency to be normalised to KES, got %q", kes.Currency)
+	}
+	if kes.String() != "KES 100.50" {
+		t.Errorf("Expected \"KES 100.50\", got %q", kes.String())
+	}
+	if kes.MinorUnits() != 10050 {
+		t.Errorf("Expected 10050 cents, got %d", kes.MinorUnits())
+	}
+
+	jpy := money.New(money.MustParse("980.40"), "JPY")
+	if jpy.String() != "JPY 980" || jpy.MinorUnits() != 980 {
+		t.Errorf("Expected JPY 980, got %s (%d)", jpy, jpy.MinorUnits())
+	}
+
+	if _, err := kes.Add(jpy); err != money.ErrCurrencyMismatch {
+		t.Errorf("Expected currency mismatch, got %v", err)
+	}
+	sum, err := kes.Add(money.New(money.FromMajor(1), "KES"))
+	if err != nil || sum.Amount != money.MustParse("101.50") {
+		t.Errorf("Expected KES 101.50, got %s (%v)", sum, err)
+	}
+}
+
+func TestParseRate(t *testing.T) {
+	rate, err := money.ParseRate("129.45")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if rate.String() != "129.45000000" {
+		t.Errorf("Expected 129.45000000, got %s", rate)
+	}
+
+	for _, input := range []string{"", "0", "-1.2", "abc"} {
+		if _, err := money.ParseRate(input); err == nil {
+			t.Errorf("Expected error for %q", input)
+		}
+	}
+}
+
+func TestRate_Convert(t *testing.T) {
+	usdKes, _ := money.ParseRate("129.45")
+	if got := money.FromMajor(10).Convert(usdKes); got != money.MustParse("1294.50") {
+		t.Errorf("Expected 1294.50, got %s", got)
+	}
+
+	kesUsd := usdKes.Inverse()
+	if kesUsd.String() != "0.00772499" {
+		t.Errorf("Expected inverse 0.00772499, got %s", kesUsd)
+	}
+	if got := money.MustParse("1294.50").Convert(kesUsd); got != money.FromMajor(10) {
+		t.Errorf("Expected 10.00, got %s", got)
+	}
+
+	eurUsd, _ := money.ParseRate("1.08")
+	if cross := eurUsd.Cross(usdKes); cross.String() != "139.80600000" {
+		t.Errorf("Expected EUR/KES 139.80600000, got %s", cross)
+	}
+}
+
+func TestRate_JSONAndScan(t *testing.T) {
+	rate, _ := money.ParseRate("1.0921")
+	data, err := json.Marshal(rate)
+	if err != nil || string(data) != "1.0921" {
+		t.Errorf("Expected 1.0921, got %s (%v)", data, err)
+	}
+	data, _ = json.Marshal(money.OneRate)
+	if string(data) != "1" {
+		t.Errorf("Expected 1, got %s", data)
+	}
+
+	var scanned money.Rate
+	if err := scanned.Scan([]byte("1.09210000")); err != nil || scanned != rate {
+		t.Errorf("Expected %s, got %s (%v)", rate, scanned, err)
+	}
+}
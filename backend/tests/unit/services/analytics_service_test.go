@@ -0,0 +1,320 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+	"github.com/nyunja/fity-budget-backend/tests/unit/mocks"
+	"github.com/nyunja/fity-budget-backend/tests/unit/testutils"
+)
+
+// monthTransactions returns a mix of income, spending and transfers dated this month
+func monthTransactions() []*models.Transaction {
+	now := time.Now()
+	return []*models.Transaction{
+		{ID: uuid.New(), Type: models.TransactionTypeIncome, Amount: money.FromMajor(5000), Category: "Salary", Status: "Completed", TransactionDate: now},
+		{ID: uuid.New(), Type: models.TransactionTypeExpense, Amount: money.FromMajor(1200), Category: "Food & Groceries", Status: "Completed", TransactionDate: now},
+		{ID: uuid.New(), Type: models.TransactionTypeExpense, Amount: money.FromMajor(300), Category: "Transportation", Status: "Completed", TransactionDate: now},
+		{ID: uuid.New(), Type: models.TransactionTypeTransfer, Amount: money.FromMajor(2000), Category: "Transfer", Status: "Completed", TransactionDate: now},
+		{ID: uuid.New(), Type: models.TransactionTypeExpense, Amount: money.FromMajor(999), Category: "Shopping", Status: "Pending", TransactionDate: now},
+	}
+}
+
+func newAnalyticsService(transactions []*models.Transaction, budgets []*models.Budget) services.AnalyticsService {
+	transactionRepo := &mocks.MockTransactionRepository{
+		AggregateFunc: aggregateTransactions(transactions),
+	}
+	walletRepo := &mocks.MockWalletRepository{
+		FindByUserIDFunc: func(userID uuid.UUID) ([]*models.Wallet, error) {
+			return []*models.Wallet{{Balance: money.FromMajor(15000)}}, nil
+		},
+	}
+	budgetRepo := &mocks.MockBudgetRepository{
+		FindByUserIDFunc: func(userID uuid.UUID) ([]*models.Budget, error) {
+			return budgets, nil
+		},
+	}
+	goalRepo := &mocks.MockGoalRepository{}
+
+	return services.NewAnalyticsService(transactionRepo, walletRepo, budgetRepo, goalRepo, &mocks.MockUserRepository{}, &mocks.MockExchangeRateRepository{}, &mocks.MockCategoryRepository{}, &mocks.MockTagRepository{})
+}
+
+func TestAnalyticsService_GetDashboardSummary_SplitsByType(t *testing.T) {
+	service := newAnalyticsService(monthTransactions(), nil)
+
+	summary, err := service.GetDashboardSummary(testutils.TestUserID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if summary.TotalIncome != money.FromMajor(5000) {
+		t.Errorf("Expected total income 5000, got %v", summary.TotalIncome)
+	}
+	if summary.TotalExpense != money.FromMajor(1500) {
+		t.Errorf("Expected total expense 1500, got %v", summary.TotalExpense)
+	}
+	if summary.NetSavings != money.FromMajor(3500) {
+		t.Errorf("Expected net savings 3500, got %v", summary.NetSavings)
+	}
+	if len(summary.TopCategories) != 2 {
+		t.Fatalf("Expected 2 spending categories, got %d", len(summary.TopCategories))
+	}
+	if summary.TopCategories[0].Category != "Food & Groceries" {
+		t.Errorf("Expected top category to be Food & Groceries, got %s", summary.TopCategories[0].Category)
+	}
+	if summary.MonthComparison.CurrentMonthIncome != money.FromMajor(5000) {
+		t.Errorf("Expected current month income 5000, got %v", summary.MonthComparison.CurrentMonthIncome)
+	}
+}
+
+func TestAnalyticsService_GetSpendingByCategory_RollsUpSubcategories(t *testing.T) {
+	now := time.Now()
+	foodID := uuid.New()
+	transactions := []*models.Transaction{
+		{ID: uuid.New(), Type: models.TransactionTypeExpense, Amount: money.FromMajor(1000), Category: "groceries", Status: "Completed", TransactionDate: now},
+		{ID: uuid.New(), Type: models.TransactionTypeExpense, Amount: money.FromMajor(500), Category: "Groceries", Status: "Completed", TransactionDate: now},
+		{ID: uuid.New(), Type: models.TransactionTypeExpense, Amount: money.FromMajor(300), Category: "Dining Out", Status: "Completed", TransactionDate: now},
+		{ID: uuid.New(), Type: models.TransactionTypeExpense, Amount: money.FromMajor(200), Category: "Food", Status: "Completed", TransactionDate: now},
+		{ID: uuid.New(), Type: models.TransactionTypeExpense, Amount: money.FromMajor(100), Category: "Transport", Status: "Completed", TransactionDate: now},
+	}
+	categoryRepo := &mocks.MockCategoryRepository{
+		FindByUserIDFunc: func(userID uuid.UUID) ([]*models.Category, error) {
+			return []*models.Category{
+				{ID: foodID, Name: "Food", Kind: models.CategoryKindExpense},
+				{ID: uuid.New(), ParentID: &foodID, Name: "Groceries", Kind: models.CategoryKindExpense},
+				{ID: uuid.New(), ParentID: &foodID, Name: "Dining Out", Kind: models.CategoryKindExpense},
+			}, nil
+		},
+	}
+	budgetRepo := &mocks.MockBudgetRepository{
+		FindByUserIDFunc: func(userID uuid.UUID) ([]*models.Budget, error) {
+			return []*models.Budget{{ID: uuid.New(), Category: "food", LimitAmount: money.FromMajor(2500)}}, nil
+		},
+	}
+	service := services.NewAnalyticsService(&mocks.MockTransactionRepository{AggregateFunc: aggregateTransactions(transactions)},
+		&mocks.MockWalletRepository{}, budgetRepo, &mocks.MockGoalRepository{}, &mocks.MockUserRepository{}, &mocks.MockExchangeRateRepository{}, categoryRepo, &mocks.MockTagRepository{})
+
+	tests := []struct {
+		name     string
+		rollup   bool
+		expected map[string]money.Amount
+	}{
+		{
+			name:   "subcategories listed on their own",
+			rollup: false,
+			expected: map[string]money.Amount{
+				"Groceries":  money.FromMajor(1500),
+				"Dining Out": money.FromMajor(300),
+				"Food":       money.FromMajor(200),
+				"Transport":  money.FromMajor(100),
+			},
+		},
+		{
+			name:   "subcategories rolled up into their parent",
+			rollup: true,
+			expected: map[string]money.Amount{
+				"Food":      money.FromMajor(2000),
+				"Transport": money.FromMajor(100),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := service.GetSpendingByCategory(testutils.TestUserID, now.AddDate(0, 0, -1), now.AddDate(0, 0, 1), tt.rollup)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if report.TotalSpending != money.FromMajor(2100) {
+				t.Errorf("Expected total spending 2100, got %v", report.TotalSpending)
+			}
+			if len(report.Categories) != len(tt.expected) {
+				t.Fatalf("Expected %d categories, got %d", len(tt.expected), len(report.Categories))
+			}
+			for _, category := range report.Categories {
+				if category.Amount != tt.expected[category.Category] {
+					t.Errorf("Expected %v spent on %s, got %v", tt.expected[category.Category], category.Category, category.Amount)
+				}
+				if category.Category == "Food" && tt.rollup && category.BudgetLimit != money.FromMajor(2500) {
+					t.Errorf("Expected the food budget limit on the rolled up category, got %v", category.BudgetLimit)
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyticsService_GetSpendingByTag(t *testing.T) {
+	now := time.Now()
+	trip := models.Tag{ID: uuid.New(), Name: "Mombasa trip 2026"}
+	reimbursable := models.Tag{ID: uuid.New(), Name: "reimbursable", Color: "#F59E0B"}
+	unused := models.Tag{ID: uuid.New(), Name: "unused"}
+	transactions := []*models.Transaction{
+		{ID: uuid.New(), Type: models.TransactionTypeExpense, Amount: money.FromMajor(4000), Category: "Travel", Status: "Completed", TransactionDate: now, Tags: []models.Tag{trip, reimbursable}},
+		{ID: uuid.New(), Type: models.TransactionTypeExpense, Amount: money.FromMajor(1500), Category: "Food", Status: "Completed", TransactionDate: now, Tags: []models.Tag{trip},
+			Splits: []models.TransactionSplit{{Category: "Food", Amount: money.FromMajor(1000)}, {Category: "Drinks", Amount: money.FromMajor(500)}}},
+		{ID: uuid.New(), Type: models.TransactionTypeExpense, Amount: money.FromMajor(700), Category: "Food", Status: "Pending", TransactionDate: now, Tags: []models.Tag{trip}},
+		{ID: uuid.New(), Type: models.TransactionTypeIncome, Amount: money.FromMajor(4000), Category: "Income", Status: "Completed", TransactionDate: now, Tags: []models.Tag{reimbursable}},
+		{ID: uuid.New(), Type: models.TransactionTypeExpense, Amount: money.FromMajor(900), Category: "Food", Status: "Completed", TransactionDate: now},
+	}
+	tagRepo := &mocks.MockTagRepository{
+		FindByUserIDFunc: func(userID uuid.UUID) ([]*models.Tag, error) {
+			return []*models.Tag{&trip, &reimbursable, &unused}, nil
+		},
+	}
+	service := services.NewAnalyticsService(&mocks.MockTransactionRepository{AggregateFunc: aggregateTransactions(transactions)},
+		&mocks.MockWalletRepository{}, &mocks.MockBudgetRepository{}, &mocks.MockGoalRepository{}, &mocks.MockUserRepository{}, &mocks.MockExchangeRateRepository{}, &mocks.MockCategoryRepository{}, tagRepo)
+
+	report, err := service.GetSpendingByTag(testutils.TestUserID, now.AddDate(0, 0, -1), now.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Only completed spending counts, and tags without any are left out
+	if len(report.Tags) != 2 {
+		t.Fatalf("Expected 2 tags, got %d", len(report.Tags))
+	}
+	first, second := report.Tags[0], report.Tags[1]
+	if first.TagID != trip.ID || first.Amount != money.FromMajor(5500) || first.Count != 2 {
+		t.Errorf("Expected the trip first with 5500 over 2 transactions, got %s with %v over %d", first.Tag, first.Amount, first.Count)
+	}
+	if second.TagID != reimbursable.ID || second.Amount != money.FromMajor(4000) || second.Color != reimbursable.Color {
+		t.Errorf("Expected reimbursable spending of 4000, got %+v", second)
+	}
+
+	// Within a tag, split lines count under their own categories
+	expected := map[string]money.Amount{"Travel": money.FromMajor(4000), "Food": money.FromMajor(1000), "Drinks": money.FromMajor(500)}
+	if len(first.Categories) != len(expected) {
+		t.Fatalf("Expected %d categories within the trip, got %d", len(expected), len(first.Categories))
+	}
+	for _, category := range first.Categories {
+		if category.Amount != expected[category.Category] {
+			t.Errorf("Expected %s to be %v, got %v", category.Category, expected[category.Category], category.Amount)
+		}
+	}
+	if first.Categories[0].Category != "Travel" {
+		t.Errorf("Expected categories ordered by amount, got %s first", first.Categories[0].Category)
+	}
+}
+
+func TestAnalyticsService_GetIncomeVsExpense_SavingsRate(t *testing.T) {
+	service := newAnalyticsService(monthTransactions(), nil)
+
+	report, err := service.GetIncomeVsExpense(testutils.TestUserID, "month")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if report.TotalIncome != money.FromMajor(5000) || report.TotalExpense != money.FromMajor(1500) {
+		t.Errorf("Expected income 5000 and expense 1500, got %v and %v", report.TotalIncome, report.TotalExpense)
+	}
+	if report.SavingsRate != 70 {
+		t.Errorf("Expected savings rate 70, got %v", report.SavingsRate)
+	}
+}
+
+func TestAnalyticsService_GetMonthlyTrends_IgnoresTransfers(t *testing.T) {
+	service := newAnalyticsService(monthTransactions(), nil)
+
+	trends, err := service.GetMonthlyTrends(testutils.TestUserID, 3)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	last := len(trends.Months) - 1
+	if trends.IncomeData[last] != money.FromMajor(5000) {
+		t.Errorf("Expected income 5000 for the current month, got %v", trends.IncomeData[last])
+	}
+	if trends.ExpenseData[last] != money.FromMajor(1500) {
+		t.Errorf("Expected expense 1500 for the current month, got %v", trends.ExpenseData[last])
+	}
+	if trends.SavingsData[last] != money.FromMajor(3500) {
+		t.Errorf("Expected savings 3500 for the current month, got %v", trends.SavingsData[last])
+	}
+}
+
+func TestAnalyticsService_GetFinancialHealthScore_UsesIncome(t *testing.T) {
+	budgets := []*models.Budget{
+		{ID: uuid.New(), Category: "Food & Groceries", LimitAmount: money.FromMajor(1000), AlertThreshold: 80},
+		{ID: uuid.New(), Category: "Transportation", LimitAmount: money.FromMajor(400), AlertThreshold: 80},
+	}
+	service := newAnalyticsService(monthTransactions(), budgets)
+
+	score, err := service.GetFinancialHealthScore(testutils.TestUserID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if score.SavingsRatio != 70 {
+		t.Errorf("Expected savings ratio 70, got %v", score.SavingsRatio)
+	}
+	if score.BudgetCompliance != 50 {
+		t.Errorf("Expected budget compliance 50, got %v", score.BudgetCompliance)
+	}
+}
+
+func TestAnalyticsService_ConvertsToBaseCurrency(t *testing.T) {
+	kesWalletID, usdWalletID := uuid.New(), uuid.New()
+	now := time.Now()
+
+	transactionRepo := &mocks.MockTransactionRepository{
+		AggregateFunc: aggregateTransactions([]*models.Transaction{
+			{ID: uuid.New(), WalletID: &usdWalletID, Type: models.TransactionTypeIncome, Amount: money.FromMajor(100), Category: "Salary", Status: "Completed", TransactionDate: now},
+			{ID: uuid.New(), WalletID: &kesWalletID, Type: models.TransactionTypeExpense, Amount: money.FromMajor(2000), Category: "Food & Groceries", Status: "Completed", TransactionDate: now},
+			{ID: uuid.New(), WalletID: &usdWalletID, Type: models.TransactionTypeExpense, Amount: money.FromMajor(10), Category: "Food & Groceries", Status: "Completed", TransactionDate: now},
+		}),
+	}
+	walletRepo := &mocks.MockWalletRepository{
+		FindByUserIDFunc: func(userID uuid.UUID) ([]*models.Wallet, error) {
+			return []*models.Wallet{
+				{ID: kesWalletID, Balance: money.FromMajor(5000), Currency: "KES"},
+				{ID: usdWalletID, Balance: money.FromMajor(50), Currency: "USD"},
+			}, nil
+		},
+	}
+	userRepo := &mocks.MockUserRepository{
+		FindByIDFunc: func(id uuid.UUID) (*models.User, error) {
+			return &models.User{ID: id, Currency: "KES"}, nil
+		},
+	}
+	rateRepo := rateRepository(
+		&models.ExchangeRate{BaseCurrency: "USD", QuoteCurrency: "KES", Rate: mustRate("129"), RateDate: now.AddDate(0, 0, -30)},
+		&models.ExchangeRate{BaseCurrency: "USD", QuoteCurrency: "KES", Rate: mustRate("130"), RateDate: now.AddDate(0, 0, -1)},
+	)
+	service := services.NewAnalyticsService(transactionRepo, walletRepo, &mocks.MockBudgetRepository{}, &mocks.MockGoalRepository{}, userRepo, rateRepo, &mocks.MockCategoryRepository{}, &mocks.MockTagRepository{})
+
+	summary, err := service.GetDashboardSummary(testutils.TestUserID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if summary.Currency != "KES" {
+		t.Errorf("Expected report currency KES, got %s", summary.Currency)
+	}
+	if summary.TotalBalance != money.FromMajor(11500) {
+		t.Errorf("Expected total balance KES 11500, got %v", summary.TotalBalance)
+	}
+	if summary.TotalIncome != money.FromMajor(13000) || summary.TotalExpense != money.FromMajor(3300) {
+		t.Errorf("Expected income 13000 and expense 3300, got %v and %v", summary.TotalIncome, summary.TotalExpense)
+	}
+	if len(summary.ExchangeRates) != 1 || summary.ExchangeRates[0].From != "USD" || summary.ExchangeRates[0].Rate != mustRate("130") {
+		t.Fatalf("Expected the latest USD/KES rate to be reported, got %+v", summary.ExchangeRates)
+	}
+
+	// Without a rate the USD amounts are left out and listed, instead of adding up mixed currencies
+	service = services.NewAnalyticsService(transactionRepo, walletRepo, &mocks.MockBudgetRepository{}, &mocks.MockGoalRepository{}, userRepo, &mocks.MockExchangeRateRepository{}, &mocks.MockCategoryRepository{}, &mocks.MockTagRepository{})
+	summary, err = service.GetDashboardSummary(testutils.TestUserID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if summary.TotalBalance != money.FromMajor(5000) || summary.TotalIncome != 0 || summary.TotalExpense != money.FromMajor(2000) {
+		t.Errorf("Expected only the KES amounts, got balance %v, income %v and expense %v", summary.TotalBalance, summary.TotalIncome, summary.TotalExpense)
+	}
+	if len(summary.UnconvertedCurrencies) != 1 || summary.UnconvertedCurrencies[0] != "USD" {
+		t.Errorf("Expected USD to be listed as unconverted, got %v", summary.UnconvertedCurrencies)
+	}
+}
@@ -0,0 +1,871 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"github.com/nyunja/fity-budget-backend/internal/repository"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+	"github.com/nyunja/fity-budget-backend/tests/unit/mocks"
+	"github.com/nyunja/fity-budget-backend/tests/unit/testutils"
+	"gorm.io/gorm"
+)
+
+func TestBudgetService_CheckBudgetStatus(t *testing.T) {
+	now := time.Now()
+	lastMonth := now.AddDate(0, -1, 0)
+	transactions := []*models.Transaction{
+		{Type: models.TransactionTypeExpense, Amount: money.FromMajor(4500), Category: "Food & Groceries", Status: "Completed", TransactionDate: now},
+		{Type: models.TransactionTypeExpense, Amount: money.FromMajor(1000), Category: "Food & Groceries", Status: "Completed", TransactionDate: now},
+		{Type: models.TransactionTypeExpense, Amount: money.FromMajor(900), Category: "Food & Groceries", Status: "Completed", TransactionDate: lastMonth},
+		{Type: models.TransactionTypeExpense, Amount: money.FromMajor(850), Category: "Transportation", Status: "Completed", TransactionDate: now},
+		{Type: models.TransactionTypeExpense, Amount: money.FromMajor(500), Category: "Transportation", Status: "Pending", TransactionDate: now},
+		{Type: models.TransactionTypeIncome, Amount: money.FromMajor(700), Category: "Transportation", Status: "Completed", TransactionDate: now},
+	}
+
+	var calls int
+	transactionRepo := &mocks.MockTransactionRepository{
+		AggregateFunc: func(filter repository.TransactionFilter, groupBy ...repository.TransactionGroup) ([]*repository.TransactionAggregate, error) {
+			calls++
+			return aggregateTransactions(transactions)(filter, groupBy...)
+		},
+	}
+	budgetRepo := &mocks.MockBudgetRepository{
+		FindByUserIDFunc: func(userID uuid.UUID) ([]*models.Budget, error) {
+			return []*models.Budget{
+				{ID: uuid.New(), Category: "Food & Groceries", LimitAmount: money.FromMajor(5000), AlertThreshold: 80},
+				{ID: uuid.New(), Category: "Transportation", LimitAmount: money.FromMajor(1000), AlertThreshold: 80},
+				{ID: uuid.New(), Category: "Shopping", LimitAmount: money.FromMajor(2000), AlertThreshold: 80},
+			}, nil
+		},
+	}
+	service := services.NewBudgetService(budgetRepo, &mocks.MockBudgetLimitRepository{}, &mocks.MockBudgetLedgerRepository{}, transactionRepo, &mocks.MockCategoryRepository{}, &mocks.MockUserRepository{}, &mocks.MockWalletRepository{}, &mocks.MockExchangeRateRepository{}, &mocks.MockTxManager{})
+
+	statuses, err := service.CheckBudgetStatus(testutils.TestUserID, now)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if calls != 1 {
+		t.Errorf("Expected spending to be aggregated in one query, got %d", calls)
+	}
+	if len(statuses) != 3 {
+		t.Fatalf("Expected 3 statuses, got %d", len(statuses))
+	}
+
+	food, transport, shopping := statuses[0], statuses[1], statuses[2]
+	if food.SpentAmount != money.FromMajor(5500) || !food.IsOverBudget {
+		t.Errorf("Expected food to be over budget at 5500, got %v (over: %v)", food.SpentAmount, food.IsOverBudget)
+	}
+	if transport.SpentAmount != money.FromMajor(850) || !transport.IsNearLimit {
+		t.Errorf("Expected transport to be near its limit at 850, got %v (near: %v)", transport.SpentAmount, transport.IsNearLimit)
+	}
+	if shopping.SpentAmount != 0 || shopping.RemainingAmount != money.FromMajor(2000) {
+		t.Errorf("Expected nothing spent on shopping, got %v", shopping.SpentAmount)
+	}
+}
+
+func TestBudgetService_CheckBudgetStatus_ConvertsCurrency(t *testing.T) {
+	now := time.Now()
+	transactions := []*models.Transaction{
+		{Type: models.TransactionTypeExpense, Amount: money.FromMajor(1000), Category: "Food & Groceries", Status: "Completed", TransactionDate: now, WalletID: walletPtr(testutils.TestWalletID)},
+		{Type: models.TransactionTypeExpense, Amount: money.FromMajor(10), Category: "Food & Groceries", Status: "Completed", TransactionDate: now, WalletID: walletPtr(secondWalletID)},
+	}
+	budgetRepo := &mocks.MockBudgetRepository{
+		FindByUserIDFunc: func(userID uuid.UUID) ([]*models.Budget, error) {
+			return []*models.Budget{{ID: uuid.New(), Category: "Food & Groceries", LimitAmount: money.FromMajor(5000), AlertThreshold: 80}}, nil
+		},
+	}
+	userRepo := &mocks.MockUserRepository{
+		FindByIDFunc: func(id uuid.UUID) (*models.User, error) {
+			return &models.User{ID: id, Currency: "KES"}, nil
+		},
+	}
+	walletRepo := &mocks.MockWalletRepository{
+		FindByUserIDFunc: func(userID uuid.UUID) ([]*models.Wallet, error) {
+			return []*models.Wallet{{ID: testutils.TestWalletID, Currency: "KES"}, {ID: secondWalletID, Currency: "USD"}}, nil
+		},
+	}
+	rates := []*models.ExchangeRate{{BaseCurrency: "USD", QuoteCurrency: "KES", Rate: mustRate("130"), RateDate: now.AddDate(0, 0, -1)}}
+	rateRepo := &mocks.MockExchangeRateRepository{
+		FindLatestFunc: func(baseCurrency, quoteCurrency string, on time.Time) (*models.ExchangeRate, error) {
+			return latestRate(rates, baseCurrency, quoteCurrency, on), nil
+		},
+	}
+	transactionRepo := &mocks.MockTransactionRepository{AggregateFunc: aggregateTransactions(transactions)}
+	service := services.NewBudgetService(budgetRepo, &mocks.MockBudgetLimitRepository{}, &mocks.MockBudgetLedgerRepository{}, transactionRepo, &mocks.MockCategoryRepository{}, userRepo, walletRepo, rateRepo, &mocks.MockTxManager{})
+
+	summary, err := service.GetBudgetSummary(testutils.TestUserID, now)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// USD 10 at 130 adds KES 1300 to the KES 1000
+	if summary.TotalSpent != money.FromMajor(2300) || summary.Currency != "KES" {
+		t.Errorf("Expected KES 2300 spent, got %s %v", summary.Currency, summary.TotalSpent)
+	}
+}
+
+func TestBudgetService_CheckBudgetStatus_SplitTransactions(t *testing.T) {
+	now := time.Now()
+	transactions := []*models.Transaction{
+		{
+			ID: uuid.New(), Type: models.TransactionTypeExpense, Amount: money.FromMajor(3000), Category: "Food & Groceries", Status: "Completed", TransactionDate: now,
+			Splits: []models.TransactionSplit{
+				{Category: "Food & Groceries", Amount: money.FromMajor(1800)},
+				{Category: "Household", Amount: money.FromMajor(700)},
+				{Category: "Alcohol", Amount: money.FromMajor(500)},
+			},
+		},
+		{ID: uuid.New(), Type: models.TransactionTypeExpense, Amount: money.FromMajor(200), Category: "Household", Status: "Completed", TransactionDate: now},
+	}
+
+	transactionRepo := &mocks.MockTransactionRepository{
+		AggregateFunc: aggregateTransactions(transactions),
+	}
+	budgetRepo := &mocks.MockBudgetRepository{
+		FindByUserIDFunc: func(userID uuid.UUID) ([]*models.Budget, error) {
+			return []*models.Budget{
+				{ID: uuid.New(), Category: "Food & Groceries", LimitAmount: money.FromMajor(5000), AlertThreshold: 80},
+				{ID: uuid.New(), Category: "Household", LimitAmount: money.FromMajor(1000), AlertThreshold: 80},
+			}, nil
+		},
+	}
+	service := services.NewBudgetService(budgetRepo, &mocks.MockBudgetLimitRepository{}, &mocks.MockBudgetLedgerRepository{}, transactionRepo, &mocks.MockCategoryRepository{}, &mocks.MockUserRepository{}, &mocks.MockWalletRepository{}, &mocks.MockExchangeRateRepository{}, &mocks.MockTxManager{})
+
+	statuses, err := service.CheckBudgetStatus(testutils.TestUserID, now)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Only the groceries line of the supermarket receipt counts against the food budget
+	if statuses[0].SpentAmount != money.FromMajor(1800) {
+		t.Errorf("Expected 1800 spent on food, got %v", statuses[0].SpentAmount)
+	}
+	if statuses[1].SpentAmount != money.FromMajor(900) || !statuses[1].IsNearLimit {
+		t.Errorf("Expected household to be near its limit at 900, got %v", statuses[1].SpentAmount)
+	}
+}
+
+func TestBudgetService_CheckBudgetStatus_RollsUpSubcategories(t *testing.T) {
+	now := time.Now()
+	foodID := uuid.New()
+	transactions := []*models.Transaction{
+		{ID: uuid.New(), Type: models.TransactionTypeExpense, Amount: money.FromMajor(2000), Category: "Groceries", Status: "Completed", TransactionDate: now},
+		{ID: uuid.New(), Type: models.TransactionTypeExpense, Amount: money.FromMajor(800), Category: "dining out", Status: "Completed", TransactionDate: now},
+		{ID: uuid.New(), Type: models.TransactionTypeExpense, Amount: money.FromMajor(400), Category: "FOOD", Status: "Completed", TransactionDate: now},
+		{ID: uuid.New(), Type: models.TransactionTypeExpense, Amount: money.FromMajor(600), Category: "Rent", Status: "Completed", TransactionDate: now},
+	}
+
+	transactionRepo := &mocks.MockTransactionRepository{
+		AggregateFunc: aggregateTransactions(transactions),
+	}
+	budgetRepo := &mocks.MockBudgetRepository{
+		FindByUserIDFunc: func(userID uuid.UUID) ([]*models.Budget, error) {
+			return []*models.Budget{
+				{ID: uuid.New(), Category: "Food", LimitAmount: money.FromMajor(3000), AlertThreshold: 80},
+				{ID: uuid.New(), Category: "Dining Out", LimitAmount: money.FromMajor(1000), AlertThreshold: 80},
+			}, nil
+		},
+	}
+	categoryRepo := &mocks.MockCategoryRepository{
+		FindByUserIDFunc: func(userID uuid.UUID) ([]*models.Category, error) {
+			return []*models.Category{
+				{ID: foodID, Name: "Food", Kind: models.CategoryKindExpense},
+				{ID: uuid.New(), ParentID: &foodID, Name: "Groceries", Kind: models.CategoryKindExpense},
+				{ID: uuid.New(), ParentID: &foodID, Name: "Dining Out", Kind: models.CategoryKindExpense},
+			}, nil
+		},
+	}
+	service := services.NewBudgetService(budgetRepo, &mocks.MockBudgetLimitRepository{}, &mocks.MockBudgetLedgerRepository{}, transactionRepo, categoryRepo, &mocks.MockUserRepository{}, &mocks.MockWalletRepository{}, &mocks.MockExchangeRateRepository{}, &mocks.MockTxManager{})
+
+	statuses, err := service.CheckBudgetStatus(testutils.TestUserID, now)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The food budget covers groceries and dining out as well as food itself
+	if statuses[0].SpentAmount != money.FromMajor(3200) || !statuses[0].IsOverBudget {
+		t.Errorf("Expected food to be over budget at 3200, got %v", statuses[0].SpentAmount)
+	}
+	if statuses[1].SpentAmount != money.FromMajor(800) || !statuses[1].IsNearLimit {
+		t.Errorf("Expected dining out to be near its limit at 800, got %v", statuses[1].SpentAmount)
+	}
+}
+
+func TestBudgetService_CheckBudgetStatus_Periods(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	// A Wednesday, two days before payday
+	at := time.Date(2026, time.March, 18, 15, 0, 0, 0, time.UTC)
+	monday, payday := date(2026, time.January, 5), date(2026, time.January, 25)
+
+	tests := []struct {
+		name      string
+		budget    models.Budget
+		wantStart time.Time
+		wantEnd   time.Time
+	}{
+		{name: "calendar month", budget: models.Budget{}, wantStart: date(2026, time.March, 1), wantEnd: date(2026, time.April, 1)},
+		{name: "weeks from Monday", budget: models.Budget{Period: models.BudgetPeriodWeekly}, wantStart: date(2026, time.March, 16), wantEnd: date(2026, time.March, 23)},
+		{name: "weeks from anchor", budget: models.Budget{Period: models.BudgetPeriodWeekly, PeriodAnchor: &monday}, wantStart: date(2026, time.March, 16), wantEnd: date(2026, time.March, 23)},
+		{name: "payday to payday", budget: models.Budget{Period: models.BudgetPeriodMonthly, PeriodAnchor: &payday}, wantStart: date(2026, time.February, 25), wantEnd: date(2026, time.March, 25)},
+		{name: "calendar quarter", budget: models.Budget{Period: models.BudgetPeriodQuarterly}, wantStart: date(2026, time.January, 1), wantEnd: date(2026, time.April, 1)},
+		{name: "year from anchor", budget: models.Budget{Period: models.BudgetPeriodYearly, PeriodAnchor: &payday}, wantStart: date(2026, time.January, 25), wantEnd: date(2027, time.January, 25)},
+		{name: "custom fortnight", budget: models.Budget{Period: models.BudgetPeriodCustom, PeriodAnchor: &monday, PeriodDays: 14}, wantStart: date(2026, time.March, 16), wantEnd: date(2026, time.March, 30)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transactions := []*models.Transaction{
+				{ID: uuid.New(), Type: models.TransactionTypeExpense, Amount: money.FromMajor(100), Category: "Food", Status: "Completed", TransactionDate: tt.wantStart},
+				{ID: uuid.New(), Type: models.TransactionTypeExpense, Amount: money.FromMajor(40), Category: "Food", Status: "Completed", TransactionDate: tt.wantStart.Add(-time.Second)},
+				{ID: uuid.New(), Type: models.TransactionTypeExpense, Amount: money.FromMajor(60), Category: "Food", Status: "Completed", TransactionDate: tt.wantEnd},
+			}
+			budget := tt.budget
+			budget.ID, budget.Category, budget.LimitAmount = uuid.New(), "Food", money.FromMajor(500)
+			budgetRepo := &mocks.MockBudgetRepository{
+				FindByUserIDFunc: func(userID uuid.UUID) ([]*models.Budget, error) {
+					return []*models.Budget{&budget}, nil
+				},
+			}
+			transactionRepo := &mocks.MockTransactionRepository{AggregateFunc: aggregateTransactions(transactions)}
+			service := services.NewBudgetService(budgetRepo, &mocks.MockBudgetLimitRepository{}, &mocks.MockBudgetLedgerRepository{}, transactionRepo, &mocks.MockCategoryRepository{}, &mocks.MockUserRepository{}, &mocks.MockWalletRepository{}, &mocks.MockExchangeRateRepository{}, &mocks.MockTxManager{})
+
+			statuses, err := service.CheckBudgetStatus(testutils.TestUserID, at)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			status := statuses[0]
+			if !status.PeriodStart.Equal(tt.wantStart) || !status.PeriodEnd.Equal(tt.wantEnd) {
+				t.Errorf("Expected period %v to %v, got %v to %v", tt.wantStart, tt.wantEnd, status.PeriodStart, status.PeriodEnd)
+			}
+			// Only spending inside the period counts
+			if status.SpentAmount != money.FromMajor(100) {
+				t.Errorf("Expected 100 spent in the period, got %v", status.SpentAmount)
+			}
+		})
+	}
+}
+
+func TestBudgetService_GetBudgetStatus_PastPeriod(t *testing.T) {
+	payday := time.Date(2025, time.December, 25, 0, 0, 0, 0, time.UTC)
+	budget := &models.Budget{
+		ID: uuid.New(), UserID: testutils.TestUserID, Category: "Rent", LimitAmount: money.FromMajor(1000),
+		AlertThreshold: 80, Period: models.BudgetPeriodMonthly, PeriodAnchor: &payday,
+	}
+	transactions := []*models.Transaction{
+		{ID: uuid.New(), Type: models.TransactionTypeExpense, Amount: money.FromMajor(1200), Category: "Rent", Status: "Completed", TransactionDate: time.Date(2026, time.January, 30, 9, 0, 0, 0, time.UTC)},
+		{ID: uuid.New(), Type: models.TransactionTypeExpense, Amount: money.FromMajor(300), Category: "Rent", Status: "Completed", TransactionDate: time.Date(2026, time.February, 26, 9, 0, 0, 0, time.UTC)},
+	}
+	budgetRepo := &mocks.MockBudgetRepository{
+		FindByIDFunc: func(id uuid.UUID) (*models.Budget, error) {
+			return budget, nil
+		},
+	}
+	transactionRepo := &mocks.MockTransactionRepository{AggregateFunc: aggregateTransactions(transactions)}
+	service := services.NewBudgetService(budgetRepo, &mocks.MockBudgetLimitRepository{}, &mocks.MockBudgetLedgerRepository{}, transactionRepo, &mocks.MockCategoryRepository{}, &mocks.MockUserRepository{}, &mocks.MockWalletRepository{}, &mocks.MockExchangeRateRepository{}, &mocks.MockTxManager{})
+
+	status, err := service.GetBudgetStatus(budget.ID, testutils.TestUserID, time.Date(2026, time.February, 10, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !status.PeriodStart.Equal(time.Date(2026, time.January, 25, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the period starting 25 January, got %v", status.PeriodStart)
+	}
+	if status.SpentAmount != money.FromMajor(1200) || !status.IsOverBudget {
+		t.Errorf("Expected the past period to be over budget at 1200, got %v", status.SpentAmount)
+	}
+
+	if _, err := service.GetBudgetStatus(budget.ID, uuid.New(), time.Now()); err == nil {
+		t.Error("Expected another user's budget to be refused")
+	}
+}
+
+func TestBudgetService_CreateBudget_Period(t *testing.T) {
+	anchor := time.Date(2026, time.January, 25, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		req     services.CreateBudgetRequest
+		wantErr bool
+	}{
+		{name: "defaults to monthly", req: services.CreateBudgetRequest{Category: "Food"}},
+		{name: "custom period", req: services.CreateBudgetRequest{Category: "Food", Period: models.BudgetPeriodCustom, PeriodAnchor: &anchor, PeriodDays: 10}},
+		{name: "custom without anchor", req: services.CreateBudgetRequest{Category: "Food", Period: models.BudgetPeriodCustom, PeriodDays: 10}, wantErr: true},
+		{name: "custom without length", req: services.CreateBudgetRequest{Category: "Food", Period: models.BudgetPeriodCustom, PeriodAnchor: &anchor}, wantErr: true},
+		{name: "length on a weekly period", req: services.CreateBudgetRequest{Category: "Food", Period: models.BudgetPeriodWeekly, PeriodDays: 10}, wantErr: true},
+		{name: "envelope", req: services.CreateBudgetRequest{Category: "Food", IsEnvelope: true}},
+		{name: "envelope on a weekly period", req: services.CreateBudgetRequest{Category: "Food", Period: models.BudgetPeriodWeekly, IsEnvelope: true}, wantErr: true},
+		{name: "envelope that rolls over", req: services.CreateBudgetRequest{Category: "Food", IsRollover: true, IsEnvelope: true}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created *models.Budget
+			budgetRepo := &mocks.MockBudgetRepository{
+				CreateFunc: func(budget *models.Budget) error {
+					created = budget
+					return nil
+				},
+			}
+			service := services.NewBudgetService(budgetRepo, &mocks.MockBudgetLimitRepository{}, &mocks.MockBudgetLedgerRepository{}, &mocks.MockTransactionRepository{}, &mocks.MockCategoryRepository{}, &mocks.MockUserRepository{}, &mocks.MockWalletRepository{}, &mocks.MockExchangeRateRepository{}, &mocks.MockTxManager{})
+
+			budget, err := service.CreateBudget(testutils.TestUserID, tt.req)
+			if tt.wantErr {
+				if err == nil || created != nil {
+					t.Errorf("Expected the budget to be rejected, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if tt.req.Period == "" && budget.Period != models.BudgetPeriodMonthly {
+				t.Errorf("Expected a monthly budget, got %q", budget.Period)
+			}
+		})
+	}
+}
+
+func TestBudgetService_CreateBudget_WritesInOneTransaction(t *testing.T) {
+	inTx := false
+	txManager := &mocks.MockTxManager{
+		WithinTransactionFunc: func(fn func(tx *gorm.DB) error) error {
+			inTx = true
+			defer func() { inTx = false }()
+			return fn(nil)
+		},
+	}
+	var outside []string
+	budgetRepo := &mocks.MockBudgetRepository{
+		CreateFunc: func(budget *models.Budget) error {
+			if !inTx {
+				outside = append(outside, "budget")
+			}
+			return nil
+		},
+	}
+	limitRepo := &mocks.MockBudgetLimitRepository{
+		UpsertFunc: func(limit *models.BudgetLimit) error {
+			if !inTx {
+				outside = append(outside, "limit")
+			}
+			return errors.New("connection reset")
+		},
+	}
+	service := services.NewBudgetService(budgetRepo, limitRepo, &mocks.MockBudgetLedgerRepository{}, &mocks.MockTransactionRepository{}, &mocks.MockCategoryRepository{}, &mocks.MockUserRepository{}, &mocks.MockWalletRepository{}, &mocks.MockExchangeRateRepository{}, txManager)
+
+	// A failed limit version fails the whole create so the budget is rolled back
+	if _, err := service.CreateBudget(testutils.TestUserID, services.CreateBudgetRequest{Category: "Food", LimitAmount: money.FromMajor(500), Color: "#00AA00"}); err == nil {
+		t.Error("Expected the create to fail with its limit version")
+	}
+	if len(outside) != 0 {
+		t.Errorf("Expected every write inside the transaction, got %v outside", outside)
+	}
+}
+
+func TestBudgetService_Rollover(t *testing.T) {
+	spend := func(month time.Month, day int, amount int64) *models.Transaction {
+		return &models.Transaction{ID: uuid.New(), Type: models.TransactionTypeExpense, Amount: money.FromMajor(amount), Category: "Food", Status: "Completed", TransactionDate: time.Date(2026, month, day, 12, 0, 0, 0, time.UTC)}
+	}
+	transactions := []*models.Transaction{
+		spend(time.January, 20, 300), // 200 left over
+		spend(time.February, 3, 500),
+		spend(time.February, 27, 300), // 100 over the 700 available
+		spend(time.March, 2, 100),
+		spend(time.April, 9, 250),
+	}
+	budget := &models.Budget{
+		ID: uuid.New(), UserID: testutils.TestUserID, Category: "Food", LimitAmount: money.FromMajor(500),
+		AlertThreshold: 80, IsRollover: true, CreatedAt: time.Date(2026, time.January, 10, 8, 0, 0, 0, time.UTC),
+	}
+
+	january := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	february := time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)
+	var appended []*models.BudgetLedgerEntry
+	ledgerRepo := &mocks.MockBudgetLedgerRepository{
+		FindByBudgetIDFunc: func(budgetID uuid.UUID) ([]*models.BudgetLedgerEntry, error) {
+			// January was corrected once already; February was recorded
+			// before its last transaction was added
+			return []*models.BudgetLedgerEntry{
+				{BudgetID: budgetID, PeriodStart: january, PeriodEnd: february, Revision: 1, BaseLimit: money.FromMajor(500), EffectiveLimit: money.FromMajor(500),
+					SpentAmount: money.FromMajor(250), CarriedOut: money.FromMajor(250)},
+				{BudgetID: budgetID, PeriodStart: january, PeriodEnd: february, Revision: 2, BaseLimit: money.FromMajor(500), EffectiveLimit: money.FromMajor(500),
+					SpentAmount: money.FromMajor(300), CarriedOut: money.FromMajor(200)},
+				{BudgetID: budgetID, PeriodStart: february, PeriodEnd: february.AddDate(0, 1, 0), Revision: 1, BaseLimit: money.FromMajor(500), CarriedIn: money.FromMajor(200),
+					EffectiveLimit: money.FromMajor(700), SpentAmount: money.FromMajor(500), CarriedOut: money.FromMajor(200)},
+			}, nil
+		},
+		AppendFunc: func(entries []*models.BudgetLedgerEntry) error {
+			appended = append(appended, entries...)
+			return nil
+		},
+	}
+	budgetRepo := &mocks.MockBudgetRepository{
+		FindByUserIDFunc: func(userID uuid.UUID) ([]*models.Budget, error) {
+			return []*models.Budget{budget}, nil
+		},
+		FindSnapshotDueFunc: func(now time.Time, limit int) ([]*models.Budget, error) {
+			return []*models.Budget{budget}, nil
+		},
+	}
+	transactionRepo := &mocks.MockTransactionRepository{AggregateFunc: aggregateTransactions(transactions)}
+	service := services.NewBudgetService(budgetRepo, &mocks.MockBudgetLimitRepository{}, ledgerRepo, transactionRepo, &mocks.MockCategoryRepository{}, &mocks.MockUserRepository{}, &mocks.MockWalletRepository{}, &mocks.MockExchangeRateRepository{}, &mocks.MockTxManager{})
+
+	tests := []struct {
+		month         time.Month
+		wantCarriedIn money.Amount
+		wantSpent     money.Amount
+		wantRemaining money.Amount
+	}{
+		{month: time.January, wantCarriedIn: 0, wantSpent: money.FromMajor(300), wantRemaining: money.FromMajor(200)},
+		{month: time.February, wantCarriedIn: money.FromMajor(200), wantSpent: money.FromMajor(800), wantRemaining: -money.FromMajor(100)},
+		{month: time.March, wantCarriedIn: -money.FromMajor(100), wantSpent: money.FromMajor(100), wantRemaining: money.FromMajor(300)},
+		{month: time.April, wantCarriedIn: money.FromMajor(300), wantSpent: money.FromMajor(250), wantRemaining: money.FromMajor(550)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.month.String(), func(t *testing.T) {
+			statuses, err := service.CheckBudgetStatus(testutils.TestUserID, time.Date(2026, tt.month, 15, 0, 0, 0, 0, time.UTC))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			status := statuses[0]
+			if status.BaseLimit != money.FromMajor(500) || status.CarriedIn != tt.wantCarriedIn || status.EffectiveLimit != money.FromMajor(500)+tt.wantCarriedIn {
+				t.Errorf("Expected 500 plus %v carried in, got base %v carried %v effective %v", tt.wantCarriedIn, status.BaseLimit, status.CarriedIn, status.EffectiveLimit)
+			}
+			if status.SpentAmount != tt.wantSpent || status.RemainingAmount != tt.wantRemaining {
+				t.Errorf("Expected %v spent and %v remaining, got %v and %v", tt.wantSpent, tt.wantRemaining, status.SpentAmount, status.RemainingAmount)
+			}
+			if status.IsOverBudget != (tt.wantRemaining < 0) {
+				t.Errorf("Expected over budget to be %v", tt.wantRemaining < 0)
+			}
+		})
+	}
+
+	// Reading statuses never writes to the ledger
+	if len(appended) != 0 {
+		t.Fatalf("Expected status reads to record nothing, got %+v", appended)
+	}
+
+	// Once March closes, February is corrected and March recorded; the
+	// unchanged January is left alone
+	if _, err := service.SnapshotClosedPeriods(time.Date(2026, time.April, 1, 0, 5, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(appended) != 2 {
+		t.Fatalf("Expected 2 ledger entries to be appended, got %+v", appended)
+	}
+	correction := appended[0]
+	if !correction.PeriodStart.Equal(february) || correction.Revision != 2 || correction.SpentAmount != money.FromMajor(800) || correction.CarriedOut != -money.FromMajor(100) {
+		t.Errorf("Expected a second revision of February spending 800 and carrying -100, got %+v", correction)
+	}
+	march := appended[1]
+	if march.PeriodStart.Month() != time.March || march.Revision != 1 || march.CarriedIn != -money.FromMajor(100) || march.CarriedOut != money.FromMajor(300) {
+		t.Errorf("Expected March to carry -100 in and 300 out, got %+v", march)
+	}
+}
+
+func TestBudgetService_Rollover_Disabled(t *testing.T) {
+	transactions := []*models.Transaction{
+		{ID: uuid.New(), Type: models.TransactionTypeExpense, Amount: money.FromMajor(100), Category: "Food", Status: "Completed", TransactionDate: time.Date(2026, time.January, 20, 0, 0, 0, 0, time.UTC)},
+	}
+	budgetRepo := &mocks.MockBudgetRepository{
+		FindByUserIDFunc: func(userID uuid.UUID) ([]*models.Budget, error) {
+			return []*models.Budget{{ID: uuid.New(), Category: "Food", LimitAmount: money.FromMajor(500), CreatedAt: time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)}}, nil
+		},
+	}
+	ledgerRepo := &mocks.MockBudgetLedgerRepository{
+		AppendFunc: func(entries []*models.BudgetLedgerEntry) error {
+			t.Error("Expected nothing to be recorded when reading statuses")
+			return nil
+		},
+	}
+	transactionRepo := &mocks.MockTransactionRepository{AggregateFunc: aggregateTransactions(transactions)}
+	service := services.NewBudgetService(budgetRepo, &mocks.MockBudgetLimitRepository{}, ledgerRepo, transactionRepo, &mocks.MockCategoryRepository{}, &mocks.MockUserRepository{}, &mocks.MockWalletRepository{}, &mocks.MockExchangeRateRepository{}, &mocks.MockTxManager{})
+
+	statuses, err := service.CheckBudgetStatus(testutils.TestUserID, time.Date(2026, time.February, 15, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if statuses[0].CarriedIn != 0 || statuses[0].EffectiveLimit != money.FromMajor(500) {
+		t.Errorf("Expected no carry, got %v carried in", statuses[0].CarriedIn)
+	}
+}
+
+func TestBudgetService_UpdateBudget_VersionsLimit(t *testing.T) {
+	budget := &models.Budget{
+		ID: uuid.New(), UserID: testutils.TestUserID, Category: "Food", LimitAmount: money.FromMajor(500),
+		CreatedAt: time.Date(2025, time.June, 12, 0, 0, 0, 0, time.UTC),
+	}
+	budgetRepo := &mocks.MockBudgetRepository{
+		FindByIDFunc: func(id uuid.UUID) (*models.Budget, error) {
+			return budget, nil
+		},
+	}
+	var versions []*models.BudgetLimit
+	limitRepo := &mocks.MockBudgetLimitRepository{
+		UpsertFunc: func(limit *models.BudgetLimit) error {
+			versions = append(versions, limit)
+			return nil
+		},
+	}
+	service := services.NewBudgetService(budgetRepo, limitRepo, &mocks.MockBudgetLedgerRepository{}, &mocks.MockTransactionRepository{}, &mocks.MockCategoryRepository{}, &mocks.MockUserRepository{}, &mocks.MockWalletRepository{}, &mocks.MockExchangeRateRepository{}, &mocks.MockTxManager{})
+
+	if _, err := service.UpdateBudget(budget.ID, testutils.TestUserID, services.UpdateBudgetRequest{LimitAmount: money.FromMajor(800)}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The budget had no versions yet, so its old limit is kept for past periods
+	if len(versions) != 2 {
+		t.Fatalf("Expected 2 limit versions, got %d", len(versions))
+	}
+	if versions[0].LimitAmount != money.FromMajor(500) || !versions[0].EffectiveFrom.Equal(time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the old limit from the creation month, got %+v", versions[0])
+	}
+	now := time.Now().UTC()
+	if versions[1].LimitAmount != money.FromMajor(800) || !versions[1].EffectiveFrom.Equal(time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the new limit from this month, got %+v", versions[1])
+	}
+}
+
+// historyFixture is a monthly Food budget with a limit raised from 500 to 800
+// in March and spending in each month from January to April
+func historyFixture(ledger []*models.BudgetLedgerEntry) (services.BudgetService, *models.Budget) {
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(2026, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	budget := &models.Budget{
+		ID: uuid.New(), UserID: testutils.TestUserID, Category: "Food", LimitAmount: money.FromMajor(800),
+		AlertThreshold: 80, CreatedAt: date(time.January, 5),
+	}
+	transactions := []*models.Transaction{
+		{ID: uuid.New(), Type: models.TransactionTypeExpense, Amount: money.FromMajor(450), Category: "Food", Status: "Completed", TransactionDate: date(time.January, 10)},
+		{ID: uuid.New(), Type: models.TransactionTypeExpense, Amount: money.FromMajor(600), Category: "Food", Status: "Completed", TransactionDate: date(time.February, 10)},
+		{ID: uuid.New(), Type: models.TransactionTypeExpense, Amount: money.FromMajor(200), Category: "Food", Status: "Completed", TransactionDate: date(time.March, 10)},
+		{ID: uuid.New(), Type: models.TransactionTypeExpense, Amount: money.FromMajor(1000), Category: "Food", Status: "Completed", TransactionDate: date(time.April, 10)},
+	}
+
+	budgetRepo := &mocks.MockBudgetRepository{
+		FindByUserIDFunc: func(userID uuid.UUID) ([]*models.Budget, error) {
+			return []*models.Budget{budget}, nil
+		},
+	}
+	limitRepo := &mocks.MockBudgetLimitRepository{
+		FindByBudgetIDsFunc: func(budgetIDs []uuid.UUID) ([]*models.BudgetLimit, error) {
+			return []*models.BudgetLimit{
+				{BudgetID: budget.ID, EffectiveFrom: date(time.January, 1), LimitAmount: money.FromMajor(500)},
+				{BudgetID: budget.ID, EffectiveFrom: date(time.March, 1), LimitAmount: money.FromMajor(800)},
+			}, nil
+		},
+	}
+	ledgerRepo := &mocks.MockBudgetLedgerRepository{
+		FindByBudgetIDFunc: func(budgetID uuid.UUID) ([]*models.BudgetLedgerEntry, error) {
+			return ledger, nil
+		},
+	}
+	transactionRepo := &mocks.MockTransactionRepository{AggregateFunc: aggregateTransactions(transactions)}
+	return services.NewBudgetService(budgetRepo, limitRepo, ledgerRepo, transactionRepo, &mocks.MockCategoryRepository{}, &mocks.MockUserRepository{}, &mocks.MockWalletRepository{}, &mocks.MockExchangeRateRepository{}, &mocks.MockTxManager{}), budget
+}
+
+func TestBudgetService_CheckBudgetStatus_VersionedLimit(t *testing.T) {
+	service, _ := historyFixture(nil)
+
+	february, err := service.CheckBudgetStatus(testutils.TestUserID, time.Date(2026, time.February, 20, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if february[0].BaseLimit != money.FromMajor(500) || !february[0].IsOverBudget {
+		t.Errorf("Expected February to keep its 500 limit and be over budget, got %v", february[0].BaseLimit)
+	}
+
+	april, err := service.CheckBudgetStatus(testutils.TestUserID, time.Date(2026, time.April, 20, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if april[0].BaseLimit != money.FromMajor(800) {
+		t.Errorf("Expected April to use the raised limit, got %v", april[0].BaseLimit)
+	}
+}
+
+func TestBudgetService_GetBudgetHistory(t *testing.T) {
+	// February was snapshotted before a late transaction was added
+	service, budget := historyFixture([]*models.BudgetLedgerEntry{{
+		PeriodStart: time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:   time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC),
+		BaseLimit:   money.FromMajor(500), EffectiveLimit: money.FromMajor(500), SpentAmount: money.FromMajor(550),
+	}})
+
+	history, err := service.GetBudgetHistory(testutils.TestUserID, 6, time.Date(2026, time.April, 20, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(history) != 1 || history[0].BudgetID != budget.ID {
+		t.Fatalf("Expected the history of one budget, got %+v", history)
+	}
+
+	// Nothing is reported before the budget was created in January
+	periods := history[0].Periods
+	if len(periods) != 4 {
+		t.Fatalf("Expected 4 periods, got %d", len(periods))
+	}
+
+	tests := []struct {
+		limit, actual, variance money.Amount
+		percentage              float64
+	}{
+		{limit: money.FromMajor(500), actual: money.FromMajor(450), variance: money.FromMajor(50), percentage: 90},
+		{limit: money.FromMajor(500), actual: money.FromMajor(550), variance: -money.FromMajor(50), percentage: 110},
+		{limit: money.FromMajor(800), actual: money.FromMajor(200), variance: money.FromMajor(600), percentage: 25},
+		{limit: money.FromMajor(800), actual: money.FromMajor(1000), variance: -money.FromMajor(200), percentage: 125},
+	}
+	for i, tt := range tests {
+		period := periods[i]
+		if period.LimitAmount != tt.limit || period.ActualAmount != tt.actual || period.Variance != tt.variance || math.Abs(period.PercentageUsed-tt.percentage) > 0.001 {
+			t.Errorf("Period %d: expected limit %v actual %v variance %v (%v%%), got %v %v %v (%v%%)",
+				i, tt.limit, tt.actual, tt.variance, tt.percentage, period.LimitAmount, period.ActualAmount, period.Variance, period.PercentageUsed)
+		}
+		if period.IsOverBudget != (tt.variance < 0) {
+			t.Errorf("Period %d: expected over budget to be %v", i, tt.variance < 0)
+		}
+	}
+
+	limited, err := service.GetBudgetHistory(testutils.TestUserID, 2, time.Date(2026, time.April, 20, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := limited[0].Periods; len(got) != 2 || got[0].PeriodStart.Month() != time.March {
+		t.Errorf("Expected March and April only, got %d periods", len(got))
+	}
+}
+
+func TestBudgetService_GetBudgetHistory_Rollover(t *testing.T) {
+	service, budget := historyFixture(nil)
+	budget.IsRollover = true
+
+	history, err := service.GetBudgetHistory(testutils.TestUserID, 6, time.Date(2026, time.April, 20, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Each period carries in what the one before left over or overspent
+	expected := []money.Amount{0, money.FromMajor(50), -money.FromMajor(50), money.FromMajor(550)}
+	periods := history[0].Periods
+	if len(periods) != len(expected) {
+		t.Fatalf("Expected %d periods, got %d", len(expected), len(periods))
+	}
+	for i, carriedIn := range expected {
+		if periods[i].CarriedIn != carriedIn || periods[i].EffectiveLimit != periods[i].LimitAmount+carriedIn {
+			t.Errorf("Period %d: expected %v carried in, got %v (effective limit %v)", i, carriedIn, periods[i].CarriedIn, periods[i].EffectiveLimit)
+		}
+	}
+}
+
+func TestBudgetService_GetBudgetHistory_PeriodChanged(t *testing.T) {
+	// The budget was weekly when its first week of February was recorded
+	service, _ := historyFixture([]*models.BudgetLedgerEntry{{
+		PeriodStart: time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:   time.Date(2026, time.February, 8, 0, 0, 0, 0, time.UTC),
+		BaseLimit:   money.FromMajor(120), EffectiveLimit: money.FromMajor(120), SpentAmount: money.FromMajor(90),
+	}})
+
+	history, err := service.GetBudgetHistory(testutils.TestUserID, 6, time.Date(2026, time.April, 20, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	february := history[0].Periods[1]
+	if february.LimitAmount != money.FromMajor(500) || february.ActualAmount != money.FromMajor(600) {
+		t.Errorf("Expected February to be worked out as a month, got limit %v actual %v", february.LimitAmount, february.ActualAmount)
+	}
+}
+
+func TestBudgetService_SnapshotClosedPeriods(t *testing.T) {
+	now := time.Date(2026, time.May, 1, 0, 5, 0, 0, time.UTC)
+	april := time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC)
+	recorded := uuid.New()
+	// Both were last snapshotted in April, so April is due
+	due := time.Date(2026, time.May, 1, 0, 0, 0, 0, time.UTC)
+	budgets := []*models.Budget{
+		{ID: uuid.New(), UserID: testutils.TestUserID, Category: "Food", LimitAmount: money.FromMajor(500), CreatedAt: time.Date(2026, time.January, 5, 0, 0, 0, 0, time.UTC), NextSnapshotAt: &due},
+		{ID: recorded, UserID: testutils.TestUserID, Category: "Rent", LimitAmount: money.FromMajor(900), CreatedAt: time.Date(2026, time.January, 5, 0, 0, 0, 0, time.UTC), NextSnapshotAt: &due},
+		{ID: uuid.New(), UserID: testutils.TestUserID, Category: "Travel", LimitAmount: money.FromMajor(300), CreatedAt: time.Date(2026, time.May, 1, 0, 1, 0, 0, time.UTC)},
+	}
+	transactions := []*models.Transaction{
+		{ID: uuid.New(), Type: models.TransactionTypeExpense, Amount: money.FromMajor(320), Category: "Food", Status: "Completed", TransactionDate: time.Date(2026, time.April, 30, 23, 0, 0, 0, time.UTC)},
+		{ID: uuid.New(), Type: models.TransactionTypeExpense, Amount: money.FromMajor(80), Category: "Food", Status: "Completed", TransactionDate: time.Date(2026, time.May, 1, 0, 1, 0, 0, time.UTC)},
+	}
+
+	budgetRepo := &mocks.MockBudgetRepository{
+		FindSnapshotDueFunc: func(now time.Time, limit int) ([]*models.Budget, error) {
+			return budgets, nil
+		},
+	}
+	next := make(map[uuid.UUID]time.Time)
+	budgetRepo.SetNextSnapshotFunc = func(id uuid.UUID, date *time.Time) error {
+		next[id] = *date
+		return nil
+	}
+	var snapshots []*models.BudgetLedgerEntry
+	ledgerRepo := &mocks.MockBudgetLedgerRepository{
+		FindByBudgetIDFunc: func(budgetID uuid.UUID) ([]*models.BudgetLedgerEntry, error) {
+			if budgetID == recorded {
+				return []*models.BudgetLedgerEntry{{BudgetID: budgetID, PeriodStart: april, PeriodEnd: april.AddDate(0, 1, 0), Revision: 1,
+					BaseLimit: money.FromMajor(900), EffectiveLimit: money.FromMajor(900)}}, nil
+			}
+			return nil, nil
+		},
+		AppendFunc: func(entries []*models.BudgetLedgerEntry) error {
+			snapshots = append(snapshots, entries...)
+			return nil
+		},
+	}
+	transactionRepo := &mocks.MockTransactionRepository{AggregateFunc: aggregateTransactions(transactions)}
+	service := services.NewBudgetService(budgetRepo, &mocks.MockBudgetLimitRepository{}, ledgerRepo, transactionRepo, &mocks.MockCategoryRepository{}, &mocks.MockUserRepository{}, &mocks.MockWalletRepository{}, &mocks.MockExchangeRateRepository{}, &mocks.MockTxManager{})
+
+	snapshotted, err := service.SnapshotClosedPeriods(now)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if snapshotted != 1 {
+		t.Errorf("Expected 1 period to be snapshotted, got %d", snapshotted)
+	}
+
+	// Only April of the food budget was missing; the travel budget has no closed period yet
+	if len(snapshots) != 1 {
+		t.Fatalf("Expected 1 snapshot, got %d", len(snapshots))
+	}
+	snapshot := snapshots[0]
+	if snapshot.BudgetID != budgets[0].ID || !snapshot.PeriodStart.Equal(april) || snapshot.SpentAmount != money.FromMajor(320) || snapshot.BaseLimit != money.FromMajor(500) {
+		t.Errorf("Expected April's food spending of 320 against 500, got %+v", snapshot)
+	}
+
+	june := time.Date(2026, time.June, 1, 0, 0, 0, 0, time.UTC)
+	for _, budget := range budgets {
+		if !next[budget.ID].Equal(june) {
+			t.Errorf("Expected %s to be snapshotted next on 1 June, got %v", budget.Category, next[budget.ID])
+		}
+	}
+}
+
+func TestBudgetService_SnapshotClosedPeriods_CatchesUp(t *testing.T) {
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(2026, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	// The job last ran in January and was down until May
+	due := date(time.February, 1)
+	stale := &models.Budget{ID: uuid.New(), UserID: testutils.TestUserID, Category: "Food", LimitAmount: money.FromMajor(500), CreatedAt: date(time.January, 5), NextSnapshotAt: &due}
+	// A budget never snapshotted starts from the period it was created in
+	fresh := &models.Budget{ID: uuid.New(), UserID: testutils.TestUserID, Category: "Rent", LimitAmount: money.FromMajor(900), CreatedAt: date(time.March, 20)}
+	transactions := []*models.Transaction{
+		{ID: uuid.New(), Type: models.TransactionTypeExpense, Amount: money.FromMajor(100), Category: "Food", Status: "Completed", TransactionDate: date(time.February, 10)},
+		{ID: uuid.New(), Type: models.TransactionTypeExpense, Amount: money.FromMajor(200), Category: "Food", Status: "Completed", TransactionDate: date(time.April, 10)},
+	}
+
+	budgetRepo := &mocks.MockBudgetRepository{
+		FindSnapshotDueFunc: func(now time.Time, limit int) ([]*models.Budget, error) {
+			return []*models.Budget{stale, fresh}, nil
+		},
+	}
+	recorded := make(map[uuid.UUID][]*models.BudgetLedgerEntry)
+	ledgerRepo := &mocks.MockBudgetLedgerRepository{
+		FindByBudgetIDFunc: func(budgetID uuid.UUID) ([]*models.BudgetLedgerEntry, error) {
+			return recorded[budgetID], nil
+		},
+		AppendFunc: func(entries []*models.BudgetLedgerEntry) error {
+			for _, entry := range entries {
+				recorded[entry.BudgetID] = append(recorded[entry.BudgetID], entry)
+			}
+			return nil
+		},
+	}
+	transactionRepo := &mocks.MockTransactionRepository{AggregateFunc: aggregateTransactions(transactions)}
+	service := services.NewBudgetService(budgetRepo, &mocks.MockBudgetLimitRepository{}, ledgerRepo, transactionRepo, &mocks.MockCategoryRepository{}, &mocks.MockUserRepository{}, &mocks.MockWalletRepository{}, &mocks.MockExchangeRateRepository{}, &mocks.MockTxManager{})
+
+	snapshotted, err := service.SnapshotClosedPeriods(date(time.May, 2))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if snapshotted != 6 {
+		t.Errorf("Expected 6 periods to be snapshotted, got %d", snapshotted)
+	}
+
+	food := recorded[stale.ID]
+	if len(food) != 4 || !food[0].PeriodStart.Equal(date(time.January, 1)) || !food[3].PeriodStart.Equal(date(time.April, 1)) {
+		t.Fatalf("Expected January to April for food, got %+v", food)
+	}
+	if food[1].SpentAmount != money.FromMajor(100) || food[2].SpentAmount != 0 || food[3].SpentAmount != money.FromMajor(200) {
+		t.Errorf("Expected each month's own spending, got %v, %v and %v", food[1].SpentAmount, food[2].SpentAmount, food[3].SpentAmount)
+	}
+	if rent := recorded[fresh.ID]; len(rent) != 2 || !rent[0].PeriodStart.Equal(date(time.March, 1)) {
+		t.Errorf("Expected March and April for rent, got %+v", rent)
+	}
+}
+
+func TestBudgetService_SnapshotClosedPeriods_Failure(t *testing.T) {
+	now := time.Date(2026, time.May, 1, 0, 5, 0, 0, time.UTC)
+	due := time.Date(2026, time.May, 1, 0, 0, 0, 0, time.UTC)
+	created := time.Date(2026, time.January, 5, 0, 0, 0, 0, time.UTC)
+	failing := &models.Budget{ID: uuid.New(), UserID: testutils.TestUserID, Category: "Food", LimitAmount: money.FromMajor(500), CreatedAt: created, NextSnapshotAt: &due}
+	failedBefore := &models.Budget{ID: uuid.New(), UserID: testutils.TestUserID, Category: "Rent", LimitAmount: money.FromMajor(900), CreatedAt: created, NextSnapshotAt: &due, SnapshotFailures: 3}
+	working := &models.Budget{ID: uuid.New(), UserID: testutils.TestUserID, Category: "Travel", LimitAmount: money.FromMajor(300), CreatedAt: created, NextSnapshotAt: &due}
+
+	budgetRepo := &mocks.MockBudgetRepository{
+		FindSnapshotDueFunc: func(now time.Time, limit int) ([]*models.Budget, error) {
+			return []*models.Budget{failing, failedBefore, working}, nil
+		},
+	}
+	next := make(map[uuid.UUID]time.Time)
+	budgetRepo.SetNextSnapshotFunc = func(id uuid.UUID, date *time.Time) error {
+		next[id] = *date
+		return nil
+	}
+	type retry struct {
+		failures int
+		at       time.Time
+	}
+	retries := make(map[uuid.UUID]retry)
+	budgetRepo.DeferSnapshotFunc = func(id uuid.UUID, failures int, retryAt time.Time) error {
+		retries[id] = retry{failures: failures, at: retryAt}
+		return nil
+	}
+	ledgerRepo := &mocks.MockBudgetLedgerRepository{
+		AppendFunc: func(entries []*models.BudgetLedgerEntry) error {
+			if entries[0].BudgetID != working.ID {
+				return errors.New("database unavailable")
+			}
+			return nil
+		},
+	}
+	service := services.NewBudgetService(budgetRepo, &mocks.MockBudgetLimitRepository{}, ledgerRepo, &mocks.MockTransactionRepository{}, &mocks.MockCategoryRepository{}, &mocks.MockUserRepository{}, &mocks.MockWalletRepository{}, &mocks.MockExchangeRateRepository{}, &mocks.MockTxManager{})
+
+	snapshotted, err := service.SnapshotClosedPeriods(now)
+	if err == nil {
+		t.Error("Expected the failed snapshots to be reported")
+	}
+	if snapshotted != 1 || !next[working.ID].Equal(time.Date(2026, time.June, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected April of the working budget to be snapshotted regardless, got %d periods", snapshotted)
+	}
+
+	// The failed budgets wait longer with each failure and stay due for April
+	if got := retries[failing.ID]; got.failures != 1 || !got.at.Equal(now.Add(15*time.Minute)) {
+		t.Errorf("Expected the first failure to be retried in 15 minutes, got %+v", got)
+	}
+	if got := retries[failedBefore.ID]; got.failures != 4 || !got.at.Equal(now.Add(2*time.Hour)) {
+		t.Errorf("Expected the fourth failure in a row to be retried in 2 hours, got %+v", got)
+	}
+	if _, ok := next[failing.ID]; ok {
+		t.Error("Expected a failed budget's next snapshot to be left as it was")
+	}
+	if _, ok := retries[working.ID]; ok {
+		t.Error("Expected the working budget not to be put off")
+	}
+}
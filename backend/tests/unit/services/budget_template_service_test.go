@@ -0,0 +1,357 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+	"github.com/nyunja/fity-budget-backend/tests/unit/mocks"
+	"github.com/nyunja/fity-budget-backend/tests/unit/testutils"
+	"gorm.io/gorm"
+)
+
+// budgetTemplateFixture is a user with a monthly income of 1000 who already
+// budgets 250 for Rent and 400 for Food, and has a green Transport category.
+// Writes made outside a transaction are listed in outside.
+type budgetTemplateFixture struct {
+	user      *models.User
+	rent      *models.Budget
+	food      *models.Budget
+	templates []*models.BudgetTemplate
+	created   []services.CreateBudgetRequest
+	updated   map[uuid.UUID]money.Amount
+	incomes   []money.Amount
+	inTx      bool
+	outside   []string
+	budgets   *mocks.MockBudgetService
+	service   services.BudgetTemplateService
+}
+
+func newBudgetTemplateFixture() *budgetTemplateFixture {
+	f := &budgetTemplateFixture{
+		user:    &models.User{ID: testutils.TestUserID, MonthlyIncome: money.FromMajor(1000), Currency: "KES"},
+		rent:    &models.Budget{ID: uuid.New(), UserID: testutils.TestUserID, Category: "Rent", LimitAmount: money.FromMajor(250)},
+		food:    &models.Budget{ID: uuid.New(), UserID: testutils.TestUserID, Category: "Food", LimitAmount: money.FromMajor(400)},
+		updated: make(map[uuid.UUID]money.Amount),
+	}
+
+	templateRepo := &mocks.MockBudgetTemplateRepository{
+		CreateFunc: func(template *models.BudgetTemplate) error {
+			template.ID = uuid.New()
+			f.templates = append(f.templates, template)
+			return nil
+		},
+		FindByIDFunc: func(id uuid.UUID) (*models.BudgetTemplate, error) {
+			for _, template := range f.templates {
+				if template.ID == id {
+					return template, nil
+				}
+			}
+			return nil, errors.New("record not found")
+		},
+		FindByUserIDAndNameFunc: func(userID uuid.UUID, name string) (*models.BudgetTemplate, error) {
+			for _, template := range f.templates {
+				if template.Name == name {
+					return template, nil
+				}
+			}
+			return nil, errors.New("record not found")
+		},
+	}
+	budgetRepo := &mocks.MockBudgetRepository{
+		FindByUserIDFunc: func(userID uuid.UUID) ([]*models.Budget, error) {
+			return []*models.Budget{f.rent, f.food}, nil
+		},
+	}
+	userRepo := &mocks.MockUserRepository{
+		FindByIDFunc: func(id uuid.UUID) (*models.User, error) {
+			return f.user, nil
+		},
+		UpdateMonthlyIncomeFunc: func(id uuid.UUID, income money.Amount) error {
+			f.wrote("income")
+			f.incomes = append(f.incomes, income)
+			return nil
+		},
+	}
+	categoryRepo := &mocks.MockCategoryRepository{
+		FindByUserIDFunc: func(userID uuid.UUID) ([]*models.Category, error) {
+			return []*models.Category{{ID: uuid.New(), UserID: userID, Name: "Transport", Color: "#10B981"}}, nil
+		},
+	}
+	f.budgets = &mocks.MockBudgetService{
+		CreateBudgetFunc: func(userID uuid.UUID, req services.CreateBudgetRequest) (*models.Budget, error) {
+			f.wrote("create")
+			f.created = append(f.created, req)
+			return &models.Budget{ID: uuid.New(), UserID: userID, Category: req.Category, LimitAmount: req.LimitAmount}, nil
+		},
+		UpdateBudgetFunc: func(id, userID uuid.UUID, req services.UpdateBudgetRequest) (*models.Budget, error) {
+			f.wrote("update")
+			f.updated[id] = req.LimitAmount
+			return &models.Budget{ID: id, UserID: userID, LimitAmount: req.LimitAmount}, nil
+		},
+	}
+
+	txManager := &mocks.MockTxManager{
+		WithinTransactionFunc: func(fn func(tx *gorm.DB) error) error {
+			f.inTx = true
+			defer func() { f.inTx = false }()
+			return fn(nil)
+		},
+	}
+
+	f.service = services.NewBudgetTemplateService(templateRepo, budgetRepo, userRepo, categoryRepo, f.budgets, txManager)
+	return f
+}
+
+func (f *budgetTemplateFixture) wrote(what string) {
+	if !f.inTx {
+		f.outside = append(f.outside, what)
+	}
+}
+
+func plannedFor(plan *services.BudgetPlan, category string) *services.PlannedBudget {
+	for _, planned := range plan.Budgets {
+		if planned.Category == category {
+			return planned
+		}
+	}
+	return nil
+}
+
+func TestBudgetTemplateService_PreviewBudgets(t *testing.T) {
+	f := newBudgetTemplateFixture()
+
+	plan, err := f.service.PreviewBudgets(testutils.TestUserID, services.GenerateBudgetsRequest{
+		Template: "50-30-20",
+		Groups:   map[string][]string{"needs": {"rent", "Food"}, "wants": {"Transport", "Fun", "Dining"}},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if plan.Income != money.FromMajor(1000) || plan.Template != "50/30/20" {
+		t.Errorf("Expected the 50/30/20 template on the monthly income of 1000, got %s on %v", plan.Template, plan.Income)
+	}
+	if len(plan.Budgets) != 6 {
+		t.Fatalf("Expected 6 budgets, got %d", len(plan.Budgets))
+	}
+
+	rent := plannedFor(plan, "Rent")
+	if rent == nil || rent.Action != services.BudgetPlanUnchanged || rent.LimitAmount != money.FromMajor(250) || rent.BudgetID == nil {
+		t.Errorf("Expected Rent to match the existing budget and stay at 250, got %+v", rent)
+	}
+	food := plannedFor(plan, "Food")
+	if food.Action != services.BudgetPlanUpdate || food.CurrentLimit != money.FromMajor(400) || food.LimitAmount != money.FromMajor(250) {
+		t.Errorf("Expected Food to drop from 400 to 250, got %+v", food)
+	}
+	savings := plannedFor(plan, "Savings")
+	if savings == nil || savings.Action != services.BudgetPlanCreate || savings.LimitAmount != money.FromMajor(200) || savings.Percentage != 20 {
+		t.Errorf("Expected an empty savings group to get a Savings budget of 200, got %+v", savings)
+	}
+	// 300 for wants splits into 100 each
+	if fun := plannedFor(plan, "Fun"); fun.LimitAmount != money.FromMajor(100) || fun.Group != "Wants" {
+		t.Errorf("Expected Fun to get 100 of the wants, got %+v", fun)
+	}
+	if plan.Total != money.FromMajor(1000) || plan.Unallocated != 0 {
+		t.Errorf("Expected the whole income to be allocated, got %v and %v unallocated", plan.Total, plan.Unallocated)
+	}
+	if len(f.created) != 0 || len(f.updated) != 0 {
+		t.Error("Expected a preview to change nothing")
+	}
+}
+
+func TestBudgetTemplateService_PreviewBudgetsErrors(t *testing.T) {
+	missingID := uuid.New()
+
+	tests := []struct {
+		name string
+		req  services.GenerateBudgetsRequest
+	}{
+		{name: "no template", req: services.GenerateBudgetsRequest{}},
+		{name: "unknown template", req: services.GenerateBudgetsRequest{Template: "60-40"}},
+		{name: "unknown group", req: services.GenerateBudgetsRequest{Template: "50-30-20", Groups: map[string][]string{"giving": {"Charity"}}}},
+		{name: "category in two groups", req: services.GenerateBudgetsRequest{Template: "50-30-20", Groups: map[string][]string{"needs": {"Food"}, "wants": {"food"}}}},
+		{name: "saved template not found", req: services.GenerateBudgetsRequest{TemplateID: &missingID}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newBudgetTemplateFixture()
+
+			if _, err := f.service.PreviewBudgets(testutils.TestUserID, tt.req); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+
+	t.Run("no income", func(t *testing.T) {
+		f := newBudgetTemplateFixture()
+		f.user.MonthlyIncome = 0
+
+		if _, err := f.service.PreviewBudgets(testutils.TestUserID, services.GenerateBudgetsRequest{Template: "70-20-10"}); err == nil {
+			t.Error("Expected an error without an income")
+		}
+	})
+}
+
+func TestBudgetTemplateService_GenerateBudgets(t *testing.T) {
+	f := newBudgetTemplateFixture()
+	template, err := f.service.CreateTemplate(testutils.TestUserID, services.CreateBudgetTemplateRequest{
+		Name: "Lean",
+		Lines: []services.BudgetTemplateLineRequest{
+			{Category: "Rent", Percentage: 12.5},
+			{Category: "Transport", Percentage: 10},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	plan, err := f.service.GenerateBudgets(testutils.TestUserID, services.GenerateBudgetsRequest{TemplateID: &template.ID, Income: money.FromMajor(2000)})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// 12.5% of 2000 keeps Rent at 250, and Food is not in the template
+	if len(f.updated) != 0 {
+		t.Errorf("Expected no budget to be updated, got %v", f.updated)
+	}
+	if len(f.created) != 1 || f.created[0].Category != "Transport" || f.created[0].LimitAmount != money.FromMajor(200) || f.created[0].Color != "#10B981" {
+		t.Errorf("Expected a Transport budget of 200 in the category color, got %+v", f.created)
+	}
+	if transport := plannedFor(plan, "Transport"); transport.BudgetID == nil {
+		t.Error("Expected the created budget's ID on the plan")
+	}
+	if plan.Unallocated != money.FromMajor(1550) {
+		t.Errorf("Expected 1550 unallocated, got %v", plan.Unallocated)
+	}
+	if f.user.MonthlyIncome != money.FromMajor(2000) {
+		t.Errorf("Expected the income to be recorded, got %v", f.user.MonthlyIncome)
+	}
+}
+
+func TestBudgetTemplateService_CreateTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     services.CreateBudgetTemplateRequest
+		wantErr bool
+	}{
+		{
+			name: "valid template",
+			req: services.CreateBudgetTemplateRequest{Name: " Family ", Lines: []services.BudgetTemplateLineRequest{
+				{Category: "Rent", Percentage: 33.33}, {Category: "School", Percentage: 33.33}, {Category: "Food", Percentage: 33.34},
+			}},
+		},
+		{
+			name: "more than 100 percent",
+			req: services.CreateBudgetTemplateRequest{Name: "Greedy", Lines: []services.BudgetTemplateLineRequest{
+				{Category: "Rent", Percentage: 60}, {Category: "Food", Percentage: 40.01},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "duplicate category",
+			req: services.CreateBudgetTemplateRequest{Name: "Twice", Lines: []services.BudgetTemplateLineRequest{
+				{Category: "Rent", Percentage: 10}, {Category: "rent", Percentage: 10},
+			}},
+			wantErr: true,
+		},
+		{name: "no lines", req: services.CreateBudgetTemplateRequest{Name: "Empty"}, wantErr: true},
+		{name: "no name", req: services.CreateBudgetTemplateRequest{Name: " ", Lines: []services.BudgetTemplateLineRequest{{Category: "Rent", Percentage: 10}}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newBudgetTemplateFixture()
+
+			template, err := f.service.CreateTemplate(testutils.TestUserID, tt.req)
+			if tt.wantErr {
+				if err == nil || len(f.templates) != 0 {
+					t.Errorf("Expected the template to be rejected, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if template.Name != "Family" || len(template.Lines) != 3 || template.UserID != testutils.TestUserID {
+				t.Errorf("Unexpected template %+v", template)
+			}
+
+			if _, err := f.service.CreateTemplate(testutils.TestUserID, tt.req); err == nil {
+				t.Error("Expected a second template with the same name to be rejected")
+			}
+		})
+	}
+}
+
+func TestBudgetTemplateService_RescaleBudgets(t *testing.T) {
+	t.Run("scales every limit", func(t *testing.T) {
+		f := newBudgetTemplateFixture()
+
+		plan, err := f.service.RescaleBudgets(testutils.TestUserID, money.FromMajor(1500))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if f.updated[f.food.ID] != money.FromMajor(600) || f.updated[f.rent.ID] != money.FromMajor(375) {
+			t.Errorf("Expected Food to go to 600 and Rent to 375, got %v", f.updated)
+		}
+		if food := plannedFor(plan, "Food"); food.CurrentLimit != money.FromMajor(400) || food.Percentage != 40 {
+			t.Errorf("Expected Food to keep 40%% of income, got %+v", food)
+		}
+		if len(f.created) != 0 || f.user.MonthlyIncome != money.FromMajor(1500) {
+			t.Errorf("Expected no new budgets and the income recorded, got %d and %v", len(f.created), f.user.MonthlyIncome)
+		}
+	})
+
+	t.Run("applies in one transaction", func(t *testing.T) {
+		f := newBudgetTemplateFixture()
+		f.budgets.UpdateBudgetFunc = func(id, userID uuid.UUID, req services.UpdateBudgetRequest) (*models.Budget, error) {
+			f.wrote("update")
+			if id == f.food.ID {
+				return nil, errors.New("connection reset")
+			}
+			f.updated[id] = req.LimitAmount
+			return &models.Budget{ID: id, UserID: userID, LimitAmount: req.LimitAmount}, nil
+		}
+
+		// A failing budget fails the whole plan so the earlier updates roll back
+		if _, err := f.service.RescaleBudgets(testutils.TestUserID, money.FromMajor(1500)); err == nil {
+			t.Fatal("Expected the rescale to fail with its Food budget")
+		}
+		if len(f.outside) != 0 {
+			t.Errorf("Expected every write inside the transaction, got %v outside", f.outside)
+		}
+		if len(f.incomes) != 0 {
+			t.Errorf("Expected the income not to be recorded, got %v", f.incomes)
+		}
+	})
+
+	t.Run("preview changes nothing", func(t *testing.T) {
+		f := newBudgetTemplateFixture()
+
+		plan, err := f.service.PreviewRescale(testutils.TestUserID, money.FromMajor(500))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if rent := plannedFor(plan, "Rent"); rent.LimitAmount != money.FromMajor(125) || rent.Action != services.BudgetPlanUpdate {
+			t.Errorf("Expected Rent to halve to 125, got %+v", rent)
+		}
+		if len(f.updated) != 0 || f.user.MonthlyIncome != money.FromMajor(1000) {
+			t.Error("Expected a preview to change nothing")
+		}
+	})
+
+	t.Run("no income on record", func(t *testing.T) {
+		f := newBudgetTemplateFixture()
+		f.user.MonthlyIncome = 0
+
+		if _, err := f.service.RescaleBudgets(testutils.TestUserID, money.FromMajor(1500)); err == nil || len(f.updated) != 0 {
+			t.Errorf("Expected the rescale to be rejected, got %v", err)
+		}
+	})
+}
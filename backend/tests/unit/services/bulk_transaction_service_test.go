@@ -0,0 +1,315 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+	"github.com/nyunja/fity-budget-backend/tests/unit/mocks"
+	"github.com/nyunja/fity-budget-backend/tests/unit/testutils"
+)
+
+// bulkFixture keeps transactions, tags and wallet balances in memory
+type bulkFixture struct {
+	store           *testutils.TransactionStore
+	stored          map[uuid.UUID]*models.Transaction
+	tags            map[uuid.UUID]*models.Tag
+	balances        map[uuid.UUID]money.Amount
+	transactionRepo *mocks.MockTransactionRepository
+	service         services.BulkTransactionService
+}
+
+func newBulkFixture() *bulkFixture {
+	transactions := testutils.NewTransactionStore()
+	wallets := testutils.NewWalletStore(
+		&models.Wallet{ID: testutils.TestWalletID, UserID: testutils.TestUserID, Currency: "KES"},
+		&models.Wallet{ID: secondWalletID, UserID: testutils.TestUserID, Currency: "KES"},
+		&models.Wallet{ID: foreignWalletID, UserID: uuid.New(), Currency: "KES"},
+	)
+	f := &bulkFixture{
+		store:           transactions,
+		stored:          transactions.Stored,
+		tags:            make(map[uuid.UUID]*models.Tag),
+		balances:        wallets.Balances,
+		transactionRepo: transactions.Repo(),
+	}
+
+	f.service = services.NewBulkTransactionService(f.transactionRepo, wallets.Repo(), newTagRepo(f.tags), &mocks.MockTxManager{})
+	return f
+}
+
+// seed stores an existing transaction in the test wallet, unless it names
+// another, without touching balances
+func (f *bulkFixture) seed(transaction models.Transaction) uuid.UUID {
+	if transaction.WalletID == nil {
+		transaction.WalletID = walletPtr(testutils.TestWalletID)
+	}
+	return f.store.Seed(transaction)
+}
+
+func outcomeStatuses(result *services.BulkTransactionResult) map[uuid.UUID]string {
+	statuses := make(map[uuid.UUID]string)
+	for _, outcome := range result.Results {
+		statuses[outcome.ID] = outcome.Status
+	}
+	return statuses
+}
+
+func TestBulkTransactionService_RecategorizeReportsEachItem(t *testing.T) {
+	f := newBulkFixture()
+	own := f.seed(models.Transaction{Category: "Food", Status: "Completed", Amount: money.FromMajor(10)})
+	same := f.seed(models.Transaction{Category: "Groceries", Status: "Completed", Amount: money.FromMajor(10)})
+	foreign := f.seed(models.Transaction{UserID: uuid.New(), Category: "Food"})
+	split := f.seed(models.Transaction{Category: "Food", Splits: []models.TransactionSplit{{Category: "Food"}, {Category: "Home"}}})
+	transferLeg := f.seed(models.Transaction{Category: "Transfer", TransferID: walletPtr(uuid.New())})
+	missing := uuid.New()
+
+	result, err := f.service.BulkUpdate(testutils.TestUserID, services.BulkTransactionRequest{
+		IDs:       []uuid.UUID{own, same, foreign, split, transferLeg, missing, own},
+		Operation: services.BulkRecategorize,
+		Category:  "Groceries",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := map[uuid.UUID]string{
+		own:         services.BulkResultUpdated,
+		same:        services.BulkResultUnchanged,
+		foreign:     services.BulkResultFailed,
+		split:       services.BulkResultFailed,
+		transferLeg: services.BulkResultFailed,
+		missing:     services.BulkResultFailed,
+	}
+	statuses := outcomeStatuses(result)
+	for id, status := range expected {
+		if statuses[id] != status {
+			t.Errorf("Expected %s for %s, got %q", status, id, statuses[id])
+		}
+	}
+	if result.Matched != 6 || result.Succeeded != 2 || result.Failed != 4 {
+		t.Errorf("Expected 6 matched, 2 succeeded and 4 failed, got %d, %d and %d", result.Matched, result.Succeeded, result.Failed)
+	}
+	if f.stored[own].Category != "Groceries" || f.stored[foreign].Category != "Food" {
+		t.Errorf("Expected only the owned transaction to be recategorized")
+	}
+}
+
+func TestBulkTransactionService_BalanceChanges(t *testing.T) {
+	tests := []struct {
+		name             string
+		req              services.BulkTransactionRequest
+		expectedBalances map[uuid.UUID]money.Amount
+	}{
+		{
+			name:             "set pending reverses completed expenses",
+			req:              services.BulkTransactionRequest{Operation: services.BulkSetStatus, Status: "Pending"},
+			expectedBalances: map[uuid.UUID]money.Amount{testutils.TestWalletID: money.FromMajor(150)},
+		},
+		{
+			name: "move wallet shifts the balance",
+			req:  services.BulkTransactionRequest{Operation: services.BulkMoveWallet, WalletID: walletPtr(secondWalletID)},
+			expectedBalances: map[uuid.UUID]money.Amount{
+				testutils.TestWalletID: money.FromMajor(150),
+				secondWalletID:         money.FromMajor(-150),
+			},
+		},
+		{
+			name:             "delete reverses the expenses",
+			req:              services.BulkTransactionRequest{Operation: services.BulkDelete},
+			expectedBalances: map[uuid.UUID]money.Amount{testutils.TestWalletID: money.FromMajor(150)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newBulkFixture()
+			first := f.seed(models.Transaction{Type: "expense", Status: "Completed", Amount: money.FromMajor(100)})
+			second := f.seed(models.Transaction{Type: "expense", Status: "Completed", Amount: money.FromMajor(50)})
+			tt.req.IDs = []uuid.UUID{first, second}
+
+			result, err := f.service.BulkUpdate(testutils.TestUserID, tt.req)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if result.Failed != 0 {
+				t.Errorf("Expected no failures, got %+v", result.Results)
+			}
+			for walletID, expected := range tt.expectedBalances {
+				if f.balances[walletID] != expected {
+					t.Errorf("Expected balance change %v for %s, got %v", expected, walletID, f.balances[walletID])
+				}
+			}
+		})
+	}
+}
+
+func TestBulkTransactionService_ConcurrentDelete(t *testing.T) {
+	f := newBulkFixture()
+	first := f.seed(models.Transaction{Type: "expense", Status: "Completed", Amount: money.FromMajor(100)})
+	second := f.seed(models.Transaction{Type: "expense", Status: "Completed", Amount: money.FromMajor(50)})
+	req := services.BulkTransactionRequest{IDs: []uuid.UUID{first, second}, Operation: services.BulkDelete}
+
+	// The same bulk delete commits while this one waits for its first lock
+	race := testutils.RaceOnce(func() {
+		if _, err := f.service.BulkUpdate(testutils.TestUserID, req); err != nil {
+			t.Fatalf("Unexpected error in the racing delete: %v", err)
+		}
+	})
+	f.transactionRepo.FindByIDForUpdateFunc = func(id uuid.UUID) (*models.Transaction, error) {
+		race()
+		return f.transactionRepo.FindByID(id)
+	}
+
+	result, err := f.service.BulkUpdate(testutils.TestUserID, req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Failed != 2 || result.Results[0].Error != "transaction not found" {
+		t.Errorf("Expected both transactions to fail as already deleted, got %+v", result.Results)
+	}
+	if f.balances[testutils.TestWalletID] != money.FromMajor(150) {
+		t.Errorf("Expected the expenses to be reversed once, got %v", f.balances[testutils.TestWalletID])
+	}
+}
+
+func TestBulkTransactionService_LocksInStableOrder(t *testing.T) {
+	f := newBulkFixture()
+	var ids []uuid.UUID
+	for i := 0; i < 5; i++ {
+		ids = append(ids, f.seed(models.Transaction{Category: "Food", Status: "Completed", Amount: money.FromMajor(10)}))
+	}
+	var locked []uuid.UUID
+	f.transactionRepo.FindByIDForUpdateFunc = func(id uuid.UUID) (*models.Transaction, error) {
+		locked = append(locked, id)
+		return f.transactionRepo.FindByID(id)
+	}
+
+	// The same transactions requested in opposite orders are locked alike
+	requested := [][]uuid.UUID{ids, {ids[4], ids[3], ids[2], ids[1], ids[0]}}
+	var orders [][]uuid.UUID
+	for _, order := range requested {
+		locked = nil
+		result, err := f.service.BulkUpdate(testutils.TestUserID, services.BulkTransactionRequest{
+			IDs: order, Operation: services.BulkRecategorize, Category: "Groceries",
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		for i, outcome := range result.Results {
+			if outcome.ID != order[i] {
+				t.Errorf("Expected outcome %d to be reported for %s, got %s", i, order[i], outcome.ID)
+			}
+		}
+		orders = append(orders, locked)
+	}
+
+	for i := range ids {
+		if orders[0][i] != orders[1][i] {
+			t.Fatalf("Expected the same lock order for both requests, got %v and %v", orders[0], orders[1])
+		}
+		if i > 0 && orders[0][i-1].String() > orders[0][i].String() {
+			t.Fatalf("Expected transactions to be locked in ID order, got %v", orders[0])
+		}
+	}
+}
+
+func TestBulkTransactionService_RepeatedMoveWallet(t *testing.T) {
+	f := newBulkFixture()
+	id := f.seed(models.Transaction{Type: "expense", Status: "Completed", Amount: money.FromMajor(100)})
+	req := services.BulkTransactionRequest{IDs: []uuid.UUID{id}, Operation: services.BulkMoveWallet, WalletID: walletPtr(secondWalletID)}
+
+	for i := 0; i < 2; i++ {
+		if _, err := f.service.BulkUpdate(testutils.TestUserID, req); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if f.balances[testutils.TestWalletID] != money.FromMajor(100) || f.balances[secondWalletID] != money.FromMajor(-100) {
+		t.Errorf("Expected the expense to move once, got %v", f.balances)
+	}
+}
+
+func TestBulkTransactionService_ByFilter(t *testing.T) {
+	f := newBulkFixture()
+	food := f.seed(models.Transaction{Category: "Food", Status: "Completed"})
+	rent := f.seed(models.Transaction{Category: "Rent", Status: "Completed"})
+
+	result, err := f.service.BulkUpdate(testutils.TestUserID, services.BulkTransactionRequest{
+		Filter:    &services.TransactionListQuery{Categories: []string{"food"}},
+		Operation: services.BulkAddTags,
+		Tags:      []string{"trip", " "},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Matched != 1 || outcomeStatuses(result)[food] != services.BulkResultUpdated {
+		t.Fatalf("Expected only the food transaction to be tagged, got %+v", result.Results)
+	}
+	if len(f.stored[food].Tags) != 1 || f.stored[food].Tags[0].Name != "trip" || len(f.stored[rent].Tags) != 0 {
+		t.Errorf("Expected the trip tag on the food transaction only")
+	}
+
+	result, err = f.service.BulkUpdate(testutils.TestUserID, services.BulkTransactionRequest{
+		IDs:       []uuid.UUID{food, rent},
+		Operation: services.BulkRemoveTags,
+		Tags:      []string{"Trip"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	statuses := outcomeStatuses(result)
+	if statuses[food] != services.BulkResultUpdated || statuses[rent] != services.BulkResultUnchanged || len(f.stored[food].Tags) != 0 {
+		t.Errorf("Expected the trip tag to be removed from the food transaction, got %+v", result.Results)
+	}
+}
+
+func TestBulkTransactionService_RejectsRequest(t *testing.T) {
+	f := newBulkFixture()
+	id := f.seed(models.Transaction{Category: "Food"})
+
+	tests := []struct {
+		name string
+		req  services.BulkTransactionRequest
+	}{
+		{name: "no selection", req: services.BulkTransactionRequest{Operation: services.BulkDelete}},
+		{name: "ids and filter", req: services.BulkTransactionRequest{IDs: []uuid.UUID{id}, Filter: &services.TransactionListQuery{}, Operation: services.BulkDelete}},
+		{name: "unknown operation", req: services.BulkTransactionRequest{IDs: []uuid.UUID{id}, Operation: "archive"}},
+		{name: "missing category", req: services.BulkTransactionRequest{IDs: []uuid.UUID{id}, Operation: services.BulkRecategorize, Category: "  "}},
+		{name: "foreign wallet", req: services.BulkTransactionRequest{IDs: []uuid.UUID{id}, Operation: services.BulkMoveWallet, WalletID: walletPtr(foreignWalletID)}},
+		{name: "invalid filter", req: services.BulkTransactionRequest{Filter: &services.TransactionListQuery{Statuses: []string{"Done"}}, Operation: services.BulkDelete}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := f.service.BulkUpdate(testutils.TestUserID, tt.req); err == nil {
+				t.Error("Expected an error")
+			}
+			if _, ok := f.stored[id]; !ok {
+				t.Error("Expected the transaction to be left alone")
+			}
+		})
+	}
+}
+
+func TestBulkTransactionService_DatabaseErrorAborts(t *testing.T) {
+	f := newBulkFixture()
+	first := f.seed(models.Transaction{Type: "expense", Status: "Completed", Amount: money.FromMajor(100)})
+	second := f.seed(models.Transaction{Type: "expense", Status: "Completed", Amount: money.FromMajor(50)})
+	f.transactionRepo.DeleteFunc = func(id uuid.UUID) error {
+		return errors.New("connection reset")
+	}
+
+	_, err := f.service.BulkUpdate(testutils.TestUserID, services.BulkTransactionRequest{
+		IDs:       []uuid.UUID{first, second},
+		Operation: services.BulkDelete,
+	})
+	if err == nil {
+		t.Fatal("Expected the request to fail")
+	}
+	if len(f.balances) != 0 {
+		t.Errorf("Expected no balance changes, got %v", f.balances)
+	}
+}
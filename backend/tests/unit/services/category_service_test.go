@@ -0,0 +1,380 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+	"github.com/nyunja/fity-budget-backend/tests/unit/mocks"
+	"github.com/nyunja/fity-budget-backend/tests/unit/testutils"
+	"gorm.io/gorm"
+)
+
+// categoryFixture keeps categories and budgets in memory and records the
+// category renames applied to the user's records
+type categoryFixture struct {
+	categories map[uuid.UUID]*models.Category
+	budgets    map[uuid.UUID]*models.Budget
+	renames    [][2]string
+	references map[string]int64
+	limits     []*models.BudgetLimit
+}
+
+func newCategoryFixture() *categoryFixture {
+	return &categoryFixture{
+		categories: make(map[uuid.UUID]*models.Category),
+		budgets:    make(map[uuid.UUID]*models.Budget),
+		references: make(map[string]int64),
+	}
+}
+
+func (f *categoryFixture) add(name string, parent *models.Category) *models.Category {
+	category := &models.Category{ID: uuid.New(), UserID: testutils.TestUserID, Name: name, Kind: models.CategoryKindExpense}
+	if parent != nil {
+		category.ParentID = &parent.ID
+		category.Kind = parent.Kind
+	}
+	f.categories[category.ID] = category
+	return category
+}
+
+func (f *categoryFixture) service() services.CategoryService {
+	categoryRepo := &mocks.MockCategoryRepository{
+		CreateFunc: func(category *models.Category) error {
+			category.ID = uuid.New()
+			f.categories[category.ID] = category
+			return nil
+		},
+		FindByIDFunc: func(id uuid.UUID) (*models.Category, error) {
+			if category, ok := f.categories[id]; ok {
+				copied := *category
+				return &copied, nil
+			}
+			return nil, gorm.ErrRecordNotFound
+		},
+		FindByUserIDFunc: func(userID uuid.UUID) ([]*models.Category, error) {
+			var categories []*models.Category
+			for _, category := range f.categories {
+				copied := *category
+				categories = append(categories, &copied)
+			}
+			return categories, nil
+		},
+		FindByUserIDAndNameFunc: func(userID uuid.UUID, name string) (*models.Category, error) {
+			for _, category := range f.categories {
+				if strings.EqualFold(category.Name, name) {
+					return category, nil
+				}
+			}
+			return nil, gorm.ErrRecordNotFound
+		},
+		CountReferencesFunc: func(userID uuid.UUID, name string) (int64, error) {
+			return f.references[strings.ToLower(name)], nil
+		},
+		RenameReferencesFunc: func(userID uuid.UUID, from, to string) error {
+			f.renames = append(f.renames, [2]string{from, to})
+			return nil
+		},
+		UpdateFunc: func(category *models.Category) error {
+			copied := *category
+			copied.Children = nil
+			f.categories[category.ID] = &copied
+			return nil
+		},
+		DeleteFunc: func(id uuid.UUID) error {
+			delete(f.categories, id)
+			return nil
+		},
+	}
+	budgetRepo := &mocks.MockBudgetRepository{
+		FindByUserIDAndCategoryFunc: func(userID uuid.UUID, category string) (*models.Budget, error) {
+			for _, budget := range f.budgets {
+				if strings.EqualFold(budget.Category, category) {
+					return budget, nil
+				}
+			}
+			return nil, gorm.ErrRecordNotFound
+		},
+		UpdateFunc: func(budget *models.Budget) error {
+			f.budgets[budget.ID] = budget
+			return nil
+		},
+		DeleteFunc: func(id uuid.UUID) error {
+			delete(f.budgets, id)
+			return nil
+		},
+	}
+
+	limitRepo := &mocks.MockBudgetLimitRepository{
+		FindByBudgetIDsFunc: func(budgetIDs []uuid.UUID) ([]*models.BudgetLimit, error) {
+			return f.limits, nil
+		},
+		UpsertFunc: func(limit *models.BudgetLimit) error {
+			f.limits = append(f.limits, limit)
+			return nil
+		},
+	}
+
+	return services.NewCategoryService(categoryRepo, budgetRepo, limitRepo, &mocks.MockTxManager{})
+}
+
+func TestCategoryService_CreateCategory(t *testing.T) {
+	fixture := newCategoryFixture()
+	food := fixture.add("Food", nil)
+	groceries := fixture.add("Groceries", food)
+	salary := fixture.add("Salary", nil)
+	salary.Kind = models.CategoryKindIncome
+
+	tests := []struct {
+		name         string
+		req          services.CreateCategoryRequest
+		expectedKind string
+		expectError  bool
+	}{
+		{
+			name:         "top-level category defaults to expense",
+			req:          services.CreateCategoryRequest{Name: " Transport ", Icon: "bus"},
+			expectedKind: models.CategoryKindExpense,
+		},
+		{
+			name:         "subcategory takes its parent's kind",
+			req:          services.CreateCategoryRequest{Name: "Bonus", ParentID: &salary.ID},
+			expectedKind: models.CategoryKindIncome,
+		},
+		{
+			name:        "subcategory of a different kind",
+			req:         services.CreateCategoryRequest{Name: "Refunds", ParentID: &food.ID, Kind: models.CategoryKindIncome},
+			expectError: true,
+		},
+		{
+			name:        "subcategories do not nest",
+			req:         services.CreateCategoryRequest{Name: "Fruit", ParentID: &groceries.ID},
+			expectError: true,
+		},
+		{
+			name:        "name taken in another case",
+			req:         services.CreateCategoryRequest{Name: "FOOD"},
+			expectError: true,
+		},
+		{
+			name:        "blank name",
+			req:         services.CreateCategoryRequest{Name: "  "},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			category, err := fixture.service().CreateCategory(testutils.TestUserID, tt.req)
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if category.Kind != tt.expectedKind {
+				t.Errorf("Expected kind %s, got %s", tt.expectedKind, category.Kind)
+			}
+			if category.Name != strings.TrimSpace(tt.req.Name) {
+				t.Errorf("Expected name %q, got %q", strings.TrimSpace(tt.req.Name), category.Name)
+			}
+		})
+	}
+}
+
+func TestCategoryService_GetUserCategories_NestsSubcategories(t *testing.T) {
+	fixture := newCategoryFixture()
+	food := fixture.add("Food", nil)
+	fixture.add("Groceries", food)
+	fixture.add("Dining Out", food)
+	fixture.add("Transport", nil)
+
+	categories, err := fixture.service().GetUserCategories(testutils.TestUserID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(categories) != 2 {
+		t.Fatalf("Expected 2 top-level categories, got %d", len(categories))
+	}
+	for _, category := range categories {
+		expected := 0
+		if category.ID == food.ID {
+			expected = 2
+		}
+		if len(category.Children) != expected {
+			t.Errorf("Expected %s to have %d subcategories, got %d", category.Name, expected, len(category.Children))
+		}
+	}
+}
+
+func TestCategoryService_UpdateCategory_RenameRepointsRecords(t *testing.T) {
+	fixture := newCategoryFixture()
+	food := fixture.add("Food", nil)
+	fixture.add("Transport", nil)
+
+	category, err := fixture.service().UpdateCategory(food.ID, testutils.TestUserID, services.UpdateCategoryRequest{Name: "Food & Drink"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if category.Name != "Food & Drink" {
+		t.Errorf("Expected the category to be renamed, got %q", category.Name)
+	}
+	if len(fixture.renames) != 1 || fixture.renames[0] != [2]string{"Food", "Food & Drink"} {
+		t.Errorf("Expected records to move from Food to Food & Drink, got %v", fixture.renames)
+	}
+
+	if _, err := fixture.service().UpdateCategory(food.ID, testutils.TestUserID, services.UpdateCategoryRequest{Name: "transport"}); err == nil {
+		t.Error("Expected renaming to an existing category to fail")
+	}
+}
+
+func TestCategoryService_UpdateCategory_Hierarchy(t *testing.T) {
+	fixture := newCategoryFixture()
+	food := fixture.add("Food", nil)
+	groceries := fixture.add("Groceries", food)
+	transport := fixture.add("Transport", nil)
+
+	if _, err := fixture.service().UpdateCategory(food.ID, testutils.TestUserID, services.UpdateCategoryRequest{ParentID: &transport.ID}); err == nil {
+		t.Error("Expected a category with subcategories not to become a subcategory")
+	}
+	if _, err := fixture.service().UpdateCategory(transport.ID, testutils.TestUserID, services.UpdateCategoryRequest{ParentID: &transport.ID}); err == nil {
+		t.Error("Expected a category not to become its own parent")
+	}
+
+	category, err := fixture.service().UpdateCategory(groceries.ID, testutils.TestUserID, services.UpdateCategoryRequest{TopLevel: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if category.ParentID != nil {
+		t.Error("Expected groceries to become a top-level category")
+	}
+	if len(fixture.renames) != 0 {
+		t.Errorf("Expected no records to be renamed, got %v", fixture.renames)
+	}
+}
+
+func TestCategoryService_DeleteCategory(t *testing.T) {
+	fixture := newCategoryFixture()
+	food := fixture.add("Food", nil)
+	fixture.add("Groceries", food)
+	transport := fixture.add("Transport", nil)
+	unused := fixture.add("Unused", nil)
+	fixture.references["transport"] = 3
+
+	tests := []struct {
+		name        string
+		id          uuid.UUID
+		expectError bool
+	}{
+		{name: "category with subcategories", id: food.ID, expectError: true},
+		{name: "category in use", id: transport.ID, expectError: true},
+		{name: "unused category", id: unused.ID},
+		{name: "unknown category", id: uuid.New(), expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, existed := fixture.categories[tt.id]
+
+			err := fixture.service().DeleteCategory(tt.id, testutils.TestUserID)
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("Expected error but got none")
+				}
+				if _, exists := fixture.categories[tt.id]; existed && !exists {
+					t.Error("Expected the category to be kept")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if _, exists := fixture.categories[tt.id]; exists {
+				t.Error("Expected the category to be deleted")
+			}
+		})
+	}
+}
+
+func TestCategoryService_MergeCategory(t *testing.T) {
+	fixture := newCategoryFixture()
+	eatingOut := fixture.add("Eating Out", nil)
+	takeaway := fixture.add("Takeaway", eatingOut)
+	food := fixture.add("Food", nil)
+	sourceBudget := &models.Budget{ID: uuid.New(), Category: "Eating Out", LimitAmount: money.FromMajor(2000)}
+	targetBudget := &models.Budget{ID: uuid.New(), Category: "Food", LimitAmount: money.FromMajor(5000)}
+	fixture.budgets[sourceBudget.ID] = sourceBudget
+	fixture.budgets[targetBudget.ID] = targetBudget
+
+	merged, err := fixture.service().MergeCategory(eatingOut.ID, testutils.TestUserID, food.ID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, exists := fixture.categories[eatingOut.ID]; exists {
+		t.Error("Expected the merged category to be deleted")
+	}
+	if len(fixture.renames) != 1 || fixture.renames[0] != [2]string{"Eating Out", "Food"} {
+		t.Errorf("Expected records to move from Eating Out to Food, got %v", fixture.renames)
+	}
+	if parent := fixture.categories[takeaway.ID].ParentID; parent == nil || *parent != food.ID {
+		t.Error("Expected takeaway to move under food")
+	}
+	if len(merged.Children) != 1 || merged.Children[0].ID != takeaway.ID {
+		t.Errorf("Expected food to have takeaway as its subcategory, got %v", merged.Children)
+	}
+	if _, exists := fixture.budgets[sourceBudget.ID]; exists {
+		t.Error("Expected the merged category's budget to be deleted")
+	}
+	if targetBudget.LimitAmount != money.FromMajor(7000) {
+		t.Errorf("Expected the budgets to be combined at 7000, got %v", targetBudget.LimitAmount)
+	}
+	// Statuses read limit versions, so the combined limit gets one from this period
+	now := time.Now().UTC()
+	thisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if len(fixture.limits) == 0 {
+		t.Fatal("Expected a limit version for the combined budget")
+	}
+	if latest := fixture.limits[len(fixture.limits)-1]; latest.BudgetID != targetBudget.ID || latest.LimitAmount != money.FromMajor(7000) || !latest.EffectiveFrom.Equal(thisMonth) {
+		t.Errorf("Expected a 7000 limit from this month, got %+v", latest)
+	}
+}
+
+func TestCategoryService_MergeCategory_Invalid(t *testing.T) {
+	fixture := newCategoryFixture()
+	food := fixture.add("Food", nil)
+	groceries := fixture.add("Groceries", food)
+	salary := fixture.add("Salary", nil)
+	salary.Kind = models.CategoryKindIncome
+
+	tests := []struct {
+		name     string
+		id       uuid.UUID
+		targetID uuid.UUID
+	}{
+		{name: "into itself", id: food.ID, targetID: food.ID},
+		{name: "into its own subcategory", id: food.ID, targetID: groceries.ID},
+		{name: "different kinds", id: salary.ID, targetID: food.ID},
+		{name: "unknown target", id: food.ID, targetID: uuid.New()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := fixture.service().MergeCategory(tt.id, testutils.TestUserID, tt.targetID); err == nil {
+				t.Fatal("Expected error but got none")
+			}
+			if len(fixture.renames) != 0 {
+				t.Errorf("Expected no records to be renamed, got %v", fixture.renames)
+			}
+		})
+	}
+}
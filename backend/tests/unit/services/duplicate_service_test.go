@@ -0,0 +1,273 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+	"github.com/nyunja/fity-budget-backend/tests/unit/mocks"
+	"github.com/nyunja/fity-budget-backend/tests/unit/testutils"
+)
+
+// duplicateFixture keeps transactions, wallet balances and receipt moves in memory
+type duplicateFixture struct {
+	store           *testutils.TransactionStore
+	ledger          map[uuid.UUID]*models.Transaction
+	balances        map[uuid.UUID]money.Amount
+	moved           map[uuid.UUID]uuid.UUID
+	transactionRepo *mocks.MockTransactionRepository
+	service         services.DuplicateService
+}
+
+func newDuplicateFixture() *duplicateFixture {
+	transactions := testutils.NewTransactionStore()
+	wallets := testutils.NewWalletStore()
+	f := &duplicateFixture{
+		store:           transactions,
+		ledger:          transactions.Stored,
+		balances:        wallets.Balances,
+		moved:           make(map[uuid.UUID]uuid.UUID),
+		transactionRepo: transactions.Repo(),
+	}
+
+	f.transactionRepo.UpdateColumnsFunc = func(id uuid.UUID, columns map[string]interface{}) error {
+		stored := f.ledger[id]
+		stored.Notes = columns["notes"].(string)
+		stored.ReceiptURL = columns["receipt_url"].(string)
+		return nil
+	}
+	receiptRepo := &mocks.MockReceiptRepository{
+		MoveToTransactionFunc: func(fromTransactionID, toTransactionID uuid.UUID) error {
+			f.moved[fromTransactionID] = toTransactionID
+			return nil
+		},
+	}
+
+	f.service = services.NewDuplicateService(f.transactionRepo, wallets.Repo(), receiptRepo, &mocks.MockTxManager{})
+	return f
+}
+
+// add stores a completed expense in the test wallet and returns its ID
+func (f *duplicateFixture) add(name string, amount int64, date time.Time, modify func(*models.Transaction)) uuid.UUID {
+	transaction := &models.Transaction{
+		UserID:          testutils.TestUserID,
+		WalletID:        walletPtr(testutils.TestWalletID),
+		Type:            models.TransactionTypeExpense,
+		Status:          "Completed",
+		Name:            name,
+		Amount:          money.FromMajor(amount),
+		TransactionDate: date,
+	}
+	if modify != nil {
+		modify(transaction)
+	}
+	return f.store.Seed(*transaction)
+}
+
+func TestDuplicateService_FindDuplicates(t *testing.T) {
+	f := newDuplicateFixture()
+	day := time.Date(2026, 10, 5, 12, 0, 0, 0, time.UTC)
+	schedule := uuid.New()
+
+	// A manual entry and its imported copy
+	manual := f.add("Naivas", 2350, day, nil)
+	imported := f.add("NAIVAS SUPERMARKET WESTLANDS", 2350, day.AddDate(0, 0, 2), func(t *models.Transaction) { t.ExternalID = "QJ81XY" })
+	// Two statement rows with their own references are separate payments
+	f.add("Uber trip", 640, day, func(t *models.Transaction) { t.ExternalID = "QJ11AA" })
+	f.add("Uber trip", 640, day, func(t *models.Transaction) { t.ExternalID = "QJ11AB" })
+	// The same name on the same day is near certain
+	first := f.add("Java House", 900, day.AddDate(0, 0, 1), nil)
+	second := f.add("java house!", 900, day.AddDate(0, 0, 1), nil)
+	// Daily recurring postings are separate occurrences
+	f.add("Bus fare", 100, day, func(t *models.Transaction) { t.RecurringID = &schedule })
+	f.add("Bus fare", 100, day.AddDate(0, 0, 1), func(t *models.Transaction) { t.RecurringID = &schedule })
+	// Too far apart, a different wallet, a different payee or a different type
+	f.add("Naivas", 2350, day.AddDate(0, 0, 10), nil)
+	f.add("Naivas", 2350, day, func(t *models.Transaction) { t.WalletID = walletPtr(secondWalletID) })
+	f.add("Carrefour", 2350, day, nil)
+	f.add("Naivas", 2350, day, func(t *models.Transaction) { t.Type = models.TransactionTypeIncome })
+
+	groups, err := f.service.FindDuplicates(testutils.TestUserID, services.FindDuplicatesRequest{
+		StartDate: day.AddDate(0, 0, -30),
+		EndDate:   day.AddDate(0, 0, 30),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(groups) != 2 {
+		for _, group := range groups {
+			t.Logf("group: %s x%d", group.Transactions[0].Name, len(group.Transactions))
+		}
+		t.Fatalf("Expected 2 groups, got %d", len(groups))
+	}
+
+	// Newest group first
+	naivas, java := groups[0], groups[1]
+	if len(naivas.Transactions) != 2 || naivas.Transactions[0].ID != manual || naivas.Transactions[1].ID != imported {
+		t.Errorf("Expected the manual and imported Naivas entries, got %+v", naivas.Transactions)
+	}
+	if naivas.Confidence != services.DuplicateConfidenceMedium || naivas.Amount != money.FromMajor(2350) {
+		t.Errorf("Unexpected Naivas group %+v", naivas)
+	}
+	if java.Transactions[0].ID != first || java.Transactions[1].ID != second || java.Confidence != services.DuplicateConfidenceHigh {
+		t.Errorf("Expected a high confidence Java House group, got %+v", java)
+	}
+}
+
+func TestDuplicateService_FindDuplicates_Invalid(t *testing.T) {
+	f := newDuplicateFixture()
+
+	if _, err := f.service.FindDuplicates(testutils.TestUserID, services.FindDuplicatesRequest{WindowDays: 30}); err == nil {
+		t.Error("Expected error for a window over the limit")
+	}
+	end := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := f.service.FindDuplicates(testutils.TestUserID, services.FindDuplicatesRequest{StartDate: end, EndDate: end}); err == nil {
+		t.Error("Expected error for an empty range")
+	}
+	groups, err := f.service.FindDuplicates(testutils.TestUserID, services.FindDuplicatesRequest{})
+	if err != nil || groups == nil || len(groups) != 0 {
+		t.Errorf("Expected no groups for an empty ledger, got %v, %v", groups, err)
+	}
+}
+
+func TestDuplicateService_MergeDuplicates(t *testing.T) {
+	f := newDuplicateFixture()
+	day := time.Date(2026, 10, 5, 12, 0, 0, 0, time.UTC)
+	travel := models.Tag{ID: uuid.New(), Name: "Travel"}
+	work := models.Tag{ID: uuid.New(), Name: "Work"}
+
+	kept := f.add("Uber", 640, day, func(t *models.Transaction) {
+		t.Notes = "Airport"
+		t.Tags = []models.Tag{travel}
+	})
+	copyOne := f.add("UBER TRIP", 640, day, func(t *models.Transaction) {
+		t.Notes = "Airport"
+		t.Tags = []models.Tag{travel, work}
+		t.ReceiptURL = "https://example.com/uber.pdf"
+	})
+	copyTwo := f.add("Uber", 640, day, func(t *models.Transaction) {
+		t.Status = "Pending"
+		t.Notes = "Client visit"
+	})
+
+	merged, err := f.service.MergeDuplicates(kept, testutils.TestUserID, services.MergeDuplicatesRequest{
+		DuplicateIDs: []uuid.UUID{copyOne, copyTwo, copyOne},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if merged.Notes != "Airport\nClient visit" || merged.ReceiptURL != "https://example.com/uber.pdf" {
+		t.Errorf("Expected combined notes and the receipt URL, got %q and %q", merged.Notes, merged.ReceiptURL)
+	}
+	if len(merged.Tags) != 2 || merged.Tags[1].ID != work.ID {
+		t.Errorf("Expected the Work tag to be added once, got %+v", merged.Tags)
+	}
+	if len(f.ledger) != 1 {
+		t.Errorf("Expected the duplicates to be deleted, %d transactions left", len(f.ledger))
+	}
+	if f.moved[copyOne] != kept || f.moved[copyTwo] != kept {
+		t.Error("Expected the duplicates' receipts to move to the kept transaction")
+	}
+	// Only the completed duplicate had debited the wallet
+	if f.balances[testutils.TestWalletID] != money.FromMajor(640) {
+		t.Errorf("Expected the wallet to be credited 640, got %s", f.balances[testutils.TestWalletID])
+	}
+}
+
+func TestDuplicateService_MergeDuplicates_Concurrent(t *testing.T) {
+	f := newDuplicateFixture()
+	day := time.Date(2026, 10, 5, 12, 0, 0, 0, time.UTC)
+	kept := f.add("Uber", 640, day, nil)
+	duplicate := f.add("Uber", 640, day, func(t *models.Transaction) { t.Notes = "Airport" })
+	req := services.MergeDuplicatesRequest{DuplicateIDs: []uuid.UUID{duplicate}}
+
+	// A second merge of the same pair commits while the first waits for its lock
+	race := testutils.RaceOnce(func() {
+		if _, err := f.service.MergeDuplicates(kept, testutils.TestUserID, req); err != nil {
+			t.Fatalf("Unexpected error in the racing merge: %v", err)
+		}
+	})
+	f.transactionRepo.FindByIDForUpdateFunc = func(id uuid.UUID) (*models.Transaction, error) {
+		race()
+		return f.transactionRepo.FindByID(id)
+	}
+
+	if _, err := f.service.MergeDuplicates(kept, testutils.TestUserID, req); err == nil {
+		t.Error("Expected the merge to fail once its duplicate is gone")
+	}
+	if _, err := f.service.MergeDuplicates(kept, testutils.TestUserID, req); err == nil {
+		t.Error("Expected a repeated merge to fail")
+	}
+	if f.balances[testutils.TestWalletID] != money.FromMajor(640) {
+		t.Errorf("Expected the duplicate to be reversed once, got %s", f.balances[testutils.TestWalletID])
+	}
+	if len(f.ledger) != 1 || f.ledger[kept].Notes != "Airport" {
+		t.Errorf("Expected the kept transaction with the duplicate's notes, got %+v", f.ledger)
+	}
+}
+
+func TestDuplicateService_MergeDuplicates_Rejected(t *testing.T) {
+	day := time.Date(2026, 10, 5, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		setup func(f *duplicateFixture) (uuid.UUID, []uuid.UUID)
+	}{
+		{
+			name: "no duplicates",
+			setup: func(f *duplicateFixture) (uuid.UUID, []uuid.UUID) {
+				return f.add("Uber", 640, day, nil), nil
+			},
+		},
+		{
+			name: "merge into itself",
+			setup: func(f *duplicateFixture) (uuid.UUID, []uuid.UUID) {
+				id := f.add("Uber", 640, day, nil)
+				return id, []uuid.UUID{id}
+			},
+		},
+		{
+			name: "another user's duplicate",
+			setup: func(f *duplicateFixture) (uuid.UUID, []uuid.UUID) {
+				foreign := f.add("Uber", 640, day, func(t *models.Transaction) { t.UserID = uuid.New() })
+				return f.add("Uber", 640, day, nil), []uuid.UUID{foreign}
+			},
+		},
+		{
+			name: "transfer leg",
+			setup: func(f *duplicateFixture) (uuid.UUID, []uuid.UUID) {
+				transferID := uuid.New()
+				leg := f.add("Transfer", 640, day, func(t *models.Transaction) {
+					t.Type = models.TransactionTypeTransfer
+					t.TransferID = &transferID
+				})
+				return f.add("Transfer", 640, day, nil), []uuid.UUID{leg}
+			},
+		},
+		{
+			name: "missing duplicate",
+			setup: func(f *duplicateFixture) (uuid.UUID, []uuid.UUID) {
+				return f.add("Uber", 640, day, nil), []uuid.UUID{uuid.New()}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newDuplicateFixture()
+			id, duplicates := tt.setup(f)
+			before := len(f.ledger)
+
+			if _, err := f.service.MergeDuplicates(id, testutils.TestUserID, services.MergeDuplicatesRequest{DuplicateIDs: duplicates}); err == nil {
+				t.Fatal("Expected an error")
+			}
+			if len(f.ledger) != before || len(f.balances) != 0 {
+				t.Error("Expected nothing to change")
+			}
+		})
+	}
+}
@@ -0,0 +1,377 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"github.com/nyunja/fity-budget-backend/internal/repository"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+	"github.com/nyunja/fity-budget-backend/tests/unit/mocks"
+	"github.com/nyunja/fity-budget-backend/tests/unit/testutils"
+	"gorm.io/gorm"
+)
+
+// envelopeFixture has Food and Rent envelopes created in January 2026 and a
+// Travel budget that is not an envelope. January brought 1000 of income into
+// the main wallet and 500 into an off-budget savings wallet; 100 went to Food
+// and 400 to Rent, and Food was overspent by 20 in January and 50 more in
+// February. Rent spending from the savings wallet does not count.
+type envelopeFixture struct {
+	food, rent, travel *models.Budget
+	allocations        []*models.EnvelopeAllocation
+	wallets            []*models.Wallet
+	rates              []*models.ExchangeRate
+	userRepo           *mocks.MockUserRepository
+	service            services.EnvelopeService
+}
+
+var envelopeFebruary = time.Date(2026, time.February, 15, 0, 0, 0, 0, time.UTC)
+
+func newEnvelopeFixture() *envelopeFixture {
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(2026, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	f := &envelopeFixture{
+		food:   &models.Budget{ID: uuid.New(), UserID: testutils.TestUserID, Category: "Food", LimitAmount: money.FromMajor(150), IsEnvelope: true, CreatedAt: date(time.January, 2)},
+		rent:   &models.Budget{ID: uuid.New(), UserID: testutils.TestUserID, Category: "Rent", LimitAmount: money.FromMajor(400), IsEnvelope: true, CreatedAt: date(time.January, 2)},
+		travel: &models.Budget{ID: uuid.New(), UserID: testutils.TestUserID, Category: "Travel", LimitAmount: money.FromMajor(300), CreatedAt: date(time.January, 2)},
+	}
+	f.allocations = []*models.EnvelopeAllocation{
+		{BudgetID: f.food.ID, Month: date(time.January, 1), Amount: money.FromMajor(100), Kind: models.EnvelopeAllocationAssign},
+		{BudgetID: f.rent.ID, Month: date(time.January, 1), Amount: money.FromMajor(400), Kind: models.EnvelopeAllocationAssign},
+	}
+
+	transaction := func(txnType, category string, amount int64, walletID uuid.UUID, on time.Time) *models.Transaction {
+		return &models.Transaction{
+			ID: uuid.New(), UserID: testutils.TestUserID, Type: txnType, Category: category, Amount: money.FromMajor(amount),
+			WalletID: walletPtr(walletID), Status: "Completed", TransactionDate: on,
+		}
+	}
+	transactions := []*models.Transaction{
+		transaction(models.TransactionTypeIncome, "Salary", 1000, testutils.TestWalletID, date(time.January, 5)),
+		transaction(models.TransactionTypeIncome, "Interest", 500, secondWalletID, date(time.January, 6)),
+		transaction(models.TransactionTypeExpense, "Food", 120, testutils.TestWalletID, date(time.January, 10)),
+		transaction(models.TransactionTypeExpense, "Food", 50, testutils.TestWalletID, date(time.February, 3)),
+		transaction(models.TransactionTypeExpense, "Rent", 30, secondWalletID, date(time.February, 4)),
+	}
+
+	budgets := []*models.Budget{f.food, f.rent, f.travel}
+	budgetRepo := &mocks.MockBudgetRepository{
+		FindByIDFunc: func(id uuid.UUID) (*models.Budget, error) {
+			for _, budget := range budgets {
+				if budget.ID == id {
+					return budget, nil
+				}
+			}
+			return nil, errors.New("record not found")
+		},
+		FindByUserIDFunc: func(userID uuid.UUID) ([]*models.Budget, error) {
+			var live []*models.Budget
+			for _, budget := range budgets {
+				if !budget.DeletedAt.Valid {
+					live = append(live, budget)
+				}
+			}
+			return live, nil
+		},
+	}
+	// Like the repository, totals only cover live envelopes
+	liveEnvelope := func(id uuid.UUID) bool {
+		for _, budget := range budgets {
+			if budget.ID == id {
+				return budget.IsEnvelope && !budget.DeletedAt.Valid
+			}
+		}
+		return false
+	}
+	allocationRepo := &mocks.MockEnvelopeAllocationRepository{
+		CreateFunc: func(allocations []*models.EnvelopeAllocation) error {
+			f.allocations = append(f.allocations, allocations...)
+			return nil
+		},
+		TotalsFunc: func(userID uuid.UUID) ([]*repository.EnvelopeAllocationTotal, error) {
+			var totals []*repository.EnvelopeAllocationTotal
+			for _, allocation := range f.allocations {
+				if !liveEnvelope(allocation.BudgetID) {
+					continue
+				}
+				totals = append(totals, &repository.EnvelopeAllocationTotal{
+					BudgetID: allocation.BudgetID, Month: allocation.Month, Kind: allocation.Kind, Total: allocation.Amount,
+				})
+			}
+			return totals, nil
+		},
+	}
+	f.wallets = []*models.Wallet{
+		{ID: testutils.TestWalletID, UserID: testutils.TestUserID, Currency: "KES"},
+		{ID: secondWalletID, UserID: testutils.TestUserID, Currency: "KES", OffBudget: true},
+	}
+	walletRepo := &mocks.MockWalletRepository{
+		FindByUserIDFunc: func(userID uuid.UUID) ([]*models.Wallet, error) {
+			return f.wallets, nil
+		},
+	}
+	f.userRepo = &mocks.MockUserRepository{
+		FindByIDFunc: func(id uuid.UUID) (*models.User, error) {
+			return &models.User{ID: id, Currency: "KES"}, nil
+		},
+	}
+	rateRepo := &mocks.MockExchangeRateRepository{
+		FindLatestFunc: func(baseCurrency, quoteCurrency string, on time.Time) (*models.ExchangeRate, error) {
+			return latestRate(f.rates, baseCurrency, quoteCurrency, on), nil
+		},
+	}
+	transactionRepo := &mocks.MockTransactionRepository{AggregateFunc: aggregateTransactions(transactions)}
+
+	f.service = services.NewEnvelopeService(budgetRepo, allocationRepo, walletRepo, transactionRepo, &mocks.MockCategoryRepository{}, f.userRepo, rateRepo, &mocks.MockTxManager{})
+	return f
+}
+
+func envelopeOf(month *services.EnvelopeMonth, budgetID uuid.UUID) *services.Envelope {
+	for _, envelope := range month.Envelopes {
+		if envelope.BudgetID == budgetID {
+			return envelope
+		}
+	}
+	return nil
+}
+
+func TestEnvelopeService_GetEnvelopes(t *testing.T) {
+	f := newEnvelopeFixture()
+
+	month, err := f.service.GetEnvelopes(testutils.TestUserID, envelopeFebruary)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !month.Month.Equal(time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the month to start on 1 February, got %v", month.Month)
+	}
+	// Off-budget income is left out, and January's assignments still count
+	if month.ReadyToAssign != money.FromMajor(500) || month.Income != 0 {
+		t.Errorf("Expected 500 ready to assign and no income in February, got %v and %v", month.ReadyToAssign, month.Income)
+	}
+	if len(month.Envelopes) != 2 {
+		t.Fatalf("Expected only the two envelope budgets, got %d", len(month.Envelopes))
+	}
+
+	food := envelopeOf(month, f.food.ID)
+	if food.CarriedIn != -money.FromMajor(20) || food.Spent != money.FromMajor(50) || food.Available != -money.FromMajor(70) || !food.IsOverspent {
+		t.Errorf("Expected Food to carry in -20, spend 50 and be overspent by 70, got %+v", food)
+	}
+	rent := envelopeOf(month, f.rent.ID)
+	if rent.Available != money.FromMajor(400) || rent.Spent != 0 {
+		t.Errorf("Expected Rent to keep 400 with no on-budget spending, got %+v", rent)
+	}
+	if month.Overspent != money.FromMajor(70) {
+		t.Errorf("Expected 70 overspent, got %v", month.Overspent)
+	}
+}
+
+func TestEnvelopeService_GetEnvelopes_ConvertsCurrency(t *testing.T) {
+	f := newEnvelopeFixture()
+	f.wallets[0].Currency = "USD"
+	f.rates = []*models.ExchangeRate{{BaseCurrency: "USD", QuoteCurrency: "KES", Rate: mustRate("130"), RateDate: day("2026-01-01")}}
+
+	month, err := f.service.GetEnvelopes(testutils.TestUserID, envelopeFebruary)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// USD 1000 of income is KES 130000, of which 500 was assigned
+	if month.ReadyToAssign != money.FromMajor(129500) || month.Currency != "KES" {
+		t.Errorf("Expected KES 129500 ready to assign, got %s %v", month.Currency, month.ReadyToAssign)
+	}
+	if food := envelopeOf(month, f.food.ID); food.Spent != money.FromMajor(6500) {
+		t.Errorf("Expected Food to spend KES 6500 in February, got %v", food.Spent)
+	}
+}
+
+func TestEnvelopeService_Assign(t *testing.T) {
+	tests := []struct {
+		name     string
+		budgetID func(f *envelopeFixture) uuid.UUID
+		amount   money.Amount
+		wantErr  bool
+	}{
+		{name: "from ready to assign", budgetID: func(f *envelopeFixture) uuid.UUID { return f.food.ID }, amount: money.FromMajor(200)},
+		{name: "more than ready to assign", budgetID: func(f *envelopeFixture) uuid.UUID { return f.food.ID }, amount: money.FromMajor(600), wantErr: true},
+		{name: "take back unspent money", budgetID: func(f *envelopeFixture) uuid.UUID { return f.rent.ID }, amount: -money.FromMajor(100)},
+		{name: "take back more than available", budgetID: func(f *envelopeFixture) uuid.UUID { return f.rent.ID }, amount: -money.FromMajor(500), wantErr: true},
+		{name: "budget is not an envelope", budgetID: func(f *envelopeFixture) uuid.UUID { return f.travel.ID }, amount: money.FromMajor(50), wantErr: true},
+		{name: "zero amount", budgetID: func(f *envelopeFixture) uuid.UUID { return f.food.ID }, amount: 0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newEnvelopeFixture()
+			budgetID := tt.budgetID(f)
+
+			month, err := f.service.Assign(testutils.TestUserID, services.AssignEnvelopeRequest{BudgetID: budgetID, Month: envelopeFebruary, Amount: tt.amount})
+			if tt.wantErr {
+				if err == nil || len(f.allocations) != 2 {
+					t.Errorf("Expected the assignment to be rejected, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			recorded := f.allocations[len(f.allocations)-1]
+			if recorded.BudgetID != budgetID || recorded.Amount != tt.amount || recorded.Month.Month() != time.February || recorded.Kind != models.EnvelopeAllocationAssign {
+				t.Errorf("Unexpected allocation %+v", recorded)
+			}
+			if month.ReadyToAssign != money.FromMajor(500)-tt.amount || envelopeOf(month, budgetID).Assigned != tt.amount {
+				t.Errorf("Expected the assignment to come out of ready to assign, got %v", month.ReadyToAssign)
+			}
+		})
+	}
+}
+
+func TestEnvelopeService_Move(t *testing.T) {
+	t.Run("between envelopes", func(t *testing.T) {
+		f := newEnvelopeFixture()
+
+		month, err := f.service.Move(testutils.TestUserID, services.MoveEnvelopeRequest{FromBudgetID: f.rent.ID, ToBudgetID: f.food.ID, Month: envelopeFebruary, Amount: money.FromMajor(100)})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		out, in := f.allocations[2], f.allocations[3]
+		if out.Amount != -money.FromMajor(100) || in.Amount != money.FromMajor(100) || out.MoveID == nil || in.MoveID == nil || *out.MoveID != *in.MoveID {
+			t.Errorf("Expected a linked pair of allocations, got %+v and %+v", out, in)
+		}
+		if envelopeOf(month, f.rent.ID).Available != money.FromMajor(300) || envelopeOf(month, f.food.ID).Available != money.FromMajor(30) {
+			t.Errorf("Expected Rent to have 300 and Food 30 available, got %+v", month.Envelopes)
+		}
+		if month.ReadyToAssign != money.FromMajor(500) {
+			t.Errorf("Expected moves to leave ready to assign alone, got %v", month.ReadyToAssign)
+		}
+	})
+
+	t.Run("released when the envelope goes", func(t *testing.T) {
+		for name, remove := range map[string]func(budget *models.Budget){
+			"deleted":            func(budget *models.Budget) { budget.DeletedAt = gorm.DeletedAt{Time: envelopeFebruary, Valid: true} },
+			"no longer envelope": func(budget *models.Budget) { budget.IsEnvelope = false },
+		} {
+			t.Run(name, func(t *testing.T) {
+				f := newEnvelopeFixture()
+				if _, err := f.service.Move(testutils.TestUserID, services.MoveEnvelopeRequest{FromBudgetID: f.rent.ID, ToBudgetID: f.food.ID, Month: envelopeFebruary, Amount: money.FromMajor(50)}); err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				remove(f.food)
+
+				month, err := f.service.GetEnvelopes(testutils.TestUserID, envelopeFebruary)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if month.ReadyToAssign != money.FromMajor(650) {
+					t.Errorf("Expected the 150 Food held to be ready to assign again, got %v", month.ReadyToAssign)
+				}
+				if envelopeOf(month, f.rent.ID).Available != money.FromMajor(350) {
+					t.Errorf("Expected Rent to keep 350, got %+v", envelopeOf(month, f.rent.ID))
+				}
+			})
+		}
+	})
+
+	t.Run("from an overspent envelope", func(t *testing.T) {
+		f := newEnvelopeFixture()
+
+		_, err := f.service.Move(testutils.TestUserID, services.MoveEnvelopeRequest{FromBudgetID: f.food.ID, ToBudgetID: f.rent.ID, Month: envelopeFebruary, Amount: money.FromMajor(10)})
+		if err == nil || len(f.allocations) != 2 {
+			t.Errorf("Expected the move to be rejected, got %v", err)
+		}
+	})
+}
+
+func TestEnvelopeService_Cover(t *testing.T) {
+	t.Run("whole overspending", func(t *testing.T) {
+		f := newEnvelopeFixture()
+
+		month, err := f.service.Cover(testutils.TestUserID, services.CoverEnvelopeRequest{BudgetID: f.food.ID, FromBudgetID: f.rent.ID, Month: envelopeFebruary})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if in := f.allocations[3]; in.Amount != money.FromMajor(70) || in.Kind != models.EnvelopeAllocationCover {
+			t.Errorf("Expected 70 to cover Food, got %+v", in)
+		}
+		if food := envelopeOf(month, f.food.ID); food.Available != 0 || food.IsOverspent || month.Overspent != 0 {
+			t.Errorf("Expected Food to be covered, got %+v", food)
+		}
+	})
+
+	t.Run("envelope is not overspent", func(t *testing.T) {
+		f := newEnvelopeFixture()
+
+		_, err := f.service.Cover(testutils.TestUserID, services.CoverEnvelopeRequest{BudgetID: f.rent.ID, FromBudgetID: f.food.ID, Month: envelopeFebruary})
+		if err == nil || len(f.allocations) != 2 {
+			t.Errorf("Expected the cover to be rejected, got %v", err)
+		}
+	})
+}
+
+// raceOnLock runs race the first time the user's row is locked, as if another
+// request held the lock and finished first
+func (f *envelopeFixture) raceOnLock(race func()) {
+	once := testutils.RaceOnce(race)
+	f.userRepo.FindByIDForUpdateFunc = func(id uuid.UUID) (*models.User, error) {
+		once()
+		return f.userRepo.FindByID(id)
+	}
+}
+
+func TestEnvelopeService_Concurrent(t *testing.T) {
+	t.Run("assigns check ready to assign in turn", func(t *testing.T) {
+		f := newEnvelopeFixture()
+		var raceErr error
+		f.raceOnLock(func() {
+			_, raceErr = f.service.Assign(testutils.TestUserID, services.AssignEnvelopeRequest{BudgetID: f.rent.ID, Month: envelopeFebruary, Amount: money.FromMajor(400)})
+		})
+
+		// Only 100 is left to assign once the other request has taken 400
+		_, err := f.service.Assign(testutils.TestUserID, services.AssignEnvelopeRequest{BudgetID: f.food.ID, Month: envelopeFebruary, Amount: money.FromMajor(300)})
+		if raceErr != nil {
+			t.Fatalf("Unexpected error: %v", raceErr)
+		}
+		if err == nil || len(f.allocations) != 3 {
+			t.Errorf("Expected the second assignment to be rejected, got %v with %d allocations", err, len(f.allocations))
+		}
+	})
+
+	t.Run("moves check the source in turn", func(t *testing.T) {
+		f := newEnvelopeFixture()
+		var raceErr error
+		f.raceOnLock(func() {
+			_, raceErr = f.service.Move(testutils.TestUserID, services.MoveEnvelopeRequest{FromBudgetID: f.rent.ID, ToBudgetID: f.food.ID, Month: envelopeFebruary, Amount: money.FromMajor(300)})
+		})
+
+		_, err := f.service.Move(testutils.TestUserID, services.MoveEnvelopeRequest{FromBudgetID: f.rent.ID, ToBudgetID: f.food.ID, Month: envelopeFebruary, Amount: money.FromMajor(300)})
+		if raceErr != nil {
+			t.Fatalf("Unexpected error: %v", raceErr)
+		}
+		if err == nil || len(f.allocations) != 4 {
+			t.Errorf("Expected the second move to be rejected, got %v with %d allocations", err, len(f.allocations))
+		}
+	})
+
+	t.Run("covers once", func(t *testing.T) {
+		f := newEnvelopeFixture()
+		var raceErr error
+		f.raceOnLock(func() {
+			_, raceErr = f.service.Cover(testutils.TestUserID, services.CoverEnvelopeRequest{BudgetID: f.food.ID, FromBudgetID: f.rent.ID, Month: envelopeFebruary})
+		})
+
+		_, err := f.service.Cover(testutils.TestUserID, services.CoverEnvelopeRequest{BudgetID: f.food.ID, FromBudgetID: f.rent.ID, Month: envelopeFebruary})
+		if raceErr != nil {
+			t.Fatalf("Unexpected error: %v", raceErr)
+		}
+		if err == nil || len(f.allocations) != 4 {
+			t.Errorf("Expected Food to be covered only once, got %v with %d allocations", err, len(f.allocations))
+		}
+	})
+}
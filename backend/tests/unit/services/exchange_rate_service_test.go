@@ -0,0 +1,203 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+	"github.com/nyunja/fity-budget-backend/tests/unit/mocks"
+)
+
+// latestRate mimics ExchangeRateRepository.FindLatest over an in-memory list
+func latestRate(rates []*models.ExchangeRate, baseCurrency, quoteCurrency string, on time.Time) *models.ExchangeRate {
+	var latest *models.ExchangeRate
+	for _, rate := range rates {
+		if rate.BaseCurrency != baseCurrency || rate.QuoteCurrency != quoteCurrency || rate.RateDate.After(on) {
+			continue
+		}
+		if latest == nil || rate.RateDate.After(latest.RateDate) {
+			latest = rate
+		}
+	}
+	return latest
+}
+
+func rateRepository(rates ...*models.ExchangeRate) *mocks.MockExchangeRateRepository {
+	return &mocks.MockExchangeRateRepository{
+		FindLatestFunc: func(baseCurrency, quoteCurrency string, on time.Time) (*models.ExchangeRate, error) {
+			return latestRate(rates, baseCurrency, quoteCurrency, on), nil
+		},
+	}
+}
+
+func mustRate(s string) money.Rate {
+	rate, err := money.ParseRate(s)
+	if err != nil {
+		panic(err)
+	}
+	return rate
+}
+
+func day(s string) time.Time {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestExchangeRateService_ImportRates_CSV(t *testing.T) {
+	var stored []*models.ExchangeRate
+	service := services.NewExchangeRateService(&mocks.MockExchangeRateRepository{
+		UpsertFunc: func(rates []*models.ExchangeRate) error {
+			stored = rates
+			return nil
+		},
+	})
+
+	file := "Date, Base, Quote, Rate\n" +
+		"2026-10-14,usd,kes,129.25\n" +
+		"2026-10-15,USD,KES,129.40\n" +
+		"2026-10-15,EUR,USD,1.0921\n"
+
+	result, err := service.ImportRates(strings.NewReader(file), services.RateFormatCSV)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if result.Imported != 3 || len(stored) != 3 {
+		t.Fatalf("Expected 3 rates to be imported, got %d (%d stored)", result.Imported, len(stored))
+	}
+	if stored[0].BaseCurrency != "USD" || stored[0].QuoteCurrency != "KES" || stored[0].Rate != mustRate("129.25") {
+		t.Errorf("Unexpected first rate: %+v", stored[0])
+	}
+	if !result.FirstDate.Equal(day("2026-10-14")) || !result.LastDate.Equal(day("2026-10-15")) {
+		t.Errorf("Expected rates from 2026-10-14 to 2026-10-15, got %v to %v", result.FirstDate, result.LastDate)
+	}
+	if strings.Join(result.Currencies, ",") != "EUR,KES,USD" {
+		t.Errorf("Expected currencies EUR,KES,USD, got %v", result.Currencies)
+	}
+}
+
+func TestExchangeRateService_ImportRates_ECB(t *testing.T) {
+	var stored []*models.ExchangeRate
+	service := services.NewExchangeRateService(&mocks.MockExchangeRateRepository{
+		UpsertFunc: func(rates []*models.ExchangeRate) error {
+			stored = rates
+			return nil
+		},
+	})
+
+	file := `<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<gesmes:Sender><gesmes:name>European Central Bank</gesmes:name></gesmes:Sender>
+	<Cube>
+		<Cube time="2026-10-15">
+			<Cube currency="USD" rate="1.0921"/>
+			<Cube currency="JPY" rate="163.45"/>
+		</Cube>
+		<Cube time="2026-10-14">
+			<Cube currency="USD" rate="1.0898"/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`
+
+	result, err := service.ImportRates(strings.NewReader(file), services.RateFormatECB)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if result.Imported != 3 {
+		t.Fatalf("Expected 3 rates to be imported, got %d", result.Imported)
+	}
+	for _, rate := range stored {
+		if rate.BaseCurrency != "EUR" || rate.Source != services.RateFormatECB {
+			t.Errorf("Expected EUR based ECB rates, got %+v", rate)
+		}
+	}
+	if stored[1].QuoteCurrency != "JPY" || stored[1].Rate != mustRate("163.45") || !stored[1].RateDate.Equal(day("2026-10-15")) {
+		t.Errorf("Unexpected JPY rate: %+v", stored[1])
+	}
+}
+
+func TestExchangeRateService_ImportRates_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		file   string
+	}{
+		{name: "missing column", format: services.RateFormatCSV, file: "date,base,rate\n2026-10-15,USD,129\n"},
+		{name: "bad date", format: services.RateFormatCSV, file: "date,base,quote,rate\n15/10/2026,USD,KES,129\n"},
+		{name: "negative rate", format: services.RateFormatCSV, file: "date,base,quote,rate\n2026-10-15,USD,KES,-1\n"},
+		{name: "same currency", format: services.RateFormatCSV, file: "date,base,quote,rate\n2026-10-15,KES,KES,1\n"},
+		{name: "no rows", format: services.RateFormatCSV, file: "date,base,quote,rate\n"},
+		{name: "malformed xml", format: services.RateFormatECB, file: "<Envelope><Cube>"},
+		{name: "unknown format", format: "json", file: "{}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upserted := false
+			service := services.NewExchangeRateService(&mocks.MockExchangeRateRepository{
+				UpsertFunc: func(rates []*models.ExchangeRate) error {
+					upserted = true
+					return nil
+				},
+			})
+
+			if _, err := service.ImportRates(strings.NewReader(tt.file), tt.format); err == nil {
+				t.Error("Expected error")
+			}
+			if upserted {
+				t.Error("Expected nothing to be stored")
+			}
+		})
+	}
+}
+
+func TestExchangeRateService_Convert(t *testing.T) {
+	service := services.NewExchangeRateService(rateRepository(
+		&models.ExchangeRate{BaseCurrency: "EUR", QuoteCurrency: "USD", Rate: mustRate("1.08"), RateDate: day("2026-10-14")},
+		&models.ExchangeRate{BaseCurrency: "EUR", QuoteCurrency: "JPY", Rate: mustRate("162"), RateDate: day("2026-10-15")},
+		&models.ExchangeRate{BaseCurrency: "USD", QuoteCurrency: "KES", Rate: mustRate("129.5"), RateDate: day("2026-10-15")},
+	))
+
+	tests := []struct {
+		name     string
+		amount   money.Amount
+		from, to string
+		on       time.Time
+		expected money.Amount
+		rateDate time.Time
+	}{
+		{name: "direct", amount: money.FromMajor(10), from: "USD", to: "KES", on: day("2026-10-16"), expected: money.FromMajor(1295), rateDate: day("2026-10-15")},
+		{name: "inverse", amount: money.FromMajor(1295), from: "KES", to: "USD", on: day("2026-10-16"), expected: money.FromMajor(10), rateDate: day("2026-10-15")},
+		{name: "cross through EUR rounds to yen", amount: money.FromMajor(100), from: "USD", to: "JPY", on: day("2026-10-16"), expected: money.FromMajor(15000), rateDate: day("2026-10-14")},
+		{name: "same currency", amount: money.MustParse("12.34"), from: "kes", to: "KES", on: day("2026-10-16"), expected: money.MustParse("12.34"), rateDate: day("2026-10-16")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converted, applied, err := service.Convert(tt.amount, tt.from, tt.to, tt.on)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if converted != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, converted)
+			}
+			if !applied.RateDate.Equal(tt.rateDate) {
+				t.Errorf("Expected rate date %v, got %v", tt.rateDate, applied.RateDate)
+			}
+		})
+	}
+
+	// Rates quoted after the requested day are never used
+	if _, _, err := service.Convert(money.FromMajor(10), "USD", "KES", day("2026-10-14")); !errors.Is(err, services.ErrNoExchangeRate) {
+		t.Errorf("Expected a missing exchange rate error, got %v", err)
+	}
+}
@@ -0,0 +1,368 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/imports"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"github.com/nyunja/fity-budget-backend/internal/repository"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+	"github.com/nyunja/fity-budget-backend/tests/unit/mocks"
+	"github.com/nyunja/fity-budget-backend/tests/unit/testutils"
+)
+
+// importFixture wires an import service to an in-memory wallet, its
+// transactions, the stored import jobs and the user's rules
+type importFixture struct {
+	service      services.ImportService
+	wallet       *models.Wallet
+	transactions []*models.Transaction
+	jobs         map[uuid.UUID]*models.ImportJob
+	rules        []*models.Rule
+}
+
+func newImportFixture() *importFixture {
+	wallets := testutils.NewWalletStore(&models.Wallet{ID: testutils.TestWalletID, UserID: testutils.TestUserID, Name: "M-Pesa", Type: models.WalletTypeMobileMoney, Balance: money.FromMajor(1000), Currency: "KES"})
+	f := &importFixture{
+		wallet: wallets.Wallets[testutils.TestWalletID],
+		jobs:   make(map[uuid.UUID]*models.ImportJob),
+	}
+
+	walletRepo := wallets.Repo()
+	walletRepo.FindByUserIDFunc = func(userID uuid.UUID) ([]*models.Wallet, error) {
+		cash := &models.Wallet{ID: uuid.New(), UserID: userID, Type: models.WalletTypeCash, IsDefault: true}
+		found := *f.wallet
+		return []*models.Wallet{cash, &found}, nil
+	}
+	walletRepo.UpdateFunc = func(wallet *models.Wallet) error {
+		*f.wallet = *wallet
+		return nil
+	}
+	transactionRepo := &mocks.MockTransactionRepository{
+		CreateBatchFunc: func(transactions []*models.Transaction) error {
+			f.transactions = append(f.transactions, transactions...)
+			return nil
+		},
+		FindByFilterFunc: func(filter repository.TransactionFilter, sort repository.TransactionSort, limit, offset int) ([]*models.Transaction, error) {
+			var found []*models.Transaction
+			for _, txn := range f.transactions {
+				if testutils.MatchesFilter(txn, filter) {
+					found = append(found, txn)
+				}
+			}
+			return found, nil
+		},
+		DeleteByImportJobIDFunc: func(jobID uuid.UUID) error {
+			var kept []*models.Transaction
+			for _, txn := range f.transactions {
+				if txn.ImportJobID == nil || *txn.ImportJobID != jobID {
+					kept = append(kept, txn)
+				}
+			}
+			f.transactions = kept
+			return nil
+		},
+	}
+	importRepo := &mocks.MockImportJobRepository{
+		CreateFunc: func(job *models.ImportJob) error {
+			job.ID = uuid.New()
+			f.jobs[job.ID] = job
+			return nil
+		},
+		FindByIDFunc: func(id uuid.UUID) (*models.ImportJob, error) {
+			job, ok := f.jobs[id]
+			if !ok {
+				return nil, errors.New("record not found")
+			}
+			return job, nil
+		},
+	}
+
+	ruleRepo := &mocks.MockRuleRepository{
+		FindActiveByUserIDFunc: func(userID uuid.UUID) ([]*models.Rule, error) {
+			return f.rules, nil
+		},
+	}
+
+	f.service = services.NewImportService(importRepo, transactionRepo, walletRepo, ruleRepo, &mocks.MockTxManager{})
+	return f
+}
+
+// seed stores an existing wallet transaction without touching the balance
+func (f *importFixture) seed(transaction models.Transaction) {
+	transaction.ID = uuid.New()
+	transaction.UserID = testutils.TestUserID
+	transaction.WalletID = walletPtr(f.wallet.ID)
+	f.transactions = append(f.transactions, &transaction)
+}
+
+const importStatement = `Date,Description,Amount,Reference
+2024-03-02,Naivas Supermarket,-1500.00,REF1
+2024-03-02,Naivas Supermarket,-1500.00,REF2
+2024-03-05,Salary,5000.00,REF3
+2024-03-06,Airtime,-100.00,REF4
+not a date,Broken,-1.00,
+`
+
+func (f *importFixture) preview(t *testing.T, file string) *models.ImportJob {
+	t.Helper()
+	preview, err := f.service.PreviewImport(testutils.TestUserID, services.PreviewImportRequest{
+		WalletID: f.wallet.ID,
+		FileName: "statement.csv",
+		File:     strings.NewReader(file),
+	})
+	if err != nil {
+		t.Fatalf("PreviewImport() error = %v", err)
+	}
+	return preview.Job
+}
+
+func TestImportService_PreviewImport_FlagsDuplicates(t *testing.T) {
+	f := newImportFixture()
+	// One of the two identical purchases is already recorded, and the
+	// airtime was imported before under its reference
+	f.seed(models.Transaction{Name: "NAIVAS", Amount: money.FromMajor(1500), Type: models.TransactionTypeExpense, TransactionDate: time.Date(2024, 3, 2, 18, 0, 0, 0, time.UTC)})
+	f.seed(models.Transaction{Name: "Airtime", Amount: money.FromMajor(100), Type: models.TransactionTypeExpense, TransactionDate: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), ExternalID: "REF4"})
+
+	job := f.preview(t, importStatement)
+
+	if job.Status != models.ImportStatusPreviewed || job.Source != models.ImportSourceCSV {
+		t.Errorf("job = %s/%s, want Previewed/csv", job.Status, job.Source)
+	}
+	if job.TotalRows != 5 || job.DuplicateRows != 2 || job.ErrorRows != 1 {
+		t.Errorf("rows total/duplicates/errors = %d/%d/%d, want 5/2/1", job.TotalRows, job.DuplicateRows, job.ErrorRows)
+	}
+
+	duplicates := []bool{true, false, false, true, false}
+	for i, want := range duplicates {
+		if got := job.Rows[i].DuplicateOfID != nil; got != want {
+			t.Errorf("row %d duplicate = %v, want %v", i, got, want)
+		}
+	}
+	if f.wallet.Balance != money.FromMajor(1000) || len(f.transactions) != 2 {
+		t.Error("preview must not change the wallet")
+	}
+}
+
+func TestImportService_PreviewImport_Errors(t *testing.T) {
+	f := newImportFixture()
+
+	_, err := f.service.PreviewImport(uuid.New(), services.PreviewImportRequest{
+		WalletID: f.wallet.ID, FileName: "statement.csv", File: strings.NewReader(importStatement),
+	})
+	if err == nil || err.Error() != "unauthorized access to wallet" {
+		t.Errorf("foreign wallet error = %v", err)
+	}
+
+	_, err = f.service.PreviewImport(testutils.TestUserID, services.PreviewImportRequest{
+		WalletID: f.wallet.ID, FileName: "statement.pdf", File: strings.NewReader(importStatement),
+	})
+	if !errors.Is(err, services.ErrUnsupportedImportFormat) {
+		t.Errorf("pdf error = %v, want ErrUnsupportedImportFormat", err)
+	}
+}
+
+func TestImportService_CommitAndUndo(t *testing.T) {
+	f := newImportFixture()
+	f.seed(models.Transaction{Name: "NAIVAS", Amount: money.FromMajor(1500), Type: models.TransactionTypeExpense, TransactionDate: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)})
+	job := f.preview(t, importStatement)
+
+	job, err := f.service.CommitImport(job.ID, testutils.TestUserID, services.CommitImportRequest{})
+	if err != nil {
+		t.Fatalf("CommitImport() error = %v", err)
+	}
+
+	// The duplicate and the unreadable row are skipped
+	if job.Status != models.ImportStatusCompleted || job.ImportedRows != 3 {
+		t.Errorf("job = %s with %d imported, want Completed with 3", job.Status, job.ImportedRows)
+	}
+	if len(f.transactions) != 4 {
+		t.Fatalf("len(transactions) = %d, want 4", len(f.transactions))
+	}
+	imported := f.transactions[1]
+	if imported.ImportJobID == nil || *imported.ImportJobID != job.ID || imported.ExternalID != "REF2" ||
+		imported.Category != "Uncategorized" || imported.Method != "M-Pesa" {
+		t.Errorf("imported transaction = %+v", imported)
+	}
+	if job.Rows[1].TransactionID == nil || *job.Rows[1].TransactionID != imported.ID {
+		t.Error("row should link to the transaction it created")
+	}
+	// 1000 - 1500 + 5000 - 100
+	if want := money.FromMajor(4400); f.wallet.Balance != want {
+		t.Errorf("Balance = %s, want %s", f.wallet.Balance, want)
+	}
+	if f.wallet.LastSynced == nil {
+		t.Error("LastSynced should be set")
+	}
+
+	if _, err := f.service.CommitImport(job.ID, testutils.TestUserID, services.CommitImportRequest{}); err == nil {
+		t.Error("committing twice should fail")
+	}
+
+	job, err = f.service.UndoImport(job.ID, testutils.TestUserID)
+	if err != nil {
+		t.Fatalf("UndoImport() error = %v", err)
+	}
+	if job.Status != models.ImportStatusUndone || job.UndoneAt == nil {
+		t.Errorf("job status = %s, want Undone", job.Status)
+	}
+	if len(f.transactions) != 1 {
+		t.Errorf("len(transactions) = %d, want only the seeded one", len(f.transactions))
+	}
+	if want := money.FromMajor(1000); f.wallet.Balance != want {
+		t.Errorf("Balance after undo = %s, want %s", f.wallet.Balance, want)
+	}
+}
+
+func TestImportService_CommitImport_IncludeDuplicates(t *testing.T) {
+	f := newImportFixture()
+	f.seed(models.Transaction{Name: "NAIVAS", Amount: money.FromMajor(1500), Type: models.TransactionTypeExpense, TransactionDate: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)})
+	job := f.preview(t, importStatement)
+
+	job, err := f.service.CommitImport(job.ID, testutils.TestUserID, services.CommitImportRequest{IncludeDuplicates: true})
+	if err != nil {
+		t.Fatalf("CommitImport() error = %v", err)
+	}
+	if job.ImportedRows != 4 {
+		t.Errorf("ImportedRows = %d, want 4", job.ImportedRows)
+	}
+}
+
+func TestImportService_CommitImport_AppliesRules(t *testing.T) {
+	f := newImportFixture()
+	groceries := models.Tag{ID: uuid.New(), Name: "groceries"}
+	f.rules = []*models.Rule{
+		{ID: uuid.New(), MatchField: models.RuleFieldName, MatchType: models.RuleMatchContains, Pattern: "naivas", SetCategory: "Food & Groceries", AddTags: []models.Tag{groceries}},
+		{ID: uuid.New(), MatchField: models.RuleFieldName, MatchType: models.RuleMatchRegex, Pattern: "^salary$", SetCategory: "Salary"},
+	}
+	job := f.preview(t, importStatement)
+
+	if _, err := f.service.CommitImport(job.ID, testutils.TestUserID, services.CommitImportRequest{}); err != nil {
+		t.Fatalf("CommitImport() error = %v", err)
+	}
+
+	categories := make(map[string]string)
+	for _, txn := range f.transactions {
+		categories[txn.Name] = txn.Category
+		if txn.Name == "Naivas Supermarket" && (len(txn.Tags) != 1 || txn.Tags[0].ID != groceries.ID) {
+			t.Errorf("tags = %+v, want the groceries tag", txn.Tags)
+		}
+	}
+	want := map[string]string{"Naivas Supermarket": "Food & Groceries", "Salary": "Salary", "Airtime": "Uncategorized"}
+	for name, category := range want {
+		if categories[name] != category {
+			t.Errorf("category of %s = %q, want %q", name, categories[name], category)
+		}
+	}
+}
+
+func TestImportService_GetImport_ForeignUser(t *testing.T) {
+	f := newImportFixture()
+	job := f.preview(t, importStatement)
+
+	if _, err := f.service.GetImport(job.ID, uuid.New()); err == nil || err.Error() != "unauthorized access to import" {
+		t.Errorf("GetImport() error = %v", err)
+	}
+	if _, err := f.service.UndoImport(job.ID, testutils.TestUserID); err == nil {
+		t.Error("undoing a previewed import should fail")
+	}
+}
+
+func TestImportService_ReimportingOFXIsIdempotent(t *testing.T) {
+	f := newImportFixture()
+	statement := `<OFX><BANKTRANLIST>
+<STMTTRN><TRNTYPE>DEBIT<DTPOSTED>20240302<TRNAMT>-250.00<FITID>A1<NAME>Shell</STMTTRN>
+<STMTTRN><TRNTYPE>DEBIT<DTPOSTED>20240302<TRNAMT>-250.00<FITID>A2<NAME>Shell</STMTTRN>
+</BANKTRANLIST></OFX>`
+
+	importOFX := func() *models.ImportJob {
+		preview, err := f.service.PreviewImport(testutils.TestUserID, services.PreviewImportRequest{
+			WalletID: f.wallet.ID,
+			FileName: "statement.qfx",
+			File:     strings.NewReader(statement),
+		})
+		if err != nil {
+			t.Fatalf("PreviewImport() error = %v", err)
+		}
+		if preview.Mapping != nil {
+			t.Error("OFX previews have no column mapping")
+		}
+		job, err := f.service.CommitImport(preview.Job.ID, testutils.TestUserID, services.CommitImportRequest{})
+		if err != nil {
+			t.Fatalf("CommitImport() error = %v", err)
+		}
+		return job
+	}
+
+	first := importOFX()
+	if first.Source != models.ImportSourceQFX || first.ImportedRows != 2 {
+		t.Errorf("first import = %s with %d rows, want qfx with 2", first.Source, first.ImportedRows)
+	}
+	if f.transactions[0].ExternalID != "A1" {
+		t.Errorf("ExternalID = %q, want the FITID", f.transactions[0].ExternalID)
+	}
+
+	second := importOFX()
+	if second.ImportedRows != 0 || second.DuplicateRows != 2 {
+		t.Errorf("second import = %d imported, %d duplicates, want 0 and 2", second.ImportedRows, second.DuplicateRows)
+	}
+	if len(f.transactions) != 2 || f.wallet.Balance != money.FromMajor(500) {
+		t.Errorf("wallet holds %d transactions and %s, want 2 and 500.00", len(f.transactions), f.wallet.Balance)
+	}
+}
+
+func TestImportService_ImportMpesaMessages(t *testing.T) {
+	f := newImportFixture()
+	// A manually entered airtime purchase is recognised, but a different
+	// M-PESA code on the same day for the same amount is not
+	f.seed(models.Transaction{Name: "Airtime", Amount: money.FromMajor(100), Type: models.TransactionTypeExpense, TransactionDate: time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC)})
+	f.seed(models.Transaction{Name: "Paid", Amount: money.FromMajor(450), Type: models.TransactionTypeExpense, TransactionDate: time.Date(2024, 3, 7, 0, 0, 0, 0, time.UTC), ExternalID: "QCZ9Z9Z9Z9"})
+
+	messages := `QCA1B2C3D4 Confirmed. Ksh500.00 sent to JOHN DOE 0712345678 on 5/3/24 at 8:15 AM. New M-PESA balance is Ksh487.00. Transaction cost, Ksh13.00.
+QCD1B2C3D7 Confirmed. Ksh450.00 paid to NAIVAS WESTLANDS. on 7/3/24 at 1:05 PM.New M-PESA balance is Ksh37.00. Transaction cost, Ksh0.00.
+QCF1B2C3D9 confirmed.You bought Ksh100.00 of airtime on 9/3/24 at 9:00 AM.New M-PESA balance is Ksh-63.00.`
+
+	job, err := f.service.ImportMpesaMessages(testutils.TestUserID, services.ImportMpesaMessagesRequest{Text: messages})
+	if err != nil {
+		t.Fatalf("ImportMpesaMessages() error = %v", err)
+	}
+	if job.WalletID != f.wallet.ID || job.Source != models.ImportSourceMpesa || job.Status != models.ImportStatusCompleted {
+		t.Errorf("job = wallet %s, %s, %s", job.WalletID, job.Source, job.Status)
+	}
+	// Sent, its fee and the till payment; the airtime was already recorded
+	if job.ImportedRows != 3 || job.DuplicateRows != 1 {
+		t.Errorf("job imported %d with %d duplicates, want 3 and 1", job.ImportedRows, job.DuplicateRows)
+	}
+	// 1000 - 500 - 13 - 450
+	if want := money.FromMajor(37); f.wallet.Balance != want {
+		t.Errorf("Balance = %s, want %s", f.wallet.Balance, want)
+	}
+
+	// Pasting the same messages again imports nothing
+	again, err := f.service.ImportMpesaMessages(testutils.TestUserID, services.ImportMpesaMessagesRequest{Text: messages})
+	if err != nil {
+		t.Fatalf("ImportMpesaMessages() error = %v", err)
+	}
+	if again.ImportedRows != 0 || again.DuplicateRows != 4 {
+		t.Errorf("second paste imported %d with %d duplicates, want 0 and 4", again.ImportedRows, again.DuplicateRows)
+	}
+}
+
+func TestImportService_ImportMpesaMessages_Errors(t *testing.T) {
+	f := newImportFixture()
+
+	if _, err := f.service.ImportMpesaMessages(testutils.TestUserID, services.ImportMpesaMessagesRequest{Text: "  "}); !errors.Is(err, imports.ErrNoRecords) {
+		t.Errorf("empty text error = %v, want ErrNoRecords", err)
+	}
+
+	f.wallet.Type = models.WalletTypeBank
+	_, err := f.service.ImportMpesaMessages(testutils.TestUserID, services.ImportMpesaMessagesRequest{Text: "QCA1B2C3D4 Confirmed."})
+	if err == nil || !strings.Contains(err.Error(), "no mobile money wallet") {
+		t.Errorf("error = %v, want no mobile money wallet", err)
+	}
+}
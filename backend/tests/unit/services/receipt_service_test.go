@@ -0,0 +1,281 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+	"github.com/nyunja/fity-budget-backend/internal/storage"
+	"github.com/nyunja/fity-budget-backend/tests/unit/mocks"
+	"github.com/nyunja/fity-budget-backend/tests/unit/testutils"
+	"gorm.io/gorm"
+)
+
+// receiptFixture keeps receipts and stored files in memory for one
+// transaction owned by the test user
+type receiptFixture struct {
+	transaction *models.Transaction
+	receipts    map[uuid.UUID]*models.Receipt
+	files       map[string][]byte
+	service     services.ReceiptService
+}
+
+func newReceiptFixture() *receiptFixture {
+	f := &receiptFixture{
+		transaction: &models.Transaction{ID: uuid.New(), UserID: testutils.TestUserID, Name: "Naivas"},
+		receipts:    make(map[uuid.UUID]*models.Receipt),
+		files:       make(map[string][]byte),
+	}
+
+	receiptRepo := &mocks.MockReceiptRepository{
+		CreateFunc: func(receipt *models.Receipt) error {
+			f.receipts[receipt.ID] = receipt
+			return nil
+		},
+		FindByIDFunc: func(id uuid.UUID) (*models.Receipt, error) {
+			if receipt, ok := f.receipts[id]; ok {
+				return receipt, nil
+			}
+			return nil, gorm.ErrRecordNotFound
+		},
+		FindByTransactionIDFunc: func(transactionID uuid.UUID) ([]*models.Receipt, error) {
+			var receipts []*models.Receipt
+			for _, receipt := range f.receipts {
+				if receipt.TransactionID == transactionID {
+					receipts = append(receipts, receipt)
+				}
+			}
+			return receipts, nil
+		},
+		DeleteFunc: func(id uuid.UUID) error {
+			delete(f.receipts, id)
+			return nil
+		},
+	}
+	transactionRepo := &mocks.MockTransactionRepository{
+		FindByIDFunc: func(id uuid.UUID) (*models.Transaction, error) {
+			if id == f.transaction.ID {
+				return f.transaction, nil
+			}
+			return nil, gorm.ErrRecordNotFound
+		},
+	}
+	store := &mocks.MockStorage{
+		PutFunc: func(key string, r io.Reader) error {
+			data, err := io.ReadAll(r)
+			f.files[key] = data
+			return err
+		},
+		OpenFunc: func(key string) (io.ReadCloser, error) {
+			data, ok := f.files[key]
+			if !ok {
+				return nil, storage.ErrNotFound
+			}
+			return io.NopCloser(bytes.NewReader(data)), nil
+		},
+		DeleteFunc: func(key string) error {
+			delete(f.files, key)
+			return nil
+		},
+	}
+
+	f.service = services.NewReceiptService(receiptRepo, transactionRepo, store)
+	return f
+}
+
+// receiptPNG encodes a half transparent PNG of the given size
+func receiptPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if x < width/2 {
+				img.Set(x, y, color.NRGBA{R: 200, G: 30, B: 30, A: 255})
+			}
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("Failed to encode PNG: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestReceiptService_UploadReceipt(t *testing.T) {
+	f := newReceiptFixture()
+
+	receipt, err := f.service.UploadReceipt(testutils.TestUserID, f.transaction.ID, services.UploadReceiptRequest{
+		FileName: "../../scans/naivas.png",
+		File:     bytes.NewReader(receiptPNG(t, 800, 400)),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if receipt.ContentType != "image/png" || receipt.FileName != "naivas.png" || !receipt.HasThumbnail {
+		t.Errorf("Unexpected receipt %+v", receipt)
+	}
+	if f.receipts[receipt.ID] == nil || len(f.files[receipt.StorageKey]) != int(receipt.Size) {
+		t.Fatal("Expected the receipt and its file to be stored")
+	}
+
+	thumbnail, err := jpeg.Decode(bytes.NewReader(f.files[receipt.ThumbnailKey]))
+	if err != nil {
+		t.Fatalf("Expected a JPEG thumbnail: %v", err)
+	}
+	if bounds := thumbnail.Bounds(); bounds.Dx() != 320 || bounds.Dy() != 160 {
+		t.Errorf("Expected a 320x160 thumbnail, got %dx%d", bounds.Dx(), bounds.Dy())
+	}
+	// The transparent half is flattened onto white
+	if r, g, b, _ := thumbnail.At(300, 80).RGBA(); r>>8 < 240 || g>>8 < 240 || b>>8 < 240 {
+		t.Errorf("Expected a white background, got %d,%d,%d", r>>8, g>>8, b>>8)
+	}
+
+	pdf, err := f.service.UploadReceipt(testutils.TestUserID, f.transaction.ID, services.UploadReceiptRequest{
+		FileName: "invoice.pdf",
+		File:     strings.NewReader("%PDF-1.4\n1 0 obj\n<<>>\nendobj\n%%EOF\n"),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if pdf.ContentType != "application/pdf" || pdf.HasThumbnail || pdf.ThumbnailKey != "" {
+		t.Errorf("Expected a PDF without thumbnail, got %+v", pdf)
+	}
+	if len(f.files) != 3 {
+		t.Errorf("Expected 3 stored files, got %d", len(f.files))
+	}
+}
+
+func TestReceiptService_UploadReceipt_Rejected(t *testing.T) {
+	tests := []struct {
+		name    string
+		userID  uuid.UUID
+		content []byte
+		wantErr error
+	}{
+		{
+			name:    "text file",
+			userID:  testutils.TestUserID,
+			content: []byte("date,amount\n2026-10-01,100\n"),
+			wantErr: services.ErrUnsupportedReceiptType,
+		},
+		{
+			name:    "html named as an image",
+			userID:  testutils.TestUserID,
+			content: []byte("<html><script>alert(1)</script></html>"),
+			wantErr: services.ErrUnsupportedReceiptType,
+		},
+		{
+			name:    "file over the size limit",
+			userID:  testutils.TestUserID,
+			content: append([]byte("%PDF-1.4\n"), make([]byte, services.MaxReceiptSize)...),
+			wantErr: services.ErrReceiptTooLarge,
+		},
+		{
+			name:    "corrupt image",
+			userID:  testutils.TestUserID,
+			content: append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 64)...),
+		},
+		{
+			name:    "empty file",
+			userID:  testutils.TestUserID,
+			content: []byte{},
+		},
+		{
+			name:    "another user's transaction",
+			userID:  uuid.New(),
+			content: []byte("%PDF-1.4\n"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newReceiptFixture()
+
+			_, err := f.service.UploadReceipt(tt.userID, f.transaction.ID, services.UploadReceiptRequest{
+				FileName: "receipt.png",
+				File:     bytes.NewReader(tt.content),
+			})
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+			if len(f.files) != 0 || len(f.receipts) != 0 {
+				t.Error("Expected nothing to be stored")
+			}
+		})
+	}
+}
+
+func TestReceiptService_OpenReceipt(t *testing.T) {
+	f := newReceiptFixture()
+	pdf, err := f.service.UploadReceipt(testutils.TestUserID, f.transaction.ID, services.UploadReceiptRequest{
+		FileName: "invoice.pdf",
+		File:     strings.NewReader("%PDF-1.4\n%%EOF\n"),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	file, err := f.service.OpenReceipt(pdf.ID, testutils.TestUserID, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer file.Body.Close()
+	data, _ := io.ReadAll(file.Body)
+	if file.ContentType != "application/pdf" || string(data) != "%PDF-1.4\n%%EOF\n" {
+		t.Errorf("Unexpected file %s %q", file.ContentType, data)
+	}
+
+	if _, err := f.service.OpenReceipt(pdf.ID, testutils.TestUserID, true); err == nil {
+		t.Error("Expected error opening the thumbnail of a PDF")
+	}
+	if _, err := f.service.OpenReceipt(pdf.ID, uuid.New(), false); err == nil {
+		t.Error("Expected error opening another user's receipt")
+	}
+}
+
+func TestReceiptService_DeleteReceipts(t *testing.T) {
+	f := newReceiptFixture()
+	upload := func(content []byte) *models.Receipt {
+		receipt, err := f.service.UploadReceipt(testutils.TestUserID, f.transaction.ID, services.UploadReceiptRequest{File: bytes.NewReader(content)})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return receipt
+	}
+	scan := upload(receiptPNG(t, 40, 40))
+	upload([]byte("%PDF-1.4\n%%EOF\n"))
+	upload([]byte("%PDF-1.7\n%%EOF\n"))
+
+	if scan.FileName != "receipt.png" {
+		t.Errorf("Expected a generic file name, got %q", scan.FileName)
+	}
+
+	if err := f.service.DeleteReceipt(scan.ID, uuid.New()); err == nil {
+		t.Error("Expected error deleting another user's receipt")
+	}
+	if err := f.service.DeleteReceipt(scan.ID, testutils.TestUserID); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := f.files[scan.ThumbnailKey]; ok || len(f.receipts) != 2 || len(f.files) != 2 {
+		t.Errorf("Expected the image and its thumbnail to be removed, %d files left", len(f.files))
+	}
+
+	if err := f.service.DeleteTransactionReceipts(f.transaction.ID); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(f.receipts) != 0 || len(f.files) != 0 {
+		t.Errorf("Expected every receipt to be removed, %d left", len(f.receipts))
+	}
+}
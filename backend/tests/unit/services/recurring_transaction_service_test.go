@@ -0,0 +1,556 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+	"github.com/nyunja/fity-budget-backend/tests/unit/mocks"
+	"github.com/nyunja/fity-budget-backend/tests/unit/testutils"
+	"gorm.io/gorm"
+)
+
+// recurringFixture wires a recurring transaction service to an in-memory
+// wallet, the stored schedules and the transactions they post
+type recurringFixture struct {
+	service       services.RecurringTransactionService
+	recurringRepo *mocks.MockRecurringTransactionRepository
+	wallet        *models.Wallet
+	wallets       map[uuid.UUID]*models.Wallet
+	recurring     map[uuid.UUID]*models.RecurringTransaction
+	transactions  []*models.Transaction
+}
+
+func newRecurringFixture() *recurringFixture {
+	wallets := testutils.NewWalletStore(&models.Wallet{ID: testutils.TestWalletID, UserID: testutils.TestUserID, Balance: money.FromMajor(10000), Currency: "KES"})
+	f := &recurringFixture{
+		wallet:    wallets.Wallets[testutils.TestWalletID],
+		wallets:   wallets.Wallets,
+		recurring: make(map[uuid.UUID]*models.RecurringTransaction),
+	}
+
+	transactionRepo := &mocks.MockTransactionRepository{
+		CreateFunc: func(transaction *models.Transaction) error {
+			// Mirror the unique index on schedule and date
+			for _, txn := range f.transactions {
+				if txn.RecurringID != nil && transaction.RecurringID != nil &&
+					*txn.RecurringID == *transaction.RecurringID && txn.TransactionDate.Equal(transaction.TransactionDate) {
+					return errors.New("duplicate key value violates unique constraint")
+				}
+			}
+			f.transactions = append(f.transactions, transaction)
+			return nil
+		},
+	}
+	recurringRepo := &mocks.MockRecurringTransactionRepository{
+		CreateFunc: func(recurring *models.RecurringTransaction) error {
+			recurring.ID = uuid.New()
+			stored := *recurring
+			f.recurring[recurring.ID] = &stored
+			return nil
+		},
+		FindByIDFunc: func(id uuid.UUID) (*models.RecurringTransaction, error) {
+			recurring, ok := f.recurring[id]
+			if !ok {
+				return nil, gorm.ErrRecordNotFound
+			}
+			found := *recurring
+			return &found, nil
+		},
+		FindByUserIDFunc: func(userID uuid.UUID) ([]*models.RecurringTransaction, error) {
+			var found []*models.RecurringTransaction
+			for _, recurring := range f.recurring {
+				if recurring.UserID == userID {
+					copied := *recurring
+					found = append(found, &copied)
+				}
+			}
+			return found, nil
+		},
+		FindDueFunc: func(now time.Time, limit int) ([]*models.RecurringTransaction, error) {
+			var found []*models.RecurringTransaction
+			for _, recurring := range f.recurring {
+				if recurring.IsActive && recurring.NextOccurrence != nil && !recurring.NextOccurrence.After(now) {
+					copied := *recurring
+					found = append(found, &copied)
+				}
+			}
+			return found, nil
+		},
+		UpdateFunc: func(recurring *models.RecurringTransaction) error {
+			stored := *recurring
+			f.recurring[recurring.ID] = &stored
+			return nil
+		},
+	}
+
+	f.recurringRepo = recurringRepo
+	f.service = services.NewRecurringTransactionService(recurringRepo, transactionRepo, wallets.Repo(), &mocks.MockTxManager{})
+	return f
+}
+
+// seed stores a schedule that started in the past, as if it had been created then
+func (f *recurringFixture) seed(recurring models.RecurringTransaction) *models.RecurringTransaction {
+	recurring.ID = uuid.New()
+	recurring.UserID = testutils.TestUserID
+	recurring.WalletID = walletPtr(f.wallet.ID)
+	recurring.IsActive = true
+	if recurring.Interval == 0 {
+		recurring.Interval = 1
+	}
+	recurring.ScheduleFrom(recurring.StartDate)
+	f.recurring[recurring.ID] = &recurring
+	return &recurring
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestRecurringTransactionService_PreviewOccurrences(t *testing.T) {
+	tests := []struct {
+		name       string
+		frequency  string
+		interval   int
+		dayOfMonth int
+		start      time.Time
+		end        *time.Time
+		max        *int
+		expected   []time.Time
+	}{
+		{
+			name:      "daily every third day",
+			frequency: models.FrequencyDaily,
+			interval:  3,
+			start:     date(2030, time.March, 30),
+			expected:  []time.Time{date(2030, time.March, 30), date(2030, time.April, 2), date(2030, time.April, 5)},
+		},
+		{
+			name:      "fortnightly",
+			frequency: models.FrequencyWeekly,
+			interval:  2,
+			start:     date(2030, time.January, 4),
+			expected:  []time.Time{date(2030, time.January, 4), date(2030, time.January, 18), date(2030, time.February, 1)},
+		},
+		{
+			name:      "monthly on the 31st moves back in short months without drifting",
+			frequency: models.FrequencyMonthly,
+			start:     date(2032, time.January, 31),
+			expected:  []time.Time{date(2032, time.January, 31), date(2032, time.February, 29), date(2032, time.March, 31), date(2032, time.April, 30)},
+		},
+		{
+			name:       "last day of the month",
+			frequency:  models.FrequencyMonthly,
+			dayOfMonth: models.LastDayOfMonth,
+			start:      date(2031, time.January, 10),
+			expected:   []time.Time{date(2031, time.January, 31), date(2031, time.February, 28), date(2031, time.March, 31)},
+		},
+		{
+			name:       "day of month before the start day begins a period later",
+			frequency:  models.FrequencyMonthly,
+			interval:   3,
+			dayOfMonth: 5,
+			start:      date(2030, time.January, 20),
+			expected:   []time.Time{date(2030, time.April, 5), date(2030, time.July, 5), date(2030, time.October, 5)},
+		},
+		{
+			name:      "yearly on a leap day",
+			frequency: models.FrequencyYearly,
+			start:     date(2032, time.February, 29),
+			expected:  []time.Time{date(2032, time.February, 29), date(2033, time.February, 28), date(2034, time.February, 28), date(2035, time.February, 28), date(2036, time.February, 29)},
+		},
+		{
+			name:      "end date is inclusive",
+			frequency: models.FrequencyWeekly,
+			start:     date(2030, time.June, 1),
+			end:       timePtr(date(2030, time.June, 15)),
+			expected:  []time.Time{date(2030, time.June, 1), date(2030, time.June, 8), date(2030, time.June, 15)},
+		},
+		{
+			name:      "occurrence limit",
+			frequency: models.FrequencyMonthly,
+			start:     date(2030, time.June, 1),
+			max:       intPtr(2),
+			expected:  []time.Time{date(2030, time.June, 1), date(2030, time.July, 1)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newRecurringFixture()
+			recurring, err := f.service.CreateRecurring(testutils.TestUserID, services.CreateRecurringRequest{
+				WalletID:       walletPtr(f.wallet.ID),
+				Amount:         money.FromMajor(100),
+				Name:           "Subscription",
+				Method:         "Card",
+				Category:       "Bills",
+				Frequency:      tt.frequency,
+				Interval:       tt.interval,
+				DayOfMonth:     tt.dayOfMonth,
+				StartDate:      tt.start,
+				EndDate:        tt.end,
+				MaxOccurrences: tt.max,
+			})
+			if err != nil {
+				t.Fatalf("CreateRecurring() error = %v", err)
+			}
+			if recurring.NextOccurrence == nil || !recurring.NextOccurrence.Equal(tt.expected[0]) {
+				t.Errorf("NextOccurrence = %v, want %v", recurring.NextOccurrence, tt.expected[0])
+			}
+
+			// Bounded schedules are asked for more dates than they have
+			limit := len(tt.expected)
+			if tt.end != nil || tt.max != nil {
+				limit = 10
+			}
+			dates, err := f.service.PreviewOccurrences(recurring.ID, testutils.TestUserID, limit)
+			if err != nil {
+				t.Fatalf("PreviewOccurrences() error = %v", err)
+			}
+			if len(dates) != len(tt.expected) {
+				t.Fatalf("PreviewOccurrences() = %v, want %v", dates, tt.expected)
+			}
+			for i := range dates {
+				if !dates[i].Equal(tt.expected[i]) {
+					t.Errorf("occurrence %d = %v, want %v", i, dates[i], tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRecurringTransactionService_CreateRecurring_Errors(t *testing.T) {
+	future := date(2030, time.January, 1)
+
+	tests := []struct {
+		name   string
+		modify func(req *services.CreateRecurringRequest)
+	}{
+		{
+			name:   "unknown frequency",
+			modify: func(req *services.CreateRecurringRequest) { req.Frequency = "hourly" },
+		},
+		{
+			name:   "negative interval",
+			modify: func(req *services.CreateRecurringRequest) { req.Interval = -1 },
+		},
+		{
+			name: "day of month on a weekly schedule",
+			modify: func(req *services.CreateRecurringRequest) {
+				req.Frequency = models.FrequencyWeekly
+				req.DayOfMonth = 3
+			},
+		},
+		{
+			name:   "day of month out of range",
+			modify: func(req *services.CreateRecurringRequest) { req.DayOfMonth = 32 },
+		},
+		{
+			name:   "end before start",
+			modify: func(req *services.CreateRecurringRequest) { req.EndDate = timePtr(future.AddDate(0, 0, -1)) },
+		},
+		{
+			name:   "transfer type",
+			modify: func(req *services.CreateRecurringRequest) { req.Type = models.TransactionTypeTransfer },
+		},
+		{
+			name:   "zero amount",
+			modify: func(req *services.CreateRecurringRequest) { req.Amount = 0 },
+		},
+		{
+			name:   "foreign wallet",
+			modify: func(req *services.CreateRecurringRequest) { req.WalletID = walletPtr(foreignWalletID) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newRecurringFixture()
+			req := services.CreateRecurringRequest{
+				WalletID:  walletPtr(f.wallet.ID),
+				Amount:    money.FromMajor(100),
+				Name:      "Rent",
+				Method:    "Bank",
+				Category:  "Housing",
+				Frequency: models.FrequencyMonthly,
+				StartDate: future,
+			}
+			tt.modify(&req)
+
+			if _, err := f.service.CreateRecurring(testutils.TestUserID, req); err == nil {
+				t.Error("CreateRecurring() expected an error")
+			}
+			if len(f.recurring) != 0 {
+				t.Error("an invalid schedule was stored")
+			}
+		})
+	}
+}
+
+func TestRecurringTransactionService_CreateRecurring_SkipsPastOccurrences(t *testing.T) {
+	f := newRecurringFixture()
+	start := time.Now().AddDate(0, -3, 0)
+
+	recurring, err := f.service.CreateRecurring(testutils.TestUserID, services.CreateRecurringRequest{
+		WalletID:  walletPtr(f.wallet.ID),
+		Amount:    money.FromMajor(50),
+		Name:      "Gym",
+		Method:    "Card",
+		Category:  "Health",
+		Frequency: models.FrequencyDaily,
+		StartDate: start,
+	})
+	if err != nil {
+		t.Fatalf("CreateRecurring() error = %v", err)
+	}
+
+	today := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 0, 0, 0, 0, start.Location())
+	if recurring.NextOccurrence == nil || recurring.NextOccurrence.Before(today) {
+		t.Errorf("NextOccurrence = %v, want a date from today on", recurring.NextOccurrence)
+	}
+	if recurring.OccurrenceCount == 0 {
+		t.Error("expected the skipped occurrences to be counted in the schedule index")
+	}
+}
+
+func TestRecurringTransactionService_PostDueOccurrences(t *testing.T) {
+	f := newRecurringFixture()
+	rent := f.seed(models.RecurringTransaction{
+		Amount:    money.FromMajor(1500),
+		Type:      models.TransactionTypeExpense,
+		Name:      "Rent",
+		Method:    "Bank",
+		Category:  "Housing",
+		Frequency: models.FrequencyMonthly,
+		StartDate: date(2026, time.January, 1),
+	})
+	salary := f.seed(models.RecurringTransaction{
+		Amount:        money.FromMajor(5000),
+		Type:          models.TransactionTypeIncome,
+		Name:          "Salary",
+		Method:        "Bank",
+		Category:      "Income",
+		Frequency:     models.FrequencyMonthly,
+		DayOfMonth:    models.LastDayOfMonth,
+		StartDate:     date(2026, time.January, 1),
+		PostAsPending: true,
+	})
+	now := date(2026, time.March, 15)
+
+	posted, err := f.service.PostDueOccurrences(now)
+	if err != nil {
+		t.Fatalf("PostDueOccurrences() error = %v", err)
+	}
+	// Rent on Jan 1, Feb 1 and Mar 1; salary on Jan 31 and Feb 28
+	if posted != 5 {
+		t.Errorf("posted = %d, want 5", posted)
+	}
+
+	completed, pending := 0, 0
+	for _, txn := range f.transactions {
+		if txn.RecurringID == nil || txn.WalletID == nil || *txn.WalletID != f.wallet.ID {
+			t.Errorf("transaction %q is not linked to its schedule and wallet", txn.Name)
+		}
+		switch txn.Status {
+		case "Completed":
+			completed++
+		case "Pending":
+			pending++
+		}
+	}
+	if completed != 3 || pending != 2 {
+		t.Errorf("completed = %d, pending = %d, want 3 and 2", completed, pending)
+	}
+
+	// Pending salary postings wait for confirmation before touching the balance
+	if want := money.FromMajor(10000 - 3*1500); f.wallet.Balance != want {
+		t.Errorf("wallet balance = %s, want %s", f.wallet.Balance, want)
+	}
+
+	stored := f.recurring[rent.ID]
+	if stored.OccurrenceCount != 3 || !stored.NextOccurrence.Equal(date(2026, time.April, 1)) || !stored.LastOccurrence.Equal(date(2026, time.March, 1)) {
+		t.Errorf("rent schedule = count %d, next %v, last %v", stored.OccurrenceCount, stored.NextOccurrence, stored.LastOccurrence)
+	}
+	if next := f.recurring[salary.ID].NextOccurrence; !next.Equal(date(2026, time.March, 31)) {
+		t.Errorf("salary next occurrence = %v, want March 31", next)
+	}
+
+	// Running again, as after a restart, posts nothing new
+	posted, err = f.service.PostDueOccurrences(now)
+	if err != nil {
+		t.Fatalf("second PostDueOccurrences() error = %v", err)
+	}
+	if posted != 0 || len(f.transactions) != 5 {
+		t.Errorf("second run posted %d, %d transactions stored, want 0 and 5", posted, len(f.transactions))
+	}
+}
+
+func TestRecurringTransactionService_PostDueOccurrences_EndsSchedule(t *testing.T) {
+	f := newRecurringFixture()
+	recurring := f.seed(models.RecurringTransaction{
+		Amount:         money.FromMajor(200),
+		Type:           models.TransactionTypeExpense,
+		Name:           "Loan repayment",
+		Method:         "M-Pesa",
+		Category:       "Debt",
+		Frequency:      models.FrequencyWeekly,
+		StartDate:      date(2026, time.January, 5),
+		MaxOccurrences: intPtr(2),
+	})
+
+	posted, err := f.service.PostDueOccurrences(date(2026, time.June, 1))
+	if err != nil {
+		t.Fatalf("PostDueOccurrences() error = %v", err)
+	}
+	if posted != 2 {
+		t.Errorf("posted = %d, want 2", posted)
+	}
+	if next := f.recurring[recurring.ID].NextOccurrence; next != nil {
+		t.Errorf("NextOccurrence = %v, want nil once the schedule has ended", next)
+	}
+}
+
+func TestRecurringTransactionService_PostDueOccurrences_DeletedWallet(t *testing.T) {
+	f := newRecurringFixture()
+	recurring := f.seed(models.RecurringTransaction{
+		Amount:    money.FromMajor(100),
+		Type:      models.TransactionTypeExpense,
+		Name:      "Internet",
+		Method:    "Card",
+		Category:  "Bills",
+		Frequency: models.FrequencyMonthly,
+		StartDate: date(2026, time.January, 1),
+	})
+	delete(f.wallets, f.wallet.ID)
+
+	posted, err := f.service.PostDueOccurrences(date(2026, time.February, 1))
+	if err != nil {
+		t.Fatalf("PostDueOccurrences() error = %v", err)
+	}
+	if posted != 0 || len(f.transactions) != 0 {
+		t.Errorf("posted = %d, want nothing posted to a deleted wallet", posted)
+	}
+	if f.recurring[recurring.ID].IsActive {
+		t.Error("expected the schedule to be paused")
+	}
+}
+
+func TestRecurringTransactionService_UpdateRecurring_DoesNotRepost(t *testing.T) {
+	f := newRecurringFixture()
+	today := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 0, 0, 0, 0, time.UTC)
+	recurring := f.seed(models.RecurringTransaction{
+		Amount:    money.FromMajor(100),
+		Type:      models.TransactionTypeExpense,
+		Name:      "Lunch",
+		Method:    "Cash",
+		Category:  "Food",
+		Frequency: models.FrequencyDaily,
+		StartDate: today.AddDate(0, 0, -2),
+	})
+
+	if _, err := f.service.PostDueOccurrences(today.Add(time.Hour)); err != nil {
+		t.Fatalf("PostDueOccurrences() error = %v", err)
+	}
+
+	updated, err := f.service.UpdateRecurring(recurring.ID, testutils.TestUserID, services.UpdateRecurringRequest{
+		Frequency: models.FrequencyWeekly,
+		StartDate: today,
+	})
+	if err != nil {
+		t.Fatalf("UpdateRecurring() error = %v", err)
+	}
+	// Today was already posted, so the weekly schedule continues next week
+	if want := today.AddDate(0, 0, 7); updated.NextOccurrence == nil || !updated.NextOccurrence.Equal(want) {
+		t.Errorf("NextOccurrence = %v, want %v", updated.NextOccurrence, want)
+	}
+}
+
+func TestRecurringTransactionService_UpdateRecurring_RacesScheduler(t *testing.T) {
+	f := newRecurringFixture()
+	today := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 0, 0, 0, 0, time.UTC)
+	recurring := f.seed(models.RecurringTransaction{
+		Amount:    money.FromMajor(100),
+		Type:      models.TransactionTypeExpense,
+		Name:      "Lunch",
+		Method:    "Cash",
+		Category:  "Food",
+		Frequency: models.FrequencyDaily,
+		StartDate: today.AddDate(0, 0, -2),
+	})
+
+	// The user opened the schedule before the scheduler posted its occurrences
+	stale := *f.recurring[recurring.ID]
+	if _, err := f.service.PostDueOccurrences(today.Add(time.Hour)); err != nil {
+		t.Fatalf("PostDueOccurrences() error = %v", err)
+	}
+	f.recurringRepo.FindByIDFunc = func(id uuid.UUID) (*models.RecurringTransaction, error) {
+		found := stale
+		return &found, nil
+	}
+	f.recurringRepo.FindByIDForUpdateFunc = func(id uuid.UUID) (*models.RecurringTransaction, error) {
+		found := *f.recurring[id]
+		return &found, nil
+	}
+
+	if _, err := f.service.UpdateRecurring(recurring.ID, testutils.TestUserID, services.UpdateRecurringRequest{Name: "Team lunch"}); err != nil {
+		t.Fatalf("UpdateRecurring() error = %v", err)
+	}
+	if stored := f.recurring[recurring.ID]; stored.OccurrenceCount != 3 || stored.Name != "Team lunch" {
+		t.Errorf("schedule = count %d, name %q, want the posted count kept", stored.OccurrenceCount, stored.Name)
+	}
+
+	posted, err := f.service.PostDueOccurrences(today.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("second PostDueOccurrences() error = %v", err)
+	}
+	if posted != 0 || len(f.transactions) != 3 {
+		t.Errorf("second run posted %d, %d transactions stored, want 0 and 3", posted, len(f.transactions))
+	}
+}
+
+func TestRecurringTransactionService_GetUpcoming(t *testing.T) {
+	f := newRecurringFixture()
+	start := time.Now().AddDate(0, 0, 1)
+
+	for _, req := range []services.CreateRecurringRequest{
+		{Name: "Weekly groceries", Frequency: models.FrequencyWeekly, StartDate: start},
+		{Name: "Rent", Frequency: models.FrequencyMonthly, StartDate: start.AddDate(0, 0, 2)},
+	} {
+		req.WalletID = walletPtr(f.wallet.ID)
+		req.Amount = money.FromMajor(100)
+		req.Method = "Cash"
+		req.Category = "Bills"
+		if _, err := f.service.CreateRecurring(testutils.TestUserID, req); err != nil {
+			t.Fatalf("CreateRecurring() error = %v", err)
+		}
+	}
+
+	upcoming, err := f.service.GetUpcoming(testutils.TestUserID, start.AddDate(0, 0, 20))
+	if err != nil {
+		t.Fatalf("GetUpcoming() error = %v", err)
+	}
+	// Groceries on days 1, 8, 15 and 22 after today, rent on day 3
+	if len(upcoming) != 4 {
+		t.Fatalf("len(upcoming) = %d, want 4", len(upcoming))
+	}
+	if upcoming[1].Name != "Rent" {
+		t.Errorf("upcoming[1] = %q, want occurrences sorted by date", upcoming[1].Name)
+	}
+	for i := 1; i < len(upcoming); i++ {
+		if upcoming[i].Date.Before(upcoming[i-1].Date) {
+			t.Errorf("occurrence %d is out of order", i)
+		}
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func intPtr(n int) *int {
+	return &n
+}
@@ -0,0 +1,385 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"github.com/nyunja/fity-budget-backend/internal/repository"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+	"github.com/nyunja/fity-budget-backend/tests/unit/mocks"
+	"github.com/nyunja/fity-budget-backend/tests/unit/testutils"
+	"gorm.io/gorm"
+)
+
+// ruleFixture keeps rules, tags and transactions in memory
+type ruleFixture struct {
+	rules        map[uuid.UUID]*models.Rule
+	tags         []models.Tag
+	transactions []*models.Transaction
+	written      map[uuid.UUID]map[string]interface{}
+	addedTags    map[uuid.UUID][]models.Tag
+}
+
+func newRuleFixture() *ruleFixture {
+	return &ruleFixture{
+		rules:     make(map[uuid.UUID]*models.Rule),
+		written:   make(map[uuid.UUID]map[string]interface{}),
+		addedTags: make(map[uuid.UUID][]models.Tag),
+	}
+}
+
+func (f *ruleFixture) add(rule models.Rule) *models.Rule {
+	rule.ID = uuid.New()
+	rule.UserID = testutils.TestUserID
+	rule.IsActive = true
+	if rule.MatchField == "" {
+		rule.MatchField = models.RuleFieldName
+	}
+	if rule.MatchType == "" {
+		rule.MatchType = models.RuleMatchContains
+	}
+	f.rules[rule.ID] = &rule
+	return &rule
+}
+
+func (f *ruleFixture) tag(name string) models.Tag {
+	for _, tag := range f.tags {
+		if strings.EqualFold(tag.Name, name) {
+			return tag
+		}
+	}
+	tag := models.Tag{ID: uuid.New(), UserID: testutils.TestUserID, Name: name}
+	f.tags = append(f.tags, tag)
+	return tag
+}
+
+func (f *ruleFixture) seed(transaction models.Transaction) *models.Transaction {
+	transaction.ID = uuid.New()
+	transaction.UserID = testutils.TestUserID
+	if transaction.Type == "" {
+		transaction.Type = models.TransactionTypeExpense
+	}
+	f.transactions = append(f.transactions, &transaction)
+	return &transaction
+}
+
+// activeRules returns the active rules in priority order
+func (f *ruleFixture) activeRules() []*models.Rule {
+	var rules []*models.Rule
+	for _, rule := range f.rules {
+		if rule.IsActive {
+			copied := *rule
+			rules = append(rules, &copied)
+		}
+	}
+	for i := 1; i < len(rules); i++ {
+		for j := i; j > 0 && rules[j].Priority < rules[j-1].Priority; j-- {
+			rules[j], rules[j-1] = rules[j-1], rules[j]
+		}
+	}
+	return rules
+}
+
+func (f *ruleFixture) ruleRepo() *mocks.MockRuleRepository {
+	return &mocks.MockRuleRepository{
+		CreateFunc: func(rule *models.Rule) error {
+			rule.ID = uuid.New()
+			f.rules[rule.ID] = rule
+			return nil
+		},
+		FindByIDFunc: func(id uuid.UUID) (*models.Rule, error) {
+			if rule, ok := f.rules[id]; ok {
+				copied := *rule
+				return &copied, nil
+			}
+			return nil, gorm.ErrRecordNotFound
+		},
+		FindActiveByUserIDFunc: func(userID uuid.UUID) ([]*models.Rule, error) {
+			return f.activeRules(), nil
+		},
+		UpdateFunc: func(rule *models.Rule) error {
+			copied := *rule
+			f.rules[rule.ID] = &copied
+			return nil
+		},
+	}
+}
+
+func (f *ruleFixture) service() services.RuleService {
+	tagRepo := &mocks.MockTagRepository{
+		FindOrCreateFunc: func(userID uuid.UUID, names []string) ([]models.Tag, error) {
+			var tags []models.Tag
+			for _, name := range names {
+				tags = append(tags, f.tag(name))
+			}
+			return tags, nil
+		},
+	}
+	transactionRepo := &mocks.MockTransactionRepository{
+		CountByFilterFunc: func(filter repository.TransactionFilter) (int64, error) {
+			transactions, _ := f.inRange(filter)
+			return int64(len(transactions)), nil
+		},
+		FindByFilterFunc: func(filter repository.TransactionFilter, sort repository.TransactionSort, limit, offset int) ([]*models.Transaction, error) {
+			return f.inRange(filter)
+		},
+		UpdateFunc: func(transaction *models.Transaction) error {
+			return errors.New("rules must not save whole transactions")
+		},
+		UpdateColumnsFunc: func(id uuid.UUID, columns map[string]interface{}) error {
+			f.written[id] = columns
+			return nil
+		},
+		AddTagsFunc: func(transactionID uuid.UUID, tags []models.Tag) error {
+			f.addedTags[transactionID] = append(f.addedTags[transactionID], tags...)
+			return nil
+		},
+	}
+	return services.NewRuleService(f.ruleRepo(), tagRepo, transactionRepo, &mocks.MockTxManager{})
+}
+
+// inRange returns copies of the transactions dated within the filter's range
+func (f *ruleFixture) inRange(filter repository.TransactionFilter) ([]*models.Transaction, error) {
+	var found []*models.Transaction
+	for _, transaction := range f.transactions {
+		if !transaction.TransactionDate.Before(filter.StartDate) && transaction.TransactionDate.Before(filter.EndDate) {
+			copied := *transaction
+			found = append(found, &copied)
+		}
+	}
+	return found, nil
+}
+
+func amountPtr(amount money.Amount) *money.Amount {
+	return &amount
+}
+
+func TestRuleService_CreateRule(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         services.CreateRuleRequest
+		expectError string
+	}{
+		{
+			name: "contains rule with tags",
+			req:  services.CreateRuleRequest{Name: "Uber", Pattern: "uber", SetCategory: "Transport", AddTags: []string{"travel", "rides"}},
+		},
+		{
+			name: "amount range without a pattern",
+			req:  services.CreateRuleRequest{Name: "Small card spend", Method: "Card", MaxAmount: amountPtr(money.FromMajor(200)), SetCategory: "Snacks"},
+		},
+		{
+			name:        "no condition",
+			req:         services.CreateRuleRequest{Name: "Everything", SetCategory: "Other"},
+			expectError: "at least one condition",
+		},
+		{
+			name:        "no action",
+			req:         services.CreateRuleRequest{Name: "Nothing", Pattern: "kplc"},
+			expectError: "at least one action",
+		},
+		{
+			name:        "invalid regex",
+			req:         services.CreateRuleRequest{Name: "Broken", MatchType: models.RuleMatchRegex, Pattern: "(kplc", SetCategory: "Utilities"},
+			expectError: "invalid pattern",
+		},
+		{
+			name:        "min above max",
+			req:         services.CreateRuleRequest{Name: "Range", MinAmount: amountPtr(money.FromMajor(500)), MaxAmount: amountPtr(money.FromMajor(100)), SetCategory: "Other"},
+			expectError: "min amount",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newRuleFixture()
+			rule, err := f.service().CreateRule(testutils.TestUserID, tt.req)
+			if tt.expectError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+					t.Errorf("Expected error containing %q, got %v", tt.expectError, err)
+				}
+				if len(f.rules) != 0 {
+					t.Error("Expected no rule to be stored")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !rule.IsActive || rule.MatchField != models.RuleFieldName || rule.MatchType != models.RuleMatchContains {
+				t.Errorf("Expected an active name/contains rule, got %+v", rule)
+			}
+			if len(rule.AddTags) != len(tt.req.AddTags) {
+				t.Errorf("Expected %d tags, got %+v", len(tt.req.AddTags), rule.AddTags)
+			}
+		})
+	}
+}
+
+func TestRuleService_UpdateRule_ForeignUser(t *testing.T) {
+	f := newRuleFixture()
+	rule := f.add(models.Rule{Name: "Uber", Pattern: "uber", SetCategory: "Transport"})
+	rule.UserID = uuid.New()
+	f.rules[rule.ID] = rule
+
+	if _, err := f.service().UpdateRule(rule.ID, testutils.TestUserID, services.UpdateRuleRequest{Name: "Mine"}); err == nil {
+		t.Error("Expected error updating another user's rule")
+	}
+}
+
+func TestRuleService_ApplyRules_DryRunDiff(t *testing.T) {
+	f := newRuleFixture()
+	march := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	travel := f.tag("travel")
+
+	// The lower priority runs first and wins the category
+	f.add(models.Rule{Name: "Airport taxi", Priority: 1, Pattern: "uber.*airport", MatchType: models.RuleMatchRegex, SetCategory: "Travel"})
+	f.add(models.Rule{Name: "Uber", Priority: 2, Pattern: "UBER", SetCategory: "Transport", AddTags: []models.Tag{travel}})
+	f.add(models.Rule{Name: "Big bills", Priority: 3, MinAmount: amountPtr(money.FromMajor(5000)), Method: "m-pesa", SetNotes: "Large payment"})
+	inactive := f.add(models.Rule{Name: "Disabled", Pattern: "kplc", SetCategory: "Ignored"})
+	inactive.IsActive = false
+
+	airport := f.seed(models.Transaction{Name: "Uber to airport", Category: "Uncategorized", Amount: money.FromMajor(2500), TransactionDate: march})
+	tagged := f.seed(models.Transaction{Name: "Uber", Category: "Transport", Amount: money.FromMajor(400), TransactionDate: march, Tags: []models.Tag{travel}})
+	rent := f.seed(models.Transaction{Name: "Rent", Category: "Housing", Method: "M-Pesa", Amount: money.FromMajor(25000), TransactionDate: march})
+	split := f.seed(models.Transaction{Name: "Uber Eats", Category: "Food", Amount: money.FromMajor(1000), TransactionDate: march,
+		Splits: []models.TransactionSplit{{Category: "Food", Amount: money.FromMajor(600)}, {Category: "Transport", Amount: money.FromMajor(400)}}})
+	f.seed(models.Transaction{Name: "KPLC tokens", Category: "Utilities", Amount: money.FromMajor(1000), TransactionDate: march})
+	f.seed(models.Transaction{Name: "Uber", Category: "Uncategorized", Amount: money.FromMajor(300), TransactionDate: march.AddDate(0, 1, 0)})
+
+	req := services.ApplyRulesRequest{
+		StartDate: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+		DryRun:    true,
+	}
+	result, err := f.service().ApplyRules(testutils.TestUserID, req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if result.Scanned != 5 || result.Changed != 3 {
+		t.Fatalf("Expected 3 of 5 transactions to change, got %d of %d: %+v", result.Changed, result.Scanned, result.Changes)
+	}
+	changes := make(map[uuid.UUID]*services.RuleChange)
+	for _, change := range result.Changes {
+		changes[change.TransactionID] = change
+	}
+
+	if change := changes[airport.ID]; change == nil || change.Category == nil ||
+		change.Category.From != "Uncategorized" || change.Category.To != "Travel" ||
+		len(change.AddedTags) != 1 || change.AddedTags[0] != "travel" || len(change.RuleIDs) != 2 {
+		t.Errorf("Unexpected change for the airport ride: %+v", change)
+	}
+	if _, ok := changes[tagged.ID]; ok {
+		t.Error("Expected no change for a transaction the rules already describe")
+	}
+	if change := changes[rent.ID]; change == nil || change.Category != nil || change.Notes == nil || change.Notes.To != "Large payment" {
+		t.Errorf("Unexpected change for rent: %+v", change)
+	}
+	// A split transaction keeps its category but still gets tags
+	if change := changes[split.ID]; change == nil || change.Category != nil || len(change.AddedTags) != 1 {
+		t.Errorf("Unexpected change for the split transaction: %+v", change)
+	}
+	if len(f.written) != 0 || len(f.addedTags) != 0 {
+		t.Error("Expected a dry run to save nothing")
+	}
+
+	req.DryRun = false
+	result, err = f.service().ApplyRules(testutils.TestUserID, req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.DryRun || len(f.addedTags[airport.ID]) != 1 || len(f.addedTags[rent.ID]) != 0 {
+		t.Errorf("Expected the travel tag added, got tags %+v", f.addedTags)
+	}
+	// Only the changed columns are written; the split transaction only gains tags
+	if len(f.written) != 2 {
+		t.Fatalf("Expected 2 column updates, got %+v", f.written)
+	}
+	if columns := f.written[airport.ID]; len(columns) != 1 || columns["category"] != "Travel" {
+		t.Errorf("Expected only the airport ride's category written, got %+v", columns)
+	}
+	if columns := f.written[rent.ID]; len(columns) != 1 || columns["notes"] != "Large payment" {
+		t.Errorf("Expected only the rent notes written, got %+v", columns)
+	}
+}
+
+func TestRuleService_ApplyRules_InvalidRange(t *testing.T) {
+	f := newRuleFixture()
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	if _, err := f.service().ApplyRules(testutils.TestUserID, services.ApplyRulesRequest{StartDate: day, EndDate: day}); err == nil {
+		t.Error("Expected error for an empty range")
+	}
+}
+
+func TestTransactionService_CreateTransaction_AppliesRules(t *testing.T) {
+	f := newRuleFixture()
+	wallet := uuid.New()
+	coffee := f.tag("coffee")
+	f.add(models.Rule{Name: "Java", Pattern: "java", SetCategory: "Cafe & Restaurants", SetNotes: "Coffee", AddTags: []models.Tag{coffee}})
+	f.add(models.Rule{Name: "Wallet", Priority: 1, WalletID: &wallet, Type: models.TransactionTypeIncome, SetCategory: "Salary"})
+
+	var created *models.Transaction
+	transactionRepo := &mocks.MockTransactionRepository{
+		CreateFunc: func(transaction *models.Transaction) error {
+			created = transaction
+			return nil
+		},
+	}
+	walletRepo := &mocks.MockWalletRepository{
+		FindByIDFunc: func(id uuid.UUID) (*models.Wallet, error) {
+			return &models.Wallet{ID: id, UserID: testutils.TestUserID, Currency: "KES"}, nil
+		},
+	}
+	service := services.NewTransactionService(transactionRepo, walletRepo, &mocks.MockUserRepository{}, &mocks.MockExchangeRateRepository{}, f.ruleRepo(), &mocks.MockTagRepository{}, &mocks.MockTxManager{})
+
+	tests := []struct {
+		name             string
+		req              services.CreateTransactionRequest
+		expectedCategory string
+		expectedNotes    string
+		expectedTags     int
+	}{
+		{
+			name:             "rule fills category, notes and tags",
+			req:              services.CreateTransactionRequest{Name: "Java House", Amount: money.FromMajor(450)},
+			expectedCategory: "Cafe & Restaurants",
+			expectedNotes:    "Coffee",
+			expectedTags:     1,
+		},
+		{
+			name:             "given category and notes are kept",
+			req:              services.CreateTransactionRequest{Name: "Java House", Amount: money.FromMajor(450), Category: "Work", Notes: "Client meeting"},
+			expectedCategory: "Work",
+			expectedNotes:    "Client meeting",
+			expectedTags:     1,
+		},
+		{
+			name:             "wallet and type conditions",
+			req:              services.CreateTransactionRequest{Name: "Payroll", Type: models.TransactionTypeIncome, WalletID: &wallet, Amount: money.FromMajor(90000)},
+			expectedCategory: "Salary",
+		},
+		{
+			name:             "unmatched income falls back to Income",
+			req:              services.CreateTransactionRequest{Name: "Gift", Type: models.TransactionTypeIncome, Amount: money.FromMajor(1000)},
+			expectedCategory: "Income",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.CreateTransaction(testutils.TestUserID, tt.req); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if created.Category != tt.expectedCategory || created.Notes != tt.expectedNotes || len(created.Tags) != tt.expectedTags {
+				t.Errorf("Expected %q/%q with %d tags, got %q/%q with %+v", tt.expectedCategory, tt.expectedNotes, tt.expectedTags, created.Category, created.Notes, created.Tags)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+	"github.com/nyunja/fity-budget-backend/tests/unit/mocks"
+	"github.com/nyunja/fity-budget-backend/tests/unit/testutils"
+	"gorm.io/gorm"
+)
+
+// newTagRepo keeps a user's tags in memory
+func newTagRepo(tags map[uuid.UUID]*models.Tag) *mocks.MockTagRepository {
+	return &mocks.MockTagRepository{
+		CreateFunc: func(tag *models.Tag) error {
+			tag.ID = uuid.New()
+			tags[tag.ID] = tag
+			return nil
+		},
+		FindByIDFunc: func(id uuid.UUID) (*models.Tag, error) {
+			if tag, ok := tags[id]; ok {
+				copied := *tag
+				return &copied, nil
+			}
+			return nil, gorm.ErrRecordNotFound
+		},
+		FindByUserIDAndNameFunc: func(userID uuid.UUID, name string) (*models.Tag, error) {
+			for _, tag := range tags {
+				if strings.EqualFold(tag.Name, name) {
+					return tag, nil
+				}
+			}
+			return nil, gorm.ErrRecordNotFound
+		},
+		FindOrCreateFunc: func(userID uuid.UUID, names []string) ([]models.Tag, error) {
+			var found []models.Tag
+			for _, name := range names {
+				var match *models.Tag
+				for _, tag := range tags {
+					if strings.EqualFold(tag.Name, name) {
+						match = tag
+					}
+				}
+				if match == nil {
+					match = &models.Tag{ID: uuid.New(), UserID: userID, Name: name}
+					tags[match.ID] = match
+				}
+				found = append(found, *match)
+			}
+			return found, nil
+		},
+		UpdateFunc: func(tag *models.Tag) error {
+			copied := *tag
+			tags[tag.ID] = &copied
+			return nil
+		},
+		DeleteFunc: func(id uuid.UUID) error {
+			delete(tags, id)
+			return nil
+		},
+	}
+}
+
+func TestTagService_CreateTag(t *testing.T) {
+	tags := make(map[uuid.UUID]*models.Tag)
+	service := services.NewTagService(newTagRepo(tags), &mocks.MockTxManager{})
+
+	tag, err := service.CreateTag(testutils.TestUserID, services.CreateTagRequest{Name: "  Reimbursable ", Color: "#F59E0B"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if tag.Name != "Reimbursable" || tag.UserID != testutils.TestUserID {
+		t.Errorf("Expected a trimmed tag for the user, got %+v", tag)
+	}
+
+	if _, err := service.CreateTag(testutils.TestUserID, services.CreateTagRequest{Name: "reimbursable"}); err == nil {
+		t.Error("Expected error for a name taken in another case")
+	}
+	if _, err := service.CreateTag(testutils.TestUserID, services.CreateTagRequest{Name: " "}); err == nil {
+		t.Error("Expected error for a blank name")
+	}
+	if len(tags) != 1 {
+		t.Errorf("Expected 1 stored tag, got %d", len(tags))
+	}
+}
+
+func TestTagService_UpdateTag(t *testing.T) {
+	tags := make(map[uuid.UUID]*models.Tag)
+	trip := &models.Tag{ID: uuid.New(), UserID: testutils.TestUserID, Name: "Trip"}
+	work := &models.Tag{ID: uuid.New(), UserID: testutils.TestUserID, Name: "Work"}
+	foreign := &models.Tag{ID: uuid.New(), UserID: uuid.New(), Name: "Theirs"}
+	for _, tag := range []*models.Tag{trip, work, foreign} {
+		tags[tag.ID] = tag
+	}
+	service := services.NewTagService(newTagRepo(tags), &mocks.MockTxManager{})
+
+	updated, err := service.UpdateTag(trip.ID, testutils.TestUserID, services.UpdateTagRequest{Name: "Mombasa trip 2026"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if updated.Name != "Mombasa trip 2026" || tags[trip.ID].Name != "Mombasa trip 2026" {
+		t.Errorf("Expected the tag to be renamed, got %q", tags[trip.ID].Name)
+	}
+
+	if _, err := service.UpdateTag(trip.ID, testutils.TestUserID, services.UpdateTagRequest{Name: "WORK"}); err == nil {
+		t.Error("Expected error renaming onto another tag's name")
+	}
+	if _, err := service.UpdateTag(foreign.ID, testutils.TestUserID, services.UpdateTagRequest{Color: "#000000"}); err == nil {
+		t.Error("Expected error updating another user's tag")
+	}
+}
+
+func TestTagService_DeleteTag(t *testing.T) {
+	tags := make(map[uuid.UUID]*models.Tag)
+	trip := &models.Tag{ID: uuid.New(), UserID: testutils.TestUserID, Name: "Trip"}
+	tags[trip.ID] = trip
+	service := services.NewTagService(newTagRepo(tags), &mocks.MockTxManager{})
+
+	if err := service.DeleteTag(uuid.New(), testutils.TestUserID); err == nil {
+		t.Error("Expected error deleting a missing tag")
+	}
+	if err := service.DeleteTag(trip.ID, testutils.TestUserID); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(tags) != 0 {
+		t.Error("Expected the tag to be deleted")
+	}
+}
+
+func TestTransactionService_Tags(t *testing.T) {
+	tags := make(map[uuid.UUID]*models.Tag)
+	existing := &models.Tag{ID: uuid.New(), UserID: testutils.TestUserID, Name: "Reimbursable"}
+	tags[existing.ID] = existing
+
+	stored := make(map[uuid.UUID]*models.Transaction)
+	var replaced *[]models.Tag
+	transactionRepo := &mocks.MockTransactionRepository{
+		CreateFunc: func(transaction *models.Transaction) error {
+			transaction.ID = uuid.New()
+			stored[transaction.ID] = transaction
+			return nil
+		},
+		FindByIDFunc: func(id uuid.UUID) (*models.Transaction, error) {
+			copied := *stored[id]
+			return &copied, nil
+		},
+		ReplaceTagsFunc: func(transactionID uuid.UUID, tags []models.Tag) error {
+			replaced = &tags
+			return nil
+		},
+	}
+	service := services.NewTransactionService(transactionRepo, &mocks.MockWalletRepository{}, &mocks.MockUserRepository{},
+		&mocks.MockExchangeRateRepository{}, &mocks.MockRuleRepository{}, newTagRepo(tags), &mocks.MockTxManager{})
+
+	created, err := service.CreateTransaction(testutils.TestUserID, services.CreateTransactionRequest{
+		Name:     "Hotel",
+		Amount:   4000,
+		Category: "Travel",
+		Tags:     []string{"reimbursable", "Mombasa trip 2026"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(created.Tags) != 2 || created.Tags[0].ID != existing.ID {
+		t.Errorf("Expected the existing tag and a new one, got %+v", created.Tags)
+	}
+	if len(tags) != 2 {
+		t.Errorf("Expected the missing tag to be created, got %d tags", len(tags))
+	}
+
+	// Tags are left alone unless the update names them
+	if _, err := service.UpdateTransaction(created.ID, testutils.TestUserID, services.UpdateTransactionRequest{Notes: "Paid by card"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if replaced != nil {
+		t.Error("Expected tags to be kept")
+	}
+
+	empty := []string{}
+	updated, err := service.UpdateTransaction(created.ID, testutils.TestUserID, services.UpdateTransactionRequest{Tags: &empty})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if replaced == nil || len(*replaced) != 0 || len(updated.Tags) != 0 {
+		t.Errorf("Expected an empty list to remove the tags, got %+v", updated.Tags)
+	}
+}
@@ -0,0 +1,955 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"github.com/nyunja/fity-budget-backend/internal/repository"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+	"github.com/nyunja/fity-budget-backend/tests/unit/mocks"
+	"github.com/nyunja/fity-budget-backend/tests/unit/testutils"
+	"gorm.io/gorm"
+)
+
+func TestTransactionService_CreateTransaction_DefaultsToExpense(t *testing.T) {
+	var created *models.Transaction
+	transactionRepo := &mocks.MockTransactionRepository{
+		CreateFunc: func(transaction *models.Transaction) error {
+			created = transaction
+			return nil
+		},
+	}
+	service := services.NewTransactionService(transactionRepo, &mocks.MockWalletRepository{}, &mocks.MockUserRepository{}, &mocks.MockExchangeRateRepository{}, &mocks.MockRuleRepository{}, &mocks.MockTagRepository{}, &mocks.MockTxManager{})
+
+	_, err := service.CreateTransaction(testutils.TestUserID, services.CreateTransactionRequest{
+		Amount:   money.FromMajor(250),
+		Name:     "Lunch",
+		Method:   "Cash",
+		Category: "Cafe & Restaurants",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if created.Type != models.TransactionTypeExpense {
+		t.Errorf("Expected type %q, got %q", models.TransactionTypeExpense, created.Type)
+	}
+}
+
+// aggregateTransactions emulates TransactionRepository.Aggregate over an
+// in-memory slice, applying the filter and grouping the way the SQL does
+func aggregateTransactions(transactions []*models.Transaction) func(repository.TransactionFilter, ...repository.TransactionGroup) ([]*repository.TransactionAggregate, error) {
+	return func(filter repository.TransactionFilter, groupBy ...repository.TransactionGroup) ([]*repository.TransactionAggregate, error) {
+		type groupKey struct {
+			txnType, category, period string
+			walletID, tagID           uuid.UUID
+		}
+		groups := make(map[groupKey]*repository.TransactionAggregate)
+		counted := make(map[groupKey]map[uuid.UUID]bool)
+		var ordered []*repository.TransactionAggregate
+
+		// Category totals are taken over split lines rather than whole transactions
+		lines := transactions
+		byCategory := len(filter.Categories) > 0
+		byTag := false
+		for _, group := range groupBy {
+			byCategory = byCategory || group == repository.GroupByCategory
+			byTag = byTag || group == repository.GroupByTag
+		}
+		if byCategory {
+			lines = splitLines(transactions)
+		}
+		if byTag {
+			lines = tagLines(lines)
+		}
+
+		for _, txn := range lines {
+			if !testutils.MatchesFilter(txn, filter) {
+				continue
+			}
+
+			var key groupKey
+			for _, group := range groupBy {
+				switch group {
+				case repository.GroupByType:
+					key.txnType = txn.Type
+				case repository.GroupByCategory:
+					key.category = txn.Category
+				case repository.GroupByWallet:
+					if txn.WalletID != nil {
+						key.walletID = *txn.WalletID
+					}
+				case repository.GroupByDay:
+					key.period = txn.TransactionDate.Format("2006-01-02")
+				case repository.GroupByMonth:
+					key.period = txn.TransactionDate.Format("2006-01")
+				case repository.GroupByTag:
+					key.tagID = txn.Tags[0].ID
+				}
+			}
+
+			aggregate, exists := groups[key]
+			if !exists {
+				aggregate = &repository.TransactionAggregate{Type: key.txnType, Category: key.category, Period: key.period}
+				if key.walletID != uuid.Nil {
+					walletID := key.walletID
+					aggregate.WalletID = &walletID
+				}
+				if key.tagID != uuid.Nil {
+					tagID := key.tagID
+					aggregate.TagID = &tagID
+				}
+				groups[key] = aggregate
+				counted[key] = make(map[uuid.UUID]bool)
+				ordered = append(ordered, aggregate)
+			}
+			aggregate.Total += txn.Amount
+			if !counted[key][txn.ID] {
+				counted[key][txn.ID] = true
+				aggregate.Count++
+			}
+		}
+
+		return ordered, nil
+	}
+}
+
+// tagLines replaces each transaction with one copy per tag carrying only that
+// tag, leaving untagged transactions out
+func tagLines(transactions []*models.Transaction) []*models.Transaction {
+	var lines []*models.Transaction
+	for _, txn := range transactions {
+		for _, tag := range txn.Tags {
+			line := *txn
+			line.Tags = []models.Tag{tag}
+			lines = append(lines, &line)
+		}
+	}
+	return lines
+}
+
+// splitLines replaces each split transaction with one copy per split line
+// carrying the line's category and amount
+func splitLines(transactions []*models.Transaction) []*models.Transaction {
+	var lines []*models.Transaction
+	for _, txn := range transactions {
+		if len(txn.Splits) == 0 {
+			lines = append(lines, txn)
+			continue
+		}
+		for _, split := range txn.Splits {
+			line := *txn
+			line.Category = split.Category
+			line.Amount = split.Amount
+			lines = append(lines, &line)
+		}
+	}
+	return lines
+}
+
+func TestTransactionService_GetTransactionStats(t *testing.T) {
+	now := time.Now()
+	startDate := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	endDate := startDate.AddDate(0, 1, 0)
+
+	transactionRepo := &mocks.MockTransactionRepository{
+		AggregateFunc: aggregateTransactions([]*models.Transaction{
+			{Type: models.TransactionTypeIncome, Amount: money.FromMajor(3000), Status: "Completed", TransactionDate: startDate},
+			{Type: models.TransactionTypeExpense, Amount: money.FromMajor(800), Status: "Completed", TransactionDate: startDate.Add(time.Hour)},
+			{Type: models.TransactionTypeTransfer, Amount: money.FromMajor(500), Status: "Completed", TransactionDate: startDate.Add(time.Hour)},
+			{Type: models.TransactionTypeExpense, Amount: money.FromMajor(100), Status: "Failed", TransactionDate: startDate.Add(time.Hour)},
+			{Type: models.TransactionTypeExpense, Amount: money.FromMajor(700), Status: "Completed", TransactionDate: endDate},
+		}),
+	}
+	service := services.NewTransactionService(transactionRepo, &mocks.MockWalletRepository{}, &mocks.MockUserRepository{}, &mocks.MockExchangeRateRepository{}, &mocks.MockRuleRepository{}, &mocks.MockTagRepository{}, &mocks.MockTxManager{})
+
+	stats, err := service.GetTransactionStats(testutils.TestUserID, startDate, endDate)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if stats.TotalIncome != money.FromMajor(3000) {
+		t.Errorf("Expected total income 3000, got %v", stats.TotalIncome)
+	}
+	if stats.TotalExpense != money.FromMajor(800) {
+		t.Errorf("Expected total expense 800, got %v", stats.TotalExpense)
+	}
+	if stats.TotalTransfers != money.FromMajor(500) {
+		t.Errorf("Expected total transfers 500, got %v", stats.TotalTransfers)
+	}
+	if stats.NetBalance != money.FromMajor(2200) {
+		t.Errorf("Expected net balance 2200, got %v", stats.NetBalance)
+	}
+	if stats.TransactionCount != 3 {
+		t.Errorf("Expected 3 transactions, got %d", stats.TransactionCount)
+	}
+}
+
+func TestTransactionService_ListTransactions(t *testing.T) {
+	var gotFilter repository.TransactionFilter
+	var gotSort repository.TransactionSort
+	transactionRepo := &mocks.MockTransactionRepository{
+		FindByFilterFunc: func(filter repository.TransactionFilter, sort repository.TransactionSort, limit, offset int) ([]*models.Transaction, error) {
+			gotFilter, gotSort = filter, sort
+			if limit != 20 || offset != 40 {
+				t.Errorf("Expected limit=20 and offset=40, got limit=%d, offset=%d", limit, offset)
+			}
+			return []*models.Transaction{{Name: "Coffee"}}, nil
+		},
+		CountByFilterFunc: func(filter repository.TransactionFilter) (int64, error) {
+			if filter.Search != gotFilter.Search || len(filter.Categories) != len(gotFilter.Categories) {
+				t.Error("Expected the total to be counted with the listing's filter")
+			}
+			return 41, nil
+		},
+	}
+	service := services.NewTransactionService(transactionRepo, &mocks.MockWalletRepository{}, &mocks.MockUserRepository{}, &mocks.MockExchangeRateRepository{}, &mocks.MockRuleRepository{}, &mocks.MockTagRepository{}, &mocks.MockTxManager{})
+
+	minAmount := money.FromMajor(100)
+	transactions, total, err := service.ListTransactions(testutils.TestUserID, services.TransactionListQuery{
+		Categories: []string{"Cafe & Restaurants"},
+		Statuses:   []string{"Completed"},
+		MinAmount:  &minAmount,
+		Search:     "  coffee ",
+		SortBy:     "amount",
+		SortOrder:  "ASC",
+		Limit:      20,
+		Offset:     40,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(transactions) != 1 || total != 41 {
+		t.Errorf("Expected 1 transaction of 41, got %d of %d", len(transactions), total)
+	}
+	if gotFilter.UserID != testutils.TestUserID || gotFilter.Search != "coffee" || *gotFilter.MinAmount != minAmount {
+		t.Errorf("Expected the user's trimmed filter, got %+v", gotFilter)
+	}
+	if gotSort.Field != repository.SortByAmount || !gotSort.Ascending {
+		t.Errorf("Expected ascending amount sort, got %+v", gotSort)
+	}
+}
+
+func TestTransactionService_ListTransactions_Invalid(t *testing.T) {
+	service := services.NewTransactionService(&mocks.MockTransactionRepository{}, &mocks.MockWalletRepository{}, &mocks.MockUserRepository{}, &mocks.MockExchangeRateRepository{}, &mocks.MockRuleRepository{}, &mocks.MockTagRepository{}, &mocks.MockTxManager{})
+	now := time.Now()
+	low, high := money.FromMajor(10), money.FromMajor(5)
+
+	tests := map[string]services.TransactionListQuery{
+		"reversed dates":   {StartDate: now, EndDate: now.AddDate(0, 0, -1)},
+		"reversed amounts": {MinAmount: &low, MaxAmount: &high},
+		"unknown status":   {Statuses: []string{"Done"}},
+		"unknown type":     {Types: []string{"refund"}},
+		"unknown sort":     {SortBy: "user_id"},
+		"unknown order":    {SortOrder: "sideways"},
+		"long search":      {Search: strings.Repeat("a", 101)},
+	}
+	for name, query := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := service.ListTransactions(testutils.TestUserID, query)
+			if !errors.Is(err, services.ErrInvalidTransactionQuery) {
+				t.Errorf("Expected ErrInvalidTransactionQuery, got %v", err)
+			}
+		})
+	}
+}
+
+// pageTransactions emulates TransactionRepository.FindByCursor over a slice,
+// which may grow between calls
+func pageTransactions(transactions *[]*models.Transaction) func(repository.TransactionFilter, repository.TransactionCursor, int) ([]*models.Transaction, error) {
+	return func(filter repository.TransactionFilter, cursor repository.TransactionCursor, limit int) ([]*models.Transaction, error) {
+		// before reports whether a sorts ahead of b in ascending (date, id) order
+		before := func(a, b *models.Transaction) bool {
+			if !a.TransactionDate.Equal(b.TransactionDate) {
+				return a.TransactionDate.Before(b.TransactionDate)
+			}
+			return a.ID.String() < b.ID.String()
+		}
+		position := &models.Transaction{ID: cursor.ID, TransactionDate: cursor.TransactionDate}
+		ascending := cursor.Ascending != cursor.Backward
+
+		var matched []*models.Transaction
+		for _, txn := range *transactions {
+			if !testutils.MatchesFilter(txn, filter) {
+				continue
+			}
+			if cursor.ID != uuid.Nil && (ascending && !before(position, txn) || !ascending && !before(txn, position)) {
+				continue
+			}
+			matched = append(matched, txn)
+		}
+		sort.Slice(matched, func(i, j int) bool {
+			return before(matched[i], matched[j]) == ascending
+		})
+		if len(matched) > limit {
+			matched = matched[:limit]
+		}
+		if cursor.Backward {
+			for i, j := 0, len(matched)-1; i < j; i, j = i+1, j-1 {
+				matched[i], matched[j] = matched[j], matched[i]
+			}
+		}
+		return matched, nil
+	}
+}
+
+func TestTransactionService_ListTransactionsByCursor(t *testing.T) {
+	start := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	var transactions []*models.Transaction
+	for i := 0; i < 5; i++ {
+		transactions = append(transactions, &models.Transaction{
+			ID:              uuid.New(),
+			Name:            string(rune('A' + i)),
+			Status:          "Completed",
+			TransactionDate: start.AddDate(0, 0, i),
+		})
+	}
+	// Two transactions on the same day are told apart by ID
+	transactions = append(transactions, &models.Transaction{ID: uuid.New(), Name: "F", Status: "Completed", TransactionDate: start.AddDate(0, 0, 2)})
+
+	transactionRepo := &mocks.MockTransactionRepository{FindByCursorFunc: pageTransactions(&transactions)}
+	service := services.NewTransactionService(transactionRepo, &mocks.MockWalletRepository{}, &mocks.MockUserRepository{}, &mocks.MockExchangeRateRepository{}, &mocks.MockRuleRepository{}, &mocks.MockTagRepository{}, &mocks.MockTxManager{})
+
+	list := func(cursor string) *services.TransactionPage {
+		t.Helper()
+		page, err := service.ListTransactionsByCursor(testutils.TestUserID, services.TransactionListQuery{Limit: 2, Cursor: cursor})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return page
+	}
+
+	first := list("")
+	if len(first.Transactions) != 2 || first.Transactions[0].Name != "E" || first.PrevCursor != "" || first.NextCursor == "" {
+		t.Fatalf("Expected the newest two transactions and only a next cursor, got %d (prev %q)", len(first.Transactions), first.PrevCursor)
+	}
+
+	// A transaction added while scrolling must not shift the following pages
+	transactions = append(transactions, &models.Transaction{ID: uuid.New(), Name: "New", Status: "Completed", TransactionDate: start.AddDate(0, 0, 10)})
+
+	seen := map[uuid.UUID]bool{first.Transactions[0].ID: true, first.Transactions[1].ID: true}
+	pages := []*services.TransactionPage{first}
+	for cursor := first.NextCursor; cursor != ""; {
+		page := list(cursor)
+		for _, txn := range page.Transactions {
+			if seen[txn.ID] {
+				t.Errorf("Transaction %s was listed twice", txn.Name)
+			}
+			seen[txn.ID] = true
+		}
+		pages = append(pages, page)
+		cursor = page.NextCursor
+	}
+	if len(seen) != 6 || len(pages) != 3 {
+		t.Errorf("Expected the 6 original transactions over 3 pages, got %d over %d", len(seen), len(pages))
+	}
+
+	// Walking back from the last page returns the previous page unchanged
+	back := list(pages[2].PrevCursor)
+	if len(back.Transactions) != 2 || back.Transactions[0].ID != pages[1].Transactions[0].ID || back.Transactions[1].ID != pages[1].Transactions[1].ID {
+		t.Errorf("Expected to walk back to the second page")
+	}
+	if back.NextCursor == "" || back.PrevCursor == "" {
+		t.Errorf("Expected both cursors on a middle page")
+	}
+
+	// Walking back from the second page now surfaces the new transaction too
+	top := list(back.PrevCursor)
+	if len(top.Transactions) != 2 || top.Transactions[0].Name != "E" || top.PrevCursor == "" {
+		t.Errorf("Expected the first page again with a cursor back to the new transaction")
+	}
+}
+
+func TestTransactionService_ListTransactionsByCursor_Invalid(t *testing.T) {
+	service := services.NewTransactionService(&mocks.MockTransactionRepository{}, &mocks.MockWalletRepository{}, &mocks.MockUserRepository{}, &mocks.MockExchangeRateRepository{}, &mocks.MockRuleRepository{}, &mocks.MockTagRepository{}, &mocks.MockTxManager{})
+
+	tests := map[string]services.TransactionListQuery{
+		"garbage cursor":   {Cursor: "not-a-cursor"},
+		"unsupported sort": {SortBy: repository.SortByAmount},
+	}
+	for name, query := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := service.ListTransactionsByCursor(testutils.TestUserID, query)
+			if !errors.Is(err, services.ErrInvalidTransactionQuery) {
+				t.Errorf("Expected ErrInvalidTransactionQuery, got %v", err)
+			}
+		})
+	}
+}
+
+var (
+	secondWalletID  = uuid.MustParse("880e8400-e29b-41d4-a716-446655440002")
+	foreignWalletID = uuid.MustParse("880e8400-e29b-41d4-a716-446655440099")
+)
+
+// ledgerFixture wires a transaction service to in-memory repositories and
+// records the net balance change applied to each wallet
+type ledgerFixture struct {
+	service      services.TransactionService
+	transactions *mocks.MockTransactionRepository
+	txManager    *mocks.MockTxManager
+	store        *testutils.TransactionStore
+	stored       map[uuid.UUID]*models.Transaction
+	balances     map[uuid.UUID]money.Amount
+}
+
+func newLedgerFixture() *ledgerFixture {
+	transactions := testutils.NewTransactionStore()
+	wallets := testutils.NewWalletStore(
+		&models.Wallet{ID: testutils.TestWalletID, UserID: testutils.TestUserID},
+		&models.Wallet{ID: secondWalletID, UserID: testutils.TestUserID},
+		&models.Wallet{ID: foreignWalletID, UserID: uuid.New()},
+	)
+	f := &ledgerFixture{
+		store:    transactions,
+		stored:   transactions.Stored,
+		balances: wallets.Balances,
+	}
+
+	f.transactions = transactions.Repo()
+	f.txManager = &mocks.MockTxManager{}
+	f.service = services.NewTransactionService(f.transactions, wallets.Repo(), &mocks.MockUserRepository{}, &mocks.MockExchangeRateRepository{}, &mocks.MockRuleRepository{}, &mocks.MockTagRepository{}, f.txManager)
+	return f
+}
+
+// seed stores an existing transaction of the test user without touching balances
+func (f *ledgerFixture) seed(transaction models.Transaction) uuid.UUID {
+	transaction.UserID = testutils.TestUserID
+	return f.store.Seed(transaction)
+}
+
+func walletPtr(id uuid.UUID) *uuid.UUID {
+	return &id
+}
+
+func TestTransactionService_CreateTransaction_UpdatesWalletBalance(t *testing.T) {
+	tests := []struct {
+		name            string
+		req             services.CreateTransactionRequest
+		expectedBalance money.Amount
+	}{
+		{
+			name:            "completed expense debits the wallet",
+			req:             services.CreateTransactionRequest{Type: "expense", Amount: money.FromMajor(500), Status: "Completed"},
+			expectedBalance: money.FromMajor(-500),
+		},
+		{
+			name:            "status defaults to completed",
+			req:             services.CreateTransactionRequest{Type: "expense", Amount: money.FromMajor(120)},
+			expectedBalance: money.FromMajor(-120),
+		},
+		{
+			name:            "completed income credits the wallet",
+			req:             services.CreateTransactionRequest{Type: "income", Amount: money.FromMajor(3000), Status: "Completed"},
+			expectedBalance: money.FromMajor(3000),
+		},
+		{
+			name:            "pending expense leaves the balance untouched",
+			req:             services.CreateTransactionRequest{Type: "expense", Amount: money.FromMajor(500), Status: "Pending"},
+			expectedBalance: 0,
+		},
+		{
+			name:            "failed income leaves the balance untouched",
+			req:             services.CreateTransactionRequest{Type: "income", Amount: money.FromMajor(500), Status: "Failed"},
+			expectedBalance: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newLedgerFixture()
+			tt.req.WalletID = walletPtr(testutils.TestWalletID)
+			tt.req.Name = "Test"
+			tt.req.Method = "Cash"
+			tt.req.Category = "Test"
+
+			if _, err := f.service.CreateTransaction(testutils.TestUserID, tt.req); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if got := f.balances[testutils.TestWalletID]; got != tt.expectedBalance {
+				t.Errorf("Expected balance change %v, got %v", tt.expectedBalance, got)
+			}
+		})
+	}
+}
+
+func TestTransactionService_CreateTransaction_WithoutWallet(t *testing.T) {
+	f := newLedgerFixture()
+
+	_, err := f.service.CreateTransaction(testutils.TestUserID, services.CreateTransactionRequest{
+		Type: "expense", Amount: money.FromMajor(75), Name: "Cash tip", Method: "Cash", Category: "Other",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(f.balances) != 0 {
+		t.Errorf("Expected no balance changes, got %v", f.balances)
+	}
+}
+
+func TestTransactionService_UpdateTransaction_RebalancesWallets(t *testing.T) {
+	completedExpense := models.Transaction{
+		WalletID: walletPtr(testutils.TestWalletID),
+		Type:     models.TransactionTypeExpense,
+		Amount:   money.FromMajor(500),
+		Status:   "Completed",
+	}
+	pendingExpense := completedExpense
+	pendingExpense.Status = "Pending"
+
+	tests := []struct {
+		name             string
+		existing         models.Transaction
+		req              services.UpdateTransactionRequest
+		expectedBalances map[uuid.UUID]money.Amount
+	}{
+		{
+			name:             "amount increase debits the difference",
+			existing:         completedExpense,
+			req:              services.UpdateTransactionRequest{Amount: money.FromMajor(800)},
+			expectedBalances: map[uuid.UUID]money.Amount{testutils.TestWalletID: money.FromMajor(-300)},
+		},
+		{
+			name:             "amount decrease credits the difference",
+			existing:         completedExpense,
+			req:              services.UpdateTransactionRequest{Amount: money.FromMajor(200)},
+			expectedBalances: map[uuid.UUID]money.Amount{testutils.TestWalletID: money.FromMajor(300)},
+		},
+		{
+			name:             "pending to completed applies the amount",
+			existing:         pendingExpense,
+			req:              services.UpdateTransactionRequest{Status: "Completed"},
+			expectedBalances: map[uuid.UUID]money.Amount{testutils.TestWalletID: money.FromMajor(-500)},
+		},
+		{
+			name:             "pending to failed changes nothing",
+			existing:         pendingExpense,
+			req:              services.UpdateTransactionRequest{Status: "Failed"},
+			expectedBalances: map[uuid.UUID]money.Amount{},
+		},
+		{
+			name:             "completed to failed reverses the amount",
+			existing:         completedExpense,
+			req:              services.UpdateTransactionRequest{Status: "Failed"},
+			expectedBalances: map[uuid.UUID]money.Amount{testutils.TestWalletID: money.FromMajor(500)},
+		},
+		{
+			name:             "completed to pending reverses the amount",
+			existing:         completedExpense,
+			req:              services.UpdateTransactionRequest{Status: "Pending"},
+			expectedBalances: map[uuid.UUID]money.Amount{testutils.TestWalletID: money.FromMajor(500)},
+		},
+		{
+			name:             "expense to income flips the direction",
+			existing:         completedExpense,
+			req:              services.UpdateTransactionRequest{Type: "income"},
+			expectedBalances: map[uuid.UUID]money.Amount{testutils.TestWalletID: money.FromMajor(1000)},
+		},
+		{
+			name:     "moving wallets refunds the old wallet and debits the new one",
+			existing: completedExpense,
+			req:      services.UpdateTransactionRequest{WalletID: walletPtr(secondWalletID)},
+			expectedBalances: map[uuid.UUID]money.Amount{
+				testutils.TestWalletID: money.FromMajor(500),
+				secondWalletID:         money.FromMajor(-500),
+			},
+		},
+		{
+			name:     "moving wallets and changing amount together",
+			existing: completedExpense,
+			req:      services.UpdateTransactionRequest{WalletID: walletPtr(secondWalletID), Amount: money.FromMajor(650)},
+			expectedBalances: map[uuid.UUID]money.Amount{
+				testutils.TestWalletID: money.FromMajor(500),
+				secondWalletID:         money.FromMajor(-650),
+			},
+		},
+		{
+			name:             "metadata changes leave balances alone",
+			existing:         completedExpense,
+			req:              services.UpdateTransactionRequest{Name: "Renamed", Category: "Shopping"},
+			expectedBalances: map[uuid.UUID]money.Amount{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newLedgerFixture()
+			id := f.seed(tt.existing)
+
+			if _, err := f.service.UpdateTransaction(id, testutils.TestUserID, tt.req); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			for walletID, expected := range tt.expectedBalances {
+				if got := f.balances[walletID]; got != expected {
+					t.Errorf("Expected balance change %v for wallet %s, got %v", expected, walletID, got)
+				}
+			}
+			for walletID, got := range f.balances {
+				if _, ok := tt.expectedBalances[walletID]; !ok && got != 0 {
+					t.Errorf("Unexpected balance change %v for wallet %s", got, walletID)
+				}
+			}
+		})
+	}
+}
+
+func TestTransactionService_UpdateTransaction_RejectsForeignWallet(t *testing.T) {
+	f := newLedgerFixture()
+	id := f.seed(models.Transaction{
+		WalletID: walletPtr(testutils.TestWalletID),
+		Type:     models.TransactionTypeExpense,
+		Amount:   money.FromMajor(500),
+		Status:   "Completed",
+	})
+
+	_, err := f.service.UpdateTransaction(id, testutils.TestUserID, services.UpdateTransactionRequest{
+		WalletID: walletPtr(foreignWalletID),
+	})
+	if err == nil {
+		t.Fatal("Expected an error when moving to another user's wallet")
+	}
+	if len(f.balances) != 0 {
+		t.Errorf("Expected no balance changes, got %v", f.balances)
+	}
+}
+
+func TestTransactionService_DeleteTransaction_ReversesBalance(t *testing.T) {
+	tests := []struct {
+		name            string
+		existing        models.Transaction
+		expectedBalance money.Amount
+	}{
+		{
+			name:            "deleting a completed expense refunds the wallet",
+			existing:        models.Transaction{Type: models.TransactionTypeExpense, Amount: money.FromMajor(500), Status: "Completed"},
+			expectedBalance: money.FromMajor(500),
+		},
+		{
+			name:            "deleting completed income debits the wallet",
+			existing:        models.Transaction{Type: models.TransactionTypeIncome, Amount: money.FromMajor(2500), Status: "Completed"},
+			expectedBalance: money.FromMajor(-2500),
+		},
+		{
+			name:            "deleting a pending expense changes nothing",
+			existing:        models.Transaction{Type: models.TransactionTypeExpense, Amount: money.FromMajor(500), Status: "Pending"},
+			expectedBalance: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newLedgerFixture()
+			tt.existing.WalletID = walletPtr(testutils.TestWalletID)
+			id := f.seed(tt.existing)
+
+			if err := f.service.DeleteTransaction(id, testutils.TestUserID); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if got := f.balances[testutils.TestWalletID]; got != tt.expectedBalance {
+				t.Errorf("Expected balance change %v, got %v", tt.expectedBalance, got)
+			}
+			if _, ok := f.stored[id]; ok {
+				t.Error("Expected transaction to be deleted")
+			}
+		})
+	}
+}
+
+func TestTransactionService_ReadsPreviousStateUnderLock(t *testing.T) {
+	f := newLedgerFixture()
+	id := f.seed(models.Transaction{WalletID: walletPtr(testutils.TestWalletID), Type: models.TransactionTypeExpense, Amount: money.FromMajor(500), Status: "Completed"})
+
+	inTx := false
+	f.txManager.WithinTransactionFunc = func(fn func(tx *gorm.DB) error) error {
+		inTx = true
+		defer func() { inTx = false }()
+		return fn(nil)
+	}
+	f.transactions.FindByIDFunc = func(id uuid.UUID) (*models.Transaction, error) {
+		t.Error("Expected the transaction to be read with a row lock")
+		return nil, errors.New("unlocked read")
+	}
+	findLocked := func(id uuid.UUID) (*models.Transaction, error) {
+		if !inTx {
+			t.Error("Expected the row lock to be taken inside the database transaction")
+		}
+		transaction, ok := f.stored[id]
+		if !ok {
+			return nil, errors.New("record not found")
+		}
+		found := *transaction
+		return &found, nil
+	}
+	f.transactions.FindByIDForUpdateFunc = findLocked
+
+	if _, err := f.service.UpdateTransaction(id, testutils.TestUserID, services.UpdateTransactionRequest{Amount: money.FromMajor(300)}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := f.service.DeleteTransaction(id, testutils.TestUserID); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// A repeated delete finds the row gone and must not refund the wallet again
+	if err := f.service.DeleteTransaction(id, testutils.TestUserID); err == nil {
+		t.Error("Expected the repeated delete to fail")
+	}
+
+	if got := f.balances[testutils.TestWalletID]; got != money.FromMajor(500) {
+		t.Errorf("Expected the wallet to be refunded 500 once, got %v", got)
+	}
+}
+
+func TestTransactionService_BalanceFailureAbortsWrite(t *testing.T) {
+	transactionRepo := &mocks.MockTransactionRepository{}
+	walletRepo := &mocks.MockWalletRepository{
+		FindByIDFunc: func(id uuid.UUID) (*models.Wallet, error) {
+			return &models.Wallet{ID: id, UserID: testutils.TestUserID}, nil
+		},
+		UpdateBalanceFunc: func(id uuid.UUID, amount money.Amount) error {
+			return errors.New("database error")
+		},
+	}
+	service := services.NewTransactionService(transactionRepo, walletRepo, &mocks.MockUserRepository{}, &mocks.MockExchangeRateRepository{}, &mocks.MockRuleRepository{}, &mocks.MockTagRepository{}, &mocks.MockTxManager{})
+
+	_, err := service.CreateTransaction(testutils.TestUserID, services.CreateTransactionRequest{
+		WalletID: walletPtr(testutils.TestWalletID),
+		Type:     "expense",
+		Amount:   money.FromMajor(100),
+		Name:     "Test",
+		Method:   "Cash",
+		Category: "Test",
+	})
+	if err == nil {
+		t.Fatal("Expected the balance failure to be returned so the transaction rolls back")
+	}
+}
+
+func TestTransactionService_TransferLegsAreReadOnly(t *testing.T) {
+	f := newLedgerFixture()
+	transferID := uuid.New()
+	id := f.seed(models.Transaction{
+		WalletID:   walletPtr(testutils.TestWalletID),
+		TransferID: &transferID,
+		Type:       models.TransactionTypeTransfer,
+		Amount:     money.FromMajor(300),
+		Status:     "Completed",
+	})
+
+	if _, err := f.service.UpdateTransaction(id, testutils.TestUserID, services.UpdateTransactionRequest{Amount: money.FromMajor(100)}); err == nil {
+		t.Error("Expected error when editing a transfer transaction")
+	}
+	if err := f.service.DeleteTransaction(id, testutils.TestUserID); err == nil {
+		t.Error("Expected error when deleting a transfer transaction")
+	}
+	if _, err := f.service.CreateTransaction(testutils.TestUserID, services.CreateTransactionRequest{
+		WalletID: walletPtr(testutils.TestWalletID),
+		Type:     models.TransactionTypeTransfer,
+		Amount:   money.FromMajor(100),
+		Name:     "Manual transfer",
+		Method:   "Cash",
+		Category: "Transfer",
+	}); err == nil {
+		t.Error("Expected error when creating a transfer transaction directly")
+	}
+
+	if len(f.balances) != 0 {
+		t.Errorf("Expected no balance changes, got %v", f.balances)
+	}
+}
+
+func TestTransactionService_CreateTransaction_RoundsToWalletCurrency(t *testing.T) {
+	var created *models.Transaction
+	transactionRepo := &mocks.MockTransactionRepository{
+		CreateFunc: func(transaction *models.Transaction) error {
+			created = transaction
+			return nil
+		},
+	}
+	var balanceChange money.Amount
+	walletRepo := &mocks.MockWalletRepository{
+		FindByIDFunc: func(id uuid.UUID) (*models.Wallet, error) {
+			return &models.Wallet{ID: id, UserID: testutils.TestUserID, Currency: "JPY"}, nil
+		},
+		UpdateBalanceFunc: func(id uuid.UUID, amount money.Amount) error {
+			balanceChange += amount
+			return nil
+		},
+	}
+	service := services.NewTransactionService(transactionRepo, walletRepo, &mocks.MockUserRepository{}, &mocks.MockExchangeRateRepository{}, &mocks.MockRuleRepository{}, &mocks.MockTagRepository{}, &mocks.MockTxManager{})
+
+	_, err := service.CreateTransaction(testutils.TestUserID, services.CreateTransactionRequest{
+		WalletID: walletPtr(testutils.TestWalletID),
+		Amount:   money.MustParse("980.60"),
+		Name:     "Ramen",
+		Method:   "Card",
+		Category: "Cafe & Restaurants",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if created.Amount != money.FromMajor(981) {
+		t.Errorf("Expected amount rounded to 981, got %s", created.Amount)
+	}
+	if balanceChange != -money.FromMajor(981) {
+		t.Errorf("Expected balance change -981, got %s", balanceChange)
+	}
+
+	_, err = service.CreateTransaction(testutils.TestUserID, services.CreateTransactionRequest{
+		WalletID: walletPtr(testutils.TestWalletID),
+		Amount:   money.MustParse("0.40"),
+		Name:     "Rounding",
+		Method:   "Card",
+		Category: "Other",
+	})
+	if err == nil {
+		t.Error("Expected error for an amount that rounds to zero")
+	}
+}
+
+func TestTransactionService_CreateTransaction_Splits(t *testing.T) {
+	splits := []services.TransactionSplitRequest{
+		{Category: "Food & Groceries", Amount: money.FromMajor(1800)},
+		{Category: "Household", Amount: money.FromMajor(700), Note: "Detergent"},
+		{Category: "Alcohol", Amount: money.FromMajor(500)},
+	}
+
+	tests := []struct {
+		name             string
+		amount           money.Amount
+		category         string
+		splits           []services.TransactionSplitRequest
+		expectedCategory string
+		expectError      bool
+	}{
+		{
+			name:             "splits adding up to the amount",
+			amount:           money.FromMajor(3000),
+			category:         "Shopping",
+			splits:           splits,
+			expectedCategory: "Shopping",
+		},
+		{
+			name:             "category defaults to the largest split",
+			amount:           money.FromMajor(3000),
+			splits:           splits,
+			expectedCategory: "Food & Groceries",
+		},
+		{
+			name:        "splits not adding up",
+			amount:      money.FromMajor(2999),
+			splits:      splits,
+			expectError: true,
+		},
+		{
+			name:        "single split line",
+			amount:      money.FromMajor(1800),
+			splits:      splits[:1],
+			expectError: true,
+		},
+		{
+			name:        "split line without a category",
+			amount:      money.FromMajor(1000),
+			splits:      []services.TransactionSplitRequest{{Category: " ", Amount: money.FromMajor(500)}, {Category: "Household", Amount: money.FromMajor(500)}},
+			expectError: true,
+		},
+		{
+			name:             "no category and no splits",
+			amount:           money.FromMajor(1000),
+			expectedCategory: "Uncategorized",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newLedgerFixture()
+
+			transaction, err := f.service.CreateTransaction(testutils.TestUserID, services.CreateTransactionRequest{
+				WalletID: walletPtr(testutils.TestWalletID),
+				Amount:   tt.amount,
+				Name:     "Naivas",
+				Method:   "Card",
+				Category: tt.category,
+				Splits:   tt.splits,
+			})
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected an error")
+				}
+				if len(f.stored) != 0 || f.balances[testutils.TestWalletID] != 0 {
+					t.Error("Expected nothing to be written")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if transaction.Category != tt.expectedCategory {
+				t.Errorf("Expected category %q, got %q", tt.expectedCategory, transaction.Category)
+			}
+			if len(f.stored[transaction.ID].Splits) != len(tt.splits) {
+				t.Errorf("Expected %d splits to be stored, got %d", len(tt.splits), len(f.stored[transaction.ID].Splits))
+			}
+			// The wallet moves by the whole amount, not per split line
+			if f.balances[testutils.TestWalletID] != -tt.amount {
+				t.Errorf("Expected balance change %v, got %v", -tt.amount, f.balances[testutils.TestWalletID])
+			}
+		})
+	}
+}
+
+func TestTransactionService_UpdateTransaction_Splits(t *testing.T) {
+	f := newLedgerFixture()
+	id := f.seed(models.Transaction{
+		WalletID: walletPtr(testutils.TestWalletID),
+		Type:     models.TransactionTypeExpense,
+		Amount:   money.FromMajor(1000),
+		Name:     "Carrefour",
+		Method:   "Card",
+		Category: "Food & Groceries",
+		Status:   "Completed",
+		Splits: []models.TransactionSplit{
+			{Category: "Food & Groceries", Amount: money.FromMajor(600)},
+			{Category: "Household", Amount: money.FromMajor(400)},
+		},
+	})
+
+	// Changing the amount alone would leave the splits out of balance
+	if _, err := f.service.UpdateTransaction(id, testutils.TestUserID, services.UpdateTransactionRequest{Amount: money.FromMajor(1200)}); err == nil {
+		t.Error("Expected an error changing the amount of a split transaction without its splits")
+	}
+
+	resplit := []services.TransactionSplitRequest{
+		{Category: "Food & Groceries", Amount: money.FromMajor(700)},
+		{Category: "Household", Amount: money.FromMajor(500)},
+	}
+	updated, err := f.service.UpdateTransaction(id, testutils.TestUserID, services.UpdateTransactionRequest{
+		Amount: money.FromMajor(1200),
+		Splits: &resplit,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if updated.Amount != money.FromMajor(1200) || len(f.stored[id].Splits) != 2 || f.stored[id].Splits[1].Amount != money.FromMajor(500) {
+		t.Errorf("Expected the amount and splits to be replaced, got %v with %+v", updated.Amount, f.stored[id].Splits)
+	}
+
+	// An empty list removes the splits
+	if _, err := f.service.UpdateTransaction(id, testutils.TestUserID, services.UpdateTransactionRequest{Splits: &[]services.TransactionSplitRequest{}}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(f.stored[id].Splits) != 0 {
+		t.Errorf("Expected the splits to be removed, got %d", len(f.stored[id].Splits))
+	}
+}
@@ -0,0 +1,369 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+	"github.com/nyunja/fity-budget-backend/tests/unit/mocks"
+	"github.com/nyunja/fity-budget-backend/tests/unit/testutils"
+	"gorm.io/gorm"
+)
+
+// trashFixture keeps deleted records and wallet balances in memory
+type trashFixture struct {
+	store        *testutils.TransactionStore
+	transactions map[uuid.UUID]*models.Transaction
+	wallets      map[uuid.UUID]*models.Wallet
+	imports      map[uuid.UUID]*models.ImportJob
+	balances     map[uuid.UUID]money.Amount
+	restored     []uuid.UUID
+	purged       []uuid.UUID
+	receiptsGone []uuid.UUID
+
+	transactionRepo *mocks.MockTransactionRepository
+	budgetRepo      *mocks.MockBudgetRepository
+	goalRepo        *mocks.MockGoalRepository
+	walletRepo      *mocks.MockWalletRepository
+	importJobRepo   *mocks.MockImportJobRepository
+	receiptService  *mocks.MockReceiptService
+	txManager       *mocks.MockTxManager
+}
+
+func newTrashFixture() *trashFixture {
+	transactions := testutils.NewTransactionStore()
+	wallets := testutils.NewWalletStore(&models.Wallet{ID: testutils.TestWalletID, UserID: testutils.TestUserID, IsDefault: true})
+	f := &trashFixture{
+		store:        transactions,
+		transactions: transactions.Stored,
+		wallets:      wallets.Wallets,
+		imports:      make(map[uuid.UUID]*models.ImportJob),
+		balances:     wallets.Balances,
+	}
+
+	f.transactionRepo = transactions.Repo()
+	f.transactionRepo.FindDeletedByIDFunc = func(id uuid.UUID) (*models.Transaction, error) {
+		if transaction, ok := f.transactions[id]; ok && transaction.DeletedAt.Valid {
+			copied := *transaction
+			return &copied, nil
+		}
+		return nil, gorm.ErrRecordNotFound
+	}
+	f.transactionRepo.RestoreFunc = func(id uuid.UUID) (bool, error) {
+		transaction, ok := f.transactions[id]
+		if !ok || !transaction.DeletedAt.Valid {
+			return false, nil
+		}
+		transaction.DeletedAt = gorm.DeletedAt{}
+		f.restored = append(f.restored, id)
+		return true, nil
+	}
+	f.transactionRepo.PurgeFunc = func(id uuid.UUID) error {
+		f.purged = append(f.purged, id)
+		return nil
+	}
+	f.budgetRepo = &mocks.MockBudgetRepository{}
+	f.goalRepo = &mocks.MockGoalRepository{}
+	f.walletRepo = wallets.Repo()
+	f.importJobRepo = &mocks.MockImportJobRepository{
+		FindByIDFunc: func(id uuid.UUID) (*models.ImportJob, error) {
+			if job, ok := f.imports[id]; ok {
+				return job, nil
+			}
+			return nil, errors.New("record not found")
+		},
+	}
+	f.txManager = &mocks.MockTxManager{}
+	f.receiptService = &mocks.MockReceiptService{
+		DeleteTransactionReceiptsFunc: func(transactionID uuid.UUID) error {
+			f.receiptsGone = append(f.receiptsGone, transactionID)
+			return nil
+		},
+	}
+	return f
+}
+
+func (f *trashFixture) service(retention time.Duration) services.TrashService {
+	return services.NewTrashService(f.transactionRepo, f.budgetRepo, f.goalRepo, f.walletRepo, f.importJobRepo, f.receiptService, f.txManager, retention)
+}
+
+func (f *trashFixture) addDeletedTransaction(userID uuid.UUID, walletID uuid.UUID, amount money.Amount) *models.Transaction {
+	id := f.store.Seed(models.Transaction{
+		UserID:    userID,
+		WalletID:  walletPtr(walletID),
+		Amount:    amount,
+		Type:      models.TransactionTypeExpense,
+		Status:    "Completed",
+		DeletedAt: gorm.DeletedAt{Time: time.Now(), Valid: true},
+	})
+	return f.transactions[id]
+}
+
+func TestTrashService_RestoreTransaction(t *testing.T) {
+	f := newTrashFixture()
+	transaction := f.addDeletedTransaction(testutils.TestUserID, testutils.TestWalletID, money.FromMajor(50))
+
+	restored, err := f.service(0).RestoreItem(services.TrashTypeTransaction, transaction.ID, testutils.TestUserID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if restored.(*models.Transaction).DeletedAt.Valid {
+		t.Error("Expected restored transaction to no longer be deleted")
+	}
+	if len(f.restored) != 1 || f.restored[0] != transaction.ID {
+		t.Errorf("Expected transaction to be restored, got %v", f.restored)
+	}
+	if f.balances[testutils.TestWalletID] != -money.FromMajor(50) {
+		t.Errorf("Expected the expense to be taken from the wallet again, got %v", f.balances[testutils.TestWalletID])
+	}
+}
+
+func TestTrashService_RestoreTransaction_Repeated(t *testing.T) {
+	f := newTrashFixture()
+	transaction := f.addDeletedTransaction(testutils.TestUserID, testutils.TestWalletID, money.FromMajor(50))
+
+	// A second restore reads the row before the first commits and only loses
+	// when it tries to restore it
+	findDeleted := f.transactionRepo.FindDeletedByIDFunc
+	stale, _ := findDeleted(transaction.ID)
+	f.transactionRepo.FindDeletedByIDFunc = func(id uuid.UUID) (*models.Transaction, error) {
+		copied := *stale
+		return &copied, nil
+	}
+
+	service := f.service(0)
+	if _, err := service.RestoreItem(services.TrashTypeTransaction, transaction.ID, testutils.TestUserID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := service.RestoreItem(services.TrashTypeTransaction, transaction.ID, testutils.TestUserID); err == nil {
+		t.Error("Expected a repeated restore to fail")
+	}
+	if len(f.restored) != 1 || f.balances[testutils.TestWalletID] != -money.FromMajor(50) {
+		t.Errorf("Expected one restore taking 50 from the wallet, got %v and %v", f.restored, f.balances[testutils.TestWalletID])
+	}
+}
+
+func TestTrashService_RestoreTransaction_UndoneImport(t *testing.T) {
+	f := newTrashFixture()
+	job := &models.ImportJob{ID: uuid.New(), UserID: testutils.TestUserID, Status: models.ImportStatusUndone}
+	f.imports[job.ID] = job
+	transaction := f.addDeletedTransaction(testutils.TestUserID, testutils.TestWalletID, money.FromMajor(50))
+	transaction.ImportJobID = &job.ID
+
+	if _, err := f.service(0).RestoreItem(services.TrashTypeTransaction, transaction.ID, testutils.TestUserID); err == nil {
+		t.Fatal("Expected restoring a row of an undone import to fail")
+	}
+	if len(f.restored) != 0 || len(f.balances) != 0 {
+		t.Errorf("Expected nothing to change, restored %v balances %v", f.restored, f.balances)
+	}
+
+	// Rows of a completed import are restored as usual
+	job.Status = models.ImportStatusCompleted
+	if _, err := f.service(0).RestoreItem(services.TrashTypeTransaction, transaction.ID, testutils.TestUserID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+func TestTrashService_RestoreTransactionRejected(t *testing.T) {
+	tests := []struct {
+		name     string
+		userID   uuid.UUID
+		walletID uuid.UUID
+	}{
+		{name: "wallet is deleted", userID: testutils.TestUserID, walletID: secondWalletID},
+		{name: "other user's transaction", userID: uuid.New(), walletID: testutils.TestWalletID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newTrashFixture()
+			transaction := f.addDeletedTransaction(tt.userID, tt.walletID, money.FromMajor(50))
+
+			if _, err := f.service(0).RestoreItem(services.TrashTypeTransaction, transaction.ID, testutils.TestUserID); err == nil {
+				t.Fatal("Expected an error")
+			}
+			if len(f.restored) != 0 || len(f.balances) != 0 {
+				t.Errorf("Expected nothing to change, restored %v balances %v", f.restored, f.balances)
+			}
+		})
+	}
+}
+
+func TestTrashService_RestoreBudgetConflict(t *testing.T) {
+	f := newTrashFixture()
+	budgetID := uuid.New()
+	f.budgetRepo.FindDeletedByIDFunc = func(id uuid.UUID) (*models.Budget, error) {
+		return &models.Budget{ID: id, UserID: testutils.TestUserID, Category: "Food"}, nil
+	}
+	f.budgetRepo.FindByUserIDAndCategoryFunc = func(userID uuid.UUID, category string) (*models.Budget, error) {
+		return &models.Budget{ID: uuid.New(), UserID: userID, Category: category}, nil
+	}
+	f.budgetRepo.RestoreFunc = func(id uuid.UUID) error {
+		t.Error("Expected budget not to be restored")
+		return nil
+	}
+
+	if _, err := f.service(0).RestoreItem(services.TrashTypeBudget, budgetID, testutils.TestUserID); err == nil {
+		t.Fatal("Expected an error")
+	}
+}
+
+func TestTrashService_RestoreDefaultWallet(t *testing.T) {
+	f := newTrashFixture()
+	deleted := &models.Wallet{ID: secondWalletID, UserID: testutils.TestUserID, IsDefault: true}
+	f.walletRepo.FindDeletedByIDFunc = func(id uuid.UUID) (*models.Wallet, error) {
+		copied := *deleted
+		return &copied, nil
+	}
+	f.walletRepo.FindDefaultByUserIDFunc = func(userID uuid.UUID) (*models.Wallet, error) {
+		return f.wallets[testutils.TestWalletID], nil
+	}
+	var updated map[string]interface{}
+	f.walletRepo.UpdateColumnsFunc = func(id uuid.UUID, columns map[string]interface{}) error {
+		if id == secondWalletID {
+			updated = columns
+		}
+		return nil
+	}
+
+	if _, err := f.service(0).RestoreItem(services.TrashTypeWallet, secondWalletID, testutils.TestUserID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if updated["is_default"] != false {
+		t.Errorf("Expected restored wallet to give up the default, got %v", updated)
+	}
+	if _, balanceWritten := updated["balance"]; balanceWritten {
+		t.Errorf("Expected the restore to leave the balance alone, got %v", updated)
+	}
+}
+
+func TestTrashService_PurgeItem(t *testing.T) {
+	t.Run("transaction and its receipts", func(t *testing.T) {
+		f := newTrashFixture()
+		transaction := f.addDeletedTransaction(testutils.TestUserID, testutils.TestWalletID, money.FromMajor(50))
+
+		if err := f.service(0).PurgeItem(services.TrashTypeTransaction, transaction.ID, testutils.TestUserID); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(f.receiptsGone) != 1 || len(f.purged) != 1 || f.purged[0] != transaction.ID {
+			t.Errorf("Expected receipts and transaction to be purged, got receipts %v purged %v", f.receiptsGone, f.purged)
+		}
+	})
+
+	t.Run("budget, goal and wallet inside a transaction", func(t *testing.T) {
+		f := newTrashFixture()
+		inTransaction := false
+		f.txManager.WithinTransactionFunc = func(fn func(tx *gorm.DB) error) error {
+			inTransaction = true
+			defer func() { inTransaction = false }()
+			return fn(nil)
+		}
+		purged := make(map[string]bool)
+		purge := func(itemType string) func(id uuid.UUID) error {
+			return func(id uuid.UUID) error {
+				purged[itemType] = inTransaction
+				return nil
+			}
+		}
+		f.budgetRepo.FindDeletedByIDFunc = func(id uuid.UUID) (*models.Budget, error) {
+			return &models.Budget{ID: id, UserID: testutils.TestUserID}, nil
+		}
+		f.budgetRepo.PurgeFunc = purge(services.TrashTypeBudget)
+		f.goalRepo.FindDeletedByIDFunc = func(id uuid.UUID) (*models.SavingGoal, error) {
+			return &models.SavingGoal{ID: id, UserID: testutils.TestUserID}, nil
+		}
+		f.goalRepo.PurgeFunc = purge(services.TrashTypeGoal)
+		f.walletRepo.FindDeletedByIDFunc = func(id uuid.UUID) (*models.Wallet, error) {
+			return &models.Wallet{ID: id, UserID: testutils.TestUserID}, nil
+		}
+		f.walletRepo.PurgeFunc = purge(services.TrashTypeWallet)
+
+		for _, itemType := range []string{services.TrashTypeBudget, services.TrashTypeGoal, services.TrashTypeWallet} {
+			if err := f.service(0).PurgeItem(itemType, uuid.New(), testutils.TestUserID); err != nil {
+				t.Fatalf("Expected no error purging a %s, got %v", itemType, err)
+			}
+			if !purged[itemType] {
+				t.Errorf("Expected the %s to be purged inside a transaction", itemType)
+			}
+		}
+	})
+
+	t.Run("wallet still referenced", func(t *testing.T) {
+		f := newTrashFixture()
+		f.walletRepo.FindDeletedByIDFunc = func(id uuid.UUID) (*models.Wallet, error) {
+			return &models.Wallet{ID: id, UserID: testutils.TestUserID}, nil
+		}
+		f.walletRepo.CountReferencesFunc = func(id uuid.UUID) (int64, error) {
+			return 3, nil
+		}
+		f.walletRepo.PurgeFunc = func(id uuid.UUID) error {
+			t.Error("Expected wallet not to be purged")
+			return nil
+		}
+
+		if err := f.service(0).PurgeItem(services.TrashTypeWallet, secondWalletID, testutils.TestUserID); err == nil {
+			t.Fatal("Expected an error")
+		}
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		f := newTrashFixture()
+		err := f.service(0).PurgeItem("category", uuid.New(), testutils.TestUserID)
+		if !errors.Is(err, services.ErrUnknownTrashType) {
+			t.Errorf("Expected ErrUnknownTrashType, got %v", err)
+		}
+	})
+}
+
+func TestTrashService_PurgeExpired(t *testing.T) {
+	now := time.Date(2026, 5, 31, 12, 0, 0, 0, time.UTC)
+
+	t.Run("purges past retention", func(t *testing.T) {
+		f := newTrashFixture()
+		expired := f.addDeletedTransaction(testutils.TestUserID, testutils.TestWalletID, money.FromMajor(50))
+		f.transactionRepo.FindDeletedBeforeFunc = func(cutoff time.Time, limit int) ([]*models.Transaction, error) {
+			if !cutoff.Equal(now.AddDate(0, 0, -30)) {
+				t.Errorf("Expected cutoff 30 days back, got %v", cutoff)
+			}
+			return []*models.Transaction{expired}, nil
+		}
+		f.goalRepo.FindDeletedBeforeFunc = func(cutoff time.Time, limit int) ([]*models.SavingGoal, error) {
+			return []*models.SavingGoal{{ID: uuid.New()}}, nil
+		}
+		f.walletRepo.FindDeletedBeforeFunc = func(cutoff time.Time, limit int) ([]*models.Wallet, error) {
+			return []*models.Wallet{{ID: secondWalletID}}, nil
+		}
+		f.walletRepo.CountReferencesFunc = func(id uuid.UUID) (int64, error) {
+			return 1, nil
+		}
+
+		purged, err := f.service(30 * 24 * time.Hour).PurgeExpired(now)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		// The referenced wallet stays in the trash
+		if purged != 2 {
+			t.Errorf("Expected 2 purged items, got %d", purged)
+		}
+		if len(f.receiptsGone) != 1 || len(f.purged) != 1 {
+			t.Errorf("Expected the transaction and its receipts to be purged, got receipts %v purged %v", f.receiptsGone, f.purged)
+		}
+	})
+
+	t.Run("retention disabled", func(t *testing.T) {
+		f := newTrashFixture()
+		f.transactionRepo.FindDeletedBeforeFunc = func(cutoff time.Time, limit int) ([]*models.Transaction, error) {
+			t.Error("Expected no lookup when retention is disabled")
+			return nil, nil
+		}
+
+		purged, err := f.service(0).PurgeExpired(now)
+		if err != nil || purged != 0 {
+			t.Errorf("Expected nothing purged, got %d, %v", purged, err)
+		}
+	})
+}
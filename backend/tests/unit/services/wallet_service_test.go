@@ -0,0 +1,467 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"github.com/nyunja/fity-budget-backend/internal/services"
+	"github.com/nyunja/fity-budget-backend/tests/unit/mocks"
+	"github.com/nyunja/fity-budget-backend/tests/unit/testutils"
+)
+
+// transferFixture wires a wallet service to in-memory wallets, transactions and
+// transfers, and records the order in which wallet rows were locked
+type transferFixture struct {
+	service      services.WalletService
+	wallets      map[uuid.UUID]*models.Wallet
+	transactions []*models.Transaction
+	transfers    map[uuid.UUID]*models.Transfer
+	walletRepo   *mocks.MockWalletRepository
+	transferRepo *mocks.MockTransferRepository
+	rates        []*models.ExchangeRate
+	locked       []uuid.UUID
+}
+
+func newTransferFixture() *transferFixture {
+	wallets := testutils.NewWalletStore(
+		&models.Wallet{ID: testutils.TestWalletID, UserID: testutils.TestUserID, Name: "M-Pesa", Balance: money.FromMajor(1000), Currency: "KES"},
+		&models.Wallet{ID: secondWalletID, UserID: testutils.TestUserID, Name: "Savings", Balance: money.FromMajor(200), Currency: "KES"},
+		&models.Wallet{ID: foreignWalletID, UserID: uuid.New(), Name: "Other", Balance: money.FromMajor(500), Currency: "KES"},
+	)
+	f := &transferFixture{
+		wallets:   wallets.Wallets,
+		transfers: make(map[uuid.UUID]*models.Transfer),
+	}
+
+	walletRepo := wallets.Repo()
+	walletRepo.FindByIDForUpdateFunc = func(id uuid.UUID) (*models.Wallet, error) {
+		f.locked = append(f.locked, id)
+		return walletRepo.FindByID(id)
+	}
+	transactionRepo := &mocks.MockTransactionRepository{
+		CreateFunc: func(transaction *models.Transaction) error {
+			transaction.ID = uuid.New()
+			f.transactions = append(f.transactions, transaction)
+			return nil
+		},
+	}
+	transferRepo := &mocks.MockTransferRepository{
+		CreateFunc: func(transfer *models.Transfer) error {
+			transfer.ID = uuid.New()
+			stored := *transfer
+			f.transfers[transfer.ID] = &stored
+			return nil
+		},
+		FindByIDFunc: func(id uuid.UUID) (*models.Transfer, error) {
+			transfer, ok := f.transfers[id]
+			if !ok {
+				return nil, errors.New("record not found")
+			}
+			found := *transfer
+			return &found, nil
+		},
+		UpdateFunc: func(transfer *models.Transfer) error {
+			stored := *transfer
+			f.transfers[transfer.ID] = &stored
+			return nil
+		},
+	}
+
+	rateRepo := &mocks.MockExchangeRateRepository{
+		FindLatestFunc: func(baseCurrency, quoteCurrency string, on time.Time) (*models.ExchangeRate, error) {
+			return latestRate(f.rates, baseCurrency, quoteCurrency, on), nil
+		},
+	}
+
+	f.walletRepo = walletRepo
+	f.transferRepo = transferRepo
+	f.service = services.NewWalletService(walletRepo, transactionRepo, transferRepo, rateRepo, &mocks.MockTxManager{})
+	return f
+}
+
+func TestWalletService_TransferBetweenWallets(t *testing.T) {
+	f := newTransferFixture()
+
+	transfer, err := f.service.TransferBetweenWallets(testutils.TestUserID, services.CreateTransferRequest{
+		FromWalletID: testutils.TestWalletID,
+		ToWalletID:   secondWalletID,
+		Amount:       money.FromMajor(300),
+		Notes:        "Monthly savings",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if f.wallets[testutils.TestWalletID].Balance != money.FromMajor(700) {
+		t.Errorf("Expected source balance 700, got %v", f.wallets[testutils.TestWalletID].Balance)
+	}
+	if f.wallets[secondWalletID].Balance != money.FromMajor(500) {
+		t.Errorf("Expected destination balance 500, got %v", f.wallets[secondWalletID].Balance)
+	}
+
+	if transfer.Status != "Completed" {
+		t.Errorf("Expected status Completed, got %q", transfer.Status)
+	}
+	if transfer.TransferDate.IsZero() {
+		t.Error("Expected transfer date to default to now")
+	}
+
+	if len(f.transactions) != 2 {
+		t.Fatalf("Expected a pair of transfer transactions, got %d", len(f.transactions))
+	}
+	for _, transaction := range f.transactions {
+		if transaction.Type != models.TransactionTypeTransfer {
+			t.Errorf("Expected type %q, got %q", models.TransactionTypeTransfer, transaction.Type)
+		}
+		if transaction.TransferID == nil || *transaction.TransferID != transfer.ID {
+			t.Error("Expected transaction to be linked to the transfer")
+		}
+		if transaction.Amount != money.FromMajor(300) {
+			t.Errorf("Expected amount 300, got %v", transaction.Amount)
+		}
+	}
+	if transfer.OutTransactionID == nil || *transfer.OutTransactionID != f.transactions[0].ID {
+		t.Error("Expected outgoing transaction to be recorded on the transfer")
+	}
+	if transfer.InTransactionID == nil || *transfer.InTransactionID != f.transactions[1].ID {
+		t.Error("Expected incoming transaction to be recorded on the transfer")
+	}
+	if *f.transactions[0].WalletID != testutils.TestWalletID || *f.transactions[1].WalletID != secondWalletID {
+		t.Error("Expected transactions to be recorded against the source and destination wallets")
+	}
+}
+
+func TestWalletService_TransferBetweenWallets_LocksInStableOrder(t *testing.T) {
+	f := newTransferFixture()
+
+	if _, err := f.service.TransferBetweenWallets(testutils.TestUserID, services.CreateTransferRequest{
+		FromWalletID: testutils.TestWalletID, ToWalletID: secondWalletID, Amount: money.FromMajor(10),
+	}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := f.service.TransferBetweenWallets(testutils.TestUserID, services.CreateTransferRequest{
+		FromWalletID: secondWalletID, ToWalletID: testutils.TestWalletID, Amount: money.FromMajor(10),
+	}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(f.locked) != 4 {
+		t.Fatalf("Expected 4 row locks, got %d", len(f.locked))
+	}
+	if f.locked[0] != f.locked[2] || f.locked[1] != f.locked[3] {
+		t.Errorf("Expected opposite transfers to lock wallets in the same order, got %v", f.locked)
+	}
+}
+
+func TestWalletService_TransferBetweenWallets_Rejected(t *testing.T) {
+	tests := []struct {
+		name string
+		req  services.CreateTransferRequest
+	}{
+		{
+			name: "same wallet",
+			req:  services.CreateTransferRequest{FromWalletID: testutils.TestWalletID, ToWalletID: testutils.TestWalletID, Amount: money.FromMajor(10)},
+		},
+		{
+			name: "non-positive amount",
+			req:  services.CreateTransferRequest{FromWalletID: testutils.TestWalletID, ToWalletID: secondWalletID, Amount: money.FromMajor(0)},
+		},
+		{
+			name: "insufficient balance",
+			req:  services.CreateTransferRequest{FromWalletID: secondWalletID, ToWalletID: testutils.TestWalletID, Amount: money.FromMajor(201)},
+		},
+		{
+			name: "foreign destination wallet",
+			req:  services.CreateTransferRequest{FromWalletID: testutils.TestWalletID, ToWalletID: foreignWalletID, Amount: money.FromMajor(10)},
+		},
+		{
+			name: "foreign source wallet",
+			req:  services.CreateTransferRequest{FromWalletID: foreignWalletID, ToWalletID: testutils.TestWalletID, Amount: money.FromMajor(10)},
+		},
+		{
+			name: "missing wallet",
+			req:  services.CreateTransferRequest{FromWalletID: testutils.TestWalletID, ToWalletID: uuid.New(), Amount: money.FromMajor(10)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newTransferFixture()
+
+			if _, err := f.service.TransferBetweenWallets(testutils.TestUserID, tt.req); err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+			if f.wallets[testutils.TestWalletID].Balance != money.FromMajor(1000) || f.wallets[secondWalletID].Balance != money.FromMajor(200) {
+				t.Error("Expected balances to be unchanged")
+			}
+			if len(f.transfers) != 0 || len(f.transactions) != 0 {
+				t.Error("Expected no records to be written")
+			}
+		})
+	}
+}
+
+func TestWalletService_TransferBetweenWallets_NoExchangeRate(t *testing.T) {
+	f := newTransferFixture()
+	f.wallets[secondWalletID].Currency = "USD"
+
+	_, err := f.service.TransferBetweenWallets(testutils.TestUserID, services.CreateTransferRequest{
+		FromWalletID: testutils.TestWalletID, ToWalletID: secondWalletID, Amount: money.FromMajor(10),
+	})
+	if !errors.Is(err, services.ErrNoExchangeRate) {
+		t.Fatalf("Expected a missing exchange rate error, got %v", err)
+	}
+	if f.wallets[testutils.TestWalletID].Balance != money.FromMajor(1000) {
+		t.Error("Expected source balance to be unchanged")
+	}
+}
+
+func TestWalletService_TransferBetweenWallets_ConvertsCurrency(t *testing.T) {
+	f := newTransferFixture()
+	f.wallets[secondWalletID].Currency = "USD"
+	f.rates = []*models.ExchangeRate{
+		{BaseCurrency: "USD", QuoteCurrency: "KES", Rate: mustRate("129.5"), RateDate: day("2026-10-01")},
+		{BaseCurrency: "USD", QuoteCurrency: "KES", Rate: mustRate("130"), RateDate: day("2026-10-09")},
+		{BaseCurrency: "USD", QuoteCurrency: "KES", Rate: mustRate("131"), RateDate: day("2026-10-15")},
+	}
+
+	transfer, err := f.service.TransferBetweenWallets(testutils.TestUserID, services.CreateTransferRequest{
+		FromWalletID: testutils.TestWalletID,
+		ToWalletID:   secondWalletID,
+		Amount:       money.FromMajor(650),
+		TransferDate: day("2026-10-12"),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// KES -> USD uses the inverse of the USD/KES rate quoted on 9 October
+	if transfer.ToAmount != money.FromMajor(5) || transfer.ToCurrency != "USD" {
+		t.Errorf("Expected USD 5.00 to be credited, got %s %v", transfer.ToCurrency, transfer.ToAmount)
+	}
+	if transfer.RateDate == nil || !transfer.RateDate.Equal(day("2026-10-09")) {
+		t.Errorf("Expected the rate of 2026-10-09 to be used, got %v", transfer.RateDate)
+	}
+	if f.wallets[testutils.TestWalletID].Balance != money.FromMajor(350) {
+		t.Errorf("Expected source balance 350, got %v", f.wallets[testutils.TestWalletID].Balance)
+	}
+	if f.wallets[secondWalletID].Balance != money.FromMajor(205) {
+		t.Errorf("Expected destination balance 205, got %v", f.wallets[secondWalletID].Balance)
+	}
+	if f.transactions[1].Amount != money.FromMajor(5) {
+		t.Errorf("Expected incoming leg of 5.00, got %v", f.transactions[1].Amount)
+	}
+
+	// A reversal restores the original amounts rather than converting again
+	f.rates = append(f.rates, &models.ExchangeRate{BaseCurrency: "USD", QuoteCurrency: "KES", Rate: mustRate("140"), RateDate: time.Now()})
+	reversal, err := f.service.ReverseTransfer(transfer.ID, testutils.TestUserID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if reversal.Amount != money.FromMajor(5) || reversal.ToAmount != money.FromMajor(650) {
+		t.Errorf("Expected USD 5.00 back as KES 650.00, got %v and %v", reversal.Amount, reversal.ToAmount)
+	}
+	if f.wallets[testutils.TestWalletID].Balance != money.FromMajor(1000) || f.wallets[secondWalletID].Balance != money.FromMajor(200) {
+		t.Errorf("Expected balances to be restored, got %v and %v",
+			f.wallets[testutils.TestWalletID].Balance, f.wallets[secondWalletID].Balance)
+	}
+}
+
+func TestWalletService_ReverseTransfer(t *testing.T) {
+	f := newTransferFixture()
+
+	original, err := f.service.TransferBetweenWallets(testutils.TestUserID, services.CreateTransferRequest{
+		FromWalletID: testutils.TestWalletID, ToWalletID: secondWalletID, Amount: money.FromMajor(300),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	reversal, err := f.service.ReverseTransfer(original.ID, testutils.TestUserID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if f.wallets[testutils.TestWalletID].Balance != money.FromMajor(1000) || f.wallets[secondWalletID].Balance != money.FromMajor(200) {
+		t.Errorf("Expected balances to be restored, got %v and %v",
+			f.wallets[testutils.TestWalletID].Balance, f.wallets[secondWalletID].Balance)
+	}
+	if reversal.FromWalletID != secondWalletID || reversal.ToWalletID != testutils.TestWalletID {
+		t.Error("Expected reversal to move money back to the source wallet")
+	}
+	if reversal.ReversalOfID == nil || *reversal.ReversalOfID != original.ID {
+		t.Error("Expected reversal to be linked to the original transfer")
+	}
+	if f.transfers[original.ID].Status != "Reversed" || f.transfers[original.ID].ReversedAt == nil {
+		t.Error("Expected original transfer to be marked as reversed")
+	}
+
+	if _, err := f.service.ReverseTransfer(original.ID, testutils.TestUserID); err == nil {
+		t.Error("Expected error when reversing a transfer twice")
+	}
+	if _, err := f.service.ReverseTransfer(reversal.ID, testutils.TestUserID); err == nil {
+		t.Error("Expected error when reversing a reversal")
+	}
+}
+
+func TestWalletService_ReverseTransfer_Concurrent(t *testing.T) {
+	f := newTransferFixture()
+
+	original, err := f.service.TransferBetweenWallets(testutils.TestUserID, services.CreateTransferRequest{
+		FromWalletID: testutils.TestWalletID, ToWalletID: secondWalletID, Amount: money.FromMajor(300),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// A second reversal commits after the first has checked the transfer but
+	// before it takes the row lock
+	race := testutils.RaceOnce(func() {
+		if _, err := f.service.ReverseTransfer(original.ID, testutils.TestUserID); err != nil {
+			t.Fatalf("Unexpected error in the concurrent reversal: %v", err)
+		}
+	})
+	f.transferRepo.FindByIDForUpdateFunc = func(id uuid.UUID) (*models.Transfer, error) {
+		race()
+		return f.transferRepo.FindByID(id)
+	}
+
+	if _, err := f.service.ReverseTransfer(original.ID, testutils.TestUserID); err == nil {
+		t.Error("Expected the later reversal to find the transfer already reversed")
+	}
+	if f.wallets[testutils.TestWalletID].Balance != money.FromMajor(1000) || f.wallets[secondWalletID].Balance != money.FromMajor(200) {
+		t.Errorf("Expected the funds to move back once, got %v and %v",
+			f.wallets[testutils.TestWalletID].Balance, f.wallets[secondWalletID].Balance)
+	}
+}
+
+func TestWalletService_ReverseTransfer_Unauthorized(t *testing.T) {
+	f := newTransferFixture()
+
+	original, err := f.service.TransferBetweenWallets(testutils.TestUserID, services.CreateTransferRequest{
+		FromWalletID: testutils.TestWalletID, ToWalletID: secondWalletID, Amount: money.FromMajor(300),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, err := f.service.ReverseTransfer(original.ID, uuid.New()); err == nil {
+		t.Fatal("Expected error when reversing another user's transfer")
+	}
+	if f.wallets[secondWalletID].Balance != money.FromMajor(500) {
+		t.Error("Expected balances to be unchanged")
+	}
+}
+
+func TestWalletService_UpdateWallet_KeepsConcurrentBalanceChange(t *testing.T) {
+	f := newTransferFixture()
+	// A transaction lands between the read and the write of the update
+	race := testutils.RaceOnce(func() {
+		_ = f.walletRepo.UpdateBalance(testutils.TestWalletID, money.FromMajor(50))
+	})
+	findByID := f.walletRepo.FindByIDFunc
+	f.walletRepo.FindByIDFunc = func(id uuid.UUID) (*models.Wallet, error) {
+		wallet, err := findByID(id)
+		race()
+		return wallet, err
+	}
+
+	wallet, err := f.service.UpdateWallet(testutils.TestWalletID, testutils.TestUserID, services.UpdateWalletRequest{Name: "Till"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if f.wallets[testutils.TestWalletID].Name != "Till" {
+		t.Errorf("Expected the name to be updated, got %q", f.wallets[testutils.TestWalletID].Name)
+	}
+	if f.wallets[testutils.TestWalletID].Balance != money.FromMajor(1050) || wallet.Balance != money.FromMajor(1050) {
+		t.Errorf("Expected the concurrent balance change to be kept, got %v", f.wallets[testutils.TestWalletID].Balance)
+	}
+}
+
+func TestWalletService_UpdateWallet_Balance(t *testing.T) {
+	f := newTransferFixture()
+
+	if _, err := f.service.UpdateWallet(testutils.TestWalletID, testutils.TestUserID, services.UpdateWalletRequest{Color: "#00FF00"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if f.wallets[testutils.TestWalletID].Balance != money.FromMajor(1000) {
+		t.Errorf("Expected an omitted balance to leave the wallet at 1000, got %v", f.wallets[testutils.TestWalletID].Balance)
+	}
+
+	balance := money.FromMajor(0)
+	if _, err := f.service.UpdateWallet(testutils.TestWalletID, testutils.TestUserID, services.UpdateWalletRequest{Balance: &balance}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if f.wallets[testutils.TestWalletID].Balance != 0 {
+		t.Errorf("Expected an explicit balance of 0 to be applied, got %v", f.wallets[testutils.TestWalletID].Balance)
+	}
+}
+
+func TestWalletService_SetDefaultWallet(t *testing.T) {
+	f := newTransferFixture()
+	f.wallets[testutils.TestWalletID].IsDefault = true
+	f.walletRepo.FindByUserIDFunc = func(userID uuid.UUID) ([]*models.Wallet, error) {
+		return []*models.Wallet{f.wallets[testutils.TestWalletID], f.wallets[secondWalletID]}, nil
+	}
+	// The stale copy read here must not carry its balance into the write
+	stale := *f.wallets[secondWalletID]
+	f.walletRepo.FindByIDFunc = func(id uuid.UUID) (*models.Wallet, error) {
+		copied := stale
+		return &copied, nil
+	}
+	f.wallets[secondWalletID].Balance += money.FromMajor(25)
+
+	if _, err := f.service.SetDefaultWallet(secondWalletID, testutils.TestUserID); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if f.wallets[testutils.TestWalletID].IsDefault || !f.wallets[secondWalletID].IsDefault {
+		t.Error("Expected the default to move to the second wallet")
+	}
+	if f.wallets[secondWalletID].Balance != money.FromMajor(225) {
+		t.Errorf("Expected the balance to be left alone, got %v", f.wallets[secondWalletID].Balance)
+	}
+}
+
+func TestWalletService_UpdateWallet_Currency(t *testing.T) {
+	t.Run("rejected with a balance", func(t *testing.T) {
+		f := newTransferFixture()
+
+		if _, err := f.service.UpdateWallet(testutils.TestWalletID, testutils.TestUserID, services.UpdateWalletRequest{Currency: "USD"}); err == nil {
+			t.Error("Expected the currency change to be rejected")
+		}
+		if f.wallets[testutils.TestWalletID].Currency != "KES" {
+			t.Errorf("Expected the currency to stay KES, got %q", f.wallets[testutils.TestWalletID].Currency)
+		}
+	})
+
+	t.Run("rejected with transactions", func(t *testing.T) {
+		f := newTransferFixture()
+		f.wallets[testutils.TestWalletID].Balance = 0
+		f.walletRepo.CountReferencesFunc = func(id uuid.UUID) (int64, error) {
+			return 3, nil
+		}
+
+		if _, err := f.service.UpdateWallet(testutils.TestWalletID, testutils.TestUserID, services.UpdateWalletRequest{Currency: "USD"}); err == nil {
+			t.Error("Expected the currency change to be rejected")
+		}
+		if f.wallets[testutils.TestWalletID].Currency != "KES" {
+			t.Errorf("Expected the currency to stay KES, got %q", f.wallets[testutils.TestWalletID].Currency)
+		}
+	})
+
+	t.Run("allowed on an unused wallet", func(t *testing.T) {
+		f := newTransferFixture()
+		f.wallets[testutils.TestWalletID].Balance = 0
+
+		if _, err := f.service.UpdateWallet(testutils.TestWalletID, testutils.TestUserID, services.UpdateWalletRequest{Currency: "USD"}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if f.wallets[testutils.TestWalletID].Currency != "USD" {
+			t.Errorf("Expected the currency to change to USD, got %q", f.wallets[testutils.TestWalletID].Currency)
+		}
+	})
+}
@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/nyunja/fity-budget-backend/internal/storage"
+)
+
+func TestLocalStorage(t *testing.T) {
+	store, err := storage.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := store.Put("receipts/user/one.pdf", strings.NewReader("first")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := store.Put("receipts/user/one.pdf", strings.NewReader("second")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	file, err := store.Open("receipts/user/one.pdf")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	data, _ := io.ReadAll(file)
+	file.Close()
+	if string(data) != "second" {
+		t.Errorf("Expected the replaced contents, got %q", data)
+	}
+
+	if err := store.Delete("receipts/user/one.pdf"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := store.Open("receipts/user/one.pdf"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after delete, got %v", err)
+	}
+	if err := store.Delete("receipts/user/one.pdf"); err != nil {
+		t.Errorf("Expected deleting a missing file to succeed, got %v", err)
+	}
+}
+
+func TestLocalStorage_RejectsKeysOutsideRoot(t *testing.T) {
+	store, err := storage.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, key := range []string{"", "../escape.pdf", "receipts/../../escape.pdf", "."} {
+		if err := store.Put(key, strings.NewReader("x")); err == nil {
+			t.Errorf("Expected key %q to be rejected", key)
+		}
+		if _, err := store.Open(key); err == nil {
+			t.Errorf("Expected key %q to be rejected", key)
+		}
+	}
+}
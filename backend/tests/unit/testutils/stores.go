@@ -0,0 +1,269 @@
+package testutils
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/nyunja/fity-budget-backend/internal/models"
+	"github.com/nyunja/fity-budget-backend/internal/money"
+	"github.com/nyunja/fity-budget-backend/internal/repository"
+	"github.com/nyunja/fity-budget-backend/tests/unit/mocks"
+	"gorm.io/gorm"
+)
+
+// TransactionStore keeps transactions in memory for service tests. Reads
+// return copies, so a service only changes what it writes back.
+type TransactionStore struct {
+	Stored map[uuid.UUID]*models.Transaction
+	order  []uuid.UUID
+}
+
+// NewTransactionStore creates an empty TransactionStore
+func NewTransactionStore() *TransactionStore {
+	return &TransactionStore{Stored: make(map[uuid.UUID]*models.Transaction)}
+}
+
+// Seed stores an existing transaction, of the test user unless it names
+// another, and returns its new ID
+func (s *TransactionStore) Seed(transaction models.Transaction) uuid.UUID {
+	transaction.ID = uuid.New()
+	if transaction.UserID == uuid.Nil {
+		transaction.UserID = TestUserID
+	}
+	s.put(&transaction)
+	return transaction.ID
+}
+
+// Find returns copies of the user's transactions that pass the filter,
+// oldest first and otherwise in the order they were stored
+func (s *TransactionStore) Find(filter repository.TransactionFilter) []*models.Transaction {
+	var found []*models.Transaction
+	for _, id := range s.order {
+		transaction, ok := s.Stored[id]
+		if !ok || transaction.UserID != filter.UserID || !MatchesFilter(transaction, filter) {
+			continue
+		}
+		copied := *transaction
+		found = append(found, &copied)
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].TransactionDate.Before(found[j].TransactionDate)
+	})
+	return found
+}
+
+// Repo returns a transaction repository backed by the store. Missing
+// transactions are reported as gorm.ErrRecordNotFound, like the real one.
+// Tests override the functions they need to behave differently.
+func (s *TransactionStore) Repo() *mocks.MockTransactionRepository {
+	return &mocks.MockTransactionRepository{
+		CreateFunc: func(transaction *models.Transaction) error {
+			if transaction.ID == uuid.Nil {
+				transaction.ID = uuid.New()
+			}
+			stored := *transaction
+			s.put(&stored)
+			return nil
+		},
+		FindByIDFunc: func(id uuid.UUID) (*models.Transaction, error) {
+			transaction, ok := s.Stored[id]
+			if !ok {
+				return nil, gorm.ErrRecordNotFound
+			}
+			found := *transaction
+			return &found, nil
+		},
+		CountByFilterFunc: func(filter repository.TransactionFilter) (int64, error) {
+			return int64(len(s.Find(filter))), nil
+		},
+		FindByFilterFunc: func(filter repository.TransactionFilter, sort repository.TransactionSort, limit, offset int) ([]*models.Transaction, error) {
+			return s.Find(filter), nil
+		},
+		UpdateFunc: func(transaction *models.Transaction) error {
+			stored := *transaction
+			s.Stored[transaction.ID] = &stored
+			return nil
+		},
+		DeleteFunc: func(id uuid.UUID) error {
+			delete(s.Stored, id)
+			return nil
+		},
+		ReplaceSplitsFunc: func(transactionID uuid.UUID, splits []models.TransactionSplit) error {
+			s.Stored[transactionID].Splits = splits
+			return nil
+		},
+		AddTagsFunc: func(transactionID uuid.UUID, tags []models.Tag) error {
+			s.Stored[transactionID].Tags = append(s.Stored[transactionID].Tags, tags...)
+			return nil
+		},
+		RemoveTagsFunc: func(transactionID uuid.UUID, tags []models.Tag) error {
+			removed := make(map[uuid.UUID]bool)
+			for _, tag := range tags {
+				removed[tag.ID] = true
+			}
+			var kept []models.Tag
+			for _, existing := range s.Stored[transactionID].Tags {
+				if !removed[existing.ID] {
+					kept = append(kept, existing)
+				}
+			}
+			s.Stored[transactionID].Tags = kept
+			return nil
+		},
+	}
+}
+
+func (s *TransactionStore) put(transaction *models.Transaction) {
+	if _, ok := s.Stored[transaction.ID]; !ok {
+		s.order = append(s.order, transaction.ID)
+	}
+	s.Stored[transaction.ID] = transaction
+}
+
+// WalletStore keeps wallets in memory for service tests and records the net
+// balance change applied to each wallet, including wallets it does not hold
+type WalletStore struct {
+	Wallets  map[uuid.UUID]*models.Wallet
+	Balances map[uuid.UUID]money.Amount
+}
+
+// NewWalletStore creates a WalletStore holding the given wallets
+func NewWalletStore(wallets ...*models.Wallet) *WalletStore {
+	s := &WalletStore{
+		Wallets:  make(map[uuid.UUID]*models.Wallet),
+		Balances: make(map[uuid.UUID]money.Amount),
+	}
+	for _, wallet := range wallets {
+		s.Wallets[wallet.ID] = wallet
+	}
+	return s
+}
+
+// Repo returns a wallet repository backed by the store. Balance changes move
+// the stored wallet's balance as well as the recorded net change.
+func (s *WalletStore) Repo() *mocks.MockWalletRepository {
+	return &mocks.MockWalletRepository{
+		FindByIDFunc: func(id uuid.UUID) (*models.Wallet, error) {
+			wallet, ok := s.Wallets[id]
+			if !ok {
+				return nil, gorm.ErrRecordNotFound
+			}
+			found := *wallet
+			return &found, nil
+		},
+		UpdateColumnsFunc: func(id uuid.UUID, columns map[string]interface{}) error {
+			wallet, ok := s.Wallets[id]
+			if !ok {
+				return gorm.ErrRecordNotFound
+			}
+			applyWalletColumns(wallet, columns)
+			return nil
+		},
+		UpdateBalanceFunc: func(id uuid.UUID, amount money.Amount) error {
+			s.Balances[id] += amount
+			if wallet, ok := s.Wallets[id]; ok {
+				wallet.Balance += amount
+			}
+			return nil
+		},
+	}
+}
+
+// applyWalletColumns sets the wallet fields named by the given columns
+func applyWalletColumns(wallet *models.Wallet, columns map[string]interface{}) {
+	for column, value := range columns {
+		switch column {
+		case "name":
+			wallet.Name = value.(string)
+		case "type":
+			wallet.Type = value.(string)
+		case "currency":
+			wallet.Currency = value.(string)
+		case "color":
+			wallet.Color = value.(string)
+		case "account_number":
+			wallet.AccountNumber = value.(string)
+		case "balance":
+			wallet.Balance = value.(money.Amount)
+		case "is_default":
+			wallet.IsDefault = value.(bool)
+		case "off_budget":
+			wallet.OffBudget = value.(bool)
+		}
+	}
+}
+
+// MatchesFilter reports whether a transaction passes a repository filter
+func MatchesFilter(txn *models.Transaction, filter repository.TransactionFilter) bool {
+	if !filter.StartDate.IsZero() && txn.TransactionDate.Before(filter.StartDate) {
+		return false
+	}
+	if !filter.EndDate.IsZero() && !txn.TransactionDate.Before(filter.EndDate) {
+		return false
+	}
+	// Categories match regardless of case, as in the repository
+	if len(filter.Categories) > 0 && !containsFold(filter.Categories, txn.Category) {
+		return false
+	}
+	if len(filter.Statuses) > 0 && !containsString(filter.Statuses, txn.Status) {
+		return false
+	}
+	if len(filter.Types) > 0 && !containsString(filter.Types, txn.Type) {
+		return false
+	}
+	if len(filter.WalletIDs) > 0 {
+		if txn.WalletID == nil {
+			return false
+		}
+		found := false
+		for _, id := range filter.WalletIDs {
+			found = found || id == *txn.WalletID
+		}
+		if !found {
+			return false
+		}
+	}
+	if filter.MinAmount != nil && txn.Amount < *filter.MinAmount {
+		return false
+	}
+	if filter.MaxAmount != nil && txn.Amount > *filter.MaxAmount {
+		return false
+	}
+	if len(filter.ExternalIDs) > 0 && !containsString(filter.ExternalIDs, txn.ExternalID) {
+		return false
+	}
+	if filter.ImportJobID != nil && (txn.ImportJobID == nil || *txn.ImportJobID != *filter.ImportJobID) {
+		return false
+	}
+	if len(filter.TagIDs) > 0 {
+		found := false
+		for _, tag := range txn.Tags {
+			for _, id := range filter.TagIDs {
+				found = found || id == tag.ID
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
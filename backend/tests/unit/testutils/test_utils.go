@@ -64,3 +64,16 @@ var TestBudgetID = uuid.MustParse("440e8400-e29b-41d4-a716-446655440001")
 
 // TestTransactionID is a constant test transaction ID
 var TestTransactionID = uuid.MustParse("770e8400-e29b-41d4-a716-446655440000")
+
+// RaceOnce returns a function that runs race only the first time it is
+// called. Hooked into a locked read, it lets a service test act as if another
+// request took the lock first and committed.
+func RaceOnce(race func()) func() {
+	raced := false
+	return func() {
+		if !raced {
+			raced = true
+			race()
+		}
+	}
+}
@@ -17,7 +17,9 @@ export const useTransactions = () => {
     return transactionsData.data.map((tx: any) => ({
       id: tx.id,
       date: formatTransactionDate(tx.transaction_date),
-      amount: tx.amount,
+      // The API stores positive amounts with a type; the UI shows spending as negative
+      amount: tx.type === 'expense' ? -Math.abs(tx.amount) : tx.amount,
+      type: tx.type,
       name: tx.name,
       method: tx.method,
       category: tx.category,